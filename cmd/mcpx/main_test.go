@@ -6,7 +6,9 @@ import (
 	"os/exec"
 	"path/filepath"
 	"strings"
+	"syscall"
 	"testing"
+	"time"
 )
 
 func TestMainVersionPathExitsZero(t *testing.T) {
@@ -33,6 +35,7 @@ func TestMainDaemonPathExitsOneWhenDaemonRunFails(t *testing.T) {
 	cmd.Env = append(os.Environ(),
 		"GO_WANT_MAIN_HELPER=1",
 		"XDG_RUNTIME_DIR="+runtimeFile, // Forces daemon runtime dir creation failure.
+		"TMPDIR="+runtimeFile,          // ...including the temp-dir fallbacks.
 	)
 
 	out, err := cmd.CombinedOutput()
@@ -63,3 +66,51 @@ func TestMainHelperProcess(t *testing.T) {
 	main()
 	os.Exit(0)
 }
+
+func TestMainDaemonRunForegroundStopsOnSignal(t *testing.T) {
+	root, err := os.MkdirTemp("", "mcpxfg")
+	if err != nil {
+		t.Fatalf("MkdirTemp: %v", err)
+	}
+	defer os.RemoveAll(root)
+
+	cmd := exec.Command(os.Args[0], "-test.run=TestMainHelperProcess", "--", "daemon", "run", "--foreground")
+	cmd.Env = append(os.Environ(),
+		"GO_WANT_MAIN_HELPER=1",
+		"HOME="+root,
+		"XDG_CONFIG_HOME="+filepath.Join(root, "config"),
+		"XDG_STATE_HOME="+filepath.Join(root, "state"),
+		"XDG_RUNTIME_DIR="+filepath.Join(root, "run"),
+	)
+	var out strings.Builder
+	cmd.Stdout = &out
+	cmd.Stderr = &out
+	if err := cmd.Start(); err != nil {
+		t.Fatalf("starting daemon run: %v", err)
+	}
+
+	socket := filepath.Join(root, "run", "mcpx", "daemon.sock")
+	deadline := time.Now().Add(5 * time.Second)
+	for {
+		if _, err := os.Stat(socket); err == nil {
+			break
+		}
+		if time.Now().After(deadline) {
+			_ = cmd.Process.Kill()
+			_ = cmd.Wait()
+			t.Fatalf("daemon socket %s did not appear (output=%q)", socket, out.String())
+		}
+		time.Sleep(20 * time.Millisecond)
+	}
+
+	if err := cmd.Process.Signal(syscall.SIGTERM); err != nil {
+		t.Fatalf("signaling daemon: %v", err)
+	}
+	if err := cmd.Wait(); err != nil {
+		t.Fatalf("daemon run exit error = %v (output=%q)", err, out.String())
+	}
+	got := out.String()
+	if !strings.Contains(got, "mcpx daemon: listening on") || !strings.Contains(got, "mcpx daemon: shutting down") {
+		t.Fatalf("daemon output = %q, want listening and shutting down lines", got)
+	}
+}
@@ -1,6 +1,7 @@
 package bootstrap
 
 import (
+	"bytes"
 	"context"
 	"encoding/base64"
 	"encoding/json"
@@ -19,10 +20,21 @@ import (
 	"github.com/lydakis/mcpx/internal/httpheaders"
 )
 
+// StdinSource is the source value that reads a manifest from stdin.
+const StdinSource = "-"
+
 type ResolveOptions struct {
 	Name     string
 	FetchURL func(ctx context.Context, source string) ([]byte, error)
 	ReadFile func(path string) ([]byte, error)
+	// Stdin supplies the manifest for StdinSource; it defaults to os.Stdin.
+	Stdin io.Reader
+	// TrustedHosts, when non-empty, refuses install links and URLs whose
+	// host or scheme is not listed (see CheckTrustedSource).
+	TrustedHosts []string
+	// Env names environment variables forwarded into the container for
+	// docker: sources as "-e NAME" flags.
+	Env []string
 }
 
 type ResolvedServer struct {
@@ -99,6 +111,9 @@ func Resolve(ctx context.Context, source string, opts ResolveOptions) (ResolvedS
 	if source == "" {
 		return ResolvedServer{}, fmt.Errorf("missing source")
 	}
+	if err := CheckTrustedSource(source, opts.TrustedHosts); err != nil {
+		return ResolvedServer{}, err
+	}
 
 	if isInstallLinkSource(source) {
 		resolved, err := resolveInstallLink(source, opts.Name)
@@ -110,13 +125,33 @@ func Resolve(ctx context.Context, source string, opts ResolveOptions) (ResolvedS
 		}
 		return resolved, nil
 	}
+	if isDockerSource(source) {
+		return resolveDockerSource(source, opts.Name, opts.Env)
+	}
+	if isPackageSource(source) {
+		return resolvePackageSource(source, opts.Name)
+	}
 
 	var payload []byte
 	var err error
-	if isHTTPURL(source) {
+	if source == StdinSource {
+		stdin := opts.Stdin
+		if stdin == nil {
+			stdin = os.Stdin
+		}
+		payload, err = io.ReadAll(stdin)
+		if err != nil {
+			return ResolvedServer{}, wrapResolveSourceAccessError(fmt.Errorf("reading stdin: %w", err))
+		}
+		if len(bytes.TrimSpace(payload)) == 0 {
+			return ResolvedServer{}, fmt.Errorf("no manifest on stdin")
+		}
+	} else if isHTTPURL(source) {
 		fetch := opts.FetchURL
 		if fetch == nil {
-			fetch = fetchSourceURL
+			fetch = func(ctx context.Context, source string) ([]byte, error) {
+				return fetchSourceURL(ctx, source, opts.TrustedHosts)
+			}
 		}
 		payload, err = fetch(ctx, source)
 		if err != nil {
@@ -149,6 +184,9 @@ func Resolve(ctx context.Context, source string, opts ResolveOptions) (ResolvedS
 			}
 			return resolved, nil
 		}
+		if source == StdinSource {
+			return ResolvedServer{}, fmt.Errorf("parsing stdin: %w", err)
+		}
 		return ResolvedServer{}, fmt.Errorf("parsing %q: %w", source, err)
 	}
 
@@ -283,13 +321,24 @@ func maybeJSONPayload(raw string) []byte {
 	return []byte(trimmed)
 }
 
-func fetchSourceURL(ctx context.Context, source string) ([]byte, error) {
+// fetchSourceURL downloads a manifest. When trusted is non-empty every
+// redirect target must pass CheckTrustedSource too, so an allowed host
+// cannot bounce the fetch to one outside trusted_install_hosts.
+func fetchSourceURL(ctx context.Context, source string, trusted []string) ([]byte, error) {
 	req, err := http.NewRequestWithContext(ctx, http.MethodGet, source, nil)
 	if err != nil {
 		return nil, err
 	}
 
 	client := &http.Client{Timeout: 15 * time.Second}
+	if len(trusted) > 0 {
+		client.CheckRedirect = func(req *http.Request, via []*http.Request) error {
+			if len(via) >= 10 {
+				return errors.New("stopped after 10 redirects")
+			}
+			return CheckTrustedSource(req.URL.String(), trusted)
+		}
+	}
 	resp, err := client.Do(req)
 	if err != nil {
 		return nil, err
@@ -481,6 +530,132 @@ func resolveDirectMCPURLSource(source, overrideName string) (ResolvedServer, err
 	return resolved, nil
 }
 
+// dockerSourcePrefix marks a source as a container image run with docker.
+const dockerSourcePrefix = "docker:"
+
+func isDockerSource(source string) bool {
+	return strings.HasPrefix(source, dockerSourcePrefix)
+}
+
+func resolveDockerSource(source, overrideName string, env []string) (ResolvedServer, error) {
+	image := strings.TrimSpace(strings.TrimPrefix(source, dockerSourcePrefix))
+	if image == "" {
+		return ResolvedServer{}, fmt.Errorf("missing image in %q", source)
+	}
+	if strings.ContainsAny(image, " \t") || strings.HasPrefix(image, "-") {
+		return ResolvedServer{}, fmt.Errorf("invalid docker image %q", image)
+	}
+
+	name := strings.TrimSpace(overrideName)
+	if name == "" {
+		name = defaultServerNameFromImage(image)
+	}
+	if name == "" {
+		return ResolvedServer{}, fmt.Errorf("unable to infer server name from image %q; pass --name", image)
+	}
+
+	args := []string{"run", "-i", "--rm"}
+	for _, envName := range env {
+		args = append(args, "-e", envName)
+	}
+	args = append(args, image)
+
+	resolved := ResolvedServer{
+		Name: name,
+		Server: config.ServerConfig{
+			Command: "docker",
+			Args:    args,
+		},
+	}
+	if err := validateResolvedServer(resolved.Name, resolved.Server); err != nil {
+		return ResolvedServer{}, err
+	}
+	return resolved, nil
+}
+
+// defaultServerNameFromImage derives a server name from the last path
+// segment of an image reference, ignoring any tag or digest.
+func defaultServerNameFromImage(image string) string {
+	image, _, _ = strings.Cut(image, "@")
+	if idx := strings.LastIndex(image, "/"); idx >= 0 {
+		image = image[idx+1:]
+	}
+	image, _, _ = strings.Cut(image, ":")
+	return sanitizeServerNameCandidate(image)
+}
+
+// packageRunners maps package source prefixes to the command and leading
+// arguments that run a package by name.
+var packageRunners = map[string][]string{
+	"npm:": {"npx", "-y"},
+	"uvx:": {"uvx"},
+}
+
+// packageRegistryHosts maps package source prefixes to the registry their
+// runner installs from, which trusted_install_hosts is checked against.
+var packageRegistryHosts = map[string]string{
+	"npm:": "registry.npmjs.org",
+	"uvx:": "pypi.org",
+}
+
+func isPackageSource(source string) bool {
+	_, _, ok := cutPackageSource(source)
+	return ok
+}
+
+func cutPackageSource(source string) (runner []string, pkg string, ok bool) {
+	for prefix, runner := range packageRunners {
+		if pkg, ok := strings.CutPrefix(source, prefix); ok {
+			return runner, strings.TrimSpace(pkg), true
+		}
+	}
+	return nil, "", false
+}
+
+func resolvePackageSource(source, overrideName string) (ResolvedServer, error) {
+	runner, pkg, _ := cutPackageSource(source)
+	if pkg == "" {
+		return ResolvedServer{}, fmt.Errorf("missing package in %q", source)
+	}
+	if strings.ContainsAny(pkg, " \t") || strings.HasPrefix(pkg, "-") {
+		return ResolvedServer{}, fmt.Errorf("invalid package %q", pkg)
+	}
+
+	name := strings.TrimSpace(overrideName)
+	if name == "" {
+		name = defaultServerNameFromPackage(pkg)
+	}
+	if name == "" {
+		return ResolvedServer{}, fmt.Errorf("unable to infer server name from package %q; pass --name", pkg)
+	}
+
+	args := append(append([]string{}, runner[1:]...), pkg)
+	resolved := ResolvedServer{
+		Name: name,
+		Server: config.ServerConfig{
+			Command: runner[0],
+			Args:    args,
+		},
+	}
+	if err := validateResolvedServer(resolved.Name, resolved.Server); err != nil {
+		return ResolvedServer{}, err
+	}
+	return resolved, nil
+}
+
+// defaultServerNameFromPackage derives a server name from a package name,
+// dropping any npm scope and version specifier ("@scope/pkg@1.2",
+// "pkg==1.2").
+func defaultServerNameFromPackage(pkg string) string {
+	if idx := strings.LastIndex(pkg, "/"); idx >= 0 {
+		pkg = pkg[idx+1:]
+	}
+	if idx := strings.IndexAny(pkg, "@=<>~![;"); idx >= 0 {
+		pkg = pkg[:idx]
+	}
+	return sanitizeServerNameCandidate(pkg)
+}
+
 func defaultServerNameFromURL(raw string) string {
 	u, err := url.Parse(strings.TrimSpace(raw))
 	if err != nil {
@@ -673,6 +848,10 @@ func decodeServerConfig(raw map[string]any) (config.ServerConfig, error) {
 		return config.ServerConfig{}, err
 	}
 
+	if err := decodeCacheConfig(raw, &srv); err != nil {
+		return config.ServerConfig{}, err
+	}
+
 	transport, hasTransport, err := decodeTransport(target, &srv)
 	if err != nil {
 		return config.ServerConfig{}, err
@@ -695,6 +874,55 @@ func decodeServerConfig(raw map[string]any) (config.ServerConfig, error) {
 	return srv, nil
 }
 
+// decodeCacheConfig reads mcpx cache overrides (default_cache_ttl,
+// no_cache_tools, and per-tool cache flags under tools) from a server entry.
+func decodeCacheConfig(raw map[string]any, srv *config.ServerConfig) error {
+	ttl, err := decodeOptionalString(raw, "default_cache_ttl")
+	if err != nil {
+		return err
+	}
+	if ttl != "" {
+		d, err := time.ParseDuration(ttl)
+		if err != nil {
+			return fmt.Errorf("default_cache_ttl: invalid duration %q: %w", ttl, err)
+		}
+		if d <= 0 {
+			return fmt.Errorf("default_cache_ttl: must be > 0, got %q", ttl)
+		}
+		srv.DefaultCacheTTL = ttl
+	}
+
+	srv.NoCacheTools, err = decodeOptionalStringSlice(raw, "no_cache_tools")
+	if err != nil {
+		return err
+	}
+
+	tools, err := decodeOptionalObject(raw, "tools")
+	if err != nil {
+		return err
+	}
+	if len(tools) == 0 {
+		return nil
+	}
+	srv.Tools = make(map[string]config.ToolConfig, len(tools))
+	for name, value := range tools {
+		obj, ok := value.(map[string]any)
+		if !ok {
+			return fmt.Errorf("tools.%s must be an object", name)
+		}
+		var tc config.ToolConfig
+		if cacheValue, ok := obj["cache"]; ok {
+			cache, ok := cacheValue.(bool)
+			if !ok {
+				return fmt.Errorf("tools.%s.cache must be a boolean", name)
+			}
+			tc.Cache = &cache
+		}
+		srv.Tools[name] = tc
+	}
+	return nil
+}
+
 func decodeCommandAndArgs(raw map[string]any) (string, []string, error) {
 	args, err := decodeOptionalStringSlice(raw, "args")
 	if err != nil {
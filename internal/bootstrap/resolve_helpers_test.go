@@ -61,7 +61,7 @@ func TestFetchSourceURLSuccessAndHTTPErrorStatus(t *testing.T) {
 	}))
 	defer srv.Close()
 
-	okBody, err := fetchSourceURL(context.Background(), srv.URL+"/ok")
+	okBody, err := fetchSourceURL(context.Background(), srv.URL+"/ok", nil)
 	if err != nil {
 		t.Fatalf("fetchSourceURL(ok) error = %v, want nil", err)
 	}
@@ -69,7 +69,7 @@ func TestFetchSourceURLSuccessAndHTTPErrorStatus(t *testing.T) {
 		t.Fatalf("fetchSourceURL(ok) body = %q, want manifest content", got)
 	}
 
-	_, err = fetchSourceURL(context.Background(), srv.URL+"/bad")
+	_, err = fetchSourceURL(context.Background(), srv.URL+"/bad", nil)
 	if err == nil {
 		t.Fatal("fetchSourceURL(status>=400) error = nil, want non-nil")
 	}
@@ -4,8 +4,12 @@ import (
 	"context"
 	"encoding/base64"
 	"errors"
+	"net"
+	"net/http"
+	"net/http/httptest"
 	"os"
 	"path/filepath"
+	"reflect"
 	"strings"
 	"testing"
 )
@@ -67,6 +71,46 @@ func TestResolveManifestFileTOMLMCPServersSuccess(t *testing.T) {
 	}
 }
 
+func TestResolveManifestDecodesCacheConfig(t *testing.T) {
+	source := testdataPath(t, "manifest_stdio_cache.toml")
+	resolved, err := Resolve(context.Background(), source, ResolveOptions{})
+	if err != nil {
+		t.Fatalf("Resolve() error = %v", err)
+	}
+
+	srv := resolved.Server
+	if srv.DefaultCacheTTL != "2m" {
+		t.Fatalf("resolved.Server.DefaultCacheTTL = %q, want %q", srv.DefaultCacheTTL, "2m")
+	}
+	if len(srv.NoCacheTools) != 1 || srv.NoCacheTools[0] != "create_*" {
+		t.Fatalf("resolved.Server.NoCacheTools = %v, want [create_*]", srv.NoCacheTools)
+	}
+	if c := srv.Tools["search_repositories"].Cache; c == nil || !*c {
+		t.Fatalf("tools.search_repositories.cache = %v, want true", c)
+	}
+	if c := srv.Tools["get_me"].Cache; c == nil || *c {
+		t.Fatalf("tools.get_me.cache = %v, want false", c)
+	}
+}
+
+func TestResolveManifestRejectsInvalidCacheConfig(t *testing.T) {
+	cases := map[string]string{
+		`{"command":"foo-mcp","default_cache_ttl":"soon"}`:         "default_cache_ttl: invalid duration",
+		`{"command":"foo-mcp","default_cache_ttl":"0s"}`:           "default_cache_ttl: must be > 0",
+		`{"command":"foo-mcp","tools":{"search":{"cache":"yes"}}}`: "tools.search.cache must be a boolean",
+		`{"command":"foo-mcp","no_cache_tools":"search"}`:          "no_cache_tools must be an array of strings",
+	}
+	for manifest, want := range cases {
+		_, err := Resolve(context.Background(), StdinSource, ResolveOptions{
+			Name:  "foo",
+			Stdin: strings.NewReader(manifest),
+		})
+		if err == nil || !strings.Contains(err.Error(), want) {
+			t.Fatalf("Resolve(%s) error = %v, want to contain %q", manifest, err, want)
+		}
+	}
+}
+
 func TestResolveManifestFileHTTPSuccess(t *testing.T) {
 	source := testdataPath(t, "manifest_http.json")
 	resolved, err := Resolve(context.Background(), source, ResolveOptions{})
@@ -313,6 +357,125 @@ func TestResolveHTTPURLFallsBackToDirectMCPWithOverrideName(t *testing.T) {
 	}
 }
 
+func TestResolveDockerSourceInfersNameFromImage(t *testing.T) {
+	tests := []struct {
+		source string
+		want   string
+	}{
+		{source: "docker:ghcr.io/org/mcp-server:1.2", want: "mcp_server"},
+		{source: "docker:localhost:5000/github-mcp", want: "github_mcp"},
+		{source: "docker:mcp/fetch@sha256:abc123", want: "fetch"},
+	}
+	for _, tt := range tests {
+		resolved, err := Resolve(context.Background(), tt.source, ResolveOptions{})
+		if err != nil {
+			t.Fatalf("Resolve(%q) error = %v", tt.source, err)
+		}
+		if resolved.Name != tt.want {
+			t.Fatalf("Resolve(%q).Name = %q, want %q", tt.source, resolved.Name, tt.want)
+		}
+		if resolved.Server.Command != "docker" {
+			t.Fatalf("resolved.Server.Command = %q, want %q", resolved.Server.Command, "docker")
+		}
+		image := strings.TrimPrefix(tt.source, "docker:")
+		wantArgs := []string{"run", "-i", "--rm", image}
+		if !reflect.DeepEqual(resolved.Server.Args, wantArgs) {
+			t.Fatalf("resolved.Server.Args = %#v, want %#v", resolved.Server.Args, wantArgs)
+		}
+	}
+}
+
+func TestResolveDockerSourceMapsEnvToFlags(t *testing.T) {
+	resolved, err := Resolve(context.Background(), "docker:ghcr.io/github/github-mcp-server", ResolveOptions{
+		Name: "github",
+		Env:  []string{"GITHUB_TOKEN", "GH_HOST"},
+	})
+	if err != nil {
+		t.Fatalf("Resolve() error = %v", err)
+	}
+
+	if resolved.Name != "github" {
+		t.Fatalf("resolved.Name = %q, want %q", resolved.Name, "github")
+	}
+	want := []string{"run", "-i", "--rm", "-e", "GITHUB_TOKEN", "-e", "GH_HOST", "ghcr.io/github/github-mcp-server"}
+	if !reflect.DeepEqual(resolved.Server.Args, want) {
+		t.Fatalf("resolved.Server.Args = %#v, want %#v", resolved.Server.Args, want)
+	}
+}
+
+func TestResolveDockerSourceRejectsMissingImage(t *testing.T) {
+	_, err := Resolve(context.Background(), "docker:", ResolveOptions{})
+	if err == nil || !strings.Contains(err.Error(), "missing image") {
+		t.Fatalf("Resolve() error = %v, want missing image error", err)
+	}
+}
+
+func TestResolvePackageSourceExpandsToStdioCommand(t *testing.T) {
+	tests := []struct {
+		source   string
+		wantName string
+		wantCmd  string
+		wantArgs []string
+	}{
+		{
+			source:   "npm:@modelcontextprotocol/server-filesystem",
+			wantName: "server_filesystem",
+			wantCmd:  "npx",
+			wantArgs: []string{"-y", "@modelcontextprotocol/server-filesystem"},
+		},
+		{
+			source:   "npm:@scope/Weird.Pkg@1.2.3",
+			wantName: "weird_pkg",
+			wantCmd:  "npx",
+			wantArgs: []string{"-y", "@scope/Weird.Pkg@1.2.3"},
+		},
+		{
+			source:   "uvx:mcp-server-fetch",
+			wantName: "mcp_server_fetch",
+			wantCmd:  "uvx",
+			wantArgs: []string{"mcp-server-fetch"},
+		},
+		{
+			source:   "uvx:mcp-server-time==0.6",
+			wantName: "mcp_server_time",
+			wantCmd:  "uvx",
+			wantArgs: []string{"mcp-server-time==0.6"},
+		},
+	}
+	for _, tt := range tests {
+		resolved, err := Resolve(context.Background(), tt.source, ResolveOptions{})
+		if err != nil {
+			t.Fatalf("Resolve(%q) error = %v", tt.source, err)
+		}
+		if resolved.Name != tt.wantName {
+			t.Fatalf("Resolve(%q).Name = %q, want %q", tt.source, resolved.Name, tt.wantName)
+		}
+		if resolved.Server.Command != tt.wantCmd {
+			t.Fatalf("Resolve(%q).Server.Command = %q, want %q", tt.source, resolved.Server.Command, tt.wantCmd)
+		}
+		if !reflect.DeepEqual(resolved.Server.Args, tt.wantArgs) {
+			t.Fatalf("Resolve(%q).Server.Args = %#v, want %#v", tt.source, resolved.Server.Args, tt.wantArgs)
+		}
+	}
+}
+
+func TestResolvePackageSourceUsesOverrideName(t *testing.T) {
+	resolved, err := Resolve(context.Background(), "npm:@modelcontextprotocol/server-github", ResolveOptions{Name: "github"})
+	if err != nil {
+		t.Fatalf("Resolve() error = %v", err)
+	}
+	if resolved.Name != "github" {
+		t.Fatalf("resolved.Name = %q, want %q", resolved.Name, "github")
+	}
+}
+
+func TestResolvePackageSourceRejectsMissingPackage(t *testing.T) {
+	_, err := Resolve(context.Background(), "uvx:", ResolveOptions{})
+	if err == nil || !strings.Contains(err.Error(), "missing package") {
+		t.Fatalf("Resolve() error = %v, want missing package error", err)
+	}
+}
+
 func TestResolveHTTPURLFallsBackToDirectMCPWhenBodyIsNotManifest(t *testing.T) {
 	source := "https://example.com/mcp"
 	resolved, err := Resolve(context.Background(), source, ResolveOptions{
@@ -613,3 +776,171 @@ func testdataPath(t *testing.T, name string) string {
 	t.Helper()
 	return filepath.Join("testdata", name)
 }
+
+func TestResolveStdinManifest(t *testing.T) {
+	resolved, err := Resolve(context.Background(), StdinSource, ResolveOptions{
+		Name:  "foo",
+		Stdin: strings.NewReader(`{"command":"foo-mcp","args":["--stdio"]}`),
+		ReadFile: func(string) ([]byte, error) {
+			t.Fatal("ReadFile called for stdin source")
+			return nil, nil
+		},
+	})
+	if err != nil {
+		t.Fatalf("Resolve(-) error = %v", err)
+	}
+	if resolved.Name != "foo" || resolved.Server.Command != "foo-mcp" {
+		t.Fatalf("Resolve(-) = %#v, want foo-mcp named foo", resolved)
+	}
+
+	_, err = Resolve(context.Background(), StdinSource, ResolveOptions{
+		Stdin: strings.NewReader(`{"command":"foo-mcp"}`),
+	})
+	if err == nil || !strings.Contains(err.Error(), "--name") {
+		t.Fatalf("Resolve(-, unnamed) error = %v, want --name hint", err)
+	}
+
+	if _, err := Resolve(context.Background(), StdinSource, ResolveOptions{Stdin: strings.NewReader("  \n")}); err == nil {
+		t.Fatal("Resolve(-, empty) error = nil, want non-nil")
+	}
+}
+
+func TestCheckTrustedSource(t *testing.T) {
+	trusted := []string{"mcp.deepwiki.com", "*.example.com", "cursor:"}
+	tests := []struct {
+		source  string
+		allowed bool
+	}{
+		{"https://mcp.deepwiki.com/mcp", true},
+		{"https://MCP.DeepWiki.com:8443/mcp", true},
+		{"https://api.example.com/manifest.json", true},
+		{"https://example.com/manifest.json", false},
+		{"https://evil.test/mcp", false},
+		{"cursor://anysphere.cursor-deeplink/mcp/install?name=x&config=e30=", true},
+		{"vscode://ms-vscode.vscode/mcp/install?name=x&config=e30=", false},
+		{"./manifest.json", true},
+		{StdinSource, true},
+		{"docker:mcp/fetch", false},
+		{"docker:registry.example.com/mcp/fetch:1.0", true},
+		{"docker:ghcr.io/acme/mcp:latest", false},
+		{"npm:@modelcontextprotocol/server-github", false},
+		{"uvx:mcp-server-fetch", false},
+	}
+	for _, tt := range tests {
+		err := CheckTrustedSource(tt.source, trusted)
+		if tt.allowed && err != nil {
+			t.Errorf("CheckTrustedSource(%q) error = %v, want nil", tt.source, err)
+		}
+		if !tt.allowed && !IsUntrustedSourceError(err) {
+			t.Errorf("CheckTrustedSource(%q) error = %v, want untrusted source error", tt.source, err)
+		}
+	}
+
+	if err := CheckTrustedSource("https://evil.test/mcp", nil); err != nil {
+		t.Fatalf("CheckTrustedSource(empty allowlist) error = %v, want nil", err)
+	}
+}
+
+func TestCheckTrustedSourceGatesDockerImagesByRegistry(t *testing.T) {
+	tests := []struct {
+		source  string
+		trusted []string
+		allowed bool
+	}{
+		{"docker:mcp/fetch", []string{"docker.io"}, true},
+		{"docker:fetch", []string{"docker.io"}, true},
+		{"docker:ghcr.io/acme/mcp:latest", []string{"ghcr.io"}, true},
+		{"docker:ghcr.io/acme/mcp:latest", []string{"docker.io"}, false},
+		{"docker:localhost:5000/mcp", []string{"localhost"}, true},
+		{"docker:registry.example.com:5000/mcp", []string{"*.example.com"}, true},
+		{"docker:ghcr.io/acme/mcp", []string{"docker:"}, true},
+		{"docker:mcp/fetch", []string{"mcp.deepwiki.com"}, false},
+	}
+	for _, tt := range tests {
+		err := CheckTrustedSource(tt.source, tt.trusted)
+		if tt.allowed && err != nil {
+			t.Errorf("CheckTrustedSource(%q, %v) error = %v, want nil", tt.source, tt.trusted, err)
+		}
+		if !tt.allowed && !IsUntrustedSourceError(err) {
+			t.Errorf("CheckTrustedSource(%q, %v) error = %v, want untrusted source error", tt.source, tt.trusted, err)
+		}
+	}
+
+	_, err := Resolve(context.Background(), "docker:ghcr.io/acme/mcp", ResolveOptions{TrustedHosts: []string{"docker.io"}})
+	if !IsUntrustedSourceError(err) {
+		t.Fatalf("Resolve(docker:ghcr.io/...) error = %v, want untrusted source error", err)
+	}
+}
+
+func TestCheckTrustedSourceGatesPackageSourcesByRegistry(t *testing.T) {
+	tests := []struct {
+		source  string
+		trusted []string
+		allowed bool
+	}{
+		{"npm:@modelcontextprotocol/server-github", []string{"registry.npmjs.org"}, true},
+		{"npm:foo-mcp", []string{"npm:"}, true},
+		{"npm:foo-mcp", []string{"pypi.org", "uvx:"}, false},
+		{"uvx:mcp-server-fetch", []string{"pypi.org"}, true},
+		{"uvx:mcp-server-fetch", []string{"uvx:"}, true},
+		{"uvx:mcp-server-fetch", []string{"registry.npmjs.org", "npm:"}, false},
+	}
+	for _, tt := range tests {
+		err := CheckTrustedSource(tt.source, tt.trusted)
+		if tt.allowed && err != nil {
+			t.Errorf("CheckTrustedSource(%q, %v) error = %v, want nil", tt.source, tt.trusted, err)
+		}
+		if !tt.allowed && !IsUntrustedSourceError(err) {
+			t.Errorf("CheckTrustedSource(%q, %v) error = %v, want untrusted source error", tt.source, tt.trusted, err)
+		}
+	}
+
+	for _, source := range []string{"npm:foo-mcp", "uvx:mcp-server-fetch"} {
+		_, err := Resolve(context.Background(), source, ResolveOptions{TrustedHosts: []string{"mcp.example.com"}})
+		if !IsUntrustedSourceError(err) {
+			t.Fatalf("Resolve(%s) error = %v, want untrusted source error", source, err)
+		}
+	}
+}
+
+func TestResolveRefusesUntrustedHostBeforeFetching(t *testing.T) {
+	_, err := Resolve(context.Background(), "http://127.0.0.1:1/mcp", ResolveOptions{TrustedHosts: []string{"mcp.example.com"}})
+	if !IsUntrustedSourceError(err) {
+		t.Fatalf("Resolve() error = %v, want untrusted source error", err)
+	}
+	if IsSourceAccessError(err) {
+		t.Fatalf("Resolve() error = %v, want refusal before any fetch", err)
+	}
+}
+
+func TestResolveRefusesRedirectToUntrustedHost(t *testing.T) {
+	target := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`{"mcpServers":{"demo":{"command":"echo"}}}`))
+	}))
+	defer target.Close()
+	_, port, _ := net.SplitHostPort(strings.TrimPrefix(target.URL, "http://"))
+
+	origin := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/same-host.json":
+			http.Redirect(w, r, target.URL+"/manifest.json", http.StatusFound)
+		default:
+			http.Redirect(w, r, "http://localhost:"+port+"/manifest.json", http.StatusFound)
+		}
+	}))
+	defer origin.Close()
+
+	opts := ResolveOptions{TrustedHosts: []string{"127.0.0.1"}}
+	_, err := Resolve(context.Background(), origin.URL+"/manifest.json", opts)
+	if !IsUntrustedSourceError(err) {
+		t.Fatalf("Resolve(redirect to localhost) error = %v, want untrusted source error", err)
+	}
+
+	resolved, err := Resolve(context.Background(), origin.URL+"/same-host.json", opts)
+	if err != nil {
+		t.Fatalf("Resolve(redirect to trusted host) error = %v", err)
+	}
+	if resolved.Name != "demo" {
+		t.Fatalf("Resolve(redirect to trusted host) name = %q, want demo", resolved.Name)
+	}
+}
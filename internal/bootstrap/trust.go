@@ -0,0 +1,97 @@
+package bootstrap
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"path"
+	"strings"
+)
+
+// UntrustedSourceError reports a remote source outside the configured
+// trusted_install_hosts allowlist.
+type UntrustedSourceError struct {
+	Source string
+	Host   string
+}
+
+func (e *UntrustedSourceError) Error() string {
+	return fmt.Sprintf("source %q (host %q) is not in trusted_install_hosts", e.Source, e.Host)
+}
+
+// IsUntrustedSourceError reports whether err was caused by the allowlist.
+func IsUntrustedSourceError(err error) bool {
+	var typed *UntrustedSourceError
+	return errors.As(err, &typed)
+}
+
+// CheckTrustedSource enforces trusted against install links, http(s)
+// sources, docker: images, and npm:/uvx: packages. Entries are host globs
+// ("example.com", "*.example.com") or a scheme followed by a colon
+// ("cursor:", "docker:", "npm:") to trust every source of that scheme. A
+// docker: image is checked by its registry host ("ghcr.io", or "docker.io"
+// for Docker Hub images); npm: and uvx: packages by their default registry
+// ("registry.npmjs.org", "pypi.org"). Local files and stdin are always
+// allowed, as is everything when trusted is empty.
+func CheckTrustedSource(source string, trusted []string) error {
+	if len(trusted) == 0 {
+		return nil
+	}
+	scheme, host, ok, err := trustTarget(source)
+	if err != nil || !ok {
+		return err
+	}
+
+	for _, entry := range trusted {
+		entry = strings.ToLower(strings.TrimSpace(entry))
+		if entry == "" {
+			continue
+		}
+		if s, ok := strings.CutSuffix(entry, ":"); ok {
+			if s == scheme {
+				return nil
+			}
+			continue
+		}
+		if matched, err := path.Match(entry, host); err == nil && matched {
+			return nil
+		}
+	}
+	return &UntrustedSourceError{Source: source, Host: host}
+}
+
+// trustTarget returns the scheme and host trusted_install_hosts is matched
+// against for source, and false for sources the allowlist does not cover.
+func trustTarget(source string) (scheme, host string, ok bool, err error) {
+	if isDockerSource(source) {
+		image := strings.TrimSpace(strings.TrimPrefix(source, dockerSourcePrefix))
+		return "docker", imageRegistryHost(image), true, nil
+	}
+	for prefix, host := range packageRegistryHosts {
+		if strings.HasPrefix(source, prefix) {
+			return strings.TrimSuffix(prefix, ":"), host, true, nil
+		}
+	}
+	if !isInstallLinkSource(source) && !isHTTPURL(source) {
+		return "", "", false, nil
+	}
+
+	u, err := url.Parse(source)
+	if err != nil {
+		return "", "", false, fmt.Errorf("invalid source URL: %w", err)
+	}
+	return strings.ToLower(u.Scheme), strings.ToLower(u.Hostname()), true, nil
+}
+
+// imageRegistryHost returns the registry an image reference pulls from.
+// Like docker, it reads the first path segment as a registry only when it
+// looks like a host (has a dot or port, or is localhost), and defaults to
+// Docker Hub.
+func imageRegistryHost(image string) string {
+	first, _, found := strings.Cut(image, "/")
+	if found && (strings.ContainsAny(first, ".:") || first == "localhost") {
+		host, _, _ := strings.Cut(first, ":")
+		return strings.ToLower(host)
+	}
+	return "docker.io"
+}
@@ -7,34 +7,129 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"strings"
+	"sync/atomic"
 	"time"
 
 	"github.com/lydakis/mcpx/internal/paths"
 )
 
 type entry struct {
-	Content  []byte    `json:"content"`
-	ExitCode int       `json:"exit_code"`
-	Created  time.Time `json:"created"`
-	Expires  time.Time `json:"expires"`
+	// Server and Tool record the key an entry was stored under so Delete
+	// can find it; the file name is a digest.
+	Server      string    `json:"server,omitempty"`
+	Tool        string    `json:"tool,omitempty"`
+	Content     []byte    `json:"content"`
+	ExitCode    int       `json:"exit_code"`
+	ContentType string    `json:"content_type,omitempty"`
+	Encoding    string    `json:"encoding,omitempty"`
+	Created     time.Time `json:"created"`
+	Expires     time.Time `json:"expires"`
+}
+
+// Response is a tool call response as the cache stores it: the unwrapped
+// output, its exit code, and the content type and encoding describing it.
+type Response struct {
+	Content     []byte
+	ExitCode    int
+	ContentType string
+	Encoding    string
+}
+
+func (e entry) response() Response {
+	return Response{Content: e.Content, ExitCode: e.ExitCode, ContentType: e.ContentType, Encoding: e.Encoding}
+}
+
+// Lookup counters since process start, reported by Stats.
+var hits, misses, stores atomic.Uint64
+
+// StatsSnapshot describes the on-disk cache and the lookups this process
+// has made since it started.
+type StatsSnapshot struct {
+	Entries int     `json:"entries"`
+	Bytes   int64   `json:"bytes"`
+	Hits    uint64  `json:"hits"`
+	Misses  uint64  `json:"misses"`
+	Stores  uint64  `json:"stores"`
+	HitRate float64 `json:"hit_rate"`
+}
+
+// Hit is a cached response returned by GetStale.
+type Hit struct {
+	Response
+	Age time.Duration
+	TTL time.Duration
+	// Stale is how long ago the entry expired, or zero while it is fresh.
+	Stale time.Duration
 }
 
 // Get looks up a cached response. Returns nil if not found or expired.
-func Get(server, tool string, args json.RawMessage) ([]byte, int, bool) {
-	e, _, ok := getEntry(server, tool, args)
+func Get(server, tool string, args json.RawMessage) (Response, bool) {
+	e, _, ok := getEntry(server, tool, args, 0)
 	if !ok {
-		return nil, 0, false
+		misses.Add(1)
+		return Response{}, false
+	}
+	hits.Add(1)
+	return e.response(), true
+}
+
+// GetStale looks up a cached response that is fresh or expired less than
+// window ago.
+func GetStale(server, tool string, args json.RawMessage, window time.Duration) (Hit, bool) {
+	e, path, ok := getEntry(server, tool, args, window)
+	if !ok {
+		misses.Add(1)
+		return Hit{}, false
+	}
+	hits.Add(1)
+	age, ttl := entryAge(e, path)
+	hit := Hit{Response: e.response(), Age: age, TTL: ttl}
+	if stale := time.Since(e.Expires); stale > 0 {
+		hit.Stale = stale
+	}
+	return hit, true
+}
+
+// Stats counts the entry files on disk, expired ones included until a
+// lookup removes them, and reports the Get and Put counters. HitRate is
+// hits over lookups, or zero before the first lookup.
+func Stats() (StatsSnapshot, error) {
+	s := StatsSnapshot{
+		Hits:   hits.Load(),
+		Misses: misses.Load(),
+		Stores: stores.Load(),
+	}
+	if lookups := s.Hits + s.Misses; lookups > 0 {
+		s.HitRate = float64(s.Hits) / float64(lookups)
+	}
+
+	paths, err := filepath.Glob(filepath.Join(cacheDir(), "*.json"))
+	if err != nil {
+		return s, err
 	}
-	return e.Content, e.ExitCode, true
+	for _, path := range paths {
+		info, err := os.Stat(path)
+		if err != nil {
+			continue
+		}
+		s.Entries++
+		s.Bytes += info.Size()
+	}
+	return s, nil
 }
 
 // GetMetadata returns cache age and ttl when a valid entry exists.
 func GetMetadata(server, tool string, args json.RawMessage) (time.Duration, time.Duration, bool) {
-	e, path, ok := getEntry(server, tool, args)
+	e, path, ok := getEntry(server, tool, args, 0)
 	if !ok {
 		return 0, 0, false
 	}
+	age, ttl := entryAge(e, path)
+	return age, ttl, true
+}
 
+func entryAge(e entry, path string) (time.Duration, time.Duration) {
 	created := e.Created
 	if created.IsZero() {
 		if st, err := os.Stat(path); err == nil {
@@ -55,11 +150,11 @@ func GetMetadata(server, tool string, args json.RawMessage) (time.Duration, time
 		age = 0
 	}
 
-	return age, ttl, true
+	return age, ttl
 }
 
 // Put stores a response in the cache.
-func Put(server, tool string, args json.RawMessage, content []byte, exitCode int, ttl time.Duration) error {
+func Put(server, tool string, args json.RawMessage, resp Response, ttl time.Duration) error {
 	dir := cacheDir()
 	if err := paths.EnsureDir(dir); err != nil {
 		return err
@@ -67,10 +162,14 @@ func Put(server, tool string, args json.RawMessage, content []byte, exitCode int
 
 	now := time.Now()
 	e := entry{
-		Content:  content,
-		ExitCode: exitCode,
-		Created:  now,
-		Expires:  now.Add(ttl),
+		Server:      server,
+		Tool:        tool,
+		Content:     resp.Content,
+		ExitCode:    resp.ExitCode,
+		ContentType: resp.ContentType,
+		Encoding:    resp.Encoding,
+		Created:     now,
+		Expires:     now.Add(ttl),
 	}
 
 	data, err := json.Marshal(e)
@@ -78,10 +177,63 @@ func Put(server, tool string, args json.RawMessage, content []byte, exitCode int
 		return err
 	}
 
-	return os.WriteFile(entryPath(server, tool, args), data, 0600)
+	if err := os.WriteFile(entryPath(server, tool, args), data, 0600); err != nil {
+		return err
+	}
+	stores.Add(1)
+	return nil
+}
+
+// Clear removes every cached response and returns how many were removed.
+func Clear() (int, error) {
+	return removeEntries(func(string) bool { return true })
+}
+
+// Delete removes cached responses for server, or only for its tool when
+// tool is non-empty, and returns how many were removed. Entries stored
+// under a cwd- or header-scoped key ("server@digest") count as the server's. Entries
+// written before keys were recorded can only be removed by Clear.
+func Delete(server, tool string) (int, error) {
+	return removeEntries(func(path string) bool {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return false
+		}
+		var e entry
+		if err := json.Unmarshal(data, &e); err != nil {
+			return false
+		}
+		if e.Server != server && !strings.HasPrefix(e.Server, server+"@") {
+			return false
+		}
+		return tool == "" || e.Tool == tool
+	})
+}
+
+func removeEntries(match func(path string) bool) (int, error) {
+	paths, err := filepath.Glob(filepath.Join(cacheDir(), "*.json"))
+	if err != nil {
+		return 0, err
+	}
+	removed := 0
+	for _, path := range paths {
+		if !match(path) {
+			continue
+		}
+		if err := os.Remove(path); err != nil {
+			if os.IsNotExist(err) {
+				continue
+			}
+			return removed, err
+		}
+		removed++
+	}
+	return removed, nil
 }
 
-func getEntry(server, tool string, args json.RawMessage) (entry, string, bool) {
+// getEntry reads an entry that expired no more than stale ago, removing
+// it once it is older than that.
+func getEntry(server, tool string, args json.RawMessage, stale time.Duration) (entry, string, bool) {
 	path := entryPath(server, tool, args)
 	data, err := os.ReadFile(path)
 	if err != nil {
@@ -94,7 +246,7 @@ func getEntry(server, tool string, args json.RawMessage) (entry, string, bool) {
 		return entry{}, path, false
 	}
 
-	if time.Now().After(e.Expires) {
+	if time.Now().After(e.Expires.Add(stale)) {
 		_ = os.Remove(path)
 		return entry{}, path, false
 	}
@@ -13,19 +13,23 @@ func TestPutGetRoundTrip(t *testing.T) {
 	t.Setenv("HOME", t.TempDir())
 
 	args := json.RawMessage(`{"query":"mcp"}`)
-	if err := Put("github", "search_repositories", args, []byte("cached\n"), 0, 30*time.Second); err != nil {
+	stored := Response{Content: []byte("cached\n"), ContentType: "application/json", Encoding: "text"}
+	if err := Put("github", "search_repositories", args, stored, 30*time.Second); err != nil {
 		t.Fatalf("Put() error = %v", err)
 	}
 
-	content, exitCode, ok := Get("github", "search_repositories", args)
+	got, ok := Get("github", "search_repositories", args)
 	if !ok {
 		t.Fatal("Get() cache miss, want hit")
 	}
-	if string(content) != "cached\n" {
-		t.Fatalf("Get() content = %q, want %q", content, "cached\n")
+	if string(got.Content) != "cached\n" {
+		t.Fatalf("Get() content = %q, want %q", got.Content, "cached\n")
 	}
-	if exitCode != 0 {
-		t.Fatalf("Get() exit code = %d, want 0", exitCode)
+	if got.ExitCode != 0 {
+		t.Fatalf("Get() exit code = %d, want 0", got.ExitCode)
+	}
+	if got.ContentType != stored.ContentType || got.Encoding != stored.Encoding {
+		t.Fatalf("Get() hint = %q, %q; want %q, %q", got.ContentType, got.Encoding, stored.ContentType, stored.Encoding)
 	}
 
 	path := entryPath("github", "search_repositories", args)
@@ -43,7 +47,7 @@ func TestGetExpiredEntryRemovesFile(t *testing.T) {
 	t.Setenv("HOME", t.TempDir())
 
 	args := json.RawMessage(`{"query":"mcp"}`)
-	if err := Put("github", "search_repositories", args, []byte("stale"), 0, -1*time.Second); err != nil {
+	if err := Put("github", "search_repositories", args, Response{Content: []byte("stale")}, -1*time.Second); err != nil {
 		t.Fatalf("Put() error = %v", err)
 	}
 
@@ -52,7 +56,7 @@ func TestGetExpiredEntryRemovesFile(t *testing.T) {
 		t.Fatalf("expected cache file before read, stat error: %v", err)
 	}
 
-	_, _, ok := Get("github", "search_repositories", args)
+	_, ok := Get("github", "search_repositories", args)
 	if ok {
 		t.Fatal("Get() hit = true, want false for expired entry")
 	}
@@ -61,6 +65,35 @@ func TestGetExpiredEntryRemovesFile(t *testing.T) {
 	}
 }
 
+func TestGetStaleServesEntriesWithinWindow(t *testing.T) {
+	t.Setenv("XDG_CACHE_HOME", t.TempDir())
+	t.Setenv("HOME", t.TempDir())
+
+	args := json.RawMessage(`{"query":"mcp"}`)
+	if err := Put("github", "search_repositories", args, Response{Content: []byte("fresh")}, time.Minute); err != nil {
+		t.Fatalf("Put() error = %v", err)
+	}
+	hit, ok := GetStale("github", "search_repositories", args, time.Minute)
+	if !ok || string(hit.Content) != "fresh" || hit.Stale != 0 || hit.TTL != time.Minute {
+		t.Fatalf("GetStale(fresh) = %+v, %v; want fresh hit with ttl=1m", hit, ok)
+	}
+
+	if err := Put("github", "search_repositories", args, Response{Content: []byte("stale")}, -time.Second); err != nil {
+		t.Fatalf("Put() error = %v", err)
+	}
+	hit, ok = GetStale("github", "search_repositories", args, time.Minute)
+	if !ok || string(hit.Content) != "stale" || hit.Stale < time.Second {
+		t.Fatalf("GetStale(stale) = %+v, %v; want stale hit at least 1s past expiry", hit, ok)
+	}
+
+	if _, ok := GetStale("github", "search_repositories", args, time.Millisecond); ok {
+		t.Fatal("GetStale() hit = true, want false past the stale window")
+	}
+	if _, err := os.Stat(entryPath("github", "search_repositories", args)); !os.IsNotExist(err) {
+		t.Fatalf("expected entry past the stale window to be removed, stat error = %v", err)
+	}
+}
+
 func TestGetCorruptEntryRemovesFile(t *testing.T) {
 	t.Setenv("XDG_CACHE_HOME", t.TempDir())
 	t.Setenv("HOME", t.TempDir())
@@ -74,7 +107,7 @@ func TestGetCorruptEntryRemovesFile(t *testing.T) {
 		t.Fatalf("write corrupt cache file: %v", err)
 	}
 
-	_, _, ok := Get("github", "search_repositories", args)
+	_, ok := Get("github", "search_repositories", args)
 	if ok {
 		t.Fatal("Get() hit = true, want false for corrupt entry")
 	}
@@ -105,7 +138,7 @@ func TestGetMetadataReturnsAgeAndTTLForHit(t *testing.T) {
 	t.Setenv("HOME", t.TempDir())
 
 	args := json.RawMessage(`{"query":"mcp"}`)
-	if err := Put("github", "search_repositories", args, []byte("cached\n"), 0, 2*time.Second); err != nil {
+	if err := Put("github", "search_repositories", args, Response{Content: []byte("cached\n")}, 2*time.Second); err != nil {
 		t.Fatalf("Put() error = %v", err)
 	}
 
@@ -212,3 +245,77 @@ func TestGetMetadataUsesFileModTimeWhenCreatedMissing(t *testing.T) {
 		t.Fatalf("GetMetadata() ttl = %s, want > 25s based on expires-modtime", ttl)
 	}
 }
+
+func TestDeleteAndClearRemoveMatchingEntries(t *testing.T) {
+	t.Setenv("XDG_CACHE_HOME", t.TempDir())
+	t.Setenv("HOME", t.TempDir())
+
+	put := func(server, tool, args string) {
+		t.Helper()
+		if err := Put(server, tool, json.RawMessage(args), Response{Content: []byte("ok")}, time.Minute); err != nil {
+			t.Fatalf("Put(%s, %s) error = %v", server, tool, err)
+		}
+	}
+	put("github", "search", `{"q":"a"}`)
+	put("github", "search", `{"q":"b"}`)
+	put("github@0123456789abcdef", "get_repo", `{}`)
+	put("linear", "search", `{}`)
+	put("githubx", "search", `{}`)
+
+	if n, err := Delete("github", "search"); err != nil || n != 2 {
+		t.Fatalf("Delete(github, search) = %d, %v; want 2", n, err)
+	}
+	if _, ok := Get("github@0123456789abcdef", "get_repo", json.RawMessage(`{}`)); !ok {
+		t.Fatal("Get(github get_repo) miss, want other tools kept")
+	}
+	if n, err := Delete("github", ""); err != nil || n != 1 {
+		t.Fatalf("Delete(github) = %d, %v; want the cwd-scoped entry", n, err)
+	}
+	if _, ok := Get("githubx", "search", json.RawMessage(`{}`)); !ok {
+		t.Fatal("Get(githubx search) miss, want servers sharing a prefix kept")
+	}
+	if n, err := Clear(); err != nil || n != 2 {
+		t.Fatalf("Clear() = %d, %v; want 2", n, err)
+	}
+	if n, err := Clear(); err != nil || n != 0 {
+		t.Fatalf("Clear(empty) = %d, %v; want 0", n, err)
+	}
+}
+
+func TestStatsCountsEntriesAndLookups(t *testing.T) {
+	t.Setenv("XDG_CACHE_HOME", t.TempDir())
+	t.Setenv("HOME", t.TempDir())
+	hits.Store(0)
+	misses.Store(0)
+	stores.Store(0)
+
+	if s, err := Stats(); err != nil || s != (StatsSnapshot{}) {
+		t.Fatalf("Stats(empty) = %+v, %v; want zero", s, err)
+	}
+
+	args := json.RawMessage(`{"q":"x"}`)
+	if _, ok := Get("github", "search", args); ok {
+		t.Fatal("Get() hit before Put")
+	}
+	if err := Put("github", "search", args, Response{Content: []byte("result")}, time.Minute); err != nil {
+		t.Fatalf("Put() error = %v", err)
+	}
+	for i := 0; i < 3; i++ {
+		if _, ok := Get("github", "search", args); !ok {
+			t.Fatal("Get() miss after Put")
+		}
+	}
+
+	s, err := Stats()
+	if err != nil {
+		t.Fatalf("Stats() error = %v", err)
+	}
+	info, err := os.Stat(entryPath("github", "search", args))
+	if err != nil {
+		t.Fatalf("stat entry: %v", err)
+	}
+	want := StatsSnapshot{Entries: 1, Bytes: info.Size(), Hits: 3, Misses: 1, Stores: 1, HitRate: 0.75}
+	if s != want {
+		t.Fatalf("Stats() = %+v, want %+v", s, want)
+	}
+}
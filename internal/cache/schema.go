@@ -0,0 +1,68 @@
+package cache
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/lydakis/mcpx/internal/paths"
+)
+
+// SchemaTTL is how long a tool schema stays cached for help, completion,
+// and client-side argument checks.
+const SchemaTTL = 5 * time.Minute
+
+// GetSchema returns the tool_schema payload stored for server and tool
+// under configHash, the digest of the server's config when it was fetched.
+// A changed config produces a different key, so stale schemas are never
+// served after an edit.
+func GetSchema(server, tool, configHash string) ([]byte, bool) {
+	path := schemaPath(server, tool, configHash)
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, false
+	}
+
+	var e entry
+	if err := json.Unmarshal(data, &e); err != nil || time.Now().After(e.Expires) {
+		_ = os.Remove(path)
+		return nil, false
+	}
+	return e.Content, true
+}
+
+// PutSchema stores a tool_schema payload for SchemaTTL.
+func PutSchema(server, tool, configHash string, payload []byte) error {
+	dir := schemaDir()
+	if err := paths.EnsureDir(dir); err != nil {
+		return err
+	}
+
+	now := time.Now()
+	data, err := json.Marshal(entry{
+		Server:  server,
+		Tool:    tool,
+		Content: payload,
+		Created: now,
+		Expires: now.Add(SchemaTTL),
+	})
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(schemaPath(server, tool, configHash), data, 0600)
+}
+
+func schemaPath(server, tool, configHash string) string {
+	h := sha256.New()
+	fmt.Fprintf(h, "%s\x00%s\x00%s", server, tool, configHash)
+	key := hex.EncodeToString(h.Sum(nil))[:32]
+	return filepath.Join(schemaDir(), key+".json")
+}
+
+func schemaDir() string {
+	return filepath.Join(paths.CacheDir(), "schemas")
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 	"io"
+	"os"
 	"strings"
 
 	"github.com/lydakis/mcpx/internal/bootstrap"
@@ -13,6 +14,10 @@ import (
 	"github.com/lydakis/mcpx/internal/paths"
 )
 
+// addStdin is read when the add source is "-".
+var addStdin io.Reader = os.Stdin
+
+// headerArg is one --header or --env override.
 type headerArg struct {
 	name  string
 	value string
@@ -22,8 +27,15 @@ type addArgs struct {
 	source    string
 	name      string
 	headers   []headerArg
+	env       []headerArg
 	overwrite bool
+	force     bool
 	help      bool
+
+	// verify lists the new server's tools after saving; verifyRequired
+	// also fails the add when that check fails.
+	verify         bool
+	verifyRequired bool
 }
 
 func maybeHandleAddCommand(args []string, cfg *config.Config, stdout, stderr io.Writer) (bool, int) {
@@ -52,11 +64,32 @@ func runAddCommand(args []string, stdout, stderr io.Writer) int {
 		return ipc.ExitOK
 	}
 
-	resolved, err := bootstrap.Resolve(context.Background(), parsed.source, bootstrap.ResolveOptions{
-		Name: parsed.name,
-	})
+	cfgPath := paths.ConfigFile()
+	cfg, err := config.LoadForEditFrom(cfgPath)
+	if err != nil {
+		fmt.Fprintf(stderr, "mcpx: add: loading config: %v\n", err)
+		return ipc.ExitInternal
+	}
+	if cfg.Servers == nil {
+		cfg.Servers = make(map[string]config.ServerConfig)
+	}
+
+	opts := bootstrap.ResolveOptions{
+		Name:  parsed.name,
+		Stdin: addStdin,
+	}
+	for _, env := range parsed.env {
+		opts.Env = append(opts.Env, env.name)
+	}
+	if !parsed.force {
+		opts.TrustedHosts = cfg.TrustedInstallHosts
+	}
+	resolved, err := bootstrap.Resolve(context.Background(), parsed.source, opts)
 	if err != nil {
 		fmt.Fprintf(stderr, "mcpx: add: %v\n", err)
+		if bootstrap.IsUntrustedSourceError(err) {
+			fmt.Fprintln(stderr, "mcpx: add: add the host to trusted_install_hosts or rerun with --force")
+		}
 		return classifyResolveErrorExitCode(err)
 	}
 	if len(parsed.headers) > 0 {
@@ -68,15 +101,14 @@ func runAddCommand(args []string, stdout, stderr io.Writer) int {
 			resolved.Server.Headers = httpheaders.Set(resolved.Server.Headers, header.name, header.value)
 		}
 	}
-
-	cfgPath := paths.ConfigFile()
-	cfg, err := config.LoadForEditFrom(cfgPath)
-	if err != nil {
-		fmt.Fprintf(stderr, "mcpx: add: loading config: %v\n", err)
-		return ipc.ExitInternal
-	}
-	if cfg.Servers == nil {
-		cfg.Servers = make(map[string]config.ServerConfig)
+	if len(parsed.env) > 0 {
+		if strings.TrimSpace(resolved.Server.Command) == "" {
+			fmt.Fprintln(stderr, "mcpx: add: --env can only be used with stdio servers")
+			return ipc.ExitUsageErr
+		}
+		for _, env := range parsed.env {
+			resolved.Server.Env = httpheaders.Set(resolved.Server.Env, env.name, env.value)
+		}
 	}
 
 	_, exists := cfg.Servers[resolved.Name]
@@ -105,6 +137,9 @@ func runAddCommand(args []string, stdout, stderr io.Writer) int {
 		verb = "Updated"
 	}
 	fmt.Fprintf(stdout, "%s server %q in %s\n", verb, resolved.Name, cfgPath)
+	if parsed.verify {
+		return verifyAddedServer(resolved.Name, parsed.verifyRequired, stdout, stderr)
+	}
 	return ipc.ExitOK
 }
 
@@ -125,6 +160,13 @@ func parseAddArgs(args []string) (*addArgs, error) {
 			parsed.help = true
 		case arg == "--overwrite":
 			parsed.overwrite = true
+		case arg == "--force":
+			parsed.force = true
+		case arg == "--verify":
+			parsed.verify = true
+		case arg == "--verify-required":
+			parsed.verify = true
+			parsed.verifyRequired = true
 		case strings.HasPrefix(arg, "--header="):
 			if err := parsed.addHeader(strings.TrimSpace(strings.TrimPrefix(arg, "--header="))); err != nil {
 				return nil, err
@@ -137,6 +179,18 @@ func parseAddArgs(args []string) (*addArgs, error) {
 			if err := parsed.addHeader(strings.TrimSpace(args[i])); err != nil {
 				return nil, err
 			}
+		case strings.HasPrefix(arg, "--env="):
+			if err := parsed.addEnv(strings.TrimPrefix(arg, "--env=")); err != nil {
+				return nil, err
+			}
+		case arg == "--env":
+			if i+1 >= len(args) {
+				return nil, fmt.Errorf("invalid --env: missing NAME=VALUE")
+			}
+			i++
+			if err := parsed.addEnv(args[i]); err != nil {
+				return nil, err
+			}
 		case strings.HasPrefix(arg, "--name="):
 			value := strings.TrimSpace(strings.TrimPrefix(arg, "--name="))
 			if value == "" {
@@ -153,7 +207,7 @@ func parseAddArgs(args []string) (*addArgs, error) {
 				return nil, fmt.Errorf("missing value for --name")
 			}
 			parsed.name = value
-		case strings.HasPrefix(arg, "-"):
+		case strings.HasPrefix(arg, "-") && arg != bootstrap.StdinSource:
 			return nil, fmt.Errorf("unknown flag: %s", arg)
 		default:
 			if parsed.source != "" {
@@ -182,6 +236,31 @@ func (a *addArgs) addHeader(raw string) error {
 	return nil
 }
 
+func (a *addArgs) addEnv(raw string) error {
+	name, value, err := parseEnvOverride(raw)
+	if err != nil {
+		return err
+	}
+	a.env = append(a.env, headerArg{name: name, value: value})
+	return nil
+}
+
+// parseEnvOverride splits NAME=VALUE. The value is kept verbatim, so it may
+// be empty or hold a ${PLACEHOLDER} that is expanded only when the server
+// starts.
+func parseEnvOverride(raw string) (string, string, error) {
+	if strings.TrimSpace(raw) == "" {
+		return "", "", fmt.Errorf("invalid --env: missing NAME=VALUE")
+	}
+
+	name, value, ok := strings.Cut(raw, "=")
+	name = strings.TrimSpace(name)
+	if !ok || name == "" || strings.ContainsAny(name, " \t") {
+		return "", "", fmt.Errorf("invalid --env: expected NAME=VALUE")
+	}
+	return name, value, nil
+}
+
 func parseHeader(raw string) (string, string, error) {
 	raw = strings.TrimSpace(raw)
 	if raw == "" {
@@ -203,18 +282,25 @@ func parseHeader(raw string) (string, string, error) {
 
 func printAddHelp(out io.Writer) {
 	fmt.Fprintln(out, "Usage:")
-	fmt.Fprintln(out, "  mcpx add <source> [--name <server>] [--header KEY=VALUE]... [--overwrite]")
+	fmt.Fprintln(out, "  mcpx add <source> [--name <server>] [--header KEY=VALUE]... [--env NAME=VALUE]... [--overwrite] [--force] [--verify | --verify-required]")
 	fmt.Fprintln(out, "")
 	fmt.Fprintln(out, "Sources:")
 	fmt.Fprintln(out, "  - install-link URL (for example cursor://.../mcp/install?... )")
 	fmt.Fprintln(out, "  - manifest URL (http/https)")
 	fmt.Fprintln(out, "  - direct MCP endpoint URL (for example https://example.com/mcp)")
 	fmt.Fprintln(out, "  - local manifest file path (JSON or TOML)")
+	fmt.Fprintln(out, "  - - to read a JSON or TOML manifest from stdin")
+	fmt.Fprintln(out, "  - npm:<package> or uvx:<package> to run a package with npx -y or uvx")
+	fmt.Fprintln(out, "  - docker:<image> to run a container image with docker run -i --rm")
 	fmt.Fprintln(out, "")
 	fmt.Fprintln(out, "Flags:")
 	fmt.Fprintln(out, "  --name <server>   Select or rename the server entry to add.")
 	fmt.Fprintln(out, "  --header KEY=VALUE")
 	fmt.Fprintln(out, "                    Set or override HTTP headers on URL-based servers.")
+	fmt.Fprintln(out, "  --env NAME=VALUE  Set or override environment variables on stdio servers.")
 	fmt.Fprintln(out, "  --overwrite       Replace existing server entry in mcpx config.")
+	fmt.Fprintln(out, "  --force           Skip the trusted_install_hosts check for this source.")
+	fmt.Fprintln(out, "  --verify          List the new server's tools after saving and report the result.")
+	fmt.Fprintln(out, "  --verify-required Like --verify, but exit non-zero when the check fails.")
 	fmt.Fprintln(out, "  --help, -h        Show this help output.")
 }
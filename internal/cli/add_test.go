@@ -7,6 +7,7 @@ import (
 	"errors"
 	"os"
 	"path/filepath"
+	"reflect"
 	"strings"
 	"testing"
 
@@ -74,6 +75,56 @@ func TestRunAddAddsServerFromManifestFile(t *testing.T) {
 	}
 }
 
+func TestRunAddKeepsManifestCacheConfig(t *testing.T) {
+	tmp := t.TempDir()
+	configHome := filepath.Join(tmp, "xdg-config")
+	manifestPath := filepath.Join(tmp, "manifest.toml")
+	manifest := `[mcpServers.github]
+command = "npx"
+default_cache_ttl = "2m"
+no_cache_tools = ["create_*"]
+
+[mcpServers.github.tools.get_me]
+cache = false
+`
+	if err := os.WriteFile(manifestPath, []byte(manifest), 0o600); err != nil {
+		t.Fatalf("WriteFile(manifest): %v", err)
+	}
+
+	t.Setenv("XDG_CONFIG_HOME", configHome)
+	t.Setenv("HOME", tmp)
+
+	oldOut := rootStdout
+	oldErr := rootStderr
+	defer func() {
+		rootStdout = oldOut
+		rootStderr = oldErr
+	}()
+	rootStdout = &bytes.Buffer{}
+	var errOut bytes.Buffer
+	rootStderr = &errOut
+
+	if code := Run([]string{"add", manifestPath}); code != ipc.ExitOK {
+		t.Fatalf("Run([add manifest]) = %d, want %d (stderr=%q)", code, ipc.ExitOK, errOut.String())
+	}
+
+	cfgPath := filepath.Join(configHome, "mcpx", "config.toml")
+	edited, err := config.LoadForEditFrom(cfgPath)
+	if err != nil {
+		t.Fatalf("LoadForEditFrom(saved config) error = %v", err)
+	}
+	saved := edited.Servers["github"]
+	if saved.DefaultCacheTTL != "2m" {
+		t.Fatalf("saved default_cache_ttl = %q, want %q", saved.DefaultCacheTTL, "2m")
+	}
+	if len(saved.NoCacheTools) != 1 || saved.NoCacheTools[0] != "create_*" {
+		t.Fatalf("saved no_cache_tools = %v, want [create_*]", saved.NoCacheTools)
+	}
+	if c := saved.Tools["get_me"].Cache; c == nil || *c {
+		t.Fatalf("saved tools.get_me.cache = %v, want false", c)
+	}
+}
+
 func TestRunAddAllowsExistingEnvPlaceholderServers(t *testing.T) {
 	tmp := t.TempDir()
 	configHome := filepath.Join(tmp, "xdg-config")
@@ -380,6 +431,66 @@ func TestRunAddAppliesHeaderOverridesCaseInsensitively(t *testing.T) {
 	}
 }
 
+func TestRunAddAppliesEnvOverridesAndKeepsPlaceholders(t *testing.T) {
+	tmp := t.TempDir()
+	configHome := filepath.Join(tmp, "xdg-config")
+	manifestPath := filepath.Join(tmp, "manifest.json")
+	if err := os.WriteFile(manifestPath, []byte(`{"mcpServers":{"github":{"command":"go","args":["version"],"env":{"github_token":"old","LOG_LEVEL":"info"}}}}`), 0o600); err != nil {
+		t.Fatalf("WriteFile(manifest): %v", err)
+	}
+	t.Setenv("XDG_CONFIG_HOME", configHome)
+	t.Setenv("HOME", tmp)
+	t.Setenv("GITHUB_TOKEN", "secret")
+
+	oldOut := rootStdout
+	oldErr := rootStderr
+	defer func() {
+		rootStdout = oldOut
+		rootStderr = oldErr
+	}()
+	var out bytes.Buffer
+	var errOut bytes.Buffer
+	rootStdout = &out
+	rootStderr = &errOut
+
+	code := Run([]string{"add", manifestPath, "--env", "GITHUB_TOKEN=${GITHUB_TOKEN}", "--env=GH_HOST=github.example.com"})
+	if code != ipc.ExitOK {
+		t.Fatalf("Run([add manifest --env]) = %d, want %d (stderr=%q)", code, ipc.ExitOK, errOut.String())
+	}
+
+	edited, err := config.LoadForEditFrom(filepath.Join(configHome, "mcpx", "config.toml"))
+	if err != nil {
+		t.Fatalf("LoadForEditFrom(saved config) error = %v", err)
+	}
+	want := map[string]string{
+		"GITHUB_TOKEN": "${GITHUB_TOKEN}",
+		"GH_HOST":      "github.example.com",
+		"LOG_LEVEL":    "info",
+	}
+	if got := edited.Servers["github"].Env; !reflect.DeepEqual(got, want) {
+		t.Fatalf("saved env = %#v, want %#v", got, want)
+	}
+}
+
+func TestRunAddRejectsEnvForURLServers(t *testing.T) {
+	tmp := t.TempDir()
+	manifestPath := filepath.Join(tmp, "manifest.json")
+	if err := os.WriteFile(manifestPath, []byte(`{"mcpServers":{"deepwiki":{"url":"https://mcp.deepwiki.com/mcp"}}}`), 0o600); err != nil {
+		t.Fatalf("WriteFile(manifest): %v", err)
+	}
+	t.Setenv("XDG_CONFIG_HOME", filepath.Join(tmp, "xdg-config"))
+	t.Setenv("HOME", tmp)
+
+	var errOut bytes.Buffer
+	code := runAddCommand([]string{manifestPath, "--env", "TOKEN=x"}, &bytes.Buffer{}, &errOut)
+	if code != ipc.ExitUsageErr {
+		t.Fatalf("runAddCommand() = %d, want %d", code, ipc.ExitUsageErr)
+	}
+	if !strings.Contains(errOut.String(), "--env can only be used with stdio servers") {
+		t.Fatalf("stderr = %q, want stdio-only message", errOut.String())
+	}
+}
+
 func TestClassifyResolveErrorExitCodeReturnsInternalForSourceAccessErrors(t *testing.T) {
 	_, err := bootstrap.Resolve(context.Background(), "manifest.json", bootstrap.ResolveOptions{
 		ReadFile: func(string) ([]byte, error) {
@@ -453,6 +564,37 @@ func TestParseAddArgsParsesHeaderFlags(t *testing.T) {
 	}
 }
 
+func TestParseAddArgsParsesEnvFlags(t *testing.T) {
+	parsed, err := parseAddArgs([]string{"manifest.json", "--env", "TOKEN=${TOKEN}", "--env=EMPTY=", "--env", "URL=a=b"})
+	if err != nil {
+		t.Fatalf("parseAddArgs() error = %v", err)
+	}
+	want := []headerArg{{name: "TOKEN", value: "${TOKEN}"}, {name: "EMPTY", value: ""}, {name: "URL", value: "a=b"}}
+	if !reflect.DeepEqual(parsed.env, want) {
+		t.Fatalf("parsed.env = %#v, want %#v", parsed.env, want)
+	}
+}
+
+func TestParseAddArgsRejectsInvalidEnvFlag(t *testing.T) {
+	tests := [][]string{
+		{"manifest.json", "--env"},
+		{"manifest.json", "--env", ""},
+		{"manifest.json", "--env", "TOKEN"},
+		{"manifest.json", "--env", "=value"},
+		{"manifest.json", "--env=MY TOKEN=x"},
+	}
+
+	for _, args := range tests {
+		_, err := parseAddArgs(args)
+		if err == nil {
+			t.Fatalf("parseAddArgs(%v) error = nil, want non-nil", args)
+		}
+		if !strings.Contains(err.Error(), "invalid --env") {
+			t.Fatalf("parseAddArgs(%v) error = %q, want invalid --env message", args, err.Error())
+		}
+	}
+}
+
 func TestParseAddArgsRejectsInvalidHeaderFlag(t *testing.T) {
 	tests := [][]string{
 		{"https://mcp.deepwiki.com/mcp", "--header"},
@@ -488,3 +630,148 @@ func TestPrintAddHelpIncludesHeaderAndOverwriteGuidance(t *testing.T) {
 		t.Fatalf("help output missing overwrite guidance: %q", help)
 	}
 }
+
+func TestRunAddReadsManifestFromStdin(t *testing.T) {
+	tmp := t.TempDir()
+	configHome := filepath.Join(tmp, "xdg-config")
+	t.Setenv("XDG_CONFIG_HOME", configHome)
+	t.Setenv("HOME", tmp)
+
+	oldOut, oldErr, oldStdin := rootStdout, rootStderr, addStdin
+	defer func() {
+		rootStdout, rootStderr, addStdin = oldOut, oldErr, oldStdin
+	}()
+	var out, errOut bytes.Buffer
+	rootStdout, rootStderr = &out, &errOut
+
+	addStdin = strings.NewReader(`{"command":"npx","args":["-y","foo-mcp"]}`)
+	if code := Run([]string{"add", "-"}); code != ipc.ExitUsageErr {
+		t.Fatalf("Run([add -]) without --name = %d, want %d", code, ipc.ExitUsageErr)
+	}
+
+	addStdin = strings.NewReader(`{"command":"npx","args":["-y","foo-mcp"]}`)
+	code := Run([]string{"add", "-", "--name", "foo"})
+	if code != ipc.ExitOK {
+		t.Fatalf("Run([add - --name foo]) = %d, want %d (stderr=%q)", code, ipc.ExitOK, errOut.String())
+	}
+	if !bytes.Contains(out.Bytes(), []byte(`Added server "foo"`)) {
+		t.Fatalf("stdout = %q, want add confirmation", out.String())
+	}
+
+	edited, err := config.LoadForEditFrom(filepath.Join(configHome, "mcpx", "config.toml"))
+	if err != nil {
+		t.Fatalf("LoadForEditFrom(saved config) error = %v", err)
+	}
+	if got := edited.Servers["foo"]; got.Command != "npx" || len(got.Args) != 2 || got.Args[1] != "foo-mcp" {
+		t.Fatalf("saved server = %#v, want npx -y foo-mcp", got)
+	}
+}
+
+func TestRunAddEnforcesTrustedInstallHosts(t *testing.T) {
+	tmp := t.TempDir()
+	configHome := filepath.Join(tmp, "xdg-config")
+	configDir := filepath.Join(configHome, "mcpx")
+	if err := os.MkdirAll(configDir, 0o700); err != nil {
+		t.Fatalf("MkdirAll(config dir): %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(configDir, "config.toml"), []byte("trusted_install_hosts = [\"*.example.com\"]\n"), 0o600); err != nil {
+		t.Fatalf("WriteFile(config): %v", err)
+	}
+	t.Setenv("XDG_CONFIG_HOME", configHome)
+	t.Setenv("HOME", tmp)
+
+	oldOut, oldErr := rootStdout, rootStderr
+	defer func() {
+		rootStdout, rootStderr = oldOut, oldErr
+	}()
+	var out, errOut bytes.Buffer
+	rootStdout, rootStderr = &out, &errOut
+
+	raw := `{"url":"https://mcp.devin.ai/mcp"}`
+	source := "cursor://anysphere.cursor-deeplink/mcp/install?name=deepwiki&config=" + base64.StdEncoding.EncodeToString([]byte(raw))
+
+	if code := Run([]string{"add", source}); code != ipc.ExitUsageErr {
+		t.Fatalf("Run([add untrusted]) = %d, want %d", code, ipc.ExitUsageErr)
+	}
+	if !strings.Contains(errOut.String(), "trusted_install_hosts") || !strings.Contains(errOut.String(), "--force") {
+		t.Fatalf("stderr = %q, want allowlist refusal with --force hint", errOut.String())
+	}
+
+	errOut.Reset()
+	if code := Run([]string{"add", source, "--force"}); code != ipc.ExitOK {
+		t.Fatalf("Run([add untrusted --force]) = %d, want %d (stderr=%q)", code, ipc.ExitOK, errOut.String())
+	}
+
+	edited, err := config.LoadForEditFrom(filepath.Join(configDir, "config.toml"))
+	if err != nil {
+		t.Fatalf("LoadForEditFrom(saved config) error = %v", err)
+	}
+	if edited.Servers["deepwiki"].URL != "https://mcp.devin.ai/mcp" {
+		t.Fatalf("saved server = %#v, want deepwiki URL", edited.Servers["deepwiki"])
+	}
+	if len(edited.TrustedInstallHosts) != 1 {
+		t.Fatalf("saved trusted_install_hosts = %#v, want preserved", edited.TrustedInstallHosts)
+	}
+}
+
+func TestRunAddVerifyReportsToolCountAndFailures(t *testing.T) {
+	tmp := t.TempDir()
+	t.Setenv("XDG_CONFIG_HOME", filepath.Join(tmp, "xdg-config"))
+	t.Setenv("HOME", tmp)
+
+	oldOut, oldErr, oldStdin := rootStdout, rootStderr, addStdin
+	oldSpawn, oldNewClient := spawnOrConnectFn, newDaemonClient
+	defer func() {
+		rootStdout, rootStderr, addStdin = oldOut, oldErr, oldStdin
+		spawnOrConnectFn, newDaemonClient = oldSpawn, oldNewClient
+	}()
+	var out, errOut bytes.Buffer
+	rootStdout, rootStderr = &out, &errOut
+
+	spawnOrConnectFn = func() (string, error) { return "nonce", nil }
+	var resp *ipc.Response
+	var gotReq *ipc.Request
+	newDaemonClient = func(string, string) daemonRequester {
+		return stubDaemonClient{sendFn: func(req *ipc.Request) (*ipc.Response, error) {
+			gotReq = req
+			return resp, nil
+		}}
+	}
+	add := func(flags ...string) int {
+		addStdin = strings.NewReader(`{"url":"https://example.com/mcp"}`)
+		return Run(append([]string{"add", "-", "--name", "remote", "--overwrite"}, flags...))
+	}
+
+	resp = &ipc.Response{Content: []byte(`[{"name":"search"},{"name":"fetch"}]`)}
+	if code := add("--verify"); code != ipc.ExitOK {
+		t.Fatalf("add --verify = %d, want %d (stderr=%q)", code, ipc.ExitOK, errOut.String())
+	}
+	if gotReq == nil || gotReq.Type != "list_tools" || gotReq.Server != "remote" {
+		t.Fatalf("verify request = %#v, want list_tools for remote", gotReq)
+	}
+	if !strings.Contains(out.String(), `Verified server "remote": 2 tool(s) available`) {
+		t.Fatalf("stdout = %q, want verify success", out.String())
+	}
+
+	resp = &ipc.Response{ExitCode: ipc.ExitInternal, Stderr: "401 Unauthorized"}
+	errOut.Reset()
+	if code := add("--verify"); code != ipc.ExitOK {
+		t.Fatalf("add --verify with failing server = %d, want %d", code, ipc.ExitOK)
+	}
+	if !strings.Contains(errOut.String(), "warning") || !strings.Contains(errOut.String(), "401 Unauthorized") {
+		t.Fatalf("stderr = %q, want verify warning", errOut.String())
+	}
+
+	errOut.Reset()
+	if code := add("--verify-required"); code != ipc.ExitInternal {
+		t.Fatalf("add --verify-required with failing server = %d, want %d", code, ipc.ExitInternal)
+	}
+	if strings.Contains(errOut.String(), "warning") || !strings.Contains(errOut.String(), "401 Unauthorized") {
+		t.Fatalf("stderr = %q, want verify failure", errOut.String())
+	}
+
+	gotReq = nil
+	if code := add(); code != ipc.ExitOK || gotReq != nil {
+		t.Fatalf("add without --verify = %d, request = %#v; want no daemon request", code, gotReq)
+	}
+}
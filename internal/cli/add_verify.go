@@ -0,0 +1,57 @@
+package cli
+
+import (
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/lydakis/mcpx/internal/ipc"
+)
+
+// verifyAddedServer lists the tools of a server that was just saved,
+// starting the daemon if needed, and reports how many tools it has. A
+// failure is a warning unless required is set, in which case its exit code
+// is returned; the config stays written either way.
+func verifyAddedServer(name string, required bool, stdout, stderr io.Writer) int {
+	count, code, err := countServerTools(name)
+	if err == nil {
+		fmt.Fprintf(stdout, "Verified server %q: %d tool(s) available\n", name, count)
+		return ipc.ExitOK
+	}
+
+	if !required {
+		fmt.Fprintf(stderr, "mcpx: add: warning: verifying server %q: %v\n", name, err)
+		return ipc.ExitOK
+	}
+	fmt.Fprintf(stderr, "mcpx: add: verifying server %q: %v\n", name, err)
+	return code
+}
+
+func countServerTools(server string) (int, int, error) {
+	nonce, err := spawnOrConnectFn()
+	if err != nil {
+		return 0, ipc.ExitInternal, fmt.Errorf("connecting to daemon: %w", err)
+	}
+
+	client := newDaemonClient(ipc.SocketPath(), nonce)
+	resp, err := client.Send(&ipc.Request{
+		Type:   "list_tools",
+		Server: server,
+		CWD:    callerWorkingDirectory(),
+	})
+	if err != nil {
+		return 0, ipc.ExitInternal, fmt.Errorf("listing tools: %w", err)
+	}
+	if resp.ExitCode != ipc.ExitOK {
+		if stderr := strings.TrimSpace(resp.Stderr); stderr != "" {
+			return 0, resp.ExitCode, fmt.Errorf("%s", stderr)
+		}
+		return 0, resp.ExitCode, fmt.Errorf("listing tools failed with exit %d", resp.ExitCode)
+	}
+
+	entries, err := decodeToolListPayload(resp.Content)
+	if err != nil {
+		return 0, ipc.ExitInternal, fmt.Errorf("decoding tool list payload: %w", err)
+	}
+	return len(entries), ipc.ExitOK, nil
+}
@@ -0,0 +1,51 @@
+package cli
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/lydakis/mcpx/internal/config"
+)
+
+func parseTemplateVar(raw string) (string, string, error) {
+	name, value, ok := strings.Cut(raw, "=")
+	name = strings.TrimSpace(name)
+	if !ok || name == "" {
+		return "", "", fmt.Errorf("--var expects <name>=<value>, got %q", raw)
+	}
+	return name, value, nil
+}
+
+// renderArgsTemplate reads a JSON args template, replaces ${NAME}
+// placeholders from vars (first) or lookupEnv, and parses the result as the
+// call's argument object. Substitution is textual, so a placeholder can fill
+// a number or a whole JSON value, and string values must be quoted in the
+// template. Unresolved placeholders are an error.
+func renderArgsTemplate(path string, vars map[string]string, lookupEnv func(string) (string, bool)) (map[string]any, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("reading args template: %w", err)
+	}
+
+	rendered, missing := config.ExpandPlaceholders(string(data), func(name string) (string, bool) {
+		if value, ok := vars[name]; ok {
+			return value, true
+		}
+		return lookupEnv(name)
+	})
+	if len(missing) > 0 {
+		return nil, fmt.Errorf("args template %s: unresolved placeholder ${%s}; set it in the environment or pass --var %s=<value>", path, missing[0], missing[0])
+	}
+
+	var decoded any
+	if err := json.Unmarshal([]byte(rendered), &decoded); err != nil {
+		return nil, fmt.Errorf("args template %s: invalid JSON after substitution: %w", path, err)
+	}
+	obj, ok := decoded.(map[string]any)
+	if !ok {
+		return nil, fmt.Errorf("args template %s: must be a JSON object", path)
+	}
+	return obj, nil
+}
@@ -0,0 +1,70 @@
+package cli
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func writeArgsTemplate(t *testing.T, content string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "args.json")
+	if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+		t.Fatalf("WriteFile() error = %v", err)
+	}
+	return path
+}
+
+func TestParseToolCallArgsArgsTemplateSubstitutesVarsAndEnv(t *testing.T) {
+	t.Setenv("MCPX_TEST_REPO", "mcpx")
+	t.Setenv("MCPX_TEST_LIMIT", "10")
+	path := writeArgsTemplate(t, `{"owner":"${OWNER}","repo":"${MCPX_TEST_REPO}","limit":${MCPX_TEST_LIMIT},"state":"open"}`)
+
+	parsed, err := parseToolCallArgs([]string{
+		"--args-template-file", path,
+		"--var", "OWNER=lydakis",
+		"--var=MCPX_TEST_LIMIT=5",
+		"--state=closed",
+	}, bytes.NewBuffer(nil), true, outputModeText)
+	if err != nil {
+		t.Fatalf("parseToolCallArgs() error = %v", err)
+	}
+
+	want := map[string]any{
+		"owner": "lydakis",
+		"repo":  "mcpx",
+		"limit": float64(5),
+		"state": "closed",
+	}
+	if !reflect.DeepEqual(parsed.toolArgs, want) {
+		t.Fatalf("toolArgs = %#v, want %#v", parsed.toolArgs, want)
+	}
+}
+
+func TestParseToolCallArgsArgsTemplateRejectsInvalidTemplates(t *testing.T) {
+	cases := map[string]struct {
+		template string
+		args     []string
+		want     string
+	}{
+		"unresolved placeholder": {template: `{"owner":"${MCPX_TEST_UNSET_OWNER}"}`, want: "unresolved placeholder ${MCPX_TEST_UNSET_OWNER}"},
+		"invalid JSON":           {template: `{"title":"${TITLE}"}`, args: []string{"--var", `TITLE=say "hi"`}, want: "invalid JSON after substitution"},
+		"not an object":          {template: `["a"]`, want: "must be a JSON object"},
+		"positional JSON":        {template: `{}`, args: []string{`{"a":1}`}, want: "cannot be combined"},
+	}
+	for name, tc := range cases {
+		path := writeArgsTemplate(t, tc.template)
+		args := append([]string{"--args-template-file=" + path}, tc.args...)
+		_, err := parseToolCallArgs(args, bytes.NewBuffer(nil), true, outputModeText)
+		if err == nil || !strings.Contains(err.Error(), tc.want) {
+			t.Errorf("%s: parseToolCallArgs() error = %v, want %q", name, err, tc.want)
+		}
+	}
+
+	if _, err := parseToolCallArgs([]string{"--var", "A=1"}, bytes.NewBuffer(nil), true, outputModeText); err == nil {
+		t.Fatal("parseToolCallArgs(--var without template) error = nil, want non-nil")
+	}
+}
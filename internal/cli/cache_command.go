@@ -0,0 +1,163 @@
+package cli
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"text/tabwriter"
+
+	"github.com/lydakis/mcpx/internal/config"
+	"github.com/lydakis/mcpx/internal/ipc"
+)
+
+func maybeHandleCacheCommand(args []string, cfg *config.Config, stdout, stderr io.Writer) (bool, int) {
+	if len(args) == 0 || args[0] != "cache" {
+		return false, 0
+	}
+
+	if cfg != nil {
+		if _, ok := cfg.Servers["cache"]; ok {
+			return false, 0
+		}
+	}
+
+	return true, runCacheCommand(args[1:], stdout, stderr)
+}
+
+func runCacheCommand(args []string, stdout, stderr io.Writer) int {
+	if len(args) == 0 || args[0] == "help" || isHelpFlag(args[0]) {
+		printCacheHelp(stdout)
+		return ipc.ExitOK
+	}
+
+	switch args[0] {
+	case "clear":
+		return runCacheClearCommand(args[1:], stdout, stderr)
+	case "stats":
+		return runCacheStatsCommand(args[1:], stdout, stderr)
+	default:
+		fmt.Fprintf(stderr, "mcpx: unknown cache command: %s\n", args[0])
+		printCacheHelp(stderr)
+		return ipc.ExitUsageErr
+	}
+}
+
+// runCacheClearCommand removes cached tool responses: all of them, one
+// server's, or one tool's. The daemon owns the cache, so it does the
+// removal.
+func runCacheClearCommand(args []string, stdout, stderr io.Writer) int {
+	for _, arg := range args {
+		if isHelpFlag(arg) {
+			printCacheHelp(stdout)
+			return ipc.ExitOK
+		}
+	}
+	if len(args) > 2 {
+		fmt.Fprintf(stderr, "mcpx: cache clear: unexpected argument: %s\n", args[2])
+		return ipc.ExitUsageErr
+	}
+	req := &ipc.Request{Type: "cache_clear"}
+	if len(args) > 0 {
+		req.Server = args[0]
+	}
+	if len(args) > 1 {
+		req.Tool = args[1]
+	}
+
+	resp, code := sendCacheRequest(req, stderr)
+	if resp == nil {
+		return code
+	}
+	stdout.Write(resp.Content) //nolint:errcheck
+	return ipc.ExitOK
+}
+
+// cacheStats mirrors the daemon's cache_stats payload.
+type cacheStats struct {
+	Entries int     `json:"entries"`
+	Bytes   int64   `json:"bytes"`
+	Hits    uint64  `json:"hits"`
+	Misses  uint64  `json:"misses"`
+	Stores  uint64  `json:"stores"`
+	HitRate float64 `json:"hit_rate"`
+}
+
+// runCacheStatsCommand prints the cache size on disk and the daemon's hit,
+// miss, and store counts since it started.
+func runCacheStatsCommand(args []string, stdout, stderr io.Writer) int {
+	jsonOut := false
+	for _, arg := range args {
+		switch {
+		case isHelpFlag(arg):
+			printCacheHelp(stdout)
+			return ipc.ExitOK
+		case arg == "--json":
+			jsonOut = true
+		default:
+			fmt.Fprintf(stderr, "mcpx: cache stats: unexpected argument: %s\n", arg)
+			return ipc.ExitUsageErr
+		}
+	}
+
+	resp, code := sendCacheRequest(&ipc.Request{Type: "cache_stats"}, stderr)
+	if resp == nil {
+		return code
+	}
+	var stats cacheStats
+	if err := json.Unmarshal(resp.Content, &stats); err != nil {
+		fmt.Fprintf(stderr, "mcpx: decoding cache stats: %v\n", err)
+		return ipc.ExitInternal
+	}
+
+	if jsonOut {
+		if err := writeJSONLine(stdout, stats); err != nil {
+			fmt.Fprintf(stderr, "mcpx: %v\n", err)
+			return ipc.ExitInternal
+		}
+		return ipc.ExitOK
+	}
+	tw := tabwriter.NewWriter(stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintf(tw, "entries\t%d\n", stats.Entries)
+	fmt.Fprintf(tw, "bytes\t%d\n", stats.Bytes)
+	fmt.Fprintf(tw, "hits\t%d\n", stats.Hits)
+	fmt.Fprintf(tw, "misses\t%d\n", stats.Misses)
+	fmt.Fprintf(tw, "stores\t%d\n", stats.Stores)
+	fmt.Fprintf(tw, "hit rate\t%.1f%%\n", stats.HitRate*100)
+	_ = tw.Flush()
+	return ipc.ExitOK
+}
+
+// sendCacheRequest sends a cache request to the daemon. It returns a nil
+// response, having reported the error, when the request failed.
+func sendCacheRequest(req *ipc.Request, stderr io.Writer) (*ipc.Response, int) {
+	nonce, err := spawnOrConnectFn()
+	if err != nil {
+		fmt.Fprintf(stderr, "mcpx: %v\n", err)
+		return nil, ipc.ExitInternal
+	}
+	client := newDaemonClient(ipc.SocketPath(), nonce)
+	resp, err := client.Send(req)
+	if err != nil {
+		fmt.Fprintf(stderr, "mcpx: %v\n", err)
+		return nil, ipc.ExitInternal
+	}
+	if resp.ExitCode != ipc.ExitOK {
+		if resp.Stderr != "" {
+			fmt.Fprintf(stderr, "mcpx: %s\n", resp.Stderr)
+		}
+		return nil, resp.ExitCode
+	}
+	return resp, ipc.ExitOK
+}
+
+func printCacheHelp(out io.Writer) {
+	fmt.Fprintln(out, "Usage:")
+	fmt.Fprintln(out, "  mcpx cache clear [<server> [<tool>]]")
+	fmt.Fprintln(out, "  mcpx cache stats [--json]")
+	fmt.Fprintln(out, "")
+	fmt.Fprintln(out, "Commands:")
+	fmt.Fprintln(out, "  clear      Remove cached tool responses: all of them, one server's, or one tool's.")
+	fmt.Fprintln(out, "             Prints how many entries were removed.")
+	fmt.Fprintln(out, "  stats      Show cache entries and bytes on disk, and hits, misses, and stores")
+	fmt.Fprintln(out, "             since the daemon started.")
+}
@@ -0,0 +1,87 @@
+package cli
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/lydakis/mcpx/internal/config"
+	"github.com/lydakis/mcpx/internal/ipc"
+)
+
+func TestRunCacheClearSendsScopedRequest(t *testing.T) {
+	tests := []struct {
+		args         []string
+		server, tool string
+	}{
+		{args: []string{"clear"}},
+		{args: []string{"clear", "github"}, server: "github"},
+		{args: []string{"clear", "github", "search"}, server: "github", tool: "search"},
+	}
+	for _, tt := range tests {
+		sent := stubDaemonReload(t, &ipc.Response{Content: []byte("removed 2 cached entries\n")})
+
+		var stdout, stderr bytes.Buffer
+		if code := runCacheCommand(tt.args, &stdout, &stderr); code != ipc.ExitOK {
+			t.Fatalf("runCacheCommand(%v) = %d, want %d (stderr=%q)", tt.args, code, ipc.ExitOK, stderr.String())
+		}
+		if len(*sent) != 1 {
+			t.Fatalf("sent requests = %#v, want one", *sent)
+		}
+		req := (*sent)[0]
+		if req.Type != "cache_clear" || req.Server != tt.server || req.Tool != tt.tool {
+			t.Fatalf("request = %+v, want cache_clear %q %q", req, tt.server, tt.tool)
+		}
+		if stdout.String() != "removed 2 cached entries\n" {
+			t.Fatalf("stdout = %q, want daemon count", stdout.String())
+		}
+	}
+}
+
+func TestRunCacheCommandRejectsExtraArgsAndDefersToServer(t *testing.T) {
+	sent := stubDaemonReload(t, &ipc.Response{})
+
+	var stdout, stderr bytes.Buffer
+	if code := runCacheCommand([]string{"clear", "a", "b", "c"}, &stdout, &stderr); code != ipc.ExitUsageErr {
+		t.Fatalf("runCacheCommand(extra args) = %d, want %d", code, ipc.ExitUsageErr)
+	}
+	if code := runCacheCommand([]string{"purge"}, &stdout, &stderr); code != ipc.ExitUsageErr {
+		t.Fatalf("runCacheCommand(purge) = %d, want %d", code, ipc.ExitUsageErr)
+	}
+	if len(*sent) != 0 {
+		t.Fatalf("sent requests = %#v, want none", *sent)
+	}
+
+	cfg := &config.Config{Servers: map[string]config.ServerConfig{"cache": {Command: "cache-mcp"}}}
+	if handled, _ := maybeHandleCacheCommand([]string{"cache", "clear"}, cfg, &stdout, &stderr); handled {
+		t.Fatal("maybeHandleCacheCommand() handled = true, want a configured cache server to win")
+	}
+}
+
+func TestRunCacheStatsPrintsTableAndJSON(t *testing.T) {
+	content := []byte(`{"entries":2,"bytes":512,"hits":3,"misses":1,"stores":2,"hit_rate":0.75}`)
+	sent := stubDaemonReload(t, &ipc.Response{Content: content})
+
+	var stdout, stderr bytes.Buffer
+	if code := runCacheCommand([]string{"stats"}, &stdout, &stderr); code != ipc.ExitOK {
+		t.Fatalf("runCacheCommand(stats) = %d, want %d (stderr=%q)", code, ipc.ExitOK, stderr.String())
+	}
+	if len(*sent) != 1 || (*sent)[0].Type != "cache_stats" {
+		t.Fatalf("sent requests = %#v, want one cache_stats request", *sent)
+	}
+	want := "entries   2\nbytes     512\nhits      3\nmisses    1\nstores    2\nhit rate  75.0%\n"
+	if stdout.String() != want {
+		t.Fatalf("stdout = %q, want %q", stdout.String(), want)
+	}
+
+	stdout.Reset()
+	if code := runCacheCommand([]string{"stats", "--json"}, &stdout, &stderr); code != ipc.ExitOK {
+		t.Fatalf("runCacheCommand(stats --json) = %d, want %d", code, ipc.ExitOK)
+	}
+	if stdout.String() != string(content)+"\n" {
+		t.Fatalf("stdout = %q, want %s", stdout.String(), content)
+	}
+
+	if code := runCacheCommand([]string{"stats", "--verbose"}, &stdout, &stderr); code != ipc.ExitUsageErr {
+		t.Fatalf("runCacheCommand(stats --verbose) = %d, want %d", code, ipc.ExitUsageErr)
+	}
+}
@@ -0,0 +1,44 @@
+package cli
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/lydakis/mcpx/internal/httpheaders"
+)
+
+// readHeadersFile loads "Name: Value" lines for --headers-from-file. Blank
+// lines and lines starting with # are skipped. A relative path is read from
+// the caller's working directory.
+func readHeadersFile(path string) (map[string]string, error) {
+	path = strings.TrimSpace(path)
+	if path == "" {
+		return nil, fmt.Errorf("missing value for --headers-from-file")
+	}
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("--headers-from-file: %w", err)
+	}
+	defer file.Close() //nolint:errcheck
+
+	var headers map[string]string
+	scanner := bufio.NewScanner(file)
+	for lineNo := 1; scanner.Scan(); lineNo++ {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		name, value, ok := strings.Cut(line, ":")
+		name, value = strings.TrimSpace(name), strings.TrimSpace(value)
+		if !ok || name == "" || strings.ContainsAny(name, " \t") {
+			return nil, fmt.Errorf("--headers-from-file %s:%d: expected \"Name: Value\", got %q", path, lineNo, line)
+		}
+		headers = httpheaders.Set(headers, name, value)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("--headers-from-file %s: %w", path, err)
+	}
+	return headers, nil
+}
@@ -0,0 +1,61 @@
+package cli
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/lydakis/mcpx/internal/ipc"
+)
+
+func TestCallToolMergesHeadersFromFileAndFlags(t *testing.T) {
+	dir := t.TempDir()
+	raw := "# per-call headers\nAuthorization: Bearer from-file\nX-Trace-Id: abc:123\n\nX-Team: core\n"
+	if err := os.WriteFile(filepath.Join(dir, "headers.txt"), []byte(raw), 0o600); err != nil {
+		t.Fatalf("WriteFile(headers): %v", err)
+	}
+	t.Chdir(dir)
+
+	oldOut, oldErr := rootStdout, rootStderr
+	defer func() { rootStdout, rootStderr = oldOut, oldErr }()
+	var stdout, stderr bytes.Buffer
+	rootStdout, rootStderr = &stdout, &stderr
+
+	var got *ipc.Request
+	client := stubDaemonClient{sendFn: func(req *ipc.Request) (*ipc.Response, error) {
+		got = req
+		return &ipc.Response{Content: []byte("ok\n")}, nil
+	}}
+
+	args := []string{"--header", "authorization=Bearer from-flag", "--headers-from-file", "headers.txt", "--query=mcp"}
+	if code := callTool(client, "api", "search", args, dir, false); code != ipc.ExitOK {
+		t.Fatalf("callTool() = %d, want %d (stderr=%q)", code, ipc.ExitOK, stderr.String())
+	}
+
+	want := map[string]string{
+		"authorization": "Bearer from-flag",
+		"X-Trace-Id":    "abc:123",
+		"X-Team":        "core",
+	}
+	if got == nil || !reflect.DeepEqual(got.Headers, want) {
+		t.Fatalf("request headers = %#v, want %#v", got.Headers, want)
+	}
+	if string(got.Args) != `{"query":"mcp"}` {
+		t.Fatalf("request args = %s, want header flags kept out of tool args", got.Args)
+	}
+}
+
+func TestReadHeadersFileRejectsMalformedLines(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "headers.txt")
+	if err := os.WriteFile(path, []byte("X-Ok: 1\nnot a header\n"), 0o600); err != nil {
+		t.Fatalf("WriteFile(headers): %v", err)
+	}
+	if _, err := readHeadersFile(path); err == nil {
+		t.Fatal("readHeadersFile(malformed) error = nil, want non-nil")
+	}
+	if _, err := parseToolCallArgs([]string{"--headers-from-file", filepath.Join(t.TempDir(), "missing")}, bytes.NewBuffer(nil), true, outputModeText); err == nil {
+		t.Fatal("parseToolCallArgs(missing headers file) error = nil, want non-nil")
+	}
+}
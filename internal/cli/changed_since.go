@@ -0,0 +1,52 @@
+package cli
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"time"
+)
+
+// parseChangedSince accepts an RFC3339 timestamp, a local date or date-time
+// (2006-01-02, 2006-01-02T15:04:05), or a duration meaning "that long ago".
+func parseChangedSince(raw string, now time.Time) (time.Time, error) {
+	raw = strings.TrimSpace(raw)
+	if raw == "" {
+		return time.Time{}, fmt.Errorf("missing value for --changed-since")
+	}
+	if t, err := time.Parse(time.RFC3339, raw); err == nil {
+		return t, nil
+	}
+	for _, layout := range []string{"2006-01-02T15:04:05", "2006-01-02"} {
+		if t, err := time.ParseInLocation(layout, raw, time.Local); err == nil {
+			return t, nil
+		}
+	}
+	if d, err := time.ParseDuration(raw); err == nil && d > 0 {
+		return now.Add(-d), nil
+	}
+	return time.Time{}, fmt.Errorf("invalid --changed-since %q: expected RFC3339 time, YYYY-MM-DD, or a duration like 24h", raw)
+}
+
+// filterServersChangedSince keeps entries whose origin file was modified
+// after since and records that mtime on them. Entries without an origin file
+// (env, runtime-only) never match.
+func filterServersChangedSince(entries []serverListEntry, since time.Time) []serverListEntry {
+	out := make([]serverListEntry, 0, len(entries))
+	for _, entry := range entries {
+		path := strings.TrimSpace(entry.Origin.Path)
+		if path == "" {
+			continue
+		}
+		info, err := os.Stat(path)
+		if err != nil {
+			continue
+		}
+		if !info.ModTime().After(since) {
+			continue
+		}
+		entry.MTime = info.ModTime().UTC().Format(time.RFC3339)
+		out = append(out, entry)
+	}
+	return out
+}
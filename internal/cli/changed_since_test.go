@@ -0,0 +1,106 @@
+package cli
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/lydakis/mcpx/internal/ipc"
+)
+
+func TestParseChangedSinceAcceptsTimesDatesAndDurations(t *testing.T) {
+	now := time.Date(2026, 5, 10, 12, 0, 0, 0, time.UTC)
+
+	got, err := parseChangedSince("2026-05-01T08:00:00Z", now)
+	if err != nil || !got.Equal(time.Date(2026, 5, 1, 8, 0, 0, 0, time.UTC)) {
+		t.Fatalf("parseChangedSince(RFC3339) = %v, %v", got, err)
+	}
+	got, err = parseChangedSince("2026-05-01", now)
+	if err != nil || !got.Equal(time.Date(2026, 5, 1, 0, 0, 0, 0, time.Local)) {
+		t.Fatalf("parseChangedSince(date) = %v, %v", got, err)
+	}
+	got, err = parseChangedSince("36h", now)
+	if err != nil || !got.Equal(now.Add(-36*time.Hour)) {
+		t.Fatalf("parseChangedSince(duration) = %v, %v", got, err)
+	}
+	if _, err := parseChangedSince("yesterday", now); err == nil {
+		t.Fatal("parseChangedSince(yesterday) error = nil, want non-nil")
+	}
+}
+
+func TestParseRootServerListArgsSupportsChangedSince(t *testing.T) {
+	parsed, handled, err := parseRootServerListArgs([]string{"--changed-since", "2026-05-01T08:00:00Z", "--json"}, outputModeText)
+	if err != nil || !handled {
+		t.Fatalf("parseRootServerListArgs() handled=%v err=%v, want handled", handled, err)
+	}
+	if parsed.changedSince == nil || !parsed.changedSince.Equal(time.Date(2026, 5, 1, 8, 0, 0, 0, time.UTC)) {
+		t.Fatalf("changedSince = %v, want 2026-05-01T08:00:00Z", parsed.changedSince)
+	}
+
+	if _, handled, err := parseRootServerListArgs([]string{"--changed-since=soon"}, outputModeText); !handled || err == nil {
+		t.Fatalf("parseRootServerListArgs(--changed-since=soon) handled=%v err=%v, want handled with error", handled, err)
+	}
+}
+
+func TestListServersChangedSinceFiltersByOriginMTime(t *testing.T) {
+	oldOut, oldErr := rootStdout, rootStderr
+	defer func() {
+		rootStdout, rootStderr = oldOut, oldErr
+	}()
+
+	tmp := t.TempDir()
+	since := time.Date(2026, 5, 1, 0, 0, 0, 0, time.UTC)
+	oldFile := filepath.Join(tmp, "old.toml")
+	newFile := filepath.Join(tmp, "new.json")
+	for path, mtime := range map[string]time.Time{
+		oldFile: since.Add(-time.Hour),
+		newFile: since.Add(time.Hour),
+	} {
+		if err := os.WriteFile(path, []byte("{}"), 0o600); err != nil {
+			t.Fatalf("WriteFile(%s): %v", path, err)
+		}
+		if err := os.Chtimes(path, mtime, mtime); err != nil {
+			t.Fatalf("Chtimes(%s): %v", path, err)
+		}
+	}
+
+	payload, err := json.Marshal([]map[string]any{
+		{"name": "stale", "origin": map[string]any{"kind": "mcpx_config", "path": oldFile}},
+		{"name": "fresh", "origin": map[string]any{"kind": "cursor", "path": newFile}},
+		{"name": "from-env", "origin": map[string]any{"kind": "env"}},
+		{"name": "missing", "origin": map[string]any{"kind": "claude", "path": filepath.Join(tmp, "gone.json")}},
+	})
+	if err != nil {
+		t.Fatalf("json.Marshal(payload): %v", err)
+	}
+	client := stubDaemonClient{sendFn: func(*ipc.Request) (*ipc.Response, error) {
+		return &ipc.Response{ExitCode: ipc.ExitOK, Content: payload}, nil
+	}}
+
+	var out, errOut bytes.Buffer
+	rootStdout, rootStderr = &out, &errOut
+	if code := listServersFromDaemonWithArgs(client, "", rootServerListArgs{output: outputModeText, changedSince: &since}); code != ipc.ExitOK {
+		t.Fatalf("listServersFromDaemonWithArgs(text) = %d, want %d", code, ipc.ExitOK)
+	}
+	if out.String() != "fresh\n" {
+		t.Fatalf("stdout = %q, want only the fresh server", out.String())
+	}
+
+	out.Reset()
+	if code := listServersFromDaemonWithArgs(client, "", rootServerListArgs{output: outputModeJSON, changedSince: &since}); code != ipc.ExitOK {
+		t.Fatalf("listServersFromDaemonWithArgs(json) = %d, want %d", code, ipc.ExitOK)
+	}
+	var got []serverListEntry
+	if err := json.Unmarshal(out.Bytes(), &got); err != nil {
+		t.Fatalf("json.Unmarshal(stdout %q): %v", out.String(), err)
+	}
+	if len(got) != 1 || got[0].Name != "fresh" || got[0].MTime != since.Add(time.Hour).Format(time.RFC3339) {
+		t.Fatalf("entries = %#v, want fresh with mtime", got)
+	}
+	if errOut.Len() != 0 {
+		t.Fatalf("stderr = %q, want empty", errOut.String())
+	}
+}
@@ -5,12 +5,16 @@ import (
 	"io"
 	"strings"
 
+	"github.com/lydakis/mcpx/internal/config"
 	"github.com/lydakis/mcpx/internal/ipc"
 )
 
 func runCompletionCommand(args []string, stdout, stderr io.Writer) int {
+	if len(args) > 0 && args[0] == "status" {
+		return runCompletionStatus(args[1:], stdout, stderr)
+	}
 	if len(args) != 1 {
-		fmt.Fprintln(stderr, "mcpx: usage: mcpx completion <bash|zsh|fish>")
+		fmt.Fprintln(stderr, "mcpx: usage: mcpx completion <bash|zsh|fish|status>")
 		return ipc.ExitUsageErr
 	}
 
@@ -24,7 +28,7 @@ func runCompletionCommand(args []string, stdout, stderr io.Writer) int {
 	return ipc.ExitOK
 }
 
-func runInternalCompletion(args []string, stdout, stderr io.Writer) int {
+func runInternalCompletion(cfg *config.Config, args []string, stdout, stderr io.Writer) int {
 	if len(args) == 0 {
 		fmt.Fprintln(stderr, "mcpx: usage: mcpx __complete <servers|tools|flags> ...")
 		return ipc.ExitUsageErr
@@ -44,11 +48,14 @@ func runInternalCompletion(args []string, stdout, stderr io.Writer) int {
 		}
 		return completeTools(args[1], stdout, stderr)
 	case "flags":
-		if len(args) != 3 {
-			fmt.Fprintln(stderr, "mcpx: usage: mcpx __complete flags <server> <tool>")
+		if len(args) != 3 && len(args) != 4 {
+			fmt.Fprintln(stderr, "mcpx: usage: mcpx __complete flags <server> <tool> [--flag=]")
 			return ipc.ExitUsageErr
 		}
-		return completeFlags(args[1], args[2], stdout, stderr)
+		if len(args) == 4 {
+			return completeFlagValues(cfg, args[1], args[2], args[3], stdout, stderr)
+		}
+		return completeFlags(cfg, args[1], args[2], stdout, stderr)
 	default:
 		fmt.Fprintf(stderr, "mcpx: unknown completion query: %s\n", args[0])
 		return ipc.ExitUsageErr
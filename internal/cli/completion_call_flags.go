@@ -1,27 +1,155 @@
 package cli
 
-import "sort"
+import (
+	"encoding/json"
+	"sort"
+	"strings"
+)
 
 var (
 	globalCallFlags = []string{
 		"--cache",
 		"--no-cache",
+		"--cache-scope",
+		"--explain-cache",
+		"--cache-errors",
+		"--no-cache-for-errors",
+		"--fresh",
+		"--stale-ok",
+		"--on-error",
+		"--soft-fail",
+		"--ignore-exit",
+		"--json-errors-to-stdout",
+		"--show-schema-diff",
+		"--param-default",
+		"--param-file-json",
+		"--param-prompt",
+		"--print-curl",
+		"--timeout",
+		"--timeout-from-env",
+		"--attempt-timeout",
+		"--confirm",
+		"--yes",
+		"--dry-run",
+		"--sample-output",
+		"--dump-schema-md",
+		"--examples",
+		"--param-required-check",
+		"--output-schema-sample",
+		"--timeout-per-attempt",
+		"--warn-slow",
+		"--max-response-time-warn",
+		"--max-retries-respect-retry-after",
+		"--retry-budget",
+		"--repeat-until",
+		"--interval",
+		"--max-wait",
+		"--map-exit",
+		"--map-exit-rule",
+		"--capture-stderr",
+		"--progress",
+		"--output-raw-bytes",
+		"--raw",
+		"--combine-content",
+		"--structured-only",
+		"--text-only",
+		"--output-encoding",
+		"--flatten",
+		"--jsonl-field",
+		"--output-file",
+		"--output-file-per-item",
+		"--tee",
+		"--name-field",
+		"--redact",
+		"--header",
+		"--headers-from-file",
+		"--idempotency-key",
+		"--save-as",
+		"--args-stdin-merge",
+		"--input-from-prev",
+		"--no-schema-cache",
+		"--args-template-file",
+		"--var",
 		"--verbose",
 		"-v",
 		"--quiet",
 		"-q",
 		"--json",
+		"--yaml",
 		"--help",
 		"-h",
 	}
 	reservedToolFlagNames = map[string]struct{}{
-		"cache":    {},
-		"no-cache": {},
-		"verbose":  {},
-		"quiet":    {},
-		"json":     {},
-		"help":     {},
-		"version":  {},
+		"cache":                           {},
+		"no-cache":                        {},
+		"cache-scope":                     {},
+		"explain-cache":                   {},
+		"cache-errors":                    {},
+		"no-cache-for-errors":             {},
+		"fresh":                           {},
+		"stale-ok":                        {},
+		"on-error":                        {},
+		"soft-fail":                       {},
+		"ignore-exit":                     {},
+		"json-errors-to-stdout":           {},
+		"show-schema-diff":                {},
+		"param-file-json":                 {},
+		"param-prompt":                    {},
+		"param-default":                   {},
+		"print-curl":                      {},
+		"timeout":                         {},
+		"timeout-from-env":                {},
+		"confirm":                         {},
+		"yes":                             {},
+		"dry-run":                         {},
+		"sample-output":                   {},
+		"dump-schema-md":                  {},
+		"examples":                        {},
+		"param-required-check":            {},
+		"output-schema-sample":            {},
+		"attempt-timeout":                 {},
+		"timeout-per-attempt":             {},
+		"warn-slow":                       {},
+		"max-response-time-warn":          {},
+		"max-retries-respect-retry-after": {},
+		"retry-budget":                    {},
+		"repeat-until":                    {},
+		"interval":                        {},
+		"max-wait":                        {},
+		"map-exit":                        {},
+		"redact":                          {},
+		"map-exit-rule":                   {},
+		"capture-stderr":                  {},
+		"progress":                        {},
+		"output-raw-bytes":                {},
+		"raw":                             {},
+		"combine-content":                 {},
+		"structured-only":                 {},
+		"output-structured-only":          {},
+		"text-only":                       {},
+		"output-text-only":                {},
+		"output-encoding":                 {},
+		"flatten":                         {},
+		"jsonl-field":                     {},
+		"output-file":                     {},
+		"output-file-per-item":            {},
+		"tee":                             {},
+		"name-field":                      {},
+		"header":                          {},
+		"headers-from-file":               {},
+		"idempotency-key":                 {},
+		"save-as":                         {},
+		"args-stdin-merge":                {},
+		"input-from-prev":                 {},
+		"no-schema-cache":                 {},
+		"args-template-file":              {},
+		"var":                             {},
+		"verbose":                         {},
+		"quiet":                           {},
+		"json":                            {},
+		"yaml":                            {},
+		"help":                            {},
+		"version":                         {},
 	}
 )
 
@@ -49,6 +177,44 @@ func toolFlagCompletions(inputSchema map[string]any) []string {
 	return uniqueSorted(flags)
 }
 
+// toolFlagValueCompletions returns "--flag=value" candidates for the enum of
+// the parameter named by a "--flag=..." token. Array parameters use the enum
+// of their items. Non-string values are JSON-encoded.
+func toolFlagValueCompletions(inputSchema map[string]any, token string) []string {
+	flag, _, ok := strings.Cut(token, "=")
+	if !ok || !strings.HasPrefix(flag, "--") {
+		return nil
+	}
+
+	props, _ := inputSchema["properties"].(map[string]any)
+	for name, raw := range props {
+		prop, _ := raw.(map[string]any)
+		typ, _ := prop["type"].(string)
+		if base, _ := toolFlagNames(name, typ); base != flag {
+			continue
+		}
+
+		enum, _ := prop["enum"].([]any)
+		if items, ok := prop["items"].(map[string]any); ok && len(enum) == 0 {
+			enum, _ = items["enum"].([]any)
+		}
+		values := make([]string, 0, len(enum))
+		for _, v := range enum {
+			if s, ok := v.(string); ok {
+				values = append(values, flag+"="+s)
+				continue
+			}
+			encoded, err := json.Marshal(v)
+			if err != nil {
+				continue
+			}
+			values = append(values, flag+"="+string(encoded))
+		}
+		return uniqueSorted(values)
+	}
+	return nil
+}
+
 func isReservedToolFlagName(name string) bool {
 	_, ok := reservedToolFlagNames[name]
 	return ok
@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"io"
 
+	"github.com/lydakis/mcpx/internal/config"
 	"github.com/lydakis/mcpx/internal/daemon"
 	"github.com/lydakis/mcpx/internal/ipc"
 )
@@ -67,13 +68,42 @@ func completeTools(server string, stdout, stderr io.Writer) int {
 	return ipc.ExitOK
 }
 
-func completeFlags(server, tool string, stdout, stderr io.Writer) int {
-	client, code := completionClient(stderr)
+func completeFlags(cfg *config.Config, server, tool string, stdout, stderr io.Writer) int {
+	inputSchema, code := completionInputSchema(cfg, server, tool, stderr)
 	if code != ipc.ExitOK {
 		return code
 	}
+	for _, flag := range toolFlagCompletions(inputSchema) {
+		fmt.Fprintln(stdout, flag)
+	}
+	return ipc.ExitOK
+}
 
-	resp, err := client.Send(&ipc.Request{
+// completeFlagValues prints --flag=value candidates for a partially typed
+// --flag= token whose parameter declares an enum.
+func completeFlagValues(cfg *config.Config, server, tool, token string, stdout, stderr io.Writer) int {
+	inputSchema, code := completionInputSchema(cfg, server, tool, stderr)
+	if code != ipc.ExitOK {
+		return code
+	}
+	for _, value := range toolFlagValueCompletions(inputSchema, token) {
+		fmt.Fprintln(stdout, value)
+	}
+	return ipc.ExitOK
+}
+
+func completionInputSchema(cfg *config.Config, server, tool string, stderr io.Writer) (map[string]any, int) {
+	if payload, ok := cachedToolSchema(cfg, server, tool); ok {
+		_, _, inputSchema, _ := parseToolHelpPayload(payload)
+		return inputSchema, ipc.ExitOK
+	}
+
+	client, code := completionClient(stderr)
+	if code != ipc.ExitOK {
+		return nil, code
+	}
+
+	resp, err := withSchemaCache(client, cfg, false).Send(&ipc.Request{
 		Type:   "tool_schema",
 		Server: server,
 		Tool:   tool,
@@ -81,25 +111,22 @@ func completeFlags(server, tool string, stdout, stderr io.Writer) int {
 	})
 	if err != nil {
 		fmt.Fprintf(stderr, "mcpx: %v\n", err)
-		return ipc.ExitInternal
+		return nil, ipc.ExitInternal
 	}
 	if resp.Stderr != "" {
 		fmt.Fprintln(stderr, resp.Stderr)
-		return resp.ExitCode
+		return nil, resp.ExitCode
 	}
 
 	_, _, inputSchema, _ := parseToolHelpPayload(resp.Content)
-	for _, flag := range toolFlagCompletions(inputSchema) {
-		fmt.Fprintln(stdout, flag)
-	}
-	return ipc.ExitOK
+	return inputSchema, ipc.ExitOK
 }
 
-func completionClient(stderr io.Writer) (*ipc.Client, int) {
+func completionClient(stderr io.Writer) (daemonRequester, int) {
 	nonce, err := daemon.SpawnOrConnect()
 	if err != nil {
 		fmt.Fprintf(stderr, "mcpx: %v\n", err)
 		return nil, ipc.ExitInternal
 	}
-	return ipc.NewClient(ipc.SocketPath(), nonce), ipc.ExitOK
+	return withEnvServers(ipc.NewClient(ipc.SocketPath(), nonce)), ipc.ExitOK
 }
@@ -297,7 +297,7 @@ func TestCompleteFlagsPrintsToolFlagCompletions(t *testing.T) {
 
 	var out bytes.Buffer
 	var errOut bytes.Buffer
-	code := completeFlags("math", "search", &out, &errOut)
+	code := completeFlags(nil, "math", "search", &out, &errOut)
 	if code != ipc.ExitOK {
 		t.Fatalf("completeFlags() code = %d, want %d", code, ipc.ExitOK)
 	}
@@ -329,6 +329,33 @@ func TestCompleteFlagsPrintsToolFlagCompletions(t *testing.T) {
 	}
 }
 
+func TestRunInternalCompletionPrintsEnumFlagValues(t *testing.T) {
+	setupCompletionRuntimeServer(t, func(*ipc.Request) *ipc.Response {
+		return &ipc.Response{
+			ExitCode: ipc.ExitOK,
+			Content: []byte(`{
+				"name": "search",
+				"input_schema": {
+					"type": "object",
+					"properties": {
+						"sort": {"type": "string", "enum": ["stars", "updated"]}
+					}
+				}
+			}`),
+		}
+	})
+
+	var out bytes.Buffer
+	var errOut bytes.Buffer
+	code := runInternalCompletion(nil, []string{"flags", "math", "search", "--sort="}, &out, &errOut)
+	if code != ipc.ExitOK {
+		t.Fatalf("runInternalCompletion() code = %d, want %d (stderr=%q)", code, ipc.ExitOK, errOut.String())
+	}
+	if got := out.String(); got != "--sort=stars\n--sort=updated\n" {
+		t.Fatalf("stdout = %q, want enum values", got)
+	}
+}
+
 func TestCompleteFlagsReturnsDaemonExitCodeWhenStderrPresent(t *testing.T) {
 	setupCompletionRuntimeServer(t, func(req *ipc.Request) *ipc.Response {
 		return &ipc.Response{ExitCode: ipc.ExitUsageErr, Stderr: "schema denied"}
@@ -336,7 +363,7 @@ func TestCompleteFlagsReturnsDaemonExitCodeWhenStderrPresent(t *testing.T) {
 
 	var out bytes.Buffer
 	var errOut bytes.Buffer
-	code := completeFlags("math", "search", &out, &errOut)
+	code := completeFlags(nil, "math", "search", &out, &errOut)
 	if code != ipc.ExitUsageErr {
 		t.Fatalf("completeFlags() code = %d, want %d", code, ipc.ExitUsageErr)
 	}
@@ -353,7 +380,7 @@ func TestCompleteFlagsHandlesSendError(t *testing.T) {
 
 	var out bytes.Buffer
 	var errOut bytes.Buffer
-	code := completeFlags("math", "search", &out, &errOut)
+	code := completeFlags(nil, "math", "search", &out, &errOut)
 	if code != ipc.ExitInternal {
 		t.Fatalf("completeFlags() code = %d, want %d", code, ipc.ExitInternal)
 	}
@@ -372,6 +399,7 @@ func TestCompletionClientReturnsInternalErrorWhenRuntimeDirInvalid(t *testing.T)
 		t.Fatalf("WriteFile(runtime file): %v", err)
 	}
 	t.Setenv("XDG_RUNTIME_DIR", runtimeFile)
+	t.Setenv("TMPDIR", runtimeFile) // also rules out the temp-dir fallbacks
 
 	var errOut bytes.Buffer
 	client, code := completionClient(&errOut)
@@ -130,6 +130,19 @@ _mcpx_completion() {
   fi
 
   tool="${COMP_WORDS[2]}"
+  local token
+  token="${COMP_LINE:0:COMP_POINT}"
+  token="${token##*[[:space:]]}"
+  if [[ "$token" == --*=* ]]; then
+    local values
+    values="$(mcpx __complete flags "$first" "$tool" "$token" 2>/dev/null)"
+    COMPREPLY=( $(compgen -W "$values" -- "$token") )
+    if [[ "$COMP_WORDBREAKS" == *=* ]]; then
+      COMPREPLY=( "${COMPREPLY[@]#"${token%%=*}="}" )
+    fi
+    return 0
+  fi
+
   local flags
   flags="$(mcpx __complete flags "$first" "$tool" 2>/dev/null)"
   COMPREPLY=( $(compgen -W "$flags" -- "$cur") )
@@ -251,6 +264,15 @@ _mcpx_completion() {
     return
   fi
 
+  if [[ "${words[CURRENT]}" == --*=* ]]; then
+    local -a values
+    values=(${(f)"$(mcpx __complete flags ${words[2]} ${words[3]} ${words[CURRENT]} 2>/dev/null)"})
+    values=(${values#*=})
+    compset -P '*='
+    compadd -a values
+    return
+  fi
+
   flags=(${(f)"$(mcpx __complete flags ${words[2]} ${words[3]} 2>/dev/null)"})
   _describe 'flag' flags
 }
@@ -321,4 +343,5 @@ complete -c mcpx -n 'set -l w (__mcpx_words); test (count $w) -ge 4; and test "$
 complete -c mcpx -n 'set -l w (__mcpx_words); test (count $w) -ge 4; and test "$w[2]" = shim; and not __mcpx_has_shim_server; and test "$w[3]" = list' -a "--dir --help -h"
 complete -c mcpx -n 'set -l w (__mcpx_words); test (count $w) -eq 2; and test "$w[2]" != completion; and begin; test "$w[2]" != add; or __mcpx_has_add_server; end; and begin; test "$w[2]" != skill; or __mcpx_has_skill_server; end; and begin; test "$w[2]" != shim; or __mcpx_has_shim_server; end' -a "(mcpx __complete tools (__mcpx_server) 2>/dev/null)"
 complete -c mcpx -n 'set -l w (__mcpx_words); test (count $w) -ge 3; and test "$w[2]" != completion; and begin; test "$w[2]" != add; or __mcpx_has_add_server; end; and begin; test "$w[2]" != skill; or __mcpx_has_skill_server; end; and begin; test "$w[2]" != shim; or __mcpx_has_shim_server; end' -a "(mcpx __complete flags (__mcpx_server) (__mcpx_tool) 2>/dev/null)"
+complete -c mcpx -n 'set -l w (__mcpx_words); test (count $w) -ge 3; and string match -q -- "--*=*" (commandline -ct); and test "$w[2]" != completion; and begin; test "$w[2]" != add; or __mcpx_has_add_server; end; and begin; test "$w[2]" != skill; or __mcpx_has_skill_server; end; and begin; test "$w[2]" != shim; or __mcpx_has_shim_server; end' -a "(mcpx __complete flags (__mcpx_server) (__mcpx_tool) (commandline -ct) 2>/dev/null)"
 `
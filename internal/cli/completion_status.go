@@ -0,0 +1,122 @@
+package cli
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/lydakis/mcpx/internal/ipc"
+	"github.com/lydakis/mcpx/internal/paths"
+)
+
+// completionStatus is the diagnosis printed by `mcpx completion status`.
+type completionStatus struct {
+	shell string
+	// path is the first installed completion file found, or "".
+	path     string
+	searched []string
+	// stale reports that the installed file differs from the script this
+	// binary generates.
+	stale bool
+	// servers is the server count `__complete servers` returned, valid when
+	// serversErr is empty.
+	servers    int
+	serversErr string
+}
+
+func (s completionStatus) ok() bool {
+	return s.path != "" && !s.stale && s.serversErr == ""
+}
+
+func runCompletionStatus(args []string, stdout, stderr io.Writer) int {
+	if len(args) > 1 {
+		fmt.Fprintln(stderr, "mcpx: usage: mcpx completion status [bash|zsh|fish]")
+		return ipc.ExitUsageErr
+	}
+
+	shell := ""
+	if len(args) == 1 {
+		shell = strings.ToLower(args[0])
+	} else {
+		shell = filepath.Base(strings.TrimSpace(os.Getenv("SHELL")))
+		if shell == "." || shell == "/" {
+			shell = ""
+		}
+	}
+	if shell == "" {
+		fmt.Fprintln(stderr, "mcpx: cannot detect shell: $SHELL is not set; pass one: mcpx completion status <bash|zsh|fish>")
+		return ipc.ExitUsageErr
+	}
+	if _, ok := completionScripts[shell]; !ok {
+		fmt.Fprintf(stderr, "mcpx: unknown shell for completion: %s\n", shell)
+		return ipc.ExitUsageErr
+	}
+
+	status := checkCompletionStatus(shell, paths.CompletionFileCandidates(shell), completeServers)
+	printCompletionStatus(stdout, status)
+	if !status.ok() {
+		return ipc.ExitToolErr
+	}
+	return ipc.ExitOK
+}
+
+// checkCompletionStatus looks for an installed completion file among
+// candidates and runs the server query the completion scripts rely on.
+func checkCompletionStatus(shell string, candidates []string, listServers func(stdout, stderr io.Writer) int) completionStatus {
+	status := completionStatus{shell: shell, searched: candidates}
+	for _, candidate := range candidates {
+		data, err := os.ReadFile(candidate)
+		if err != nil {
+			continue
+		}
+		status.path = candidate
+		status.stale = string(data) != completionScripts[shell]
+		break
+	}
+
+	var out, errOut bytes.Buffer
+	if code := listServers(&out, &errOut); code != ipc.ExitOK {
+		msg := strings.TrimSpace(errOut.String())
+		if msg == "" {
+			msg = fmt.Sprintf("exit code %d", code)
+		}
+		status.serversErr = msg
+		return status
+	}
+	for _, line := range strings.Split(out.String(), "\n") {
+		if strings.TrimSpace(line) != "" {
+			status.servers++
+		}
+	}
+	return status
+}
+
+func printCompletionStatus(w io.Writer, status completionStatus) {
+	fmt.Fprintf(w, "shell: %s\n", status.shell)
+	switch {
+	case status.path == "":
+		fmt.Fprintln(w, "completion file: not found; looked in:")
+		for _, candidate := range status.searched {
+			fmt.Fprintf(w, "  %s\n", candidate)
+		}
+		if len(status.searched) > 0 {
+			fmt.Fprintf(w, "  install with: mcpx completion %s > %s\n", status.shell, status.searched[0])
+		}
+	case status.stale:
+		fmt.Fprintf(w, "completion file: %s (out of date)\n", status.path)
+		fmt.Fprintf(w, "  regenerate with: mcpx completion %s > %s\n", status.shell, status.path)
+	default:
+		fmt.Fprintf(w, "completion file: %s (up to date)\n", status.path)
+	}
+	if status.serversErr != "" {
+		fmt.Fprintf(w, "mcpx __complete servers: failed: %s\n", status.serversErr)
+	} else {
+		fmt.Fprintf(w, "mcpx __complete servers: ok (%d servers)\n", status.servers)
+	}
+	if status.ok() {
+		fmt.Fprintln(w, "completion is installed; restart the shell if it does not respond")
+	}
+}
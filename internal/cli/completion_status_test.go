@@ -0,0 +1,94 @@
+package cli
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/lydakis/mcpx/internal/ipc"
+	"github.com/lydakis/mcpx/internal/paths"
+)
+
+func listServersStub(names ...string) func(stdout, stderr io.Writer) int {
+	return func(stdout, _ io.Writer) int {
+		for _, name := range names {
+			fmt.Fprintln(stdout, name)
+		}
+		return ipc.ExitOK
+	}
+}
+
+func TestCheckCompletionStatusFindsInstalledFile(t *testing.T) {
+	dataHome := t.TempDir()
+	t.Setenv("XDG_DATA_HOME", dataHome)
+	candidates := paths.CompletionFileCandidates("bash")
+	installed := filepath.Join(dataHome, "bash-completion", "completions", "mcpx")
+	if candidates[0] != installed {
+		t.Fatalf("first bash candidate = %q, want %q", candidates[0], installed)
+	}
+
+	status := checkCompletionStatus("bash", candidates, listServersStub("github", "linear"))
+	if status.path != "" || status.ok() {
+		t.Fatalf("status before install = %+v, want not found", status)
+	}
+
+	if err := os.MkdirAll(filepath.Dir(installed), 0o755); err != nil {
+		t.Fatalf("MkdirAll() error = %v", err)
+	}
+	if err := os.WriteFile(installed, []byte(completionScripts["bash"]), 0o644); err != nil {
+		t.Fatalf("WriteFile() error = %v", err)
+	}
+	status = checkCompletionStatus("bash", candidates, listServersStub("github", "linear"))
+	if status.path != installed || status.stale || status.servers != 2 || !status.ok() {
+		t.Fatalf("status after install = %+v, want up-to-date file with 2 servers", status)
+	}
+
+	if err := os.WriteFile(installed, []byte("# old script\n"), 0o644); err != nil {
+		t.Fatalf("WriteFile() error = %v", err)
+	}
+	status = checkCompletionStatus("bash", candidates, listServersStub("github"))
+	if !status.stale || status.ok() {
+		t.Fatalf("status with old script = %+v, want stale", status)
+	}
+	var out bytes.Buffer
+	printCompletionStatus(&out, status)
+	if !strings.Contains(out.String(), "regenerate with: mcpx completion bash > "+installed) {
+		t.Fatalf("status output = %q, want regenerate hint", out.String())
+	}
+}
+
+func TestCheckCompletionStatusReportsServerQueryFailure(t *testing.T) {
+	dir := t.TempDir()
+	installed := filepath.Join(dir, "mcpx.fish")
+	if err := os.WriteFile(installed, []byte(completionScripts["fish"]), 0o644); err != nil {
+		t.Fatalf("WriteFile() error = %v", err)
+	}
+
+	failing := func(_, stderr io.Writer) int {
+		fmt.Fprintln(stderr, "mcpx: connecting to daemon: refused")
+		return ipc.ExitInternal
+	}
+	status := checkCompletionStatus("fish", []string{filepath.Join(dir, "missing"), installed}, failing)
+	if status.path != installed {
+		t.Fatalf("status.path = %q, want %q", status.path, installed)
+	}
+	if status.ok() || !strings.Contains(status.serversErr, "refused") {
+		t.Fatalf("status = %+v, want server query failure", status)
+	}
+}
+
+func TestRunCompletionStatusRequiresKnownShell(t *testing.T) {
+	t.Setenv("SHELL", "")
+	var out, errOut bytes.Buffer
+	if code := runCompletionCommand([]string{"status"}, &out, &errOut); code != ipc.ExitUsageErr {
+		t.Fatalf("runCompletionCommand(status) with no $SHELL = %d, want %d", code, ipc.ExitUsageErr)
+	}
+	errOut.Reset()
+	if code := runCompletionCommand([]string{"status", "powershell"}, &out, &errOut); code != ipc.ExitUsageErr {
+		t.Fatalf("runCompletionCommand(status powershell) = %d, want %d", code, ipc.ExitUsageErr)
+	}
+}
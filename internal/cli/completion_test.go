@@ -2,6 +2,7 @@ package cli
 
 import (
 	"bytes"
+	"strings"
 	"testing"
 
 	"github.com/lydakis/mcpx/internal/ipc"
@@ -112,7 +113,7 @@ func TestRunInternalCompletionRequiresQueryType(t *testing.T) {
 	var out bytes.Buffer
 	var errOut bytes.Buffer
 
-	code := runInternalCompletion(nil, &out, &errOut)
+	code := runInternalCompletion(nil, nil, &out, &errOut)
 	if code != ipc.ExitUsageErr {
 		t.Fatalf("runInternalCompletion() code = %d, want %d", code, ipc.ExitUsageErr)
 	}
@@ -128,7 +129,7 @@ func TestRunInternalCompletionRejectsUnknownQueryType(t *testing.T) {
 	var out bytes.Buffer
 	var errOut bytes.Buffer
 
-	code := runInternalCompletion([]string{"unknown"}, &out, &errOut)
+	code := runInternalCompletion(nil, []string{"unknown"}, &out, &errOut)
 	if code != ipc.ExitUsageErr {
 		t.Fatalf("runInternalCompletion() code = %d, want %d", code, ipc.ExitUsageErr)
 	}
@@ -144,7 +145,7 @@ func TestRunInternalCompletionValidatesServersArity(t *testing.T) {
 	var out bytes.Buffer
 	var errOut bytes.Buffer
 
-	code := runInternalCompletion([]string{"servers", "extra"}, &out, &errOut)
+	code := runInternalCompletion(nil, []string{"servers", "extra"}, &out, &errOut)
 	if code != ipc.ExitUsageErr {
 		t.Fatalf("runInternalCompletion() code = %d, want %d", code, ipc.ExitUsageErr)
 	}
@@ -160,7 +161,7 @@ func TestRunInternalCompletionValidatesToolsArity(t *testing.T) {
 	var out bytes.Buffer
 	var errOut bytes.Buffer
 
-	code := runInternalCompletion([]string{"tools"}, &out, &errOut)
+	code := runInternalCompletion(nil, []string{"tools"}, &out, &errOut)
 	if code != ipc.ExitUsageErr {
 		t.Fatalf("runInternalCompletion() code = %d, want %d", code, ipc.ExitUsageErr)
 	}
@@ -176,14 +177,14 @@ func TestRunInternalCompletionValidatesFlagsArity(t *testing.T) {
 	var out bytes.Buffer
 	var errOut bytes.Buffer
 
-	code := runInternalCompletion([]string{"flags", "github"}, &out, &errOut)
+	code := runInternalCompletion(nil, []string{"flags", "github"}, &out, &errOut)
 	if code != ipc.ExitUsageErr {
 		t.Fatalf("runInternalCompletion() code = %d, want %d", code, ipc.ExitUsageErr)
 	}
 	if out.Len() != 0 {
 		t.Fatalf("stdout = %q, want empty", out.String())
 	}
-	if got := errOut.String(); got != "mcpx: usage: mcpx __complete flags <server> <tool>\n" {
+	if got := errOut.String(); got != "mcpx: usage: mcpx __complete flags <server> <tool> [--flag=]\n" {
 		t.Fatalf("stderr = %q, want flags usage", got)
 	}
 }
@@ -388,3 +389,31 @@ func contains(items []string, needle string) bool {
 	}
 	return false
 }
+
+func TestToolFlagValueCompletionsListsEnumValues(t *testing.T) {
+	input := map[string]any{
+		"type": "object",
+		"properties": map[string]any{
+			"mode":  map[string]any{"type": "string", "enum": []any{"slow", "fast"}},
+			"level": map[string]any{"type": "integer", "enum": []any{float64(1), float64(2)}},
+			"tags":  map[string]any{"type": "array", "items": map[string]any{"enum": []any{"a", "b"}}},
+			"cache": map[string]any{"type": "string", "enum": []any{"on"}},
+			"query": map[string]any{"type": "string"},
+		},
+	}
+
+	cases := map[string][]string{
+		"--mode=f":      {"--mode=fast", "--mode=slow"},
+		"--level=":      {"--level=1", "--level=2"},
+		"--tags=":       {"--tags=a", "--tags=b"},
+		"--tool-cache=": {"--tool-cache=on"},
+		"--query=":      {},
+		"--mode":        nil,
+	}
+	for token, want := range cases {
+		got := toolFlagValueCompletions(input, token)
+		if strings.Join(got, ",") != strings.Join(want, ",") {
+			t.Fatalf("toolFlagValueCompletions(%q) = %v, want %v", token, got, want)
+		}
+	}
+}
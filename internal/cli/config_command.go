@@ -0,0 +1,67 @@
+package cli
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/lydakis/mcpx/internal/config"
+	"github.com/lydakis/mcpx/internal/ipc"
+)
+
+func maybeHandleConfigCommand(args []string, cfg *config.Config, stdout, stderr io.Writer) (bool, int) {
+	if len(args) == 0 || args[0] != "config" {
+		return false, 0
+	}
+
+	if cfg != nil {
+		if _, ok := cfg.Servers["config"]; ok {
+			return false, 0
+		}
+	}
+
+	return true, runConfigCommand(args[1:], stdout, stderr)
+}
+
+func runConfigCommand(args []string, stdout, stderr io.Writer) int {
+	if len(args) == 0 || args[0] == "help" || isHelpFlag(args[0]) {
+		printConfigHelp(stdout)
+		return ipc.ExitOK
+	}
+
+	switch args[0] {
+	case "schema":
+		return runConfigSchemaCommand(args[1:], stdout, stderr)
+	default:
+		fmt.Fprintf(stderr, "mcpx: unknown config command: %s\n", args[0])
+		printConfigHelp(stderr)
+		return ipc.ExitUsageErr
+	}
+}
+
+func runConfigSchemaCommand(args []string, stdout, stderr io.Writer) int {
+	if len(args) > 0 {
+		if isHelpFlag(args[0]) {
+			printConfigHelp(stdout)
+			return ipc.ExitOK
+		}
+		fmt.Fprintf(stderr, "mcpx: config schema: unexpected argument: %s\n", args[0])
+		return ipc.ExitUsageErr
+	}
+
+	schema, err := config.JSONSchema()
+	if err != nil {
+		fmt.Fprintf(stderr, "mcpx: config schema: %v\n", err)
+		return ipc.ExitInternal
+	}
+	fmt.Fprintln(stdout, string(schema))
+	return ipc.ExitOK
+}
+
+func printConfigHelp(out io.Writer) {
+	fmt.Fprintln(out, "Usage:")
+	fmt.Fprintln(out, "  mcpx config schema")
+	fmt.Fprintln(out, "")
+	fmt.Fprintln(out, "Commands:")
+	fmt.Fprintln(out, "  schema     Print the JSON Schema for config.toml, for editor validation")
+	fmt.Fprintln(out, "             and completion (for example with taplo or Even Better TOML).")
+}
@@ -0,0 +1,40 @@
+package cli
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+
+	"github.com/lydakis/mcpx/internal/config"
+	"github.com/lydakis/mcpx/internal/ipc"
+)
+
+func TestMaybeHandleConfigCommandDefersToServerName(t *testing.T) {
+	cfg := &config.Config{Servers: map[string]config.ServerConfig{"config": {}}}
+
+	handled, _ := maybeHandleConfigCommand([]string{"config", "schema"}, cfg, &bytes.Buffer{}, &bytes.Buffer{})
+	if handled {
+		t.Fatal("handled = true, want false")
+	}
+}
+
+func TestRunConfigSchemaPrintsJSONSchema(t *testing.T) {
+	var out bytes.Buffer
+	var errOut bytes.Buffer
+	code := runConfigCommand([]string{"schema"}, &out, &errOut)
+	if code != ipc.ExitOK {
+		t.Fatalf("runConfigCommand(schema) = %d, want %d (stderr=%q)", code, ipc.ExitOK, errOut.String())
+	}
+
+	var schema map[string]any
+	if err := json.Unmarshal(out.Bytes(), &schema); err != nil {
+		t.Fatalf("stdout is not JSON: %v", err)
+	}
+	if schema["$id"] != config.SchemaID {
+		t.Fatalf("$id = %v, want %q", schema["$id"], config.SchemaID)
+	}
+
+	if code := runConfigCommand([]string{"bogus"}, &out, &errOut); code != ipc.ExitUsageErr {
+		t.Fatalf("runConfigCommand(bogus) = %d, want %d", code, ipc.ExitUsageErr)
+	}
+}
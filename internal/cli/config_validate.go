@@ -0,0 +1,86 @@
+package cli
+
+import (
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/lydakis/mcpx/internal/config"
+	"github.com/lydakis/mcpx/internal/ipc"
+)
+
+type configValidationReport struct {
+	OK     bool     `json:"ok"`
+	Errors []string `json:"errors"`
+}
+
+// maybeHandleValidateFlag handles `mcpx --validate [--json]`: load, merge
+// fallback servers, and validate config without contacting the daemon.
+func maybeHandleValidateFlag(args []string, stdout, stderr io.Writer) (bool, int) {
+	validate := false
+	output := outputModeText
+	for _, arg := range args {
+		switch arg {
+		case "--validate":
+			validate = true
+		case "--json":
+			output = outputModeJSON
+		default:
+			return false, 0
+		}
+	}
+	if !validate {
+		return false, 0
+	}
+
+	report := validateConfigReport()
+	if output.isJSON() {
+		if err := writeJSONLine(stdout, report); err != nil {
+			fmt.Fprintf(stderr, "mcpx: %v\n", err)
+			return true, ipc.ExitInternal
+		}
+	} else if report.OK {
+		fmt.Fprintln(stdout, "config OK")
+	} else {
+		for _, msg := range report.Errors {
+			fmt.Fprintf(stderr, "mcpx: invalid config: %s\n", msg)
+		}
+	}
+
+	if !report.OK {
+		return true, ipc.ExitUsageErr
+	}
+	return true, ipc.ExitOK
+}
+
+func validateConfigReport() configValidationReport {
+	report := configValidationReport{Errors: []string{}}
+
+	cfg, err := config.Load()
+	if err != nil {
+		report.Errors = append(report.Errors, err.Error())
+		return report
+	}
+	if err := config.MergeFallbackServers(cfg); err != nil {
+		report.Errors = append(report.Errors, fmt.Sprintf("loading fallback MCP server config: %v", err))
+	}
+	report.Errors = append(report.Errors, flattenErrors(config.Validate(cfg))...)
+	report.OK = len(report.Errors) == 0
+	return report
+}
+
+// flattenErrors expands errors.Join results into one message per error.
+func flattenErrors(err error) []string {
+	if err == nil {
+		return nil
+	}
+	var joined interface{ Unwrap() []error }
+	if errors.As(err, &joined) {
+		var out []string
+		for _, inner := range joined.Unwrap() {
+			out = append(out, flattenErrors(inner)...)
+		}
+		return out
+	}
+	return []string{err.Error()}
+}
@@ -0,0 +1,102 @@
+package cli
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/lydakis/mcpx/internal/ipc"
+)
+
+func writeValidateTestConfig(t *testing.T, body string) {
+	t.Helper()
+
+	tmp := t.TempDir()
+	configHome := filepath.Join(tmp, "xdg-config")
+	t.Setenv("XDG_CONFIG_HOME", configHome)
+	t.Setenv("HOME", tmp)
+	if err := os.MkdirAll(filepath.Join(configHome, "mcpx"), 0o700); err != nil {
+		t.Fatalf("MkdirAll() error = %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(configHome, "mcpx", "config.toml"), []byte(body), 0o600); err != nil {
+		t.Fatalf("WriteFile() error = %v", err)
+	}
+}
+
+func TestRunValidateReportsConfigOKWithoutDaemon(t *testing.T) {
+	writeValidateTestConfig(t, "[servers.github]\ncommand = \"gh-mcp\"\n")
+
+	oldOut, oldErr, oldSpawn := rootStdout, rootStderr, spawnOrConnectFn
+	defer func() { rootStdout, rootStderr, spawnOrConnectFn = oldOut, oldErr, oldSpawn }()
+	var stdout, stderr bytes.Buffer
+	rootStdout, rootStderr = &stdout, &stderr
+	spawnOrConnectFn = func() (string, error) {
+		t.Fatal("--validate contacted the daemon")
+		return "", errors.New("unreachable")
+	}
+
+	if code := Run([]string{"--validate"}); code != ipc.ExitOK {
+		t.Fatalf("Run([--validate]) = %d, want %d (stderr=%q)", code, ipc.ExitOK, stderr.String())
+	}
+	if stdout.String() != "config OK\n" {
+		t.Fatalf("stdout = %q, want %q", stdout.String(), "config OK\n")
+	}
+}
+
+func TestRunValidateReportsErrors(t *testing.T) {
+	writeValidateTestConfig(t, `
+[servers.both]
+command = "x"
+url = "https://example.com/mcp"
+
+[servers.bad_ttl]
+command = "y"
+default_cache_ttl = "soon"
+`)
+
+	oldOut, oldErr := rootStdout, rootStderr
+	defer func() { rootStdout, rootStderr = oldOut, oldErr }()
+	var stdout, stderr bytes.Buffer
+	rootStdout, rootStderr = &stdout, &stderr
+
+	if code := Run([]string{"--validate"}); code != ipc.ExitUsageErr {
+		t.Fatalf("Run([--validate]) = %d, want %d", code, ipc.ExitUsageErr)
+	}
+	lines := strings.Split(strings.TrimSpace(stderr.String()), "\n")
+	if len(lines) != 2 || !strings.Contains(lines[0], "servers.bad_ttl") || !strings.Contains(lines[1], "servers.both") {
+		t.Fatalf("stderr = %q, want one line per error", stderr.String())
+	}
+
+	stdout.Reset()
+	stderr.Reset()
+	if code := Run([]string{"--validate", "--json"}); code != ipc.ExitUsageErr {
+		t.Fatalf("Run([--validate --json]) = %d, want %d", code, ipc.ExitUsageErr)
+	}
+	var report configValidationReport
+	if err := json.Unmarshal(stdout.Bytes(), &report); err != nil {
+		t.Fatalf("json.Unmarshal() error = %v (stdout=%q)", err, stdout.String())
+	}
+	if report.OK || len(report.Errors) != 2 {
+		t.Fatalf("report = %#v, want two errors", report)
+	}
+}
+
+func TestRunValidateReportsParseErrors(t *testing.T) {
+	writeValidateTestConfig(t, "[servers.github\n")
+
+	oldOut, oldErr := rootStdout, rootStderr
+	defer func() { rootStdout, rootStderr = oldOut, oldErr }()
+	var stdout, stderr bytes.Buffer
+	rootStdout, rootStderr = &stdout, &stderr
+
+	if code := Run([]string{"--json", "--validate"}); code != ipc.ExitUsageErr {
+		t.Fatalf("Run([--json --validate]) = %d, want %d", code, ipc.ExitUsageErr)
+	}
+	if !strings.Contains(stdout.String(), `"ok":false`) || !strings.Contains(stdout.String(), "parsing config") {
+		t.Fatalf("stdout = %q, want parse error report", stdout.String())
+	}
+}
@@ -0,0 +1,106 @@
+package cli
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/lydakis/mcpx/internal/ipc"
+)
+
+var (
+	confirmInput       io.Reader = os.Stdin
+	confirmInteractive           = func() bool { return isTerminal(os.Stdin) && isTerminal(os.Stderr) }
+)
+
+// toolAnnotations mirrors the MCP tool hints carried in the tool_schema
+// payload. Nil means the server did not declare the hint.
+type toolAnnotations struct {
+	ReadOnly    *bool `json:"readOnlyHint,omitempty"`
+	Destructive *bool `json:"destructiveHint,omitempty"`
+	Idempotent  *bool `json:"idempotentHint,omitempty"`
+	OpenWorld   *bool `json:"openWorldHint,omitempty"`
+}
+
+func parseToolAnnotations(raw []byte) toolAnnotations {
+	var payload struct {
+		Annotations toolAnnotations `json:"annotations"`
+	}
+	if err := json.Unmarshal(raw, &payload); err != nil {
+		return toolAnnotations{}
+	}
+	return payload.Annotations
+}
+
+// destructive reports an explicit destructiveHint=true. Undeclared hints do
+// not gate calls, even though the MCP default for destructiveHint is true.
+func (a toolAnnotations) destructive() bool {
+	return a.Destructive != nil && *a.Destructive
+}
+
+// badges lists declared hints in help order (for example: "read-only").
+func (a toolAnnotations) badges() []string {
+	var out []string
+	if a.ReadOnly != nil && *a.ReadOnly {
+		out = append(out, "read-only")
+	}
+	if a.destructive() {
+		out = append(out, "destructive")
+	}
+	if a.Idempotent != nil && *a.Idempotent {
+		out = append(out, "idempotent")
+	}
+	if a.OpenWorld != nil && *a.OpenWorld {
+		out = append(out, "open-world")
+	}
+	return out
+}
+
+// confirmToolCall asks before calling a tool when --confirm is set, or when
+// the tool is declared destructive and stdin is a terminal. --yes skips the
+// prompt. It returns false with an exit code when the call must not proceed.
+func confirmToolCall(client daemonRequester, server, tool, cwd string, parsed *toolCallArgs, canonicalizeSource bool) (bool, int) {
+	if parsed.yes {
+		return true, ipc.ExitOK
+	}
+	interactive := confirmInteractive()
+	if !parsed.confirm && !interactive {
+		return true, ipc.ExitOK
+	}
+
+	var annotations toolAnnotations
+	resp, err := sendServerRequestWithEphemeralFallback(client, &ipc.Request{
+		Type:   "tool_schema",
+		Server: server,
+		Tool:   tool,
+		CWD:    cwd,
+	}, canonicalizeSource)
+	if err == nil && resp.ExitCode == ipc.ExitOK {
+		annotations = parseToolAnnotations(resp.Content)
+	}
+	if !parsed.confirm && !annotations.destructive() {
+		return true, ipc.ExitOK
+	}
+
+	label := "tool"
+	if annotations.destructive() {
+		label = "destructive tool"
+	}
+	if !interactive {
+		fmt.Fprintf(rootStderr, "mcpx: %s %s on %s needs confirmation but stdin is not a terminal; pass --yes to proceed\n", label, tool, server)
+		return false, ipc.ExitUsageErr
+	}
+
+	fmt.Fprintf(rootStderr, "Call %s %s on %s? [y/N] ", label, tool, server)
+	line, _ := bufio.NewReader(confirmInput).ReadString('\n')
+	switch strings.ToLower(strings.TrimSpace(line)) {
+	case "y", "yes":
+		return true, ipc.ExitOK
+	default:
+		fmt.Fprintln(rootStderr, "mcpx: call cancelled")
+		return false, ipc.ExitUsageErr
+	}
+}
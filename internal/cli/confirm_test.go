@@ -0,0 +1,111 @@
+package cli
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/lydakis/mcpx/internal/ipc"
+)
+
+const destructiveToolSchema = `{"name":"delete-repo","input_schema":{"type":"object"},"annotations":{"destructiveHint":true}}`
+
+func stubConfirmTerminal(t *testing.T, interactive bool, input string) {
+	t.Helper()
+	oldInteractive, oldInput := confirmInteractive, confirmInput
+	t.Cleanup(func() { confirmInteractive, confirmInput = oldInteractive, oldInput })
+	confirmInteractive = func() bool { return interactive }
+	confirmInput = strings.NewReader(input)
+}
+
+func destructiveToolClient(calls *int) stubDaemonClient {
+	return stubDaemonClient{
+		sendFn: func(req *ipc.Request) (*ipc.Response, error) {
+			if req.Type == "tool_schema" {
+				return &ipc.Response{Content: []byte(destructiveToolSchema)}, nil
+			}
+			*calls++
+			return &ipc.Response{Content: []byte("deleted\n")}, nil
+		},
+	}
+}
+
+func TestCallToolPromptsBeforeDestructiveToolOnTerminal(t *testing.T) {
+	oldOut, oldErr := rootStdout, rootStderr
+	defer func() { rootStdout, rootStderr = oldOut, oldErr }()
+	var stdout, stderr bytes.Buffer
+	rootStdout, rootStderr = &stdout, &stderr
+
+	stubConfirmTerminal(t, true, "n\n")
+	calls := 0
+	code := callTool(destructiveToolClient(&calls), "github", "delete-repo", []string{"--repo=x"}, "", false)
+	if code != ipc.ExitUsageErr {
+		t.Fatalf("callTool() = %d, want %d", code, ipc.ExitUsageErr)
+	}
+	if calls != 0 {
+		t.Fatalf("call_tool requests = %d, want 0 after declining", calls)
+	}
+	if !strings.Contains(stderr.String(), "Call destructive tool delete-repo on github? [y/N]") {
+		t.Fatalf("stderr = %q, want confirmation prompt", stderr.String())
+	}
+
+	stubConfirmTerminal(t, true, "y\n")
+	stdout.Reset()
+	if code := callTool(destructiveToolClient(&calls), "github", "delete-repo", []string{"--repo=x"}, "", false); code != ipc.ExitOK {
+		t.Fatalf("callTool(confirmed) = %d, want %d", code, ipc.ExitOK)
+	}
+	if calls != 1 || stdout.String() != "deleted\n" {
+		t.Fatalf("calls = %d stdout = %q, want one call with output", calls, stdout.String())
+	}
+}
+
+func TestCallToolYesSkipsDestructiveConfirmation(t *testing.T) {
+	oldOut, oldErr := rootStdout, rootStderr
+	defer func() { rootStdout, rootStderr = oldOut, oldErr }()
+	var stdout, stderr bytes.Buffer
+	rootStdout, rootStderr = &stdout, &stderr
+
+	stubConfirmTerminal(t, true, "")
+	calls := 0
+	if code := callTool(destructiveToolClient(&calls), "github", "delete-repo", []string{"--yes"}, "", false); code != ipc.ExitOK {
+		t.Fatalf("callTool() = %d, want %d", code, ipc.ExitOK)
+	}
+	if calls != 1 {
+		t.Fatalf("call_tool requests = %d, want 1", calls)
+	}
+	if strings.Contains(stderr.String(), "[y/N]") {
+		t.Fatalf("stderr = %q, want no prompt", stderr.String())
+	}
+}
+
+func TestCallToolDestructiveToolOffTerminalCallsWithoutPrompt(t *testing.T) {
+	oldOut, oldErr := rootStdout, rootStderr
+	defer func() { rootStdout, rootStderr = oldOut, oldErr }()
+	var stdout, stderr bytes.Buffer
+	rootStdout, rootStderr = &stdout, &stderr
+
+	stubConfirmTerminal(t, false, "")
+	calls := 0
+	if code := callTool(destructiveToolClient(&calls), "github", "delete-repo", []string{"--repo=x"}, "", false); code != ipc.ExitOK {
+		t.Fatalf("callTool() = %d, want %d", code, ipc.ExitOK)
+	}
+	if calls != 1 {
+		t.Fatalf("call_tool requests = %d, want 1", calls)
+	}
+
+	code := callTool(destructiveToolClient(&calls), "github", "delete-repo", []string{"--confirm"}, "", false)
+	if code != ipc.ExitUsageErr {
+		t.Fatalf("callTool(--confirm) = %d, want %d", code, ipc.ExitUsageErr)
+	}
+	if !strings.Contains(stderr.String(), "pass --yes to proceed") {
+		t.Fatalf("stderr = %q, want --yes hint", stderr.String())
+	}
+}
+
+func TestPrintToolHelpShowsAnnotationBadges(t *testing.T) {
+	var out bytes.Buffer
+	printToolHelp(&out, "github", "delete-repo", "", map[string]any{"type": "object"}, nil, parseToolAnnotations([]byte(destructiveToolSchema)))
+	if !strings.Contains(out.String(), "Annotations: destructive") {
+		t.Fatalf("help output missing destructive badge:\n%s", out.String())
+	}
+}
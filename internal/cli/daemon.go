@@ -0,0 +1,163 @@
+package cli
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/lydakis/mcpx/internal/config"
+	"github.com/lydakis/mcpx/internal/daemon"
+	"github.com/lydakis/mcpx/internal/ipc"
+)
+
+var (
+	runDaemonFn     = daemon.RunWithOptions
+	connectDaemonFn = daemon.Connect
+)
+
+func maybeHandleDaemonCommand(args []string, cfg *config.Config, stdout, stderr io.Writer) (bool, int) {
+	if len(args) == 0 || args[0] != "daemon" {
+		return false, 0
+	}
+
+	if cfg != nil {
+		if _, ok := cfg.Servers["daemon"]; ok {
+			return false, 0
+		}
+	}
+
+	return true, runDaemonCommand(args[1:], stdout, stderr)
+}
+
+func runDaemonCommand(args []string, stdout, stderr io.Writer) int {
+	if len(args) == 0 || args[0] == "help" || isHelpFlag(args[0]) {
+		printDaemonHelp(stdout)
+		return ipc.ExitOK
+	}
+
+	switch args[0] {
+	case "reload":
+		return runDaemonReloadCommand(args[1:], stdout, stderr)
+	case "run":
+		return runDaemonRunCommand(args[1:], stdout, stderr)
+	case "status":
+		return runDaemonStatusCommand(args[1:], stdout, stderr)
+	default:
+		fmt.Fprintf(stderr, "mcpx: unknown daemon command: %s\n", args[0])
+		printDaemonHelp(stderr)
+		return ipc.ExitUsageErr
+	}
+}
+
+func runDaemonReloadCommand(args []string, stdout, stderr io.Writer) int {
+	if len(args) > 0 {
+		if isHelpFlag(args[0]) {
+			printDaemonHelp(stdout)
+			return ipc.ExitOK
+		}
+		fmt.Fprintf(stderr, "mcpx: daemon reload: unexpected argument: %s\n", args[0])
+		return ipc.ExitUsageErr
+	}
+
+	// A daemon that is not running has nothing to reload; the next one
+	// started reads config fresh anyway.
+	socket := ipc.SocketPath()
+	nonce, err := connectDaemonFn()
+	if errors.Is(err, daemon.ErrNotRunning) {
+		fmt.Fprintf(stderr, "mcpx: daemon not running (no daemon listening on %s)\n", socket)
+		return ipc.ExitToolErr
+	}
+	if err != nil {
+		fmt.Fprintf(stderr, "mcpx: %v\n", err)
+		return ipc.ExitInternal
+	}
+	client := newDaemonClient(socket, nonce)
+	resp, err := client.Send(&ipc.Request{Type: "reload", CWD: callerWorkingDirectory()})
+	if err != nil {
+		fmt.Fprintf(stderr, "mcpx: %v\n", err)
+		return ipc.ExitInternal
+	}
+	if resp.ExitCode != ipc.ExitOK {
+		if resp.Stderr != "" {
+			fmt.Fprintf(stderr, "mcpx: %s\n", resp.Stderr)
+		}
+		return resp.ExitCode
+	}
+	stdout.Write(resp.Content) //nolint:errcheck
+	return ipc.ExitOK
+}
+
+// runDaemonRunCommand serves requests in this process until SIGINT/SIGTERM,
+// for supervisors that manage the daemon lifecycle themselves.
+func runDaemonRunCommand(args []string, stdout, stderr io.Writer) int {
+	opts := daemon.RunOptions{Foreground: true, LogFormat: daemon.LogFormatText}
+	for i := 0; i < len(args); i++ {
+		arg := args[i]
+		name, value, hasValue := strings.Cut(arg, "=")
+		switch name {
+		case "-h", "--help":
+			printDaemonHelp(stdout)
+			return ipc.ExitOK
+		case "--foreground":
+			// Accepted for explicitness; daemon run never detaches.
+			if hasValue {
+				fmt.Fprintf(stderr, "mcpx: daemon run: --foreground does not take a value\n")
+				return ipc.ExitUsageErr
+			}
+			continue
+		case "--log-format", "--log-level":
+		default:
+			fmt.Fprintf(stderr, "mcpx: daemon run: unexpected argument: %s\n", arg)
+			return ipc.ExitUsageErr
+		}
+
+		if !hasValue {
+			if i+1 >= len(args) {
+				fmt.Fprintf(stderr, "mcpx: daemon run: missing value for %s\n", name)
+				return ipc.ExitUsageErr
+			}
+			i++
+			value = args[i]
+		}
+		if name == "--log-format" {
+			switch value {
+			case daemon.LogFormatText, daemon.LogFormatJSON:
+				opts.LogFormat = value
+			default:
+				fmt.Fprintf(stderr, "mcpx: daemon run: invalid --log-format %q (want text or json)\n", value)
+				return ipc.ExitUsageErr
+			}
+			continue
+		}
+		level, err := daemon.ParseLogLevel(value)
+		if err != nil {
+			fmt.Fprintf(stderr, "mcpx: daemon run: %v\n", err)
+			return ipc.ExitUsageErr
+		}
+		opts.LogLevel = level
+	}
+
+	if err := runDaemonFn(opts); err != nil {
+		fmt.Fprintf(stderr, "mcpx daemon: %v\n", err)
+		return ipc.ExitInternal
+	}
+	return ipc.ExitOK
+}
+
+func printDaemonHelp(out io.Writer) {
+	fmt.Fprintln(out, "Usage:")
+	fmt.Fprintln(out, "  mcpx daemon reload")
+	fmt.Fprintln(out, "  mcpx daemon status [--json]")
+	fmt.Fprintln(out, "  mcpx daemon run [--foreground] [--log-format text|json] [--log-level debug|info|warn|error]")
+	fmt.Fprintln(out, "")
+	fmt.Fprintln(out, "Commands:")
+	fmt.Fprintln(out, "  reload     Reload and validate config for the current directory.")
+	fmt.Fprintln(out, "             Server connections are reset only if the config changed.")
+	fmt.Fprintln(out, "  status     Show whether a daemon is running, its PID, socket, uptime, config")
+	fmt.Fprintln(out, "             fingerprint, and server connections. Never starts a daemon;")
+	fmt.Fprintln(out, "             exits 1 when none is running.")
+	fmt.Fprintln(out, "  run        Run the daemon in the foreground until SIGINT/SIGTERM (for systemd,")
+	fmt.Fprintln(out, "             supervisord, and similar). It does not exit when servers go idle;")
+	fmt.Fprintln(out, "             CLI calls connect to it instead of spawning their own daemon.")
+}
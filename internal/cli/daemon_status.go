@@ -0,0 +1,129 @@
+package cli
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/lydakis/mcpx/internal/daemon"
+	"github.com/lydakis/mcpx/internal/ipc"
+)
+
+// daemonStatusPayload mirrors the daemon's status response. Running is set
+// by the CLI, so `--json` reports a missing daemon in the same shape.
+type daemonStatusPayload struct {
+	Running           bool                 `json:"running"`
+	PID               int                  `json:"pid,omitempty"`
+	Socket            string               `json:"socket"`
+	StartedAt         string               `json:"started_at,omitempty"`
+	UptimeSeconds     int64                `json:"uptime_seconds,omitempty"`
+	ConfigFingerprint string               `json:"config_fingerprint,omitempty"`
+	ActiveCWD         string               `json:"active_cwd,omitempty"`
+	Connections       int                  `json:"connections"`
+	Servers           []daemonServerStatus `json:"servers,omitempty"`
+}
+
+type daemonServerStatus struct {
+	Name        string `json:"name"`
+	State       string `json:"state"`
+	Connections int    `json:"connections"`
+}
+
+func runDaemonStatusCommand(args []string, stdout, stderr io.Writer) int {
+	jsonOut := false
+	for _, arg := range args {
+		switch {
+		case isHelpFlag(arg):
+			printDaemonHelp(stdout)
+			return ipc.ExitOK
+		case arg == "--json":
+			jsonOut = true
+		default:
+			fmt.Fprintf(stderr, "mcpx: daemon status: unexpected argument: %s\n", arg)
+			return ipc.ExitUsageErr
+		}
+	}
+
+	socket := ipc.SocketPath()
+	nonce, err := connectDaemonFn()
+	if errors.Is(err, daemon.ErrNotRunning) {
+		if jsonOut {
+			return writeDaemonStatusJSON(stdout, stderr, daemonStatusPayload{Socket: socket}, ipc.ExitToolErr)
+		}
+		fmt.Fprintf(stdout, "mcpx daemon is not running (no daemon listening on %s)\n", socket)
+		return ipc.ExitToolErr
+	}
+	if err != nil {
+		fmt.Fprintf(stderr, "mcpx: %v\n", err)
+		return ipc.ExitInternal
+	}
+
+	resp, err := newDaemonClient(socket, nonce).Send(&ipc.Request{Type: "status"})
+	if err != nil {
+		fmt.Fprintf(stderr, "mcpx: %v\n", err)
+		return ipc.ExitInternal
+	}
+	if resp.ExitCode != ipc.ExitOK {
+		if resp.Stderr != "" {
+			fmt.Fprintf(stderr, "mcpx: %s\n", resp.Stderr)
+		}
+		return resp.ExitCode
+	}
+
+	var status daemonStatusPayload
+	if err := json.Unmarshal(resp.Content, &status); err != nil {
+		fmt.Fprintf(stderr, "mcpx: decoding daemon status: %v\n", err)
+		return ipc.ExitInternal
+	}
+	status.Running = true
+	if jsonOut {
+		return writeDaemonStatusJSON(stdout, stderr, status, ipc.ExitOK)
+	}
+	writeDaemonStatusText(stdout, status)
+	return ipc.ExitOK
+}
+
+func writeDaemonStatusJSON(stdout, stderr io.Writer, status daemonStatusPayload, code int) int {
+	data, err := json.Marshal(status)
+	if err != nil {
+		fmt.Fprintf(stderr, "mcpx: encoding daemon status: %v\n", err)
+		return ipc.ExitInternal
+	}
+	stdout.Write(append(data, '\n')) //nolint:errcheck
+	return code
+}
+
+func writeDaemonStatusText(out io.Writer, status daemonStatusPayload) {
+	fingerprint := status.ConfigFingerprint
+	if len(fingerprint) > 12 {
+		fingerprint = fingerprint[:12]
+	}
+	cwd := strings.TrimSpace(status.ActiveCWD)
+	if cwd == "" {
+		cwd = "-"
+	}
+
+	fmt.Fprintf(out, "mcpx daemon is running (pid %d)\n", status.PID)
+	tw := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+	fmt.Fprintf(tw, "socket:\t%s\n", status.Socket)
+	fmt.Fprintf(tw, "uptime:\t%s\n", time.Duration(status.UptimeSeconds)*time.Second)
+	fmt.Fprintf(tw, "config:\t%s\n", fingerprint)
+	fmt.Fprintf(tw, "active cwd:\t%s\n", cwd)
+	fmt.Fprintf(tw, "connections:\t%d\n", status.Connections)
+	tw.Flush() //nolint:errcheck
+
+	if len(status.Servers) == 0 {
+		return
+	}
+	fmt.Fprintln(out, "servers:")
+	tw = tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "  NAME\tSTATE\tCONNECTIONS")
+	for _, server := range status.Servers {
+		fmt.Fprintf(tw, "  %s\t%s\t%d\n", server.Name, server.State, server.Connections)
+	}
+	tw.Flush() //nolint:errcheck
+}
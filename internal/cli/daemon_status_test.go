@@ -0,0 +1,101 @@
+package cli
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/lydakis/mcpx/internal/daemon"
+	"github.com/lydakis/mcpx/internal/ipc"
+)
+
+func stubDaemonStatus(t *testing.T, connectErr error, resp *ipc.Response) *[]*ipc.Request {
+	t.Helper()
+
+	oldConnect := connectDaemonFn
+	oldSpawn := spawnOrConnectFn
+	oldClient := newDaemonClient
+	t.Cleanup(func() {
+		connectDaemonFn = oldConnect
+		spawnOrConnectFn = oldSpawn
+		newDaemonClient = oldClient
+	})
+
+	var sent []*ipc.Request
+	connectDaemonFn = func() (string, error) { return "nonce", connectErr }
+	spawnOrConnectFn = func() (string, error) {
+		t.Fatal("daemon status spawned a daemon")
+		return "", nil
+	}
+	newDaemonClient = func(_, _ string) daemonRequester {
+		return stubDaemonClient{sendFn: func(req *ipc.Request) (*ipc.Response, error) {
+			sent = append(sent, req)
+			return resp, nil
+		}}
+	}
+	return &sent
+}
+
+const daemonStatusFixture = `{"pid":4242,"socket":"/run/mcpx.sock","started_at":"2026-05-10T12:00:00Z","uptime_seconds":3723,"config_fingerprint":"0123456789abcdef","active_cwd":"/tmp/project","connections":2,"servers":[{"name":"github","state":"connected","connections":2},{"name":"linear","state":"never","connections":0}]}`
+
+func TestRunDaemonStatusPrintsSummary(t *testing.T) {
+	sent := stubDaemonStatus(t, nil, &ipc.Response{Content: []byte(daemonStatusFixture)})
+
+	var stdout, stderr bytes.Buffer
+	if code := runDaemonCommand([]string{"status"}, &stdout, &stderr); code != ipc.ExitOK {
+		t.Fatalf("runDaemonCommand(status) = %d, want %d (stderr=%q)", code, ipc.ExitOK, stderr.String())
+	}
+	if len(*sent) != 1 || (*sent)[0].Type != "status" {
+		t.Fatalf("sent requests = %#v, want one status request", *sent)
+	}
+	out := stdout.String()
+	for _, want := range []string{
+		"mcpx daemon is running (pid 4242)",
+		"uptime:       1h2m3s",
+		"config:       0123456789ab\n",
+		"active cwd:   /tmp/project",
+		"connections:  2",
+		"  github  connected  2",
+	} {
+		if !strings.Contains(out, want) {
+			t.Fatalf("stdout = %q, want %q", out, want)
+		}
+	}
+}
+
+func TestRunDaemonStatusJSON(t *testing.T) {
+	stubDaemonStatus(t, nil, &ipc.Response{Content: []byte(daemonStatusFixture)})
+
+	var stdout, stderr bytes.Buffer
+	if code := runDaemonCommand([]string{"status", "--json"}, &stdout, &stderr); code != ipc.ExitOK {
+		t.Fatalf("runDaemonCommand(status --json) = %d, want %d (stderr=%q)", code, ipc.ExitOK, stderr.String())
+	}
+	var got daemonStatusPayload
+	if err := json.Unmarshal(stdout.Bytes(), &got); err != nil {
+		t.Fatalf("unmarshal stdout %q: %v", stdout.String(), err)
+	}
+	if !got.Running || got.PID != 4242 || got.ConfigFingerprint != "0123456789abcdef" || len(got.Servers) != 2 {
+		t.Fatalf("status = %+v, want running daemon with pid 4242 and two servers", got)
+	}
+}
+
+func TestRunDaemonStatusReportsNotRunning(t *testing.T) {
+	sent := stubDaemonStatus(t, daemon.ErrNotRunning, nil)
+
+	var stdout, stderr bytes.Buffer
+	if code := runDaemonCommand([]string{"status"}, &stdout, &stderr); code != ipc.ExitToolErr {
+		t.Fatalf("runDaemonCommand(status) = %d, want %d", code, ipc.ExitToolErr)
+	}
+	if !strings.Contains(stdout.String(), "mcpx daemon is not running") || len(*sent) != 0 {
+		t.Fatalf("stdout = %q sent = %d, want not-running message and no request", stdout.String(), len(*sent))
+	}
+
+	stdout.Reset()
+	if code := runDaemonCommand([]string{"status", "--json"}, &stdout, &stderr); code != ipc.ExitToolErr {
+		t.Fatalf("runDaemonCommand(status --json) = %d, want %d", code, ipc.ExitToolErr)
+	}
+	if !strings.HasPrefix(stdout.String(), `{"running":false,"socket":`) {
+		t.Fatalf("stdout = %q, want running:false JSON", stdout.String())
+	}
+}
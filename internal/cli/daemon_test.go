@@ -0,0 +1,123 @@
+package cli
+
+import (
+	"bytes"
+	"log/slog"
+	"strings"
+	"testing"
+
+	"github.com/lydakis/mcpx/internal/daemon"
+	"github.com/lydakis/mcpx/internal/ipc"
+)
+
+func stubDaemonReload(t *testing.T, resp *ipc.Response) *[]*ipc.Request {
+	t.Helper()
+
+	oldConnect := connectDaemonFn
+	oldSpawn := spawnOrConnectFn
+	oldClient := newDaemonClient
+	t.Cleanup(func() {
+		connectDaemonFn = oldConnect
+		spawnOrConnectFn = oldSpawn
+		newDaemonClient = oldClient
+	})
+
+	var sent []*ipc.Request
+	connectDaemonFn = func() (string, error) { return "nonce", nil }
+	spawnOrConnectFn = func() (string, error) { return "nonce", nil }
+	newDaemonClient = func(_, _ string) daemonRequester {
+		return stubDaemonClient{
+			sendFn: func(req *ipc.Request) (*ipc.Response, error) {
+				sent = append(sent, req)
+				return resp, nil
+			},
+		}
+	}
+	return &sent
+}
+
+func TestRunDaemonReloadSendsReloadRequest(t *testing.T) {
+	sent := stubDaemonReload(t, &ipc.Response{Content: []byte("config reloaded (unchanged)\n")})
+
+	var stdout, stderr bytes.Buffer
+	code := runDaemonCommand([]string{"reload"}, &stdout, &stderr)
+	if code != ipc.ExitOK {
+		t.Fatalf("runDaemonCommand(reload) = %d, want %d (stderr=%q)", code, ipc.ExitOK, stderr.String())
+	}
+	if len(*sent) != 1 || (*sent)[0].Type != "reload" {
+		t.Fatalf("sent requests = %#v, want one reload request", *sent)
+	}
+	if (*sent)[0].CWD != callerWorkingDirectory() {
+		t.Fatalf("reload cwd = %q, want %q", (*sent)[0].CWD, callerWorkingDirectory())
+	}
+	if stdout.String() != "config reloaded (unchanged)\n" {
+		t.Fatalf("stdout = %q, want daemon message", stdout.String())
+	}
+}
+
+func TestRunDaemonReloadReportsValidationError(t *testing.T) {
+	stubDaemonReload(t, &ipc.Response{ExitCode: ipc.ExitUsageErr, Stderr: "reloading config: invalid config: servers.x: missing transport"})
+
+	var stdout, stderr bytes.Buffer
+	code := runDaemonCommand([]string{"reload"}, &stdout, &stderr)
+	if code != ipc.ExitUsageErr {
+		t.Fatalf("runDaemonCommand(reload) = %d, want %d", code, ipc.ExitUsageErr)
+	}
+	if !strings.Contains(stderr.String(), "missing transport") {
+		t.Fatalf("stderr = %q, want validation error", stderr.String())
+	}
+	if stdout.Len() != 0 {
+		t.Fatalf("stdout = %q, want empty", stdout.String())
+	}
+}
+
+func TestRunDaemonReloadDoesNotStartDaemon(t *testing.T) {
+	sent := stubDaemonStatus(t, daemon.ErrNotRunning, nil)
+
+	var stdout, stderr bytes.Buffer
+	code := runDaemonCommand([]string{"reload"}, &stdout, &stderr)
+	if code != ipc.ExitToolErr {
+		t.Fatalf("runDaemonCommand(reload) = %d, want %d", code, ipc.ExitToolErr)
+	}
+	if len(*sent) != 0 {
+		t.Fatalf("sent requests = %#v, want none", *sent)
+	}
+	if !strings.Contains(stderr.String(), "daemon not running") {
+		t.Fatalf("stderr = %q, want not-running message", stderr.String())
+	}
+}
+
+func TestRunDaemonCommandRejectsUnknownSubcommand(t *testing.T) {
+	var stdout, stderr bytes.Buffer
+	if code := runDaemonCommand([]string{"restart"}, &stdout, &stderr); code != ipc.ExitUsageErr {
+		t.Fatalf("runDaemonCommand(restart) = %d, want %d", code, ipc.ExitUsageErr)
+	}
+	if !strings.Contains(stderr.String(), "unknown daemon command") {
+		t.Fatalf("stderr = %q, want unknown command message", stderr.String())
+	}
+}
+
+func TestRunDaemonRunPassesForegroundLogOptions(t *testing.T) {
+	oldRun := runDaemonFn
+	t.Cleanup(func() { runDaemonFn = oldRun })
+
+	var got daemon.RunOptions
+	runDaemonFn = func(opts daemon.RunOptions) error {
+		got = opts
+		return nil
+	}
+
+	var stdout, stderr bytes.Buffer
+	code := runDaemonCommand([]string{"run", "--foreground", "--log-format=json", "--log-level", "warn"}, &stdout, &stderr)
+	if code != ipc.ExitOK {
+		t.Fatalf("runDaemonCommand(run) = %d, want %d (stderr=%q)", code, ipc.ExitOK, stderr.String())
+	}
+	want := daemon.RunOptions{Foreground: true, LogFormat: daemon.LogFormatJSON, LogLevel: slog.LevelWarn}
+	if got != want {
+		t.Fatalf("run options = %#v, want %#v", got, want)
+	}
+
+	if code := runDaemonCommand([]string{"run", "--log-format=xml"}, &stdout, &stderr); code != ipc.ExitUsageErr {
+		t.Fatalf("runDaemonCommand(run --log-format=xml) = %d, want %d", code, ipc.ExitUsageErr)
+	}
+}
@@ -0,0 +1,38 @@
+package cli
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/lydakis/mcpx/internal/ipc"
+)
+
+// printDryRun asks the daemon how a call would be routed (backend server,
+// resolved tool name, final arguments, cache TTL) and prints that instead of
+// calling the tool.
+func printDryRun(client daemonRequester, server, tool string, argsJSON json.RawMessage, cwd string, parsed *toolCallArgs, canonicalizeSource bool) int {
+	resp, err := sendServerRequestWithEphemeralFallback(client, &ipc.Request{
+		Type:           "resolve_call",
+		Server:         server,
+		Tool:           tool,
+		Args:           argsJSON,
+		Cache:          parsed.cacheTTL,
+		IdempotencyKey: parsed.idempotencyKey,
+		CWD:            cwd,
+	}, canonicalizeSource)
+	if err != nil {
+		fmt.Fprintf(rootStderr, "mcpx: %v\n", err)
+		return ipc.ExitInternal
+	}
+	if resp.ExitCode != ipc.ExitOK {
+		if resp.Stderr != "" {
+			fmt.Fprintln(rootStderr, resp.Stderr)
+		}
+		return resp.ExitCode
+	}
+	if err := writePayload(rootStdout, "dry run", resp.Content); err != nil {
+		fmt.Fprintf(rootStderr, "mcpx: %v\n", err)
+		return ipc.ExitInternal
+	}
+	return ipc.ExitOK
+}
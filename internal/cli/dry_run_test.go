@@ -0,0 +1,62 @@
+package cli
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/lydakis/mcpx/internal/ipc"
+)
+
+func TestCallToolDryRunPrintsResolvedCallWithoutCalling(t *testing.T) {
+	oldOut, oldErr := rootStdout, rootStderr
+	defer func() { rootStdout, rootStderr = oldOut, oldErr }()
+	var stdout, stderr bytes.Buffer
+	rootStdout, rootStderr = &stdout, &stderr
+
+	payload := []byte("{\n  \"server\": \"github\",\n  \"backend\": \"github\",\n  \"tool\": \"search\",\n  \"args\": {\"query\":\"mcp\"}\n}\n")
+	var types []string
+	var got *ipc.Request
+	client := stubDaemonClient{sendFn: func(req *ipc.Request) (*ipc.Response, error) {
+		types = append(types, req.Type)
+		got = req
+		return &ipc.Response{Content: payload}, nil
+	}}
+
+	if code := callTool(client, "github", "search", []string{"--dry-run", "--query=mcp", "--cache=30s"}, "/work", false); code != ipc.ExitOK {
+		t.Fatalf("callTool(--dry-run) = %d, want %d (stderr=%q)", code, ipc.ExitOK, stderr.String())
+	}
+	if len(types) != 1 || types[0] != "resolve_call" {
+		t.Fatalf("request types = %v, want only resolve_call", types)
+	}
+	if string(got.Args) != `{"query":"mcp"}` || got.Cache == nil || got.CWD != "/work" {
+		t.Fatalf("resolve_call request = %#v, want args, cache TTL, and cwd forwarded", got)
+	}
+	if stdout.String() != string(payload) {
+		t.Fatalf("stdout = %q, want resolved call payload", stdout.String())
+	}
+}
+
+func TestCallToolHelpWinsOverDryRun(t *testing.T) {
+	oldOut, oldErr := rootStdout, rootStderr
+	defer func() { rootStdout, rootStderr = oldOut, oldErr }()
+	var stdout, stderr bytes.Buffer
+	rootStdout, rootStderr = &stdout, &stderr
+
+	var types []string
+	client := stubDaemonClient{sendFn: func(req *ipc.Request) (*ipc.Response, error) {
+		types = append(types, req.Type)
+		return &ipc.Response{Content: []byte(`{"name":"search","input_schema":{"type":"object"}}`)}, nil
+	}}
+
+	if code := callTool(client, "github", "search", []string{"--dry-run", "--help"}, "", false); code != ipc.ExitOK {
+		t.Fatalf("callTool(--dry-run --help) = %d, want %d (stderr=%q)", code, ipc.ExitOK, stderr.String())
+	}
+	for _, typ := range types {
+		if typ == "resolve_call" {
+			t.Fatalf("request types = %v, want help without resolve_call", types)
+		}
+	}
+	if !bytes.Contains(stdout.Bytes(), []byte("search")) {
+		t.Fatalf("stdout = %q, want tool help", stdout.String())
+	}
+}
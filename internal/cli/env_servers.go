@@ -0,0 +1,31 @@
+package cli
+
+import (
+	"os"
+
+	"github.com/lydakis/mcpx/internal/config"
+	"github.com/lydakis/mcpx/internal/ipc"
+)
+
+// envServersClient sends the caller's MCPX_SERVER_<NAME>_* servers with
+// every request. A running daemon keeps the environment it was spawned
+// with, so without them a new or changed env definition would be unknown
+// to it or served stale.
+type envServersClient struct {
+	daemonRequester
+}
+
+func withEnvServers(client daemonRequester) daemonRequester {
+	return envServersClient{daemonRequester: client}
+}
+
+func (c envServersClient) Send(req *ipc.Request) (*ipc.Response, error) {
+	if req != nil && req.EnvServers == nil {
+		// config.Load already rejected invalid definitions; on error the
+		// daemon keeps the set it has.
+		if servers, err := config.ExpandedServersFromEnv(os.Environ()); err == nil {
+			req.EnvServers = servers
+		}
+	}
+	return c.daemonRequester.Send(req)
+}
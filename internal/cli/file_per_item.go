@@ -0,0 +1,155 @@
+package cli
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/lydakis/mcpx/internal/ipc"
+)
+
+// maxItemFileName caps the length of a file name taken from --name-field.
+const maxItemFileName = 200
+
+func parseNameField(raw string) ([]any, error) {
+	raw = strings.TrimSpace(raw)
+	if raw == "" {
+		return nil, fmt.Errorf("missing value for --name-field")
+	}
+	path, err := parseResultPath(raw)
+	if err != nil {
+		return nil, fmt.Errorf("invalid --name-field %q: %w", raw, err)
+	}
+	if len(path) == 0 {
+		return nil, fmt.Errorf("invalid --name-field %q: path must name a field", raw)
+	}
+	return path, nil
+}
+
+// itemFileNames picks a file name for each item: the sanitized string or
+// number at namePath, or the item's index when there is no such value.
+// Repeated names get a -2, -3, ... suffix so no item overwrites another.
+func itemFileNames(items []any, namePath []any) []string {
+	names := make([]string, len(items))
+	used := make(map[string]bool, len(items))
+	for i, item := range items {
+		base := ""
+		if len(namePath) > 0 {
+			if value, ok := lookupResultPath(item, namePath); ok {
+				base = sanitizeItemFileName(value)
+			}
+		}
+		if base == "" {
+			base = strconv.Itoa(i)
+		}
+		name := base
+		for n := 2; used[strings.ToLower(name)]; n++ {
+			name = base + "-" + strconv.Itoa(n)
+		}
+		used[strings.ToLower(name)] = true
+		names[i] = name + ".json"
+	}
+	return names
+}
+
+// sanitizeItemFileName turns a string or number into a file name with no
+// path separators: characters other than letters, digits, '.', '-', and
+// '_' become '_', and leading dots are dropped so names cannot be hidden
+// files or "..".
+func sanitizeItemFileName(value any) string {
+	var raw string
+	switch v := value.(type) {
+	case string:
+		raw = v
+	case json.Number:
+		raw = v.String()
+	default:
+		return ""
+	}
+
+	var b strings.Builder
+	for _, r := range strings.TrimSpace(raw) {
+		switch {
+		case r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z', r >= '0' && r <= '9', r == '.', r == '-', r == '_':
+			b.WriteRune(r)
+		default:
+			b.WriteByte('_')
+		}
+	}
+	name := strings.TrimLeft(b.String(), ".")
+	if len(name) > maxItemFileName {
+		name = name[:maxItemFileName]
+	}
+	return name
+}
+
+// arrayResultItems returns the elements of a top-level JSON array result.
+func arrayResultItems(content []byte) ([]any, error) {
+	dec := json.NewDecoder(bytes.NewReader(content))
+	dec.UseNumber()
+	var doc any
+	if err := dec.Decode(&doc); err != nil {
+		return nil, fmt.Errorf("--output-file-per-item requires a JSON result: %w", err)
+	}
+	if dec.More() {
+		return nil, fmt.Errorf("--output-file-per-item requires a single JSON document")
+	}
+	items, ok := doc.([]any)
+	if !ok {
+		return nil, fmt.Errorf("--output-file-per-item requires a JSON array result")
+	}
+	return items, nil
+}
+
+// writeItemFiles writes each item as indented JSON to its own file in dir,
+// creating dir if needed.
+func writeItemFiles(items []any, dir string, namePath []any) error {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return fmt.Errorf("creating --output-file-per-item directory: %w", err)
+	}
+	for i, name := range itemFileNames(items, namePath) {
+		var buf bytes.Buffer
+		enc := json.NewEncoder(&buf)
+		enc.SetEscapeHTML(false)
+		enc.SetIndent("", "  ")
+		if err := enc.Encode(items[i]); err != nil {
+			return fmt.Errorf("encoding item %d: %w", i, err)
+		}
+		if err := os.WriteFile(filepath.Join(dir, name), buf.Bytes(), 0o644); err != nil {
+			return fmt.Errorf("writing item %d: %w", i, err)
+		}
+	}
+	return nil
+}
+
+func writeFilePerItemResponse(resp *ipc.Response, parsed *toolCallArgs) int {
+	items, err := arrayResultItems(resp.Content)
+	if resp.ContentType != "" && resp.ContentType != "application/json" {
+		err = fmt.Errorf("--output-file-per-item requires a JSON result, got %s (%s)", resp.ContentType, resp.Encoding)
+	}
+	if err != nil {
+		if !parsed.quiet {
+			fmt.Fprintf(rootStderr, "mcpx: %v\n", err)
+		}
+		return ipc.ExitUsageErr
+	}
+	if !parsed.quiet && resp.Stderr != "" {
+		fmt.Fprintln(rootStderr, resp.Stderr)
+	}
+	if err := writeItemFiles(items, parsed.outputDir, parsed.namePath); err != nil {
+		fmt.Fprintf(rootStderr, "mcpx: %v\n", err)
+		return ipc.ExitInternal
+	}
+	if !parsed.quiet {
+		noun := "files"
+		if len(items) == 1 {
+			noun = "file"
+		}
+		fmt.Fprintf(rootStdout, "Wrote %d %s to %s\n", len(items), noun, parsed.outputDir)
+	}
+	return ipc.ExitOK
+}
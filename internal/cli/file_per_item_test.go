@@ -0,0 +1,94 @@
+package cli
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"testing"
+
+	"github.com/lydakis/mcpx/internal/ipc"
+)
+
+func TestCallToolOutputFilePerItemWritesEachElement(t *testing.T) {
+	oldOut, oldErr := rootStdout, rootStderr
+	defer func() { rootStdout, rootStderr = oldOut, oldErr }()
+	var stdout, stderr bytes.Buffer
+	rootStdout, rootStderr = &stdout, &stderr
+	stubConfirmTerminal(t, false, "")
+
+	client := stubDaemonClient{sendFn: func(req *ipc.Request) (*ipc.Response, error) {
+		return &ipc.Response{Content: []byte(`[
+  {"slug": "getting-started", "body": "a"},
+  {"slug": "../../etc/passwd", "body": "b"},
+  {"slug": "getting-started", "body": "c"},
+  {"body": "d"}
+]` + "\n")}, nil
+	}}
+
+	dir := filepath.Join(t.TempDir(), "docs")
+	args := []string{"--output-file-per-item", dir, "--name-field", "slug"}
+	if code := callTool(client, "svc", "export", args, "", false); code != ipc.ExitOK {
+		t.Fatalf("callTool() = %d, want %d (stderr=%q)", code, ipc.ExitOK, stderr.String())
+	}
+	if want := "Wrote 4 files to " + dir + "\n"; stdout.String() != want {
+		t.Fatalf("stdout = %q, want %q", stdout.String(), want)
+	}
+
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		t.Fatalf("ReadDir() error = %v", err)
+	}
+	var names []string
+	for _, entry := range entries {
+		names = append(names, entry.Name())
+	}
+	sort.Strings(names)
+	want := []string{"3.json", "_.._etc_passwd.json", "getting-started-2.json", "getting-started.json"}
+	if !reflect.DeepEqual(names, want) {
+		t.Fatalf("files = %q, want %q", names, want)
+	}
+	data, err := os.ReadFile(filepath.Join(dir, "getting-started-2.json"))
+	if err != nil {
+		t.Fatalf("ReadFile() error = %v", err)
+	}
+	if got := string(data); got != "{\n  \"body\": \"c\",\n  \"slug\": \"getting-started\"\n}\n" {
+		t.Fatalf("file content = %q", got)
+	}
+}
+
+func TestCallToolOutputFilePerItemRejectsNonArrayResult(t *testing.T) {
+	oldOut, oldErr := rootStdout, rootStderr
+	defer func() { rootStdout, rootStderr = oldOut, oldErr }()
+	var stdout, stderr bytes.Buffer
+	rootStdout, rootStderr = &stdout, &stderr
+	stubConfirmTerminal(t, false, "")
+
+	client := stubDaemonClient{sendFn: func(req *ipc.Request) (*ipc.Response, error) {
+		return &ipc.Response{Content: []byte(`{"items": []}`)}, nil
+	}}
+
+	dir := filepath.Join(t.TempDir(), "out")
+	if code := callTool(client, "svc", "export", []string{"--output-file-per-item=" + dir}, "", false); code != ipc.ExitUsageErr {
+		t.Fatalf("callTool() = %d, want %d", code, ipc.ExitUsageErr)
+	}
+	if _, err := os.Stat(dir); !os.IsNotExist(err) {
+		t.Fatalf("output dir stat err = %v, want not exist", err)
+	}
+}
+
+func TestParseToolCallArgsOutputFilePerItemValidation(t *testing.T) {
+	for _, args := range [][]string{
+		{"--output-file-per-item"},
+		{"--output-file-per-item="},
+		{"--name-field", "slug"},
+		{"--output-file-per-item", "out", "--name-field", "$"},
+		{"--output-file-per-item", "out", "--jsonl-field", "items"},
+		{"--output-file-per-item", "out", "--flatten"},
+	} {
+		if _, err := parseToolCallArgs(args, bytes.NewBuffer(nil), true, outputModeText); err == nil {
+			t.Fatalf("parseToolCallArgs(%q) error = nil, want non-nil", args)
+		}
+	}
+}
@@ -4,8 +4,13 @@ import (
 	"encoding/json"
 	"fmt"
 	"io"
+	"os"
+	"strconv"
 	"strings"
 	"time"
+
+	"github.com/lydakis/mcpx/internal/config"
+	"github.com/lydakis/mcpx/internal/httpheaders"
 )
 
 type toolCallArgs struct {
@@ -15,13 +20,160 @@ type toolCallArgs struct {
 	quiet    bool
 	help     bool
 	output   outputMode
+	onError  []string
+	softFail bool
+	// ignoreExit exits 0 after a call whatever its outcome, still writing
+	// output and errors as usual.
+	ignoreExit bool
+	// schemaDiff names a second server whose same-named tool schema is
+	// compared instead of calling the tool.
+	schemaDiff string
+	// paramDefaults are saved to config instead of calling the tool.
+	paramDefaults []paramDefault
+	// printCurl prints an equivalent curl command instead of calling.
+	printCurl bool
+	// timeout caps the whole call, in place of the server's request_timeout
+	// (0 means no limit); attemptTimeout caps each try, which is retried
+	// while timeout has budget left.
+	timeout        *time.Duration
+	attemptTimeout *time.Duration
+	// warnSlow prints a stderr warning when the call takes longer than
+	// this, measured around the daemon request.
+	warnSlow *time.Duration
+	// confirm always prompts before calling; yes skips the prompt that
+	// destructive tools otherwise get on a terminal.
+	confirm bool
+	yes     bool
+	// sampleOutput prints a sample document from the output schema instead
+	// of calling the tool.
+	sampleOutput bool
+	// schemaMarkdown prints the input and output schemas as Markdown tables
+	// instead of calling the tool.
+	schemaMarkdown bool
+	// dryRun prints how the call would be routed instead of calling.
+	dryRun bool
+	// examples prints the generated example invocations instead of calling.
+	examples bool
+	// requiredCheck lists which required parameters the flags leave unset
+	// instead of calling.
+	requiredCheck bool
+	// retryAfterRetries retries calls rejected with 429 + Retry-After, up to
+	// this many times, waiting as long as the server asked.
+	retryAfterRetries int
+	// retryBudget retries transient failures with backoff until this much
+	// time has passed.
+	retryBudget *time.Duration
+	// repeatUntil re-calls the tool every repeatInterval until the result
+	// matches, or fails once repeatMaxWait elapses.
+	repeatUntil    *repeatCondition
+	repeatInterval *time.Duration
+	repeatMaxWait  *time.Duration
+	// mapExit sets the exit code of a successful call from a field of its
+	// JSON result (--map-exit, --map-exit-rule).
+	mapExit *exitMapping
+	// redact replaces the values at these paths of a JSON result with "***"
+	// before printing.
+	redact []redactPath
+	// captureStderr forwards a stdio server's stderr for this call.
+	captureStderr bool
+	// rawBytes writes the result's single content block to stdout as raw
+	// bytes, with no newline or temp-file rendering.
+	rawBytes bool
+	// rawResult prints the whole CallToolResult as JSON, with content block
+	// types and metadata, instead of the unwrapped output (--raw).
+	rawResult bool
+	// contentOnly keeps only the structured content or only the text blocks
+	// of the result ("structured" or "text").
+	contentOnly string
+	// combineContent joins a multi-block result as "text" or "json"; empty
+	// keeps the newline join.
+	combineContent string
+	// flatten prints a JSON result as "path = value" lines, or as one flat
+	// object with --json.
+	flatten bool
+	// jsonlField streams the array at this path of a JSON result as one
+	// compact JSON value per line.
+	jsonlField string
+	jsonlPath  []any
+	// outputDir writes each element of a JSON array result to its own file
+	// in this directory, named by the value at namePath (--name-field).
+	outputDir string
+	nameField string
+	namePath  []any
+	// outputFile writes a successful result to this path instead of
+	// stdout, with a short confirmation on stderr.
+	outputFile string
+	// tee saves a successful result to this path while still printing it.
+	tee string
+	// outputEncoding re-encodes successful output as base64 or hex; empty
+	// or utf8 passes it through.
+	outputEncoding string
+	// progress prints the server's progress notifications to stderr.
+	progress bool
+	// headers are added to this call's HTTP requests: --headers-from-file
+	// entries, then --header flags over them.
+	headers map[string]string
+	// idempotencyKey is forwarded so servers can deduplicate retried writes.
+	idempotencyKey string
+	// saveAs names a recipe the call's server, tool, and arguments are
+	// saved under before calling (replayed with `mcpx run <name>`).
+	saveAs string
+	// stdinMerge reads a base object from stdin and applies tool flags on
+	// top of it, instead of stdin being used only when no flags are given.
+	stdinMerge bool
+	// noSchemaCache fetches the tool schema from the daemon instead of the
+	// on-disk schema cache, refreshing the cached copy.
+	noSchemaCache bool
+	// inputFromPrev is stdinMerge for chained calls: stdin holds the
+	// previous call's output, unwrapped from a CallToolResult envelope.
+	inputFromPrev bool
+	// stdinParam names the one tool parameter whose value is read from
+	// stdin (--<param>@-).
+	stdinParam string
+	// cacheScope overrides the config cache_scope ("global" or "cwd").
+	cacheScope string
+	// explainCache prints why the call was or was not served from cache.
+	explainCache bool
+	// cacheErrors overrides the server's cache_errors: true caches tool
+	// error results briefly (--cache-errors), false never does
+	// (--no-cache-for-errors).
+	cacheErrors *bool
+	// fresh skips the cache lookup but still stores the result (--fresh);
+	// staleOK accepts a cached entry up to that age (--stale-ok).
+	fresh   bool
+	staleOK *time.Duration
+	// argsTemplate is a JSON args file whose ${NAME} placeholders are filled
+	// from templateVars (--var) or the environment; flags apply on top.
+	argsTemplate string
+	templateVars map[string]string
+	// promptParams are read from the terminal with echo off just before
+	// calling, instead of from flags (--param-prompt).
+	promptParams []string
+	// paramFiles set object and array parameters from JSON files; they are
+	// checked against the tool schema before calling.
+	paramFiles []paramFileJSON
+}
+
+// isTextOutputFlag reports whether args[i] is a bare --text selecting text
+// output, overriding default_output. --text=<value> and --text followed by
+// a value still pass a "text" tool argument; a boolean "text" parameter
+// needs --text=true.
+func isTextOutputFlag(args []string, i int) bool {
+	if args[i] != "--text" {
+		return false
+	}
+	return i+1 >= len(args) || strings.HasPrefix(args[i+1], "-")
 }
 
-func parseToolCallArgs(args []string, stdin io.Reader, stdinIsTTY bool) (*toolCallArgs, error) {
+// parseToolCallArgs parses a tool call's flags. defaultOutput is the output
+// mode used unless --json, --yaml, or --text is given (default_output).
+func parseToolCallArgs(args []string, stdin io.Reader, stdinIsTTY bool, defaultOutput outputMode) (*toolCallArgs, error) {
 	parsed := &toolCallArgs{
 		toolArgs: make(map[string]any),
-		output:   outputModeText,
+		output:   defaultOutput,
 	}
+	outputSet, structuredSet := false, false
+	var fileHeaders, flagHeaders map[string]string
 
 	var positionalJSON string
 	hasToolFlags := false
@@ -49,8 +201,20 @@ func parseToolCallArgs(args []string, stdin io.Reader, stdinIsTTY bool) (*toolCa
 				parsed.help = true
 				hasAnyFlags = true
 				continue
-			case arg == "--json":
-				parsed.output = outputModeJSON
+			case arg == "--json" || arg == "--yaml":
+				mode := outputModeJSON
+				if arg == "--yaml" {
+					mode = outputModeYAML
+				}
+				if err := setStructuredOutput(&parsed.output, &structuredSet, mode); err != nil {
+					return nil, err
+				}
+				outputSet = true
+				hasAnyFlags = true
+				continue
+			case isTextOutputFlag(args, i):
+				parsed.output = outputModeText
+				outputSet, structuredSet = false, false
 				hasAnyFlags = true
 				continue
 			case strings.HasPrefix(arg, "--cache="):
@@ -79,6 +243,58 @@ func parseToolCallArgs(args []string, stdin io.Reader, stdinIsTTY bool) (*toolCa
 				parsed.cacheTTL = &ttl
 				hasAnyFlags = true
 				continue
+			case strings.HasPrefix(arg, "--cache-scope="):
+				scope, err := config.ParseCacheScope(strings.TrimPrefix(arg, "--cache-scope="))
+				if err != nil {
+					return nil, fmt.Errorf("--cache-scope: %w", err)
+				}
+				parsed.cacheScope = scope
+				hasAnyFlags = true
+				continue
+			case arg == "--cache-scope":
+				if i+1 >= len(args) {
+					return nil, fmt.Errorf("missing value for --cache-scope")
+				}
+				i++
+				scope, err := config.ParseCacheScope(args[i])
+				if err != nil {
+					return nil, fmt.Errorf("--cache-scope: %w", err)
+				}
+				parsed.cacheScope = scope
+				hasAnyFlags = true
+				continue
+			case arg == "--explain-cache":
+				parsed.explainCache = true
+				hasAnyFlags = true
+				continue
+			case arg == "--cache-errors" || arg == "--no-cache-for-errors":
+				enabled := arg == "--cache-errors"
+				if parsed.cacheErrors != nil && *parsed.cacheErrors != enabled {
+					return nil, fmt.Errorf("--cache-errors and --no-cache-for-errors cannot be combined")
+				}
+				parsed.cacheErrors = &enabled
+				hasAnyFlags = true
+				continue
+			case arg == "--fresh":
+				parsed.fresh = true
+				hasAnyFlags = true
+				continue
+			case arg == "--stale-ok" || strings.HasPrefix(arg, "--stale-ok="):
+				value, hasValue := strings.CutPrefix(arg, "--stale-ok=")
+				if !hasValue {
+					if i+1 >= len(args) {
+						return nil, fmt.Errorf("missing value for --stale-ok")
+					}
+					i++
+					value = args[i]
+				}
+				maxAge, err := parseCallTimeout("--stale-ok", value)
+				if err != nil {
+					return nil, err
+				}
+				parsed.staleOK = &maxAge
+				hasAnyFlags = true
+				continue
 			case arg == "--no-cache":
 				if parsed.cacheTTL != nil {
 					return nil, fmt.Errorf("conflicting cache flags")
@@ -87,6 +303,593 @@ func parseToolCallArgs(args []string, stdin io.Reader, stdinIsTTY bool) (*toolCa
 				parsed.cacheTTL = &ttl
 				hasAnyFlags = true
 				continue
+			case strings.HasPrefix(arg, "--timeout="):
+				ttl, err := parseTotalTimeout("--timeout", strings.TrimPrefix(arg, "--timeout="))
+				if err != nil {
+					return nil, err
+				}
+				parsed.timeout = &ttl
+				hasAnyFlags = true
+				continue
+			case arg == "--timeout":
+				if i+1 >= len(args) {
+					return nil, fmt.Errorf("missing value for --timeout")
+				}
+				i++
+				ttl, err := parseTotalTimeout("--timeout", args[i])
+				if err != nil {
+					return nil, err
+				}
+				parsed.timeout = &ttl
+				hasAnyFlags = true
+				continue
+			case strings.HasPrefix(arg, "--timeout-from-env=") || arg == "--timeout-from-env":
+				name, hasValue := strings.CutPrefix(arg, "--timeout-from-env=")
+				if !hasValue {
+					if i+1 >= len(args) {
+						return nil, fmt.Errorf("missing value for --timeout-from-env")
+					}
+					i++
+					name = args[i]
+				}
+				ttl, err := timeoutFromEnv(name)
+				if err != nil {
+					return nil, err
+				}
+				parsed.timeout = &ttl
+				hasAnyFlags = true
+				continue
+			case strings.HasPrefix(arg, "--attempt-timeout=") || strings.HasPrefix(arg, "--timeout-per-attempt="):
+				ttl, err := parseCallTimeout("--attempt-timeout", arg[strings.Index(arg, "=")+1:])
+				if err != nil {
+					return nil, err
+				}
+				parsed.attemptTimeout = &ttl
+				hasAnyFlags = true
+				continue
+			case arg == "--attempt-timeout" || arg == "--timeout-per-attempt":
+				if i+1 >= len(args) {
+					return nil, fmt.Errorf("missing value for %s", arg)
+				}
+				i++
+				ttl, err := parseCallTimeout("--attempt-timeout", args[i])
+				if err != nil {
+					return nil, err
+				}
+				parsed.attemptTimeout = &ttl
+				hasAnyFlags = true
+				continue
+			case strings.HasPrefix(arg, "--warn-slow=") || strings.HasPrefix(arg, "--max-response-time-warn="):
+				threshold, err := parseCallTimeout("--warn-slow", arg[strings.Index(arg, "=")+1:])
+				if err != nil {
+					return nil, err
+				}
+				parsed.warnSlow = &threshold
+				hasAnyFlags = true
+				continue
+			case arg == "--warn-slow" || arg == "--max-response-time-warn":
+				if i+1 >= len(args) {
+					return nil, fmt.Errorf("missing value for %s", arg)
+				}
+				i++
+				threshold, err := parseCallTimeout("--warn-slow", args[i])
+				if err != nil {
+					return nil, err
+				}
+				parsed.warnSlow = &threshold
+				hasAnyFlags = true
+				continue
+			case strings.HasPrefix(arg, "--max-retries-respect-retry-after="):
+				n, err := parseRetryAfterRetries(strings.TrimPrefix(arg, "--max-retries-respect-retry-after="))
+				if err != nil {
+					return nil, err
+				}
+				parsed.retryAfterRetries = n
+				hasAnyFlags = true
+				continue
+			case arg == "--max-retries-respect-retry-after":
+				if i+1 >= len(args) {
+					return nil, fmt.Errorf("missing value for --max-retries-respect-retry-after")
+				}
+				i++
+				n, err := parseRetryAfterRetries(args[i])
+				if err != nil {
+					return nil, err
+				}
+				parsed.retryAfterRetries = n
+				hasAnyFlags = true
+				continue
+			case strings.HasPrefix(arg, "--retry-budget="):
+				budget, err := parseCallTimeout("--retry-budget", strings.TrimPrefix(arg, "--retry-budget="))
+				if err != nil {
+					return nil, err
+				}
+				parsed.retryBudget = &budget
+				hasAnyFlags = true
+				continue
+			case arg == "--retry-budget":
+				if i+1 >= len(args) {
+					return nil, fmt.Errorf("missing value for --retry-budget")
+				}
+				i++
+				budget, err := parseCallTimeout("--retry-budget", args[i])
+				if err != nil {
+					return nil, err
+				}
+				parsed.retryBudget = &budget
+				hasAnyFlags = true
+				continue
+			case strings.HasPrefix(arg, "--repeat-until="):
+				cond, err := parseRepeatUntil(strings.TrimPrefix(arg, "--repeat-until="))
+				if err != nil {
+					return nil, err
+				}
+				parsed.repeatUntil = cond
+				hasAnyFlags = true
+				continue
+			case arg == "--repeat-until":
+				if i+1 >= len(args) {
+					return nil, fmt.Errorf("missing value for --repeat-until")
+				}
+				i++
+				cond, err := parseRepeatUntil(args[i])
+				if err != nil {
+					return nil, err
+				}
+				parsed.repeatUntil = cond
+				hasAnyFlags = true
+				continue
+			case strings.HasPrefix(arg, "--map-exit=") || strings.HasPrefix(arg, "--map-exit-rule=") ||
+				arg == "--map-exit" || arg == "--map-exit-rule":
+				flag, raw, hasValue := strings.Cut(arg, "=")
+				if !hasValue {
+					if i+1 >= len(args) {
+						return nil, fmt.Errorf("missing value for %s", flag)
+					}
+					i++
+					raw = args[i]
+				}
+				if parsed.mapExit == nil {
+					parsed.mapExit = &exitMapping{}
+				}
+				setMapping := parsed.mapExit.setField
+				if flag == "--map-exit-rule" {
+					setMapping = parsed.mapExit.addRule
+				}
+				if err := setMapping(raw); err != nil {
+					return nil, err
+				}
+				hasAnyFlags = true
+				continue
+			case strings.HasPrefix(arg, "--interval=") || strings.HasPrefix(arg, "--max-wait="):
+				flag, raw, _ := strings.Cut(arg, "=")
+				d, err := parseCallTimeout(flag, raw)
+				if err != nil {
+					return nil, err
+				}
+				if flag == "--interval" {
+					parsed.repeatInterval = &d
+				} else {
+					parsed.repeatMaxWait = &d
+				}
+				hasAnyFlags = true
+				continue
+			case arg == "--interval" || arg == "--max-wait":
+				if i+1 >= len(args) {
+					return nil, fmt.Errorf("missing value for %s", arg)
+				}
+				i++
+				d, err := parseCallTimeout(arg, args[i])
+				if err != nil {
+					return nil, err
+				}
+				if arg == "--interval" {
+					parsed.repeatInterval = &d
+				} else {
+					parsed.repeatMaxWait = &d
+				}
+				hasAnyFlags = true
+				continue
+			case arg == "--args-stdin-merge":
+				parsed.stdinMerge = true
+				hasAnyFlags = true
+				continue
+			case arg == "--no-schema-cache":
+				parsed.noSchemaCache = true
+				hasAnyFlags = true
+				continue
+			case arg == "--input-from-prev":
+				parsed.inputFromPrev = true
+				hasAnyFlags = true
+				continue
+			case strings.HasPrefix(arg, "--args-template-file="):
+				parsed.argsTemplate = strings.TrimSpace(strings.TrimPrefix(arg, "--args-template-file="))
+				if parsed.argsTemplate == "" {
+					return nil, fmt.Errorf("missing value for --args-template-file")
+				}
+				hasAnyFlags = true
+				continue
+			case arg == "--args-template-file":
+				if i+1 >= len(args) || strings.TrimSpace(args[i+1]) == "" {
+					return nil, fmt.Errorf("missing value for --args-template-file")
+				}
+				i++
+				parsed.argsTemplate = strings.TrimSpace(args[i])
+				hasAnyFlags = true
+				continue
+			case strings.HasPrefix(arg, "--var=") || arg == "--var":
+				raw := strings.TrimPrefix(arg, "--var=")
+				if arg == "--var" {
+					if i+1 >= len(args) {
+						return nil, fmt.Errorf("missing value for --var")
+					}
+					i++
+					raw = args[i]
+				}
+				name, value, err := parseTemplateVar(raw)
+				if err != nil {
+					return nil, err
+				}
+				if parsed.templateVars == nil {
+					parsed.templateVars = make(map[string]string)
+				}
+				parsed.templateVars[name] = value
+				hasAnyFlags = true
+				continue
+			case strings.HasPrefix(arg, "--idempotency-key="):
+				value := strings.TrimSpace(strings.TrimPrefix(arg, "--idempotency-key="))
+				if value == "" {
+					return nil, fmt.Errorf("missing value for --idempotency-key")
+				}
+				parsed.idempotencyKey = value
+				hasAnyFlags = true
+				continue
+			case arg == "--idempotency-key":
+				if i+1 >= len(args) || strings.TrimSpace(args[i+1]) == "" {
+					return nil, fmt.Errorf("missing value for --idempotency-key")
+				}
+				i++
+				parsed.idempotencyKey = strings.TrimSpace(args[i])
+				hasAnyFlags = true
+				continue
+			case strings.HasPrefix(arg, "--save-as="):
+				value := strings.TrimSpace(strings.TrimPrefix(arg, "--save-as="))
+				if value == "" {
+					return nil, fmt.Errorf("missing value for --save-as")
+				}
+				parsed.saveAs = value
+				hasAnyFlags = true
+				continue
+			case arg == "--save-as":
+				if i+1 >= len(args) || strings.TrimSpace(args[i+1]) == "" {
+					return nil, fmt.Errorf("missing value for --save-as")
+				}
+				i++
+				parsed.saveAs = strings.TrimSpace(args[i])
+				hasAnyFlags = true
+				continue
+			case arg == "--output-raw-bytes":
+				parsed.rawBytes = true
+				hasAnyFlags = true
+				continue
+			case arg == "--ignore-exit":
+				parsed.ignoreExit = true
+				hasAnyFlags = true
+				continue
+			case arg == "--structured-only" || arg == "--output-structured-only" || arg == "--text-only" || arg == "--output-text-only":
+				kind := "structured"
+				if strings.HasSuffix(arg, "text-only") {
+					kind = "text"
+				}
+				if parsed.contentOnly != "" && parsed.contentOnly != kind {
+					return nil, fmt.Errorf("--structured-only and --text-only cannot be combined")
+				}
+				parsed.contentOnly = kind
+				hasAnyFlags = true
+				continue
+			case arg == "--raw":
+				parsed.rawResult = true
+				hasAnyFlags = true
+				continue
+			case arg == "--combine-content" || strings.HasPrefix(arg, "--combine-content="):
+				raw, hasValue := strings.CutPrefix(arg, "--combine-content=")
+				if !hasValue {
+					if i+1 >= len(args) {
+						return nil, fmt.Errorf("missing value for --combine-content")
+					}
+					i++
+					raw = args[i]
+				}
+				switch mode := strings.ToLower(strings.TrimSpace(raw)); mode {
+				case "text", "json":
+					parsed.combineContent = mode
+				default:
+					return nil, fmt.Errorf("invalid --combine-content %q: expected text or json", raw)
+				}
+				hasAnyFlags = true
+				continue
+			case strings.HasPrefix(arg, "--output-encoding="):
+				encoding, err := parseOutputEncoding(strings.TrimPrefix(arg, "--output-encoding="))
+				if err != nil {
+					return nil, err
+				}
+				parsed.outputEncoding = encoding
+				hasAnyFlags = true
+				continue
+			case arg == "--output-encoding":
+				if i+1 >= len(args) {
+					return nil, fmt.Errorf("missing value for --output-encoding")
+				}
+				i++
+				encoding, err := parseOutputEncoding(args[i])
+				if err != nil {
+					return nil, err
+				}
+				parsed.outputEncoding = encoding
+				hasAnyFlags = true
+				continue
+			case arg == "--flatten":
+				parsed.flatten = true
+				hasAnyFlags = true
+				continue
+			case arg == "--jsonl-field" || strings.HasPrefix(arg, "--jsonl-field="):
+				raw, hasValue := strings.CutPrefix(arg, "--jsonl-field=")
+				if !hasValue {
+					if i+1 >= len(args) {
+						return nil, fmt.Errorf("missing value for --jsonl-field")
+					}
+					i++
+					raw = args[i]
+				}
+				path, err := parseJSONLField(raw)
+				if err != nil {
+					return nil, err
+				}
+				parsed.jsonlField = strings.TrimSpace(raw)
+				parsed.jsonlPath = path
+				hasAnyFlags = true
+				continue
+			case arg == "--output-file-per-item" || strings.HasPrefix(arg, "--output-file-per-item="):
+				dir, hasValue := strings.CutPrefix(arg, "--output-file-per-item=")
+				if !hasValue {
+					if i+1 >= len(args) {
+						return nil, fmt.Errorf("missing value for --output-file-per-item")
+					}
+					i++
+					dir = args[i]
+				}
+				if strings.TrimSpace(dir) == "" {
+					return nil, fmt.Errorf("missing value for --output-file-per-item")
+				}
+				parsed.outputDir = strings.TrimSpace(dir)
+				hasAnyFlags = true
+				continue
+			case arg == "--output-file" || strings.HasPrefix(arg, "--output-file="):
+				path, hasValue := strings.CutPrefix(arg, "--output-file=")
+				if !hasValue {
+					if i+1 >= len(args) {
+						return nil, fmt.Errorf("missing value for --output-file")
+					}
+					i++
+					path = args[i]
+				}
+				if strings.TrimSpace(path) == "" {
+					return nil, fmt.Errorf("missing value for --output-file")
+				}
+				parsed.outputFile = strings.TrimSpace(path)
+				hasAnyFlags = true
+				continue
+			case arg == "--tee" || strings.HasPrefix(arg, "--tee="):
+				path, hasValue := strings.CutPrefix(arg, "--tee=")
+				if !hasValue {
+					if i+1 >= len(args) {
+						return nil, fmt.Errorf("missing value for --tee")
+					}
+					i++
+					path = args[i]
+				}
+				if strings.TrimSpace(path) == "" {
+					return nil, fmt.Errorf("missing value for --tee")
+				}
+				parsed.tee = strings.TrimSpace(path)
+				hasAnyFlags = true
+				continue
+			case arg == "--param-prompt" || strings.HasPrefix(arg, "--param-prompt="):
+				name, hasValue := strings.CutPrefix(arg, "--param-prompt=")
+				if !hasValue {
+					if i+1 >= len(args) {
+						return nil, fmt.Errorf("missing value for --param-prompt")
+					}
+					i++
+					name = args[i]
+				}
+				name = strings.TrimSpace(name)
+				if name == "" {
+					return nil, fmt.Errorf("missing value for --param-prompt")
+				}
+				parsed.promptParams = append(parsed.promptParams, name)
+				hasAnyFlags = true
+				continue
+			case arg == "--name-field" || strings.HasPrefix(arg, "--name-field="):
+				raw, hasValue := strings.CutPrefix(arg, "--name-field=")
+				if !hasValue {
+					if i+1 >= len(args) {
+						return nil, fmt.Errorf("missing value for --name-field")
+					}
+					i++
+					raw = args[i]
+				}
+				path, err := parseNameField(raw)
+				if err != nil {
+					return nil, err
+				}
+				parsed.nameField = strings.TrimSpace(raw)
+				parsed.namePath = path
+				hasAnyFlags = true
+				continue
+			case arg == "--redact" || strings.HasPrefix(arg, "--redact="):
+				raw, hasValue := strings.CutPrefix(arg, "--redact=")
+				if !hasValue {
+					if i+1 >= len(args) {
+						return nil, fmt.Errorf("missing value for --redact")
+					}
+					i++
+					raw = args[i]
+				}
+				path, err := parseRedactPath(raw)
+				if err != nil {
+					return nil, err
+				}
+				parsed.redact = append(parsed.redact, path)
+				hasAnyFlags = true
+				continue
+			case arg == "--progress":
+				parsed.progress = true
+				hasAnyFlags = true
+				continue
+			case arg == "--capture-stderr":
+				parsed.captureStderr = true
+				hasAnyFlags = true
+				continue
+			case arg == "--confirm":
+				parsed.confirm = true
+				hasAnyFlags = true
+				continue
+			case arg == "--yes":
+				parsed.yes = true
+				hasAnyFlags = true
+				continue
+			case arg == "--examples":
+				parsed.examples = true
+				hasAnyFlags = true
+				continue
+			case arg == "--param-required-check":
+				parsed.requiredCheck = true
+				hasAnyFlags = true
+				continue
+			case arg == "--dry-run":
+				parsed.dryRun = true
+				hasAnyFlags = true
+				continue
+			case arg == "--sample-output" || arg == "--output-schema-sample":
+				parsed.sampleOutput = true
+				hasAnyFlags = true
+				continue
+			case arg == "--dump-schema-md":
+				parsed.schemaMarkdown = true
+				hasAnyFlags = true
+				continue
+			case arg == "--print-curl":
+				parsed.printCurl = true
+				hasAnyFlags = true
+				continue
+			case arg == "--soft-fail" || arg == "--json-errors-to-stdout":
+				parsed.softFail = true
+				hasAnyFlags = true
+				continue
+			case strings.HasPrefix(arg, "--show-schema-diff="):
+				parsed.schemaDiff = strings.TrimSpace(strings.TrimPrefix(arg, "--show-schema-diff="))
+				if parsed.schemaDiff == "" {
+					return nil, fmt.Errorf("--show-schema-diff requires a server name")
+				}
+				hasAnyFlags = true
+				continue
+			case arg == "--show-schema-diff":
+				if i+1 >= len(args) {
+					return nil, fmt.Errorf("missing value for --show-schema-diff")
+				}
+				i++
+				parsed.schemaDiff = strings.TrimSpace(args[i])
+				if parsed.schemaDiff == "" {
+					return nil, fmt.Errorf("--show-schema-diff requires a server name")
+				}
+				hasAnyFlags = true
+				continue
+			case strings.HasPrefix(arg, "--param-default="):
+				def, err := parseParamDefault(strings.TrimPrefix(arg, "--param-default="))
+				if err != nil {
+					return nil, err
+				}
+				parsed.paramDefaults = append(parsed.paramDefaults, def)
+				hasAnyFlags = true
+				continue
+			case arg == "--param-default":
+				if i+1 >= len(args) {
+					return nil, fmt.Errorf("missing value for --param-default")
+				}
+				i++
+				def, err := parseParamDefault(args[i])
+				if err != nil {
+					return nil, err
+				}
+				parsed.paramDefaults = append(parsed.paramDefaults, def)
+				hasAnyFlags = true
+				continue
+			case strings.HasPrefix(arg, "--header=") || arg == "--header" ||
+				strings.HasPrefix(arg, "--headers-from-file=") || arg == "--headers-from-file":
+				flag, raw, hasValue := strings.Cut(arg, "=")
+				if !hasValue {
+					if i+1 >= len(args) {
+						return nil, fmt.Errorf("missing value for %s", flag)
+					}
+					i++
+					raw = args[i]
+				}
+				if flag == "--header" {
+					name, value, err := parseHeader(raw)
+					if err != nil {
+						return nil, err
+					}
+					flagHeaders = httpheaders.Set(flagHeaders, name, value)
+				} else {
+					loaded, err := readHeadersFile(raw)
+					if err != nil {
+						return nil, err
+					}
+					fileHeaders = httpheaders.Merge(fileHeaders, loaded, true)
+				}
+				hasAnyFlags = true
+				continue
+			case strings.HasPrefix(arg, "--param-file-json="):
+				param, err := parseParamFileJSON(strings.TrimPrefix(arg, "--param-file-json="))
+				if err != nil {
+					return nil, err
+				}
+				parsed.paramFiles = append(parsed.paramFiles, param)
+				hasAnyFlags = true
+				continue
+			case arg == "--param-file-json":
+				if i+1 >= len(args) {
+					return nil, fmt.Errorf("missing value for --param-file-json")
+				}
+				i++
+				param, err := parseParamFileJSON(args[i])
+				if err != nil {
+					return nil, err
+				}
+				parsed.paramFiles = append(parsed.paramFiles, param)
+				hasAnyFlags = true
+				continue
+			case strings.HasPrefix(arg, "--on-error="):
+				argv, err := parseOnErrorCommand(strings.TrimPrefix(arg, "--on-error="))
+				if err != nil {
+					return nil, err
+				}
+				parsed.onError = argv
+				hasAnyFlags = true
+				continue
+			case arg == "--on-error":
+				if i+1 >= len(args) {
+					return nil, fmt.Errorf("missing value for --on-error")
+				}
+				i++
+				argv, err := parseOnErrorCommand(args[i])
+				if err != nil {
+					return nil, err
+				}
+				parsed.onError = argv
+				hasAnyFlags = true
+				continue
 			}
 		}
 
@@ -98,6 +901,15 @@ func parseToolCallArgs(args []string, stdin io.Reader, stdinIsTTY bool) (*toolCa
 			if positionalJSON != "" {
 				return nil, fmt.Errorf("cannot mix positional JSON arguments with --flags")
 			}
+			if key, ok := strings.CutSuffix(strings.TrimPrefix(flagArg, "--"), "@-"); ok && key != "" && !strings.Contains(key, "=") {
+				if parsed.stdinParam != "" {
+					return nil, fmt.Errorf("only one parameter can be read from stdin, got --%s@- and --%s@-", parsed.stdinParam, key)
+				}
+				parsed.stdinParam = key
+				hasToolFlags = true
+				hasAnyFlags = true
+				continue
+			}
 
 			key, value, err := parseLongFlagValue(args, &i, flagArg)
 			if err != nil {
@@ -122,29 +934,216 @@ func parseToolCallArgs(args []string, stdin io.Reader, stdinIsTTY bool) (*toolCa
 		positionalJSON = arg
 	}
 
-	if positionalJSON != "" {
+	if parsed.inputFromPrev {
+		switch {
+		case parsed.stdinMerge:
+			return nil, fmt.Errorf("--input-from-prev cannot be combined with --args-stdin-merge")
+		case parsed.stdinParam != "":
+			return nil, fmt.Errorf("--input-from-prev cannot be combined with --%s@-", parsed.stdinParam)
+		case positionalJSON != "":
+			return nil, fmt.Errorf("--input-from-prev cannot be combined with positional JSON arguments")
+		case stdinIsTTY || stdin == nil:
+			return nil, fmt.Errorf("--input-from-prev requires the previous call's JSON output on stdin")
+		}
+		base, err := readPrevResult(stdin)
+		if err != nil {
+			return nil, err
+		}
+		// Flags win over the previous result, key by key at the top level.
+		for key, value := range parsed.toolArgs {
+			base[key] = value
+		}
+		parsed.toolArgs = base
+	}
+
+	if parsed.stdinParam != "" {
+		if parsed.stdinMerge {
+			return nil, fmt.Errorf("--%s@- cannot be combined with --args-stdin-merge", parsed.stdinParam)
+		}
+		if stdinIsTTY || stdin == nil {
+			return nil, fmt.Errorf("--%s@- requires input on stdin", parsed.stdinParam)
+		}
+		data, err := io.ReadAll(stdin)
+		if err != nil {
+			return nil, fmt.Errorf("reading stdin: %w", err)
+		}
+		// The daemon coerces the string against the tool schema, so JSON
+		// values still reach object, array, and number parameters typed.
+		putArgValue(parsed.toolArgs, parsed.stdinParam, string(data))
+	}
+
+	if parsed.stdinMerge {
+		if positionalJSON != "" {
+			return nil, fmt.Errorf("--args-stdin-merge cannot be combined with positional JSON arguments")
+		}
+		if stdinIsTTY || stdin == nil {
+			return nil, fmt.Errorf("--args-stdin-merge requires a JSON object on stdin")
+		}
+		base, err := readStdinJSONObject(stdin)
+		if err != nil {
+			return nil, err
+		}
+		// Flags win over stdin, key by key at the top level.
+		for key, value := range parsed.toolArgs {
+			base[key] = value
+		}
+		parsed.toolArgs = base
+	} else if positionalJSON != "" {
 		obj, err := parseJSONObject(positionalJSON)
 		if err != nil {
 			return nil, err
 		}
 		parsed.toolArgs = obj
 	} else if !hasAnyFlags && !hasToolFlags && !stdinIsTTY && stdin != nil {
-		data, err := io.ReadAll(stdin)
+		obj, err := readStdinJSONObject(stdin)
 		if err != nil {
-			return nil, fmt.Errorf("reading stdin: %w", err)
+			return nil, err
 		}
-		trimmed := strings.TrimSpace(string(data))
-		if trimmed != "" {
-			obj, err := parseJSONObject(trimmed)
-			if err != nil {
-				return nil, err
-			}
+		if len(obj) > 0 {
 			parsed.toolArgs = obj
 		}
 	}
 
-	if parsed.output.isJSON() && !parsed.help {
-		return nil, fmt.Errorf("--json is only supported with --help")
+	for _, param := range parsed.paramFiles {
+		if _, exists := parsed.toolArgs[param.name]; exists {
+			return nil, fmt.Errorf("--param-file-json %s: parameter is already set", param.name)
+		}
+		parsed.toolArgs[param.name] = param.value
+	}
+
+	if parsed.argsTemplate != "" {
+		if parsed.inputFromPrev {
+			return nil, fmt.Errorf("--args-template-file cannot be combined with --input-from-prev")
+		}
+		if positionalJSON != "" || parsed.stdinMerge {
+			return nil, fmt.Errorf("--args-template-file cannot be combined with positional JSON arguments or --args-stdin-merge")
+		}
+		base, err := renderArgsTemplate(parsed.argsTemplate, parsed.templateVars, os.LookupEnv)
+		if err != nil {
+			return nil, err
+		}
+		// Flags win over the template, key by key at the top level.
+		for key, value := range parsed.toolArgs {
+			base[key] = value
+		}
+		parsed.toolArgs = base
+	} else if len(parsed.templateVars) > 0 {
+		return nil, fmt.Errorf("--var requires --args-template-file")
+	}
+
+	noCache := parsed.cacheTTL != nil && *parsed.cacheTTL == 0
+	if parsed.fresh && parsed.staleOK != nil {
+		return nil, fmt.Errorf("--fresh cannot be combined with --stale-ok")
+	}
+	if noCache && (parsed.fresh || parsed.staleOK != nil) {
+		return nil, fmt.Errorf("--no-cache cannot be combined with --fresh or --stale-ok")
+	}
+
+	if parsed.rawBytes && parsed.repeatUntil != nil {
+		return nil, fmt.Errorf("--output-raw-bytes cannot be combined with --repeat-until")
+	}
+	if parsed.rawBytes && parsed.combineContent != "" {
+		return nil, fmt.Errorf("--output-raw-bytes cannot be combined with --combine-content")
+	}
+	if parsed.rawBytes && parsed.flatten {
+		return nil, fmt.Errorf("--output-raw-bytes cannot be combined with --flatten")
+	}
+	if parsed.rawResult && parsed.rawBytes {
+		return nil, fmt.Errorf("--raw cannot be combined with --output-raw-bytes")
+	}
+	if parsed.rawResult && parsed.combineContent != "" {
+		return nil, fmt.Errorf("--raw cannot be combined with --combine-content")
+	}
+	if parsed.contentOnly != "" {
+		flag := "--" + parsed.contentOnly + "-only"
+		switch {
+		case parsed.rawResult:
+			return nil, fmt.Errorf("%s cannot be combined with --raw", flag)
+		case parsed.rawBytes:
+			return nil, fmt.Errorf("%s cannot be combined with --output-raw-bytes", flag)
+		case parsed.contentOnly == "structured" && parsed.combineContent != "":
+			return nil, fmt.Errorf("%s cannot be combined with --combine-content", flag)
+		}
+	}
+	if parsed.flatten && parsed.outputEncoding != "" && parsed.outputEncoding != outputEncodingUTF8 {
+		return nil, fmt.Errorf("--output-encoding cannot be combined with --flatten")
+	}
+	if parsed.jsonlField != "" {
+		switch {
+		case parsed.flatten:
+			return nil, fmt.Errorf("--jsonl-field cannot be combined with --flatten")
+		case parsed.rawBytes:
+			return nil, fmt.Errorf("--jsonl-field cannot be combined with --output-raw-bytes")
+		case parsed.outputEncoding != "" && parsed.outputEncoding != outputEncodingUTF8:
+			return nil, fmt.Errorf("--output-encoding cannot be combined with --jsonl-field")
+		}
+	}
+	if parsed.nameField != "" && parsed.outputDir == "" {
+		return nil, fmt.Errorf("--name-field requires --output-file-per-item")
+	}
+	if parsed.outputDir != "" {
+		switch {
+		case parsed.jsonlField != "":
+			return nil, fmt.Errorf("--output-file-per-item cannot be combined with --jsonl-field")
+		case parsed.flatten:
+			return nil, fmt.Errorf("--output-file-per-item cannot be combined with --flatten")
+		case parsed.rawBytes:
+			return nil, fmt.Errorf("--output-file-per-item cannot be combined with --output-raw-bytes")
+		case parsed.outputEncoding != "" && parsed.outputEncoding != outputEncodingUTF8:
+			return nil, fmt.Errorf("--output-encoding cannot be combined with --output-file-per-item")
+		}
+	}
+	for _, name := range parsed.promptParams {
+		if _, ok := parsed.toolArgs[name]; ok {
+			return nil, fmt.Errorf("--param-prompt %s cannot be combined with a value for %s", name, name)
+		}
+	}
+	if parsed.outputFile != "" {
+		switch {
+		case parsed.outputDir != "":
+			return nil, fmt.Errorf("--output-file cannot be combined with --output-file-per-item")
+		case parsed.jsonlField != "":
+			return nil, fmt.Errorf("--output-file cannot be combined with --jsonl-field")
+		case parsed.flatten:
+			return nil, fmt.Errorf("--output-file cannot be combined with --flatten")
+		}
+	}
+	if parsed.tee != "" {
+		switch {
+		case parsed.outputFile != "":
+			return nil, fmt.Errorf("--tee cannot be combined with --output-file")
+		case parsed.outputDir != "":
+			return nil, fmt.Errorf("--tee cannot be combined with --output-file-per-item")
+		case parsed.jsonlField != "":
+			return nil, fmt.Errorf("--tee cannot be combined with --jsonl-field")
+		case parsed.flatten:
+			return nil, fmt.Errorf("--tee cannot be combined with --flatten")
+		}
+	}
+	parsed.headers = httpheaders.Merge(fileHeaders, flagHeaders, true)
+	if parsed.mapExit != nil && parsed.mapExit.field == "" {
+		return nil, fmt.Errorf("--map-exit-rule requires --map-exit")
+	}
+	if len(parsed.redact) > 0 && parsed.rawBytes {
+		return nil, fmt.Errorf("--redact cannot be combined with --output-raw-bytes")
+	}
+	if parsed.mapExit != nil && parsed.ignoreExit {
+		return nil, fmt.Errorf("--map-exit cannot be combined with --ignore-exit")
+	}
+	if parsed.mapExit != nil && parsed.rawBytes {
+		return nil, fmt.Errorf("--map-exit cannot be combined with --output-raw-bytes")
+	}
+	if parsed.repeatUntil == nil && (parsed.repeatInterval != nil || parsed.repeatMaxWait != nil) {
+		return nil, fmt.Errorf("--interval and --max-wait require --repeat-until")
+	}
+	if parsed.confirm && parsed.yes {
+		return nil, fmt.Errorf("--confirm and --yes cannot be combined")
+	}
+	if parsed.output.isYAML() && !parsed.help {
+		return nil, fmt.Errorf("--yaml is only supported with --help")
+	}
+	if outputSet && !parsed.help && parsed.schemaDiff == "" && !parsed.flatten && !parsed.examples && !parsed.requiredCheck && parsed.outputFile == "" {
+		return nil, fmt.Errorf("--json is only supported with --help, --show-schema-diff, --examples, --param-required-check, --flatten, or --output-file")
 	}
 
 	return parsed, nil
@@ -173,6 +1172,20 @@ func parseFlags(args []string) (map[string]any, error) {
 	return result, nil
 }
 
+// readStdinJSONObject reads a JSON object from stdin; empty input is an
+// empty object.
+func readStdinJSONObject(stdin io.Reader) (map[string]any, error) {
+	data, err := io.ReadAll(stdin)
+	if err != nil {
+		return nil, fmt.Errorf("reading stdin: %w", err)
+	}
+	trimmed := strings.TrimSpace(string(data))
+	if trimmed == "" {
+		return make(map[string]any), nil
+	}
+	return parseJSONObject(trimmed)
+}
+
 func parseJSONObject(raw string) (map[string]any, error) {
 	var decoded any
 	if err := json.Unmarshal([]byte(raw), &decoded); err != nil {
@@ -227,6 +1240,51 @@ func putArgValue(dst map[string]any, key string, value any) {
 	dst[key] = value
 }
 
+func parseCallTimeout(flag, raw string) (time.Duration, error) {
+	timeout, err := time.ParseDuration(strings.TrimSpace(raw))
+	if err != nil {
+		return 0, fmt.Errorf("invalid %s value: %w", flag, err)
+	}
+	if timeout <= 0 {
+		return 0, fmt.Errorf("%s must be > 0", flag)
+	}
+	return timeout, nil
+}
+
+// parseTotalTimeout parses --timeout (or --timeout-from-env), where 0 means
+// no timeout: the call runs without the server's request_timeout as well.
+func parseTotalTimeout(flag, raw string) (time.Duration, error) {
+	if d, err := time.ParseDuration(strings.TrimSpace(raw)); err == nil && d == 0 {
+		return 0, nil
+	}
+	return parseCallTimeout(flag, raw)
+}
+
+// timeoutFromEnv reads a --timeout duration from the environment variable
+// named by --timeout-from-env.
+func timeoutFromEnv(name string) (time.Duration, error) {
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return 0, fmt.Errorf("missing value for --timeout-from-env")
+	}
+	raw, ok := os.LookupEnv(name)
+	if !ok || strings.TrimSpace(raw) == "" {
+		return 0, fmt.Errorf("--timeout-from-env: $%s is not set", name)
+	}
+	return parseTotalTimeout("--timeout-from-env $"+name, raw)
+}
+
+func parseRetryAfterRetries(raw string) (int, error) {
+	n, err := strconv.Atoi(strings.TrimSpace(raw))
+	if err != nil {
+		return 0, fmt.Errorf("invalid --max-retries-respect-retry-after value: %w", err)
+	}
+	if n <= 0 {
+		return 0, fmt.Errorf("--max-retries-respect-retry-after must be > 0")
+	}
+	return n, nil
+}
+
 func parseCacheDuration(raw string) (time.Duration, error) {
 	ttl, err := time.ParseDuration(raw)
 	if err != nil {
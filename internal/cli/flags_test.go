@@ -2,8 +2,12 @@ package cli
 
 import (
 	"bytes"
+	"reflect"
+	"strings"
 	"testing"
 	"time"
+
+	"github.com/lydakis/mcpx/internal/ipc"
 )
 
 func TestParseFlagsSupportsPositionalJSONObject(t *testing.T) {
@@ -22,8 +26,28 @@ func TestParseFlagsSupportsPositionalJSONObject(t *testing.T) {
 	}
 }
 
+func TestParseFlagsKeepsExplicitBooleanValues(t *testing.T) {
+	got, err := parseFlags([]string{"--draft=false", "--archived=null", "--no-notify=true", "--force"})
+	if err != nil {
+		t.Fatalf("parseFlags() error = %v", err)
+	}
+
+	if got["draft"] != "false" {
+		t.Fatalf("draft = %#v, want %q", got["draft"], "false")
+	}
+	if got["archived"] != "null" {
+		t.Fatalf("archived = %#v, want %q", got["archived"], "null")
+	}
+	if got["no-notify"] != "true" {
+		t.Fatalf("no-notify = %#v, want %q", got["no-notify"], "true")
+	}
+	if got["force"] != true {
+		t.Fatalf("force = %#v, want true", got["force"])
+	}
+}
+
 func TestParseToolCallArgsExtractsCacheTTL(t *testing.T) {
-	parsed, err := parseToolCallArgs([]string{"--cache=30s", "--query=mcp"}, bytes.NewBuffer(nil), true)
+	parsed, err := parseToolCallArgs([]string{"--cache=30s", "--query=mcp"}, bytes.NewBuffer(nil), true, outputModeText)
 	if err != nil {
 		t.Fatalf("parseToolCallArgs() error = %v", err)
 	}
@@ -37,7 +61,7 @@ func TestParseToolCallArgsExtractsCacheTTL(t *testing.T) {
 }
 
 func TestParseToolCallArgsNoCacheWithSeparator(t *testing.T) {
-	parsed, err := parseToolCallArgs([]string{"--no-cache", "--", "--cache=true"}, bytes.NewBuffer(nil), true)
+	parsed, err := parseToolCallArgs([]string{"--no-cache", "--", "--cache=true"}, bytes.NewBuffer(nil), true, outputModeText)
 	if err != nil {
 		t.Fatalf("parseToolCallArgs() error = %v", err)
 	}
@@ -52,13 +76,13 @@ func TestParseToolCallArgsNoCacheWithSeparator(t *testing.T) {
 }
 
 func TestParseToolCallArgsRejectsConflictingCacheFlags(t *testing.T) {
-	if _, err := parseToolCallArgs([]string{"--cache=30s", "--no-cache"}, bytes.NewBuffer(nil), true); err == nil {
+	if _, err := parseToolCallArgs([]string{"--cache=30s", "--no-cache"}, bytes.NewBuffer(nil), true, outputModeText); err == nil {
 		t.Fatal("parseToolCallArgs() error = nil, want non-nil")
 	}
 }
 
 func TestParseToolCallArgsGlobalToolCollisionWithToolPrefix(t *testing.T) {
-	parsed, err := parseToolCallArgs([]string{"--cache=30s", "--tool-cache=true"}, bytes.NewBuffer(nil), true)
+	parsed, err := parseToolCallArgs([]string{"--cache=30s", "--tool-cache=true"}, bytes.NewBuffer(nil), true, outputModeText)
 	if err != nil {
 		t.Fatalf("parseToolCallArgs() error = %v", err)
 	}
@@ -72,7 +96,7 @@ func TestParseToolCallArgsGlobalToolCollisionWithToolPrefix(t *testing.T) {
 }
 
 func TestParseToolCallArgsSeparatorTreatsGlobalNamesAsToolArgs(t *testing.T) {
-	parsed, err := parseToolCallArgs([]string{"--", "--cache=true", "--help"}, bytes.NewBuffer(nil), true)
+	parsed, err := parseToolCallArgs([]string{"--", "--cache=true", "--help"}, bytes.NewBuffer(nil), true, outputModeText)
 	if err != nil {
 		t.Fatalf("parseToolCallArgs() error = %v", err)
 	}
@@ -91,7 +115,7 @@ func TestParseToolCallArgsSeparatorTreatsGlobalNamesAsToolArgs(t *testing.T) {
 }
 
 func TestParseToolCallArgsSeparatorNormalizesToolPrefixFlags(t *testing.T) {
-	parsed, err := parseToolCallArgs([]string{"--", "--tool-cache=true"}, bytes.NewBuffer(nil), true)
+	parsed, err := parseToolCallArgs([]string{"--", "--tool-cache=true"}, bytes.NewBuffer(nil), true, outputModeText)
 	if err != nil {
 		t.Fatalf("parseToolCallArgs() error = %v", err)
 	}
@@ -105,7 +129,7 @@ func TestParseToolCallArgsSeparatorNormalizesToolPrefixFlags(t *testing.T) {
 }
 
 func TestParseToolCallArgsDoesNotCoerceExplicitFlagValues(t *testing.T) {
-	parsed, err := parseToolCallArgs([]string{"--id=00123", "--enabled=false", "--score", "1.5"}, bytes.NewBuffer(nil), true)
+	parsed, err := parseToolCallArgs([]string{"--id=00123", "--enabled=false", "--score", "1.5"}, bytes.NewBuffer(nil), true, outputModeText)
 	if err != nil {
 		t.Fatalf("parseToolCallArgs() error = %v", err)
 	}
@@ -122,7 +146,7 @@ func TestParseToolCallArgsDoesNotCoerceExplicitFlagValues(t *testing.T) {
 }
 
 func TestParseToolCallArgsReadsJSONFromStdinWhenNoFlags(t *testing.T) {
-	parsed, err := parseToolCallArgs([]string{}, bytes.NewBufferString(`{"query":"mcp","page":3}`), false)
+	parsed, err := parseToolCallArgs([]string{}, bytes.NewBufferString(`{"query":"mcp","page":3}`), false, outputModeText)
 	if err != nil {
 		t.Fatalf("parseToolCallArgs() error = %v", err)
 	}
@@ -136,7 +160,7 @@ func TestParseToolCallArgsReadsJSONFromStdinWhenNoFlags(t *testing.T) {
 }
 
 func TestParseToolCallArgsDoesNotReadStdinWhenFlagsProvided(t *testing.T) {
-	parsed, err := parseToolCallArgs([]string{"--query=mcp"}, bytes.NewBufferString(`{not-json}`), false)
+	parsed, err := parseToolCallArgs([]string{"--query=mcp"}, bytes.NewBufferString(`{not-json}`), false, outputModeText)
 	if err != nil {
 		t.Fatalf("parseToolCallArgs() error = %v", err)
 	}
@@ -146,7 +170,7 @@ func TestParseToolCallArgsDoesNotReadStdinWhenFlagsProvided(t *testing.T) {
 }
 
 func TestParseToolCallArgsDoesNotReadStdinWhenOnlyGlobalFlagsProvided(t *testing.T) {
-	parsed, err := parseToolCallArgs([]string{"--verbose"}, bytes.NewBufferString(`{not-json}`), false)
+	parsed, err := parseToolCallArgs([]string{"--verbose"}, bytes.NewBufferString(`{not-json}`), false, outputModeText)
 	if err != nil {
 		t.Fatalf("parseToolCallArgs() error = %v", err)
 	}
@@ -156,7 +180,7 @@ func TestParseToolCallArgsDoesNotReadStdinWhenOnlyGlobalFlagsProvided(t *testing
 }
 
 func TestParseToolCallArgsSupportsVerboseQuietAndHelp(t *testing.T) {
-	parsed, err := parseToolCallArgs([]string{"-v", "--quiet", "--help"}, bytes.NewBuffer(nil), true)
+	parsed, err := parseToolCallArgs([]string{"-v", "--quiet", "--help"}, bytes.NewBuffer(nil), true, outputModeText)
 	if err != nil {
 		t.Fatalf("parseToolCallArgs() error = %v", err)
 	}
@@ -172,7 +196,7 @@ func TestParseToolCallArgsSupportsVerboseQuietAndHelp(t *testing.T) {
 }
 
 func TestParseToolCallArgsParsesExplicitJSONHelpFlag(t *testing.T) {
-	parsed, err := parseToolCallArgs([]string{"--help", "--json"}, bytes.NewBuffer(nil), true)
+	parsed, err := parseToolCallArgs([]string{"--help", "--json"}, bytes.NewBuffer(nil), true, outputModeText)
 	if err != nil {
 		t.Fatalf("parseToolCallArgs() error = %v", err)
 	}
@@ -188,7 +212,7 @@ func TestParseToolCallArgsParsesExplicitJSONHelpFlag(t *testing.T) {
 }
 
 func TestParseToolCallArgsParsesJSONHelpFlagBeforeHelp(t *testing.T) {
-	parsed, err := parseToolCallArgs([]string{"--json", "--help"}, bytes.NewBuffer(nil), true)
+	parsed, err := parseToolCallArgs([]string{"--json", "--help"}, bytes.NewBuffer(nil), true, outputModeText)
 	if err != nil {
 		t.Fatalf("parseToolCallArgs() error = %v", err)
 	}
@@ -201,13 +225,13 @@ func TestParseToolCallArgsParsesJSONHelpFlagBeforeHelp(t *testing.T) {
 }
 
 func TestParseToolCallArgsRejectsJSONFlagWithoutHelp(t *testing.T) {
-	if _, err := parseToolCallArgs([]string{"--json"}, bytes.NewBuffer(nil), true); err == nil {
+	if _, err := parseToolCallArgs([]string{"--json"}, bytes.NewBuffer(nil), true, outputModeText); err == nil {
 		t.Fatal("parseToolCallArgs() error = nil, want non-nil")
 	}
 }
 
 func TestParseToolCallArgsDoesNotTreatToolJSONAsJSONHelpFlag(t *testing.T) {
-	parsed, err := parseToolCallArgs([]string{"--help", "--tool-json"}, bytes.NewBuffer(nil), true)
+	parsed, err := parseToolCallArgs([]string{"--help", "--tool-json"}, bytes.NewBuffer(nil), true, outputModeText)
 	if err != nil {
 		t.Fatalf("parseToolCallArgs() error = %v", err)
 	}
@@ -223,7 +247,7 @@ func TestParseToolCallArgsDoesNotTreatToolJSONAsJSONHelpFlag(t *testing.T) {
 }
 
 func TestParseToolCallArgsPositionalJSONDoesNotTriggerJSONHelpFlag(t *testing.T) {
-	parsed, err := parseToolCallArgs([]string{"--help", `{"json":true}`}, bytes.NewBuffer(nil), true)
+	parsed, err := parseToolCallArgs([]string{"--help", `{"json":true}`}, bytes.NewBuffer(nil), true, outputModeText)
 	if err != nil {
 		t.Fatalf("parseToolCallArgs() error = %v", err)
 	}
@@ -239,7 +263,7 @@ func TestParseToolCallArgsPositionalJSONDoesNotTriggerJSONHelpFlag(t *testing.T)
 }
 
 func TestParseToolCallArgsPreservesNoPrefixedLiteralFlagAndArrayFlags(t *testing.T) {
-	parsed, err := parseToolCallArgs([]string{"--no-dry-run", "--tag=a", "--tag=b"}, bytes.NewBuffer(nil), true)
+	parsed, err := parseToolCallArgs([]string{"--no-dry-run", "--tag=a", "--tag=b"}, bytes.NewBuffer(nil), true, outputModeText)
 	if err != nil {
 		t.Fatalf("parseToolCallArgs() error = %v", err)
 	}
@@ -256,14 +280,14 @@ func TestParseToolCallArgsPreservesNoPrefixedLiteralFlagAndArrayFlags(t *testing
 }
 
 func TestParseToolCallArgsRejectsPositionalJSONMixedWithToolFlags(t *testing.T) {
-	_, err := parseToolCallArgs([]string{`{"query":"mcp"}`, "--page=2"}, bytes.NewBuffer(nil), true)
+	_, err := parseToolCallArgs([]string{`{"query":"mcp"}`, "--page=2"}, bytes.NewBuffer(nil), true, outputModeText)
 	if err == nil {
 		t.Fatal("parseToolCallArgs() error = nil, want non-nil")
 	}
 }
 
 func TestParseToolCallArgsAllowsPositionalJSONWithGlobalFlags(t *testing.T) {
-	parsed, err := parseToolCallArgs([]string{"--cache=30s", `{"query":"mcp"}`}, bytes.NewBuffer(nil), true)
+	parsed, err := parseToolCallArgs([]string{"--cache=30s", `{"query":"mcp"}`}, bytes.NewBuffer(nil), true, outputModeText)
 	if err != nil {
 		t.Fatalf("parseToolCallArgs() error = %v", err)
 	}
@@ -317,3 +341,422 @@ func TestParseFlagsRejectsInvalidFlagToken(t *testing.T) {
 		t.Fatal("parseFlags() error = nil, want non-nil")
 	}
 }
+
+func TestParseToolCallArgsExtractsCallTimeouts(t *testing.T) {
+	parsed, err := parseToolCallArgs([]string{"--timeout=30s", "--attempt-timeout", "5s", "--query=mcp"}, bytes.NewBuffer(nil), true, outputModeText)
+	if err != nil {
+		t.Fatalf("parseToolCallArgs() error = %v", err)
+	}
+	if parsed.timeout == nil || *parsed.timeout != 30*time.Second {
+		t.Fatalf("timeout = %v, want 30s", parsed.timeout)
+	}
+	if parsed.attemptTimeout == nil || *parsed.attemptTimeout != 5*time.Second {
+		t.Fatalf("attemptTimeout = %v, want 5s", parsed.attemptTimeout)
+	}
+	if parsed.toolArgs["query"] != "mcp" {
+		t.Fatalf("query = %v, want mcp", parsed.toolArgs["query"])
+	}
+
+	parsed, err = parseToolCallArgs([]string{"--timeout-per-attempt=2s"}, bytes.NewBuffer(nil), true, outputModeText)
+	if err != nil {
+		t.Fatalf("parseToolCallArgs(alias) error = %v", err)
+	}
+	if parsed.attemptTimeout == nil || *parsed.attemptTimeout != 2*time.Second {
+		t.Fatalf("attemptTimeout = %v, want 2s", parsed.attemptTimeout)
+	}
+
+	for _, args := range [][]string{{"--timeout=0"}, {"--timeout", "0s"}} {
+		parsed, err = parseToolCallArgs(args, bytes.NewBuffer(nil), true, outputModeText)
+		if err != nil {
+			t.Fatalf("parseToolCallArgs(%q) error = %v", args, err)
+		}
+		if parsed.timeout == nil || *parsed.timeout != 0 {
+			t.Fatalf("parseToolCallArgs(%q) timeout = %v, want explicit 0 (no timeout)", args, parsed.timeout)
+		}
+	}
+	if _, err := parseToolCallArgs([]string{"--timeout=-1s"}, bytes.NewBuffer(nil), true, outputModeText); err == nil {
+		t.Fatal("parseToolCallArgs(--timeout=-1s) error = nil, want non-nil")
+	}
+	if _, err := parseToolCallArgs([]string{"--attempt-timeout=0s"}, bytes.NewBuffer(nil), true, outputModeText); err == nil {
+		t.Fatal("parseToolCallArgs(--attempt-timeout=0s) error = nil, want non-nil")
+	}
+}
+
+func TestParseToolCallArgsReadsTimeoutFromEnv(t *testing.T) {
+	t.Setenv("JOB_DEADLINE", "45s")
+	parsed, err := parseToolCallArgs([]string{"--timeout-from-env", "JOB_DEADLINE", "--query=mcp"}, bytes.NewBuffer(nil), true, outputModeText)
+	if err != nil {
+		t.Fatalf("parseToolCallArgs() error = %v", err)
+	}
+	if parsed.timeout == nil || *parsed.timeout != 45*time.Second {
+		t.Fatalf("timeout = %v, want 45s", parsed.timeout)
+	}
+	if _, ok := parsed.toolArgs["timeout-from-env"]; ok {
+		t.Fatal("--timeout-from-env leaked into tool args")
+	}
+
+	t.Setenv("NO_DEADLINE", "0")
+	parsed, err = parseToolCallArgs([]string{"--timeout-from-env=NO_DEADLINE"}, bytes.NewBuffer(nil), true, outputModeText)
+	if err != nil {
+		t.Fatalf("parseToolCallArgs(NO_DEADLINE=0) error = %v", err)
+	}
+	if parsed.timeout == nil || *parsed.timeout != 0 {
+		t.Fatalf("timeout = %v, want 0 (no timeout) like --timeout=0", parsed.timeout)
+	}
+
+	t.Setenv("BAD_DEADLINE", "soon")
+	for _, args := range [][]string{
+		{"--timeout-from-env=MCPX_TEST_UNSET_DEADLINE"},
+		{"--timeout-from-env=BAD_DEADLINE"},
+		{"--timeout-from-env"},
+	} {
+		if _, err := parseToolCallArgs(args, bytes.NewBuffer(nil), true, outputModeText); err == nil {
+			t.Fatalf("parseToolCallArgs(%v) error = nil, want non-nil", args)
+		}
+	}
+}
+
+func TestParseToolCallArgsExtractsCacheErrorsOverride(t *testing.T) {
+	parsed, err := parseToolCallArgs([]string{"--cache-errors", "--query=mcp"}, bytes.NewBuffer(nil), true, outputModeText)
+	if err != nil {
+		t.Fatalf("parseToolCallArgs() error = %v", err)
+	}
+	if parsed.cacheErrors == nil || !*parsed.cacheErrors {
+		t.Fatalf("cacheErrors = %v, want true", parsed.cacheErrors)
+	}
+
+	parsed, err = parseToolCallArgs([]string{"--no-cache-for-errors"}, bytes.NewBuffer(nil), true, outputModeText)
+	if err != nil {
+		t.Fatalf("parseToolCallArgs(--no-cache-for-errors) error = %v", err)
+	}
+	if parsed.cacheErrors == nil || *parsed.cacheErrors {
+		t.Fatalf("cacheErrors = %v, want false", parsed.cacheErrors)
+	}
+	if _, ok := parsed.toolArgs["cache-for-errors"]; ok {
+		t.Fatal("--no-cache-for-errors leaked into tool args")
+	}
+
+	if _, err := parseToolCallArgs([]string{"--cache-errors", "--no-cache-for-errors"}, bytes.NewBuffer(nil), true, outputModeText); err == nil {
+		t.Fatal("parseToolCallArgs(both) error = nil, want non-nil")
+	}
+}
+
+func TestParseToolCallArgsMapsFreshAndStaleOKToCacheReads(t *testing.T) {
+	parsed, err := parseToolCallArgs([]string{"--fresh", "--cache=1m", "--query=mcp"}, bytes.NewBuffer(nil), true, outputModeText)
+	if err != nil {
+		t.Fatalf("parseToolCallArgs(--fresh) error = %v", err)
+	}
+	if !parsed.fresh || parsed.staleOK != nil {
+		t.Fatalf("fresh = %v, staleOK = %v; want fresh only", parsed.fresh, parsed.staleOK)
+	}
+	if parsed.cacheTTL == nil || *parsed.cacheTTL != time.Minute {
+		t.Fatalf("cacheTTL = %v, want 1m (--fresh still stores)", parsed.cacheTTL)
+	}
+
+	for _, args := range [][]string{{"--stale-ok", "10m"}, {"--stale-ok=10m"}} {
+		parsed, err := parseToolCallArgs(append(args, "--query=mcp"), bytes.NewBuffer(nil), true, outputModeText)
+		if err != nil {
+			t.Fatalf("parseToolCallArgs(%q) error = %v", args, err)
+		}
+		if parsed.staleOK == nil || *parsed.staleOK != 10*time.Minute || parsed.fresh {
+			t.Fatalf("parseToolCallArgs(%q) staleOK = %v, fresh = %v; want 10m only", args, parsed.staleOK, parsed.fresh)
+		}
+		if _, ok := parsed.toolArgs["stale-ok"]; ok {
+			t.Fatalf("parseToolCallArgs(%q) leaked --stale-ok into tool args", args)
+		}
+	}
+
+	for _, args := range [][]string{
+		{"--stale-ok"},
+		{"--stale-ok=0s"},
+		{"--stale-ok=soon"},
+		{"--fresh", "--stale-ok=1m"},
+		{"--fresh", "--no-cache"},
+		{"--no-cache", "--stale-ok=1m"},
+	} {
+		if _, err := parseToolCallArgs(args, bytes.NewBuffer(nil), true, outputModeText); err == nil {
+			t.Fatalf("parseToolCallArgs(%q) error = nil, want non-nil", args)
+		}
+	}
+}
+
+func TestParseToolCallArgsExtractsCombineContent(t *testing.T) {
+	for _, tt := range []struct {
+		args []string
+		want string
+	}{
+		{args: []string{"--combine-content", "text"}, want: "text"},
+		{args: []string{"--combine-content=JSON"}, want: "json"},
+		{args: []string{"--query=mcp"}, want: ""},
+	} {
+		parsed, err := parseToolCallArgs(tt.args, bytes.NewBuffer(nil), true, outputModeText)
+		if err != nil {
+			t.Fatalf("parseToolCallArgs(%q) error = %v", tt.args, err)
+		}
+		if parsed.combineContent != tt.want {
+			t.Fatalf("parseToolCallArgs(%q) combineContent = %q, want %q", tt.args, parsed.combineContent, tt.want)
+		}
+	}
+
+	for _, args := range [][]string{
+		{"--combine-content"},
+		{"--combine-content=lines"},
+		{"--combine-content=json", "--output-raw-bytes"},
+	} {
+		if _, err := parseToolCallArgs(args, bytes.NewBuffer(nil), true, outputModeText); err == nil {
+			t.Fatalf("parseToolCallArgs(%q) error = nil, want non-nil", args)
+		}
+	}
+}
+
+func TestParseToolCallArgsExtractsRetryAfterRetries(t *testing.T) {
+	parsed, err := parseToolCallArgs([]string{"--max-retries-respect-retry-after", "3", "--query=mcp"}, bytes.NewBuffer(nil), true, outputModeText)
+	if err != nil {
+		t.Fatalf("parseToolCallArgs() error = %v", err)
+	}
+	if parsed.retryAfterRetries != 3 {
+		t.Fatalf("retryAfterRetries = %d, want 3", parsed.retryAfterRetries)
+	}
+
+	if _, err := parseToolCallArgs([]string{"--max-retries-respect-retry-after=0"}, bytes.NewBuffer(nil), true, outputModeText); err == nil {
+		t.Fatal("parseToolCallArgs(--max-retries-respect-retry-after=0) error = nil, want non-nil")
+	}
+}
+
+func TestParseToolCallArgsExtractsRetryBudget(t *testing.T) {
+	parsed, err := parseToolCallArgs([]string{"--retry-budget", "30s", "--query=mcp"}, bytes.NewBuffer(nil), true, outputModeText)
+	if err != nil {
+		t.Fatalf("parseToolCallArgs() error = %v", err)
+	}
+	if parsed.retryBudget == nil || *parsed.retryBudget != 30*time.Second {
+		t.Fatalf("retryBudget = %v, want 30s", parsed.retryBudget)
+	}
+	if _, ok := parsed.toolArgs["retry-budget"]; ok {
+		t.Fatal("--retry-budget leaked into tool args")
+	}
+
+	if _, err := parseToolCallArgs([]string{"--retry-budget=0s"}, bytes.NewBuffer(nil), true, outputModeText); err == nil {
+		t.Fatal("parseToolCallArgs(--retry-budget=0s) error = nil, want non-nil")
+	}
+}
+
+func TestParseToolCallArgsExtractsIdempotencyKey(t *testing.T) {
+	parsed, err := parseToolCallArgs([]string{"--idempotency-key", "order-1", "--amount=5"}, bytes.NewBuffer(nil), true, outputModeText)
+	if err != nil {
+		t.Fatalf("parseToolCallArgs() error = %v", err)
+	}
+	if parsed.idempotencyKey != "order-1" {
+		t.Fatalf("idempotencyKey = %q, want %q", parsed.idempotencyKey, "order-1")
+	}
+	if _, ok := parsed.toolArgs["idempotency-key"]; ok {
+		t.Fatalf("toolArgs = %#v, want --idempotency-key kept out of tool args", parsed.toolArgs)
+	}
+
+	if _, err := parseToolCallArgs([]string{"--idempotency-key="}, bytes.NewBuffer(nil), true, outputModeText); err == nil {
+		t.Fatal("parseToolCallArgs(--idempotency-key=) error = nil, want non-nil")
+	}
+}
+
+func TestParseToolCallArgsStdinMergeAppliesFlagsOverStdin(t *testing.T) {
+	stdin := bytes.NewBufferString(`{"owner":"lydakis","repo":"mcpx","state":"open","labels":["bug"]}`)
+	parsed, err := parseToolCallArgs([]string{"--args-stdin-merge", "--state=closed", "--limit", "5"}, stdin, false, outputModeText)
+	if err != nil {
+		t.Fatalf("parseToolCallArgs() error = %v", err)
+	}
+
+	want := map[string]any{
+		"owner":  "lydakis",
+		"repo":   "mcpx",
+		"state":  "closed",
+		"labels": []any{"bug"},
+		"limit":  "5",
+	}
+	if !reflect.DeepEqual(parsed.toolArgs, want) {
+		t.Fatalf("toolArgs = %#v, want %#v", parsed.toolArgs, want)
+	}
+}
+
+func TestParseToolCallArgsCacheScope(t *testing.T) {
+	parsed, err := parseToolCallArgs([]string{"--cache-scope", "cwd", "--q=x"}, bytes.NewBuffer(nil), true, outputModeText)
+	if err != nil {
+		t.Fatalf("parseToolCallArgs() error = %v", err)
+	}
+	if parsed.cacheScope != "cwd" {
+		t.Fatalf("cacheScope = %q, want cwd", parsed.cacheScope)
+	}
+	if _, ok := parsed.toolArgs["cache-scope"]; ok {
+		t.Fatal("--cache-scope leaked into tool args")
+	}
+	if _, err := parseToolCallArgs([]string{"--cache-scope=project"}, bytes.NewBuffer(nil), true, outputModeText); err == nil {
+		t.Fatal("parseToolCallArgs(--cache-scope=project) error = nil, want non-nil")
+	}
+}
+
+func TestParseToolCallArgsReadsSingleParamFromStdin(t *testing.T) {
+	stdin := bytes.NewBufferString("# Notes\n\nline two\n")
+	parsed, err := parseToolCallArgs([]string{"--title=Notes", "--content@-", "--tool-tags=docs"}, stdin, false, outputModeText)
+	if err != nil {
+		t.Fatalf("parseToolCallArgs() error = %v", err)
+	}
+
+	want := map[string]any{
+		"title":   "Notes",
+		"content": "# Notes\n\nline two\n",
+		"tags":    "docs",
+	}
+	if !reflect.DeepEqual(parsed.toolArgs, want) {
+		t.Fatalf("toolArgs = %#v, want %#v", parsed.toolArgs, want)
+	}
+}
+
+func TestParseToolCallArgsRejectsInvalidStdinParam(t *testing.T) {
+	if _, err := parseToolCallArgs([]string{"--a@-", "--b@-"}, bytes.NewBufferString("x"), false, outputModeText); err == nil || !strings.Contains(err.Error(), "only one parameter") {
+		t.Fatalf("parseToolCallArgs(two @-) error = %v, want only-one error", err)
+	}
+	if _, err := parseToolCallArgs([]string{"--content@-"}, bytes.NewBuffer(nil), true, outputModeText); err == nil {
+		t.Fatal("parseToolCallArgs(terminal stdin) error = nil, want non-nil")
+	}
+	if _, err := parseToolCallArgs([]string{"--content@-", "--args-stdin-merge"}, bytes.NewBufferString("{}"), false, outputModeText); err == nil {
+		t.Fatal("parseToolCallArgs(@- with --args-stdin-merge) error = nil, want non-nil")
+	}
+}
+
+func TestParseToolCallArgsStdinMergeRejectsTerminalAndPositionalJSON(t *testing.T) {
+	if _, err := parseToolCallArgs([]string{"--args-stdin-merge", "--state=closed"}, bytes.NewBuffer(nil), true, outputModeText); err == nil {
+		t.Fatal("parseToolCallArgs(terminal stdin) error = nil, want non-nil")
+	}
+	if _, err := parseToolCallArgs([]string{"--args-stdin-merge", `{"state":"open"}`}, bytes.NewBufferString(`{}`), false, outputModeText); err == nil {
+		t.Fatal("parseToolCallArgs(positional JSON) error = nil, want non-nil")
+	}
+	if _, err := parseToolCallArgs([]string{"--args-stdin-merge"}, bytes.NewBufferString(`[1]`), false, outputModeText); err == nil {
+		t.Fatal("parseToolCallArgs(non-object stdin) error = nil, want non-nil")
+	}
+}
+
+func TestCallToolOutputRawBytesWritesContentUnchanged(t *testing.T) {
+	oldOut, oldErr := rootStdout, rootStderr
+	defer func() { rootStdout, rootStderr = oldOut, oldErr }()
+	var stdout, stderr bytes.Buffer
+	rootStdout, rootStderr = &stdout, &stderr
+	stubConfirmTerminal(t, false, "")
+
+	png := []byte{0x89, 'P', 'N', 'G', 0x00, 0xff}
+	client := stubDaemonClient{sendFn: func(req *ipc.Request) (*ipc.Response, error) {
+		if !req.RawBytes {
+			t.Fatal("request RawBytes = false, want true")
+		}
+		return &ipc.Response{Content: png}, nil
+	}}
+
+	if code := callTool(client, "shots", "capture", []string{"--url=x", "--output-raw-bytes"}, "", false); code != ipc.ExitOK {
+		t.Fatalf("callTool() = %d, want %d (stderr=%q)", code, ipc.ExitOK, stderr.String())
+	}
+	if !bytes.Equal(stdout.Bytes(), png) {
+		t.Fatalf("stdout = %v, want %v", stdout.Bytes(), png)
+	}
+
+	if _, err := parseToolCallArgs([]string{"--output-raw-bytes", "--repeat-until=$.done=true"}, bytes.NewBuffer(nil), true, outputModeText); err == nil {
+		t.Fatal("parseToolCallArgs(--output-raw-bytes --repeat-until) error = nil, want non-nil")
+	}
+}
+
+func TestCallToolRawSendsRawRequest(t *testing.T) {
+	oldOut, oldErr := rootStdout, rootStderr
+	defer func() { rootStdout, rootStderr = oldOut, oldErr }()
+	var stdout, stderr bytes.Buffer
+	rootStdout, rootStderr = &stdout, &stderr
+	stubConfirmTerminal(t, false, "")
+
+	result := `{"content":[{"type":"text","text":"hi"}]}` + "\n"
+	client := stubDaemonClient{sendFn: func(req *ipc.Request) (*ipc.Response, error) {
+		if !req.Raw {
+			t.Fatal("request Raw = false, want true")
+		}
+		return &ipc.Response{Content: []byte(result)}, nil
+	}}
+
+	if code := callTool(client, "github", "search", []string{"--query=x", "--raw"}, "", false); code != ipc.ExitOK {
+		t.Fatalf("callTool() = %d, want %d (stderr=%q)", code, ipc.ExitOK, stderr.String())
+	}
+	if stdout.String() != result {
+		t.Fatalf("stdout = %q, want %q", stdout.String(), result)
+	}
+
+	if _, err := parseToolCallArgs([]string{"--raw", "--output-raw-bytes"}, bytes.NewBuffer(nil), true, outputModeText); err == nil {
+		t.Fatal("parseToolCallArgs(--raw --output-raw-bytes) error = nil, want non-nil")
+	}
+}
+
+func TestParseToolCallArgsOutputEncoding(t *testing.T) {
+	parsed, err := parseToolCallArgs([]string{"--output-encoding", "HEX", "--id=1"}, bytes.NewBuffer(nil), true, outputModeText)
+	if err != nil {
+		t.Fatalf("parseToolCallArgs() error = %v", err)
+	}
+	if parsed.outputEncoding != outputEncodingHex {
+		t.Fatalf("outputEncoding = %q, want %q", parsed.outputEncoding, outputEncodingHex)
+	}
+	if _, ok := parsed.toolArgs["output-encoding"]; ok {
+		t.Fatal("--output-encoding leaked into tool args")
+	}
+
+	if _, err := parseToolCallArgs([]string{"--output-encoding=latin1"}, bytes.NewBuffer(nil), true, outputModeText); err == nil {
+		t.Fatal("parseToolCallArgs(--output-encoding=latin1) error = nil, want non-nil")
+	}
+	if _, err := parseToolCallArgs([]string{"--output-encoding=base64", "--flatten"}, bytes.NewBuffer(nil), true, outputModeText); err == nil {
+		t.Fatal("parseToolCallArgs(--output-encoding=base64 --flatten) error = nil, want non-nil")
+	}
+}
+
+func TestCallToolIgnoreExitReturnsZeroOnFailure(t *testing.T) {
+	oldOut, oldErr := rootStdout, rootStderr
+	defer func() { rootStdout, rootStderr = oldOut, oldErr }()
+	var stdout, stderr bytes.Buffer
+	rootStdout, rootStderr = &stdout, &stderr
+	stubConfirmTerminal(t, false, "")
+
+	client := stubDaemonClient{sendFn: func(req *ipc.Request) (*ipc.Response, error) {
+		return &ipc.Response{ExitCode: ipc.ExitToolErr, Content: []byte("not found\n")}, nil
+	}}
+
+	if code := callTool(client, "github", "get-repo", []string{"--repo=missing"}, "", false); code != ipc.ExitToolErr {
+		t.Fatalf("callTool() = %d, want %d without --ignore-exit", code, ipc.ExitToolErr)
+	}
+	stderr.Reset()
+	if code := callTool(client, "github", "get-repo", []string{"--repo=missing", "--ignore-exit"}, "", false); code != ipc.ExitOK {
+		t.Fatalf("callTool(--ignore-exit) = %d, want %d", code, ipc.ExitOK)
+	}
+	if stderr.String() != "not found\n" {
+		t.Fatalf("stderr = %q, want the tool error still written", stderr.String())
+	}
+
+	if code := callTool(client, "github", "get-repo", []string{"--ignore-exit", "--timeout=bogus"}, "", false); code != ipc.ExitUsageErr {
+		t.Fatalf("callTool(invalid flag, --ignore-exit) = %d, want %d", code, ipc.ExitUsageErr)
+	}
+}
+
+func TestParseToolCallArgsContentOnly(t *testing.T) {
+	for arg, want := range map[string]string{
+		"--structured-only":        "structured",
+		"--output-structured-only": "structured",
+		"--text-only":              "text",
+		"--output-text-only":       "text",
+	} {
+		parsed, err := parseToolCallArgs([]string{arg, "--query=x"}, bytes.NewBuffer(nil), true, outputModeText)
+		if err != nil {
+			t.Fatalf("parseToolCallArgs(%s) error = %v", arg, err)
+		}
+		if parsed.contentOnly != want {
+			t.Fatalf("parseToolCallArgs(%s) contentOnly = %q, want %q", arg, parsed.contentOnly, want)
+		}
+	}
+
+	if _, err := parseToolCallArgs([]string{"--structured-only", "--text-only"}, bytes.NewBuffer(nil), true, outputModeText); err == nil {
+		t.Fatal("parseToolCallArgs(--structured-only --text-only) error = nil, want non-nil")
+	}
+	if _, err := parseToolCallArgs([]string{"--text-only", "--raw"}, bytes.NewBuffer(nil), true, outputModeText); err == nil {
+		t.Fatal("parseToolCallArgs(--text-only --raw) error = nil, want non-nil")
+	}
+	if _, err := parseToolCallArgs([]string{"--text-only", "--combine-content=json"}, bytes.NewBuffer(nil), true, outputModeText); err != nil {
+		t.Fatalf("parseToolCallArgs(--text-only --combine-content) error = %v, want nil", err)
+	}
+}
@@ -0,0 +1,113 @@
+package cli
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+)
+
+// flatField is one leaf of a flattened JSON document.
+type flatField struct {
+	path  string
+	value any
+}
+
+// flattenJSON decodes a tool result and returns its leaves in document
+// order: object keys sorted at each level, array elements by index. Paths
+// join object keys with "." and index arrays as "[i]". Empty objects and
+// arrays are kept as leaves so their keys still show up.
+func flattenJSON(content []byte) ([]flatField, error) {
+	dec := json.NewDecoder(bytes.NewReader(content))
+	dec.UseNumber()
+	var doc any
+	if err := dec.Decode(&doc); err != nil {
+		return nil, fmt.Errorf("--flatten requires a JSON result: %w", err)
+	}
+	if dec.More() {
+		return nil, fmt.Errorf("--flatten requires a single JSON document")
+	}
+
+	var fields []flatField
+	var walk func(path string, v any)
+	walk = func(path string, v any) {
+		switch node := v.(type) {
+		case map[string]any:
+			if len(node) == 0 {
+				fields = append(fields, flatField{path: path, value: node})
+				return
+			}
+			keys := make([]string, 0, len(node))
+			for key := range node {
+				keys = append(keys, key)
+			}
+			sort.Strings(keys)
+			for _, key := range keys {
+				child := key
+				if path != "" {
+					child = path + "." + key
+				}
+				walk(child, node[key])
+			}
+		case []any:
+			if len(node) == 0 {
+				fields = append(fields, flatField{path: path, value: node})
+				return
+			}
+			for i, item := range node {
+				walk(path+"["+strconv.Itoa(i)+"]", item)
+			}
+		default:
+			fields = append(fields, flatField{path: path, value: node})
+		}
+	}
+	walk("", doc)
+	return fields, nil
+}
+
+// writeFlattened prints fields as "path = value" lines, or as one flat JSON
+// object keyed by path when output is JSON. Strings print unquoted in text
+// mode so values paste cleanly into spreadsheets.
+func writeFlattened(w io.Writer, fields []flatField, output outputMode) error {
+	if output.isJSON() {
+		flat := make(map[string]any, len(fields))
+		for _, field := range fields {
+			flat[field.path] = field.value
+		}
+		data, err := json.Marshal(flat)
+		if err != nil {
+			return fmt.Errorf("encoding flattened result: %w", err)
+		}
+		_, err = w.Write(append(data, '\n'))
+		return err
+	}
+
+	var buf bytes.Buffer
+	for _, field := range fields {
+		value, err := flatTextValue(field.value)
+		if err != nil {
+			return err
+		}
+		if field.path == "" {
+			buf.WriteString(value)
+		} else {
+			buf.WriteString(field.path + " = " + value)
+		}
+		buf.WriteByte('\n')
+	}
+	_, err := w.Write(buf.Bytes())
+	return err
+}
+
+func flatTextValue(v any) (string, error) {
+	if s, ok := v.(string); ok {
+		return s, nil
+	}
+	data, err := json.Marshal(v)
+	if err != nil {
+		return "", fmt.Errorf("encoding flattened value: %w", err)
+	}
+	return string(data), nil
+}
@@ -0,0 +1,113 @@
+package cli
+
+import (
+	"bytes"
+	"encoding/json"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/lydakis/mcpx/internal/ipc"
+)
+
+func TestFlattenJSONNestedObjectsAndArrays(t *testing.T) {
+	content := []byte(`{"repo":{"name":"mcpx","stars":42,"private":false},"tags":["cli","mcp"],"owners":[{"login":"a"},{"login":"b","admin":true}],"meta":{},"next":null}`)
+
+	fields, err := flattenJSON(content)
+	if err != nil {
+		t.Fatalf("flattenJSON() error = %v", err)
+	}
+	var buf bytes.Buffer
+	if err := writeFlattened(&buf, fields, outputModeText); err != nil {
+		t.Fatalf("writeFlattened() error = %v", err)
+	}
+	want := "meta = {}\n" +
+		"next = null\n" +
+		"owners[0].login = a\n" +
+		"owners[1].admin = true\n" +
+		"owners[1].login = b\n" +
+		"repo.name = mcpx\n" +
+		"repo.private = false\n" +
+		"repo.stars = 42\n" +
+		"tags[0] = cli\n" +
+		"tags[1] = mcp\n"
+	if got := buf.String(); got != want {
+		t.Fatalf("flattened text =\n%s\nwant\n%s", got, want)
+	}
+}
+
+func TestFlattenJSONKeepsArrayIndexOrder(t *testing.T) {
+	items := make([]int, 12)
+	for i := range items {
+		items[i] = i
+	}
+	content, _ := json.Marshal(map[string]any{"items": items})
+
+	fields, err := flattenJSON(content)
+	if err != nil {
+		t.Fatalf("flattenJSON() error = %v", err)
+	}
+	if len(fields) != 12 || fields[2].path != "items[2]" || fields[10].path != "items[10]" {
+		t.Fatalf("fields = %#v, want items[0]..items[11] in index order", fields)
+	}
+}
+
+func TestFlattenJSONRejectsNonJSON(t *testing.T) {
+	if _, err := flattenJSON([]byte("plain text\n")); err == nil {
+		t.Fatal("flattenJSON(plain text) error = nil, want non-nil")
+	}
+}
+
+func TestCallToolFlattenJSONEmitsFlatObject(t *testing.T) {
+	oldOut, oldErr := rootStdout, rootStderr
+	defer func() { rootStdout, rootStderr = oldOut, oldErr }()
+	var stdout, stderr bytes.Buffer
+	rootStdout, rootStderr = &stdout, &stderr
+	stubConfirmTerminal(t, false, "")
+
+	client := stubDaemonClient{sendFn: func(req *ipc.Request) (*ipc.Response, error) {
+		return &ipc.Response{Content: []byte(`{"a":{"b":[1,{"c":"x"}]}}` + "\n")}, nil
+	}}
+
+	if code := callTool(client, "svc", "get", []string{"--flatten", "--json"}, "", false); code != ipc.ExitOK {
+		t.Fatalf("callTool() = %d, want %d (stderr=%q)", code, ipc.ExitOK, stderr.String())
+	}
+	var got map[string]any
+	if err := json.Unmarshal(stdout.Bytes(), &got); err != nil {
+		t.Fatalf("stdout is not JSON: %v (%q)", err, stdout.String())
+	}
+	want := map[string]any{"a.b[0]": float64(1), "a.b[1].c": "x"}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("flat object = %#v, want %#v", got, want)
+	}
+
+	if _, err := parseToolCallArgs([]string{"--json"}, bytes.NewBuffer(nil), true, outputModeText); err == nil {
+		t.Fatal("parseToolCallArgs(--json) error = nil, want non-nil without --flatten")
+	}
+}
+
+func TestCallToolFlattenRejectsNonJSONContentTypeHint(t *testing.T) {
+	oldOut, oldErr := rootStdout, rootStderr
+	defer func() { rootStdout, rootStderr = oldOut, oldErr }()
+	var stdout, stderr bytes.Buffer
+	rootStdout, rootStderr = &stdout, &stderr
+	stubConfirmTerminal(t, false, "")
+
+	client := stubDaemonClient{sendFn: func(req *ipc.Request) (*ipc.Response, error) {
+		return &ipc.Response{
+			Content:     []byte("/tmp/mcpx-image-1.png\n"),
+			ContentType: "image/png",
+			Encoding:    ipc.EncodingPath,
+		}, nil
+	}}
+
+	if code := callTool(client, "svc", "shot", []string{"--flatten"}, "", false); code != ipc.ExitUsageErr {
+		t.Fatalf("callTool() = %d, want %d", code, ipc.ExitUsageErr)
+	}
+	if !strings.Contains(stderr.String(), "got image/png (path)") {
+		t.Fatalf("stderr = %q, want content type in error", stderr.String())
+	}
+	if stdout.Len() != 0 {
+		t.Fatalf("stdout = %q, want empty", stdout.String())
+	}
+}
@@ -4,6 +4,9 @@ import (
 	"encoding/json"
 	"fmt"
 	"io"
+	"strings"
+
+	"github.com/lydakis/mcpx/internal/ipc"
 )
 
 func parseToolHelpPayload(raw []byte) (name, description string, inputSchema map[string]any, outputSchema map[string]any) {
@@ -33,11 +36,17 @@ func parseToolHelpPayload(raw []byte) (name, description string, inputSchema map
 	return "", "", nil, nil
 }
 
-func printToolHelp(w io.Writer, server, tool, description string, inputSchema, outputSchema map[string]any) {
+func printToolHelp(w io.Writer, server, tool, description string, inputSchema, outputSchema map[string]any, annotations toolAnnotations) {
 	fmt.Fprintf(w, "Usage: mcpx %s %s [FLAGS]\n", server, tool)
 	if description != "" {
 		fmt.Fprintf(w, "\nDescription:\n  %s\n", description)
 	}
+	if badges := annotations.badges(); len(badges) > 0 {
+		fmt.Fprintf(w, "\nAnnotations: %s\n", strings.Join(badges, ", "))
+		if annotations.destructive() {
+			fmt.Fprintln(w, "  Calls from a terminal ask for confirmation; pass --yes to skip.")
+		}
+	}
 
 	fmt.Fprintln(w, "\nOptions:")
 	fmt.Fprintln(w, "  Tool flags:")
@@ -71,9 +80,36 @@ func printToolHelp(w io.Writer, server, tool, description string, inputSchema, o
 	}
 }
 
+// showToolExamples prints only the example invocations from tool help, one
+// per line, or as a JSON array of strings.
+func showToolExamples(client daemonRequester, server, tool, cwd string, output outputMode, canonicalizeSource bool) int {
+	name, inputSchema, _, code := fetchToolSchemaPayload(client, server, tool, cwd, canonicalizeSource)
+	if code != ipc.ExitOK {
+		return code
+	}
+
+	examples := toolExamples(server, name, inputSchema)
+	var data []byte
+	if output.isJSON() {
+		encoded, err := json.Marshal(examples)
+		if err != nil {
+			fmt.Fprintf(rootStderr, "mcpx: encoding examples: %v\n", err)
+			return ipc.ExitInternal
+		}
+		data = append(encoded, '\n')
+	} else {
+		data = []byte(strings.Join(examples, "\n") + "\n")
+	}
+	if err := writePayload(rootStdout, "examples", data); err != nil {
+		fmt.Fprintf(rootStderr, "mcpx: %v\n", err)
+		return ipc.ExitInternal
+	}
+	return ipc.ExitOK
+}
+
 func printTypeToFlagForms(w io.Writer) {
 	fmt.Fprintln(w, "    string/number/integer: --key=value")
-	fmt.Fprintln(w, "    boolean: --flag / --no-flag")
+	fmt.Fprintln(w, "    boolean: --flag / --no-flag / --flag=true|false / --flag=null (omit)")
 	fmt.Fprintln(w, "    array: --item=a --item=b OR --items='[\"a\",\"b\"]'")
 	fmt.Fprintln(w, "    object: --config='{\"k\":\"v\"}'")
 }
@@ -100,9 +136,112 @@ func printToolInputFlags(w io.Writer, schema map[string]any) {
 func printGlobalFlags(w io.Writer) {
 	fmt.Fprintln(w, "    --cache <duration>   Cache this tool response for a TTL (for example: 30s, 5m).")
 	fmt.Fprintln(w, "    --no-cache           Disable cache for this call.")
+	fmt.Fprintln(w, "    --cache-errors       Also cache a tool error result, briefly (see cache_errors).")
+	fmt.Fprintln(w, "    --no-cache-for-errors")
+	fmt.Fprintln(w, "                         Never cache a tool error result, even with cache_errors set.")
+	fmt.Fprintln(w, "    --fresh              Skip the cache lookup but still cache the new result.")
+	fmt.Fprintln(w, "    --stale-ok <duration>")
+	fmt.Fprintln(w, "                         Accept a cached response up to this age, even past its TTL.")
+	fmt.Fprintln(w, "    --explain-cache      Print why this call was or was not cached, and hit/miss/age, to stderr.")
+	fmt.Fprintln(w, "    --cache-scope <global|cwd>")
+	fmt.Fprintln(w, "                         Key cached responses per directory and server definition (cwd)")
+	fmt.Fprintln(w, "                         or share them across directories (global, default; see cache_scope).")
+	fmt.Fprintln(w, "    --on-error <cmd>     Run cmd (no shell) when the call fails; error text on stdin and $MCPX_ERROR.")
+	fmt.Fprintln(w, "    --soft-fail          On failure, print a JSON error object to stdout and exit 0.")
+	fmt.Fprintln(w, "                         Alias: --json-errors-to-stdout.")
+	fmt.Fprintln(w, "    --ignore-exit        Exit 0 whatever the call's outcome; output and errors print as usual.")
+	fmt.Fprintln(w, "    --param-default <name>=<value>")
+	fmt.Fprintln(w, "                         Save a default for this tool's parameter instead of calling it.")
+	fmt.Fprintln(w, "    --param-file-json <name>=<path>")
+	fmt.Fprintln(w, "                         Set an object or array parameter from a JSON file.")
+	fmt.Fprintln(w, "    --param-prompt <name>")
+	fmt.Fprintln(w, "                         Read a parameter from the terminal without echo (repeatable).")
+	fmt.Fprintln(w, "    --print-curl         Print an equivalent curl command (HTTP servers) without sending.")
+	fmt.Fprintln(w, "    --confirm            Ask for confirmation before calling.")
+	fmt.Fprintln(w, "    --yes                Skip the confirmation destructive tools get on a terminal.")
+	fmt.Fprintln(w, "    --dry-run            Print the backend server, resolved tool, final args, and cache TTL")
+	fmt.Fprintln(w, "                         as JSON without calling.")
+	fmt.Fprintln(w, "    --sample-output      Print a sample JSON document from the output schema without calling.")
+	fmt.Fprintln(w, "                         Alias: --output-schema-sample.")
+	fmt.Fprintln(w, "    --dump-schema-md     Print the input and output schemas as Markdown tables without calling.")
+	fmt.Fprintln(w, "    --examples           Print only the example invocations from this help (--json for an array).")
+	fmt.Fprintln(w, "    --param-required-check")
+	fmt.Fprintln(w, "                         List required parameters the given flags leave unset; exit 2 if any.")
+	fmt.Fprintln(w, "    --timeout <duration> Abort the call when this total budget is spent (for example: 30s);")
+	fmt.Fprintln(w, "                         overrides request_timeout, and 0 means no timeout.")
+	fmt.Fprintln(w, "    --timeout-from-env <VAR>")
+	fmt.Fprintln(w, "                         Read the --timeout duration from environment variable VAR.")
+	fmt.Fprintln(w, "    --attempt-timeout <duration>")
+	fmt.Fprintln(w, "                         Cap each attempt; a timed-out attempt is retried within --timeout.")
+	fmt.Fprintln(w, "                         Alias: --timeout-per-attempt.")
+	fmt.Fprintln(w, "    --warn-slow <duration>")
+	fmt.Fprintln(w, "                         Warn on stderr when the call takes longer than this; the result")
+	fmt.Fprintln(w, "                         is returned as usual. Alias: --max-response-time-warn.")
+	fmt.Fprintln(w, "    --max-retries-respect-retry-after <n>")
+	fmt.Fprintln(w, "                         Retry up to n times when an HTTP server answers 429 with Retry-After,")
+	fmt.Fprintln(w, "                         waiting as long as it asks.")
+	fmt.Fprintln(w, "    --retry-budget <duration>")
+	fmt.Fprintln(w, "                         Retry transient failures with backoff until this much time has passed.")
+	fmt.Fprintln(w, "    --repeat-until <path>=<value>")
+	fmt.Fprintln(w, "                         Re-call every --interval (default 2s) until the JSON result has value")
+	fmt.Fprintln(w, "                         at path (for example $.status=done); print only the final result.")
+	fmt.Fprintln(w, "                         Fails after --max-wait (default 5m).")
+	fmt.Fprintln(w, "    --map-exit <path>    Exit with a code picked by a JSON result field: ok/success/succeeded/")
+	fmt.Fprintln(w, "                         passed/true -> 0, error/failed/failure/false -> 1; other values exit 1.")
+	fmt.Fprintln(w, "    --map-exit-rule <value>=<code>")
+	fmt.Fprintln(w, "                         Map one more --map-exit value to an exit code (repeatable; wins).")
+	fmt.Fprintln(w, "    --capture-stderr     Forward what a stdio server writes to stderr during this call.")
+	fmt.Fprintln(w, "    --output-raw-bytes   Write the result's single content block (image, audio, blob, or text)")
+	fmt.Fprintln(w, "                         to stdout as raw bytes, with no trailing newline.")
+	fmt.Fprintln(w, "    --raw                Print the full MCP CallToolResult as JSON (content block types, _meta)")
+	fmt.Fprintln(w, "                         instead of the unwrapped output. Never cached.")
+	fmt.Fprintln(w, "    --combine-content <text|json>")
+	fmt.Fprintln(w, "                         Join several content blocks with no separator (text) or as a JSON")
+	fmt.Fprintln(w, "                         array of strings (json) instead of one per line.")
+	fmt.Fprintln(w, "    --structured-only    Print only the result's structured content; fail if it has none.")
+	fmt.Fprintln(w, "                         Alias: --output-structured-only.")
+	fmt.Fprintln(w, "    --text-only          Print only the result's text blocks; fail if it has none.")
+	fmt.Fprintln(w, "                         Alias: --output-text-only.")
+	fmt.Fprintln(w, "    --output-encoding <utf8|base64|hex>")
+	fmt.Fprintln(w, "                         Encode successful output for text pipelines (default utf8: as is).")
+	fmt.Fprintln(w, "    --flatten            Print the JSON result as path = value lines (arrays indexed);")
+	fmt.Fprintln(w, "                         with --json, as one flat object.")
+	fmt.Fprintln(w, "    --jsonl-field <path> Print each element of the array at <path> of a JSON result as one")
+	fmt.Fprintln(w, "                         compact JSON line (\"$\" for a top-level array).")
+	fmt.Fprintln(w, "    --output-file <path> Write the successful result to <path> (truncating) instead of stdout.")
+	fmt.Fprintln(w, "    --output-file-per-item <dir>")
+	fmt.Fprintln(w, "                         Write each element of a JSON array result to <dir>/<name>.json.")
+	fmt.Fprintln(w, "    --tee <path>         Print the successful result and also save it to <path> (truncating).")
+	fmt.Fprintln(w, "    --name-field <path>  Name each --output-file-per-item file by the value at <path> in the")
+	fmt.Fprintln(w, "                         element (default: its index).")
+	fmt.Fprintln(w, "    --redact <path>      Print \"***\" for the value at <path> of a JSON result (repeatable).")
+	fmt.Fprintln(w, "    --progress           Print the server's progress notifications to stderr as they arrive.")
+	fmt.Fprintln(w, "    --header KEY=VALUE   Add an HTTP header to this call (repeatable; HTTP servers only).")
+	fmt.Fprintln(w, "    --headers-from-file <path>")
+	fmt.Fprintln(w, "                         Add the file's \"Name: Value\" lines as headers; --header wins.")
+	fmt.Fprintln(w, "    --idempotency-key <key>")
+	fmt.Fprintln(w, "                         Forward a deduplication key (Idempotency-Key header on HTTP servers;")
+	fmt.Fprintln(w, "                         stdio tools need tools.<tool>.idempotency_key = \"arg:<param>\").")
+	fmt.Fprintln(w, "    --save-as <name>     Save this call's server, tool, and arguments as a recipe, then call;")
+	fmt.Fprintln(w, "                         replay it with mcpx run <name>.")
+	fmt.Fprintln(w, "    --<param>@-          Read one parameter's value from stdin; other flags come from argv.")
+	fmt.Fprintln(w, "    --args-template-file <path>")
+	fmt.Fprintln(w, "                         Use a JSON args template with ${NAME} placeholders filled from --var")
+	fmt.Fprintln(w, "                         or the environment; --key=value flags apply on top.")
+	fmt.Fprintln(w, "    --var <name>=<value> Set a template placeholder (repeatable; wins over the environment).")
+	fmt.Fprintln(w, "    --args-stdin-merge   Read a base JSON object from stdin and apply --key=value flags on top")
+	fmt.Fprintln(w, "                         (flags win).")
+	fmt.Fprintln(w, "    --input-from-prev    Like --args-stdin-merge for `mcpx a | mcpx b`: a CallToolResult on")
+	fmt.Fprintln(w, "                         stdin is unwrapped to its structured or JSON text content.")
+	fmt.Fprintln(w, "    --no-schema-cache    Fetch the tool schema from the server instead of the 5-minute")
+	fmt.Fprintln(w, "                         schema cache used by --help and argument checks.")
 	fmt.Fprintln(w, "    --verbose, -v        Print verbose diagnostics to stderr.")
 	fmt.Fprintln(w, "    --quiet, -q          Suppress stderr output.")
+	fmt.Fprintln(w, "    --show-schema-diff <server>")
+	fmt.Fprintln(w, "                         Compare this tool's schema with the same tool on <server>.")
 	fmt.Fprintln(w, "    --json               With --help, emit raw schema JSON from mcpx.")
+	fmt.Fprintln(w, "                         With --show-schema-diff, emit diff entries as JSON.")
+	fmt.Fprintln(w, "    --yaml               With --help, emit the schema as YAML.")
 	fmt.Fprintln(w, "    --help, -h           Show this help output.")
 }
 
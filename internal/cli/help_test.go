@@ -2,9 +2,14 @@ package cli
 
 import (
 	"bytes"
+	"encoding/json"
 	"os"
 	"path/filepath"
+	"reflect"
+	"strings"
 	"testing"
+
+	"github.com/lydakis/mcpx/internal/ipc"
 )
 
 func TestPrintToolHelpIncludesOutputSchemaSection(t *testing.T) {
@@ -28,7 +33,7 @@ func TestPrintToolHelpIncludesOutputSchemaSection(t *testing.T) {
 	}
 
 	var out bytes.Buffer
-	printToolHelp(&out, "github", "search-repositories", "Search repos", input, output)
+	printToolHelp(&out, "github", "search-repositories", "Search repos", input, output, toolAnnotations{})
 	got := out.String()
 
 	if !bytes.Contains(out.Bytes(), []byte("Usage: mcpx github search-repositories [FLAGS]")) {
@@ -48,7 +53,7 @@ func TestPrintToolHelpShowsUndeclaredOutput(t *testing.T) {
 	}
 
 	var out bytes.Buffer
-	printToolHelp(&out, "github", "search-repositories", "", input, nil)
+	printToolHelp(&out, "github", "search-repositories", "", input, nil, toolAnnotations{})
 	got := out.String()
 	if !bytes.Contains(out.Bytes(), []byte("Output: not declared by server")) {
 		t.Fatalf("expected undeclared output message, got: %q", got)
@@ -98,7 +103,7 @@ func TestPrintToolHelpFlattensNestedOutputPaths(t *testing.T) {
 	}
 
 	var out bytes.Buffer
-	printToolHelp(&out, "github", "search-repositories", "", input, output)
+	printToolHelp(&out, "github", "search-repositories", "", input, output, toolAnnotations{})
 	got := out.String()
 	if !bytes.Contains(out.Bytes(), []byte("items[].name <string>")) {
 		t.Fatalf("missing nested array field path: %q", got)
@@ -121,7 +126,7 @@ func TestPrintToolHelpHandlesRootArrayOutputSchema(t *testing.T) {
 	}
 
 	var out bytes.Buffer
-	printToolHelp(&out, "github", "list-results", "", input, output)
+	printToolHelp(&out, "github", "list-results", "", input, output, toolAnnotations{})
 	got := out.String()
 
 	if !bytes.Contains(out.Bytes(), []byte("[] <array>")) {
@@ -172,7 +177,7 @@ func TestPrintToolHelpIncludesOptionSemanticsAndExamples(t *testing.T) {
 	}
 
 	var out bytes.Buffer
-	printToolHelp(&out, "github", "search-repositories", "Search repos", input, nil)
+	printToolHelp(&out, "github", "search-repositories", "Search repos", input, nil, toolAnnotations{})
 	got := out.String()
 
 	if !bytes.Contains(out.Bytes(), []byte("--query <string> (required)")) {
@@ -208,7 +213,7 @@ func TestPrintToolHelpShowsGlobalFlagsAndCollisionNamespace(t *testing.T) {
 	}
 
 	var out bytes.Buffer
-	printToolHelp(&out, "github", "search-repositories", "", input, nil)
+	printToolHelp(&out, "github", "search-repositories", "", input, nil, toolAnnotations{})
 	got := out.String()
 
 	if !bytes.Contains(out.Bytes(), []byte("Tool flags:")) {
@@ -276,6 +281,32 @@ func TestToolExamplesUseToolPrefixForReservedFlags(t *testing.T) {
 	}
 }
 
+func TestToolExamplesUseToolPrefixForYAMLParam(t *testing.T) {
+	input := map[string]any{
+		"type": "object",
+		"properties": map[string]any{
+			"yaml": map[string]any{"type": "string"},
+		},
+		"required": []any{"yaml"},
+	}
+
+	examples := toolExamples("github", "render", input)
+	if len(examples) == 0 {
+		t.Fatal("toolExamples() returned no examples")
+	}
+	if !bytes.Contains([]byte(examples[0]), []byte("--tool-yaml")) {
+		t.Fatalf("expected yaml param to use --tool- prefix, got %q", examples[0])
+	}
+
+	parsed, err := parseToolCallArgs([]string{"--tool-yaml=a: 1"}, nil, true, outputModeText)
+	if err != nil {
+		t.Fatalf("parseToolCallArgs(--tool-yaml) error = %v", err)
+	}
+	if parsed.toolArgs["yaml"] != "a: 1" || parsed.output.isYAML() {
+		t.Fatalf("parseToolCallArgs(--tool-yaml) = %+v, want yaml tool arg", parsed)
+	}
+}
+
 func TestToolExamplesEscapeSingleQuotesInJSONLiterals(t *testing.T) {
 	input := map[string]any{
 		"type": "object",
@@ -304,3 +335,45 @@ func TestToolExamplesEscapeSingleQuotesInJSONLiterals(t *testing.T) {
 		t.Fatalf("expected pipe json example to escape single quotes, got %q", examples[2])
 	}
 }
+
+func TestCallToolExamplesPrintsOnlyExampleInvocations(t *testing.T) {
+	oldOut, oldErr := rootStdout, rootStderr
+	defer func() { rootStdout, rootStderr = oldOut, oldErr }()
+	var stdout, stderr bytes.Buffer
+	rootStdout, rootStderr = &stdout, &stderr
+
+	input := map[string]any{
+		"type": "object",
+		"properties": map[string]any{
+			"query": map[string]any{"type": "string"},
+		},
+		"required": []any{"query"},
+	}
+	payload, _ := json.Marshal(map[string]any{"name": "search_repositories", "input_schema": input})
+	client := stubDaemonClient{sendFn: func(req *ipc.Request) (*ipc.Response, error) {
+		if req.Type != "tool_schema" {
+			t.Fatalf("request type = %q, want tool_schema", req.Type)
+		}
+		return &ipc.Response{Content: payload}, nil
+	}}
+
+	want := toolExamples("github", "search_repositories", input)
+	if code := callTool(client, "github", "search-repositories", []string{"--examples"}, "", false); code != ipc.ExitOK {
+		t.Fatalf("callTool(--examples) = %d, want %d (stderr=%q)", code, ipc.ExitOK, stderr.String())
+	}
+	if got := stdout.String(); got != strings.Join(want, "\n")+"\n" {
+		t.Fatalf("examples =\n%s\nwant\n%s", got, strings.Join(want, "\n"))
+	}
+
+	stdout.Reset()
+	if code := callTool(client, "github", "search-repositories", []string{"--examples", "--json"}, "", false); code != ipc.ExitOK {
+		t.Fatalf("callTool(--examples --json) = %d, want %d (stderr=%q)", code, ipc.ExitOK, stderr.String())
+	}
+	var got []string
+	if err := json.Unmarshal(stdout.Bytes(), &got); err != nil {
+		t.Fatalf("json.Unmarshal(stdout) error = %v (stdout=%q)", err, stdout.String())
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("JSON examples = %q, want %q", got, want)
+	}
+}
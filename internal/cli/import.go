@@ -0,0 +1,145 @@
+package cli
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+
+	"github.com/lydakis/mcpx/internal/config"
+	"github.com/lydakis/mcpx/internal/ipc"
+	"github.com/lydakis/mcpx/internal/paths"
+)
+
+type importArgs struct {
+	client    string
+	overwrite bool
+	help      bool
+}
+
+func maybeHandleImportCommand(args []string, cfg *config.Config, stdout, stderr io.Writer) (bool, int) {
+	if len(args) == 0 || args[0] != "import" {
+		return false, 0
+	}
+
+	if cfg != nil {
+		if _, ok := cfg.Servers["import"]; ok {
+			return false, 0
+		}
+	}
+
+	return true, runImportCommand(args[1:], stdout, stderr)
+}
+
+func runImportCommand(args []string, stdout, stderr io.Writer) int {
+	parsed, err := parseImportArgs(args)
+	if err != nil {
+		fmt.Fprintf(stderr, "mcpx: %v\n", err)
+		printImportHelp(stderr)
+		return ipc.ExitUsageErr
+	}
+	if parsed.help {
+		printImportHelp(stdout)
+		return ipc.ExitOK
+	}
+
+	cfgPath := paths.ConfigFile()
+	cfg, err := config.LoadForEditFrom(cfgPath)
+	if err != nil {
+		fmt.Fprintf(stderr, "mcpx: import: loading config: %v\n", err)
+		return ipc.ExitInternal
+	}
+	if cfg.Servers == nil {
+		cfg.Servers = make(map[string]config.ServerConfig)
+	}
+
+	found, sources, err := config.ImportClientServers(cfg, parsed.client, "")
+	if err != nil {
+		if len(found) == 0 {
+			fmt.Fprintf(stderr, "mcpx: import: %v\n", err)
+			return ipc.ExitUsageErr
+		}
+		fmt.Fprintf(stderr, "mcpx: import: warning: %v\n", err)
+	}
+	if len(sources) == 0 {
+		fmt.Fprintf(stderr, "mcpx: import: no %s config found\n", parsed.client)
+		return ipc.ExitUsageErr
+	}
+
+	names := make([]string, 0, len(found))
+	for name := range found {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var imported, skipped []string
+	for _, name := range names {
+		if _, exists := cfg.Servers[name]; exists && !parsed.overwrite {
+			skipped = append(skipped, name)
+			continue
+		}
+		cfg.Servers[name] = found[name]
+		imported = append(imported, name)
+	}
+
+	if len(imported) > 0 {
+		if err := config.ValidateForCurrentEnv(cfg); err != nil {
+			fmt.Fprintf(stderr, "mcpx: import: invalid resulting config: %v\n", err)
+			return ipc.ExitUsageErr
+		}
+		if err := config.SaveTo(cfgPath, cfg); err != nil {
+			fmt.Fprintf(stderr, "mcpx: import: writing config: %v\n", err)
+			return ipc.ExitInternal
+		}
+	}
+
+	for _, name := range imported {
+		fmt.Fprintf(stdout, "Imported server %q\n", name)
+	}
+	for _, name := range skipped {
+		fmt.Fprintf(stdout, "Skipped server %q (already exists; use --overwrite to replace it)\n", name)
+	}
+	fmt.Fprintf(stdout, "Imported %d server(s) from %s into %s\n", len(imported), strings.Join(sources, ", "), cfgPath)
+	return ipc.ExitOK
+}
+
+func parseImportArgs(args []string) (*importArgs, error) {
+	parsed := &importArgs{}
+
+	for _, arg := range args {
+		switch {
+		case arg == "--help" || arg == "-h":
+			parsed.help = true
+		case arg == "--overwrite":
+			parsed.overwrite = true
+		case strings.HasPrefix(arg, "-"):
+			return nil, fmt.Errorf("unknown flag: %s", arg)
+		default:
+			if parsed.client != "" {
+				return nil, fmt.Errorf("unexpected positional argument: %s", arg)
+			}
+			parsed.client = strings.ToLower(strings.TrimSpace(arg))
+		}
+	}
+
+	if parsed.help {
+		return parsed, nil
+	}
+	if parsed.client == "" {
+		return nil, fmt.Errorf("missing client (usage: mcpx import <%s>)", strings.Join(config.ImportClients(), "|"))
+	}
+
+	return parsed, nil
+}
+
+func printImportHelp(out io.Writer) {
+	fmt.Fprintln(out, "Usage:")
+	fmt.Fprintf(out, "  mcpx import <%s> [--overwrite]\n", strings.Join(config.ImportClients(), "|"))
+	fmt.Fprintln(out, "")
+	fmt.Fprintln(out, "Copies the client's MCP servers into mcpx config.toml.")
+	fmt.Fprintln(out, "${VAR} placeholders are kept as written; values are not expanded.")
+	fmt.Fprintln(out, "")
+	fmt.Fprintln(out, "Flags:")
+	fmt.Fprintln(out, "  --overwrite      Replace servers that already exist in mcpx config")
+	fmt.Fprintln(out, "  --help, -h       Show help")
+}
@@ -0,0 +1,97 @@
+package cli
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/lydakis/mcpx/internal/config"
+	"github.com/lydakis/mcpx/internal/ipc"
+)
+
+func TestMaybeHandleImportCommandDefersToServerName(t *testing.T) {
+	cfg := &config.Config{
+		Servers: map[string]config.ServerConfig{
+			"import": {},
+		},
+	}
+
+	handled, code := maybeHandleImportCommand([]string{"import", "cursor"}, cfg, &bytes.Buffer{}, &bytes.Buffer{})
+	if handled {
+		t.Fatal("handled = true, want false")
+	}
+	if code != 0 {
+		t.Fatalf("code = %d, want 0", code)
+	}
+}
+
+func TestRunImportCopiesClientServersAndSkipsExisting(t *testing.T) {
+	tmp := t.TempDir()
+	configHome := filepath.Join(tmp, "xdg-config")
+	configDir := filepath.Join(configHome, "mcpx")
+	cursorPath := filepath.Join(tmp, ".cursor", "mcp.json")
+	if err := os.MkdirAll(filepath.Dir(cursorPath), 0o755); err != nil {
+		t.Fatalf("MkdirAll(cursor): %v", err)
+	}
+	if err := os.WriteFile(cursorPath, []byte(`{"mcpServers":{"github":{"command":"npx","args":["-y","@modelcontextprotocol/server-github"]},"existing":{"command":"other"}}}`), 0o600); err != nil {
+		t.Fatalf("WriteFile(cursor): %v", err)
+	}
+	if err := os.MkdirAll(configDir, 0o755); err != nil {
+		t.Fatalf("MkdirAll(configDir): %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(configDir, "config.toml"), []byte(`fallback_sources = ["`+cursorPath+`"]
+
+[servers.existing]
+command = "mine"
+`), 0o600); err != nil {
+		t.Fatalf("WriteFile(config): %v", err)
+	}
+
+	t.Setenv("XDG_CONFIG_HOME", configHome)
+	t.Setenv("HOME", tmp)
+
+	oldOut := rootStdout
+	oldErr := rootStderr
+	defer func() {
+		rootStdout = oldOut
+		rootStderr = oldErr
+	}()
+	var out bytes.Buffer
+	var errOut bytes.Buffer
+	rootStdout = &out
+	rootStderr = &errOut
+
+	code := Run([]string{"import", "cursor"})
+	if code != ipc.ExitOK {
+		t.Fatalf("Run([import cursor]) = %d, want %d (stderr=%q)", code, ipc.ExitOK, errOut.String())
+	}
+	if !strings.Contains(out.String(), `Imported server "github"`) || !strings.Contains(out.String(), `Skipped server "existing"`) {
+		t.Fatalf("stdout = %q, want imported and skipped names", out.String())
+	}
+
+	edited, err := config.LoadForEditFrom(filepath.Join(configDir, "config.toml"))
+	if err != nil {
+		t.Fatalf("LoadForEditFrom(saved config) error = %v", err)
+	}
+	if edited.Servers["github"].Command != "npx" {
+		t.Fatalf("github command = %q, want %q", edited.Servers["github"].Command, "npx")
+	}
+	if edited.Servers["existing"].Command != "mine" {
+		t.Fatalf("existing command = %q, want unchanged", edited.Servers["existing"].Command)
+	}
+
+	out.Reset()
+	code = Run([]string{"import", "cursor", "--overwrite"})
+	if code != ipc.ExitOK {
+		t.Fatalf("Run([import cursor --overwrite]) = %d, want %d (stderr=%q)", code, ipc.ExitOK, errOut.String())
+	}
+	edited, err = config.LoadForEditFrom(filepath.Join(configDir, "config.toml"))
+	if err != nil {
+		t.Fatalf("LoadForEditFrom(saved config) error = %v", err)
+	}
+	if edited.Servers["existing"].Command != "other" {
+		t.Fatalf("existing command = %q, want overwritten", edited.Servers["existing"].Command)
+	}
+}
@@ -0,0 +1,107 @@
+package cli
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// readPrevResult reads the previous call's output from stdin for
+// --input-from-prev and returns the object to use as arguments. Plain JSON
+// objects, which is what mcpx prints for structured results, are used as is.
+// An MCP CallToolResult envelope ({"content": [...], "structuredContent":
+// ...}) is unwrapped to its structured content, or to its single text block
+// parsed as JSON. A --soft-fail error payload stops the chain with the
+// previous call's error.
+func readPrevResult(stdin io.Reader) (map[string]any, error) {
+	data, err := io.ReadAll(stdin)
+	if err != nil {
+		return nil, fmt.Errorf("reading stdin: %w", err)
+	}
+	trimmed := strings.TrimSpace(string(data))
+	if trimmed == "" {
+		return nil, fmt.Errorf("--input-from-prev requires the previous call's JSON output on stdin")
+	}
+
+	var decoded any
+	if err := json.Unmarshal([]byte(trimmed), &decoded); err != nil {
+		return nil, fmt.Errorf("--input-from-prev: stdin is not JSON: %w", err)
+	}
+	obj, ok := decoded.(map[string]any)
+	if !ok {
+		return nil, fmt.Errorf("--input-from-prev: previous result must be a JSON object")
+	}
+
+	if msg, ok := softFailMessage(obj); ok {
+		return nil, fmt.Errorf("--input-from-prev: previous call failed: %s", msg)
+	}
+	if !isCallToolResultEnvelope(obj) {
+		return obj, nil
+	}
+	if isErr, _ := obj["isError"].(bool); isErr {
+		return nil, fmt.Errorf("--input-from-prev: previous call returned a tool error")
+	}
+	if structured, ok := obj["structuredContent"].(map[string]any); ok {
+		return structured, nil
+	}
+
+	blocks, _ := obj["content"].([]any)
+	if len(blocks) != 1 {
+		return nil, fmt.Errorf("--input-from-prev: previous result has %d content blocks and no structured content; expected one JSON text block", len(blocks))
+	}
+	block, _ := blocks[0].(map[string]any)
+	text, _ := block["text"].(string)
+	if block["type"] != "text" {
+		return nil, fmt.Errorf("--input-from-prev: previous result's content block is %v, not JSON text", block["type"])
+	}
+	payload, err := parseJSONObject(strings.TrimSpace(text))
+	if err != nil {
+		return nil, fmt.Errorf("--input-from-prev: previous result's text is not a JSON object")
+	}
+	return payload, nil
+}
+
+// isCallToolResultEnvelope reports whether obj has the MCP CallToolResult
+// shape: a content array of typed blocks and no keys outside the result.
+func isCallToolResultEnvelope(obj map[string]any) bool {
+	blocks, ok := obj["content"].([]any)
+	if !ok {
+		return false
+	}
+	for key := range obj {
+		switch key {
+		case "content", "structuredContent", "isError", "_meta":
+		default:
+			return false
+		}
+	}
+	for _, raw := range blocks {
+		block, ok := raw.(map[string]any)
+		if !ok {
+			return false
+		}
+		if _, ok := block["type"].(string); !ok {
+			return false
+		}
+	}
+	return true
+}
+
+// softFailMessage recognizes the {"error": {"message", "exit_code"}} object
+// --soft-fail prints in place of a failed call.
+func softFailMessage(obj map[string]any) (string, bool) {
+	if len(obj) != 1 {
+		return "", false
+	}
+	errObj, ok := obj["error"].(map[string]any)
+	if !ok {
+		return "", false
+	}
+	msg, hasMsg := errObj["message"].(string)
+	_, hasCode := errObj["exit_code"].(float64)
+	if !hasMsg || !hasCode {
+		return "", false
+	}
+	return msg, true
+}
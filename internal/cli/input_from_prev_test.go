@@ -0,0 +1,82 @@
+package cli
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestParseToolCallArgsInputFromPrevUnwrapsEnvelope(t *testing.T) {
+	tests := []struct {
+		name  string
+		stdin string
+		want  map[string]any
+	}{
+		{
+			name:  "raw JSON object",
+			stdin: `{"title":"from prev","number":42}`,
+			want:  map[string]any{"title": "from prev", "number": float64(42), "team": "ENG"},
+		},
+		{
+			name:  "structured content envelope",
+			stdin: `{"content":[{"type":"text","text":"ignored"}],"structuredContent":{"title":"structured"}}`,
+			want:  map[string]any{"title": "structured", "team": "ENG"},
+		},
+		{
+			name:  "single JSON text block envelope",
+			stdin: `{"content":[{"type":"text","text":"{\"title\":\"from text\"}"}],"isError":false}`,
+			want:  map[string]any{"title": "from text", "team": "ENG"},
+		},
+		{
+			name:  "flags win over previous result",
+			stdin: `{"title":"from prev","team":"OPS"}`,
+			want:  map[string]any{"title": "from prev", "team": "ENG"},
+		},
+		{
+			name:  "object with a content key that is not an envelope",
+			stdin: `{"content":"body","title":"note"}`,
+			want:  map[string]any{"content": "body", "title": "note", "team": "ENG"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			parsed, err := parseToolCallArgs([]string{"--input-from-prev", "--team=ENG"}, strings.NewReader(tt.stdin), false, outputModeText)
+			if err != nil {
+				t.Fatalf("parseToolCallArgs() error = %v", err)
+			}
+			if !reflect.DeepEqual(parsed.toolArgs, tt.want) {
+				t.Fatalf("toolArgs = %#v, want %#v", parsed.toolArgs, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseToolCallArgsInputFromPrevRejectsUnusableInput(t *testing.T) {
+	tests := []struct {
+		name    string
+		args    []string
+		stdin   string
+		wantErr string
+	}{
+		{"array", nil, `[1,2]`, "must be a JSON object"},
+		{"not JSON", nil, `plain text`, "stdin is not JSON"},
+		{"tool error", nil, `{"content":[{"type":"text","text":"boom"}],"isError":true}`, "previous call returned a tool error"},
+		{"soft fail", nil, `{"error":{"message":"rate limited","exit_code":1}}`, "previous call failed: rate limited"},
+		{"many blocks", nil, `{"content":[{"type":"text","text":"a"},{"type":"text","text":"b"}]}`, "has 2 content blocks"},
+		{"text not JSON", nil, `{"content":[{"type":"text","text":"hello"}]}`, "text is not a JSON object"},
+		{"with stdin merge", []string{"--args-stdin-merge"}, `{}`, "cannot be combined with --args-stdin-merge"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			args := append([]string{"--input-from-prev"}, tt.args...)
+			_, err := parseToolCallArgs(args, strings.NewReader(tt.stdin), false, outputModeText)
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Fatalf("parseToolCallArgs() error = %v, want %q", err, tt.wantErr)
+			}
+		})
+	}
+
+	if _, err := parseToolCallArgs([]string{"--input-from-prev"}, strings.NewReader(`{}`), true, outputModeText); err == nil || !strings.Contains(err.Error(), "requires the previous call's JSON output") {
+		t.Fatalf("parseToolCallArgs(tty) error = %v, want stdin required", err)
+	}
+}
@@ -0,0 +1,82 @@
+package cli
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/lydakis/mcpx/internal/ipc"
+)
+
+func parseJSONLField(raw string) ([]any, error) {
+	raw = strings.TrimSpace(raw)
+	if raw == "" {
+		return nil, fmt.Errorf("missing value for --jsonl-field")
+	}
+	path, err := parseResultPath(raw)
+	if err != nil {
+		return nil, fmt.Errorf("invalid --jsonl-field %q: %w", raw, err)
+	}
+	return path, nil
+}
+
+// jsonlItems returns the elements of the array at path in a JSON result.
+// An empty path ("$") streams a top-level array.
+func jsonlItems(content []byte, raw string, path []any) ([]any, error) {
+	dec := json.NewDecoder(bytes.NewReader(content))
+	dec.UseNumber()
+	var doc any
+	if err := dec.Decode(&doc); err != nil {
+		return nil, fmt.Errorf("--jsonl-field requires a JSON result: %w", err)
+	}
+	if dec.More() {
+		return nil, fmt.Errorf("--jsonl-field requires a single JSON document")
+	}
+
+	value, ok := lookupResultPath(doc, path)
+	if !ok {
+		return nil, fmt.Errorf("--jsonl-field %q: path not found in result", raw)
+	}
+	items, ok := value.([]any)
+	if !ok {
+		return nil, fmt.Errorf("--jsonl-field %q: value is not an array", raw)
+	}
+	return items, nil
+}
+
+// writeJSONL writes each item as one compact JSON line.
+func writeJSONL(w io.Writer, items []any) error {
+	var buf bytes.Buffer
+	enc := json.NewEncoder(&buf)
+	enc.SetEscapeHTML(false)
+	for _, item := range items {
+		if err := enc.Encode(item); err != nil {
+			return fmt.Errorf("encoding --jsonl-field item: %w", err)
+		}
+	}
+	_, err := w.Write(buf.Bytes())
+	return err
+}
+
+func writeJSONLFieldResponse(resp *ipc.Response, parsed *toolCallArgs) int {
+	items, err := jsonlItems(resp.Content, parsed.jsonlField, parsed.jsonlPath)
+	if resp.ContentType != "" && resp.ContentType != "application/json" {
+		err = fmt.Errorf("--jsonl-field requires a JSON result, got %s (%s)", resp.ContentType, resp.Encoding)
+	}
+	if err != nil {
+		if !parsed.quiet {
+			fmt.Fprintf(rootStderr, "mcpx: %v\n", err)
+		}
+		return ipc.ExitUsageErr
+	}
+	if !parsed.quiet && resp.Stderr != "" {
+		fmt.Fprintln(rootStderr, resp.Stderr)
+	}
+	if err := writeJSONL(rootStdout, items); err != nil {
+		fmt.Fprintf(rootStderr, "mcpx: %v\n", err)
+		return ipc.ExitInternal
+	}
+	return ipc.ExitOK
+}
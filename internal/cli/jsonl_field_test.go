@@ -0,0 +1,82 @@
+package cli
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/lydakis/mcpx/internal/ipc"
+)
+
+func TestCallToolJSONLFieldStreamsNestedArray(t *testing.T) {
+	oldOut, oldErr := rootStdout, rootStderr
+	defer func() { rootStdout, rootStderr = oldOut, oldErr }()
+	var stdout, stderr bytes.Buffer
+	rootStdout, rootStderr = &stdout, &stderr
+	stubConfirmTerminal(t, false, "")
+
+	client := stubDaemonClient{sendFn: func(req *ipc.Request) (*ipc.Response, error) {
+		return &ipc.Response{Content: []byte(`{
+  "result": {"total": 2, "items": [{"id": 1, "name": "a<b"}, {"id": 2.50, "tags": []}]}
+}` + "\n")}, nil
+	}}
+
+	if code := callTool(client, "svc", "search", []string{"--jsonl-field", "result.items"}, "", false); code != ipc.ExitOK {
+		t.Fatalf("callTool() = %d, want %d (stderr=%q)", code, ipc.ExitOK, stderr.String())
+	}
+	want := `{"id":1,"name":"a<b"}` + "\n" + `{"id":2.50,"tags":[]}` + "\n"
+	if stdout.String() != want {
+		t.Fatalf("stdout = %q, want %q", stdout.String(), want)
+	}
+}
+
+func TestCallToolJSONLFieldRejectsNonArray(t *testing.T) {
+	oldOut, oldErr := rootStdout, rootStderr
+	defer func() { rootStdout, rootStderr = oldOut, oldErr }()
+	var stdout, stderr bytes.Buffer
+	rootStdout, rootStderr = &stdout, &stderr
+	stubConfirmTerminal(t, false, "")
+
+	client := stubDaemonClient{sendFn: func(req *ipc.Request) (*ipc.Response, error) {
+		return &ipc.Response{Content: []byte(`{"result":{"total":2}}`)}, nil
+	}}
+
+	tests := map[string]string{
+		"$.result.total": "value is not an array",
+		"result.items":   "path not found in result",
+	}
+	for path, wantErr := range tests {
+		stdout.Reset()
+		stderr.Reset()
+		if code := callTool(client, "svc", "search", []string{"--jsonl-field=" + path}, "", false); code != ipc.ExitUsageErr {
+			t.Fatalf("callTool(%s) = %d, want %d", path, code, ipc.ExitUsageErr)
+		}
+		if !strings.Contains(stderr.String(), wantErr) {
+			t.Fatalf("stderr = %q, want %q", stderr.String(), wantErr)
+		}
+		if stdout.Len() != 0 {
+			t.Fatalf("stdout = %q, want empty", stdout.String())
+		}
+	}
+}
+
+func TestJSONLItemsStreamsTopLevelArray(t *testing.T) {
+	path, err := parseJSONLField("$")
+	if err != nil {
+		t.Fatalf("parseJSONLField($) error = %v", err)
+	}
+	items, err := jsonlItems([]byte(`[1,"two",null]`), "$", path)
+	if err != nil || len(items) != 3 {
+		t.Fatalf("jsonlItems() = %#v, %v; want three items", items, err)
+	}
+
+	for _, args := range [][]string{
+		{"--jsonl-field"},
+		{"--jsonl-field", "items", "--flatten"},
+		{"--jsonl-field", "items", "--output-raw-bytes"},
+	} {
+		if _, err := parseToolCallArgs(args, bytes.NewBuffer(nil), true, outputModeText); err == nil {
+			t.Fatalf("parseToolCallArgs(%v) error = nil, want non-nil", args)
+		}
+	}
+}
@@ -0,0 +1,104 @@
+package cli
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/lydakis/mcpx/internal/ipc"
+)
+
+// defaultExitRules maps common status values, compared case-insensitively,
+// to exit codes. --map-exit-rule entries win over them.
+var defaultExitRules = map[string]int{
+	"ok":        0,
+	"success":   0,
+	"succeeded": 0,
+	"passed":    0,
+	"true":      0,
+	"error":     1,
+	"failed":    1,
+	"failure":   1,
+	"false":     1,
+}
+
+// exitMapping is a parsed --map-exit field with its --map-exit-rule
+// overrides, keyed by lower-cased value.
+type exitMapping struct {
+	field string
+	path  []any
+	rules map[string]int
+}
+
+func (m *exitMapping) setField(raw string) error {
+	raw = strings.TrimSpace(raw)
+	if raw == "" {
+		return fmt.Errorf("missing value for --map-exit")
+	}
+	path, err := parseResultPath(raw)
+	if err != nil {
+		return fmt.Errorf("invalid --map-exit %q: %w", raw, err)
+	}
+	m.field, m.path = raw, path
+	return nil
+}
+
+// addRule parses a --map-exit-rule <value>=<code> entry.
+func (m *exitMapping) addRule(raw string) error {
+	value, rawCode, ok := strings.Cut(raw, "=")
+	value = strings.TrimSpace(value)
+	code, err := strconv.Atoi(strings.TrimSpace(rawCode))
+	if !ok || value == "" || err != nil || code < 0 || code > 255 {
+		return fmt.Errorf("invalid --map-exit-rule %q: expected <value>=<code> with code 0-255", raw)
+	}
+	if m.rules == nil {
+		m.rules = make(map[string]int)
+	}
+	m.rules[strings.ToLower(value)] = code
+	return nil
+}
+
+// exitCode reads the mapped field from a JSON tool result and returns its
+// exit code. Strings are matched as is; other values by their JSON text
+// (true, 3).
+func (m *exitMapping) exitCode(content []byte) (int, error) {
+	var doc any
+	if err := json.Unmarshal(content, &doc); err != nil {
+		return 0, fmt.Errorf("--map-exit requires a JSON result")
+	}
+	found, ok := lookupResultPath(doc, m.path)
+	if !ok {
+		return 0, fmt.Errorf("--map-exit: result has no field %s", m.field)
+	}
+
+	value, isString := found.(string)
+	if !isString {
+		encoded, err := json.Marshal(found)
+		if err != nil {
+			return 0, fmt.Errorf("--map-exit: %v", err)
+		}
+		value = string(encoded)
+	}
+	key := strings.ToLower(strings.TrimSpace(value))
+	if code, ok := m.rules[key]; ok {
+		return code, nil
+	}
+	if code, ok := defaultExitRules[key]; ok {
+		return code, nil
+	}
+	return 0, fmt.Errorf("--map-exit: %s is %q, which has no exit code; add --map-exit-rule %s=<code>", m.field, value, value)
+}
+
+// mappedCallExit returns the exit code --map-exit picks for a successful
+// call, or ExitToolErr when the field is missing or its value is unmapped.
+func mappedCallExit(resp *ipc.Response, parsed *toolCallArgs) int {
+	code, err := parsed.mapExit.exitCode(resp.Content)
+	if err != nil {
+		if !parsed.quiet {
+			fmt.Fprintf(rootStderr, "mcpx: %v\n", err)
+		}
+		return ipc.ExitToolErr
+	}
+	return code
+}
@@ -0,0 +1,93 @@
+package cli
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/lydakis/mcpx/internal/ipc"
+)
+
+func TestExitMappingUsesDefaultTableAndCustomRules(t *testing.T) {
+	parsed, err := parseToolCallArgs([]string{"--map-exit", "$.job.status", "--map-exit-rule=Pending=3", "--id=7"}, bytes.NewBuffer(nil), true, outputModeText)
+	if err != nil {
+		t.Fatalf("parseToolCallArgs() error = %v", err)
+	}
+	if _, ok := parsed.toolArgs["map-exit"]; ok {
+		t.Fatal("--map-exit leaked into tool args")
+	}
+
+	tests := []struct {
+		content string
+		want    int
+		wantErr string
+	}{
+		{content: `{"job":{"status":"OK"}}`, want: 0},
+		{content: `{"job":{"status":"success"}}`, want: 0},
+		{content: `{"job":{"status":"failed"}}`, want: 1},
+		{content: `{"job":{"status":"pending"}}`, want: 3},
+		{content: `{"job":{"status":true}}`, want: 0},
+		{content: `{"job":{"status":"queued"}}`, wantErr: "--map-exit-rule queued=<code>"},
+		{content: `{"job":{}}`, wantErr: "no field $.job.status"},
+		{content: `not json`, wantErr: "requires a JSON result"},
+	}
+	for _, tt := range tests {
+		got, err := parsed.mapExit.exitCode([]byte(tt.content))
+		if tt.wantErr != "" {
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Fatalf("exitCode(%s) error = %v, want %q", tt.content, err, tt.wantErr)
+			}
+			continue
+		}
+		if err != nil || got != tt.want {
+			t.Fatalf("exitCode(%s) = %d, %v; want %d", tt.content, got, err, tt.want)
+		}
+	}
+}
+
+func TestParseToolCallArgsRejectsInvalidMapExitFlags(t *testing.T) {
+	for _, args := range [][]string{
+		{"--map-exit-rule=done=0"},
+		{"--map-exit=status", "--map-exit-rule=done"},
+		{"--map-exit=status", "--map-exit-rule=done=256"},
+		{"--map-exit="},
+		{"--map-exit=status", "--output-raw-bytes"},
+	} {
+		if _, err := parseToolCallArgs(args, bytes.NewBuffer(nil), true, outputModeText); err == nil {
+			t.Fatalf("parseToolCallArgs(%v) error = nil, want non-nil", args)
+		}
+	}
+}
+
+func TestCallToolMapExitSetsExitCodeAfterWritingOutput(t *testing.T) {
+	oldOut, oldErr := rootStdout, rootStderr
+	defer func() { rootStdout, rootStderr = oldOut, oldErr }()
+	var stdout, stderr bytes.Buffer
+	rootStdout, rootStderr = &stdout, &stderr
+
+	content := []byte(`{"status":"failed","reason":"lint"}` + "\n")
+	client := stubDaemonClient{
+		sendFn: func(*ipc.Request) (*ipc.Response, error) {
+			return &ipc.Response{ExitCode: ipc.ExitOK, Content: content}, nil
+		},
+	}
+
+	if code := callTool(client, "ci", "run", []string{"--map-exit", "status"}, "", false); code != ipc.ExitToolErr {
+		t.Fatalf("callTool(status=failed) = %d, want %d", code, ipc.ExitToolErr)
+	}
+	if stdout.String() != string(content) {
+		t.Fatalf("stdout = %q, want tool output %q", stdout.String(), content)
+	}
+
+	stdout.Reset()
+	if code := callTool(client, "ci", "run", []string{"--map-exit", "status", "--map-exit-rule", "failed=4"}, "", false); code != 4 {
+		t.Fatalf("callTool(rule failed=4) = %d, want 4", code)
+	}
+
+	if code := callTool(client, "ci", "run", []string{"--map-exit", "reason"}, "", false); code != ipc.ExitToolErr {
+		t.Fatalf("callTool(unmapped reason) = %d, want %d", code, ipc.ExitToolErr)
+	}
+	if !strings.Contains(stderr.String(), `reason is "lint"`) {
+		t.Fatalf("stderr = %q, want unmapped value message", stderr.String())
+	}
+}
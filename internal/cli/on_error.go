@@ -0,0 +1,120 @@
+package cli
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"strconv"
+	"strings"
+
+	"github.com/lydakis/mcpx/internal/ipc"
+)
+
+// parseOnErrorCommand splits an --on-error value into argv with shell-style
+// quoting (see splitCommandWords). The hook is executed directly, never
+// through a shell, so metacharacters such as | and $ are passed literally.
+func parseOnErrorCommand(raw string) ([]string, error) {
+	argv, err := splitCommandWords(raw)
+	if err != nil {
+		return nil, fmt.Errorf("invalid --on-error command: %w", err)
+	}
+	if len(argv) == 0 {
+		return nil, fmt.Errorf("--on-error requires a command")
+	}
+	return argv, nil
+}
+
+// splitCommandWords splits raw on unquoted whitespace the way a POSIX shell
+// splits words: single quotes keep everything literally, double quotes keep
+// whitespace and allow \", \\, \$ and \` escapes, and a backslash outside
+// quotes escapes the next character. No expansion happens.
+func splitCommandWords(raw string) ([]string, error) {
+	var words []string
+	var word strings.Builder
+	inWord := false
+	for i := 0; i < len(raw); i++ {
+		c := raw[i]
+		switch {
+		case c == ' ' || c == '\t' || c == '\n':
+			if inWord {
+				words = append(words, word.String())
+				word.Reset()
+				inWord = false
+			}
+		case c == '\'':
+			end := strings.IndexByte(raw[i+1:], '\'')
+			if end < 0 {
+				return nil, fmt.Errorf("unterminated single quote")
+			}
+			word.WriteString(raw[i+1 : i+1+end])
+			i += end + 1
+			inWord = true
+		case c == '"':
+			i++
+			for ; i < len(raw) && raw[i] != '"'; i++ {
+				if raw[i] == '\\' && i+1 < len(raw) && strings.IndexByte("\"\\$`", raw[i+1]) >= 0 {
+					i++
+				}
+				word.WriteByte(raw[i])
+			}
+			if i >= len(raw) {
+				return nil, fmt.Errorf("unterminated double quote")
+			}
+			inWord = true
+		case c == '\\':
+			if i+1 < len(raw) {
+				i++
+				word.WriteByte(raw[i])
+			}
+			inWord = true
+		default:
+			word.WriteByte(c)
+			inWord = true
+		}
+	}
+	if inWord {
+		words = append(words, word.String())
+	}
+	return words, nil
+}
+
+// callFailureMessage returns the text handed to an --on-error hook for a
+// failed call response.
+func callFailureMessage(resp *ipc.Response) string {
+	if resp == nil {
+		return ""
+	}
+	parts := make([]string, 0, 2)
+	if msg := strings.TrimSpace(resp.Stderr); msg != "" {
+		parts = append(parts, msg)
+	}
+	if msg := strings.TrimSpace(string(resp.Content)); msg != "" {
+		parts = append(parts, msg)
+	}
+	return strings.Join(parts, "\n")
+}
+
+// runOnErrorHook runs the --on-error command after a failed call. The error
+// text is written to the hook's stdin and exported as MCPX_ERROR alongside
+// MCPX_SERVER, MCPX_TOOL, and MCPX_EXIT_CODE. Hook output goes to stderr so
+// stdout stays reserved for tool results; hook failures never change the
+// call's exit code.
+func runOnErrorHook(argv []string, server, tool string, exitCode int, message string, quiet bool) {
+	if len(argv) == 0 {
+		return
+	}
+
+	cmd := exec.Command(argv[0], argv[1:]...)
+	cmd.Env = append(os.Environ(),
+		"MCPX_SERVER="+server,
+		"MCPX_TOOL="+tool,
+		"MCPX_EXIT_CODE="+strconv.Itoa(exitCode),
+		"MCPX_ERROR="+message,
+	)
+	cmd.Stdin = strings.NewReader(message)
+	cmd.Stdout = rootStderr
+	cmd.Stderr = rootStderr
+	if err := cmd.Run(); err != nil && !quiet {
+		fmt.Fprintf(rootStderr, "mcpx: --on-error hook failed: %v\n", err)
+	}
+}
@@ -0,0 +1,135 @@
+package cli
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/lydakis/mcpx/internal/ipc"
+)
+
+func writeOnErrorHookScript(t *testing.T) (script, marker string) {
+	t.Helper()
+
+	dir := t.TempDir()
+	marker = filepath.Join(dir, "hook.out")
+	script = filepath.Join(dir, "hook.sh")
+	body := "#!/bin/sh\n{ printf '%s|%s|%s|' \"$MCPX_SERVER\" \"$MCPX_TOOL\" \"$MCPX_EXIT_CODE\"; cat; } > \"$1\"\n"
+	if err := os.WriteFile(script, []byte(body), 0o755); err != nil {
+		t.Fatalf("WriteFile(hook): %v", err)
+	}
+	return script, marker
+}
+
+func TestCallToolRunsOnErrorHookOnFailure(t *testing.T) {
+	script, marker := writeOnErrorHookScript(t)
+
+	oldOut, oldErr := rootStdout, rootStderr
+	defer func() { rootStdout, rootStderr = oldOut, oldErr }()
+	var stdout, stderr bytes.Buffer
+	rootStdout, rootStderr = &stdout, &stderr
+
+	client := stubDaemonClient{
+		sendFn: func(*ipc.Request) (*ipc.Response, error) {
+			return &ipc.Response{ExitCode: ipc.ExitToolErr, Content: []byte("rate limited")}, nil
+		},
+	}
+
+	code := callTool(client, "github", "search", []string{"--on-error", script + " " + marker, "--query=mcp"}, "", false)
+	if code != ipc.ExitToolErr {
+		t.Fatalf("callTool() = %d, want %d", code, ipc.ExitToolErr)
+	}
+
+	got, err := os.ReadFile(marker)
+	if err != nil {
+		t.Fatalf("hook did not run: %v", err)
+	}
+	if want := "github|search|1|rate limited"; string(got) != want {
+		t.Fatalf("hook saw %q, want %q", got, want)
+	}
+}
+
+func TestCallToolSkipsOnErrorHookOnSuccess(t *testing.T) {
+	script, marker := writeOnErrorHookScript(t)
+
+	oldOut, oldErr := rootStdout, rootStderr
+	defer func() { rootStdout, rootStderr = oldOut, oldErr }()
+	var stdout, stderr bytes.Buffer
+	rootStdout, rootStderr = &stdout, &stderr
+
+	client := stubDaemonClient{
+		sendFn: func(*ipc.Request) (*ipc.Response, error) {
+			return &ipc.Response{ExitCode: ipc.ExitOK, Content: []byte("ok\n")}, nil
+		},
+	}
+
+	code := callTool(client, "github", "search", []string{"--on-error=" + script + " " + marker}, "", false)
+	if code != ipc.ExitOK {
+		t.Fatalf("callTool() = %d, want %d", code, ipc.ExitOK)
+	}
+	if _, err := os.Stat(marker); !os.IsNotExist(err) {
+		t.Fatalf("hook ran on success (stat err = %v)", err)
+	}
+	if stdout.String() != "ok\n" {
+		t.Fatalf("stdout = %q, want %q", stdout.String(), "ok\n")
+	}
+}
+
+func TestCallToolOnErrorHookFailureKeepsOriginalExitCode(t *testing.T) {
+	oldOut, oldErr := rootStdout, rootStderr
+	defer func() { rootStdout, rootStderr = oldOut, oldErr }()
+	var stdout, stderr bytes.Buffer
+	rootStdout, rootStderr = &stdout, &stderr
+
+	client := stubDaemonClient{
+		sendFn: func(*ipc.Request) (*ipc.Response, error) {
+			return &ipc.Response{ExitCode: ipc.ExitInternal, Content: []byte("boom")}, nil
+		},
+	}
+
+	missing := filepath.Join(t.TempDir(), "missing-hook")
+	code := callTool(client, "github", "search", []string{"--on-error", missing}, "", false)
+	if code != ipc.ExitInternal {
+		t.Fatalf("callTool() = %d, want %d", code, ipc.ExitInternal)
+	}
+	if !strings.Contains(stderr.String(), "--on-error hook failed") {
+		t.Fatalf("stderr = %q, want hook failure note", stderr.String())
+	}
+}
+
+func TestParseToolCallArgsRejectsEmptyOnErrorCommand(t *testing.T) {
+	if _, err := parseToolCallArgs([]string{"--on-error="}, bytes.NewBuffer(nil), true, outputModeText); err == nil {
+		t.Fatal("parseToolCallArgs(--on-error=) error = nil, want non-nil")
+	}
+	if _, err := parseToolCallArgs([]string{"--on-error"}, bytes.NewBuffer(nil), true, outputModeText); err == nil {
+		t.Fatal("parseToolCallArgs(--on-error) error = nil, want non-nil")
+	}
+}
+
+func TestParseOnErrorCommandHonorsShellQuoting(t *testing.T) {
+	tests := map[string][]string{
+		`notify "build failed"`:        {"notify", "build failed"},
+		`notify 'it''s done' plain`:    {"notify", "its done", "plain"},
+		`say "a \"quoted\" word" x\ y`: {"say", `a "quoted" word`, "x y"},
+		`  echo   $HOME|cat  `:         {"echo", "$HOME|cat"},
+		`printf "" end`:                {"printf", "", "end"},
+	}
+	for raw, want := range tests {
+		got, err := parseOnErrorCommand(raw)
+		if err != nil {
+			t.Fatalf("parseOnErrorCommand(%q) error = %v", raw, err)
+		}
+		if !reflect.DeepEqual(got, want) {
+			t.Fatalf("parseOnErrorCommand(%q) = %q, want %q", raw, got, want)
+		}
+	}
+
+	for _, raw := range []string{`notify "unterminated`, `notify 'unterminated`} {
+		if _, err := parseOnErrorCommand(raw); err == nil {
+			t.Fatalf("parseOnErrorCommand(%q) error = nil, want unterminated quote error", raw)
+		}
+	}
+}
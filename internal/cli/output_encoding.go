@@ -0,0 +1,41 @@
+package cli
+
+import (
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"strings"
+)
+
+const (
+	outputEncodingUTF8   = "utf8"
+	outputEncodingBase64 = "base64"
+	outputEncodingHex    = "hex"
+)
+
+func parseOutputEncoding(raw string) (string, error) {
+	switch strings.ToLower(strings.TrimSpace(raw)) {
+	case "utf8", "utf-8":
+		return outputEncodingUTF8, nil
+	case outputEncodingBase64:
+		return outputEncodingBase64, nil
+	case outputEncodingHex:
+		return outputEncodingHex, nil
+	default:
+		return "", fmt.Errorf("invalid --output-encoding %q: expected utf8, base64, or hex", raw)
+	}
+}
+
+// encodeOutput applies --output-encoding to successful tool output. base64
+// and hex encode the bytes exactly as received and end with a newline;
+// utf8 (or no encoding) passes content through.
+func encodeOutput(content []byte, encoding string) []byte {
+	switch encoding {
+	case outputEncodingBase64:
+		return []byte(base64.StdEncoding.EncodeToString(content) + "\n")
+	case outputEncodingHex:
+		return []byte(hex.EncodeToString(content) + "\n")
+	default:
+		return content
+	}
+}
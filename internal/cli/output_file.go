@@ -0,0 +1,26 @@
+package cli
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/lydakis/mcpx/internal/ipc"
+)
+
+// writeOutputFileResponse writes a successful result to parsed.outputFile,
+// truncating it, in place of stdout. Content is written exactly as it would
+// be printed, so --json and --output-raw-bytes results land unchanged.
+func writeOutputFileResponse(resp *ipc.Response, parsed *toolCallArgs) int {
+	if !parsed.quiet && resp.Stderr != "" {
+		fmt.Fprintln(rootStderr, resp.Stderr)
+	}
+	data := encodeOutput(resp.Content, parsed.outputEncoding)
+	if err := os.WriteFile(parsed.outputFile, data, 0o644); err != nil {
+		fmt.Fprintf(rootStderr, "mcpx: writing --output-file: %v\n", err)
+		return ipc.ExitInternal
+	}
+	if !parsed.quiet {
+		fmt.Fprintf(rootStderr, "Wrote %d bytes to %s\n", len(data), parsed.outputFile)
+	}
+	return ipc.ExitOK
+}
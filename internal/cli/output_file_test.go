@@ -0,0 +1,139 @@
+package cli
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/lydakis/mcpx/internal/ipc"
+)
+
+func TestCallToolOutputFileWritesResultInsteadOfStdout(t *testing.T) {
+	oldOut, oldErr := rootStdout, rootStderr
+	defer func() { rootStdout, rootStderr = oldOut, oldErr }()
+	var stdout, stderr bytes.Buffer
+	rootStdout, rootStderr = &stdout, &stderr
+	stubConfirmTerminal(t, false, "")
+
+	client := stubDaemonClient{sendFn: func(req *ipc.Request) (*ipc.Response, error) {
+		return &ipc.Response{Content: []byte(`{"ok":true}`)}, nil
+	}}
+
+	path := filepath.Join(t.TempDir(), "result.json")
+	if err := os.WriteFile(path, []byte("previous contents that are longer"), 0o600); err != nil {
+		t.Fatalf("WriteFile() error = %v", err)
+	}
+	if code := callTool(client, "svc", "get", []string{"--json", "--output-file", path}, "", false); code != ipc.ExitOK {
+		t.Fatalf("callTool() = %d, want %d (stderr=%q)", code, ipc.ExitOK, stderr.String())
+	}
+	if stdout.Len() != 0 {
+		t.Fatalf("stdout = %q, want empty", stdout.String())
+	}
+	if want := "Wrote 11 bytes to " + path + "\n"; stderr.String() != want {
+		t.Fatalf("stderr = %q, want %q", stderr.String(), want)
+	}
+	got, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("ReadFile() error = %v", err)
+	}
+	if string(got) != `{"ok":true}` {
+		t.Fatalf("file = %q, want raw result bytes", got)
+	}
+
+	stderr.Reset()
+	if code := callTool(client, "svc", "get", []string{"--output-file=" + path, "--quiet"}, "", false); code != ipc.ExitOK {
+		t.Fatalf("callTool(--quiet) = %d, want %d", code, ipc.ExitOK)
+	}
+	if stderr.Len() != 0 {
+		t.Fatalf("stderr = %q, want empty with --quiet", stderr.String())
+	}
+}
+
+func TestCallToolOutputFileReportsWriteErrorsAsInternal(t *testing.T) {
+	oldOut, oldErr := rootStdout, rootStderr
+	defer func() { rootStdout, rootStderr = oldOut, oldErr }()
+	var stdout, stderr bytes.Buffer
+	rootStdout, rootStderr = &stdout, &stderr
+	stubConfirmTerminal(t, false, "")
+
+	client := stubDaemonClient{sendFn: func(req *ipc.Request) (*ipc.Response, error) {
+		return &ipc.Response{Content: []byte("data")}, nil
+	}}
+
+	path := filepath.Join(t.TempDir(), "missing", "result.txt")
+	if code := callTool(client, "svc", "get", []string{"--output-file", path}, "", false); code != ipc.ExitInternal {
+		t.Fatalf("callTool() = %d, want %d", code, ipc.ExitInternal)
+	}
+	if !strings.Contains(stderr.String(), "writing --output-file") {
+		t.Fatalf("stderr = %q, want write error", stderr.String())
+	}
+}
+
+func TestCallToolOutputFileLeavesFileOnToolError(t *testing.T) {
+	oldOut, oldErr := rootStdout, rootStderr
+	defer func() { rootStdout, rootStderr = oldOut, oldErr }()
+	var stdout, stderr bytes.Buffer
+	rootStdout, rootStderr = &stdout, &stderr
+	stubConfirmTerminal(t, false, "")
+
+	client := stubDaemonClient{sendFn: func(req *ipc.Request) (*ipc.Response, error) {
+		return &ipc.Response{ExitCode: ipc.ExitToolErr, Content: []byte("boom")}, nil
+	}}
+
+	path := filepath.Join(t.TempDir(), "result.txt")
+	if code := callTool(client, "svc", "get", []string{"--output-file", path}, "", false); code != ipc.ExitToolErr {
+		t.Fatalf("callTool() = %d, want %d", code, ipc.ExitToolErr)
+	}
+	if _, err := os.Stat(path); !os.IsNotExist(err) {
+		t.Fatalf("Stat(output file) error = %v, want not exist", err)
+	}
+	if stderr.String() != "boom" {
+		t.Fatalf("stderr = %q, want tool error", stderr.String())
+	}
+}
+
+func TestParseToolCallArgsRejectsOutputFileWithPerItem(t *testing.T) {
+	_, err := parseToolCallArgs([]string{"--output-file", "out.json", "--output-file-per-item", "dir"}, nil, true, outputModeText)
+	if err == nil || !strings.Contains(err.Error(), "--output-file cannot be combined with --output-file-per-item") {
+		t.Fatalf("parseToolCallArgs() error = %v, want conflict", err)
+	}
+}
+
+func TestCallToolTeeWritesResultToStdoutAndFile(t *testing.T) {
+	oldOut, oldErr := rootStdout, rootStderr
+	defer func() { rootStdout, rootStderr = oldOut, oldErr }()
+	var stdout, stderr bytes.Buffer
+	rootStdout, rootStderr = &stdout, &stderr
+	stubConfirmTerminal(t, false, "")
+
+	client := stubDaemonClient{sendFn: func(req *ipc.Request) (*ipc.Response, error) {
+		return &ipc.Response{Content: []byte(`{"ok":true}`), Stderr: "server log"}, nil
+	}}
+
+	path := filepath.Join(t.TempDir(), "result.json")
+	if code := callTool(client, "svc", "get", []string{"--tee", path, "--quiet"}, "", false); code != ipc.ExitOK {
+		t.Fatalf("callTool() = %d, want %d (stderr=%q)", code, ipc.ExitOK, stderr.String())
+	}
+	if stdout.String() != `{"ok":true}` {
+		t.Fatalf("stdout = %q, want result", stdout.String())
+	}
+	if stderr.Len() != 0 {
+		t.Fatalf("stderr = %q, want empty with --quiet", stderr.String())
+	}
+	got, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("ReadFile() error = %v", err)
+	}
+	if string(got) != `{"ok":true}` {
+		t.Fatalf("file = %q, want result", got)
+	}
+}
+
+func TestParseToolCallArgsTeeConflictsWithOutputFile(t *testing.T) {
+	_, err := parseToolCallArgs([]string{"--tee", "a.json", "--output-file", "b.json"}, nil, true, outputModeText)
+	if err == nil || !strings.Contains(err.Error(), "--tee cannot be combined with --output-file") {
+		t.Fatalf("parseToolCallArgs() error = %v, want conflict", err)
+	}
+}
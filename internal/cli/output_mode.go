@@ -1,12 +1,74 @@
 package cli
 
+import (
+	"fmt"
+	"io"
+
+	"github.com/lydakis/mcpx/internal/config"
+)
+
 type outputMode int
 
 const (
 	outputModeText outputMode = iota
 	outputModeJSON
+	outputModeYAML
 )
 
 func (m outputMode) isJSON() bool {
 	return m == outputModeJSON
 }
+
+func (m outputMode) isYAML() bool {
+	return m == outputModeYAML
+}
+
+// isStructured reports whether m emits a data document (JSON or YAML)
+// instead of text.
+func (m outputMode) isStructured() bool {
+	return m == outputModeJSON || m == outputModeYAML
+}
+
+// flag names the flag that selects m, for error messages.
+func (m outputMode) flag() string {
+	switch m {
+	case outputModeJSON:
+		return "--json"
+	case outputModeYAML:
+		return "--yaml"
+	default:
+		return "--text"
+	}
+}
+
+// setStructuredOutput applies --json or --yaml to current, rejecting the
+// other structured flag once one has been given.
+func setStructuredOutput(current *outputMode, structuredSet *bool, mode outputMode) error {
+	if *structuredSet && *current != mode {
+		return fmt.Errorf("--json and --yaml cannot be combined")
+	}
+	*current = mode
+	*structuredSet = true
+	return nil
+}
+
+// writeStructuredLine writes payload as JSON, or as YAML in YAML mode.
+func writeStructuredLine(w io.Writer, output outputMode, payload any) error {
+	if output.isYAML() {
+		return writeYAMLLine(w, payload)
+	}
+	return writeJSONLine(w, payload)
+}
+
+// outputModeFromConfig returns the output mode used when no --json, --yaml,
+// or --text flag is given: JSON when default_output or MCPX_OUTPUT selects
+// it, else text. A nil cfg means text.
+func outputModeFromConfig(cfg *config.Config) outputMode {
+	if cfg == nil {
+		return outputModeText
+	}
+	if mode, err := config.ParseDefaultOutput(cfg.DefaultOutput); err == nil && mode == config.OutputJSON {
+		return outputModeJSON
+	}
+	return outputModeText
+}
@@ -0,0 +1,138 @@
+package cli
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+
+	"github.com/lydakis/mcpx/internal/config"
+	"github.com/lydakis/mcpx/internal/ipc"
+)
+
+func TestDefaultOutputJSONAppliesToListAndCallParsing(t *testing.T) {
+	defaultOutput := outputModeFromConfig(&config.Config{DefaultOutput: "json"})
+
+	root, ok, err := parseRootServerListArgs(nil, defaultOutput)
+	if err != nil || !ok || !root.output.isJSON() {
+		t.Fatalf("parseRootServerListArgs(nil) = %+v, %v, %v; want JSON", root, ok, err)
+	}
+	if root, _, err := parseRootServerListArgs([]string{"--text"}, defaultOutput); err != nil || root.output.isJSON() {
+		t.Fatalf("parseRootServerListArgs(--text) = %+v, %v; want text", root, err)
+	}
+	if root, _, err := parseRootServerListArgs([]string{"--graph"}, defaultOutput); err != nil || root.output.isJSON() {
+		t.Fatalf("parseRootServerListArgs(--graph) = %+v, %v; want text graph", root, err)
+	}
+
+	list, err := parseToolListArgs(nil, defaultOutput)
+	if err != nil || !list.output.isJSON() {
+		t.Fatalf("parseToolListArgs(nil) = %+v, %v; want JSON", list, err)
+	}
+	if list, err := parseToolListArgs([]string{"--text"}, defaultOutput); err != nil || list.output.isJSON() {
+		t.Fatalf("parseToolListArgs(--text) = %+v, %v; want text", list, err)
+	}
+	if list, err := parseToolListArgs([]string{"--names-only"}, defaultOutput); err != nil || list.output.isJSON() {
+		t.Fatalf("parseToolListArgs(--names-only) = %+v, %v; want text names", list, err)
+	}
+
+	call, err := parseToolCallArgs([]string{"--help"}, nil, true, defaultOutput)
+	if err != nil || !call.output.isJSON() {
+		t.Fatalf("parseToolCallArgs(--help) = %+v, %v; want JSON help", call, err)
+	}
+	if _, err := parseToolCallArgs([]string{"--owner", "lydakis"}, nil, true, defaultOutput); err != nil {
+		t.Fatalf("parseToolCallArgs(plain call) error = %v, want nil with JSON default", err)
+	}
+	if _, err := parseToolCallArgs([]string{"--json", "--owner", "lydakis"}, nil, true, defaultOutput); err == nil {
+		t.Fatal("parseToolCallArgs(--json plain call) error = nil, want explicit --json rejected")
+	}
+}
+
+func TestParseToolCallArgsBareTextOverridesJSONDefault(t *testing.T) {
+	call, err := parseToolCallArgs([]string{"--help", "--text"}, nil, true, outputModeJSON)
+	if err != nil || call.output.isJSON() {
+		t.Fatalf("parseToolCallArgs(--help --text) = %+v, %v; want text help", call, err)
+	}
+	call, err = parseToolCallArgs([]string{"--text", "--owner", "lydakis"}, nil, true, outputModeJSON)
+	if err != nil || call.output.isJSON() {
+		t.Fatalf("parseToolCallArgs(--text plain call) = %+v, %v; want text", call, err)
+	}
+	if _, ok := call.toolArgs["text"]; ok {
+		t.Fatalf("toolArgs = %#v, want bare --text consumed as output flag", call.toolArgs)
+	}
+
+	call, err = parseToolCallArgs([]string{"--text", "hi"}, nil, true, outputModeJSON)
+	if err != nil || call.toolArgs["text"] != "hi" || !call.output.isJSON() {
+		t.Fatalf("parseToolCallArgs(--text hi) = %+v, %v; want text param with JSON output", call, err)
+	}
+	call, err = parseToolCallArgs([]string{"--text=true"}, nil, true, outputModeJSON)
+	if err != nil || call.toolArgs["text"] != "true" {
+		t.Fatalf("parseToolCallArgs(--text=true) = %+v, %v; want text param", call, err)
+	}
+}
+
+func TestRunUsesMCPXOutputEnvForServerList(t *testing.T) {
+	tmp := t.TempDir()
+	t.Setenv("XDG_CONFIG_HOME", tmp)
+	t.Setenv("HOME", tmp)
+	t.Setenv(config.DefaultOutputEnvVar, "json")
+
+	oldOut, oldErr := rootStdout, rootStderr
+	oldSpawn, oldNewClient := spawnOrConnectFn, newDaemonClient
+	defer func() {
+		rootStdout, rootStderr = oldOut, oldErr
+		spawnOrConnectFn, newDaemonClient = oldSpawn, oldNewClient
+	}()
+	var out, errOut bytes.Buffer
+	rootStdout, rootStderr = &out, &errOut
+
+	spawnOrConnectFn = func() (string, error) { return "nonce", nil }
+	newDaemonClient = func(string, string) daemonRequester {
+		return stubDaemonClient{sendFn: func(req *ipc.Request) (*ipc.Response, error) {
+			return &ipc.Response{Content: []byte(`[{"name":"github"}]`)}, nil
+		}}
+	}
+
+	if code := Run(nil); code != ipc.ExitOK {
+		t.Fatalf("Run() = %d, want %d (stderr=%q)", code, ipc.ExitOK, errOut.String())
+	}
+	var names []string
+	if err := json.Unmarshal(out.Bytes(), &names); err != nil || len(names) != 1 || names[0] != "github" {
+		t.Fatalf("stdout = %q, want JSON server names (err=%v)", out.String(), err)
+	}
+
+	out.Reset()
+	if code := Run([]string{"--text"}); code != ipc.ExitOK {
+		t.Fatalf("Run(--text) = %d, want %d (stderr=%q)", code, ipc.ExitOK, errOut.String())
+	}
+	if got := out.String(); got != "github\n" {
+		t.Fatalf("stdout = %q, want text server list", got)
+	}
+}
+
+func TestYAMLOutputFlagsParseAndRejectJSON(t *testing.T) {
+	root, ok, err := parseRootServerListArgs([]string{"--yaml", "-v"}, outputModeText)
+	if err != nil || !ok || !root.output.isYAML() {
+		t.Fatalf("parseRootServerListArgs(--yaml) = %+v, %v, %v; want YAML", root, ok, err)
+	}
+	if list, err := parseToolListArgs([]string{"--yaml"}, outputModeText); err != nil || !list.output.isYAML() {
+		t.Fatalf("parseToolListArgs(--yaml) = %+v, %v; want YAML", list, err)
+	}
+	if call, err := parseToolCallArgs([]string{"--help", "--yaml"}, nil, true, outputModeText); err != nil || !call.output.isYAML() {
+		t.Fatalf("parseToolCallArgs(--help --yaml) = %+v, %v; want YAML help", call, err)
+	}
+
+	if _, _, err := parseRootServerListArgs([]string{"--json", "--yaml"}, outputModeText); err == nil {
+		t.Fatal("parseRootServerListArgs(--json --yaml) error = nil, want conflict")
+	}
+	if _, err := parseToolListArgs([]string{"--yaml", "--json"}, outputModeText); err == nil {
+		t.Fatal("parseToolListArgs(--yaml --json) error = nil, want conflict")
+	}
+	if _, err := parseToolCallArgs([]string{"--help", "--json", "--yaml"}, nil, true, outputModeText); err == nil {
+		t.Fatal("parseToolCallArgs(--help --json --yaml) error = nil, want conflict")
+	}
+	if _, err := parseToolCallArgs([]string{"--yaml", "--owner", "lydakis"}, nil, true, outputModeText); err == nil {
+		t.Fatal("parseToolCallArgs(--yaml plain call) error = nil, want --help required")
+	}
+	if _, _, err := parseRootServerListArgs([]string{"--yaml", "--graph"}, outputModeText); err == nil {
+		t.Fatal("parseRootServerListArgs(--yaml --graph) error = nil, want conflict")
+	}
+}
@@ -0,0 +1,69 @@
+package cli
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+
+	"github.com/lydakis/mcpx/internal/ipc"
+)
+
+// showOutputSample prints a sample JSON document built from the tool's
+// declared output schema, without calling the tool.
+func showOutputSample(client daemonRequester, server, tool, cwd string, canonicalizeSource bool) int {
+	name, _, outputSchema, code := fetchToolSchemaPayload(client, server, tool, cwd, canonicalizeSource)
+	if code != ipc.ExitOK {
+		return code
+	}
+	if outputSchema == nil {
+		fmt.Fprintf(rootStderr, "mcpx: tool %s on server %s does not declare an output schema\n", name, server)
+		return ipc.ExitUsageErr
+	}
+
+	data, err := json.MarshalIndent(sampleOutputValue(outputSchema), "", "  ")
+	if err != nil {
+		fmt.Fprintf(rootStderr, "mcpx: encoding output sample: %v\n", err)
+		return ipc.ExitInternal
+	}
+	if err := writePayload(rootStdout, "output sample", append(data, '\n')); err != nil {
+		fmt.Fprintf(rootStderr, "mcpx: %v\n", err)
+		return ipc.ExitInternal
+	}
+	return ipc.ExitOK
+}
+
+// sampleOutputValue expands every object property so the sample shows the
+// full document shape; sampleValue keeps input examples to one property.
+// Leaf values, defaults, and enums come from sampleValue.
+func sampleOutputValue(schema map[string]any) any {
+	if schema == nil {
+		return sampleValue(nil)
+	}
+	_, hasDefault := schema["default"]
+	_, hasEnum := schema["enum"]
+	if hasDefault || hasEnum {
+		return sampleValue(schema)
+	}
+
+	typ, _ := schema["type"].(string)
+	switch typ {
+	case "array":
+		items, _ := schema["items"].(map[string]any)
+		return []any{sampleOutputValue(items)}
+	case "object":
+		props, _ := schema["properties"].(map[string]any)
+		names := make([]string, 0, len(props))
+		for name := range props {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		out := make(map[string]any, len(names))
+		for _, name := range names {
+			child, _ := props[name].(map[string]any)
+			out[name] = sampleOutputValue(child)
+		}
+		return out
+	default:
+		return sampleValue(schema)
+	}
+}
@@ -0,0 +1,79 @@
+package cli
+
+import (
+	"bytes"
+	"encoding/json"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/lydakis/mcpx/internal/ipc"
+)
+
+func TestCallToolSampleOutputPrintsDocumentFromOutputSchema(t *testing.T) {
+	oldOut, oldErr := rootStdout, rootStderr
+	defer func() { rootStdout, rootStderr = oldOut, oldErr }()
+	var stdout, stderr bytes.Buffer
+	rootStdout, rootStderr = &stdout, &stderr
+
+	payload := `{
+		"name": "search",
+		"input_schema": {"type": "object"},
+		"output_schema": {
+			"type": "object",
+			"properties": {
+				"total": {"type": "integer"},
+				"items": {"type": "array", "items": {"type": "object", "properties": {
+					"name": {"type": "string"},
+					"state": {"type": "string", "enum": ["open", "closed"]}
+				}}}
+			}
+		}
+	}`
+	client := stubDaemonClient{
+		sendFn: func(req *ipc.Request) (*ipc.Response, error) {
+			if req.Type != "tool_schema" {
+				t.Fatalf("request type = %q, want tool_schema", req.Type)
+			}
+			return &ipc.Response{Content: []byte(payload)}, nil
+		},
+	}
+
+	code := callTool(client, "github", "search", []string{"--sample-output"}, "", false)
+	if code != ipc.ExitOK {
+		t.Fatalf("callTool() = %d, want %d (stderr=%q)", code, ipc.ExitOK, stderr.String())
+	}
+
+	var got any
+	if err := json.Unmarshal(stdout.Bytes(), &got); err != nil {
+		t.Fatalf("json.Unmarshal(stdout) error = %v (stdout=%q)", err, stdout.String())
+	}
+	want := map[string]any{
+		"total": float64(1),
+		"items": []any{map[string]any{"name": "example", "state": "open"}},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("sample = %#v, want %#v", got, want)
+	}
+}
+
+func TestCallToolSampleOutputRequiresOutputSchema(t *testing.T) {
+	oldOut, oldErr := rootStdout, rootStderr
+	defer func() { rootStdout, rootStderr = oldOut, oldErr }()
+	var stdout, stderr bytes.Buffer
+	rootStdout, rootStderr = &stdout, &stderr
+
+	client := stubDaemonClient{
+		sendFn: func(*ipc.Request) (*ipc.Response, error) {
+			return &ipc.Response{Content: []byte(`{"name":"search","input_schema":{"type":"object"}}`)}, nil
+		},
+	}
+
+	code := callTool(client, "github", "search", []string{"--output-schema-sample"}, "", false)
+	if code != ipc.ExitUsageErr {
+		t.Fatalf("callTool() = %d, want %d", code, ipc.ExitUsageErr)
+	}
+	if !strings.Contains(stderr.String(), "does not declare an output schema") {
+		t.Fatalf("stderr = %q, want missing output schema message", stderr.String())
+	}
+}
@@ -0,0 +1,63 @@
+package cli
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/lydakis/mcpx/internal/config"
+	"github.com/lydakis/mcpx/internal/ipc"
+	"github.com/lydakis/mcpx/internal/paths"
+)
+
+// paramDefault is one --param-default name=value pair.
+type paramDefault struct {
+	name  string
+	value string
+}
+
+func parseParamDefault(raw string) (paramDefault, error) {
+	name, value, ok := strings.Cut(raw, "=")
+	name = strings.TrimSpace(name)
+	if !ok || name == "" {
+		return paramDefault{}, fmt.Errorf("--param-default expects <name>=<value>, got %q", raw)
+	}
+	return paramDefault{name: name, value: value}, nil
+}
+
+// saveParamDefaults persists defaults under servers.<server>.tools.<tool>.defaults
+// in the user config file. Values are stored as strings and coerced against
+// the tool schema at call time, exactly like flag values.
+func saveParamDefaults(server, tool string, defaults []paramDefault) (string, error) {
+	cfgPath := paths.ConfigFile()
+	cfg, err := config.LoadForEditFrom(cfgPath)
+	if err != nil {
+		return cfgPath, err
+	}
+	for _, def := range defaults {
+		if err := config.SetToolDefault(cfg, server, tool, def.name, def.value); err != nil {
+			return cfgPath, fmt.Errorf("%w (%s)", err, cfgPath)
+		}
+	}
+	if err := config.SaveTo(cfgPath, cfg); err != nil {
+		return cfgPath, err
+	}
+	return cfgPath, nil
+}
+
+func runSaveParamDefaults(server, tool string, parsed *toolCallArgs) int {
+	if len(parsed.toolArgs) > 0 {
+		fmt.Fprintln(rootStderr, "mcpx: --param-default saves defaults without calling the tool; remove tool arguments")
+		return ipc.ExitUsageErr
+	}
+	cfgPath, err := saveParamDefaults(server, tool, parsed.paramDefaults)
+	if err != nil {
+		fmt.Fprintf(rootStderr, "mcpx: saving defaults: %v\n", err)
+		return ipc.ExitUsageErr
+	}
+	if !parsed.quiet {
+		for _, def := range parsed.paramDefaults {
+			fmt.Fprintf(rootStderr, "mcpx: saved default %s=%s for %s %s in %s\n", def.name, def.value, server, tool, cfgPath)
+		}
+	}
+	return ipc.ExitOK
+}
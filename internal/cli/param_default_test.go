@@ -0,0 +1,74 @@
+package cli
+
+import (
+	"bytes"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/lydakis/mcpx/internal/config"
+	"github.com/lydakis/mcpx/internal/ipc"
+)
+
+func TestParseToolCallArgsCollectsParamDefaults(t *testing.T) {
+	parsed, err := parseToolCallArgs([]string{"--param-default", "limit=10", "--param-default=sort=stars=desc"}, bytes.NewBuffer(nil), true, outputModeText)
+	if err != nil {
+		t.Fatalf("parseToolCallArgs() error = %v", err)
+	}
+	want := []paramDefault{{name: "limit", value: "10"}, {name: "sort", value: "stars=desc"}}
+	if len(parsed.paramDefaults) != len(want) || parsed.paramDefaults[0] != want[0] || parsed.paramDefaults[1] != want[1] {
+		t.Fatalf("paramDefaults = %#v, want %#v", parsed.paramDefaults, want)
+	}
+
+	for _, args := range [][]string{{"--param-default"}, {"--param-default=limit"}, {"--param-default", "=10"}} {
+		if _, err := parseToolCallArgs(args, bytes.NewBuffer(nil), true, outputModeText); err == nil {
+			t.Fatalf("parseToolCallArgs(%v) error = nil, want non-nil", args)
+		}
+	}
+}
+
+func TestCallToolParamDefaultSavesConfigWithoutCalling(t *testing.T) {
+	configHome := t.TempDir()
+	t.Setenv("XDG_CONFIG_HOME", configHome)
+	cfgPath := filepath.Join(configHome, "mcpx", "config.toml")
+	if err := os.MkdirAll(filepath.Dir(cfgPath), 0o700); err != nil {
+		t.Fatalf("MkdirAll() error = %v", err)
+	}
+	if err := os.WriteFile(cfgPath, []byte("[servers.github]\ncommand = \"gh-mcp\"\n"), 0o600); err != nil {
+		t.Fatalf("WriteFile() error = %v", err)
+	}
+
+	oldOut, oldErr := rootStdout, rootStderr
+	defer func() { rootStdout, rootStderr = oldOut, oldErr }()
+	var stdout, stderr bytes.Buffer
+	rootStdout, rootStderr = &stdout, &stderr
+
+	client := stubDaemonClient{
+		sendFn: func(*ipc.Request) (*ipc.Response, error) {
+			return nil, errors.New("daemon should not be called")
+		},
+	}
+
+	code := callTool(client, "github", "search", []string{"--param-default", "limit=10"}, "", false)
+	if code != ipc.ExitOK {
+		t.Fatalf("callTool() = %d, want %d (stderr=%q)", code, ipc.ExitOK, stderr.String())
+	}
+
+	cfg, err := config.LoadFrom(cfgPath)
+	if err != nil {
+		t.Fatalf("LoadFrom() error = %v", err)
+	}
+	if got := cfg.Servers["github"].Tools["search"].Defaults["limit"]; got != "10" {
+		t.Fatalf("saved default = %#v, want %q", got, "10")
+	}
+
+	code = callTool(client, "github", "search", []string{"--param-default", "limit=10", "--query=x"}, "", false)
+	if code != ipc.ExitUsageErr {
+		t.Fatalf("callTool(with tool args) = %d, want %d", code, ipc.ExitUsageErr)
+	}
+	code = callTool(client, "gitlab", "search", []string{"--param-default", "limit=10"}, "", false)
+	if code != ipc.ExitUsageErr {
+		t.Fatalf("callTool(unknown server) = %d, want %d", code, ipc.ExitUsageErr)
+	}
+}
@@ -0,0 +1,89 @@
+package cli
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"slices"
+	"strings"
+
+	"github.com/lydakis/mcpx/internal/ipc"
+)
+
+// paramFileJSON is one --param-file-json name=path pair with the file's
+// decoded JSON object or array.
+type paramFileJSON struct {
+	name  string
+	path  string
+	value any
+}
+
+func parseParamFileJSON(raw string) (paramFileJSON, error) {
+	name, path, ok := strings.Cut(raw, "=")
+	name = strings.TrimSpace(name)
+	path = strings.TrimSpace(path)
+	if !ok || name == "" || path == "" {
+		return paramFileJSON{}, fmt.Errorf("--param-file-json expects <name>=<path>, got %q", raw)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return paramFileJSON{}, fmt.Errorf("--param-file-json %s: %w", name, err)
+	}
+	var value any
+	if err := json.Unmarshal(data, &value); err != nil {
+		return paramFileJSON{}, fmt.Errorf("--param-file-json %s: %s is not valid JSON: %w", name, path, err)
+	}
+	if jsonKind(value) == "" {
+		return paramFileJSON{}, fmt.Errorf("--param-file-json %s: %s must contain a JSON object or array", name, path)
+	}
+	return paramFileJSON{name: name, path: path, value: value}, nil
+}
+
+// jsonKind names the schema type of a decoded object or array, or "" for
+// scalars.
+func jsonKind(value any) string {
+	switch value.(type) {
+	case map[string]any:
+		return "object"
+	case []any:
+		return "array"
+	default:
+		return ""
+	}
+}
+
+// checkParamFileJSON verifies each file-sourced parameter is declared in the
+// tool's input schema with a type that accepts the file's object or array.
+func checkParamFileJSON(inputSchema map[string]any, params []paramFileJSON) error {
+	props, _ := inputSchema["properties"].(map[string]any)
+	for _, param := range params {
+		prop, ok := props[param.name].(map[string]any)
+		if !ok {
+			return fmt.Errorf("--param-file-json %s: tool has no parameter %q", param.name, param.name)
+		}
+		types := toStringSlice(prop["type"])
+		if typ, ok := prop["type"].(string); ok {
+			types = []string{typ}
+		}
+		kind := jsonKind(param.value)
+		if len(types) > 0 && !slices.Contains(types, kind) {
+			return fmt.Errorf("--param-file-json %s: %s holds a JSON %s but the parameter is %s", param.name, param.path, kind, strings.Join(types, "|"))
+		}
+	}
+	return nil
+}
+
+// validateParamFileJSON fetches the tool schema and checks parsed.paramFiles
+// against it before calling.
+func validateParamFileJSON(client daemonRequester, server, tool, cwd string, parsed *toolCallArgs, canonicalizeSource bool) int {
+	_, inputSchema, _, code := fetchToolSchemaPayload(client, server, tool, cwd, canonicalizeSource)
+	if code != ipc.ExitOK {
+		return code
+	}
+	if err := checkParamFileJSON(inputSchema, parsed.paramFiles); err != nil {
+		fmt.Fprintf(rootStderr, "mcpx: %v\n", err)
+		return ipc.ExitUsageErr
+	}
+	return ipc.ExitOK
+}
@@ -0,0 +1,92 @@
+package cli
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func writeJSONFile(t *testing.T, name, content string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), name)
+	if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+		t.Fatalf("WriteFile() error = %v", err)
+	}
+	return path
+}
+
+func TestParseToolCallArgsParamFileJSONObjectAndArray(t *testing.T) {
+	filter := writeJSONFile(t, "filter.json", `{"state":"open","labels":{"any":["bug"]}}`)
+	ids := writeJSONFile(t, "ids.json", `[1, 2, 3]`)
+
+	parsed, err := parseToolCallArgs([]string{
+		"--param-file-json", "filter=" + filter,
+		"--param-file-json=ids=" + ids,
+		"--limit=5",
+	}, bytes.NewBuffer(nil), true, outputModeText)
+	if err != nil {
+		t.Fatalf("parseToolCallArgs() error = %v", err)
+	}
+
+	want := map[string]any{
+		"filter": map[string]any{"state": "open", "labels": map[string]any{"any": []any{"bug"}}},
+		"ids":    []any{float64(1), float64(2), float64(3)},
+		"limit":  "5",
+	}
+	if !reflect.DeepEqual(parsed.toolArgs, want) {
+		t.Fatalf("toolArgs = %#v, want %#v", parsed.toolArgs, want)
+	}
+	if len(parsed.paramFiles) != 2 {
+		t.Fatalf("paramFiles = %d, want 2", len(parsed.paramFiles))
+	}
+}
+
+func TestParseToolCallArgsParamFileJSONRejectsBadInput(t *testing.T) {
+	scalar := writeJSONFile(t, "scalar.json", `"just a string"`)
+	broken := writeJSONFile(t, "broken.json", `{"state":`)
+	object := writeJSONFile(t, "object.json", `{}`)
+
+	cases := map[string][]string{
+		"missing path":    {"--param-file-json", "filter"},
+		"missing file":    {"--param-file-json", "filter=" + filepath.Join(t.TempDir(), "none.json")},
+		"invalid JSON":    {"--param-file-json", "filter=" + broken},
+		"scalar JSON":     {"--param-file-json", "filter=" + scalar},
+		"set by flag too": {"--filter=x", "--param-file-json", "filter=" + object},
+	}
+	for name, args := range cases {
+		if _, err := parseToolCallArgs(args, bytes.NewBuffer(nil), true, outputModeText); err == nil {
+			t.Errorf("%s: parseToolCallArgs(%q) error = nil, want non-nil", name, args)
+		}
+	}
+}
+
+func TestCheckParamFileJSONMatchesSchema(t *testing.T) {
+	schema := map[string]any{
+		"properties": map[string]any{
+			"filter": map[string]any{"type": "object"},
+			"ids":    map[string]any{"type": []any{"array", "null"}},
+			"query":  map[string]any{"type": "string"},
+		},
+	}
+
+	ok := []paramFileJSON{
+		{name: "filter", value: map[string]any{}},
+		{name: "ids", value: []any{}},
+	}
+	if err := checkParamFileJSON(schema, ok); err != nil {
+		t.Fatalf("checkParamFileJSON() error = %v", err)
+	}
+
+	if err := checkParamFileJSON(schema, []paramFileJSON{{name: "missing", value: []any{}}}); err == nil || !strings.Contains(err.Error(), "no parameter") {
+		t.Fatalf("checkParamFileJSON(unknown param) error = %v, want no-parameter error", err)
+	}
+	if err := checkParamFileJSON(schema, []paramFileJSON{{name: "filter", path: "f.json", value: []any{}}}); err == nil || !strings.Contains(err.Error(), "JSON array but the parameter is object") {
+		t.Fatalf("checkParamFileJSON(array for object) error = %v, want type mismatch", err)
+	}
+	if err := checkParamFileJSON(schema, []paramFileJSON{{name: "query", value: map[string]any{}}}); err == nil {
+		t.Fatal("checkParamFileJSON(object for string) error = nil, want non-nil")
+	}
+}
@@ -0,0 +1,53 @@
+package cli
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/lydakis/mcpx/internal/ipc"
+)
+
+// promptToolParams reads each --param-prompt parameter from the terminal
+// with echo off and sets it in parsed.toolArgs. It returns a non-OK exit
+// code when stdin is not a terminal or the input cannot be read.
+func promptToolParams(parsed *toolCallArgs) int {
+	if !confirmInteractive() {
+		fmt.Fprintf(rootStderr, "mcpx: --param-prompt %s needs a terminal to read from\n", parsed.promptParams[0])
+		return ipc.ExitUsageErr
+	}
+
+	if parsed.toolArgs == nil {
+		parsed.toolArgs = make(map[string]any)
+	}
+	reader := bufio.NewReader(confirmInput)
+	for _, name := range parsed.promptParams {
+		fmt.Fprintf(rootStderr, "%s: ", name)
+		value, err := readHiddenLine(reader)
+		fmt.Fprintln(rootStderr)
+		if err != nil {
+			fmt.Fprintf(rootStderr, "mcpx: reading %s: %v\n", name, err)
+			return ipc.ExitUsageErr
+		}
+		parsed.toolArgs[name] = value
+	}
+	return ipc.ExitOK
+}
+
+// readHiddenLine reads one line, without its line ending, turning off echo
+// while it does when confirmInput is the terminal.
+func readHiddenLine(reader *bufio.Reader) (string, error) {
+	if file, ok := confirmInput.(*os.File); ok {
+		restore, err := disableEcho(file)
+		if err != nil {
+			return "", err
+		}
+		defer restore()
+	}
+	line, err := reader.ReadString('\n')
+	if err != nil && line == "" {
+		return "", err
+	}
+	return strings.TrimRight(line, "\r\n"), nil
+}
@@ -0,0 +1,70 @@
+package cli
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/lydakis/mcpx/internal/ipc"
+)
+
+func TestCallToolParamPromptReadsValueFromTerminal(t *testing.T) {
+	oldOut, oldErr := rootStdout, rootStderr
+	defer func() { rootStdout, rootStderr = oldOut, oldErr }()
+	var stdout, stderr bytes.Buffer
+	rootStdout, rootStderr = &stdout, &stderr
+	stubConfirmTerminal(t, true, "hunter2\n")
+
+	var sent map[string]any
+	client := stubDaemonClient{sendFn: func(req *ipc.Request) (*ipc.Response, error) {
+		if req.Type == "call_tool" {
+			if err := json.Unmarshal(req.Args, &sent); err != nil {
+				t.Fatalf("unmarshal args: %v", err)
+			}
+		}
+		return &ipc.Response{Content: []byte("ok\n")}, nil
+	}}
+
+	code := callTool(client, "db", "connect", []string{"--user=admin", "--param-prompt", "password"}, "", false)
+	if code != ipc.ExitOK {
+		t.Fatalf("callTool() = %d, want %d (stderr=%q)", code, ipc.ExitOK, stderr.String())
+	}
+	if sent["user"] != "admin" || sent["password"] != "hunter2" {
+		t.Fatalf("call args = %#v, want user=admin and prompted password", sent)
+	}
+	if !strings.HasPrefix(stderr.String(), "password: ") || strings.Contains(stderr.String(), "hunter2") {
+		t.Fatalf("stderr = %q, want a prompt without the value", stderr.String())
+	}
+}
+
+func TestCallToolParamPromptRequiresTerminal(t *testing.T) {
+	oldOut, oldErr := rootStdout, rootStderr
+	defer func() { rootStdout, rootStderr = oldOut, oldErr }()
+	var stdout, stderr bytes.Buffer
+	rootStdout, rootStderr = &stdout, &stderr
+	stubConfirmTerminal(t, false, "hunter2\n")
+
+	calls := 0
+	client := stubDaemonClient{sendFn: func(req *ipc.Request) (*ipc.Response, error) {
+		calls++
+		return &ipc.Response{Content: []byte("ok\n")}, nil
+	}}
+
+	if code := callTool(client, "db", "connect", []string{"--param-prompt=password"}, "", false); code != ipc.ExitUsageErr {
+		t.Fatalf("callTool() = %d, want %d", code, ipc.ExitUsageErr)
+	}
+	if calls != 0 {
+		t.Fatalf("daemon requests = %d, want 0", calls)
+	}
+	if !strings.Contains(stderr.String(), "--param-prompt password needs a terminal") {
+		t.Fatalf("stderr = %q, want terminal requirement", stderr.String())
+	}
+}
+
+func TestParseToolCallArgsRejectsParamPromptWithFlagValue(t *testing.T) {
+	_, err := parseToolCallArgs([]string{"--password=x", "--param-prompt", "password"}, nil, true, outputModeText)
+	if err == nil || !strings.Contains(err.Error(), "--param-prompt password cannot be combined") {
+		t.Fatalf("parseToolCallArgs() error = %v, want conflict", err)
+	}
+}
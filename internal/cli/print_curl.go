@@ -0,0 +1,164 @@
+package cli
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/url"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/lydakis/mcpx/internal/config"
+	"github.com/lydakis/mcpx/internal/httpheaders"
+	"github.com/lydakis/mcpx/internal/ipc"
+)
+
+const redactedValue = "REDACTED"
+
+// hasPrintCurlFlag reports whether --print-curl appears before any "--"
+// separator, so normal calls never pay for an extra argument parse.
+func hasPrintCurlFlag(args []string) bool {
+	for _, arg := range args {
+		if arg == "--" {
+			return false
+		}
+		if arg == "--print-curl" {
+			return true
+		}
+	}
+	return false
+}
+
+// maybePrintToolCurl handles `mcpx <server> <tool> --print-curl` without
+// contacting the daemon or the server.
+func maybePrintToolCurl(cfg *config.Config, server, tool string, args []string) (bool, int) {
+	if !hasPrintCurlFlag(args) {
+		return false, 0
+	}
+
+	parsed, err := parseToolCallArgs(args, os.Stdin, stdinIsTTY(os.Stdin), outputModeFromConfig(cfg))
+	if err != nil {
+		fmt.Fprintf(rootStderr, "mcpx: %v\n", err)
+		return true, ipc.ExitUsageErr
+	}
+
+	var scfg config.ServerConfig
+	ok := false
+	if cfg != nil {
+		scfg, ok = cfg.Servers[server]
+	}
+	switch {
+	case !ok:
+		fmt.Fprintf(rootStderr, "mcpx: --print-curl requires a server defined in config; %q is not\n", server)
+		return true, ipc.ExitUsageErr
+	case !scfg.IsHTTP():
+		fmt.Fprintf(rootStderr, "mcpx: --print-curl is only available for HTTP servers; %s uses stdio\n", server)
+		return true, ipc.ExitUsageErr
+	}
+
+	if err := writeToolCurl(rootStdout, scfg, parsed.headers, tool, parsed.toolArgs); err != nil {
+		fmt.Fprintf(rootStderr, "mcpx: %v\n", err)
+		return true, ipc.ExitInternal
+	}
+	return true, ipc.ExitOK
+}
+
+// writeToolCurl prints a curl command approximating the tools/call request
+// mcpx would send. callHeaders (--header, --headers-from-file) override the
+// server's configured headers regardless of case, as on the HTTP transport.
+// Arguments are shown as parsed from the command line, before schema
+// coercion, and session setup (initialize) is not included.
+func writeToolCurl(w io.Writer, scfg config.ServerConfig, callHeaders map[string]string, tool string, args map[string]any) error {
+	if args == nil {
+		args = map[string]any{}
+	}
+	body, err := json.Marshal(map[string]any{
+		"jsonrpc": "2.0",
+		"id":      1,
+		"method":  "tools/call",
+		"params": map[string]any{
+			"name":      tool,
+			"arguments": args,
+		},
+	})
+	if err != nil {
+		return fmt.Errorf("encoding request body: %w", err)
+	}
+
+	headers := map[string]string{
+		"Accept":       "application/json, text/event-stream",
+		"Content-Type": "application/json",
+	}
+	merged := httpheaders.Merge(httpheaders.Merge(nil, scfg.Headers, true), callHeaders, true)
+	for name, value := range merged {
+		headers[name] = redactHeaderValue(name, value)
+	}
+	names := make([]string, 0, len(headers))
+	for name := range headers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "curl -sS -X POST %s", shellQuote(redactURL(scfg.URL)))
+	for _, name := range names {
+		fmt.Fprintf(&b, " \\\n  -H %s", shellQuote(name+": "+headers[name]))
+	}
+	fmt.Fprintf(&b, " \\\n  --data-raw %s\n", shellQuote(string(body)))
+
+	if _, err := io.WriteString(w, b.String()); err != nil {
+		return fmt.Errorf("writing curl command: %w", err)
+	}
+	return nil
+}
+
+func isSensitiveName(name string) bool {
+	lower := strings.ToLower(name)
+	switch lower {
+	case "authorization", "proxy-authorization", "cookie", "set-cookie":
+		return true
+	}
+	for _, marker := range []string{"token", "key", "secret", "password", "auth", "session"} {
+		if strings.Contains(lower, marker) {
+			return true
+		}
+	}
+	return false
+}
+
+// redactHeaderValue hides sensitive header values, keeping an auth scheme
+// such as "Bearer" so the shape of the request stays recognizable.
+func redactHeaderValue(name, value string) string {
+	if !isSensitiveName(name) {
+		return value
+	}
+	if scheme, _, ok := strings.Cut(strings.TrimSpace(value), " "); ok && scheme != "" {
+		return scheme + " " + redactedValue
+	}
+	return redactedValue
+}
+
+func redactURL(raw string) string {
+	u, err := url.Parse(raw)
+	if err != nil {
+		return raw
+	}
+	if u.User != nil {
+		u.User = url.User(redactedValue)
+	}
+	if u.RawQuery != "" {
+		query := u.Query()
+		for key := range query {
+			if isSensitiveName(key) {
+				query.Set(key, redactedValue)
+			}
+		}
+		u.RawQuery = query.Encode()
+	}
+	return u.String()
+}
+
+func shellQuote(s string) string {
+	return "'" + strings.ReplaceAll(s, "'", `'\''`) + "'"
+}
@@ -0,0 +1,106 @@
+package cli
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/lydakis/mcpx/internal/config"
+	"github.com/lydakis/mcpx/internal/ipc"
+)
+
+func TestWriteToolCurlRedactsSecrets(t *testing.T) {
+	scfg := config.ServerConfig{
+		URL: "https://user:pw@mcp.example.com/mcp?api_key=abc123&region=eu",
+		Headers: map[string]string{
+			"Authorization": "Bearer sk-live-secret",
+			"X-Api-Key":     "plainsecret",
+			"X-Client":      "mcpx",
+		},
+	}
+
+	var out bytes.Buffer
+	if err := writeToolCurl(&out, scfg, nil, "search", map[string]any{"query": "it's mcp"}); err != nil {
+		t.Fatalf("writeToolCurl() error = %v", err)
+	}
+
+	want := strings.Join([]string{
+		`curl -sS -X POST 'https://REDACTED@mcp.example.com/mcp?api_key=REDACTED&region=eu' \`,
+		`  -H 'Accept: application/json, text/event-stream' \`,
+		`  -H 'Authorization: Bearer REDACTED' \`,
+		`  -H 'Content-Type: application/json' \`,
+		`  -H 'X-Api-Key: REDACTED' \`,
+		`  -H 'X-Client: mcpx' \`,
+		`  --data-raw '{"id":1,"jsonrpc":"2.0","method":"tools/call","params":{"arguments":{"query":"it'\''s mcp"},"name":"search"}}'`,
+		``,
+	}, "\n")
+	if got := out.String(); got != want {
+		t.Fatalf("writeToolCurl() =\n%s\nwant\n%s", got, want)
+	}
+	for _, secret := range []string{"sk-live-secret", "plainsecret", "abc123", ":pw@"} {
+		if strings.Contains(out.String(), secret) {
+			t.Fatalf("output leaks %q", secret)
+		}
+	}
+}
+
+func TestMaybePrintToolCurlIncludesPerCallHeaders(t *testing.T) {
+	oldOut, oldErr := rootStdout, rootStderr
+	defer func() { rootStdout, rootStderr = oldOut, oldErr }()
+	var stdout, stderr bytes.Buffer
+	rootStdout, rootStderr = &stdout, &stderr
+
+	cfg := &config.Config{Servers: map[string]config.ServerConfig{
+		"remote": {URL: "https://mcp.example.com/mcp", Headers: map[string]string{
+			"X-Tenant": "config",
+			"X-Client": "mcpx",
+		}},
+	}}
+
+	handled, code := maybePrintToolCurl(cfg, "remote", "search", []string{"--print-curl", "--header", "x-tenant=call", "--header", "X-Trace-Id=t1"})
+	if !handled || code != ipc.ExitOK {
+		t.Fatalf("maybePrintToolCurl() = (%v, %d), want (true, %d) (stderr=%q)", handled, code, ipc.ExitOK, stderr.String())
+	}
+	got := stdout.String()
+	for _, want := range []string{`-H 'x-tenant: call'`, `-H 'X-Trace-Id: t1'`, `-H 'X-Client: mcpx'`} {
+		if !strings.Contains(got, want) {
+			t.Fatalf("stdout = %q, want %s", got, want)
+		}
+	}
+	if strings.Contains(got, "X-Tenant: config") {
+		t.Fatalf("stdout = %q, want the configured X-Tenant replaced by the per-call header", got)
+	}
+}
+
+func TestMaybePrintToolCurlRejectsStdioServers(t *testing.T) {
+	oldOut, oldErr := rootStdout, rootStderr
+	defer func() { rootStdout, rootStderr = oldOut, oldErr }()
+	var stdout, stderr bytes.Buffer
+	rootStdout, rootStderr = &stdout, &stderr
+
+	cfg := &config.Config{Servers: map[string]config.ServerConfig{
+		"local":  {Command: "local-mcp"},
+		"remote": {URL: "https://mcp.example.com/mcp"},
+	}}
+
+	handled, code := maybePrintToolCurl(cfg, "local", "search", []string{"--print-curl"})
+	if !handled || code != ipc.ExitUsageErr {
+		t.Fatalf("maybePrintToolCurl(stdio) = (%v, %d), want (true, %d)", handled, code, ipc.ExitUsageErr)
+	}
+	if !strings.Contains(stderr.String(), "only available for HTTP servers") {
+		t.Fatalf("stderr = %q, want HTTP-only message", stderr.String())
+	}
+
+	handled, code = maybePrintToolCurl(cfg, "remote", "search", []string{"--", "--print-curl"})
+	if handled {
+		t.Fatalf("maybePrintToolCurl(after --) handled = true, code = %d", code)
+	}
+
+	handled, code = maybePrintToolCurl(cfg, "remote", "search", []string{"--print-curl", "--query=mcp"})
+	if !handled || code != ipc.ExitOK {
+		t.Fatalf("maybePrintToolCurl(http) = (%v, %d), want (true, %d)", handled, code, ipc.ExitOK)
+	}
+	if !strings.HasPrefix(stdout.String(), "curl -sS -X POST 'https://mcp.example.com/mcp'") {
+		t.Fatalf("stdout = %q, want curl command", stdout.String())
+	}
+}
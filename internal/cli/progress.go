@@ -0,0 +1,28 @@
+package cli
+
+import (
+	"fmt"
+	"io"
+	"strconv"
+
+	"github.com/lydakis/mcpx/internal/ipc"
+)
+
+// progressPrinter writes each --progress update to w as one line, for
+// example "mcpx: progress 3/10 (30%): indexing".
+func progressPrinter(w io.Writer) func(ipc.Progress) {
+	return func(p ipc.Progress) {
+		line := "mcpx: progress " + formatProgressNumber(p.Progress)
+		if p.Total > 0 {
+			line += "/" + formatProgressNumber(p.Total) + fmt.Sprintf(" (%.0f%%)", 100*p.Progress/p.Total)
+		}
+		if p.Message != "" {
+			line += ": " + p.Message
+		}
+		fmt.Fprintln(w, line)
+	}
+}
+
+func formatProgressNumber(v float64) string {
+	return strconv.FormatFloat(v, 'f', -1, 64)
+}
@@ -0,0 +1,42 @@
+package cli
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/lydakis/mcpx/internal/ipc"
+)
+
+func TestCallToolProgressPrintsUpdatesToStderr(t *testing.T) {
+	oldOut, oldErr := rootStdout, rootStderr
+	defer func() { rootStdout, rootStderr = oldOut, oldErr }()
+	var stdout, stderr bytes.Buffer
+	rootStdout, rootStderr = &stdout, &stderr
+	stubConfirmTerminal(t, false, "")
+
+	client := stubDaemonClient{sendFn: func(req *ipc.Request) (*ipc.Response, error) {
+		if req.Progress && req.OnProgress != nil {
+			req.OnProgress(ipc.Progress{Progress: 3, Total: 10, Message: "indexing"})
+			req.OnProgress(ipc.Progress{Progress: 42})
+		}
+		return &ipc.Response{Content: []byte("built\n")}, nil
+	}}
+
+	if code := callTool(client, "builder", "build", []string{"--target=release"}, "", false); code != ipc.ExitOK {
+		t.Fatalf("callTool() = %d, want %d", code, ipc.ExitOK)
+	}
+	if stderr.Len() != 0 {
+		t.Fatalf("stderr without --progress = %q, want empty", stderr.String())
+	}
+
+	stdout.Reset()
+	if code := callTool(client, "builder", "build", []string{"--target=release", "--progress"}, "", false); code != ipc.ExitOK {
+		t.Fatalf("callTool(--progress) = %d, want %d (stderr=%q)", code, ipc.ExitOK, stderr.String())
+	}
+	if got, want := stderr.String(), "mcpx: progress 3/10 (30%): indexing\nmcpx: progress 42\n"; got != want {
+		t.Fatalf("stderr = %q, want %q", got, want)
+	}
+	if stdout.String() != "built\n" {
+		t.Fatalf("stdout = %q, want tool output only", stdout.String())
+	}
+}
@@ -0,0 +1,325 @@
+package cli
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/lydakis/mcpx/internal/config"
+	"github.com/lydakis/mcpx/internal/ipc"
+)
+
+type promptResultPayload struct {
+	Description string               `json:"description,omitempty"`
+	Messages    []promptMessageEntry `json:"messages"`
+}
+
+type promptMessageEntry struct {
+	Role    string          `json:"role"`
+	Content json.RawMessage `json:"content"`
+}
+
+// looksLikePromptName reports whether the token after `prompt` names a prompt
+// rather than tool input for a tool that happens to be called "prompt".
+func looksLikePromptName(token string) bool {
+	token = strings.TrimSpace(token)
+	if token == "" {
+		return false
+	}
+	return !strings.HasPrefix(token, "-") && !strings.HasPrefix(token, "{")
+}
+
+// parsePromptCallArgs reuses tool-call flag parsing for prompt arguments.
+// Unlike tool calls, --json selects raw message output rather than help JSON;
+// a bare --text selects text output over defaultOutput.
+func parsePromptCallArgs(args []string, stdin io.Reader, stdinIsTTY bool, defaultOutput outputMode) (*toolCallArgs, error) {
+	output := defaultOutput
+	filtered := make([]string, 0, len(args))
+	for i, arg := range args {
+		if arg == "--" {
+			filtered = append(filtered, args[i:]...)
+			break
+		}
+		if arg == "--json" {
+			output = outputModeJSON
+			continue
+		}
+		if isTextOutputFlag(args, i) {
+			output = outputModeText
+			continue
+		}
+		filtered = append(filtered, arg)
+	}
+
+	parsed, err := parseToolCallArgs(filtered, stdin, stdinIsTTY, outputModeText)
+	if err != nil {
+		return nil, err
+	}
+	if parsed.cacheTTL != nil || parsed.cacheScope != "" || parsed.explainCache || parsed.cacheErrors != nil || parsed.fresh || parsed.staleOK != nil {
+		return nil, fmt.Errorf("cache flags are not supported for prompts")
+	}
+	if parsed.onError != nil {
+		return nil, fmt.Errorf("--on-error is not supported for prompts")
+	}
+	if parsed.schemaDiff != "" {
+		return nil, fmt.Errorf("--show-schema-diff is not supported for prompts")
+	}
+	if parsed.softFail {
+		return nil, fmt.Errorf("--soft-fail is not supported for prompts")
+	}
+	if parsed.ignoreExit {
+		return nil, fmt.Errorf("--ignore-exit is not supported for prompts")
+	}
+	if len(parsed.paramDefaults) > 0 {
+		return nil, fmt.Errorf("--param-default is not supported for prompts")
+	}
+	if parsed.printCurl {
+		return nil, fmt.Errorf("--print-curl is not supported for prompts")
+	}
+	if parsed.timeout != nil || parsed.attemptTimeout != nil {
+		return nil, fmt.Errorf("timeout flags are not supported for prompts")
+	}
+	if parsed.warnSlow != nil {
+		return nil, fmt.Errorf("--warn-slow is not supported for prompts")
+	}
+	if parsed.captureStderr {
+		return nil, fmt.Errorf("--capture-stderr is not supported for prompts")
+	}
+	if parsed.rawBytes {
+		return nil, fmt.Errorf("--output-raw-bytes is not supported for prompts")
+	}
+	if parsed.rawResult {
+		return nil, fmt.Errorf("--raw is not supported for prompts")
+	}
+	if parsed.combineContent != "" {
+		return nil, fmt.Errorf("--combine-content is not supported for prompts")
+	}
+	if parsed.contentOnly != "" {
+		return nil, fmt.Errorf("--%s-only is not supported for prompts", parsed.contentOnly)
+	}
+	if parsed.outputEncoding != "" {
+		return nil, fmt.Errorf("--output-encoding is not supported for prompts")
+	}
+	if parsed.flatten {
+		return nil, fmt.Errorf("--flatten is not supported for prompts")
+	}
+	if parsed.jsonlField != "" {
+		return nil, fmt.Errorf("--jsonl-field is not supported for prompts")
+	}
+	if parsed.outputDir != "" {
+		return nil, fmt.Errorf("--output-file-per-item is not supported for prompts")
+	}
+	if parsed.outputFile != "" {
+		return nil, fmt.Errorf("--output-file is not supported for prompts")
+	}
+	if parsed.tee != "" {
+		return nil, fmt.Errorf("--tee is not supported for prompts")
+	}
+	if parsed.argsTemplate != "" || len(parsed.templateVars) > 0 {
+		return nil, fmt.Errorf("--args-template-file is not supported for prompts")
+	}
+	if len(parsed.paramFiles) > 0 {
+		return nil, fmt.Errorf("--param-file-json is not supported for prompts")
+	}
+	if len(parsed.promptParams) > 0 {
+		return nil, fmt.Errorf("--param-prompt is not supported for prompts")
+	}
+	if parsed.progress {
+		return nil, fmt.Errorf("--progress is not supported for prompts")
+	}
+	if len(parsed.headers) > 0 {
+		return nil, fmt.Errorf("--header and --headers-from-file are not supported for prompts")
+	}
+	if parsed.idempotencyKey != "" {
+		return nil, fmt.Errorf("--idempotency-key is not supported for prompts")
+	}
+	if parsed.saveAs != "" {
+		return nil, fmt.Errorf("--save-as is not supported for prompts")
+	}
+	if parsed.repeatUntil != nil {
+		return nil, fmt.Errorf("--repeat-until is not supported for prompts")
+	}
+	if parsed.mapExit != nil {
+		return nil, fmt.Errorf("--map-exit is not supported for prompts")
+	}
+	if len(parsed.redact) > 0 {
+		return nil, fmt.Errorf("--redact is not supported for prompts")
+	}
+	if parsed.retryAfterRetries > 0 {
+		return nil, fmt.Errorf("--max-retries-respect-retry-after is not supported for prompts")
+	}
+	if parsed.retryBudget != nil {
+		return nil, fmt.Errorf("--retry-budget is not supported for prompts")
+	}
+	if parsed.confirm || parsed.yes {
+		return nil, fmt.Errorf("confirmation flags are not supported for prompts")
+	}
+	if parsed.dryRun {
+		return nil, fmt.Errorf("--dry-run is not supported for prompts")
+	}
+	if parsed.examples {
+		return nil, fmt.Errorf("--examples is not supported for prompts")
+	}
+	if parsed.requiredCheck {
+		return nil, fmt.Errorf("--param-required-check is not supported for prompts")
+	}
+	if parsed.sampleOutput {
+		return nil, fmt.Errorf("--sample-output is not supported for prompts")
+	}
+	if parsed.schemaMarkdown {
+		return nil, fmt.Errorf("--dump-schema-md is not supported for prompts")
+	}
+	if parsed.noSchemaCache {
+		return nil, fmt.Errorf("--no-schema-cache is not supported for prompts")
+	}
+	parsed.output = output
+	return parsed, nil
+}
+
+func getPrompt(cfg *config.Config, client daemonRequester, server, prompt string, rawArgs []string, cwd string, canonicalizeSource bool) int {
+	parsed, err := parsePromptCallArgs(rawArgs, os.Stdin, stdinIsTTY(os.Stdin), outputModeFromConfig(cfg))
+	if err != nil {
+		fmt.Fprintf(rootStderr, "mcpx: %v\n", err)
+		return ipc.ExitUsageErr
+	}
+	if parsed.help {
+		return showPromptHelp(client, server, prompt, cwd, parsed.output, canonicalizeSource)
+	}
+
+	argsJSON, err := json.Marshal(parsed.toolArgs)
+	if err != nil {
+		if !parsed.quiet {
+			fmt.Fprintf(rootStderr, "mcpx: invalid arguments: %v\n", err)
+		}
+		return ipc.ExitUsageErr
+	}
+
+	resp, err := sendServerRequestWithEphemeralFallback(client, &ipc.Request{
+		Type:   "get_prompt",
+		Server: server,
+		Prompt: prompt,
+		Args:   argsJSON,
+		CWD:    cwd,
+	}, canonicalizeSource)
+	if err != nil {
+		if !parsed.quiet {
+			fmt.Fprintf(rootStderr, "mcpx: %v\n", err)
+		}
+		return ipc.ExitInternal
+	}
+	if resp.Stderr != "" && !parsed.quiet {
+		fmt.Fprintln(rootStderr, resp.Stderr)
+	}
+	if resp.ExitCode != ipc.ExitOK {
+		return resp.ExitCode
+	}
+
+	if parsed.output.isJSON() {
+		if err := writePayload(rootStdout, "prompt output", resp.Content); err != nil {
+			fmt.Fprintf(rootStderr, "mcpx: %v\n", err)
+			return ipc.ExitInternal
+		}
+		return resp.ExitCode
+	}
+
+	var payload promptResultPayload
+	if err := json.Unmarshal(resp.Content, &payload); err != nil {
+		fmt.Fprintf(rootStderr, "mcpx: invalid daemon response for prompt: %v\n", err)
+		return ipc.ExitInternal
+	}
+	if err := writePromptMessagesText(rootStdout, payload.Messages); err != nil {
+		fmt.Fprintf(rootStderr, "mcpx: %v\n", err)
+		return ipc.ExitInternal
+	}
+	return resp.ExitCode
+}
+
+// writePromptMessagesText renders one "role: content" block per message.
+// Text content is emitted as-is; other content blocks are emitted as JSON.
+func writePromptMessagesText(w io.Writer, messages []promptMessageEntry) error {
+	for _, msg := range messages {
+		role := strings.TrimSpace(msg.Role)
+		if role == "" {
+			role = "-"
+		}
+		if _, err := fmt.Fprintf(w, "%s: %s\n", role, promptContentText(msg.Content)); err != nil {
+			return fmt.Errorf("writing prompt output: %w", err)
+		}
+	}
+	return nil
+}
+
+func promptContentText(raw json.RawMessage) string {
+	var block struct {
+		Type string `json:"type"`
+		Text string `json:"text"`
+	}
+	if err := json.Unmarshal(raw, &block); err == nil && block.Type == "text" {
+		return block.Text
+	}
+	return strings.TrimSpace(string(raw))
+}
+
+func showPromptHelp(client daemonRequester, server, prompt, cwd string, output outputMode, canonicalizeSource bool) int {
+	resp, ok, code := sendCapabilityListRequest(client, "list_prompts", server, cwd, true, canonicalizeSource)
+	if !ok {
+		return code
+	}
+
+	var entries []promptListEntry
+	if err := decodeCapabilityListPayload(resp.Content, "prompt list", &entries); err != nil {
+		fmt.Fprintf(rootStderr, "mcpx: %v\n", err)
+		return ipc.ExitInternal
+	}
+
+	var entry *promptListEntry
+	for i := range entries {
+		if entries[i].Name == prompt {
+			entry = &entries[i]
+			break
+		}
+	}
+	if entry == nil {
+		fmt.Fprintf(rootStderr, "mcpx: prompt %s not found on server %s\n", prompt, server)
+		return ipc.ExitUsageErr
+	}
+
+	if output.isJSON() {
+		if err := writeJSONLine(rootStdout, entry); err != nil {
+			fmt.Fprintf(rootStderr, "mcpx: %v\n", err)
+			return ipc.ExitInternal
+		}
+		return ipc.ExitOK
+	}
+
+	printPromptHelp(rootStdout, server, *entry)
+	return ipc.ExitOK
+}
+
+func printPromptHelp(w io.Writer, server string, entry promptListEntry) {
+	fmt.Fprintf(w, "Usage: mcpx %s prompt %s [FLAGS]\n", server, entry.Name)
+	if desc := strings.TrimSpace(entry.Description); desc != "" {
+		fmt.Fprintf(w, "\nDescription:\n  %s\n", desc)
+	}
+
+	fmt.Fprintln(w, "\nOptions:")
+	fmt.Fprintln(w, "  Prompt arguments:")
+	if len(entry.Arguments) == 0 {
+		fmt.Fprintln(w, "    (none)")
+	}
+	for _, arg := range entry.Arguments {
+		line := schemaLine{Path: arg.Name, Type: "string", Required: arg.Required}
+		baseFlag, _ := toolFlagNames(line.Path, line.Type)
+		fmt.Fprintf(w, "    %s <%s>%s\n", baseFlag, line.Type, optionSemantics(line))
+		if desc := strings.TrimSpace(arg.Description); desc != "" {
+			fmt.Fprintf(w, "      %s\n", desc)
+		}
+	}
+	fmt.Fprintln(w, "\n  Global flags:")
+	fmt.Fprintln(w, "    --json               Emit the rendered messages as JSON.")
+	fmt.Fprintln(w, "    --quiet, -q          Suppress stderr output.")
+	fmt.Fprintln(w, "    --help, -h           Show this help output.")
+	fmt.Fprintln(w, "\nNon-string flag values are sent as their JSON encoding.")
+}
@@ -0,0 +1,163 @@
+package cli
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/lydakis/mcpx/internal/ipc"
+)
+
+func TestParseServerCommandParsesPromptInvocation(t *testing.T) {
+	cmd, err := parseServerCommand([]string{"prompt", "summarize", "--text=hi"}, outputModeText)
+	if err != nil {
+		t.Fatalf("parseServerCommand() error = %v", err)
+	}
+	if cmd.prompt != "summarize" {
+		t.Fatalf("prompt = %q, want %q", cmd.prompt, "summarize")
+	}
+	if len(cmd.toolArgs) != 1 || cmd.toolArgs[0] != "--text=hi" {
+		t.Fatalf("toolArgs = %v, want [--text=hi]", cmd.toolArgs)
+	}
+}
+
+func TestParseServerCommandKeepsPromptToolCallsWithFlagsOrJSON(t *testing.T) {
+	for _, args := range [][]string{
+		{"prompt", "--text=hi"},
+		{"prompt", `{"text":"hi"}`},
+		{"prompt"},
+	} {
+		cmd, err := parseServerCommand(args, outputModeText)
+		if err != nil {
+			t.Fatalf("parseServerCommand(%v) error = %v", args, err)
+		}
+		if cmd.prompt != "" || cmd.tool != "prompt" {
+			t.Fatalf("parseServerCommand(%v) = %#v, want tool call for prompt", args, cmd)
+		}
+	}
+}
+
+func TestParsePromptCallArgsAcceptsJSONOutputFlag(t *testing.T) {
+	parsed, err := parsePromptCallArgs([]string{"--text", "hi", "--json", "--limit=3"}, nil, true, outputModeText)
+	if err != nil {
+		t.Fatalf("parsePromptCallArgs() error = %v", err)
+	}
+	if !parsed.output.isJSON() {
+		t.Fatal("output mode = text, want json")
+	}
+	if parsed.toolArgs["text"] != "hi" || parsed.toolArgs["limit"] != "3" {
+		t.Fatalf("toolArgs = %#v, want text and limit", parsed.toolArgs)
+	}
+}
+
+func TestParsePromptCallArgsBareTextOverridesJSONDefault(t *testing.T) {
+	parsed, err := parsePromptCallArgs([]string{"--topic=go", "--text"}, nil, true, outputModeJSON)
+	if err != nil {
+		t.Fatalf("parsePromptCallArgs() error = %v", err)
+	}
+	if parsed.output.isJSON() {
+		t.Fatal("output mode = json, want text")
+	}
+	if _, ok := parsed.toolArgs["text"]; ok || parsed.toolArgs["topic"] != "go" {
+		t.Fatalf("toolArgs = %#v, want only topic", parsed.toolArgs)
+	}
+}
+
+func TestParsePromptCallArgsKeepsJSONAfterSeparatorAsArgument(t *testing.T) {
+	parsed, err := parsePromptCallArgs([]string{"--", "--json=yes"}, nil, true, outputModeText)
+	if err != nil {
+		t.Fatalf("parsePromptCallArgs() error = %v", err)
+	}
+	if parsed.output.isJSON() {
+		t.Fatal("output mode = json, want text")
+	}
+	if parsed.toolArgs["json"] != "yes" {
+		t.Fatalf("toolArgs = %#v, want json argument", parsed.toolArgs)
+	}
+}
+
+func TestParsePromptCallArgsRejectsCacheFlags(t *testing.T) {
+	if _, err := parsePromptCallArgs([]string{"--cache=30s"}, nil, true, outputModeText); err == nil {
+		t.Fatal("parsePromptCallArgs() error = nil, want cache rejection")
+	}
+}
+
+func TestGetPromptRendersTextMessages(t *testing.T) {
+	oldOut := rootStdout
+	defer func() { rootStdout = oldOut }()
+	var out bytes.Buffer
+	rootStdout = &out
+
+	var gotReq *ipc.Request
+	client := stubDaemonClient{
+		sendFn: func(req *ipc.Request) (*ipc.Response, error) {
+			gotReq = req
+			return &ipc.Response{Content: []byte(`{"messages":[{"role":"user","content":{"type":"text","text":"Summarize: hi"}},{"role":"assistant","content":{"type":"image","data":"AA==","mimeType":"image/png"}}]}`)}, nil
+		},
+	}
+
+	if code := getPrompt(nil, client, "docs", "summarize", []string{"--text=hi"}, "/tmp", false); code != ipc.ExitOK {
+		t.Fatalf("getPrompt() = %d, want %d", code, ipc.ExitOK)
+	}
+	if gotReq == nil || gotReq.Type != "get_prompt" || gotReq.Prompt != "summarize" || string(gotReq.Args) != `{"text":"hi"}` {
+		t.Fatalf("request = %#v, want get_prompt for summarize with text arg", gotReq)
+	}
+
+	lines := strings.Split(strings.TrimSpace(out.String()), "\n")
+	if len(lines) != 2 {
+		t.Fatalf("output lines = %d, want 2 (output=%q)", len(lines), out.String())
+	}
+	if lines[0] != "user: Summarize: hi" {
+		t.Fatalf("first line = %q, want user text", lines[0])
+	}
+	if !strings.HasPrefix(lines[1], `assistant: {"type":"image"`) {
+		t.Fatalf("second line = %q, want assistant JSON content", lines[1])
+	}
+}
+
+func TestGetPromptJSONWritesRawPayload(t *testing.T) {
+	oldOut := rootStdout
+	defer func() { rootStdout = oldOut }()
+	var out bytes.Buffer
+	rootStdout = &out
+
+	payload := `{"messages":[{"role":"user","content":{"type":"text","text":"hi"}}]}` + "\n"
+	client := stubDaemonClient{
+		sendFn: func(*ipc.Request) (*ipc.Response, error) {
+			return &ipc.Response{Content: []byte(payload)}, nil
+		},
+	}
+
+	if code := getPrompt(nil, client, "docs", "greet", []string{"--json"}, "/tmp", false); code != ipc.ExitOK {
+		t.Fatalf("getPrompt() = %d, want %d", code, ipc.ExitOK)
+	}
+	if out.String() != payload {
+		t.Fatalf("stdout = %q, want raw payload %q", out.String(), payload)
+	}
+}
+
+func TestShowPromptHelpListsArguments(t *testing.T) {
+	oldOut := rootStdout
+	defer func() { rootStdout = oldOut }()
+	var out bytes.Buffer
+	rootStdout = &out
+
+	client := stubDaemonClient{
+		sendFn: func(req *ipc.Request) (*ipc.Response, error) {
+			if req.Type != "list_prompts" {
+				t.Fatalf("request type = %q, want list_prompts", req.Type)
+			}
+			return &ipc.Response{Content: []byte(`[{"name":"summarize","description":"Summarize text","arguments":[{"name":"text","description":"Input text","required":true}]}]`)}, nil
+		},
+	}
+
+	if code := getPrompt(nil, client, "docs", "summarize", []string{"--help"}, "/tmp", false); code != ipc.ExitOK {
+		t.Fatalf("getPrompt(--help) = %d, want %d", code, ipc.ExitOK)
+	}
+	got := out.String()
+	for _, want := range []string{"Usage: mcpx docs prompt summarize [FLAGS]", "--text <string> (required)", "Input text"} {
+		if !strings.Contains(got, want) {
+			t.Fatalf("help output missing %q:\n%s", want, got)
+		}
+	}
+}
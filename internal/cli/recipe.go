@@ -0,0 +1,128 @@
+package cli
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+
+	"github.com/lydakis/mcpx/internal/config"
+	"github.com/lydakis/mcpx/internal/ipc"
+	"github.com/lydakis/mcpx/internal/paths"
+)
+
+// saveRecipe persists a call under recipes.<name> in the user config file.
+func saveRecipe(name, server, tool string, args map[string]any) (string, error) {
+	cfgPath := paths.ConfigFile()
+	cfg, err := config.LoadForEditFrom(cfgPath)
+	if err != nil {
+		return cfgPath, err
+	}
+	recipe := config.Recipe{Server: server, Tool: tool}
+	if len(args) > 0 {
+		recipe.Args = args
+	}
+	if err := config.SetRecipe(cfg, name, recipe); err != nil {
+		return cfgPath, fmt.Errorf("%w (%s)", err, cfgPath)
+	}
+	if err := config.SaveTo(cfgPath, cfg); err != nil {
+		return cfgPath, err
+	}
+	return cfgPath, nil
+}
+
+func runSaveRecipe(server, tool string, parsed *toolCallArgs) int {
+	cfgPath, err := saveRecipe(parsed.saveAs, server, tool, parsed.toolArgs)
+	if err != nil {
+		fmt.Fprintf(rootStderr, "mcpx: saving recipe: %v\n", err)
+		return ipc.ExitUsageErr
+	}
+	if !parsed.quiet {
+		fmt.Fprintf(rootStderr, "mcpx: saved recipe %q (%s %s) in %s\n", parsed.saveAs, server, tool, cfgPath)
+	}
+	return ipc.ExitOK
+}
+
+// maybeHandleRunCommand resolves `mcpx run <name> [flags]` to a call of the
+// saved recipe. It returns a nil invocation with handled set when Run should
+// exit with code instead (help, or a usage error). A configured server named
+// "run" takes precedence.
+func maybeHandleRunCommand(args []string, cfg *config.Config, stdout, stderr io.Writer) (*invocation, bool, int) {
+	if len(args) == 0 || args[0] != "run" {
+		return nil, false, 0
+	}
+	if cfg != nil {
+		if _, ok := cfg.Servers["run"]; ok {
+			return nil, false, 0
+		}
+	}
+
+	var recipes map[string]config.Recipe
+	if cfg != nil {
+		recipes = cfg.Recipes
+	}
+	if len(args) < 2 {
+		fmt.Fprintln(stderr, "mcpx: run requires a recipe name")
+		printRunHelp(stderr, recipes)
+		return nil, true, ipc.ExitUsageErr
+	}
+	name := args[1]
+	if name == "--help" || name == "-h" {
+		printRunHelp(stdout, recipes)
+		return nil, true, ipc.ExitOK
+	}
+	recipe, ok := recipes[name]
+	if !ok {
+		printUnknownRecipe(stderr, name, recipes)
+		return nil, true, ipc.ExitUsageErr
+	}
+
+	return &invocation{
+		kind:   invocationKindServer,
+		server: recipe.Server,
+		serverCmd: serverCommand{
+			tool:     recipe.Tool,
+			toolArgs: args[2:],
+			baseArgs: recipe.Args,
+		},
+	}, true, ipc.ExitOK
+}
+
+func printUnknownRecipe(w io.Writer, name string, recipes map[string]config.Recipe) {
+	fmt.Fprintf(w, "mcpx: unknown recipe: %s\n", name)
+	if len(recipes) == 0 {
+		fmt.Fprintln(w, "No recipes saved; save one with mcpx <server> <tool> [args] --save-as <name>.")
+		return
+	}
+	fmt.Fprintln(w, "Available recipes:")
+	for _, name := range sortedRecipeNames(recipes) {
+		fmt.Fprintf(w, "  %s\n", name)
+	}
+}
+
+func sortedRecipeNames(recipes map[string]config.Recipe) []string {
+	names := make([]string, 0, len(recipes))
+	for name := range recipes {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+func printRunHelp(w io.Writer, recipes map[string]config.Recipe) {
+	fmt.Fprintln(w, "Usage:")
+	fmt.Fprintln(w, "  mcpx run <name> [--key=value ...] [call flags]")
+	fmt.Fprintln(w)
+	fmt.Fprintln(w, "Replays a call saved with --save-as <name>. Flags given here override the")
+	fmt.Fprintln(w, "saved arguments key by key; call flags such as --json apply as usual.")
+	fmt.Fprintln(w)
+	if len(recipes) == 0 {
+		fmt.Fprintln(w, "No recipes saved.")
+		return
+	}
+	fmt.Fprintln(w, "Recipes:")
+	for _, name := range sortedRecipeNames(recipes) {
+		recipe := recipes[name]
+		fmt.Fprintf(w, "  %s\t%s\n", name, strings.TrimSpace(recipe.Server+" "+recipe.Tool))
+	}
+}
@@ -0,0 +1,163 @@
+package cli
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/lydakis/mcpx/internal/config"
+	"github.com/lydakis/mcpx/internal/ipc"
+)
+
+func TestCallToolSaveAsStoresRecipeAndRunReplaysWithOverrides(t *testing.T) {
+	configHome := t.TempDir()
+	t.Setenv("XDG_CONFIG_HOME", configHome)
+	cfgPath := filepath.Join(configHome, "mcpx", "config.toml")
+	if err := os.MkdirAll(filepath.Dir(cfgPath), 0o700); err != nil {
+		t.Fatalf("MkdirAll() error = %v", err)
+	}
+	if err := os.WriteFile(cfgPath, []byte("[servers.github]\ncommand = \"gh-mcp\"\n"), 0o600); err != nil {
+		t.Fatalf("WriteFile() error = %v", err)
+	}
+
+	oldOut, oldErr := rootStdout, rootStderr
+	defer func() { rootStdout, rootStderr = oldOut, oldErr }()
+	var stdout, stderr bytes.Buffer
+	rootStdout, rootStderr = &stdout, &stderr
+
+	var calls []*ipc.Request
+	client := stubDaemonClient{
+		sendFn: func(req *ipc.Request) (*ipc.Response, error) {
+			calls = append(calls, req)
+			return &ipc.Response{Content: []byte("ok"), ExitCode: ipc.ExitOK}, nil
+		},
+	}
+
+	code := callTool(client, "github", "search", []string{"--query=mcp", "--limit=5", "--save-as", "mcp-repos"}, "", false)
+	if code != ipc.ExitOK {
+		t.Fatalf("callTool() = %d, want %d (stderr=%q)", code, ipc.ExitOK, stderr.String())
+	}
+	if len(calls) != 1 {
+		t.Fatalf("daemon calls = %d, want 1 (--save-as still calls the tool)", len(calls))
+	}
+	if !strings.Contains(stderr.String(), `saved recipe "mcp-repos" (github search)`) {
+		t.Fatalf("stderr = %q, want saved recipe notice", stderr.String())
+	}
+
+	cfg, err := config.LoadFrom(cfgPath)
+	if err != nil {
+		t.Fatalf("LoadFrom() error = %v", err)
+	}
+	recipe, ok := cfg.Recipes["mcp-repos"]
+	if !ok {
+		t.Fatalf("recipes = %#v, want mcp-repos", cfg.Recipes)
+	}
+	if recipe.Server != "github" || recipe.Tool != "search" || recipe.Args["query"] != "mcp" || recipe.Args["limit"] != "5" {
+		t.Fatalf("recipe = %#v, want github search with saved args", recipe)
+	}
+
+	inv, handled, code := maybeHandleRunCommand([]string{"run", "mcp-repos", "--query=mcpx"}, cfg, &stdout, &stderr)
+	if !handled || inv == nil || code != ipc.ExitOK {
+		t.Fatalf("maybeHandleRunCommand() = (%v, %v, %d), want invocation", inv, handled, code)
+	}
+	if inv.server != "github" || inv.serverCmd.tool != "search" {
+		t.Fatalf("invocation = %s %s, want github search", inv.server, inv.serverCmd.tool)
+	}
+
+	calls = nil
+	code = callToolWithBaseArgs(nil, client, inv.server, inv.serverCmd.tool, inv.serverCmd.toolArgs, inv.serverCmd.baseArgs, "", false)
+	if code != ipc.ExitOK {
+		t.Fatalf("callToolWithBaseArgs() = %d, want %d (stderr=%q)", code, ipc.ExitOK, stderr.String())
+	}
+	if len(calls) != 1 {
+		t.Fatalf("daemon calls = %d, want 1", len(calls))
+	}
+	var args map[string]any
+	if err := json.Unmarshal(calls[0].Args, &args); err != nil {
+		t.Fatalf("unmarshal args: %v", err)
+	}
+	if args["query"] != "mcpx" || args["limit"] != "5" {
+		t.Fatalf("replayed args = %#v, want override query and saved limit", args)
+	}
+}
+
+func TestMaybeHandleRunCommandRejectsUnknownRecipe(t *testing.T) {
+	cfg := &config.Config{Recipes: map[string]config.Recipe{"daily": {Server: "github", Tool: "search"}}}
+	var stdout, stderr bytes.Buffer
+
+	inv, handled, code := maybeHandleRunCommand([]string{"run", "weekly"}, cfg, &stdout, &stderr)
+	if !handled || inv != nil || code != ipc.ExitUsageErr {
+		t.Fatalf("maybeHandleRunCommand(unknown) = (%v, %v, %d), want usage error", inv, handled, code)
+	}
+	if want := "mcpx: unknown recipe: weekly\nAvailable recipes:\n  daily\n"; stderr.String() != want {
+		t.Fatalf("stderr = %q, want %q", stderr.String(), want)
+	}
+
+	cfg.Servers = map[string]config.ServerConfig{"run": {Command: "run-mcp"}}
+	if _, handled, _ := maybeHandleRunCommand([]string{"run", "daily"}, cfg, &stdout, &stderr); handled {
+		t.Fatal("maybeHandleRunCommand() handled = true, want a configured run server to win")
+	}
+}
+
+func TestRunReplaysRecipeWithAndWithoutOverrides(t *testing.T) {
+	tmp := t.TempDir()
+	t.Setenv("XDG_CONFIG_HOME", tmp)
+	t.Setenv("HOME", tmp)
+	cfgPath := filepath.Join(tmp, "mcpx", "config.toml")
+	if err := os.MkdirAll(filepath.Dir(cfgPath), 0o700); err != nil {
+		t.Fatalf("MkdirAll() error = %v", err)
+	}
+	raw := "[servers.github]\ncommand = \"gh-mcp\"\n\n[recipes.mcp-repos]\nserver = \"github\"\ntool = \"search\"\nargs = { query = \"mcp\", limit = 5 }\n"
+	if err := os.WriteFile(cfgPath, []byte(raw), 0o600); err != nil {
+		t.Fatalf("WriteFile() error = %v", err)
+	}
+
+	oldOut, oldErr := rootStdout, rootStderr
+	oldSpawn, oldNewClient := spawnOrConnectFn, newDaemonClient
+	defer func() {
+		rootStdout, rootStderr = oldOut, oldErr
+		spawnOrConnectFn, newDaemonClient = oldSpawn, oldNewClient
+	}()
+	var out, errOut bytes.Buffer
+	rootStdout, rootStderr = &out, &errOut
+
+	var last *ipc.Request
+	spawnOrConnectFn = func() (string, error) { return "nonce", nil }
+	newDaemonClient = func(string, string) daemonRequester {
+		return stubDaemonClient{sendFn: func(req *ipc.Request) (*ipc.Response, error) {
+			last = req
+			return &ipc.Response{Content: []byte("ok"), ExitCode: ipc.ExitOK}, nil
+		}}
+	}
+
+	tests := []struct {
+		name string
+		args []string
+		want map[string]any
+	}{
+		{name: "saved", args: []string{"run", "mcp-repos"}, want: map[string]any{"query": "mcp", "limit": float64(5)}},
+		{name: "override", args: []string{"run", "mcp-repos", "--limit=10"}, want: map[string]any{"query": "mcp", "limit": "10"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			last = nil
+			if code := Run(tt.args); code != ipc.ExitOK {
+				t.Fatalf("Run(%v) = %d, want %d (stderr=%q)", tt.args, code, ipc.ExitOK, errOut.String())
+			}
+			if last == nil || last.Type != "call_tool" || last.Server != "github" || last.Tool != "search" {
+				t.Fatalf("request = %+v, want call_tool github search", last)
+			}
+			var args map[string]any
+			if err := json.Unmarshal(last.Args, &args); err != nil {
+				t.Fatalf("unmarshal args: %v", err)
+			}
+			if !reflect.DeepEqual(args, tt.want) {
+				t.Fatalf("args = %#v, want %#v", args, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,106 @@
+package cli
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/lydakis/mcpx/internal/ipc"
+)
+
+// redactMask replaces each --redact path's value in printed output.
+const redactMask = "***"
+
+// redactPath is one parsed --redact path.
+type redactPath struct {
+	raw  string
+	path []any
+}
+
+func parseRedactPath(raw string) (redactPath, error) {
+	raw = strings.TrimSpace(raw)
+	if raw == "" {
+		return redactPath{}, fmt.Errorf("missing value for --redact")
+	}
+	path, err := parseResultPath(raw)
+	if err != nil {
+		return redactPath{}, fmt.Errorf("invalid --redact %q: %w", raw, err)
+	}
+	if len(path) == 0 {
+		return redactPath{}, fmt.Errorf("invalid --redact %q: path must name a field", raw)
+	}
+	return redactPath{raw: raw, path: path}, nil
+}
+
+// redactJSON replaces the value at each path in a JSON document with "***".
+// Paths the document does not have are skipped. It reports false when
+// content is not JSON.
+func redactJSON(content []byte, paths []redactPath) ([]byte, bool) {
+	dec := json.NewDecoder(bytes.NewReader(content))
+	dec.UseNumber()
+	var doc any
+	if err := dec.Decode(&doc); err != nil {
+		return content, false
+	}
+	if _, err := dec.Token(); err == nil {
+		return content, false
+	}
+
+	for _, p := range paths {
+		setResultPath(doc, p.path, redactMask)
+	}
+
+	var buf bytes.Buffer
+	enc := json.NewEncoder(&buf)
+	enc.SetEscapeHTML(false)
+	if bytes.Contains(bytes.TrimSpace(content), []byte("\n")) {
+		enc.SetIndent("", "  ")
+	}
+	if err := enc.Encode(doc); err != nil {
+		return content, false
+	}
+	out := buf.Bytes()
+	if !bytes.HasSuffix(content, []byte("\n")) {
+		out = bytes.TrimSuffix(out, []byte("\n"))
+	}
+	return out, true
+}
+
+// setResultPath replaces the value at path, which must already exist.
+func setResultPath(doc any, path []any, value any) {
+	parent, ok := lookupResultPath(doc, path[:len(path)-1])
+	if !ok {
+		return
+	}
+	switch step := path[len(path)-1].(type) {
+	case string:
+		if obj, ok := parent.(map[string]any); ok {
+			if _, exists := obj[step]; exists {
+				obj[step] = value
+			}
+		}
+	case int:
+		if arr, ok := parent.([]any); ok && step < len(arr) {
+			arr[step] = value
+		}
+	}
+}
+
+// redactedCallResponse returns resp with --redact applied to its content
+// for printing. Non-JSON results are returned unchanged with a warning.
+func redactedCallResponse(resp *ipc.Response, parsed *toolCallArgs) *ipc.Response {
+	if len(parsed.redact) == 0 || resp == nil {
+		return resp
+	}
+	content, ok := redactJSON(resp.Content, parsed.redact)
+	if !ok {
+		if !parsed.quiet {
+			fmt.Fprintln(rootStderr, "mcpx: warning: --redact skipped: result is not JSON")
+		}
+		return resp
+	}
+	redacted := *resp
+	redacted.Content = content
+	return &redacted
+}
@@ -0,0 +1,102 @@
+package cli
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/lydakis/mcpx/internal/ipc"
+)
+
+func TestRedactJSONReplacesSingleAndMultiplePaths(t *testing.T) {
+	single, err := parseRedactPath("$.token")
+	if err != nil {
+		t.Fatalf("parseRedactPath() error = %v", err)
+	}
+	got, ok := redactJSON([]byte(`{"token":"s3cr3t","user":"ada"}`), []redactPath{single})
+	if !ok || string(got) != `{"token":"***","user":"ada"}` {
+		t.Fatalf("redactJSON(single) = %s, %v", got, ok)
+	}
+
+	var paths []redactPath
+	for _, raw := range []string{"auth.key", "items[1].secret", "missing.field", "count"} {
+		path, err := parseRedactPath(raw)
+		if err != nil {
+			t.Fatalf("parseRedactPath(%q) error = %v", raw, err)
+		}
+		paths = append(paths, path)
+	}
+	content := "{\n  \"auth\": {\"key\": \"k\", \"scope\": \"read\"},\n  \"count\": 12345678901234567890,\n" +
+		"  \"items\": [{\"secret\": \"a\"}, {\"secret\": \"b\"}]\n}\n"
+	want := `{
+  "auth": {
+    "key": "***",
+    "scope": "read"
+  },
+  "count": "***",
+  "items": [
+    {
+      "secret": "a"
+    },
+    {
+      "secret": "***"
+    }
+  ]
+}
+`
+	got, ok = redactJSON([]byte(content), paths)
+	if !ok || string(got) != want {
+		t.Fatalf("redactJSON(multiple) =\n%s\nwant:\n%s", got, want)
+	}
+}
+
+func TestParseToolCallArgsCollectsRedactPaths(t *testing.T) {
+	parsed, err := parseToolCallArgs([]string{"--redact", "token", "--redact=$.auth.key", "--id=7"}, bytes.NewBuffer(nil), true, outputModeText)
+	if err != nil {
+		t.Fatalf("parseToolCallArgs() error = %v", err)
+	}
+	if len(parsed.redact) != 2 || parsed.redact[0].raw != "token" || parsed.redact[1].raw != "$.auth.key" {
+		t.Fatalf("redact = %+v, want two paths", parsed.redact)
+	}
+	if _, ok := parsed.toolArgs["redact"]; ok {
+		t.Fatal("--redact leaked into tool args")
+	}
+
+	for _, args := range [][]string{{"--redact"}, {"--redact=$"}, {"--redact=items[x]"}, {"--redact=a", "--output-raw-bytes"}} {
+		if _, err := parseToolCallArgs(args, bytes.NewBuffer(nil), true, outputModeText); err == nil {
+			t.Fatalf("parseToolCallArgs(%v) error = nil, want non-nil", args)
+		}
+	}
+}
+
+func TestCallToolRedactsPrintedOutputAndWarnsOnText(t *testing.T) {
+	oldOut, oldErr := rootStdout, rootStderr
+	defer func() { rootStdout, rootStderr = oldOut, oldErr }()
+	var stdout, stderr bytes.Buffer
+	rootStdout, rootStderr = &stdout, &stderr
+
+	content := []byte(`{"status":"ok","token":"s3cr3t"}` + "\n")
+	client := stubDaemonClient{
+		sendFn: func(*ipc.Request) (*ipc.Response, error) {
+			return &ipc.Response{ExitCode: ipc.ExitOK, Content: content}, nil
+		},
+	}
+	if code := callTool(client, "auth", "login", []string{"--redact", "token", "--map-exit", "status"}, "", false); code != ipc.ExitOK {
+		t.Fatalf("callTool() = %d, want %d (stderr=%q)", code, ipc.ExitOK, stderr.String())
+	}
+	if got := stdout.String(); got != `{"status":"ok","token":"***"}`+"\n" {
+		t.Fatalf("stdout = %q, want redacted token", got)
+	}
+
+	stdout.Reset()
+	content = []byte("token=s3cr3t\n")
+	if code := callTool(client, "auth", "login", []string{"--redact", "token"}, "", false); code != ipc.ExitOK {
+		t.Fatalf("callTool(text) = %d, want %d", code, ipc.ExitOK)
+	}
+	if stdout.String() != string(content) {
+		t.Fatalf("stdout = %q, want unchanged text", stdout.String())
+	}
+	if !strings.Contains(stderr.String(), "--redact skipped: result is not JSON") {
+		t.Fatalf("stderr = %q, want non-JSON warning", stderr.String())
+	}
+}
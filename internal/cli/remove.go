@@ -0,0 +1,147 @@
+package cli
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/lydakis/mcpx/internal/config"
+	"github.com/lydakis/mcpx/internal/ipc"
+	"github.com/lydakis/mcpx/internal/paths"
+)
+
+type removeArgs struct {
+	server string
+	yes    bool
+	help   bool
+}
+
+func maybeHandleRemoveCommand(args []string, cfg *config.Config, stdout, stderr io.Writer) (bool, int) {
+	if len(args) == 0 || (args[0] != "remove" && args[0] != "rm") {
+		return false, 0
+	}
+
+	if cfg != nil {
+		if _, ok := cfg.Servers[args[0]]; ok {
+			return false, 0
+		}
+	}
+
+	return true, runRemoveCommand(args[1:], cfg, stdout, stderr)
+}
+
+// runRemoveCommand deletes a server from the mcpx config. loaded is the
+// merged runtime config, used to point servers from other sources back at
+// the file that defines them.
+func runRemoveCommand(args []string, loaded *config.Config, stdout, stderr io.Writer) int {
+	parsed, err := parseRemoveArgs(args)
+	if err != nil {
+		fmt.Fprintf(stderr, "mcpx: %v\n", err)
+		printRemoveHelp(stderr)
+		return ipc.ExitUsageErr
+	}
+	if parsed.help {
+		printRemoveHelp(stdout)
+		return ipc.ExitOK
+	}
+
+	cfgPath := paths.ConfigFile()
+	cfg, err := config.LoadForEditFrom(cfgPath)
+	if err != nil {
+		fmt.Fprintf(stderr, "mcpx: remove: loading config: %v\n", err)
+		return ipc.ExitInternal
+	}
+
+	name := parsed.server
+	if _, ok := cfg.Servers[name]; !ok {
+		if origin, external := externalServerOrigin(loaded, name); external {
+			where := string(origin.Kind)
+			if origin.Path != "" {
+				where = fmt.Sprintf("%s (%s)", origin.Path, origin.Kind)
+			}
+			fmt.Fprintf(stderr, "mcpx: remove: server %q is defined in %s, not in %s; remove it there\n", name, where, cfgPath)
+			return ipc.ExitUsageErr
+		}
+		fmt.Fprintf(stderr, "mcpx: remove: server %q is not in %s\n", name, cfgPath)
+		return ipc.ExitUsageErr
+	}
+
+	if !parsed.yes && confirmInteractive() {
+		fmt.Fprintf(stderr, "Remove server %q from %s? [y/N] ", name, cfgPath)
+		line, _ := bufio.NewReader(confirmInput).ReadString('\n')
+		switch strings.ToLower(strings.TrimSpace(line)) {
+		case "y", "yes":
+		default:
+			fmt.Fprintln(stderr, "mcpx: remove cancelled")
+			return ipc.ExitUsageErr
+		}
+	}
+
+	delete(cfg.Servers, name)
+	delete(cfg.ServerOrigins, name)
+	if err := config.SaveTo(cfgPath, cfg); err != nil {
+		fmt.Fprintf(stderr, "mcpx: remove: writing config: %v\n", err)
+		return ipc.ExitInternal
+	}
+
+	fmt.Fprintf(stdout, "Removed server %q\n", name)
+	return ipc.ExitOK
+}
+
+// externalServerOrigin reports where name comes from when the loaded config
+// has it from somewhere other than the mcpx config file.
+func externalServerOrigin(loaded *config.Config, name string) (config.ServerOrigin, bool) {
+	if loaded == nil {
+		return config.ServerOrigin{}, false
+	}
+	if _, ok := loaded.Servers[name]; !ok {
+		return config.ServerOrigin{}, false
+	}
+	origin, ok := loaded.ServerOrigins[name]
+	if !ok {
+		return config.ServerOrigin{}, false
+	}
+	origin = config.NormalizeServerOrigin(origin)
+	return origin, origin.Kind != config.ServerOriginKindMCPXConfig
+}
+
+func parseRemoveArgs(args []string) (*removeArgs, error) {
+	parsed := &removeArgs{}
+	for _, arg := range args {
+		switch {
+		case arg == "--help" || arg == "-h":
+			parsed.help = true
+		case arg == "--yes" || arg == "-y":
+			parsed.yes = true
+		case strings.HasPrefix(arg, "-"):
+			return nil, fmt.Errorf("unknown flag: %s", arg)
+		default:
+			if parsed.server != "" {
+				return nil, fmt.Errorf("unexpected positional argument: %s", arg)
+			}
+			parsed.server = strings.TrimSpace(arg)
+		}
+	}
+
+	if parsed.help {
+		return parsed, nil
+	}
+	if parsed.server == "" {
+		return nil, fmt.Errorf("missing server (usage: mcpx remove <server>)")
+	}
+	return parsed, nil
+}
+
+func printRemoveHelp(out io.Writer) {
+	fmt.Fprintln(out, "Usage:")
+	fmt.Fprintln(out, "  mcpx remove <server> [--yes]")
+	fmt.Fprintln(out, "  mcpx rm <server> [--yes]")
+	fmt.Fprintln(out, "")
+	fmt.Fprintln(out, "Delete a server entry from the mcpx config. Servers read from other clients'")
+	fmt.Fprintln(out, "configs must be removed from the file that defines them.")
+	fmt.Fprintln(out, "")
+	fmt.Fprintln(out, "Flags:")
+	fmt.Fprintln(out, "  --yes, -y         Skip the confirmation prompt shown on a terminal.")
+	fmt.Fprintln(out, "  --help, -h        Show this help output.")
+}
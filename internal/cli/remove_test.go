@@ -0,0 +1,113 @@
+package cli
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/lydakis/mcpx/internal/config"
+	"github.com/lydakis/mcpx/internal/ipc"
+)
+
+func writeRemoveTestConfig(t *testing.T) string {
+	t.Helper()
+	tmp := t.TempDir()
+	configHome := filepath.Join(tmp, "xdg-config")
+	t.Setenv("XDG_CONFIG_HOME", configHome)
+	t.Setenv("HOME", tmp)
+
+	cfgPath := filepath.Join(configHome, "mcpx", "config.toml")
+	if err := os.MkdirAll(filepath.Dir(cfgPath), 0o700); err != nil {
+		t.Fatalf("MkdirAll(config dir): %v", err)
+	}
+	raw := "[servers.github]\ncommand = \"gh-mcp\"\n\n[servers.docs]\nurl = \"https://example.com/mcp\"\n"
+	if err := os.WriteFile(cfgPath, []byte(raw), 0o600); err != nil {
+		t.Fatalf("WriteFile(config): %v", err)
+	}
+	return cfgPath
+}
+
+func TestRunRemoveDeletesServerFromConfig(t *testing.T) {
+	cfgPath := writeRemoveTestConfig(t)
+
+	oldOut, oldErr, oldInteractive := rootStdout, rootStderr, confirmInteractive
+	defer func() { rootStdout, rootStderr, confirmInteractive = oldOut, oldErr, oldInteractive }()
+	var out, errOut bytes.Buffer
+	rootStdout, rootStderr = &out, &errOut
+	confirmInteractive = func() bool { return false }
+
+	if code := Run([]string{"rm", "github"}); code != ipc.ExitOK {
+		t.Fatalf("Run([rm github]) = %d, want %d (stderr=%q)", code, ipc.ExitOK, errOut.String())
+	}
+	if got := out.String(); got != "Removed server \"github\"\n" {
+		t.Fatalf("stdout = %q, want removal confirmation", got)
+	}
+
+	edited, err := config.LoadForEditFrom(cfgPath)
+	if err != nil {
+		t.Fatalf("LoadForEditFrom() error = %v", err)
+	}
+	if _, ok := edited.Servers["github"]; ok {
+		t.Fatal("github still in config after remove")
+	}
+	if _, ok := edited.Servers["docs"]; !ok {
+		t.Fatal("docs removed along with github")
+	}
+
+	errOut.Reset()
+	if code := Run([]string{"remove", "github"}); code != ipc.ExitUsageErr {
+		t.Fatalf("Run([remove github]) again = %d, want %d", code, ipc.ExitUsageErr)
+	}
+	if !strings.Contains(errOut.String(), `server "github" is not in`) {
+		t.Fatalf("stderr = %q, want not-found message", errOut.String())
+	}
+}
+
+func TestRunRemoveAsksOnTerminalUnlessYes(t *testing.T) {
+	cfgPath := writeRemoveTestConfig(t)
+
+	oldInput, oldInteractive := confirmInput, confirmInteractive
+	defer func() { confirmInput, confirmInteractive = oldInput, oldInteractive }()
+	confirmInteractive = func() bool { return true }
+
+	var out, errOut bytes.Buffer
+	confirmInput = strings.NewReader("n\n")
+	if code := runRemoveCommand([]string{"github"}, nil, &out, &errOut); code != ipc.ExitUsageErr {
+		t.Fatalf("runRemoveCommand(declined) = %d, want %d", code, ipc.ExitUsageErr)
+	}
+	if !strings.Contains(errOut.String(), `Remove server "github"`) || !strings.Contains(errOut.String(), "cancelled") {
+		t.Fatalf("stderr = %q, want prompt and cancellation", errOut.String())
+	}
+
+	confirmInput = strings.NewReader("")
+	if code := runRemoveCommand([]string{"github", "-y"}, nil, &out, &errOut); code != ipc.ExitOK {
+		t.Fatalf("runRemoveCommand(-y) = %d, want %d (stderr=%q)", code, ipc.ExitOK, errOut.String())
+	}
+	edited, err := config.LoadForEditFrom(cfgPath)
+	if err != nil {
+		t.Fatalf("LoadForEditFrom() error = %v", err)
+	}
+	if _, ok := edited.Servers["github"]; ok {
+		t.Fatal("github still in config after remove -y")
+	}
+}
+
+func TestRunRemoveRefusesServersFromOtherSources(t *testing.T) {
+	writeRemoveTestConfig(t)
+
+	loaded := &config.Config{
+		Servers: map[string]config.ServerConfig{"linear": {URL: "https://mcp.linear.app/mcp"}},
+		ServerOrigins: map[string]config.ServerOrigin{
+			"linear": config.NewServerOrigin(config.ServerOriginKindCodexApps, "/home/u/.codex/config.toml"),
+		},
+	}
+	var out, errOut bytes.Buffer
+	if code := runRemoveCommand([]string{"linear", "--yes"}, loaded, &out, &errOut); code != ipc.ExitUsageErr {
+		t.Fatalf("runRemoveCommand(fallback server) = %d, want %d", code, ipc.ExitUsageErr)
+	}
+	if !strings.Contains(errOut.String(), "/home/u/.codex/config.toml (codex_apps)") {
+		t.Fatalf("stderr = %q, want pointer to the defining file", errOut.String())
+	}
+}
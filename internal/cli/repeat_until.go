@@ -0,0 +1,158 @@
+package cli
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/lydakis/mcpx/internal/ipc"
+)
+
+const (
+	defaultRepeatInterval = 2 * time.Second
+	defaultRepeatMaxWait  = 5 * time.Minute
+)
+
+var (
+	repeatSleep = time.Sleep
+	repeatNow   = time.Now
+)
+
+// repeatCondition is a parsed --repeat-until path=value.
+type repeatCondition struct {
+	raw   string
+	path  []any // string keys and int indices
+	value string
+}
+
+// parseRepeatUntil parses "path=value". Paths are dot-separated keys with
+// optional [n] indices and an optional leading "$." (for example
+// "$.job.status" or "items[0].done").
+func parseRepeatUntil(raw string) (*repeatCondition, error) {
+	pathRaw, value, ok := strings.Cut(raw, "=")
+	pathRaw = strings.TrimSpace(pathRaw)
+	if !ok || pathRaw == "" {
+		return nil, fmt.Errorf("invalid --repeat-until %q: expected <path>=<value>", raw)
+	}
+	path, err := parseResultPath(pathRaw)
+	if err != nil {
+		return nil, fmt.Errorf("invalid --repeat-until %q: %w", raw, err)
+	}
+	return &repeatCondition{raw: raw, path: path, value: value}, nil
+}
+
+func parseResultPath(raw string) ([]any, error) {
+	raw = strings.TrimPrefix(strings.TrimPrefix(raw, "$"), ".")
+	if raw == "" {
+		return nil, nil
+	}
+
+	var path []any
+	for _, segment := range strings.Split(raw, ".") {
+		key, rest, hasIndex := strings.Cut(segment, "[")
+		if key == "" && !hasIndex {
+			return nil, fmt.Errorf("empty path segment")
+		}
+		if key != "" {
+			path = append(path, key)
+		}
+		for hasIndex {
+			idx, after, ok := strings.Cut(rest, "]")
+			n, err := strconv.Atoi(idx)
+			if !ok || err != nil || n < 0 {
+				return nil, fmt.Errorf("bad index in path segment %q", segment)
+			}
+			path = append(path, n)
+			if after == "" {
+				break
+			}
+			if !strings.HasPrefix(after, "[") {
+				return nil, fmt.Errorf("bad path segment %q", segment)
+			}
+			rest = after[1:]
+		}
+	}
+	return path, nil
+}
+
+// matches reports whether the JSON tool output has value at path. The value
+// is compared as JSON when it parses as JSON (true, 3, "x"), otherwise as a
+// plain string.
+func (c *repeatCondition) matches(content []byte) bool {
+	var doc any
+	if err := json.Unmarshal(content, &doc); err != nil {
+		return false
+	}
+	found, ok := lookupResultPath(doc, c.path)
+	if !ok {
+		return false
+	}
+	if s, isString := found.(string); isString && s == c.value {
+		return true
+	}
+	var want any
+	if err := json.Unmarshal([]byte(c.value), &want); err != nil {
+		return false
+	}
+	return reflect.DeepEqual(found, want)
+}
+
+func lookupResultPath(doc any, path []any) (any, bool) {
+	cur := doc
+	for _, step := range path {
+		switch step := step.(type) {
+		case string:
+			obj, ok := cur.(map[string]any)
+			if !ok {
+				return nil, false
+			}
+			if cur, ok = obj[step]; !ok {
+				return nil, false
+			}
+		case int:
+			arr, ok := cur.([]any)
+			if !ok || step >= len(arr) {
+				return nil, false
+			}
+			cur = arr[step]
+		}
+	}
+	return cur, true
+}
+
+// pollToolCall re-sends req every interval until the condition holds on a
+// successful response, a call fails, or max-wait elapses. Only the final
+// response is returned. Caching is off unless --cache was given explicitly,
+// since a cached result would never change.
+func pollToolCall(client daemonRequester, req *ipc.Request, parsed *toolCallArgs, canonicalizeSource bool) (*ipc.Response, error) {
+	if req.Cache == nil {
+		noCache := time.Duration(0)
+		req.Cache = &noCache
+	}
+	interval := defaultRepeatInterval
+	if parsed.repeatInterval != nil {
+		interval = *parsed.repeatInterval
+	}
+	maxWait := defaultRepeatMaxWait
+	if parsed.repeatMaxWait != nil {
+		maxWait = *parsed.repeatMaxWait
+	}
+
+	deadline := repeatNow().Add(maxWait)
+	for calls := 1; ; calls++ {
+		resp, err := sendServerRequestWithEphemeralFallback(client, req, canonicalizeSource)
+		if err != nil || resp.ExitCode != ipc.ExitOK {
+			return resp, err
+		}
+		if parsed.repeatUntil.matches(resp.Content) {
+			return resp, nil
+		}
+		if repeatNow().Add(interval).After(deadline) {
+			return nil, fmt.Errorf("--repeat-until %s not met within %s (%d calls)", parsed.repeatUntil.raw, maxWait, calls)
+		}
+		repeatSleep(interval)
+	}
+}
@@ -0,0 +1,103 @@
+package cli
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/lydakis/mcpx/internal/ipc"
+)
+
+func stubRepeatClock(t *testing.T) *[]time.Duration {
+	t.Helper()
+	oldSleep, oldNow := repeatSleep, repeatNow
+	t.Cleanup(func() { repeatSleep, repeatNow = oldSleep, oldNow })
+
+	now := time.Unix(0, 0)
+	var sleeps []time.Duration
+	repeatNow = func() time.Time { return now }
+	repeatSleep = func(d time.Duration) {
+		sleeps = append(sleeps, d)
+		now = now.Add(d)
+	}
+	return &sleeps
+}
+
+func TestCallToolRepeatUntilPrintsFinalResultWhenConditionMet(t *testing.T) {
+	oldOut, oldErr := rootStdout, rootStderr
+	defer func() { rootStdout, rootStderr = oldOut, oldErr }()
+	var stdout, stderr bytes.Buffer
+	rootStdout, rootStderr = &stdout, &stderr
+	stubConfirmTerminal(t, false, "")
+	sleeps := stubRepeatClock(t)
+
+	calls := 0
+	client := stubDaemonClient{sendFn: func(req *ipc.Request) (*ipc.Response, error) {
+		calls++
+		if req.Cache == nil || *req.Cache != 0 {
+			t.Fatalf("request cache = %v, want explicit no-cache", req.Cache)
+		}
+		status := "running"
+		if calls == 3 {
+			status = "done"
+		}
+		return &ipc.Response{Content: []byte(fmt.Sprintf(`{"job":{"status":%q,"steps":[%d]}}`+"\n", status, calls))}, nil
+	}}
+
+	code := callTool(client, "jobs", "get-job", []string{"--id=7", "--repeat-until", "$.job.status=done", "--interval=3s"}, "", false)
+	if code != ipc.ExitOK {
+		t.Fatalf("callTool() = %d, want %d (stderr=%q)", code, ipc.ExitOK, stderr.String())
+	}
+	if calls != 3 {
+		t.Fatalf("calls = %d, want 3", calls)
+	}
+	if got := stdout.String(); got != `{"job":{"status":"done","steps":[3]}}`+"\n" {
+		t.Fatalf("stdout = %q, want only the final result", got)
+	}
+	if len(*sleeps) != 2 || (*sleeps)[0] != 3*time.Second {
+		t.Fatalf("sleeps = %v, want two 3s waits", *sleeps)
+	}
+}
+
+func TestCallToolRepeatUntilFailsAfterMaxWait(t *testing.T) {
+	oldOut, oldErr := rootStdout, rootStderr
+	defer func() { rootStdout, rootStderr = oldOut, oldErr }()
+	var stdout, stderr bytes.Buffer
+	rootStdout, rootStderr = &stdout, &stderr
+	stubConfirmTerminal(t, false, "")
+	stubRepeatClock(t)
+
+	calls := 0
+	client := stubDaemonClient{sendFn: func(*ipc.Request) (*ipc.Response, error) {
+		calls++
+		return &ipc.Response{Content: []byte(`{"items":[{"done":false}]}`)}, nil
+	}}
+
+	code := callTool(client, "jobs", "list", []string{"--repeat-until=items[0].done=true", "--interval", "1s", "--max-wait", "3s"}, "", false)
+	if code == ipc.ExitOK {
+		t.Fatal("callTool() = 0, want non-zero on timeout")
+	}
+	if calls != 4 {
+		t.Fatalf("calls = %d, want 4", calls)
+	}
+	if stdout.Len() != 0 {
+		t.Fatalf("stdout = %q, want empty", stdout.String())
+	}
+	if !strings.Contains(stderr.String(), "not met within 3s") {
+		t.Fatalf("stderr = %q, want timeout message", stderr.String())
+	}
+}
+
+func TestParseToolCallArgsRepeatUntilFlags(t *testing.T) {
+	if _, err := parseToolCallArgs([]string{"--interval=1s"}, bytes.NewBuffer(nil), true, outputModeText); err == nil {
+		t.Fatal("parseToolCallArgs(--interval without --repeat-until) error = nil, want non-nil")
+	}
+	if _, err := parseToolCallArgs([]string{"--repeat-until=status"}, bytes.NewBuffer(nil), true, outputModeText); err == nil {
+		t.Fatal("parseToolCallArgs(--repeat-until without =) error = nil, want non-nil")
+	}
+	if _, err := parseToolCallArgs([]string{"--repeat-until=a[x]=1"}, bytes.NewBuffer(nil), true, outputModeText); err == nil {
+		t.Fatal("parseToolCallArgs(bad index) error = nil, want non-nil")
+	}
+}
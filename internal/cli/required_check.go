@@ -0,0 +1,101 @@
+package cli
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+
+	"github.com/lydakis/mcpx/internal/ipc"
+)
+
+// requiredParamStatus is one line of the --param-required-check checklist.
+type requiredParamStatus struct {
+	Name        string `json:"name"`
+	Type        string `json:"type,omitempty"`
+	Description string `json:"description,omitempty"`
+	Provided    bool   `json:"provided"`
+}
+
+// requiredParamChecklist lists the schema's top-level required parameters,
+// in the order the schema declares them, and whether args sets each one.
+func requiredParamChecklist(inputSchema, args map[string]any) []requiredParamStatus {
+	props, _ := inputSchema["properties"].(map[string]any)
+	var checklist []requiredParamStatus
+	for _, name := range toStringSlice(inputSchema["required"]) {
+		status := requiredParamStatus{Name: name}
+		if prop, ok := props[name].(map[string]any); ok {
+			status.Type = propType(prop)
+			status.Description, _ = prop["description"].(string)
+		}
+		if value, ok := args[name]; ok && value != nil {
+			status.Provided = true
+		}
+		checklist = append(checklist, status)
+	}
+	return checklist
+}
+
+// showRequiredParamCheck reports which required parameters the given flags
+// still leave unset, without calling the tool. It exits with a usage error
+// while any are missing.
+func showRequiredParamCheck(client daemonRequester, server, tool, cwd string, parsed *toolCallArgs, canonicalizeSource bool) int {
+	_, inputSchema, _, code := fetchToolSchemaPayload(client, server, tool, cwd, canonicalizeSource)
+	if code != ipc.ExitOK {
+		return code
+	}
+
+	checklist := requiredParamChecklist(inputSchema, parsed.toolArgs)
+	missing := 0
+	for _, status := range checklist {
+		if !status.Provided {
+			missing++
+		}
+	}
+
+	if parsed.output.isJSON() {
+		if checklist == nil {
+			checklist = []requiredParamStatus{}
+		}
+		data, err := json.Marshal(checklist)
+		if err != nil {
+			fmt.Fprintf(rootStderr, "mcpx: encoding required parameters: %v\n", err)
+			return ipc.ExitInternal
+		}
+		if err := writePayload(rootStdout, "required parameters", append(data, '\n')); err != nil {
+			fmt.Fprintf(rootStderr, "mcpx: %v\n", err)
+			return ipc.ExitInternal
+		}
+	} else {
+		printRequiredParamChecklist(rootStdout, checklist, missing)
+	}
+
+	if missing > 0 {
+		return ipc.ExitUsageErr
+	}
+	return ipc.ExitOK
+}
+
+func printRequiredParamChecklist(w io.Writer, checklist []requiredParamStatus, missing int) {
+	for _, status := range checklist {
+		mark := "[ ]"
+		if status.Provided {
+			mark = "[x]"
+		}
+		line := mark + " --" + status.Name
+		if status.Type != "" {
+			line += " (" + status.Type + ")"
+		}
+		if !status.Provided && status.Description != "" {
+			line += ": " + status.Description
+		}
+		fmt.Fprintln(w, line)
+	}
+	switch {
+	case len(checklist) == 0:
+		fmt.Fprintln(w, "no required parameters")
+	case missing == 0:
+		fmt.Fprintf(w, "all %d required parameters set\n", len(checklist))
+	default:
+		fmt.Fprintf(w, "%d of %d required parameters missing\n", missing, len(checklist))
+	}
+}
@@ -0,0 +1,67 @@
+package cli
+
+import (
+	"bytes"
+	"encoding/json"
+	"reflect"
+	"testing"
+
+	"github.com/lydakis/mcpx/internal/ipc"
+)
+
+func TestCallToolParamRequiredCheckListsMissingParams(t *testing.T) {
+	oldOut, oldErr := rootStdout, rootStderr
+	defer func() { rootStdout, rootStderr = oldOut, oldErr }()
+	var stdout, stderr bytes.Buffer
+	rootStdout, rootStderr = &stdout, &stderr
+
+	payload, _ := json.Marshal(map[string]any{
+		"name": "create_issue",
+		"input_schema": map[string]any{
+			"type": "object",
+			"properties": map[string]any{
+				"owner": map[string]any{"type": "string"},
+				"repo":  map[string]any{"type": "string", "description": "Repository name"},
+				"title": map[string]any{"type": "string"},
+				"body":  map[string]any{"type": "string"},
+			},
+			"required": []any{"owner", "repo", "title"},
+		},
+	})
+	client := stubDaemonClient{sendFn: func(req *ipc.Request) (*ipc.Response, error) {
+		if req.Type != "tool_schema" {
+			t.Fatalf("request type = %q, want tool_schema only", req.Type)
+		}
+		return &ipc.Response{Content: payload}, nil
+	}}
+
+	code := callTool(client, "github", "create-issue", []string{"--param-required-check", "--owner=lydakis", "--body=x"}, "", false)
+	if code != ipc.ExitUsageErr {
+		t.Fatalf("callTool() = %d, want %d (stderr=%q)", code, ipc.ExitUsageErr, stderr.String())
+	}
+	want := "[x] --owner (string)\n" +
+		"[ ] --repo (string): Repository name\n" +
+		"[ ] --title (string)\n" +
+		"2 of 3 required parameters missing\n"
+	if stdout.String() != want {
+		t.Fatalf("checklist =\n%s\nwant\n%s", stdout.String(), want)
+	}
+
+	stdout.Reset()
+	code = callTool(client, "github", "create-issue", []string{"--param-required-check", "--json", "--owner=a", "--repo=b", "--title=c"}, "", false)
+	if code != ipc.ExitOK {
+		t.Fatalf("callTool(all set) = %d, want %d (stderr=%q)", code, ipc.ExitOK, stderr.String())
+	}
+	var got []requiredParamStatus
+	if err := json.Unmarshal(stdout.Bytes(), &got); err != nil {
+		t.Fatalf("json.Unmarshal(stdout) error = %v (stdout=%q)", err, stdout.String())
+	}
+	wantJSON := []requiredParamStatus{
+		{Name: "owner", Type: "string", Provided: true},
+		{Name: "repo", Type: "string", Description: "Repository name", Provided: true},
+		{Name: "title", Type: "string", Provided: true},
+	}
+	if !reflect.DeepEqual(got, wantJSON) {
+		t.Fatalf("JSON checklist = %+v, want %+v", got, wantJSON)
+	}
+}
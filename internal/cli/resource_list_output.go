@@ -0,0 +1,192 @@
+package cli
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/lydakis/mcpx/internal/ipc"
+)
+
+type resourceListEntry struct {
+	URI         string `json:"uri"`
+	Name        string `json:"name,omitempty"`
+	Description string `json:"description,omitempty"`
+	MIMEType    string `json:"mime_type,omitempty"`
+}
+
+type promptListEntry struct {
+	Name        string                `json:"name"`
+	Description string                `json:"description,omitempty"`
+	Arguments   []promptArgumentEntry `json:"arguments,omitempty"`
+}
+
+type promptArgumentEntry struct {
+	Name        string `json:"name"`
+	Description string `json:"description,omitempty"`
+	Required    bool   `json:"required,omitempty"`
+}
+
+func printResourceListHelp(out io.Writer, server string) {
+	fmt.Fprintf(out, "Usage: mcpx %s resources [FLAGS]\n", server)
+	fmt.Fprintln(out, "")
+	fmt.Fprintln(out, "List resources exposed by the server.")
+	fmt.Fprintln(out, "")
+	fmt.Fprintln(out, "Flags:")
+	fmt.Fprintln(out, "  --verbose, -v    Show full resource descriptions")
+	fmt.Fprintln(out, "  --json           Emit resource list as JSON")
+	fmt.Fprintln(out, "  --help, -h       Show this help output")
+	fmt.Fprintln(out, "")
+	fmt.Fprintf(out, "Call a tool named \"resources\" with: mcpx %s -- resources\n", server)
+}
+
+func printPromptListHelp(out io.Writer, server string) {
+	fmt.Fprintf(out, "Usage: mcpx %s prompts [FLAGS]\n", server)
+	fmt.Fprintln(out, "")
+	fmt.Fprintln(out, "List prompts exposed by the server.")
+	fmt.Fprintln(out, "")
+	fmt.Fprintln(out, "Flags:")
+	fmt.Fprintln(out, "  --verbose, -v    Show full prompt descriptions")
+	fmt.Fprintln(out, "  --json           Emit prompt list as JSON")
+	fmt.Fprintln(out, "  --help, -h       Show this help output")
+	fmt.Fprintln(out, "")
+	fmt.Fprintf(out, "Call a tool named \"prompts\" with: mcpx %s -- prompts\n", server)
+}
+
+func listResources(client daemonRequester, server, cwd string, verbose bool, output outputMode, canonicalizeSource bool) int {
+	resp, ok, code := sendCapabilityListRequest(client, "list_resources", server, cwd, verbose, canonicalizeSource)
+	if !ok {
+		return code
+	}
+
+	var entries []resourceListEntry
+	if err := decodeCapabilityListPayload(resp.Content, "resource list", &entries); err != nil {
+		fmt.Fprintf(rootStderr, "mcpx: %v\n", err)
+		return ipc.ExitInternal
+	}
+	if entries == nil {
+		entries = make([]resourceListEntry, 0)
+	}
+
+	if output.isJSON() {
+		if err := writeJSONLine(rootStdout, entries); err != nil {
+			fmt.Fprintf(rootStderr, "mcpx: %v\n", err)
+			return ipc.ExitInternal
+		}
+		return resp.ExitCode
+	}
+	if err := writeResourceListText(rootStdout, entries); err != nil {
+		fmt.Fprintf(rootStderr, "mcpx: %v\n", err)
+		return ipc.ExitInternal
+	}
+	return resp.ExitCode
+}
+
+func listPrompts(client daemonRequester, server, cwd string, verbose bool, output outputMode, canonicalizeSource bool) int {
+	resp, ok, code := sendCapabilityListRequest(client, "list_prompts", server, cwd, verbose, canonicalizeSource)
+	if !ok {
+		return code
+	}
+
+	var entries []promptListEntry
+	if err := decodeCapabilityListPayload(resp.Content, "prompt list", &entries); err != nil {
+		fmt.Fprintf(rootStderr, "mcpx: %v\n", err)
+		return ipc.ExitInternal
+	}
+	if entries == nil {
+		entries = make([]promptListEntry, 0)
+	}
+
+	if output.isJSON() {
+		if err := writeJSONLine(rootStdout, entries); err != nil {
+			fmt.Fprintf(rootStderr, "mcpx: %v\n", err)
+			return ipc.ExitInternal
+		}
+		return resp.ExitCode
+	}
+	if err := writePromptListText(rootStdout, entries); err != nil {
+		fmt.Fprintf(rootStderr, "mcpx: %v\n", err)
+		return ipc.ExitInternal
+	}
+	return resp.ExitCode
+}
+
+func sendCapabilityListRequest(client daemonRequester, reqType, server, cwd string, verbose, canonicalizeSource bool) (*ipc.Response, bool, int) {
+	resp, err := sendServerRequestWithEphemeralFallback(client, &ipc.Request{
+		Type:    reqType,
+		Server:  server,
+		Verbose: verbose,
+		CWD:     cwd,
+	}, canonicalizeSource)
+	if err != nil {
+		fmt.Fprintf(rootStderr, "mcpx: %v\n", err)
+		return nil, false, ipc.ExitInternal
+	}
+	if resp.Stderr != "" {
+		fmt.Fprintln(rootStderr, resp.Stderr)
+	}
+	if resp.ExitCode != ipc.ExitOK {
+		return nil, false, resp.ExitCode
+	}
+	return resp, true, ipc.ExitOK
+}
+
+func decodeCapabilityListPayload(raw []byte, label string, dst any) error {
+	trimmed := bytes.TrimSpace(raw)
+	if len(trimmed) == 0 || trimmed[0] != '[' {
+		return fmt.Errorf("invalid daemon response for %s: expected JSON array payload", label)
+	}
+	if err := json.Unmarshal(trimmed, dst); err != nil {
+		return fmt.Errorf("invalid daemon response for %s: %w", label, err)
+	}
+	return nil
+}
+
+func writeResourceListText(w io.Writer, entries []resourceListEntry) error {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	for _, entry := range entries {
+		uri := strings.TrimSpace(entry.URI)
+		if uri == "" {
+			continue
+		}
+		name := strings.TrimSpace(entry.Name)
+		if name == "" {
+			name = "-"
+		}
+		line := uri + "\t" + name
+		if desc := strings.TrimSpace(entry.Description); desc != "" {
+			line += "\t" + desc
+		}
+		if _, err := fmt.Fprintln(tw, line); err != nil {
+			return fmt.Errorf("writing resource list output: %w", err)
+		}
+	}
+	if err := tw.Flush(); err != nil {
+		return fmt.Errorf("writing resource list output: %w", err)
+	}
+	return nil
+}
+
+func writePromptListText(w io.Writer, entries []promptListEntry) error {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	for _, entry := range entries {
+		name := strings.TrimSpace(entry.Name)
+		if name == "" {
+			continue
+		}
+		line := name
+		if desc := strings.TrimSpace(entry.Description); desc != "" {
+			line += "\t" + desc
+		}
+		if _, err := fmt.Fprintln(tw, line); err != nil {
+			return fmt.Errorf("writing prompt list output: %w", err)
+		}
+	}
+	if err := tw.Flush(); err != nil {
+		return fmt.Errorf("writing prompt list output: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,105 @@
+package cli
+
+import (
+	"bytes"
+	"encoding/json"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/lydakis/mcpx/internal/ipc"
+)
+
+func TestWriteResourceListTextRendersURINameAndDescription(t *testing.T) {
+	entries := []resourceListEntry{
+		{URI: "file:///a.md", Name: "a", Description: "First doc"},
+		{URI: "file:///b.md"},
+	}
+
+	var out bytes.Buffer
+	if err := writeResourceListText(&out, entries); err != nil {
+		t.Fatalf("writeResourceListText() error = %v", err)
+	}
+
+	lines := strings.Split(strings.TrimSpace(out.String()), "\n")
+	if len(lines) != 2 {
+		t.Fatalf("lines = %d, want 2 (output=%q)", len(lines), out.String())
+	}
+	if fields := strings.Fields(lines[0]); len(fields) != 4 || fields[0] != "file:///a.md" || fields[1] != "a" || strings.Join(fields[2:], " ") != "First doc" {
+		t.Fatalf("first line = %q, want uri, name, and description columns", lines[0])
+	}
+	if fields := strings.Fields(lines[1]); len(fields) != 2 || fields[0] != "file:///b.md" || fields[1] != "-" {
+		t.Fatalf("second line = %q, want uri and placeholder name", lines[1])
+	}
+}
+
+func TestWritePromptListTextRendersNameAndDescription(t *testing.T) {
+	entries := []promptListEntry{
+		{Name: "greet"},
+		{Name: "summarize", Description: "Summarize text"},
+	}
+
+	var out bytes.Buffer
+	if err := writePromptListText(&out, entries); err != nil {
+		t.Fatalf("writePromptListText() error = %v", err)
+	}
+
+	lines := strings.Split(strings.TrimSpace(out.String()), "\n")
+	if len(lines) != 2 || strings.TrimSpace(lines[0]) != "greet" {
+		t.Fatalf("output = %q, want greet then summarize", out.String())
+	}
+	if fields := strings.Fields(lines[1]); len(fields) < 2 || fields[0] != "summarize" || strings.Join(fields[1:], " ") != "Summarize text" {
+		t.Fatalf("second line = %q, want name and description columns", lines[1])
+	}
+}
+
+func TestListPromptsJSONRequestsDaemonAndEmitsEntries(t *testing.T) {
+	oldOut := rootStdout
+	defer func() { rootStdout = oldOut }()
+	var out bytes.Buffer
+	rootStdout = &out
+
+	var gotReq *ipc.Request
+	client := stubDaemonClient{
+		sendFn: func(req *ipc.Request) (*ipc.Response, error) {
+			gotReq = req
+			return &ipc.Response{Content: []byte(`[{"name":"summarize","arguments":[{"name":"text","required":true}]}]` + "\n")}, nil
+		},
+	}
+
+	if code := listPrompts(client, "docs", "/tmp", true, outputModeJSON, false); code != ipc.ExitOK {
+		t.Fatalf("listPrompts() = %d, want %d", code, ipc.ExitOK)
+	}
+	if gotReq == nil || gotReq.Type != "list_prompts" || gotReq.Server != "docs" || !gotReq.Verbose {
+		t.Fatalf("request = %#v, want verbose list_prompts for docs", gotReq)
+	}
+
+	var got []promptListEntry
+	if err := json.Unmarshal(out.Bytes(), &got); err != nil {
+		t.Fatalf("json.Unmarshal(output): %v", err)
+	}
+	want := []promptListEntry{{Name: "summarize", Arguments: []promptArgumentEntry{{Name: "text", Required: true}}}}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("prompts = %#v, want %#v", got, want)
+	}
+}
+
+func TestListResourcesRejectsNonArrayPayload(t *testing.T) {
+	oldErr := rootStderr
+	defer func() { rootStderr = oldErr }()
+	var errOut bytes.Buffer
+	rootStderr = &errOut
+
+	client := stubDaemonClient{
+		sendFn: func(*ipc.Request) (*ipc.Response, error) {
+			return &ipc.Response{Content: []byte("file:///a.md\n")}, nil
+		},
+	}
+
+	if code := listResources(client, "docs", "/tmp", false, outputModeText, false); code != ipc.ExitInternal {
+		t.Fatalf("listResources() = %d, want %d", code, ipc.ExitInternal)
+	}
+	if !strings.Contains(errOut.String(), "invalid daemon response for resource list") {
+		t.Fatalf("stderr = %q, want decode error", errOut.String())
+	}
+}
@@ -11,6 +11,7 @@ import (
 	"sort"
 	"strings"
 	"text/tabwriter"
+	"time"
 
 	"github.com/lydakis/mcpx/internal/bootstrap"
 	"github.com/lydakis/mcpx/internal/config"
@@ -25,7 +26,7 @@ type daemonRequester interface {
 var (
 	spawnOrConnectFn = daemon.SpawnOrConnect
 	newDaemonClient  = func(socketPath, nonce string) daemonRequester {
-		return ipc.NewClient(socketPath, nonce)
+		return withEnvServers(ipc.NewClient(socketPath, nonce))
 	}
 	resolveSourceFn = bootstrap.Resolve
 	checkPrereqsFn  = bootstrap.CheckPrerequisites
@@ -37,6 +38,9 @@ func Run(args []string) int {
 	if handled, code := handleRootFlags(args); handled {
 		return code
 	}
+	if handled, code := maybeHandleValidateFlag(args, rootStdout, rootStderr); handled {
+		return code
+	}
 
 	cfg, err := config.Load()
 	if err != nil {
@@ -60,19 +64,44 @@ func Run(args []string) int {
 		return code
 	}
 
+	if handled, code := maybeHandleRemoveCommand(args, cfg, rootStdout, rootStderr); handled {
+		return code
+	}
+
+	if handled, code := maybeHandleImportCommand(args, cfg, rootStdout, rootStderr); handled {
+		return code
+	}
 	if handled, code := maybeHandleShimCommand(args, cfg, rootStdout, rootStderr); handled {
 		return code
 	}
 
+	if handled, code := maybeHandleConfigCommand(args, cfg, rootStdout, rootStderr); handled {
+		return code
+	}
+	if handled, code := maybeHandleDaemonCommand(args, cfg, rootStdout, rootStderr); handled {
+		return code
+	}
+	if handled, code := maybeHandleCacheCommand(args, cfg, rootStdout, rootStderr); handled {
+		return code
+	}
+
 	if verr := config.Validate(cfg); verr != nil {
 		fmt.Fprintf(rootStderr, "mcpx: invalid config: %v\n", verr)
 		return ipc.ExitUsageErr
 	}
 
-	inv, err := parseInvocation(args, cfg)
-	if err != nil {
-		fmt.Fprintf(rootStderr, "mcpx: %v\n", err)
-		return ipc.ExitUsageErr
+	var inv invocation
+	if recipeInv, handled, code := maybeHandleRunCommand(args, cfg, rootStdout, rootStderr); handled {
+		if recipeInv == nil {
+			return code
+		}
+		inv = *recipeInv
+	} else {
+		inv, err = parseInvocation(args, cfg)
+		if err != nil {
+			fmt.Fprintf(rootStderr, "mcpx: %v\n", err)
+			return ipc.ExitUsageErr
+		}
 	}
 
 	if inv.kind == invocationKindRootList {
@@ -82,7 +111,7 @@ func Run(args []string) int {
 			return ipc.ExitInternal
 		}
 		client := newDaemonClient(ipc.SocketPath(), nonce)
-		return listServersFromDaemon(client, callerWorkingDirectory(), inv.rootList.output, inv.rootList.verbose)
+		return listServersFromDaemonWithArgs(client, callerWorkingDirectory(), inv.rootList)
 	}
 
 	server := inv.server
@@ -138,6 +167,21 @@ func Run(args []string) int {
 		return ipc.ExitOK
 	}
 
+	if (cmd.resources || cmd.prompts) && cmd.listOpts.help {
+		if cmd.resources {
+			printResourceListHelp(rootStdout, server)
+		} else {
+			printPromptListHelp(rootStdout, server)
+		}
+		return ipc.ExitOK
+	}
+
+	if cmd.tool != "" {
+		if handled, code := maybePrintToolCurl(cfg, server, cmd.tool, cmd.toolArgs); handled {
+			return code
+		}
+	}
+
 	// Connect to daemon
 	nonce, err := spawnOrConnectFn()
 	if err != nil {
@@ -148,10 +192,19 @@ func Run(args []string) int {
 	cwd := callerWorkingDirectory()
 
 	if cmd.list {
-		return listTools(client, server, cwd, cmd.listOpts.verbose, cmd.listOpts.output, canonicalizeSource)
+		return listToolsWithArgs(client, server, cwd, cmd.listOpts, canonicalizeSource)
+	}
+	if cmd.resources {
+		return listResources(client, server, cwd, cmd.listOpts.verbose, cmd.listOpts.output, canonicalizeSource)
+	}
+	if cmd.prompts {
+		return listPrompts(client, server, cwd, cmd.listOpts.verbose, cmd.listOpts.output, canonicalizeSource)
+	}
+	if cmd.prompt != "" {
+		return getPrompt(cfg, client, server, cmd.prompt, cmd.toolArgs, cwd, canonicalizeSource)
 	}
 
-	return callTool(client, server, cmd.tool, cmd.toolArgs, cwd, canonicalizeSource)
+	return callToolWithBaseArgs(cfg, client, server, cmd.tool, cmd.toolArgs, cmd.baseArgs, cwd, canonicalizeSource)
 }
 
 func maybeHandleCompletionCommand(args []string, cfg *config.Config, stdout, stderr io.Writer) (bool, int) {
@@ -173,7 +226,7 @@ func maybeHandleCompletionCommand(args []string, cfg *config.Config, stdout, std
 				return false, 0
 			}
 		}
-		return true, runInternalCompletion(args[1:], stdout, stderr)
+		return true, runInternalCompletion(cfg, args[1:], stdout, stderr)
 	default:
 		return false, 0
 	}
@@ -182,6 +235,15 @@ func maybeHandleCompletionCommand(args []string, cfg *config.Config, stdout, std
 type rootServerListArgs struct {
 	output  outputMode
 	verbose bool
+	// changedSince, when set, keeps only servers whose origin config file
+	// was modified after it.
+	changedSince *time.Time
+	// graph prints servers grouped by origin as a "tree" or "dot" graph
+	// instead of the plain list.
+	graph string
+	// unused, when set, keeps only servers the daemon has not called within
+	// that window.
+	unused *time.Duration
 }
 
 type invocationKind int
@@ -207,7 +269,8 @@ func parseInvocation(args []string, cfg *config.Config) (invocation, error) {
 		}, nil
 	}
 
-	rootList, isRootList, err := parseRootServerListArgs(args)
+	defaultOutput := outputModeFromConfig(cfg)
+	rootList, isRootList, err := parseRootServerListArgs(args, defaultOutput)
 	if err != nil {
 		return invocation{}, err
 	}
@@ -225,7 +288,7 @@ func parseInvocation(args []string, cfg *config.Config) (invocation, error) {
 		}, nil
 	}
 
-	serverCmd, err := parseServerCommand(args[1:])
+	serverCmd, err := parseServerCommand(args[1:], defaultOutput)
 	if err != nil {
 		return invocation{}, err
 	}
@@ -243,49 +306,109 @@ func parseConfiguredServerInvocation(args []string, cfg *config.Config) (serverC
 	if _, exists := cfg.Servers[args[0]]; !exists {
 		return serverCommand{}, false
 	}
-	serverCmd, err := parseServerCommand(args[1:])
+	serverCmd, err := parseServerCommand(args[1:], outputModeFromConfig(cfg))
 	if err != nil {
 		return serverCommand{}, false
 	}
 	return serverCmd, true
 }
 
-func parseRootServerListArgs(args []string) (rootServerListArgs, bool, error) {
-	parsed := rootServerListArgs{output: outputModeText}
+func parseRootServerListArgs(args []string, defaultOutput outputMode) (rootServerListArgs, bool, error) {
+	parsed := rootServerListArgs{output: defaultOutput}
 	if len(args) == 0 {
 		return parsed, true, nil
 	}
+	outputSet, structuredSet := false, false
 
-	for _, arg := range args {
+	for i := 0; i < len(args); i++ {
+		arg := args[i]
 		if !isRootServerListFlag(arg) {
 			// Preserve command contract: if any token is not a root-list flag,
 			// treat argv[0] as a server name instead of claiming root-list mode.
 			return rootServerListArgs{}, false, nil
 		}
-		switch arg {
-		case "--json":
-			parsed.output = outputModeJSON
-		case "-v", "--verbose":
+		switch {
+		case arg == "--json" || arg == "--yaml":
+			mode := outputModeJSON
+			if arg == "--yaml" {
+				mode = outputModeYAML
+			}
+			if err := setStructuredOutput(&parsed.output, &structuredSet, mode); err != nil {
+				return rootServerListArgs{}, true, err
+			}
+			outputSet = true
+		case arg == "--text":
+			parsed.output = outputModeText
+			outputSet = true
+		case arg == "-v" || arg == "--verbose":
 			parsed.verbose = true
+		case arg == "--changed-since" || strings.HasPrefix(arg, "--changed-since="):
+			raw, hasValue := strings.CutPrefix(arg, "--changed-since=")
+			if !hasValue {
+				if i+1 >= len(args) {
+					return rootServerListArgs{}, true, fmt.Errorf("missing value for --changed-since")
+				}
+				i++
+				raw = args[i]
+			}
+			since, err := parseChangedSince(raw, lastErrorNow())
+			if err != nil {
+				return rootServerListArgs{}, true, err
+			}
+			parsed.changedSince = &since
+		case arg == "--unused" || strings.HasPrefix(arg, "--unused="):
+			raw, hasValue := strings.CutPrefix(arg, "--unused=")
+			if !hasValue {
+				if i+1 >= len(args) {
+					return rootServerListArgs{}, true, fmt.Errorf("missing value for --unused")
+				}
+				i++
+				raw = args[i]
+			}
+			window, err := time.ParseDuration(strings.TrimSpace(raw))
+			if err != nil || window <= 0 {
+				return rootServerListArgs{}, true, fmt.Errorf("invalid --unused %q: expected a positive duration like 24h", raw)
+			}
+			parsed.unused = &window
+		case arg == "--graph":
+			parsed.graph = serverGraphTree
+		case strings.HasPrefix(arg, "--graph="):
+			format, err := parseServerGraphFormat(strings.TrimPrefix(arg, "--graph="))
+			if err != nil {
+				return rootServerListArgs{}, true, err
+			}
+			parsed.graph = format
 		}
 	}
+	if parsed.graph != "" && !outputSet {
+		parsed.output = outputModeText
+	}
+	if parsed.graph != "" && parsed.output.isStructured() {
+		return rootServerListArgs{}, true, fmt.Errorf("--graph cannot be combined with %s", parsed.output.flag())
+	}
 
 	return parsed, true, nil
 }
 
 func isRootServerListFlag(arg string) bool {
 	switch arg {
-	case "-v", "--verbose", "--json":
+	case "-v", "--verbose", "--json", "--yaml", "--text", "--changed-since", "--graph", "--unused":
 		return true
 	default:
-		return false
+		return strings.HasPrefix(arg, "--changed-since=") || strings.HasPrefix(arg, "--graph=") || strings.HasPrefix(arg, "--unused=")
 	}
 }
 
 func listServersFromDaemon(client daemonRequester, cwd string, output outputMode, verbose bool) int {
+	return listServersFromDaemonWithArgs(client, cwd, rootServerListArgs{output: output, verbose: verbose})
+}
+
+func listServersFromDaemonWithArgs(client daemonRequester, cwd string, args rootServerListArgs) int {
+	output, verbose := args.output, args.verbose
 	resp, err := client.Send(&ipc.Request{
-		Type: "list_servers",
-		CWD:  cwd,
+		Type:   "list_servers",
+		CWD:    cwd,
+		Unused: args.unused,
 	})
 	if err != nil {
 		fmt.Fprintf(rootStderr, "mcpx: %v\n", err)
@@ -299,27 +422,42 @@ func listServersFromDaemon(client daemonRequester, cwd string, output outputMode
 	}
 
 	entries := decodeServerListEntries(resp.Content)
+	if args.changedSince != nil {
+		entries = filterServersChangedSince(entries, *args.changedSince)
+	}
 
-	if output.isJSON() {
-		if !verbose {
+	if args.graph != "" {
+		if err := writeServerGraph(rootStdout, entries, args.graph); err != nil {
+			fmt.Fprintf(rootStderr, "mcpx: writing server graph: %v\n", err)
+			return ipc.ExitInternal
+		}
+		return ipc.ExitOK
+	}
+
+	filtered := args.changedSince != nil || args.unused != nil
+	if output.isStructured() {
+		if !verbose && !filtered {
 			names := make([]string, 0, len(entries))
 			for _, entry := range entries {
 				names = append(names, entry.Name)
 			}
-			if err := writeJSONLine(rootStdout, names); err != nil {
+			if err := writeStructuredLine(rootStdout, output, names); err != nil {
 				fmt.Fprintf(rootStderr, "mcpx: %v\n", err)
 				return ipc.ExitInternal
 			}
 			return ipc.ExitOK
 		}
 
-		if err := writeJSONLine(rootStdout, entries); err != nil {
+		if err := writeStructuredLine(rootStdout, output, entries); err != nil {
 			fmt.Fprintf(rootStderr, "mcpx: %v\n", err)
 			return ipc.ExitInternal
 		}
 		return ipc.ExitOK
 	}
 
+	if len(entries) == 0 && filtered {
+		return ipc.ExitOK
+	}
 	if len(entries) == 0 {
 		fmt.Fprintln(rootStdout, "No MCP servers configured.")
 		fmt.Fprintf(rootStdout, "Create a config file at %s\n", config.ExampleConfigPath())
@@ -335,6 +473,15 @@ func listServersFromDaemon(client daemonRequester, cwd string, output outputMode
 		return resp.ExitCode
 	}
 
+	showState := false
+	for _, entry := range entries {
+		if entry.State != "" {
+			showState = true
+			break
+		}
+	}
+
+	now := lastErrorNow()
 	tw := tabwriter.NewWriter(rootStdout, 0, 0, 2, ' ', 0)
 	for _, entry := range entries {
 		origin := config.NormalizeServerOrigin(entry.Origin)
@@ -342,7 +489,24 @@ func listServersFromDaemon(client daemonRequester, cwd string, output outputMode
 		if source == "" {
 			source = "-"
 		}
-		if _, err := fmt.Fprintf(tw, "%s\t%s\n", entry.Name, source); err != nil {
+		line := entry.Name + "\t" + source
+		if showState {
+			state := entry.State
+			if state == "" {
+				state = "-"
+			}
+			line += "\t" + state
+		}
+		if entry.MTime != "" {
+			line += "\t" + entry.MTime
+		}
+		if lastCall := formatServerLastCall(entry, now); lastCall != "" {
+			line += "\t" + lastCall
+		}
+		if lastErr := formatServerLastError(entry, now); lastErr != "" {
+			line += "\t" + lastErr
+		}
+		if _, err := fmt.Fprintln(tw, line); err != nil {
 			fmt.Fprintf(rootStderr, "mcpx: writing server list output: %v\n", err)
 			return ipc.ExitInternal
 		}
@@ -358,18 +522,29 @@ type toolListArgs struct {
 	verbose bool
 	help    bool
 	output  outputMode
+	shape   toolListShape
+	// byUsage sorts tools most-called first, by the daemon's call counts.
+	byUsage bool
+	// full wraps the JSON tool list with the server's name, transport, and
+	// origin (--json-full).
+	full bool
 }
 
 type serverCommand struct {
-	list     bool
-	listOpts toolListArgs
-	tool     string
-	toolArgs []string
+	list      bool
+	resources bool
+	prompts   bool
+	prompt    string
+	listOpts  toolListArgs
+	tool      string
+	toolArgs  []string
+	// baseArgs are a recipe's saved arguments; toolArgs override them.
+	baseArgs map[string]any
 }
 
-func parseServerCommand(args []string) (serverCommand, error) {
+func parseServerCommand(args []string, defaultOutput outputMode) (serverCommand, error) {
 	if len(args) == 0 {
-		return serverCommand{list: true}, nil
+		return serverCommand{list: true, listOpts: toolListArgs{output: defaultOutput}}, nil
 	}
 
 	// Force tool mode for dash-prefixed tool names:
@@ -384,8 +559,28 @@ func parseServerCommand(args []string) (serverCommand, error) {
 		}, nil
 	}
 
+	// Reserved capability listings. Extra tool-style args fall through to a
+	// normal tool call so servers exposing tools with these names still work.
+	switch args[0] {
+	case "resources", "prompts":
+		if opts, err := parseToolListArgs(args[1:], defaultOutput); err == nil && opts.shape == toolListShapeDefault && !opts.byUsage && !opts.full {
+			return serverCommand{
+				resources: args[0] == "resources",
+				prompts:   args[0] == "prompts",
+				listOpts:  opts,
+			}, nil
+		}
+	case "prompt":
+		if len(args) >= 2 && looksLikePromptName(args[1]) {
+			return serverCommand{
+				prompt:   args[1],
+				toolArgs: args[2:],
+			}, nil
+		}
+	}
+
 	if strings.HasPrefix(args[0], "-") {
-		opts, err := parseToolListArgs(args)
+		opts, err := parseToolListArgs(args, defaultOutput)
 		if err == nil {
 			return serverCommand{
 				list:     true,
@@ -403,28 +598,60 @@ func parseServerCommand(args []string) (serverCommand, error) {
 	}, nil
 }
 
-func parseToolListArgs(args []string) (toolListArgs, error) {
+func parseToolListArgs(args []string, defaultOutput outputMode) (toolListArgs, error) {
 	parsed := toolListArgs{
-		output: outputModeText,
+		output: defaultOutput,
 	}
+	outputSet, structuredSet := false, false
 	for _, arg := range args {
 		switch arg {
 		case "-v", "--verbose":
 			parsed.verbose = true
 		case "-h", "--help":
 			parsed.help = true
-		case "--json":
-			parsed.output = outputModeJSON
+		case "--json", "--yaml", "--json-full":
+			mode := outputModeJSON
+			if arg == "--yaml" {
+				mode = outputModeYAML
+			}
+			if err := setStructuredOutput(&parsed.output, &structuredSet, mode); err != nil {
+				return toolListArgs{}, err
+			}
+			parsed.full = parsed.full || arg == "--json-full"
+			outputSet = true
+		case "--text":
+			parsed.output = outputModeText
+			outputSet = true
+		case "--names-only", "--descriptions-only":
+			shape := toolListShapeNames
+			if arg == "--descriptions-only" {
+				shape = toolListShapeDescriptions
+			}
+			if parsed.shape != toolListShapeDefault && parsed.shape != shape {
+				return toolListArgs{}, fmt.Errorf("--names-only and --descriptions-only cannot be combined")
+			}
+			parsed.shape = shape
+		case "--by-usage":
+			parsed.byUsage = true
 		default:
 			return toolListArgs{}, fmt.Errorf("unsupported flag for tool listing: %s", arg)
 		}
 	}
+	if parsed.shape != toolListShapeDefault && !outputSet {
+		parsed.output = outputModeText
+	}
+	if parsed.shape != toolListShapeDefault && parsed.output.isStructured() {
+		return toolListArgs{}, fmt.Errorf("--names-only and --descriptions-only cannot be combined with %s", parsed.output.flag())
+	}
+	if parsed.full && !parsed.output.isJSON() {
+		return toolListArgs{}, fmt.Errorf("--json-full cannot be combined with %s", parsed.output.flag())
+	}
 	return parsed, nil
 }
 
 func isToolListFlag(arg string) bool {
 	switch arg {
-	case "-v", "--verbose", "-h", "--help", "--json":
+	case "-v", "--verbose", "-h", "--help", "--json", "--yaml", "--text", "--names-only", "--descriptions-only", "--by-usage", "--json-full":
 		return true
 	default:
 		return false
@@ -439,10 +666,27 @@ func printToolListHelp(out io.Writer, server string) {
 	fmt.Fprintln(out, "Flags:")
 	fmt.Fprintln(out, "  --verbose, -v    Show full tool descriptions")
 	fmt.Fprintln(out, "  --json           Emit mcpx list output as JSON")
+	fmt.Fprintln(out, "  --json-full      Emit {server, tools} JSON with the server's name, transport, and origin")
+	fmt.Fprintln(out, "  --yaml           Emit mcpx list output as YAML")
+	fmt.Fprintln(out, "  --text           Emit text output even when default_output is json")
+	fmt.Fprintln(out, "  --names-only     Print only tool names, one per line")
+	fmt.Fprintln(out, "  --descriptions-only")
+	fmt.Fprintln(out, "                   Print each tool as `name: description`")
+	fmt.Fprintln(out, "  --by-usage       Sort most-called tools first (counts since the daemon started)")
 	fmt.Fprintln(out, "  --help, -h       Show this help output")
+	fmt.Fprintln(out, "")
+	fmt.Fprintln(out, "Related:")
+	fmt.Fprintf(out, "  mcpx %s resources    List resources exposed by the server\n", server)
+	fmt.Fprintf(out, "  mcpx %s prompts      List prompts exposed by the server\n", server)
+	fmt.Fprintf(out, "  mcpx %s prompt <name> [ARGS]  Render a prompt\n", server)
 }
 
-func listTools(client daemonRequester, server, cwd string, verbose bool, output outputMode, canonicalizeSource bool) int {
+func listTools(client daemonRequester, server, cwd string, verbose bool, output outputMode, shape toolListShape, canonicalizeSource bool) int {
+	return listToolsWithArgs(client, server, cwd, toolListArgs{verbose: verbose, output: output, shape: shape}, canonicalizeSource)
+}
+
+func listToolsWithArgs(client daemonRequester, server, cwd string, args toolListArgs, canonicalizeSource bool) int {
+	verbose, output, shape := args.verbose, args.output, args.shape
 	resp, err := sendServerRequestWithEphemeralFallback(client, &ipc.Request{
 		Type:    "list_tools",
 		Server:  server,
@@ -465,16 +709,31 @@ func listTools(client daemonRequester, server, cwd string, verbose bool, output
 		fmt.Fprintf(rootStderr, "mcpx: %v\n", err)
 		return ipc.ExitInternal
 	}
+	if args.byUsage {
+		sortToolListByUsage(entries)
+	} else {
+		for i := range entries {
+			entries[i].Uses = 0
+		}
+	}
 
-	if output.isJSON() {
-		if err := writeJSONLine(rootStdout, entries); err != nil {
+	if args.full {
+		payload := toolListFullPayload{Server: fetchToolListServer(client, server, cwd), Tools: entries}
+		if err := writeJSONLine(rootStdout, payload); err != nil {
+			fmt.Fprintf(rootStderr, "mcpx: %v\n", err)
+			return ipc.ExitInternal
+		}
+		return resp.ExitCode
+	}
+	if output.isStructured() {
+		if err := writeStructuredLine(rootStdout, output, entries); err != nil {
 			fmt.Fprintf(rootStderr, "mcpx: %v\n", err)
 			return ipc.ExitInternal
 		}
 		return resp.ExitCode
 	}
 
-	if err := writeToolListText(rootStdout, entries); err != nil {
+	if err := writeToolListShaped(rootStdout, entries, shape); err != nil {
 		fmt.Fprintf(rootStderr, "mcpx: %v\n", err)
 		return ipc.ExitInternal
 	}
@@ -509,6 +768,21 @@ func decodeServerListPayload(payload []byte) []string {
 type serverListEntry struct {
 	Name   string              `json:"name"`
 	Origin config.ServerOrigin `json:"origin"`
+	// State is the daemon's last-known connection state (connected, idle,
+	// never, failed). Older daemons omit it.
+	State string `json:"state,omitempty"`
+	// Transport is "stdio" or "http". Older daemons omit it.
+	Transport string `json:"transport,omitempty"`
+	// LastError and LastErrorAt describe the most recent connect or request
+	// failure (secrets redacted by the daemon).
+	LastError   string `json:"last_error,omitempty"`
+	LastErrorAt string `json:"last_error_at,omitempty"`
+	// MTime is the origin file's modification time, set only when listing
+	// with --changed-since.
+	MTime string `json:"mtime,omitempty"`
+	// LastCallAt is when the daemon last called one of the server's tools.
+	// Older daemons omit it.
+	LastCallAt string `json:"last_call_at,omitempty"`
 }
 
 func decodeServerListEntries(payload []byte) []serverListEntry {
@@ -530,8 +804,13 @@ func decodeServerListEntries(payload []byte) []serverListEntry {
 			}
 			seen[name] = struct{}{}
 			out = append(out, serverListEntry{
-				Name:   name,
-				Origin: config.NormalizeServerOrigin(entry.Origin),
+				Name:        name,
+				Origin:      config.NormalizeServerOrigin(entry.Origin),
+				State:       strings.TrimSpace(entry.State),
+				Transport:   strings.TrimSpace(entry.Transport),
+				LastError:   strings.TrimSpace(entry.LastError),
+				LastErrorAt: strings.TrimSpace(entry.LastErrorAt),
+				LastCallAt:  strings.TrimSpace(entry.LastCallAt),
 			})
 		}
 		sort.Slice(out, func(i, j int) bool {
@@ -639,6 +918,13 @@ func showHelp(client daemonRequester, server, tool, cwd string, output outputMod
 		}
 		return resp.ExitCode
 	}
+	if output.isYAML() {
+		if err := writeYAMLFromJSON(rootStdout, resp.Content); err != nil {
+			fmt.Fprintf(rootStderr, "mcpx: %v\n", err)
+			return ipc.ExitInternal
+		}
+		return resp.ExitCode
+	}
 
 	toolName, desc, inputSchema, outputSchema := parseToolHelpPayload(resp.Content)
 	if inputSchema == nil {
@@ -650,19 +936,76 @@ func showHelp(client daemonRequester, server, tool, cwd string, output outputMod
 	}
 	toolName = resolvedToolHelpName(tool, toolName)
 
-	printToolHelp(rootStdout, server, toolName, desc, inputSchema, outputSchema)
+	printToolHelp(rootStdout, server, toolName, desc, inputSchema, outputSchema, parseToolAnnotations(resp.Content))
 	return resp.ExitCode
 }
 
 func callTool(client daemonRequester, server, tool string, rawArgs []string, cwd string, canonicalizeSource bool) int {
-	parsed, err := parseToolCallArgs(rawArgs, os.Stdin, stdinIsTTY(os.Stdin))
+	return callToolWithBaseArgs(nil, client, server, tool, rawArgs, nil, cwd, canonicalizeSource)
+}
+
+// callToolWithBaseArgs calls tool with base arguments (a replayed recipe's)
+// under those parsed from rawArgs. cfg supplies default_output and keys the
+// schema cache; nil means text output and no schema cache.
+func callToolWithBaseArgs(cfg *config.Config, client daemonRequester, server, tool string, rawArgs []string, base map[string]any, cwd string, canonicalizeSource bool) int {
+	parsed, err := parseToolCallArgs(rawArgs, os.Stdin, stdinIsTTY(os.Stdin), outputModeFromConfig(cfg))
 	if err != nil {
 		fmt.Fprintf(rootStderr, "mcpx: %v\n", err)
 		return ipc.ExitUsageErr
 	}
+	code := callParsedTool(cfg, client, server, tool, parsed, base, cwd, canonicalizeSource)
+	if parsed.ignoreExit {
+		// Output and errors are already written; only the code is dropped.
+		return ipc.ExitOK
+	}
+	return code
+}
+
+func callParsedTool(cfg *config.Config, client daemonRequester, server, tool string, parsed *toolCallArgs, base map[string]any, cwd string, canonicalizeSource bool) int {
+	var err error
+	if len(base) > 0 {
+		merged := make(map[string]any, len(base)+len(parsed.toolArgs))
+		for key, value := range base {
+			merged[key] = value
+		}
+		for key, value := range parsed.toolArgs {
+			merged[key] = value
+		}
+		parsed.toolArgs = merged
+	}
+	client = withSchemaCache(client, cfg, parsed.noSchemaCache || parsed.fresh)
 	if parsed.help {
 		return showHelp(client, server, tool, cwd, parsed.output, canonicalizeSource)
 	}
+	if parsed.schemaDiff != "" {
+		return showSchemaDiff(client, server, parsed.schemaDiff, tool, cwd, parsed.output, canonicalizeSource)
+	}
+	if parsed.sampleOutput {
+		return showOutputSample(client, server, tool, cwd, canonicalizeSource)
+	}
+	if parsed.schemaMarkdown {
+		return showSchemaMarkdown(client, server, tool, cwd, canonicalizeSource)
+	}
+	if parsed.examples {
+		return showToolExamples(client, server, tool, cwd, parsed.output, canonicalizeSource)
+	}
+	if parsed.requiredCheck {
+		return showRequiredParamCheck(client, server, tool, cwd, parsed, canonicalizeSource)
+	}
+	if len(parsed.paramDefaults) > 0 {
+		return runSaveParamDefaults(server, tool, parsed)
+	}
+	if parsed.printCurl {
+		// Run handles --print-curl from config before reaching the daemon.
+		fmt.Fprintf(rootStderr, "mcpx: --print-curl requires a server defined in config; %q is not\n", server)
+		return ipc.ExitUsageErr
+	}
+
+	if len(parsed.paramFiles) > 0 {
+		if code := validateParamFileJSON(client, server, tool, cwd, parsed, canonicalizeSource); code != ipc.ExitOK {
+			return code
+		}
+	}
 
 	argsJSON, err := json.Marshal(parsed.toolArgs)
 	if err != nil {
@@ -671,47 +1014,152 @@ func callTool(client daemonRequester, server, tool string, rawArgs []string, cwd
 		}
 		return ipc.ExitUsageErr
 	}
+	if parsed.saveAs != "" {
+		if code := runSaveRecipe(server, tool, parsed); code != ipc.ExitOK {
+			return code
+		}
+	}
+	if parsed.dryRun {
+		return printDryRun(client, server, tool, argsJSON, cwd, parsed, canonicalizeSource)
+	}
+	if len(parsed.promptParams) > 0 {
+		// Prompted values are kept out of --save-as recipes and --dry-run.
+		if code := promptToolParams(parsed); code != ipc.ExitOK {
+			return code
+		}
+		argsJSON, _ = json.Marshal(parsed.toolArgs)
+	}
+	if ok, code := confirmToolCall(client, server, tool, cwd, parsed, canonicalizeSource); !ok {
+		return code
+	}
 
-	resp, err := sendServerRequestWithEphemeralFallback(client, &ipc.Request{
-		Type:    "call_tool",
-		Server:  server,
-		Tool:    tool,
-		Args:    argsJSON,
-		Cache:   parsed.cacheTTL,
-		Verbose: parsed.verbose,
-		CWD:     cwd,
-	}, canonicalizeSource)
+	req := &ipc.Request{
+		Type:              "call_tool",
+		Server:            server,
+		Tool:              tool,
+		Args:              argsJSON,
+		Cache:             parsed.cacheTTL,
+		CacheScope:        parsed.cacheScope,
+		ExplainCache:      parsed.explainCache,
+		CacheErrors:       parsed.cacheErrors,
+		Fresh:             parsed.fresh,
+		StaleOK:           parsed.staleOK,
+		Verbose:           parsed.verbose,
+		CWD:               cwd,
+		Timeout:           parsed.timeout,
+		AttemptTimeout:    parsed.attemptTimeout,
+		RetryAfterRetries: parsed.retryAfterRetries,
+		RetryBudget:       parsed.retryBudget,
+		CaptureStderr:     parsed.captureStderr,
+		Headers:           parsed.headers,
+		IdempotencyKey:    parsed.idempotencyKey,
+		RawBytes:          parsed.rawBytes,
+		Raw:               parsed.rawResult,
+		ContentOnly:       parsed.contentOnly,
+		CombineContent:    parsed.combineContent,
+	}
+	if parsed.progress && !parsed.quiet {
+		req.Progress = true
+		req.OnProgress = progressPrinter(rootStderr)
+	}
+	var resp *ipc.Response
+	started := time.Now()
+	if parsed.repeatUntil != nil {
+		resp, err = pollToolCall(client, req, parsed, canonicalizeSource)
+	} else {
+		resp, err = sendServerRequestWithEphemeralFallback(client, req, canonicalizeSource)
+	}
+	warnSlowCall(server, tool, time.Since(started), parsed)
 	if err != nil {
-		if !parsed.quiet {
+		if parsed.softFail {
+			writeSoftFailResponse(rootStdout, ipc.ExitInternal, "", err.Error())
+		} else if !parsed.quiet {
 			fmt.Fprintf(rootStderr, "mcpx: %v\n", err)
 		}
+		runOnErrorHook(parsed.onError, server, tool, ipc.ExitInternal, err.Error(), parsed.quiet)
+		if parsed.softFail {
+			return ipc.ExitOK
+		}
 		return ipc.ExitInternal
 	}
-	writeCallResponse(resp, parsed.quiet, rootStdout, rootStderr)
+	if resp.ExitCode == ipc.ExitOK {
+		code := ipc.ExitOK
+		printed := redactedCallResponse(resp, parsed)
+		if parsed.outputDir != "" {
+			code = writeFilePerItemResponse(printed, parsed)
+		} else if parsed.outputFile != "" {
+			code = writeOutputFileResponse(printed, parsed)
+		} else if parsed.tee != "" {
+			code = writeTeeResponse(printed, parsed)
+		} else if parsed.jsonlField != "" {
+			code = writeJSONLFieldResponse(printed, parsed)
+		} else if parsed.flatten {
+			code = writeFlattenedResponse(printed, parsed)
+		} else {
+			writeCallResponse(printed, parsed.quiet, parsed.outputEncoding, rootStdout, rootStderr)
+		}
+		if code == ipc.ExitOK && parsed.mapExit != nil {
+			return mappedCallExit(resp, parsed)
+		}
+		return code
+	}
+
+	if parsed.softFail {
+		writeSoftFailResponse(rootStdout, resp.ExitCode, resp.ErrorCode, callFailureMessage(resp))
+	} else {
+		writeCallResponse(resp, parsed.quiet, parsed.outputEncoding, rootStdout, rootStderr)
+	}
+	runOnErrorHook(parsed.onError, server, tool, resp.ExitCode, callFailureMessage(resp), parsed.quiet)
+	if parsed.softFail {
+		return ipc.ExitOK
+	}
 	return resp.ExitCode
 }
 
-func writeCallResponse(resp *ipc.Response, quiet bool, stdout, stderr io.Writer) {
+func writeFlattenedResponse(resp *ipc.Response, parsed *toolCallArgs) int {
+	fields, err := flattenJSON(resp.Content)
+	if resp.ContentType != "" && resp.ContentType != "application/json" {
+		err = fmt.Errorf("--flatten requires a JSON result, got %s (%s)", resp.ContentType, resp.Encoding)
+	}
+	if err != nil {
+		if !parsed.quiet {
+			fmt.Fprintf(rootStderr, "mcpx: %v\n", err)
+		}
+		return ipc.ExitUsageErr
+	}
+	if !parsed.quiet && resp.Stderr != "" {
+		fmt.Fprintln(rootStderr, resp.Stderr)
+	}
+	if err := writeFlattened(rootStdout, fields, parsed.output); err != nil {
+		fmt.Fprintf(rootStderr, "mcpx: %v\n", err)
+		return ipc.ExitInternal
+	}
+	return ipc.ExitOK
+}
+
+func writeCallResponse(resp *ipc.Response, quiet bool, encoding string, stdout, stderr io.Writer) {
 	if resp == nil {
 		return
 	}
 	if quiet {
-		writeToolResponse(resp, true, stdout, stderr)
+		writeToolResponse(resp, true, encoding, stdout, stderr)
 		return
 	}
 	if resp.Stderr != "" {
 		fmt.Fprintln(stderr, resp.Stderr)
 	}
-	writeToolResponse(resp, false, stdout, stderr)
+	writeToolResponse(resp, false, encoding, stdout, stderr)
 }
 
-func writeToolResponse(resp *ipc.Response, quiet bool, stdout, stderr io.Writer) {
+// writeToolResponse writes successful content to stdout, re-encoded per
+// --output-encoding, and failed content to stderr unless quiet.
+func writeToolResponse(resp *ipc.Response, quiet bool, encoding string, stdout, stderr io.Writer) {
 	if resp == nil {
 		return
 	}
 
 	if resp.ExitCode == ipc.ExitOK {
-		stdout.Write(resp.Content) //nolint:errcheck
+		stdout.Write(encodeOutput(resp.Content, encoding)) //nolint:errcheck
 		return
 	}
 
@@ -221,7 +221,7 @@ func TestListToolsCanonicalizesExplicitSourceBeforeRequest(t *testing.T) {
 			}
 			return &ipc.Response{ExitCode: ipc.ExitOK, Content: []byte(`[]`)}, nil
 		},
-	}, source, cwd, true, outputModeText, true)
+	}, source, cwd, true, outputModeText, toolListShapeDefault, true)
 
 	if code != ipc.ExitOK {
 		t.Fatalf("listTools(canonicalized source) = %d, want %d", code, ipc.ExitOK)
@@ -251,7 +251,7 @@ func TestListToolsReturnsInternalOnInvalidPayload(t *testing.T) {
 		sendFn: func(req *ipc.Request) (*ipc.Response, error) {
 			return &ipc.Response{ExitCode: ipc.ExitOK, Content: []byte(`not-json`)}, nil
 		},
-	}, "github", "/tmp", false, outputModeText, false)
+	}, "github", "/tmp", false, outputModeText, toolListShapeDefault, false)
 
 	if code != ipc.ExitInternal {
 		t.Fatalf("listTools(invalid payload) = %d, want %d", code, ipc.ExitInternal)
@@ -280,7 +280,7 @@ func TestListToolsJSONWriteErrorReturnsInternal(t *testing.T) {
 		sendFn: func(req *ipc.Request) (*ipc.Response, error) {
 			return &ipc.Response{ExitCode: ipc.ExitOK, Content: []byte(`[{"name":"ping"}]`)}, nil
 		},
-	}, "github", "/tmp", false, outputModeJSON, false)
+	}, "github", "/tmp", false, outputModeJSON, toolListShapeDefault, false)
 
 	if code != ipc.ExitInternal {
 		t.Fatalf("listTools(json write error) = %d, want %d", code, ipc.ExitInternal)
@@ -307,7 +307,7 @@ func TestListToolsPropagatesDaemonExitAndStderr(t *testing.T) {
 		sendFn: func(req *ipc.Request) (*ipc.Response, error) {
 			return &ipc.Response{ExitCode: ipc.ExitUsageErr, Stderr: "tool listing failed"}, nil
 		},
-	}, "github", "/tmp", false, outputModeText, false)
+	}, "github", "/tmp", false, outputModeText, toolListShapeDefault, false)
 
 	if code != ipc.ExitUsageErr {
 		t.Fatalf("listTools(daemon error) = %d, want %d", code, ipc.ExitUsageErr)
@@ -435,7 +435,7 @@ func TestWriteToolResponseQuietNonOKSuppressesContent(t *testing.T) {
 	var out bytes.Buffer
 	var errOut bytes.Buffer
 
-	writeToolResponse(resp, true, &out, &errOut)
+	writeToolResponse(resp, true, "", &out, &errOut)
 
 	if out.Len() != 0 {
 		t.Fatalf("stdout = %q, want empty", out.String())
@@ -453,7 +453,7 @@ func TestWriteToolResponseNonQuietNonOKWritesToStderr(t *testing.T) {
 	var out bytes.Buffer
 	var errOut bytes.Buffer
 
-	writeToolResponse(resp, false, &out, &errOut)
+	writeToolResponse(resp, false, "", &out, &errOut)
 
 	if out.Len() != 0 {
 		t.Fatalf("stdout = %q, want empty", out.String())
@@ -472,7 +472,7 @@ func TestWriteCallResponseQuietSkipsStderrButWritesSuccessContent(t *testing.T)
 	var out bytes.Buffer
 	var errOut bytes.Buffer
 
-	writeCallResponse(resp, true, &out, &errOut)
+	writeCallResponse(resp, true, "", &out, &errOut)
 
 	if got := out.String(); got != "ok\n" {
 		t.Fatalf("stdout = %q, want %q", got, "ok\\n")
@@ -529,3 +529,29 @@ func TestSendServerRequestWithEphemeralFallbackDoesNotRetryWhenEphemeralPresent(
 		t.Fatalf("resp = %#v, want usage error response", resp)
 	}
 }
+
+func TestWriteToolResponseAppliesOutputEncoding(t *testing.T) {
+	content := []byte{0x89, 'P', 'N', 'G', 0x00, 0xff}
+	tests := []struct {
+		encoding string
+		want     string
+	}{
+		{encoding: "", want: string(content)},
+		{encoding: outputEncodingUTF8, want: string(content)},
+		{encoding: outputEncodingBase64, want: "iVBORwD/\n"},
+		{encoding: outputEncodingHex, want: "89504e4700ff\n"},
+	}
+	for _, tt := range tests {
+		var out, errOut bytes.Buffer
+		writeToolResponse(&ipc.Response{ExitCode: ipc.ExitOK, Content: content}, false, tt.encoding, &out, &errOut)
+		if out.String() != tt.want {
+			t.Fatalf("encoding %q: stdout = %q, want %q", tt.encoding, out.String(), tt.want)
+		}
+	}
+
+	var out, errOut bytes.Buffer
+	writeToolResponse(&ipc.Response{ExitCode: ipc.ExitToolErr, Content: []byte("boom")}, false, outputEncodingHex, &out, &errOut)
+	if errOut.String() != "boom" || out.Len() != 0 {
+		t.Fatalf("error output stdout=%q stderr=%q, want stderr left unencoded", out.String(), errOut.String())
+	}
+}
@@ -57,25 +57,56 @@ func printRootHelp(out io.Writer) {
 	fmt.Fprintln(out, "Usage:")
 	fmt.Fprintln(out, "  mcpx")
 	fmt.Fprintln(out, "  mcpx --json")
+	fmt.Fprintln(out, "  mcpx --validate [--json]")
 	fmt.Fprintln(out, "  mcpx <server> [FLAGS]")
 	fmt.Fprintln(out, "  mcpx <server> <tool> [FLAGS]")
-	fmt.Fprintln(out, "  mcpx add <source> [--name <server>] [--header KEY=VALUE]... [--overwrite]")
+	fmt.Fprintln(out, "  mcpx <server> resources [FLAGS]")
+	fmt.Fprintln(out, "  mcpx <server> prompts [FLAGS]")
+	fmt.Fprintln(out, "  mcpx <server> prompt <name> [FLAGS]")
+	fmt.Fprintln(out, "  mcpx run <recipe> [FLAGS]")
+	fmt.Fprintln(out, "  mcpx add <source> [--name <server>] [--header KEY=VALUE]... [--overwrite] [--force]")
+	fmt.Fprintln(out, "  mcpx remove <server> [--yes]")
+	fmt.Fprintln(out, "  mcpx import <cursor|claude|codex|kiro> [--overwrite]")
 	fmt.Fprintln(out, "  mcpx shim <install|remove|list> ...")
+	fmt.Fprintln(out, "  mcpx config schema")
+	fmt.Fprintln(out, "  mcpx daemon reload")
+	fmt.Fprintln(out, "  mcpx daemon status [--json]")
+	fmt.Fprintln(out, "  mcpx daemon run [--foreground] [--log-format text|json]")
+	fmt.Fprintln(out, "  mcpx cache clear [<server> [<tool>]]")
+	fmt.Fprintln(out, "  mcpx cache stats [--json]")
 	fmt.Fprintln(out, "  mcpx completion <bash|zsh|fish>")
+	fmt.Fprintln(out, "  mcpx completion status [bash|zsh|fish]")
 	fmt.Fprintln(out, "  mcpx skill install [<server>] [FLAGS]")
 	fmt.Fprintln(out, "")
 	fmt.Fprintln(out, "Global flags:")
 	fmt.Fprintln(out, "  --help, -h       Show help")
 	fmt.Fprintln(out, "  --version, -V    Show version")
+	fmt.Fprintln(out, "  --validate       Validate config (no daemon); exit 2 on problems")
 	fmt.Fprintln(out, "  --json           Emit mcpx-owned output as JSON for:")
 	fmt.Fprintln(out, "                   mcpx, mcpx <server>, and mcpx <server> <tool> --help")
+	fmt.Fprintln(out, "  --yaml           Emit the same output as --json, encoded as YAML")
+	fmt.Fprintln(out, "  --text           Emit text for mcpx and mcpx <server> when default_output")
+	fmt.Fprintln(out, "                   or MCPX_OUTPUT is json")
 	fmt.Fprintln(out, "")
 	fmt.Fprintln(out, "Server listing flags (for `mcpx`):")
-	fmt.Fprintln(out, "  --verbose, -v    Include server origin kind metadata")
+	fmt.Fprintln(out, "  --verbose, -v    Include server origin kind and connection state")
+	fmt.Fprintln(out, "  --changed-since <time>")
+	fmt.Fprintln(out, "                   Only servers whose config file changed after <time>")
+	fmt.Fprintln(out, "                   (RFC3339, YYYY-MM-DD, or a duration ago like 24h)")
+	fmt.Fprintln(out, "  --unused <duration>")
+	fmt.Fprintln(out, "                   Only servers with no tool calls in that window, from the")
+	fmt.Fprintln(out, "                   daemon's call history since it started")
+	fmt.Fprintln(out, "  --graph[=tree|dot]")
+	fmt.Fprintln(out, "                   Group servers by origin as a tree (default) or Graphviz DOT")
 	fmt.Fprintln(out, "")
 	fmt.Fprintln(out, "Tool listing flags (for `mcpx <server>`):")
 	fmt.Fprintln(out, "  --verbose, -v    Show full tool descriptions")
 	fmt.Fprintln(out, "  --json           Emit tool list as JSON")
+	fmt.Fprintln(out, "  --json-full      Emit tool list with server name, transport, and origin")
+	fmt.Fprintln(out, "  --yaml           Emit tool list as YAML")
+	fmt.Fprintln(out, "")
+	fmt.Fprintln(out, "Resource and prompt listings (`mcpx <server> resources|prompts`) accept the same flags.")
+	fmt.Fprintln(out, "Use `mcpx <server> -- resources` to call a tool with a reserved name.")
 	fmt.Fprintln(out, "")
 	fmt.Fprintln(out, "Man page:")
 	fmt.Fprintln(out, "  man mcpx")
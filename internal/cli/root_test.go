@@ -214,7 +214,7 @@ func TestResolveBuildVersionHonorsInjectedValue(t *testing.T) {
 }
 
 func TestParseToolListArgsVerbose(t *testing.T) {
-	parsed, err := parseToolListArgs([]string{"--verbose"})
+	parsed, err := parseToolListArgs([]string{"--verbose"}, outputModeText)
 	if err != nil {
 		t.Fatalf("parseToolListArgs() error = %v", err)
 	}
@@ -227,7 +227,7 @@ func TestParseToolListArgsVerbose(t *testing.T) {
 }
 
 func TestParseToolListArgsHelpAndVerbose(t *testing.T) {
-	parsed, err := parseToolListArgs([]string{"-h", "-v"})
+	parsed, err := parseToolListArgs([]string{"-h", "-v"}, outputModeText)
 	if err != nil {
 		t.Fatalf("parseToolListArgs() error = %v", err)
 	}
@@ -240,7 +240,7 @@ func TestParseToolListArgsHelpAndVerbose(t *testing.T) {
 }
 
 func TestParseToolListArgsSupportsJSON(t *testing.T) {
-	parsed, err := parseToolListArgs([]string{"--json"})
+	parsed, err := parseToolListArgs([]string{"--json"}, outputModeText)
 	if err != nil {
 		t.Fatalf("parseToolListArgs() error = %v", err)
 	}
@@ -250,7 +250,7 @@ func TestParseToolListArgsSupportsJSON(t *testing.T) {
 }
 
 func TestParseRootServerListArgsDefaults(t *testing.T) {
-	parsed, handled, err := parseRootServerListArgs(nil)
+	parsed, handled, err := parseRootServerListArgs(nil, outputModeText)
 	if err != nil {
 		t.Fatalf("parseRootServerListArgs(nil) error = %v", err)
 	}
@@ -266,7 +266,7 @@ func TestParseRootServerListArgsDefaults(t *testing.T) {
 }
 
 func TestParseRootServerListArgsSupportsVerboseJSON(t *testing.T) {
-	parsed, handled, err := parseRootServerListArgs([]string{"-v", "--json"})
+	parsed, handled, err := parseRootServerListArgs([]string{"-v", "--json"}, outputModeText)
 	if err != nil {
 		t.Fatalf("parseRootServerListArgs() error = %v", err)
 	}
@@ -282,13 +282,13 @@ func TestParseRootServerListArgsSupportsVerboseJSON(t *testing.T) {
 }
 
 func TestParseRootServerListArgsDoesNotClaimUnknownFlag(t *testing.T) {
-	if _, handled, err := parseRootServerListArgs([]string{"--bogus"}); handled || err != nil {
+	if _, handled, err := parseRootServerListArgs([]string{"--bogus"}, outputModeText); handled || err != nil {
 		t.Fatalf("parseRootServerListArgs([--bogus]) handled=%v err=%v, want handled=false and nil error", handled, err)
 	}
 }
 
 func TestParseRootServerListArgsDoesNotClaimMixedRootAndUnknownTokens(t *testing.T) {
-	if _, handled, err := parseRootServerListArgs([]string{"--json", "--bogus"}); handled || err != nil {
+	if _, handled, err := parseRootServerListArgs([]string{"--json", "--bogus"}, outputModeText); handled || err != nil {
 		t.Fatalf("parseRootServerListArgs([--json --bogus]) handled=%v err=%v, want handled=false and nil error", handled, err)
 	}
 }
@@ -846,14 +846,45 @@ args = ["ok"]
 	}
 }
 
+func TestParseToolListArgsSupportsOutputShapes(t *testing.T) {
+	parsed, err := parseToolListArgs([]string{"--names-only"}, outputModeText)
+	if err != nil {
+		t.Fatalf("parseToolListArgs(--names-only) error = %v", err)
+	}
+	if parsed.shape != toolListShapeNames {
+		t.Fatalf("shape = %v, want names-only", parsed.shape)
+	}
+
+	parsed, err = parseToolListArgs([]string{"-v", "--descriptions-only"}, outputModeText)
+	if err != nil {
+		t.Fatalf("parseToolListArgs(--descriptions-only) error = %v", err)
+	}
+	if parsed.shape != toolListShapeDescriptions || !parsed.verbose {
+		t.Fatalf("parsed = %#v, want verbose descriptions-only", parsed)
+	}
+
+	if _, err := parseToolListArgs([]string{"--names-only", "--descriptions-only"}, outputModeText); err == nil {
+		t.Fatal("parseToolListArgs(both shapes) error = nil, want non-nil")
+	}
+	if _, err := parseToolListArgs([]string{"--names-only", "--json"}, outputModeText); err == nil {
+		t.Fatal("parseToolListArgs(--names-only --json) error = nil, want non-nil")
+	}
+}
+
+func TestParseServerCommandRejectsConflictingListShapes(t *testing.T) {
+	if _, err := parseServerCommand([]string{"--names-only", "--json"}, outputModeText); err == nil {
+		t.Fatal("parseServerCommand(--names-only --json) error = nil, want non-nil")
+	}
+}
+
 func TestParseToolListArgsRejectsUnknownFlags(t *testing.T) {
-	if _, err := parseToolListArgs([]string{"--cache=10s"}); err == nil {
+	if _, err := parseToolListArgs([]string{"--cache=10s"}, outputModeText); err == nil {
 		t.Fatal("parseToolListArgs() error = nil, want non-nil")
 	}
 }
 
 func TestParseServerCommandDefaultsToToolList(t *testing.T) {
-	cmd, err := parseServerCommand(nil)
+	cmd, err := parseServerCommand(nil, outputModeText)
 	if err != nil {
 		t.Fatalf("parseServerCommand() error = %v", err)
 	}
@@ -869,7 +900,7 @@ func TestParseServerCommandDefaultsToToolList(t *testing.T) {
 }
 
 func TestParseServerCommandParsesToolListFlags(t *testing.T) {
-	cmd, err := parseServerCommand([]string{"-v"})
+	cmd, err := parseServerCommand([]string{"-v"}, outputModeText)
 	if err != nil {
 		t.Fatalf("parseServerCommand() error = %v", err)
 	}
@@ -882,7 +913,7 @@ func TestParseServerCommandParsesToolListFlags(t *testing.T) {
 }
 
 func TestParseServerCommandParsesToolListJSONFlag(t *testing.T) {
-	cmd, err := parseServerCommand([]string{"--json"})
+	cmd, err := parseServerCommand([]string{"--json"}, outputModeText)
 	if err != nil {
 		t.Fatalf("parseServerCommand() error = %v", err)
 	}
@@ -895,7 +926,7 @@ func TestParseServerCommandParsesToolListJSONFlag(t *testing.T) {
 }
 
 func TestParseServerCommandTreatsUnknownDashTokenAsToolName(t *testing.T) {
-	cmd, err := parseServerCommand([]string{"--status", "--json=true"})
+	cmd, err := parseServerCommand([]string{"--status", "--json=true"}, outputModeText)
 	if err != nil {
 		t.Fatalf("parseServerCommand() error = %v", err)
 	}
@@ -910,8 +941,53 @@ func TestParseServerCommandTreatsUnknownDashTokenAsToolName(t *testing.T) {
 	}
 }
 
+func TestParseServerCommandParsesResourcesAndPromptsListings(t *testing.T) {
+	cmd, err := parseServerCommand([]string{"resources", "--json"}, outputModeText)
+	if err != nil {
+		t.Fatalf("parseServerCommand(resources) error = %v", err)
+	}
+	if !cmd.resources || cmd.prompts || cmd.list {
+		t.Fatalf("parseServerCommand(resources) = %#v, want resources listing", cmd)
+	}
+	if !cmd.listOpts.output.isJSON() {
+		t.Fatal("output mode = text, want json")
+	}
+
+	cmd, err = parseServerCommand([]string{"prompts", "-v"}, outputModeText)
+	if err != nil {
+		t.Fatalf("parseServerCommand(prompts) error = %v", err)
+	}
+	if !cmd.prompts || cmd.resources || cmd.list {
+		t.Fatalf("parseServerCommand(prompts) = %#v, want prompts listing", cmd)
+	}
+	if !cmd.listOpts.verbose {
+		t.Fatal("verbose = false, want true")
+	}
+}
+
+func TestParseServerCommandTreatsResourcesWithToolArgsAsToolCall(t *testing.T) {
+	cmd, err := parseServerCommand([]string{"resources", "--uri=file:///a"}, outputModeText)
+	if err != nil {
+		t.Fatalf("parseServerCommand() error = %v", err)
+	}
+	if cmd.resources {
+		t.Fatal("resources = true, want tool call")
+	}
+	if cmd.tool != "resources" {
+		t.Fatalf("tool = %q, want %q", cmd.tool, "resources")
+	}
+
+	cmd, err = parseServerCommand([]string{"--", "prompts"}, outputModeText)
+	if err != nil {
+		t.Fatalf("parseServerCommand(-- prompts) error = %v", err)
+	}
+	if cmd.prompts || cmd.tool != "prompts" {
+		t.Fatalf("parseServerCommand(-- prompts) = %#v, want tool call", cmd)
+	}
+}
+
 func TestParseServerCommandSeparatorForcesToolMode(t *testing.T) {
-	cmd, err := parseServerCommand([]string{"--", "--help"})
+	cmd, err := parseServerCommand([]string{"--", "--help"}, outputModeText)
 	if err != nil {
 		t.Fatalf("parseServerCommand() error = %v", err)
 	}
@@ -924,7 +1000,7 @@ func TestParseServerCommandSeparatorForcesToolMode(t *testing.T) {
 }
 
 func TestParseServerCommandSeparatorRequiresToolName(t *testing.T) {
-	if _, err := parseServerCommand([]string{"--"}); err == nil {
+	if _, err := parseServerCommand([]string{"--"}, outputModeText); err == nil {
 		t.Fatal("parseServerCommand() error = nil, want non-nil")
 	}
 }
@@ -947,6 +1023,7 @@ args = ["ok"]
 	t.Setenv("XDG_CONFIG_HOME", xdgConfigHome)
 	t.Setenv("HOME", tmp)
 	t.Setenv("XDG_RUNTIME_DIR", "/dev/null")
+	t.Setenv("TMPDIR", "/dev/null") // also rules out the temp-dir fallbacks
 
 	if code := Run([]string{"github", "--help"}); code != ipc.ExitInternal {
 		t.Fatalf("Run([github --help]) = %d, want %d", code, ipc.ExitInternal)
@@ -2437,6 +2514,56 @@ args = ["ok"]
 	}
 }
 
+func TestRunRootVerboseShowsConnectionState(t *testing.T) {
+	tmp := t.TempDir()
+	t.Setenv("XDG_CONFIG_HOME", filepath.Join(tmp, "xdg-config"))
+	t.Setenv("HOME", tmp)
+	t.Setenv("XDG_RUNTIME_DIR", t.TempDir())
+
+	oldSpawn := spawnOrConnectFn
+	oldClient := newDaemonClient
+	defer func() {
+		spawnOrConnectFn = oldSpawn
+		newDaemonClient = oldClient
+	}()
+
+	spawnOrConnectFn = func() (string, error) { return "nonce", nil }
+	newDaemonClient = func(_, _ string) daemonRequester {
+		return stubDaemonClient{
+			sendFn: func(req *ipc.Request) (*ipc.Response, error) {
+				if req.Type != "list_servers" {
+					return nil, errors.New("unexpected request type")
+				}
+				return &ipc.Response{ExitCode: ipc.ExitOK, Content: []byte(`[{"name":"alpha","origin":{"kind":"mcpx_config"},"state":"connected"},{"name":"beta","origin":{"kind":"codex_apps"}}]`)}, nil
+			},
+		}
+	}
+
+	oldOut := rootStdout
+	defer func() { rootStdout = oldOut }()
+	var out bytes.Buffer
+	rootStdout = &out
+
+	if code := Run([]string{"-v"}); code != ipc.ExitOK {
+		t.Fatalf("Run([-v]) = %d, want %d", code, ipc.ExitOK)
+	}
+
+	found := map[string]string{}
+	for _, line := range strings.Split(strings.TrimSpace(out.String()), "\n") {
+		fields := strings.Fields(line)
+		if len(fields) != 3 {
+			t.Fatalf("line = %q, want 3 columns", line)
+		}
+		found[fields[0]] = fields[1] + " " + fields[2]
+	}
+	if found["alpha"] != "mcpx_config connected" {
+		t.Fatalf("alpha = %q, want %q", found["alpha"], "mcpx_config connected")
+	}
+	if found["beta"] != "codex_apps -" {
+		t.Fatalf("beta = %q, want %q", found["beta"], "codex_apps -")
+	}
+}
+
 func TestRunRootVerboseJSONShowsSources(t *testing.T) {
 	tmp := t.TempDir()
 	xdgConfigHome := filepath.Join(tmp, "xdg-config")
@@ -2570,7 +2697,7 @@ func TestWriteCallResponseUsageErrorPrintsStderrWithoutVerbose(t *testing.T) {
 	var out bytes.Buffer
 	var errOut bytes.Buffer
 
-	writeCallResponse(resp, false, &out, &errOut)
+	writeCallResponse(resp, false, "", &out, &errOut)
 
 	if out.Len() != 0 {
 		t.Fatalf("stdout = %q, want empty", out.String())
@@ -0,0 +1,81 @@
+package cli
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+
+	"github.com/lydakis/mcpx/internal/cache"
+	"github.com/lydakis/mcpx/internal/config"
+	"github.com/lydakis/mcpx/internal/ipc"
+)
+
+var (
+	getSchemaCacheFn = cache.GetSchema
+	putSchemaCacheFn = cache.PutSchema
+)
+
+// schemaCacheClient answers tool_schema requests from the on-disk schema
+// cache when it can and stores what the daemon returns. With fresh set it
+// skips the read but still stores, so --no-schema-cache and --fresh also
+// refresh the entry. Keys hash the requested server's entry in cfg, the
+// config Run loaded; a nil cfg disables the cache. Other requests pass
+// through unchanged.
+type schemaCacheClient struct {
+	next  daemonRequester
+	cfg   *config.Config
+	fresh bool
+}
+
+func withSchemaCache(client daemonRequester, cfg *config.Config, fresh bool) daemonRequester {
+	return schemaCacheClient{next: client, cfg: cfg, fresh: fresh}
+}
+
+func (c schemaCacheClient) Send(req *ipc.Request) (*ipc.Response, error) {
+	if req == nil || req.Type != "tool_schema" || req.Ephemeral != nil {
+		return c.next.Send(req)
+	}
+	hash, ok := schemaCacheKey(c.cfg, req.Server)
+	if !ok {
+		return c.next.Send(req)
+	}
+	if !c.fresh {
+		if payload, hit := getSchemaCacheFn(req.Server, req.Tool, hash); hit {
+			return &ipc.Response{Content: payload}, nil
+		}
+	}
+
+	resp, err := c.next.Send(req)
+	if err == nil && resp != nil && resp.ExitCode == ipc.ExitOK && resp.Stderr == "" {
+		_ = putSchemaCacheFn(req.Server, req.Tool, hash, resp.Content)
+	}
+	return resp, err
+}
+
+// cachedToolSchema returns a cached tool_schema payload without contacting
+// the daemon, for shell completion.
+func cachedToolSchema(cfg *config.Config, server, tool string) ([]byte, bool) {
+	hash, ok := schemaCacheKey(cfg, server)
+	if !ok {
+		return nil, false
+	}
+	return getSchemaCacheFn(server, tool, hash)
+}
+
+// schemaCacheKey digests server's config entry. Servers missing from config
+// (explicit sources, codex apps) are not cached.
+func schemaCacheKey(cfg *config.Config, server string) (string, bool) {
+	if cfg == nil {
+		return "", false
+	}
+	scfg, ok := cfg.Servers[server]
+	if !ok {
+		return "", false
+	}
+	data, err := json.Marshal(scfg)
+	if err != nil {
+		return "", false
+	}
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:]), true
+}
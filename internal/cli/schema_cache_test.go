@@ -0,0 +1,94 @@
+package cli
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/lydakis/mcpx/internal/config"
+	"github.com/lydakis/mcpx/internal/ipc"
+)
+
+const schemaCachePayload = `{"name":"search","description":"Search repos","inputSchema":{"type":"object","properties":{"query":{"type":"string"}}}}`
+
+func stubSchemaCache(t *testing.T) *int {
+	t.Helper()
+	t.Setenv("XDG_CACHE_HOME", t.TempDir())
+	oldOut, oldErr := rootStdout, rootStderr
+	t.Cleanup(func() { rootStdout, rootStderr = oldOut, oldErr })
+	rootStdout, rootStderr = &bytes.Buffer{}, &bytes.Buffer{}
+
+	calls := 0
+	return &calls
+}
+
+func schemaCountingClient(calls *int) daemonRequester {
+	return stubDaemonClient{sendFn: func(req *ipc.Request) (*ipc.Response, error) {
+		if req.Type == "tool_schema" {
+			*calls++
+		}
+		return &ipc.Response{Content: []byte(schemaCachePayload)}, nil
+	}}
+}
+
+func TestToolHelpSecondSchemaFetchWithinTTLAvoidsDaemon(t *testing.T) {
+	cfg := &config.Config{Servers: map[string]config.ServerConfig{"github": {Command: "github-mcp"}}}
+	calls := stubSchemaCache(t)
+	client := schemaCountingClient(calls)
+
+	for i := 0; i < 2; i++ {
+		if code := callToolWithBaseArgs(cfg, client, "github", "search", []string{"--help"}, nil, "", false); code != ipc.ExitOK {
+			t.Fatalf("callTool(--help) #%d = %d, want %d", i+1, code, ipc.ExitOK)
+		}
+	}
+	if *calls != 1 {
+		t.Fatalf("tool_schema requests = %d, want 1 (second served from cache)", *calls)
+	}
+
+	if code := callToolWithBaseArgs(cfg, client, "github", "search", []string{"--help", "--no-schema-cache"}, nil, "", false); code != ipc.ExitOK {
+		t.Fatalf("callTool(--no-schema-cache) = %d, want %d", code, ipc.ExitOK)
+	}
+	if *calls != 2 {
+		t.Fatalf("tool_schema requests = %d, want 2 after --no-schema-cache", *calls)
+	}
+}
+
+func TestToolHelpFreshBypassesSchemaCache(t *testing.T) {
+	cfg := &config.Config{Servers: map[string]config.ServerConfig{"github": {Command: "github-mcp"}}}
+	calls := stubSchemaCache(t)
+	client := schemaCountingClient(calls)
+
+	if code := callToolWithBaseArgs(cfg, client, "github", "search", []string{"--help"}, nil, "", false); code != ipc.ExitOK {
+		t.Fatalf("callTool(--help) = %d, want %d", code, ipc.ExitOK)
+	}
+	if code := callToolWithBaseArgs(cfg, client, "github", "search", []string{"--help", "--fresh"}, nil, "", false); code != ipc.ExitOK {
+		t.Fatalf("callTool(--help --fresh) = %d, want %d", code, ipc.ExitOK)
+	}
+	if *calls != 2 {
+		t.Fatalf("tool_schema requests = %d, want 2 after --fresh", *calls)
+	}
+}
+
+func TestSchemaCacheMissesAfterServerConfigChange(t *testing.T) {
+	cfg := &config.Config{Servers: map[string]config.ServerConfig{"github": {Command: "github-mcp"}}}
+	calls := stubSchemaCache(t)
+	client := withSchemaCache(schemaCountingClient(calls), cfg, false)
+
+	req := &ipc.Request{Type: "tool_schema", Server: "github", Tool: "search"}
+	if _, err := client.Send(req); err != nil {
+		t.Fatalf("Send() error = %v", err)
+	}
+	cfg.Servers["github"] = config.ServerConfig{Command: "github-mcp", Args: []string{"--v2"}}
+	if _, err := client.Send(req); err != nil {
+		t.Fatalf("Send() error = %v", err)
+	}
+	if *calls != 2 {
+		t.Fatalf("tool_schema requests = %d, want 2 after config change", *calls)
+	}
+
+	if payload, ok := cachedToolSchema(cfg, "github", "search"); !ok || string(payload) != schemaCachePayload {
+		t.Fatalf("cachedToolSchema(cfg) = (%q, %v), want cached payload for the new config", payload, ok)
+	}
+	if _, ok := cachedToolSchema(cfg, "unknown", "search"); ok {
+		t.Fatal("cachedToolSchema(cfg, unknown) hit, want servers outside config uncached")
+	}
+}
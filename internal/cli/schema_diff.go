@@ -0,0 +1,229 @@
+package cli
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"reflect"
+	"sort"
+
+	"github.com/lydakis/mcpx/internal/ipc"
+)
+
+type schemaDiffKind string
+
+const (
+	schemaDiffRemoved schemaDiffKind = "removed"
+	schemaDiffAdded   schemaDiffKind = "added"
+	schemaDiffChanged schemaDiffKind = "changed"
+)
+
+// schemaDiffEntry is one structural difference between two schemas. Path uses
+// the same dotted/[] notation as tool help output.
+type schemaDiffEntry struct {
+	Section string         `json:"section"`
+	Path    string         `json:"path"`
+	Kind    schemaDiffKind `json:"kind"`
+	Detail  string         `json:"detail"`
+}
+
+// diffToolSchemas compares input and output schemas of the same tool as
+// exposed by two servers.
+func diffToolSchemas(leftIn, leftOut, rightIn, rightOut map[string]any) []schemaDiffEntry {
+	var entries []schemaDiffEntry
+	entries = append(entries, diffSchemaSection("input_schema", leftIn, rightIn)...)
+	entries = append(entries, diffSchemaSection("output_schema", leftOut, rightOut)...)
+	return entries
+}
+
+func diffSchemaSection(section string, left, right map[string]any) []schemaDiffEntry {
+	switch {
+	case left == nil && right == nil:
+		return nil
+	case left == nil:
+		return []schemaDiffEntry{{Section: section, Kind: schemaDiffAdded, Detail: "schema only on right"}}
+	case right == nil:
+		return []schemaDiffEntry{{Section: section, Kind: schemaDiffRemoved, Detail: "schema only on left"}}
+	}
+
+	var entries []schemaDiffEntry
+	diffSchemaNode(&entries, section, "", left, right)
+	return entries
+}
+
+func diffSchemaNode(entries *[]schemaDiffEntry, section, path string, left, right map[string]any) {
+	if lt, rt := propType(left), propType(right); lt != rt {
+		*entries = append(*entries, schemaDiffEntry{Section: section, Path: path, Kind: schemaDiffChanged, Detail: fmt.Sprintf("type %s -> %s", lt, rt)})
+	}
+	if le, re := left["enum"], right["enum"]; !reflect.DeepEqual(le, re) && le != nil && re != nil {
+		*entries = append(*entries, schemaDiffEntry{Section: section, Path: path, Kind: schemaDiffChanged, Detail: fmt.Sprintf("enum %s -> %s", compactJSON(le), compactJSON(re))})
+	}
+
+	leftProps, _ := left["properties"].(map[string]any)
+	rightProps, _ := right["properties"].(map[string]any)
+	leftRequired := stringSet(toStringSlice(left["required"]))
+	rightRequired := stringSet(toStringSlice(right["required"]))
+
+	names := make([]string, 0, len(leftProps)+len(rightProps))
+	for name := range leftProps {
+		names = append(names, name)
+	}
+	for name := range rightProps {
+		if _, ok := leftProps[name]; !ok {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		childPath := name
+		if path != "" {
+			childPath = path + "." + name
+		}
+		leftChild, inLeft := leftProps[name].(map[string]any)
+		rightChild, inRight := rightProps[name].(map[string]any)
+		_, leftReq := leftRequired[name]
+		_, rightReq := rightRequired[name]
+
+		switch {
+		case inLeft && !inRight:
+			*entries = append(*entries, schemaDiffEntry{Section: section, Path: childPath, Kind: schemaDiffRemoved, Detail: describeSchemaProperty(leftChild, leftReq)})
+		case !inLeft && inRight:
+			*entries = append(*entries, schemaDiffEntry{Section: section, Path: childPath, Kind: schemaDiffAdded, Detail: describeSchemaProperty(rightChild, rightReq)})
+		case inLeft && inRight:
+			if leftReq != rightReq {
+				*entries = append(*entries, schemaDiffEntry{Section: section, Path: childPath, Kind: schemaDiffChanged, Detail: fmt.Sprintf("%s -> %s", requiredLabel(leftReq), requiredLabel(rightReq))})
+			}
+			diffSchemaNode(entries, section, childPath, leftChild, rightChild)
+		}
+	}
+
+	leftItems, leftHasItems := left["items"].(map[string]any)
+	rightItems, rightHasItems := right["items"].(map[string]any)
+	if leftHasItems && rightHasItems {
+		diffSchemaNode(entries, section, path+"[]", leftItems, rightItems)
+	}
+}
+
+func describeSchemaProperty(schema map[string]any, required bool) string {
+	return fmt.Sprintf("%s, %s", propType(schema), requiredLabel(required))
+}
+
+func requiredLabel(required bool) string {
+	if required {
+		return "required"
+	}
+	return "optional"
+}
+
+func stringSet(values []string) map[string]struct{} {
+	out := make(map[string]struct{}, len(values))
+	for _, v := range values {
+		out[v] = struct{}{}
+	}
+	return out
+}
+
+func compactJSON(v any) string {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return fmt.Sprint(v)
+	}
+	return string(data)
+}
+
+func writeSchemaDiffText(out io.Writer, leftLabel, rightLabel string, entries []schemaDiffEntry) error {
+	if _, err := fmt.Fprintf(out, "--- %s\n+++ %s\n", leftLabel, rightLabel); err != nil {
+		return err
+	}
+	if len(entries) == 0 {
+		_, err := fmt.Fprintln(out, "(schemas are identical)")
+		return err
+	}
+
+	section := ""
+	for _, entry := range entries {
+		if entry.Section != section {
+			section = entry.Section
+			if _, err := fmt.Fprintf(out, "%s:\n", section); err != nil {
+				return err
+			}
+		}
+		marker := "~"
+		switch entry.Kind {
+		case schemaDiffRemoved:
+			marker = "-"
+		case schemaDiffAdded:
+			marker = "+"
+		}
+		path := entry.Path
+		if path == "" {
+			path = "(root)"
+		}
+		if _, err := fmt.Fprintf(out, "  %s %s: %s\n", marker, path, entry.Detail); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func fetchToolSchemaPayload(client daemonRequester, server, tool, cwd string, canonicalizeSource bool) (name string, inputSchema, outputSchema map[string]any, code int) {
+	name, _, inputSchema, outputSchema, code = fetchToolHelpPayload(client, server, tool, cwd, canonicalizeSource)
+	return name, inputSchema, outputSchema, code
+}
+
+// fetchToolHelpPayload is fetchToolSchemaPayload with the tool description.
+func fetchToolHelpPayload(client daemonRequester, server, tool, cwd string, canonicalizeSource bool) (name, description string, inputSchema, outputSchema map[string]any, code int) {
+	resp, err := sendServerRequestWithEphemeralFallback(client, &ipc.Request{
+		Type:   "tool_schema",
+		Server: server,
+		Tool:   tool,
+		CWD:    cwd,
+	}, canonicalizeSource)
+	if err != nil {
+		fmt.Fprintf(rootStderr, "mcpx: %v\n", err)
+		return "", "", nil, nil, ipc.ExitInternal
+	}
+	if resp.ExitCode != ipc.ExitOK {
+		if resp.Stderr != "" {
+			fmt.Fprintln(rootStderr, resp.Stderr)
+		}
+		return "", "", nil, nil, resp.ExitCode
+	}
+
+	name, description, inputSchema, outputSchema = parseToolHelpPayload(resp.Content)
+	return resolvedToolHelpName(tool, name), description, inputSchema, outputSchema, ipc.ExitOK
+}
+
+// showSchemaDiff compares tool's schema on server against the same-named tool
+// on otherServer.
+func showSchemaDiff(client daemonRequester, server, otherServer, tool, cwd string, output outputMode, canonicalizeSource bool) int {
+	leftName, leftIn, leftOut, code := fetchToolSchemaPayload(client, server, tool, cwd, canonicalizeSource)
+	if code != ipc.ExitOK {
+		return code
+	}
+	rightName, rightIn, rightOut, code := fetchToolSchemaPayload(client, otherServer, tool, cwd, false)
+	if code != ipc.ExitOK {
+		return code
+	}
+
+	entries := diffToolSchemas(leftIn, leftOut, rightIn, rightOut)
+	if output.isJSON() {
+		if entries == nil {
+			entries = []schemaDiffEntry{}
+		}
+		data, err := json.Marshal(entries)
+		if err != nil {
+			fmt.Fprintf(rootStderr, "mcpx: encoding schema diff: %v\n", err)
+			return ipc.ExitInternal
+		}
+		rootStdout.Write(append(data, '\n')) //nolint:errcheck
+		return ipc.ExitOK
+	}
+
+	if err := writeSchemaDiffText(rootStdout, server+"/"+leftName, otherServer+"/"+rightName, entries); err != nil {
+		fmt.Fprintf(rootStderr, "mcpx: %v\n", err)
+		return ipc.ExitInternal
+	}
+	return ipc.ExitOK
+}
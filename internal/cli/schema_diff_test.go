@@ -0,0 +1,97 @@
+package cli
+
+import (
+	"bytes"
+	"encoding/json"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/lydakis/mcpx/internal/ipc"
+)
+
+func TestDiffToolSchemasReportsStructuralChanges(t *testing.T) {
+	left := map[string]any{
+		"type": "object",
+		"properties": map[string]any{
+			"query": map[string]any{"type": "string"},
+			"limit": map[string]any{"type": "integer"},
+			"sort":  map[string]any{"type": "string", "enum": []any{"stars", "updated"}},
+			"owner": map[string]any{"type": "string"},
+		},
+		"required": []any{"query"},
+	}
+	right := map[string]any{
+		"type": "object",
+		"properties": map[string]any{
+			"query":  map[string]any{"type": "string"},
+			"limit":  map[string]any{"type": "number"},
+			"sort":   map[string]any{"type": "string", "enum": []any{"stars"}},
+			"labels": map[string]any{"type": "array", "items": map[string]any{"type": "string"}},
+		},
+		"required": []any{"query", "limit"},
+	}
+
+	got := diffToolSchemas(left, nil, right, map[string]any{"type": "object"})
+	want := []schemaDiffEntry{
+		{Section: "input_schema", Path: "labels", Kind: schemaDiffAdded, Detail: "array, optional"},
+		{Section: "input_schema", Path: "limit", Kind: schemaDiffChanged, Detail: "optional -> required"},
+		{Section: "input_schema", Path: "limit", Kind: schemaDiffChanged, Detail: "type integer -> number"},
+		{Section: "input_schema", Path: "owner", Kind: schemaDiffRemoved, Detail: "string, optional"},
+		{Section: "input_schema", Path: "sort", Kind: schemaDiffChanged, Detail: `enum ["stars","updated"] -> ["stars"]`},
+		{Section: "output_schema", Kind: schemaDiffAdded, Detail: "schema only on right"},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("diffToolSchemas() =\n%#v\nwant\n%#v", got, want)
+	}
+}
+
+func TestCallToolShowSchemaDiffComparesTwoServers(t *testing.T) {
+	schemas := map[string]string{
+		"github": `{"name":"search","input_schema":{"type":"object","properties":{"query":{"type":"string"}},"required":["query"]}}`,
+		"gitlab": `{"name":"search","input_schema":{"type":"object","properties":{"query":{"type":"string"},"scope":{"type":"string"}}}}`,
+	}
+
+	oldOut, oldErr := rootStdout, rootStderr
+	defer func() { rootStdout, rootStderr = oldOut, oldErr }()
+	var stdout, stderr bytes.Buffer
+	rootStdout, rootStderr = &stdout, &stderr
+
+	client := stubDaemonClient{
+		sendFn: func(req *ipc.Request) (*ipc.Response, error) {
+			if req.Type != "tool_schema" {
+				t.Fatalf("request type = %q, want tool_schema", req.Type)
+			}
+			return &ipc.Response{Content: []byte(schemas[req.Server])}, nil
+		},
+	}
+
+	code := callTool(client, "github", "search", []string{"--show-schema-diff", "gitlab"}, "", false)
+	if code != ipc.ExitOK {
+		t.Fatalf("callTool() = %d, want %d (stderr=%q)", code, ipc.ExitOK, stderr.String())
+	}
+	want := strings.Join([]string{
+		"--- github/search",
+		"+++ gitlab/search",
+		"input_schema:",
+		"  ~ query: required -> optional",
+		"  + scope: string, optional",
+		"",
+	}, "\n")
+	if stdout.String() != want {
+		t.Fatalf("stdout =\n%s\nwant\n%s", stdout.String(), want)
+	}
+
+	stdout.Reset()
+	code = callTool(client, "github", "search", []string{"--show-schema-diff=gitlab", "--json"}, "", false)
+	if code != ipc.ExitOK {
+		t.Fatalf("callTool(--json) = %d, want %d", code, ipc.ExitOK)
+	}
+	var entries []schemaDiffEntry
+	if err := json.Unmarshal(stdout.Bytes(), &entries); err != nil {
+		t.Fatalf("json.Unmarshal(diff) error = %v (stdout=%q)", err, stdout.String())
+	}
+	if len(entries) != 2 {
+		t.Fatalf("diff entries = %#v, want 2", entries)
+	}
+}
@@ -0,0 +1,73 @@
+package cli
+
+import (
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/lydakis/mcpx/internal/ipc"
+)
+
+// showSchemaMarkdown prints the tool's input and output schemas as Markdown
+// tables for docs sites, without calling the tool.
+func showSchemaMarkdown(client daemonRequester, server, tool, cwd string, canonicalizeSource bool) int {
+	name, description, inputSchema, outputSchema, code := fetchToolHelpPayload(client, server, tool, cwd, canonicalizeSource)
+	if code != ipc.ExitOK {
+		return code
+	}
+	writeSchemaMarkdown(rootStdout, server, name, description, inputSchema, outputSchema)
+	return ipc.ExitOK
+}
+
+func writeSchemaMarkdown(w io.Writer, server, tool, description string, inputSchema, outputSchema map[string]any) {
+	fmt.Fprintf(w, "# %s\n\n", tool)
+	if description != "" {
+		fmt.Fprintf(w, "%s\n\n", description)
+	}
+	fmt.Fprintf(w, "```bash\nmcpx %s %s [FLAGS]\n```\n", server, tool)
+
+	fmt.Fprintln(w, "\n## Inputs")
+	fmt.Fprintln(w)
+	if lines := inputFlagLines(inputSchema); len(lines) == 0 {
+		fmt.Fprintln(w, "None.")
+	} else {
+		fmt.Fprintln(w, "| Parameter | Type | Required | Default | Description |")
+		fmt.Fprintln(w, "| --- | --- | --- | --- | --- |")
+		for _, line := range lines {
+			required := "no"
+			if line.Required {
+				required = "yes"
+			}
+			def := ""
+			if line.HasDefault {
+				def = "`" + line.Default + "`"
+			}
+			fmt.Fprintf(w, "| `%s` | %s | %s | %s | %s |\n",
+				line.Path, markdownCell(line.Type), required, markdownCell(def), markdownCell(line.Description))
+		}
+	}
+
+	fmt.Fprintln(w, "\n## Outputs")
+	fmt.Fprintln(w)
+	if outputSchema == nil {
+		fmt.Fprintln(w, "Not declared by the server.")
+		return
+	}
+	lines := schemaLines(outputSchema)
+	if len(lines) == 0 {
+		fmt.Fprintln(w, "None.")
+		return
+	}
+	fmt.Fprintln(w, "| Field | Type | Description |")
+	fmt.Fprintln(w, "| --- | --- | --- |")
+	for _, line := range lines {
+		fmt.Fprintf(w, "| `%s` | %s | %s |\n", line.Path, markdownCell(line.Type), markdownCell(line.Description))
+	}
+}
+
+// markdownCell keeps a value on one table row: pipes are escaped and line
+// breaks become spaces.
+func markdownCell(s string) string {
+	s = strings.ReplaceAll(s, "|", `\|`)
+	return strings.Join(strings.Fields(s), " ")
+}
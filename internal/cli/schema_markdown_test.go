@@ -0,0 +1,82 @@
+package cli
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/lydakis/mcpx/internal/ipc"
+)
+
+func TestCallToolDumpSchemaMarkdownRendersInputsAndOutputs(t *testing.T) {
+	oldOut, oldErr := rootStdout, rootStderr
+	defer func() { rootStdout, rootStderr = oldOut, oldErr }()
+	var stdout, stderr bytes.Buffer
+	rootStdout, rootStderr = &stdout, &stderr
+
+	payload := `{
+		"name": "search",
+		"description": "Search repositories",
+		"input_schema": {
+			"type": "object",
+			"properties": {
+				"query": {"type": "string", "description": "Search | filter\nterms"},
+				"limit": {"type": "integer", "default": 10}
+			},
+			"required": ["query"]
+		},
+		"output_schema": {
+			"type": "object",
+			"properties": {
+				"items": {"type": "array", "items": {"type": "object", "properties": {
+					"name": {"type": "string", "description": "Repository name"}
+				}}}
+			}
+		}
+	}`
+	client := stubDaemonClient{sendFn: func(req *ipc.Request) (*ipc.Response, error) {
+		if req.Type != "tool_schema" {
+			t.Fatalf("request type = %q, want tool_schema", req.Type)
+		}
+		return &ipc.Response{Content: []byte(payload)}, nil
+	}}
+
+	if code := callTool(client, "github", "search", []string{"--dump-schema-md"}, "", false); code != ipc.ExitOK {
+		t.Fatalf("callTool() = %d, want %d (stderr=%q)", code, ipc.ExitOK, stderr.String())
+	}
+
+	want := "# search\n" +
+		"\n" +
+		"Search repositories\n" +
+		"\n" +
+		"```bash\n" +
+		"mcpx github search [FLAGS]\n" +
+		"```\n" +
+		"\n" +
+		"## Inputs\n" +
+		"\n" +
+		"| Parameter | Type | Required | Default | Description |\n" +
+		"| --- | --- | --- | --- | --- |\n" +
+		"| `limit` | integer | no | `10` |  |\n" +
+		"| `query` | string | yes |  | Search \\| filter terms |\n" +
+		"\n" +
+		"## Outputs\n" +
+		"\n" +
+		"| Field | Type | Description |\n" +
+		"| --- | --- | --- |\n" +
+		"| `items` | array |  |\n" +
+		"| `items[]` | object |  |\n" +
+		"| `items[].name` | string | Repository name |\n"
+	if stdout.String() != want {
+		t.Fatalf("stdout =\n%s\nwant\n%s", stdout.String(), want)
+	}
+}
+
+func TestWriteSchemaMarkdownNotesUndeclaredOutput(t *testing.T) {
+	var out bytes.Buffer
+	writeSchemaMarkdown(&out, "svc", "ping", "", map[string]any{"type": "object"}, nil)
+
+	want := "# ping\n\n```bash\nmcpx svc ping [FLAGS]\n```\n\n## Inputs\n\nNone.\n\n## Outputs\n\nNot declared by the server.\n"
+	if out.String() != want {
+		t.Fatalf("markdown =\n%s\nwant\n%s", out.String(), want)
+	}
+}
@@ -0,0 +1,119 @@
+package cli
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+
+	"github.com/lydakis/mcpx/internal/config"
+)
+
+const (
+	serverGraphTree = "tree"
+	serverGraphDOT  = "dot"
+)
+
+func parseServerGraphFormat(raw string) (string, error) {
+	switch strings.ToLower(strings.TrimSpace(raw)) {
+	case serverGraphTree:
+		return serverGraphTree, nil
+	case serverGraphDOT:
+		return serverGraphDOT, nil
+	default:
+		return "", fmt.Errorf("invalid --graph format %q: expected tree or dot", raw)
+	}
+}
+
+// serverOriginGroup is the servers that came from one origin kind and path.
+type serverOriginGroup struct {
+	kind    config.ServerOriginKind
+	path    string
+	servers []string
+}
+
+// groupServersByOrigin groups entries by origin kind, then by origin path,
+// sorting kinds, paths, and server names so output is stable.
+func groupServersByOrigin(entries []serverListEntry) []serverOriginGroup {
+	index := make(map[config.ServerOrigin]int)
+	var groups []serverOriginGroup
+	for _, entry := range entries {
+		origin := config.NormalizeServerOrigin(entry.Origin)
+		i, ok := index[origin]
+		if !ok {
+			i = len(groups)
+			index[origin] = i
+			groups = append(groups, serverOriginGroup{kind: origin.Kind, path: origin.Path})
+		}
+		groups[i].servers = append(groups[i].servers, entry.Name)
+	}
+
+	sort.Slice(groups, func(i, j int) bool {
+		if groups[i].kind != groups[j].kind {
+			return groups[i].kind < groups[j].kind
+		}
+		return groups[i].path < groups[j].path
+	})
+	for _, group := range groups {
+		sort.Strings(group.servers)
+	}
+	return groups
+}
+
+// writeServerGraph prints entries grouped by origin as an indented tree
+// (kind, then path, then servers) or as a Graphviz DOT digraph.
+func writeServerGraph(w io.Writer, entries []serverListEntry, format string) error {
+	groups := groupServersByOrigin(entries)
+	var buf bytes.Buffer
+	if format == serverGraphDOT {
+		writeServerGraphDOT(&buf, groups)
+	} else {
+		writeServerGraphTree(&buf, groups)
+	}
+	_, err := w.Write(buf.Bytes())
+	return err
+}
+
+func writeServerGraphTree(buf *bytes.Buffer, groups []serverOriginGroup) {
+	for i, group := range groups {
+		if i == 0 || groups[i-1].kind != group.kind {
+			fmt.Fprintln(buf, group.kind)
+		}
+		indent := "  "
+		if group.path != "" {
+			fmt.Fprintf(buf, "  %s\n", group.path)
+			indent = "    "
+		}
+		for _, server := range group.servers {
+			fmt.Fprintf(buf, "%s%s\n", indent, server)
+		}
+	}
+}
+
+func writeServerGraphDOT(buf *bytes.Buffer, groups []serverOriginGroup) {
+	fmt.Fprintln(buf, "digraph mcpx {")
+	fmt.Fprintln(buf, "  rankdir=LR;")
+	for i, group := range groups {
+		kindID := dotQuote("origin:" + string(group.kind))
+		if i == 0 || groups[i-1].kind != group.kind {
+			fmt.Fprintf(buf, "  %s [label=%s, shape=box];\n", kindID, dotQuote(string(group.kind)))
+		}
+		parentID := kindID
+		if group.path != "" {
+			parentID = dotQuote("path:" + string(group.kind) + ":" + group.path)
+			fmt.Fprintf(buf, "  %s [label=%s, shape=note];\n", parentID, dotQuote(group.path))
+			fmt.Fprintf(buf, "  %s -> %s;\n", kindID, parentID)
+		}
+		for _, server := range group.servers {
+			serverID := dotQuote("server:" + server)
+			fmt.Fprintf(buf, "  %s [label=%s];\n", serverID, dotQuote(server))
+			fmt.Fprintf(buf, "  %s -> %s;\n", parentID, serverID)
+		}
+	}
+	fmt.Fprintln(buf, "}")
+}
+
+func dotQuote(s string) string {
+	return `"` + strings.NewReplacer(`\`, `\\`, `"`, `\"`).Replace(s) + `"`
+}
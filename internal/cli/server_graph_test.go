@@ -0,0 +1,93 @@
+package cli
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/lydakis/mcpx/internal/config"
+	"github.com/lydakis/mcpx/internal/ipc"
+)
+
+func TestListServersGraphTreeGroupsByOrigin(t *testing.T) {
+	oldOut, oldErr := rootStdout, rootStderr
+	defer func() { rootStdout, rootStderr = oldOut, oldErr }()
+	var stdout, stderr bytes.Buffer
+	rootStdout, rootStderr = &stdout, &stderr
+
+	payload, err := json.Marshal([]serverListEntry{
+		{Name: "linear", Origin: config.NewServerOrigin(config.ServerOriginKindMCPXConfig, "/home/u/.config/mcpx/config.toml")},
+		{Name: "browser", Origin: config.NewServerOrigin(config.ServerOriginKindCursor, "/work/.cursor/mcp.json")},
+		{Name: "github", Origin: config.NewServerOrigin(config.ServerOriginKindMCPXConfig, "/home/u/.config/mcpx/config.toml")},
+		{Name: "search", Origin: config.NewServerOrigin(config.ServerOriginKindEnv, "MCPX_SERVER_SEARCH_*")},
+		{Name: "apps", Origin: config.NewServerOrigin(config.ServerOriginKindCodexApps, "")},
+	})
+	if err != nil {
+		t.Fatalf("json.Marshal(payload): %v", err)
+	}
+	client := stubDaemonClient{sendFn: func(req *ipc.Request) (*ipc.Response, error) {
+		return &ipc.Response{Content: payload}, nil
+	}}
+
+	args, handled, err := parseRootServerListArgs([]string{"--graph"}, outputModeText)
+	if err != nil || !handled {
+		t.Fatalf("parseRootServerListArgs(--graph) handled=%v err=%v", handled, err)
+	}
+	if code := listServersFromDaemonWithArgs(client, "/tmp", args); code != ipc.ExitOK {
+		t.Fatalf("listServersFromDaemonWithArgs() = %d, want %d (stderr=%q)", code, ipc.ExitOK, stderr.String())
+	}
+
+	want := strings.Join([]string{
+		"codex_apps",
+		"  apps",
+		"cursor",
+		"  /work/.cursor/mcp.json",
+		"    browser",
+		"env",
+		"  MCPX_SERVER_SEARCH_*",
+		"    search",
+		"mcpx_config",
+		"  /home/u/.config/mcpx/config.toml",
+		"    github",
+		"    linear",
+	}, "\n") + "\n"
+	if stdout.String() != want {
+		t.Fatalf("tree =\n%s\nwant\n%s", stdout.String(), want)
+	}
+}
+
+func TestWriteServerGraphDOTLinksOriginsToServers(t *testing.T) {
+	var out bytes.Buffer
+	err := writeServerGraph(&out, []serverListEntry{
+		{Name: "github", Origin: config.NewServerOrigin(config.ServerOriginKindMCPXConfig, "/cfg.toml")},
+		{Name: "apps", Origin: config.NewServerOrigin(config.ServerOriginKindCodexApps, "")},
+	}, serverGraphDOT)
+	if err != nil {
+		t.Fatalf("writeServerGraph() error = %v", err)
+	}
+	got := out.String()
+	for _, line := range []string{
+		"digraph mcpx {",
+		`"origin:mcpx_config" -> "path:mcpx_config:/cfg.toml";`,
+		`"path:mcpx_config:/cfg.toml" -> "server:github";`,
+		`"origin:codex_apps" -> "server:apps";`,
+	} {
+		if !strings.Contains(got, line) {
+			t.Fatalf("dot output missing %q:\n%s", line, got)
+		}
+	}
+}
+
+func TestParseRootServerListArgsGraph(t *testing.T) {
+	parsed, handled, err := parseRootServerListArgs([]string{"--graph=dot"}, outputModeText)
+	if err != nil || !handled || parsed.graph != serverGraphDOT {
+		t.Fatalf("parseRootServerListArgs(--graph=dot) = %+v handled=%v err=%v", parsed, handled, err)
+	}
+	if _, handled, err := parseRootServerListArgs([]string{"--graph=svg"}, outputModeText); !handled || err == nil {
+		t.Fatalf("parseRootServerListArgs(--graph=svg) handled=%v err=%v, want handled with error", handled, err)
+	}
+	if _, _, err := parseRootServerListArgs([]string{"--graph", "--json"}, outputModeText); err == nil {
+		t.Fatal("parseRootServerListArgs(--graph --json) error = nil, want non-nil")
+	}
+}
@@ -0,0 +1,52 @@
+package cli
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+var lastErrorNow = time.Now
+
+// formatServerLastError renders an entry's last error as
+// "last error 2m ago: <message>", or "" when the daemon reported none.
+func formatServerLastError(entry serverListEntry, now time.Time) string {
+	msg := strings.TrimSpace(entry.LastError)
+	if msg == "" {
+		return ""
+	}
+	// Keep the tabular listing on one line per server.
+	msg = strings.Join(strings.Fields(msg), " ")
+
+	at, err := time.Parse(time.RFC3339, entry.LastErrorAt)
+	if err != nil {
+		return "last error: " + msg
+	}
+	return fmt.Sprintf("last error %s ago: %s", formatErrorAge(now.Sub(at)), msg)
+}
+
+// formatServerLastCall renders an entry's last call as "last call 2h ago",
+// or "" when the daemon has not called the server.
+func formatServerLastCall(entry serverListEntry, now time.Time) string {
+	at, err := time.Parse(time.RFC3339, entry.LastCallAt)
+	if err != nil {
+		return ""
+	}
+	return fmt.Sprintf("last call %s ago", formatErrorAge(now.Sub(at)))
+}
+
+func formatErrorAge(d time.Duration) string {
+	switch {
+	case d < time.Minute:
+		if d < 0 {
+			d = 0
+		}
+		return fmt.Sprintf("%ds", int(d/time.Second))
+	case d < time.Hour:
+		return fmt.Sprintf("%dm", int(d/time.Minute))
+	case d < 24*time.Hour:
+		return fmt.Sprintf("%dh", int(d/time.Hour))
+	default:
+		return fmt.Sprintf("%dd", int(d/(24*time.Hour)))
+	}
+}
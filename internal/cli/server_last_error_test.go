@@ -0,0 +1,92 @@
+package cli
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/lydakis/mcpx/internal/ipc"
+)
+
+func TestFormatServerLastError(t *testing.T) {
+	now := time.Date(2026, 1, 2, 3, 10, 0, 0, time.UTC)
+	tests := []struct {
+		entry serverListEntry
+		want  string
+	}{
+		{serverListEntry{}, ""},
+		{serverListEntry{LastError: "connection refused", LastErrorAt: "2026-01-02T03:08:00Z"}, "last error 2m ago: connection refused"},
+		{serverListEntry{LastError: "boom\n  again", LastErrorAt: "2026-01-02T03:09:55Z"}, "last error 5s ago: boom again"},
+		{serverListEntry{LastError: "old", LastErrorAt: "2025-12-30T03:10:00Z"}, "last error 3d ago: old"},
+		{serverListEntry{LastError: "no timestamp"}, "last error: no timestamp"},
+	}
+	for _, tt := range tests {
+		if got := formatServerLastError(tt.entry, now); got != tt.want {
+			t.Fatalf("formatServerLastError(%#v) = %q, want %q", tt.entry, got, tt.want)
+		}
+	}
+}
+
+func TestListServersVerboseShowsLastError(t *testing.T) {
+	oldOut, oldNow := rootStdout, lastErrorNow
+	defer func() { rootStdout, lastErrorNow = oldOut, oldNow }()
+	var out bytes.Buffer
+	rootStdout = &out
+	lastErrorNow = func() time.Time { return time.Date(2026, 1, 2, 3, 10, 0, 0, time.UTC) }
+
+	client := stubDaemonClient{
+		sendFn: func(*ipc.Request) (*ipc.Response, error) {
+			return &ipc.Response{Content: []byte(`[{"name":"github","origin":{"kind":"mcpx_config"},"state":"failed","last_error":"connecting to github: connection refused","last_error_at":"2026-01-02T03:08:00Z"}]`)}, nil
+		},
+	}
+	if code := listServersFromDaemon(client, "", outputModeText, true); code != ipc.ExitOK {
+		t.Fatalf("listServersFromDaemon() = %d, want %d", code, ipc.ExitOK)
+	}
+	if got := out.String(); !strings.Contains(got, "failed  last error 2m ago: connecting to github: connection refused") {
+		t.Fatalf("stdout = %q, want last error column", got)
+	}
+}
+
+func TestListServersUnusedForwardsWindowAndPrintsEntries(t *testing.T) {
+	parsed, handled, err := parseRootServerListArgs([]string{"--unused", "24h", "--json"}, outputModeText)
+	if err != nil || !handled {
+		t.Fatalf("parseRootServerListArgs() handled=%v err=%v", handled, err)
+	}
+	if parsed.unused == nil || *parsed.unused != 24*time.Hour {
+		t.Fatalf("unused = %v, want 24h", parsed.unused)
+	}
+	if _, handled, err := parseRootServerListArgs([]string{"--unused=-1h"}, outputModeText); !handled || err == nil {
+		t.Fatalf("parseRootServerListArgs(--unused=-1h) handled=%v err=%v, want handled with error", handled, err)
+	}
+
+	oldStdout, oldStderr := rootStdout, rootStderr
+	defer func() { rootStdout, rootStderr = oldStdout, oldStderr }()
+	var out, errOut bytes.Buffer
+	rootStdout, rootStderr = &out, &errOut
+
+	var gotWindow *time.Duration
+	client := stubDaemonClient{sendFn: func(req *ipc.Request) (*ipc.Response, error) {
+		gotWindow = req.Unused
+		return &ipc.Response{ExitCode: ipc.ExitOK, Content: []byte(`[{"name":"linear","origin":{"kind":"mcpx_config"},"last_call_at":"2026-05-08T12:00:00Z"}]`)}, nil
+	}}
+	if code := listServersFromDaemonWithArgs(client, "", parsed); code != ipc.ExitOK {
+		t.Fatalf("listServersFromDaemonWithArgs() = %d, want %d", code, ipc.ExitOK)
+	}
+	if gotWindow == nil || *gotWindow != 24*time.Hour {
+		t.Fatalf("request unused = %v, want 24h", gotWindow)
+	}
+	if !strings.Contains(out.String(), `"last_call_at":"2026-05-08T12:00:00Z"`) {
+		t.Fatalf("stdout = %q, want entries with last_call_at", out.String())
+	}
+}
+
+func TestFormatServerLastCall(t *testing.T) {
+	now := time.Date(2026, 5, 10, 12, 0, 0, 0, time.UTC)
+	if got := formatServerLastCall(serverListEntry{LastCallAt: "2026-05-10T10:00:00Z"}, now); got != "last call 2h ago" {
+		t.Fatalf("formatServerLastCall() = %q, want %q", got, "last call 2h ago")
+	}
+	if got := formatServerLastCall(serverListEntry{}, now); got != "" {
+		t.Fatalf("formatServerLastCall(no call) = %q, want empty", got)
+	}
+}
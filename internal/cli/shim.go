@@ -14,8 +14,13 @@ import (
 )
 
 type shimInstallArgs struct {
-	server             string
-	dir                string
+	server string
+	dir    string
+	// recipe or toolServer and tool make the shim run a saved recipe or
+	// one tool instead of forwarding to the server named server.
+	recipe             string
+	toolServer         string
+	tool               string
 	installSkill       bool
 	skillStrict        bool
 	dataAgentDir       string
@@ -91,23 +96,43 @@ func runShimInstallCommand(args []string, cfg *config.Config, stdout, stderr io.
 		return ipc.ExitOK
 	}
 	if cfg != nil {
-		ok, err := shimServerKnown(parsed.server, cfg)
-		if err == nil && !ok {
-			fmt.Fprintf(stderr, "mcpx: shim: unknown server: %q\n", parsed.server)
-			return ipc.ExitUsageErr
+		if parsed.recipe != "" {
+			if _, ok := cfg.Recipes[parsed.recipe]; !ok {
+				printUnknownRecipe(stderr, parsed.recipe, cfg.Recipes)
+				return ipc.ExitUsageErr
+			}
+		} else {
+			server := parsed.server
+			if parsed.toolServer != "" {
+				server = parsed.toolServer
+			}
+			ok, err := shimServerKnown(server, cfg)
+			if err == nil && !ok {
+				fmt.Fprintf(stderr, "mcpx: shim: unknown server: %q\n", server)
+				return ipc.ExitUsageErr
+			}
 		}
 	}
 
-	result, err := shim.Install(parsed.server, shim.InstallOptions{Dir: parsed.dir})
+	result, err := shim.Install(parsed.server, shim.InstallOptions{
+		Dir:    parsed.dir,
+		Recipe: parsed.recipe,
+		Server: parsed.toolServer,
+		Tool:   parsed.tool,
+	})
 	if err != nil {
 		fmt.Fprintf(stderr, "mcpx: shim: %v\n", err)
 		return classifyShimErrorExitCode(err)
 	}
 
+	runs := ""
+	if result.Target != result.Server {
+		runs = fmt.Sprintf(" (runs mcpx %s)", result.Target)
+	}
 	if result.AlreadyInstalled {
-		fmt.Fprintf(stdout, "Shim %q already installed at %s\n", result.Server, result.Path)
+		fmt.Fprintf(stdout, "Shim %q already installed at %s%s\n", result.Server, result.Path, runs)
 	} else {
-		fmt.Fprintf(stdout, "Installed shim %q at %s\n", result.Server, result.Path)
+		fmt.Fprintf(stdout, "Installed shim %q at %s%s\n", result.Server, result.Path, runs)
 	}
 	if !result.DirInPath {
 		fmt.Fprintf(stderr, "mcpx: shim: warning: %s is not in PATH; add it to run shims directly\n", result.Dir)
@@ -181,9 +206,9 @@ func runShimListCommand(args []string, stdout, stderr io.Writer) int {
 	}
 
 	tw := tabwriter.NewWriter(stdout, 0, 0, 2, ' ', 0)
-	fmt.Fprintln(tw, "SERVER\tPATH")
+	fmt.Fprintln(tw, "SHIM\tRUNS\tPATH")
 	for _, entry := range entries {
-		fmt.Fprintf(tw, "%s\t%s\n", entry.Server, entry.Path)
+		fmt.Fprintf(tw, "%s\tmcpx %s\t%s\n", entry.Server, entry.Target, entry.Path)
 	}
 	_ = tw.Flush()
 	return ipc.ExitOK
@@ -194,7 +219,8 @@ func classifyShimErrorExitCode(err error) int {
 		errors.Is(err, shim.ErrPathOccupied) ||
 		errors.Is(err, shim.ErrCommandCollision) ||
 		errors.Is(err, shim.ErrNotInstalled) ||
-		errors.Is(err, shim.ErrNotManagedShim) {
+		errors.Is(err, shim.ErrNotManagedShim) ||
+		errors.Is(err, shim.ErrInvalidTarget) {
 		return ipc.ExitUsageErr
 	}
 	return ipc.ExitInternal
@@ -233,6 +259,32 @@ func parseShimInstallArgs(args []string) (*shimInstallArgs, error) {
 				return nil, err
 			}
 			parsed.dir = value
+		case arg == "--recipe" || strings.HasPrefix(arg, "--recipe="):
+			value, hasValue := strings.CutPrefix(arg, "--recipe=")
+			if !hasValue {
+				var err error
+				if value, err = parseShimPathArg(args, &i, "--recipe"); err != nil {
+					return nil, err
+				}
+			}
+			parsed.recipe = strings.TrimSpace(value)
+			if parsed.recipe == "" {
+				return nil, fmt.Errorf("missing value for --recipe")
+			}
+		case arg == "--tool" || strings.HasPrefix(arg, "--tool="):
+			value, hasValue := strings.CutPrefix(arg, "--tool=")
+			if !hasValue {
+				var err error
+				if value, err = parseShimPathArg(args, &i, "--tool"); err != nil {
+					return nil, err
+				}
+			}
+			server, tool, ok := strings.Cut(strings.TrimSpace(value), "/")
+			if !ok || strings.TrimSpace(server) == "" || strings.TrimSpace(tool) == "" {
+				return nil, fmt.Errorf("invalid --tool %q (want <server>/<tool>)", value)
+			}
+			parsed.toolServer = strings.TrimSpace(server)
+			parsed.tool = strings.TrimSpace(tool)
 		case strings.HasPrefix(arg, "--data-agent-dir="):
 			parsed.dataAgentDir = strings.TrimSpace(strings.TrimPrefix(arg, "--data-agent-dir="))
 			skillFlagsSeen = true
@@ -301,6 +353,12 @@ func parseShimInstallArgs(args []string) (*shimInstallArgs, error) {
 	if parsed.server == "" {
 		return nil, fmt.Errorf("missing server (usage: mcpx shim install <server>)")
 	}
+	if parsed.recipe != "" && parsed.toolServer != "" {
+		return nil, fmt.Errorf("--recipe and --tool cannot be combined")
+	}
+	if parsed.installSkill && (parsed.recipe != "" || parsed.toolServer != "") {
+		return nil, fmt.Errorf("--skill is only supported for server shims")
+	}
 	if parsed.skillStrict && !parsed.installSkill {
 		return nil, fmt.Errorf("--skill-strict requires --skill")
 	}
@@ -412,11 +470,14 @@ func parseShimPathArg(args []string, idx *int, flag string) (string, error) {
 func printShimHelp(out io.Writer) {
 	fmt.Fprintln(out, "Usage:")
 	fmt.Fprintln(out, "  mcpx shim install <server> [--dir <path>]")
+	fmt.Fprintln(out, "  mcpx shim install <name> --recipe <recipe> [--dir <path>]")
+	fmt.Fprintln(out, "  mcpx shim install <name> --tool <server>/<tool> [--dir <path>]")
 	fmt.Fprintln(out, "  mcpx shim remove <server> [--dir <path>]")
 	fmt.Fprintln(out, "  mcpx shim list [--dir <path>]")
 	fmt.Fprintln(out, "")
 	fmt.Fprintln(out, "Commands:")
-	fmt.Fprintln(out, "  install    Create a command shim that forwards to `mcpx <server> ...`.")
+	fmt.Fprintln(out, "  install    Create a command shim that forwards to `mcpx <server> ...`, or that runs")
+	fmt.Fprintln(out, "             a saved recipe (`mcpx run <recipe> ...`) or one tool (`mcpx <server> <tool> ...`).")
 	fmt.Fprintln(out, "  remove     Delete an installed shim.")
 	fmt.Fprintln(out, "  list       List installed mcpx-managed shims.")
 	fmt.Fprintln(out, "")
@@ -430,6 +491,8 @@ func printShimHelp(out io.Writer) {
 func printShimInstallHelp(out io.Writer) {
 	fmt.Fprintln(out, "Install flags:")
 	fmt.Fprintf(out, "  --dir <path>  Install directory (default: %s)\n", shim.DefaultDir())
+	fmt.Fprintln(out, "  --recipe <recipe>       Make the shim run a recipe saved with --save-as.")
+	fmt.Fprintln(out, "  --tool <server>/<tool>  Make the shim call one tool.")
 	fmt.Fprintln(out, "  --skill                 Also install a generated server skill after shim install succeeds.")
 	fmt.Fprintln(out, "  --skill-strict          Fail if server skill installation fails (requires --skill).")
 	fmt.Fprintf(out, "  --data-agent-dir <path> Skill root (default: %s, requires --skill)\n", skill.DefaultDataAgentDir())
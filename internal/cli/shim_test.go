@@ -176,6 +176,74 @@ func TestRunShimListPrintsInstalledEntries(t *testing.T) {
 	}
 }
 
+func TestMaybeHandleShimCommandInstallsRecipeShimAndListsTarget(t *testing.T) {
+	tmp := t.TempDir()
+	t.Setenv("PATH", tmp)
+
+	cfg := &config.Config{
+		Servers: map[string]config.ServerConfig{"github": {}},
+		Recipes: map[string]config.Recipe{
+			"mcp-repos": {Server: "github", Tool: "search-repositories"},
+		},
+	}
+	var out bytes.Buffer
+	var errOut bytes.Buffer
+	handled, code := maybeHandleShimCommand([]string{"shim", "install", "ghrepos", "--recipe", "mcp-repos", "--dir", tmp}, cfg, &out, &errOut)
+	if !handled || code != ipc.ExitOK {
+		t.Fatalf("install = (%v, %d), want (true, %d); stderr = %q", handled, code, ipc.ExitOK, errOut.String())
+	}
+	if !strings.Contains(out.String(), "(runs mcpx run mcp-repos)") {
+		t.Fatalf("stdout = %q, want recipe target", out.String())
+	}
+
+	out.Reset()
+	handled, code = maybeHandleShimCommand([]string{"shim", "install", "ghsearch", "--tool", "github/search-repositories", "--dir", tmp}, cfg, &out, &errOut)
+	if !handled || code != ipc.ExitOK {
+		t.Fatalf("install --tool = (%v, %d), want (true, %d); stderr = %q", handled, code, ipc.ExitOK, errOut.String())
+	}
+
+	out.Reset()
+	if code := runShimCommand([]string{"list", "--dir", tmp}, &out, &errOut); code != ipc.ExitOK {
+		t.Fatalf("runShimCommand(list) = %d, want %d", code, ipc.ExitOK)
+	}
+	for _, want := range []string{"mcpx run mcp-repos", "mcpx github search-repositories"} {
+		if !strings.Contains(out.String(), want) {
+			t.Fatalf("stdout = %q, want %q", out.String(), want)
+		}
+	}
+}
+
+func TestMaybeHandleShimCommandInstallRejectsUnknownRecipe(t *testing.T) {
+	tmp := t.TempDir()
+	t.Setenv("PATH", tmp)
+
+	cfg := &config.Config{Servers: map[string]config.ServerConfig{}}
+	var errOut bytes.Buffer
+	handled, code := maybeHandleShimCommand([]string{"shim", "install", "ghrepos", "--recipe", "missing", "--dir", tmp}, cfg, &bytes.Buffer{}, &errOut)
+	if !handled || code != ipc.ExitUsageErr {
+		t.Fatalf("install = (%v, %d), want (true, %d)", handled, code, ipc.ExitUsageErr)
+	}
+	if !strings.Contains(errOut.String(), "unknown recipe") {
+		t.Fatalf("stderr = %q, want unknown recipe", errOut.String())
+	}
+	if _, err := os.Stat(filepath.Join(tmp, "ghrepos")); !os.IsNotExist(err) {
+		t.Fatalf("shim stat err = %v, want not exist", err)
+	}
+}
+
+func TestParseShimInstallArgsRejectsInvalidTargets(t *testing.T) {
+	for _, args := range [][]string{
+		{"ghsearch", "--tool", "github"},
+		{"ghsearch", "--tool=/search"},
+		{"ghsearch", "--recipe", "r", "--tool", "github/search"},
+		{"ghsearch", "--recipe", "r", "--skill"},
+	} {
+		if _, err := parseShimInstallArgs(args); err == nil {
+			t.Fatalf("parseShimInstallArgs(%q) error = nil, want error", args)
+		}
+	}
+}
+
 func TestRunShimRemoveDeletesInstalledShim(t *testing.T) {
 	tmp := t.TempDir()
 	t.Setenv("PATH", tmp)
@@ -0,0 +1,31 @@
+package cli
+
+import (
+	"encoding/json"
+	"io"
+	"strings"
+)
+
+// softFailPayload is printed to stdout by --soft-fail in place of a non-zero
+// exit, so pipelines can inspect failures as data.
+type softFailPayload struct {
+	Error softFailError `json:"error"`
+}
+
+type softFailError struct {
+	Message   string `json:"message"`
+	ExitCode  int    `json:"exit_code"`
+	ErrorCode string `json:"error_code,omitempty"`
+}
+
+func writeSoftFailResponse(out io.Writer, exitCode int, errorCode, message string) {
+	data, err := json.Marshal(softFailPayload{Error: softFailError{
+		Message:   strings.TrimSpace(message),
+		ExitCode:  exitCode,
+		ErrorCode: errorCode,
+	}})
+	if err != nil {
+		return
+	}
+	out.Write(append(data, '\n')) //nolint:errcheck
+}
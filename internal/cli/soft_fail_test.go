@@ -0,0 +1,86 @@
+package cli
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/lydakis/mcpx/internal/ipc"
+)
+
+func TestCallToolSoftFailPrintsJSONErrorAndExitsZero(t *testing.T) {
+	oldOut, oldErr := rootStdout, rootStderr
+	defer func() { rootStdout, rootStderr = oldOut, oldErr }()
+	var stdout, stderr bytes.Buffer
+	rootStdout, rootStderr = &stdout, &stderr
+
+	client := stubDaemonClient{
+		sendFn: func(*ipc.Request) (*ipc.Response, error) {
+			return &ipc.Response{ExitCode: ipc.ExitToolErr, Content: []byte("repository not found\n")}, nil
+		},
+	}
+
+	code := callTool(client, "github", "get-repo", []string{"--soft-fail", "--repo=missing"}, "", false)
+	if code != ipc.ExitOK {
+		t.Fatalf("callTool() = %d, want %d", code, ipc.ExitOK)
+	}
+	if stderr.Len() != 0 {
+		t.Fatalf("stderr = %q, want empty", stderr.String())
+	}
+
+	var got softFailPayload
+	if err := json.Unmarshal(stdout.Bytes(), &got); err != nil {
+		t.Fatalf("json.Unmarshal(stdout) error = %v (stdout=%q)", err, stdout.String())
+	}
+	want := softFailError{Message: "repository not found", ExitCode: ipc.ExitToolErr}
+	if got.Error != want {
+		t.Fatalf("soft-fail error = %#v, want %#v", got.Error, want)
+	}
+}
+
+func TestCallToolSoftFailReportsTransportErrors(t *testing.T) {
+	oldOut, oldErr := rootStdout, rootStderr
+	defer func() { rootStdout, rootStderr = oldOut, oldErr }()
+	var stdout, stderr bytes.Buffer
+	rootStdout, rootStderr = &stdout, &stderr
+
+	client := stubDaemonClient{
+		sendFn: func(*ipc.Request) (*ipc.Response, error) {
+			return nil, errors.New("connection refused")
+		},
+	}
+
+	code := callTool(client, "github", "get-repo", []string{"--json-errors-to-stdout"}, "", false)
+	if code != ipc.ExitOK {
+		t.Fatalf("callTool() = %d, want %d", code, ipc.ExitOK)
+	}
+
+	var got softFailPayload
+	if err := json.Unmarshal(stdout.Bytes(), &got); err != nil {
+		t.Fatalf("json.Unmarshal(stdout) error = %v (stdout=%q)", err, stdout.String())
+	}
+	if got.Error.ExitCode != ipc.ExitInternal || got.Error.Message != "connection refused" {
+		t.Fatalf("soft-fail error = %#v, want internal connection refused", got.Error)
+	}
+}
+
+func TestCallToolSoftFailLeavesSuccessOutputUntouched(t *testing.T) {
+	oldOut, oldErr := rootStdout, rootStderr
+	defer func() { rootStdout, rootStderr = oldOut, oldErr }()
+	var stdout, stderr bytes.Buffer
+	rootStdout, rootStderr = &stdout, &stderr
+
+	client := stubDaemonClient{
+		sendFn: func(*ipc.Request) (*ipc.Response, error) {
+			return &ipc.Response{ExitCode: ipc.ExitOK, Content: []byte("ok\n")}, nil
+		},
+	}
+
+	if code := callTool(client, "github", "get-repo", []string{"--soft-fail"}, "", false); code != ipc.ExitOK {
+		t.Fatalf("callTool() = %d, want %d", code, ipc.ExitOK)
+	}
+	if stdout.String() != "ok\n" {
+		t.Fatalf("stdout = %q, want %q", stdout.String(), "ok\n")
+	}
+}
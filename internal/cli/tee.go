@@ -0,0 +1,22 @@
+package cli
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/lydakis/mcpx/internal/ipc"
+)
+
+// writeTeeResponse prints a successful result as usual and also saves the
+// printed bytes to parsed.tee, truncating it, like tee(1).
+func writeTeeResponse(resp *ipc.Response, parsed *toolCallArgs) int {
+	var saved bytes.Buffer
+	writeCallResponse(resp, parsed.quiet, parsed.outputEncoding, io.MultiWriter(rootStdout, &saved), rootStderr)
+	if err := os.WriteFile(parsed.tee, saved.Bytes(), 0o644); err != nil {
+		fmt.Fprintf(rootStderr, "mcpx: writing --tee: %v\n", err)
+		return ipc.ExitInternal
+	}
+	return ipc.ExitOK
+}
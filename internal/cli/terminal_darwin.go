@@ -0,0 +1,31 @@
+//go:build darwin
+
+package cli
+
+import (
+	"os"
+
+	"golang.org/x/sys/unix"
+)
+
+func isTerminal(file *os.File) bool {
+	_, err := unix.IoctlGetTermios(int(file.Fd()), unix.TIOCGETA)
+	return err == nil
+}
+
+// disableEcho turns off terminal echo on file and returns a func restoring
+// the previous mode.
+func disableEcho(file *os.File) (func(), error) {
+	fd := int(file.Fd())
+	saved, err := unix.IoctlGetTermios(fd, unix.TIOCGETA)
+	if err != nil {
+		return nil, err
+	}
+	silent := *saved
+	silent.Lflag &^= unix.ECHO
+	silent.Lflag |= unix.ICANON
+	if err := unix.IoctlSetTermios(fd, unix.TIOCSETA, &silent); err != nil {
+		return nil, err
+	}
+	return func() { _ = unix.IoctlSetTermios(fd, unix.TIOCSETA, saved) }, nil
+}
@@ -0,0 +1,16 @@
+//go:build !linux && !darwin
+
+package cli
+
+import (
+	"errors"
+	"os"
+)
+
+func isTerminal(file *os.File) bool {
+	return false
+}
+
+func disableEcho(file *os.File) (func(), error) {
+	return nil, errors.New("hiding terminal input is not supported on this platform")
+}
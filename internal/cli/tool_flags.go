@@ -2,6 +2,9 @@ package cli
 
 import "strings"
 
+// toolFlagNames returns the CLI flag spelling for a schema property. Boolean
+// properties also get a --no-<name> negation; both forms accept an explicit
+// =true, =false, or =null (omit the argument) value.
 func toolFlagNames(name, typ string) (base string, negative string) {
 	prefix := ""
 	if isReservedToolFlagName(name) {
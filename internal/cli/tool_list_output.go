@@ -8,11 +8,26 @@ import (
 	"sort"
 	"strings"
 	"text/tabwriter"
+
+	"github.com/lydakis/mcpx/internal/config"
+	"github.com/lydakis/mcpx/internal/ipc"
 )
 
 type toolListEntry struct {
 	Name        string `json:"name"`
 	Description string `json:"description,omitempty"`
+	// Uses is the daemon's call count for the tool; it is kept in output
+	// only with --by-usage.
+	Uses int `json:"uses,omitempty"`
+}
+
+// sortToolListByUsage orders entries most-called first. The sort is stable,
+// so tools with equal counts (including all of them when there is no usage
+// data yet) keep the daemon's alphabetical order.
+func sortToolListByUsage(entries []toolListEntry) {
+	sort.SliceStable(entries, func(i, j int) bool {
+		return entries[i].Uses > entries[j].Uses
+	})
 }
 
 func decodeToolListPayload(raw []byte) ([]toolListEntry, error) {
@@ -74,3 +89,69 @@ func toolListNames(entries []toolListEntry) []string {
 	sort.Strings(out)
 	return out
 }
+
+// toolListShape selects an alternate plain-text rendering for tool listings.
+type toolListShape int
+
+const (
+	toolListShapeDefault toolListShape = iota
+	toolListShapeNames
+	toolListShapeDescriptions
+)
+
+// writeToolListShaped renders entries as one name per line (names-only) or
+// as `name: description` lines (descriptions-only). Entries without a
+// description print just the name.
+func writeToolListShaped(w io.Writer, entries []toolListEntry, shape toolListShape) error {
+	if shape == toolListShapeDefault {
+		return writeToolListText(w, entries)
+	}
+	for _, entry := range entries {
+		name := strings.TrimSpace(entry.Name)
+		if name == "" {
+			continue
+		}
+		line := name
+		if desc := strings.TrimSpace(entry.Description); desc != "" && shape == toolListShapeDescriptions {
+			line = name + ": " + desc
+		}
+		if _, err := fmt.Fprintln(w, line); err != nil {
+			return fmt.Errorf("writing tool list output: %w", err)
+		}
+	}
+	return nil
+}
+
+// toolListFullPayload is the --json-full tool listing: the tool entries
+// alongside the server they came from.
+type toolListFullPayload struct {
+	Server toolListServer  `json:"server"`
+	Tools  []toolListEntry `json:"tools"`
+}
+
+type toolListServer struct {
+	Name      string               `json:"name"`
+	Transport string               `json:"transport,omitempty"`
+	Origin    *config.ServerOrigin `json:"origin,omitempty"`
+}
+
+// fetchToolListServer looks server up in the daemon's server list for
+// --json-full. Servers the list does not include, or a failed lookup, yield
+// just the name.
+func fetchToolListServer(client daemonRequester, server, cwd string) toolListServer {
+	meta := toolListServer{Name: server}
+	resp, err := client.Send(&ipc.Request{Type: "list_servers", CWD: cwd, IncludeHidden: true})
+	if err != nil || resp.ExitCode != ipc.ExitOK {
+		return meta
+	}
+	for _, entry := range decodeServerListEntries(resp.Content) {
+		if entry.Name != server {
+			continue
+		}
+		origin := entry.Origin
+		meta.Transport = entry.Transport
+		meta.Origin = &origin
+		break
+	}
+	return meta
+}
@@ -5,6 +5,8 @@ import (
 	"encoding/json"
 	"strings"
 	"testing"
+
+	"github.com/lydakis/mcpx/internal/ipc"
 )
 
 func TestDecodeToolListPayloadRejectsLegacyText(t *testing.T) {
@@ -76,3 +78,92 @@ func TestWriteToolListTextSkipsBlankEntryNames(t *testing.T) {
 		t.Fatalf("writeToolListText() output = %q, want empty", got)
 	}
 }
+
+func TestWriteToolListShapedNamesAndDescriptionsOnly(t *testing.T) {
+	entries := []toolListEntry{
+		{Name: "list_issues", Description: "List issues"},
+		{Name: "  "},
+		{Name: "search_repositories"},
+	}
+
+	var names bytes.Buffer
+	if err := writeToolListShaped(&names, entries, toolListShapeNames); err != nil {
+		t.Fatalf("writeToolListShaped(names) error = %v", err)
+	}
+	if got, want := names.String(), "list_issues\nsearch_repositories\n"; got != want {
+		t.Fatalf("names-only output = %q, want %q", got, want)
+	}
+
+	var descs bytes.Buffer
+	if err := writeToolListShaped(&descs, entries, toolListShapeDescriptions); err != nil {
+		t.Fatalf("writeToolListShaped(descriptions) error = %v", err)
+	}
+	if got, want := descs.String(), "list_issues: List issues\nsearch_repositories\n"; got != want {
+		t.Fatalf("descriptions-only output = %q, want %q", got, want)
+	}
+}
+
+func TestListToolsByUsageSortsMostCalledFirst(t *testing.T) {
+	oldOut, oldErr := rootStdout, rootStderr
+	defer func() { rootStdout, rootStderr = oldOut, oldErr }()
+	var stdout, stderr bytes.Buffer
+	rootStdout, rootStderr = &stdout, &stderr
+
+	payload := []byte(`[{"name":"a_tool"},{"name":"b_tool","uses":1},{"name":"c_tool"},{"name":"d_tool","uses":5}]`)
+	client := stubDaemonClient{sendFn: func(req *ipc.Request) (*ipc.Response, error) {
+		return &ipc.Response{Content: payload}, nil
+	}}
+
+	cmd, err := parseServerCommand([]string{"--by-usage", "--names-only"}, outputModeText)
+	if err != nil || !cmd.list || !cmd.listOpts.byUsage {
+		t.Fatalf("parseServerCommand(--by-usage) = %+v, %v", cmd, err)
+	}
+	if code := listToolsWithArgs(client, "github", "", cmd.listOpts, false); code != ipc.ExitOK {
+		t.Fatalf("listToolsWithArgs() = %d (stderr=%q)", code, stderr.String())
+	}
+	if got, want := stdout.String(), "d_tool\nb_tool\na_tool\nc_tool\n"; got != want {
+		t.Fatalf("by-usage list = %q, want %q", got, want)
+	}
+
+	stdout.Reset()
+	if code := listToolsWithArgs(client, "github", "", toolListArgs{output: outputModeJSON}, false); code != ipc.ExitOK {
+		t.Fatalf("listToolsWithArgs(json) = %d (stderr=%q)", code, stderr.String())
+	}
+	if got, want := stdout.String(), `[{"name":"a_tool"},{"name":"b_tool"},{"name":"c_tool"},{"name":"d_tool"}]`+"\n"; got != want {
+		t.Fatalf("default JSON list = %q, want %q (alphabetical, no uses)", got, want)
+	}
+}
+
+func TestListToolsJSONFullWrapsToolsWithServerMetadata(t *testing.T) {
+	oldOut, oldErr := rootStdout, rootStderr
+	defer func() { rootStdout, rootStderr = oldOut, oldErr }()
+	var stdout, stderr bytes.Buffer
+	rootStdout, rootStderr = &stdout, &stderr
+
+	client := stubDaemonClient{sendFn: func(req *ipc.Request) (*ipc.Response, error) {
+		if req.Type == "list_servers" {
+			return &ipc.Response{Content: []byte(`[{"name":"docs","origin":{"kind":"mcpx_config"},"transport":"stdio"},` +
+				`{"name":"github","origin":{"kind":"cursor","path":"/home/me/.cursor/mcp.json"},"transport":"http","state":"idle"}]`)}, nil
+		}
+		return &ipc.Response{Content: []byte(`[{"name":"search","description":"Search repos"}]`)}, nil
+	}}
+
+	cmd, err := parseServerCommand([]string{"--json-full"}, outputModeText)
+	if err != nil || !cmd.list || !cmd.listOpts.full {
+		t.Fatalf("parseServerCommand(--json-full) = %+v, %v", cmd, err)
+	}
+	if code := listToolsWithArgs(client, "github", "", cmd.listOpts, false); code != ipc.ExitOK {
+		t.Fatalf("listToolsWithArgs() = %d (stderr=%q)", code, stderr.String())
+	}
+	want := `{"server":{"name":"github","transport":"http","origin":{"kind":"cursor","path":"/home/me/.cursor/mcp.json"}},` +
+		`"tools":[{"name":"search","description":"Search repos"}]}` + "\n"
+	if got := stdout.String(); got != want {
+		t.Fatalf("--json-full output = %q, want %q", got, want)
+	}
+
+	for _, args := range [][]string{{"--json-full", "--yaml"}, {"--json-full", "--names-only"}} {
+		if _, err := parseToolListArgs(args, outputModeText); err == nil {
+			t.Fatalf("parseToolListArgs(%v) error = nil, want non-nil", args)
+		}
+	}
+}
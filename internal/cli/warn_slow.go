@@ -0,0 +1,16 @@
+package cli
+
+import (
+	"fmt"
+	"time"
+)
+
+// warnSlowCall prints a stderr warning when a call took longer than its
+// --warn-slow threshold. The result is still printed and the exit code is
+// unchanged, so scripts can spot slowdowns without failing.
+func warnSlowCall(server, tool string, elapsed time.Duration, parsed *toolCallArgs) {
+	if parsed.warnSlow == nil || parsed.quiet || elapsed <= *parsed.warnSlow {
+		return
+	}
+	fmt.Fprintf(rootStderr, "mcpx: warning: %s %s took %s (over --warn-slow %s)\n", server, tool, elapsed.Round(time.Millisecond), *parsed.warnSlow)
+}
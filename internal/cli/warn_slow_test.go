@@ -0,0 +1,50 @@
+package cli
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/lydakis/mcpx/internal/ipc"
+)
+
+func TestCallToolWarnSlowWarnsAndStillPrintsResult(t *testing.T) {
+	oldOut, oldErr := rootStdout, rootStderr
+	defer func() { rootStdout, rootStderr = oldOut, oldErr }()
+	var stdout, stderr bytes.Buffer
+	rootStdout, rootStderr = &stdout, &stderr
+	stubConfirmTerminal(t, false, "")
+
+	client := stubDaemonClient{sendFn: func(req *ipc.Request) (*ipc.Response, error) {
+		time.Sleep(30 * time.Millisecond)
+		return &ipc.Response{Content: []byte("done\n")}, nil
+	}}
+
+	if code := callTool(client, "svc", "slow", []string{"--warn-slow", "5ms"}, "", false); code != ipc.ExitOK {
+		t.Fatalf("callTool() = %d, want %d (stderr=%q)", code, ipc.ExitOK, stderr.String())
+	}
+	if stdout.String() != "done\n" {
+		t.Fatalf("stdout = %q, want result printed", stdout.String())
+	}
+	if !strings.Contains(stderr.String(), "mcpx: warning: svc slow took ") || !strings.Contains(stderr.String(), "(over --warn-slow 5ms)") {
+		t.Fatalf("stderr = %q, want slow call warning", stderr.String())
+	}
+
+	stdout.Reset()
+	stderr.Reset()
+	if code := callTool(client, "svc", "slow", []string{"--max-response-time-warn=1m"}, "", false); code != ipc.ExitOK {
+		t.Fatalf("callTool(under threshold) = %d, want %d", code, ipc.ExitOK)
+	}
+	if stderr.Len() != 0 {
+		t.Fatalf("stderr = %q, want no warning under the threshold", stderr.String())
+	}
+}
+
+func TestParseToolCallArgsRejectsInvalidWarnSlow(t *testing.T) {
+	for _, args := range [][]string{{"--warn-slow"}, {"--warn-slow=0s"}, {"--warn-slow", "soon"}} {
+		if _, err := parseToolCallArgs(args, bytes.NewBuffer(nil), true, outputModeText); err == nil {
+			t.Fatalf("parseToolCallArgs(%v) error = nil, want non-nil", args)
+		}
+	}
+}
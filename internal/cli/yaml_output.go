@@ -0,0 +1,244 @@
+package cli
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// yamlField is one key of a decoded JSON object, kept in document order so
+// YAML output lists fields in the same order as the JSON output.
+type yamlField struct {
+	key   string
+	value any
+}
+
+// writeYAMLLine encodes payload as JSON and writes it back out as a YAML
+// document, so --yaml output has the same shape as --json.
+func writeYAMLLine(w io.Writer, payload any) error {
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return fmt.Errorf("encoding yaml output: %w", err)
+	}
+	return writeYAMLFromJSON(w, data)
+}
+
+// writeYAMLFromJSON re-encodes a JSON document as YAML.
+func writeYAMLFromJSON(w io.Writer, data []byte) error {
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+	value, err := decodeOrderedJSON(dec)
+	if err != nil {
+		return fmt.Errorf("encoding yaml output: %w", err)
+	}
+
+	var buf bytes.Buffer
+	writeYAMLNode(&buf, value, 0)
+	if _, err := w.Write(buf.Bytes()); err != nil {
+		return fmt.Errorf("writing yaml output: %w", err)
+	}
+	return nil
+}
+
+// decodeOrderedJSON decodes the next JSON value, returning objects as
+// []yamlField, arrays as []any, and numbers as json.Number.
+func decodeOrderedJSON(dec *json.Decoder) (any, error) {
+	tok, err := dec.Token()
+	if err != nil {
+		return nil, err
+	}
+	delim, ok := tok.(json.Delim)
+	if !ok {
+		return tok, nil
+	}
+
+	switch delim {
+	case '{':
+		fields := []yamlField{}
+		for dec.More() {
+			keyTok, err := dec.Token()
+			if err != nil {
+				return nil, err
+			}
+			key, _ := keyTok.(string)
+			value, err := decodeOrderedJSON(dec)
+			if err != nil {
+				return nil, err
+			}
+			fields = append(fields, yamlField{key: key, value: value})
+		}
+		if _, err := dec.Token(); err != nil {
+			return nil, err
+		}
+		return fields, nil
+	case '[':
+		items := []any{}
+		for dec.More() {
+			item, err := decodeOrderedJSON(dec)
+			if err != nil {
+				return nil, err
+			}
+			items = append(items, item)
+		}
+		if _, err := dec.Token(); err != nil {
+			return nil, err
+		}
+		return items, nil
+	default:
+		return nil, fmt.Errorf("unexpected JSON delimiter %q", delim)
+	}
+}
+
+// writeYAMLNode writes value as block YAML with nested levels indented two
+// spaces per level. Empty objects and arrays use flow style.
+func writeYAMLNode(buf *bytes.Buffer, value any, indent int) {
+	pad := strings.Repeat(" ", indent)
+	switch v := value.(type) {
+	case []yamlField:
+		if len(v) == 0 {
+			buf.WriteString(pad + "{}\n")
+			return
+		}
+		for _, field := range v {
+			buf.WriteString(pad + yamlScalar(field.key) + ":")
+			writeYAMLChild(buf, field.value, indent)
+		}
+	case []any:
+		if len(v) == 0 {
+			buf.WriteString(pad + "[]\n")
+			return
+		}
+		for _, item := range v {
+			if fields, ok := item.([]yamlField); ok && len(fields) > 0 {
+				// Inline the first field after the dash; the rest align
+				// under it.
+				var nested bytes.Buffer
+				writeYAMLNode(&nested, fields, indent+2)
+				buf.WriteString(pad + "- ")
+				buf.Write(nested.Bytes()[indent+2:])
+				continue
+			}
+			buf.WriteString(pad + "-")
+			writeYAMLChild(buf, item, indent)
+		}
+	default:
+		buf.WriteString(pad)
+		writeYAMLScalarValue(buf, value, indent)
+	}
+}
+
+// writeYAMLChild writes the value of a mapping key or sequence item whose
+// "key:" or "-" has already been written.
+func writeYAMLChild(buf *bytes.Buffer, value any, indent int) {
+	switch v := value.(type) {
+	case []yamlField:
+		if len(v) == 0 {
+			buf.WriteString(" {}\n")
+			return
+		}
+		buf.WriteString("\n")
+		writeYAMLNode(buf, v, indent+2)
+	case []any:
+		if len(v) == 0 {
+			buf.WriteString(" []\n")
+			return
+		}
+		buf.WriteString("\n")
+		writeYAMLNode(buf, v, indent+2)
+	default:
+		buf.WriteString(" ")
+		writeYAMLScalarValue(buf, value, indent)
+	}
+}
+
+// writeYAMLScalarValue writes a scalar and its trailing newline. Multi-line
+// strings use a literal block indented under the current level.
+func writeYAMLScalarValue(buf *bytes.Buffer, value any, indent int) {
+	switch v := value.(type) {
+	case nil:
+		buf.WriteString("null\n")
+	case bool:
+		fmt.Fprintf(buf, "%t\n", v)
+	case json.Number:
+		buf.WriteString(v.String() + "\n")
+	case string:
+		if header, ok := yamlLiteralHeader(v); ok {
+			pad := strings.Repeat(" ", indent+2)
+			buf.WriteString(header + "\n")
+			for _, line := range strings.Split(strings.TrimSuffix(v, "\n"), "\n") {
+				if line == "" {
+					buf.WriteString("\n")
+					continue
+				}
+				buf.WriteString(pad + line + "\n")
+			}
+			return
+		}
+		buf.WriteString(yamlScalar(v) + "\n")
+	default:
+		buf.WriteString(yamlScalar(fmt.Sprint(v)) + "\n")
+	}
+}
+
+// yamlLiteralHeader reports whether s can be written as a literal block
+// scalar and returns the block header that keeps its trailing newline.
+func yamlLiteralHeader(s string) (string, bool) {
+	if !strings.Contains(strings.TrimSuffix(s, "\n"), "\n") {
+		return "", false
+	}
+	if strings.HasPrefix(s, " ") || strings.HasSuffix(s, "\n\n") || strings.ContainsAny(s, "\r\t") {
+		return "", false
+	}
+	for _, r := range s {
+		if r != '\n' && (r < 0x20 || r == 0x7f) {
+			return "", false
+		}
+	}
+	for _, line := range strings.Split(s, "\n") {
+		if strings.HasSuffix(line, " ") {
+			return "", false
+		}
+	}
+	if strings.HasSuffix(s, "\n") {
+		return "|", true
+	}
+	return "|-", true
+}
+
+// yamlScalar returns s as a plain YAML scalar when that reads back as the
+// same string, and as a double-quoted scalar otherwise.
+func yamlScalar(s string) string {
+	if yamlNeedsQuotes(s) {
+		var quoted bytes.Buffer
+		enc := json.NewEncoder(&quoted)
+		enc.SetEscapeHTML(false)
+		_ = enc.Encode(s)
+		return strings.TrimSuffix(quoted.String(), "\n")
+	}
+	return s
+}
+
+func yamlNeedsQuotes(s string) bool {
+	if s == "" || s != strings.TrimSpace(s) {
+		return true
+	}
+	switch strings.ToLower(s) {
+	case "null", "~", "true", "false", "yes", "no", "on", "off", "y", "n":
+		return true
+	}
+	first := s[0]
+	if (first >= '0' && first <= '9') || strings.IndexByte("-?:,[]{}#&*!|>'\"%@`.+=", first) >= 0 {
+		return true
+	}
+	if strings.HasSuffix(s, ":") || strings.Contains(s, ": ") || strings.Contains(s, " #") {
+		return true
+	}
+	for _, r := range s {
+		if r < 0x20 || r == 0x7f || r == '\u00a0' || r == '\u2028' || r == '\u2029' || r == '\ufeff' {
+			return true
+		}
+	}
+	return false
+}
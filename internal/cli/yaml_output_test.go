@@ -0,0 +1,52 @@
+package cli
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestWriteYAMLFromJSONKeepsOrderAndQuotesAmbiguousScalars(t *testing.T) {
+	input := `{"name":"search","enabled":true,"count":3,"empty":{},"tags":[],"note":null,` +
+		`"description":"Find things.\nUse --query.","values":["yes","10","a: b","plain"],` +
+		`"tools":[{"name":"a","uses":2},{"name":"b","args":{"q":"&x"}}],"nested":[[1,2]]}`
+	want := `name: search
+enabled: true
+count: 3
+empty: {}
+tags: []
+note: null
+description: |-
+  Find things.
+  Use --query.
+values:
+  - "yes"
+  - "10"
+  - "a: b"
+  - plain
+tools:
+  - name: a
+    uses: 2
+  - name: b
+    args:
+      q: "&x"
+nested:
+  -
+    - 1
+    - 2
+`
+
+	var out bytes.Buffer
+	if err := writeYAMLFromJSON(&out, []byte(input)); err != nil {
+		t.Fatalf("writeYAMLFromJSON() error = %v", err)
+	}
+	if got := out.String(); got != want {
+		t.Fatalf("writeYAMLFromJSON() =\n%s\nwant:\n%s", got, want)
+	}
+}
+
+func TestWriteYAMLFromJSONRejectsInvalidJSON(t *testing.T) {
+	var out bytes.Buffer
+	if err := writeYAMLFromJSON(&out, []byte(`{"name":`)); err == nil {
+		t.Fatal("writeYAMLFromJSON(invalid) error = nil, want non-nil")
+	}
+}
@@ -0,0 +1,24 @@
+package config
+
+import "fmt"
+
+// Cache modes select how a server's expired cache entries are served.
+const (
+	// CacheModeStrict treats an expired entry as a miss.
+	CacheModeStrict = "strict"
+	// CacheModeStaleWhileRevalidate serves an entry that expired less than
+	// stale_ttl ago and refreshes it in the background.
+	CacheModeStaleWhileRevalidate = "stale_while_revalidate"
+)
+
+// ParseCacheMode normalizes a cache mode name. Empty means strict.
+func ParseCacheMode(raw string) (string, error) {
+	switch raw {
+	case "", CacheModeStrict:
+		return CacheModeStrict, nil
+	case CacheModeStaleWhileRevalidate:
+		return CacheModeStaleWhileRevalidate, nil
+	default:
+		return "", fmt.Errorf("invalid cache mode %q (want %s or %s)", raw, CacheModeStrict, CacheModeStaleWhileRevalidate)
+	}
+}
@@ -0,0 +1,25 @@
+package config
+
+import "fmt"
+
+// Cache scopes select what a cached tool response is keyed by.
+const (
+	// CacheScopeGlobal keys responses by server, tool, and arguments only.
+	CacheScopeGlobal = "global"
+	// CacheScopeCWD also keys responses by the request directory and the
+	// resolved server definition, so per-project servers that share a name
+	// never read each other's entries.
+	CacheScopeCWD = "cwd"
+)
+
+// ParseCacheScope normalizes a cache scope name. Empty means global.
+func ParseCacheScope(raw string) (string, error) {
+	switch raw {
+	case "", CacheScopeGlobal:
+		return CacheScopeGlobal, nil
+	case CacheScopeCWD:
+		return CacheScopeCWD, nil
+	default:
+		return "", fmt.Errorf("invalid cache scope %q (want %s or %s)", raw, CacheScopeGlobal, CacheScopeCWD)
+	}
+}
@@ -11,10 +11,35 @@ import (
 
 var envVarRe = regexp.MustCompile(`\$\{([A-Za-z_][A-Za-z0-9_]*)\}`)
 
-// Load reads the config file and returns the parsed Config.
+// Load reads the config file and returns the parsed Config, including
+// servers defined via MCPX_SERVER_<NAME>_* environment variables.
 // If the config file does not exist, it returns an empty Config (no error).
 func Load() (*Config, error) {
-	return LoadFrom(paths.ConfigFile())
+	cfg, err := LoadWithoutEnvServers()
+	if err != nil {
+		return nil, err
+	}
+	if err := MergeEnvServers(cfg, os.Environ()); err != nil {
+		return nil, fmt.Errorf("loading env-defined servers: %w", err)
+	}
+	return cfg, nil
+}
+
+// LoadWithoutEnvServers is Load without the MCPX_SERVER_<NAME>_* servers.
+// The daemon loads config this way because each CLI request carries the
+// env-defined servers of its own environment.
+func LoadWithoutEnvServers() (*Config, error) {
+	cfg, err := LoadFrom(paths.ConfigFile())
+	if err != nil {
+		return nil, err
+	}
+	if err := applyMaxConnectionsEnv(cfg, os.Environ()); err != nil {
+		return nil, err
+	}
+	if err := applyDefaultOutputEnv(cfg, os.Environ()); err != nil {
+		return nil, err
+	}
+	return cfg, nil
 }
 
 // LoadForEdit reads the config file for in-place edits.
@@ -53,7 +78,16 @@ func loadFrom(path string, expand bool) (*Config, error) {
 	}
 
 	var cfg Config
-	if err := toml.Unmarshal(data, &cfg); err != nil {
+	list, err := usesServerList(data)
+	if err != nil {
+		return nil, fmt.Errorf("parsing config %s: %w", path, err)
+	}
+	if list {
+		cfg, err = decodeServerList(data)
+	} else {
+		err = toml.Unmarshal(data, &cfg)
+	}
+	if err != nil {
 		return nil, fmt.Errorf("parsing config %s: %w", path, err)
 	}
 	if cfg.Servers == nil {
@@ -99,6 +133,12 @@ func expandServerEnvVars(srv ServerConfig) ServerConfig {
 	for i := range srv.NoCacheTools {
 		srv.NoCacheTools[i] = expandEnvVars(srv.NoCacheTools[i])
 	}
+	for i := range srv.AllowTools {
+		srv.AllowTools[i] = expandEnvVars(srv.AllowTools[i])
+	}
+	for i := range srv.DenyTools {
+		srv.DenyTools[i] = expandEnvVars(srv.DenyTools[i])
+	}
 	for k, v := range srv.Env {
 		srv.Env[k] = expandEnvVars(v)
 	}
@@ -111,11 +151,21 @@ func expandServerEnvVars(srv ServerConfig) ServerConfig {
 
 // expandEnvVars replaces ${VAR_NAME} with the value of the environment variable.
 func expandEnvVars(s string) string {
-	return envVarRe.ReplaceAllStringFunc(s, func(match string) string {
+	expanded, _ := ExpandPlaceholders(s, os.LookupEnv)
+	return expanded
+}
+
+// ExpandPlaceholders replaces ${NAME} in s with lookup(NAME). Names lookup
+// cannot resolve are left in place and returned, in order of appearance.
+func ExpandPlaceholders(s string, lookup func(string) (string, bool)) (string, []string) {
+	var missing []string
+	expanded := envVarRe.ReplaceAllStringFunc(s, func(match string) string {
 		name := envVarRe.FindStringSubmatch(match)[1]
-		if val, ok := os.LookupEnv(name); ok {
+		if val, ok := lookup(name); ok {
 			return val
 		}
+		missing = append(missing, name)
 		return match // leave unresolved vars as-is
 	})
+	return expanded, missing
 }
@@ -58,6 +58,71 @@ fallback_sources = ["${HOME}/custom/mcp.json"]
 	}
 }
 
+func TestLoadFromReadsServersArrayOfTables(t *testing.T) {
+	t.Setenv("API_TOKEN", "secret")
+
+	path := filepath.Join(t.TempDir(), "config.toml")
+	const raw = `
+max_connections = 3
+
+[[servers]]
+name = "github"
+command = "gh-mcp"
+args = ["--stdio"]
+
+[servers.tools.create_issue]
+idempotency_key = "arg:request_id"
+
+[[servers]]
+name = "linear"
+url = "https://linear.example.com/mcp"
+headers = { Authorization = "Bearer ${API_TOKEN}" }
+`
+	if err := os.WriteFile(path, []byte(raw), 0600); err != nil {
+		t.Fatalf("writing config: %v", err)
+	}
+
+	cfg, err := LoadFrom(path)
+	if err != nil {
+		t.Fatalf("LoadFrom() error = %v", err)
+	}
+	if len(cfg.Servers) != 2 {
+		t.Fatalf("servers = %v, want github and linear", cfg.Servers)
+	}
+	github := cfg.Servers["github"]
+	if github.Command != "gh-mcp" || len(github.Args) != 1 || github.Tools["create_issue"].IdempotencyKey != "arg:request_id" {
+		t.Fatalf("github = %+v, want command, args, and tool override from [[servers]]", github)
+	}
+	if got := cfg.Servers["linear"].Headers["Authorization"]; got != "Bearer secret" {
+		t.Fatalf("linear Authorization = %q, want expanded placeholder", got)
+	}
+	if cfg.MaxConnections != 3 {
+		t.Fatalf("MaxConnections = %d, want top-level keys kept alongside [[servers]]", cfg.MaxConnections)
+	}
+	if got := cfg.ServerOrigins["linear"].Kind; got != ServerOriginKindMCPXConfig {
+		t.Fatalf("linear origin = %q, want %q", got, ServerOriginKindMCPXConfig)
+	}
+	if err := Validate(cfg); err != nil {
+		t.Fatalf("Validate() error = %v", err)
+	}
+}
+
+func TestLoadFromRejectsInvalidServersArrayEntries(t *testing.T) {
+	cases := map[string]string{
+		"duplicate name": "[[servers]]\nname = \"a\"\ncommand = \"x\"\n\n[[servers]]\nname = \"a\"\ncommand = \"y\"\n",
+		"missing name":   "[[servers]]\ncommand = \"x\"\n",
+	}
+	for name, raw := range cases {
+		path := filepath.Join(t.TempDir(), "config.toml")
+		if err := os.WriteFile(path, []byte(raw), 0600); err != nil {
+			t.Fatalf("writing config: %v", err)
+		}
+		if _, err := LoadFrom(path); err == nil {
+			t.Errorf("%s: LoadFrom() error = nil, want non-nil", name)
+		}
+	}
+}
+
 func TestLoadWrapperReadsFromDefaultPath(t *testing.T) {
 	configHome := t.TempDir()
 	t.Setenv("XDG_CONFIG_HOME", configHome)
@@ -0,0 +1,50 @@
+package config
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Output modes for default_output.
+const (
+	OutputText = "text"
+	OutputJSON = "json"
+)
+
+// DefaultOutputEnvVar overrides default_output from the environment.
+const DefaultOutputEnvVar = "MCPX_OUTPUT"
+
+// ParseDefaultOutput normalizes a default output mode. Empty means text.
+func ParseDefaultOutput(raw string) (string, error) {
+	switch strings.ToLower(strings.TrimSpace(raw)) {
+	case "", OutputText:
+		return OutputText, nil
+	case OutputJSON:
+		return OutputJSON, nil
+	default:
+		return "", fmt.Errorf("invalid output mode %q (want %s or %s)", raw, OutputText, OutputJSON)
+	}
+}
+
+// applyDefaultOutputEnv sets cfg.DefaultOutput from MCPX_OUTPUT in environ
+// (KEY=VALUE form), which wins over the config file.
+func applyDefaultOutputEnv(cfg *Config, environ []string) error {
+	if cfg == nil {
+		return nil
+	}
+	prefix := DefaultOutputEnvVar + "="
+	for _, entry := range environ {
+		if !strings.HasPrefix(entry, prefix) {
+			continue
+		}
+		raw := strings.TrimSpace(strings.TrimPrefix(entry, prefix))
+		if raw == "" {
+			continue
+		}
+		if _, err := ParseDefaultOutput(raw); err != nil {
+			return fmt.Errorf("%s: %w", DefaultOutputEnvVar, err)
+		}
+		cfg.DefaultOutput = raw
+	}
+	return nil
+}
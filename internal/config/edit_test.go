@@ -72,6 +72,49 @@ func TestSaveToWritesConfigAndCreatesParentDir(t *testing.T) {
 	}
 }
 
+func TestSaveToKeepsServersArrayOfTables(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.toml")
+	const raw = `
+max_connections = 3
+
+[[servers]]
+name = "github"
+command = "gh-mcp"
+`
+	if err := os.WriteFile(path, []byte(raw), 0600); err != nil {
+		t.Fatalf("writing config: %v", err)
+	}
+
+	cfg, err := LoadForEditFrom(path)
+	if err != nil {
+		t.Fatalf("LoadForEditFrom() error = %v", err)
+	}
+	cfg.Servers["apify"] = ServerConfig{URL: "https://mcp.apify.com"}
+	if err := SaveTo(path, cfg); err != nil {
+		t.Fatalf("SaveTo() error = %v", err)
+	}
+
+	saved, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("ReadFile() error = %v", err)
+	}
+	text := string(saved)
+	if !strings.Contains(text, "[[servers]]") || strings.Contains(text, "[servers.") {
+		t.Fatalf("saved config = %q, want [[servers]] form kept", text)
+	}
+	if strings.Index(text, `name = "apify"`) > strings.Index(text, `name = "github"`) {
+		t.Fatalf("saved config = %q, want entries in name order", text)
+	}
+
+	reloaded, err := LoadFrom(path)
+	if err != nil {
+		t.Fatalf("LoadFrom() error = %v", err)
+	}
+	if len(reloaded.Servers) != 2 || reloaded.Servers["github"].Command != "gh-mcp" || reloaded.MaxConnections != 3 {
+		t.Fatalf("reloaded config = %+v, want both servers and max_connections", reloaded)
+	}
+}
+
 func TestValidateForCurrentEnvExpandsWithoutMutatingSource(t *testing.T) {
 	t.Setenv("MCP_URL", "https://example.com/mcp")
 
@@ -144,3 +187,41 @@ func TestSaveHandlesNilConfig(t *testing.T) {
 		t.Fatal("LoadForEdit() = nil after Save(nil), want non-nil")
 	}
 }
+
+func TestSetToolDefaultRoundTripsThroughSave(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.toml")
+	const raw = `
+[servers.github]
+command = "gh-mcp"
+env = { GITHUB_TOKEN = "${GITHUB_TOKEN}" }
+`
+	if err := os.WriteFile(path, []byte(raw), 0o600); err != nil {
+		t.Fatalf("WriteFile() error = %v", err)
+	}
+
+	cfg, err := LoadForEditFrom(path)
+	if err != nil {
+		t.Fatalf("LoadForEditFrom() error = %v", err)
+	}
+	if err := SetToolDefault(cfg, "github", "search", "limit", "10"); err != nil {
+		t.Fatalf("SetToolDefault() error = %v", err)
+	}
+	if err := SetToolDefault(cfg, "linear", "search", "limit", "10"); err == nil {
+		t.Fatal("SetToolDefault(unknown server) error = nil, want non-nil")
+	}
+	if err := SaveTo(path, cfg); err != nil {
+		t.Fatalf("SaveTo() error = %v", err)
+	}
+
+	reloaded, err := LoadForEditFrom(path)
+	if err != nil {
+		t.Fatalf("LoadForEditFrom(reloaded) error = %v", err)
+	}
+	srv := reloaded.Servers["github"]
+	if got := srv.Tools["search"].Defaults["limit"]; got != "10" {
+		t.Fatalf("defaults[limit] = %#v, want %q", got, "10")
+	}
+	if got := srv.Env["GITHUB_TOKEN"]; got != "${GITHUB_TOKEN}" {
+		t.Fatalf("env GITHUB_TOKEN = %q, want placeholder preserved", got)
+	}
+}
@@ -0,0 +1,153 @@
+package config
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// envServerPrefix introduces server definitions in the environment, e.g.
+// MCPX_SERVER_GITHUB_COMMAND=gh-mcp defines server "github".
+const envServerPrefix = "MCPX_SERVER_"
+
+// envServerFields maps variable suffixes to the ServerConfig field they set.
+// Suffixes are matched longest first so names may contain underscores.
+var envServerFields = []struct {
+	suffix string
+	apply  func(srv *ServerConfig, value string) error
+}{
+	{"_DEFAULT_CACHE_TTL", func(srv *ServerConfig, value string) error {
+		srv.DefaultCacheTTL = value
+		return nil
+	}},
+	{"_COMMAND", func(srv *ServerConfig, value string) error {
+		srv.Command = value
+		return nil
+	}},
+	{"_HEADERS", func(srv *ServerConfig, value string) error {
+		return decodeEnvStringMap(value, &srv.Headers)
+	}},
+	{"_ARGS", func(srv *ServerConfig, value string) error {
+		return decodeEnvArgs(value, &srv.Args)
+	}},
+	{"_URL", func(srv *ServerConfig, value string) error {
+		srv.URL = value
+		return nil
+	}},
+	{"_ENV", func(srv *ServerConfig, value string) error {
+		return decodeEnvStringMap(value, &srv.Env)
+	}},
+}
+
+// ServersFromEnv synthesizes server definitions from MCPX_SERVER_<NAME>_*
+// entries in environ (KEY=VALUE form). Server names are the lowercased
+// <NAME> part.
+func ServersFromEnv(environ []string) (map[string]ServerConfig, error) {
+	servers := make(map[string]ServerConfig)
+	for _, entry := range environ {
+		key, value, ok := strings.Cut(entry, "=")
+		if !ok || !strings.HasPrefix(key, envServerPrefix) {
+			continue
+		}
+		rest := strings.TrimPrefix(key, envServerPrefix)
+		for _, field := range envServerFields {
+			if !strings.HasSuffix(rest, field.suffix) {
+				continue
+			}
+			name := strings.ToLower(strings.TrimSuffix(rest, field.suffix))
+			if name == "" {
+				break
+			}
+			srv := servers[name]
+			if err := field.apply(&srv, value); err != nil {
+				return nil, fmt.Errorf("%s: %w", key, err)
+			}
+			servers[name] = srv
+			break
+		}
+	}
+	return servers, nil
+}
+
+// ExpandedServersFromEnv is ServersFromEnv with ${ENV_VAR} placeholders
+// expanded from the current process environment, as MergeEnvServers adds
+// them. The CLI sends these to the daemon, whose own environment may be
+// older than the caller's.
+func ExpandedServersFromEnv(environ []string) (map[string]ServerConfig, error) {
+	servers, err := ServersFromEnv(environ)
+	if err != nil {
+		return nil, err
+	}
+	for name, srv := range servers {
+		servers[name] = expandServerEnvVars(srv)
+	}
+	return servers, nil
+}
+
+// EnvServerOrigin is the origin recorded for the env-defined server name.
+func EnvServerOrigin(name string) ServerOrigin {
+	return NewServerOrigin(ServerOriginKindEnv, envServerPrefix+strings.ToUpper(name)+"_*")
+}
+
+// MergeEnvServers adds servers defined via MCPX_SERVER_<NAME>_* variables to
+// cfg. An env-defined server replaces a config file entry of the same name.
+func MergeEnvServers(cfg *Config, environ []string) error {
+	if cfg == nil {
+		return nil
+	}
+	servers, err := ExpandedServersFromEnv(environ)
+	if err != nil {
+		return err
+	}
+	if len(servers) == 0 {
+		return nil
+	}
+	if cfg.Servers == nil {
+		cfg.Servers = make(map[string]ServerConfig, len(servers))
+	}
+	if cfg.ServerOrigins == nil {
+		cfg.ServerOrigins = make(map[string]ServerOrigin, len(servers))
+	}
+
+	names := make([]string, 0, len(servers))
+	for name := range servers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		cfg.Servers[name] = servers[name]
+		cfg.ServerOrigins[name] = EnvServerOrigin(name)
+	}
+	return nil
+}
+
+// decodeEnvArgs accepts a JSON string array or whitespace-separated words.
+func decodeEnvArgs(value string, dst *[]string) error {
+	trimmed := strings.TrimSpace(value)
+	if strings.HasPrefix(trimmed, "[") {
+		var args []string
+		if err := json.Unmarshal([]byte(trimmed), &args); err != nil {
+			return fmt.Errorf("invalid JSON array: %w", err)
+		}
+		*dst = args
+		return nil
+	}
+	*dst = strings.Fields(trimmed)
+	return nil
+}
+
+// decodeEnvStringMap accepts a JSON object of string values.
+func decodeEnvStringMap(value string, dst *map[string]string) error {
+	trimmed := strings.TrimSpace(value)
+	if trimmed == "" {
+		*dst = nil
+		return nil
+	}
+	var out map[string]string
+	if err := json.Unmarshal([]byte(trimmed), &out); err != nil {
+		return fmt.Errorf("expected JSON object of strings: %w", err)
+	}
+	*dst = out
+	return nil
+}
@@ -0,0 +1,158 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestServersFromEnvDefinesStdioAndHTTPServers(t *testing.T) {
+	servers, err := ServersFromEnv([]string{
+		"PATH=/usr/bin",
+		"MCPX_SERVER_GITHUB_COMMAND=npx",
+		`MCPX_SERVER_GITHUB_ARGS=["-y", "@modelcontextprotocol/server-github"]`,
+		`MCPX_SERVER_GITHUB_ENV={"GITHUB_TOKEN":"t0k"}`,
+		"MCPX_SERVER_MY_API_URL=https://mcp.example.com/mcp",
+		`MCPX_SERVER_MY_API_HEADERS={"Authorization":"Bearer abc"}`,
+		"MCPX_SERVER_MY_API_DEFAULT_CACHE_TTL=30s",
+		"MCPX_SERVER_LOCAL_COMMAND=local-mcp",
+		"MCPX_SERVER_LOCAL_ARGS=--stdio --verbose",
+		"MCPX_SERVER__URL=https://ignored.example.com",
+	})
+	if err != nil {
+		t.Fatalf("ServersFromEnv() error = %v", err)
+	}
+
+	want := map[string]ServerConfig{
+		"github": {
+			Command: "npx",
+			Args:    []string{"-y", "@modelcontextprotocol/server-github"},
+			Env:     map[string]string{"GITHUB_TOKEN": "t0k"},
+		},
+		"my_api": {
+			URL:             "https://mcp.example.com/mcp",
+			Headers:         map[string]string{"Authorization": "Bearer abc"},
+			DefaultCacheTTL: "30s",
+		},
+		"local": {
+			Command: "local-mcp",
+			Args:    []string{"--stdio", "--verbose"},
+		},
+	}
+	if !reflect.DeepEqual(servers, want) {
+		t.Fatalf("ServersFromEnv() = %#v, want %#v", servers, want)
+	}
+}
+
+func TestServersFromEnvRejectsMalformedValues(t *testing.T) {
+	if _, err := ServersFromEnv([]string{"MCPX_SERVER_X_ARGS=[not json"}); err == nil {
+		t.Fatal("ServersFromEnv(bad args) error = nil, want non-nil")
+	}
+	if _, err := ServersFromEnv([]string{"MCPX_SERVER_X_HEADERS=Authorization=Bearer"}); err == nil {
+		t.Fatal("ServersFromEnv(bad headers) error = nil, want non-nil")
+	}
+}
+
+func TestLoadMergesEnvServersOverConfigFile(t *testing.T) {
+	configHome := t.TempDir()
+	t.Setenv("XDG_CONFIG_HOME", configHome)
+	if err := os.MkdirAll(filepath.Join(configHome, "mcpx"), 0o700); err != nil {
+		t.Fatalf("MkdirAll() error = %v", err)
+	}
+	raw := "[servers.github]\ncommand = \"from-file\"\n\n[servers.linear]\nurl = \"https://linear.example.com/mcp\"\n"
+	if err := os.WriteFile(filepath.Join(configHome, "mcpx", "config.toml"), []byte(raw), 0o600); err != nil {
+		t.Fatalf("WriteFile() error = %v", err)
+	}
+
+	t.Setenv("MCPX_TEST_TOKEN", "secret")
+	t.Setenv("MCPX_SERVER_GITHUB_COMMAND", "from-env")
+	t.Setenv("MCPX_SERVER_REMOTE_URL", "https://remote.example.com/mcp")
+	t.Setenv("MCPX_SERVER_REMOTE_HEADERS", `{"Authorization":"Bearer ${MCPX_TEST_TOKEN}"}`)
+
+	cfg, err := Load()
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	if got := cfg.Servers["github"].Command; got != "from-env" {
+		t.Fatalf("github command = %q, want env override", got)
+	}
+	if got := cfg.Servers["remote"].Headers["Authorization"]; got != "Bearer secret" {
+		t.Fatalf("remote Authorization = %q, want expanded placeholder", got)
+	}
+	if got := cfg.ServerOrigins["remote"].Kind; got != ServerOriginKindEnv {
+		t.Fatalf("remote origin = %q, want %q", got, ServerOriginKindEnv)
+	}
+	if got := cfg.ServerOrigins["linear"].Kind; got != ServerOriginKindMCPXConfig {
+		t.Fatalf("linear origin = %q, want %q", got, ServerOriginKindMCPXConfig)
+	}
+	if err := Validate(cfg); err != nil {
+		t.Fatalf("Validate() error = %v", err)
+	}
+}
+
+func TestLoadAppliesMaxConnectionsEnvOverConfigFile(t *testing.T) {
+	configHome := t.TempDir()
+	t.Setenv("XDG_CONFIG_HOME", configHome)
+	if err := os.MkdirAll(filepath.Join(configHome, "mcpx"), 0o700); err != nil {
+		t.Fatalf("MkdirAll() error = %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(configHome, "mcpx", "config.toml"), []byte("max_connections = 4\n"), 0o600); err != nil {
+		t.Fatalf("WriteFile() error = %v", err)
+	}
+
+	cfg, err := Load()
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	if cfg.MaxConnections != 4 {
+		t.Fatalf("MaxConnections = %d, want 4 from file", cfg.MaxConnections)
+	}
+
+	t.Setenv(MaxConnectionsEnvVar, "2")
+	cfg, err = Load()
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	if cfg.MaxConnections != 2 {
+		t.Fatalf("MaxConnections = %d, want 2 from env", cfg.MaxConnections)
+	}
+
+	t.Setenv(MaxConnectionsEnvVar, "many")
+	if _, err := Load(); err == nil {
+		t.Fatal("Load() with invalid MCPX_MAX_CONNECTIONS error = nil, want non-nil")
+	}
+}
+
+func TestLoadAppliesDefaultOutputEnvOverConfigFile(t *testing.T) {
+	configHome := t.TempDir()
+	t.Setenv("XDG_CONFIG_HOME", configHome)
+	if err := os.MkdirAll(filepath.Join(configHome, "mcpx"), 0o700); err != nil {
+		t.Fatalf("MkdirAll() error = %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(configHome, "mcpx", "config.toml"), []byte("default_output = \"json\"\n"), 0o600); err != nil {
+		t.Fatalf("WriteFile() error = %v", err)
+	}
+
+	cfg, err := Load()
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	if cfg.DefaultOutput != OutputJSON {
+		t.Fatalf("DefaultOutput = %q, want json from file", cfg.DefaultOutput)
+	}
+
+	t.Setenv(DefaultOutputEnvVar, "text")
+	cfg, err = Load()
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	if cfg.DefaultOutput != OutputText {
+		t.Fatalf("DefaultOutput = %q, want text from env", cfg.DefaultOutput)
+	}
+
+	t.Setenv(DefaultOutputEnvVar, "yaml")
+	if _, err := Load(); err == nil {
+		t.Fatal("Load() with invalid MCPX_OUTPUT error = nil, want non-nil")
+	}
+}
@@ -6,6 +6,7 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"regexp"
 	"runtime"
 	"strings"
 
@@ -30,6 +31,36 @@ type mcpServerEntry struct {
 	Headers map[string]string `json:"headers"`
 }
 
+// vscodeMCPDocument is a VS Code .vscode/mcp.json file. VS Code names the
+// table "servers"; "mcpServers" is accepted as well.
+type vscodeMCPDocument struct {
+	Servers    map[string]mcpServerEntry `json:"servers"`
+	MCPServers map[string]mcpServerEntry `json:"mcpServers"`
+}
+
+// zedSettingsDocument is the part of Zed's settings.json mcpx reads.
+type zedSettingsDocument struct {
+	ContextServers map[string]zedContextServer `json:"context_servers"`
+}
+
+// zedContextServer is one context_servers entry. Zed has written command as
+// an object ({"path", "args", "env"}) and, more recently, as a plain string
+// with args and env beside it; both are accepted.
+type zedContextServer struct {
+	Command json.RawMessage   `json:"command"`
+	Args    []string          `json:"args"`
+	Env     map[string]string `json:"env"`
+	URL     string            `json:"url"`
+	Headers map[string]string `json:"headers"`
+	Enabled *bool             `json:"enabled"`
+}
+
+type zedCommand struct {
+	Path string            `json:"path"`
+	Args []string          `json:"args"`
+	Env  map[string]string `json:"env"`
+}
+
 type codexConfigDocument struct {
 	MCPServers     map[string]codexMCPServerEntry `toml:"mcp_servers"`
 	Features       codexFeaturesDocument          `toml:"features"`
@@ -228,35 +259,40 @@ func loadFallbackServersWithSourcesForCWD(paths []string, cwd string) (map[strin
 	return servers, nil
 }
 
+// classifyFallbackOrigin infers the client that owns a fallback source from
+// its path. Matching is done on a forward-slash copy of the path, so
+// Windows paths with backslashes classify the same way.
 func classifyFallbackOrigin(path string) ServerOrigin {
 	path = strings.TrimSpace(path)
 	if path == "" {
 		return NewServerOrigin(ServerOriginKindFallbackCustom, "")
 	}
 	cleanPath := filepath.Clean(path)
-	lower := strings.ToLower(cleanPath)
-	sepPath := string(os.PathSeparator)
+	lower := strings.ToLower(strings.ReplaceAll(cleanPath, `\`, "/"))
 
 	looksLike := func(suffix string) bool {
 		return strings.HasSuffix(lower, strings.ToLower(suffix))
 	}
 	containsDir := func(dir string) bool {
-		needle := strings.ToLower(sepPath + dir + sepPath)
-		return strings.Contains(lower, needle)
+		return strings.Contains(lower, "/"+strings.ToLower(dir)+"/")
 	}
 
 	switch {
-	case looksLike(filepath.Join(".cursor", "mcp.json")):
+	case looksLike(".vscode/mcp.json"):
+		return NewServerOrigin(ServerOriginKindVSCode, cleanPath)
+	case looksLike(".cursor/mcp.json"):
 		return NewServerOrigin(ServerOriginKindCursor, cleanPath)
-	case looksLike(filepath.Join(".codex", "config.toml")):
+	case looksLike(".codex/config.toml"):
 		return NewServerOrigin(ServerOriginKindCodex, cleanPath)
 	case looksLike(".mcp.json") ||
 		looksLike(".claude.json") ||
-		looksLike(filepath.Join("claude", "claude_desktop_config.json")) ||
+		looksLike("claude/claude_desktop_config.json") ||
 		containsDir("saoudrizwan.claude-dev"):
 		return NewServerOrigin(ServerOriginKindClaude, cleanPath)
-	case looksLike(filepath.Join(".kiro", "settings", "mcp.json")):
+	case looksLike(".kiro/settings/mcp.json"):
 		return NewServerOrigin(ServerOriginKindKiro, cleanPath)
+	case looksLike("zed/settings.json"):
+		return NewServerOrigin(ServerOriginKindZed, cleanPath)
 	default:
 		return NewServerOrigin(ServerOriginKindFallbackCustom, cleanPath)
 	}
@@ -271,9 +307,13 @@ func cleanFallbackSourcePath(path string) string {
 }
 
 func loadFallbackSourceForCWD(path, cwd string) (map[string]ServerConfig, error) {
-	switch strings.ToLower(filepath.Ext(path)) {
-	case ".toml":
+	switch {
+	case strings.EqualFold(filepath.Ext(path), ".toml"):
 		return loadCodexConfigFile(path)
+	case classifyFallbackOrigin(path).Kind == ServerOriginKindZed:
+		return loadZedSettingsFile(path)
+	case classifyFallbackOrigin(path).Kind == ServerOriginKindVSCode:
+		return loadVSCodeMCPFile(path)
 	default:
 		return loadMCPServersFileForCWD(path, cwd)
 	}
@@ -284,6 +324,12 @@ func loadMCPServersFile(path string) (map[string]ServerConfig, error) {
 }
 
 func loadMCPServersFileForCWD(path, cwd string) (map[string]ServerConfig, error) {
+	return readMCPServersFileForCWD(path, cwd, true)
+}
+
+// readMCPServersFileForCWD parses an mcpServers JSON file. expand resolves
+// ${VAR} placeholders against the current environment; importers keep them.
+func readMCPServersFileForCWD(path, cwd string, expand bool) (map[string]ServerConfig, error) {
 	data, err := os.ReadFile(path)
 	if err != nil {
 		return nil, err
@@ -295,27 +341,171 @@ func loadMCPServersFileForCWD(path, cwd string) (map[string]ServerConfig, error)
 	}
 
 	servers := make(map[string]ServerConfig, len(doc.MCPServers))
-	mergeServerEntries(servers, matchProjectServers(doc.Projects, cwd))
-	mergeServerEntries(servers, doc.MCPServers)
+	mergeServerEntries(servers, matchProjectServers(doc.Projects, cwd), expand)
+	mergeServerEntries(servers, doc.MCPServers, expand)
 	return servers, nil
 }
 
-func mergeServerEntries(dst map[string]ServerConfig, src map[string]mcpServerEntry) {
+func mergeServerEntries(dst map[string]ServerConfig, src map[string]mcpServerEntry, expand bool) {
 	for name, srv := range src {
 		if _, exists := dst[name]; exists {
 			continue
 		}
-		dst[name] = expandServerEnvVars(ServerConfig{
+		server := ServerConfig{
 			Command: srv.Command,
 			Args:    srv.Args,
 			Env:     srv.Env,
 			URL:     srv.URL,
 			Headers: srv.Headers,
-		})
+		}
+		if expand {
+			server = expandServerEnvVars(server)
+		}
+		dst[name] = server
+	}
+}
+
+// loadVSCodeMCPFile reads a VS Code .vscode/mcp.json, which may contain
+// comments and trailing commas. VS Code variables are rewritten before
+// ${VAR} expansion: ${input:id} becomes ${ID} (upper-cased, with other
+// characters replaced by underscores) so prompted inputs can be supplied
+// through the environment, ${env:NAME} becomes ${NAME}, and
+// ${workspaceFolder} is the directory holding .vscode.
+func loadVSCodeMCPFile(path string) (map[string]ServerConfig, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var doc vscodeMCPDocument
+	if err := json.Unmarshal(stripJSONC(data), &doc); err != nil {
+		return nil, fmt.Errorf("parsing VS Code mcp.json: %w", err)
+	}
+
+	workspace := filepath.Dir(filepath.Dir(filepath.Clean(path)))
+	servers := make(map[string]ServerConfig, len(doc.Servers)+len(doc.MCPServers))
+	for _, entries := range []map[string]mcpServerEntry{doc.Servers, doc.MCPServers} {
+		for name, entry := range entries {
+			if _, exists := servers[name]; exists {
+				continue
+			}
+			server := ServerConfig{
+				Command: vscodeVariables(entry.Command, workspace),
+				Args:    entry.Args,
+				Env:     entry.Env,
+				URL:     vscodeVariables(entry.URL, workspace),
+				Headers: entry.Headers,
+			}
+			for i := range server.Args {
+				server.Args[i] = vscodeVariables(server.Args[i], workspace)
+			}
+			for k, v := range server.Env {
+				server.Env[k] = vscodeVariables(v, workspace)
+			}
+			for k, v := range server.Headers {
+				server.Headers[k] = vscodeVariables(v, workspace)
+			}
+			servers[name] = expandServerEnvVars(server)
+		}
+	}
+	return servers, nil
+}
+
+var vscodeVariableRe = regexp.MustCompile(`\$\{(input|env):([^}]+)\}|\$\{workspaceFolder\}`)
+
+// vscodeVariables rewrites VS Code ${input:id}, ${env:NAME}, and
+// ${workspaceFolder} variables in s. Other variables are left as is.
+func vscodeVariables(s, workspace string) string {
+	return vscodeVariableRe.ReplaceAllStringFunc(s, func(match string) string {
+		parts := vscodeVariableRe.FindStringSubmatch(match)
+		switch parts[1] {
+		case "input":
+			return "${" + vscodeInputEnvName(parts[2]) + "}"
+		case "env":
+			return "${" + strings.TrimSpace(parts[2]) + "}"
+		default:
+			return workspace
+		}
+	})
+}
+
+// vscodeInputEnvName maps an input id such as "github-token" to the
+// environment variable GITHUB_TOKEN.
+func vscodeInputEnvName(id string) string {
+	var b strings.Builder
+	for _, r := range strings.TrimSpace(id) {
+		switch {
+		case r >= 'a' && r <= 'z':
+			b.WriteRune(r - 'a' + 'A')
+		case (r >= 'A' && r <= 'Z') || (r >= '0' && r <= '9') || r == '_':
+			b.WriteRune(r)
+		default:
+			b.WriteByte('_')
+		}
+	}
+	name := b.String()
+	if name == "" || (name[0] >= '0' && name[0] <= '9') {
+		name = "_" + name
+	}
+	return name
+}
+
+// loadZedSettingsFile reads the context_servers of a Zed settings.json,
+// which may contain comments and trailing commas. Entries with
+// "enabled": false are skipped.
+func loadZedSettingsFile(path string) (map[string]ServerConfig, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var doc zedSettingsDocument
+	if err := json.Unmarshal(stripJSONC(data), &doc); err != nil {
+		return nil, fmt.Errorf("parsing Zed settings JSON: %w", err)
+	}
+
+	servers := make(map[string]ServerConfig, len(doc.ContextServers))
+	for name, entry := range doc.ContextServers {
+		if entry.Enabled != nil && !*entry.Enabled {
+			continue
+		}
+
+		server := ServerConfig{
+			Args:    entry.Args,
+			Env:     entry.Env,
+			URL:     entry.URL,
+			Headers: entry.Headers,
+		}
+		if len(entry.Command) > 0 && json.Unmarshal(entry.Command, &server.Command) != nil {
+			var cmd zedCommand
+			if err := json.Unmarshal(entry.Command, &cmd); err != nil {
+				return nil, fmt.Errorf("parsing Zed context server %q command: %w", name, err)
+			}
+			server.Command = cmd.Path
+			if cmd.Args != nil {
+				server.Args = cmd.Args
+			}
+			if cmd.Env != nil {
+				server.Env = cmd.Env
+			}
+		}
+		if server.Command == "" && server.URL == "" {
+			continue
+		}
+		servers[name] = expandServerEnvVars(server)
 	}
+	return servers, nil
 }
 
 func loadCodexConfigFile(path string) (map[string]ServerConfig, error) {
+	return readCodexConfigFile(path, true)
+}
+
+// readCodexConfigFile parses a Codex config.toml. With expand, env_vars are
+// read from the current environment, ${VAR} placeholders are resolved, and the
+// synthesized codex_apps server is added. Without it, env_vars become ${VAR}
+// placeholders and codex_apps is omitted, so no secret values are copied.
+func readCodexConfigFile(path string, expand bool) (map[string]ServerConfig, error) {
 	data, err := os.ReadFile(path)
 	if err != nil {
 		return nil, err
@@ -341,6 +531,13 @@ func loadCodexConfigFile(path string) (map[string]ServerConfig, error) {
 			if _, exists := env[key]; exists {
 				continue
 			}
+			if !expand {
+				if env == nil {
+					env = make(map[string]string)
+				}
+				env[key] = "${" + key + "}"
+				continue
+			}
 			if val, ok := os.LookupEnv(key); ok {
 				if env == nil {
 					env = make(map[string]string)
@@ -373,15 +570,22 @@ func loadCodexConfigFile(path string) (map[string]ServerConfig, error) {
 			}
 		}
 
-		servers[name] = expandServerEnvVars(ServerConfig{
+		server := ServerConfig{
 			Command: entry.Command,
 			Args:    entry.Args,
 			Env:     env,
 			URL:     entry.URL,
 			Headers: headers,
-		})
+		}
+		if expand {
+			server = expandServerEnvVars(server)
+		}
+		servers[name] = server
 	}
 
+	if !expand {
+		return servers, nil
+	}
 	if _, exists := servers[codexAppsServerName]; !exists && !codexAppsExplicitlyDisabled(doc) {
 		if appServer, ok := codexAppsServerFromConfig(path, doc); ok {
 			servers[codexAppsServerName] = appServer
@@ -630,11 +834,18 @@ func defaultFallbackSourcePaths() []string {
 
 func defaultFallbackSourcePathsForCWD(cwd string) []string {
 	home, _ := os.UserHomeDir()
+	return defaultFallbackSourcePathsFor(runtime.GOOS, home, os.Getenv("APPDATA"), cwd)
+}
+
+// defaultFallbackSourcePathsFor lists the client config files read on goos.
+// On Windows, home is %USERPROFILE% and appData is %APPDATA% (defaulting to
+// home\AppData\Roaming), where Claude desktop and VS Code keep their settings.
+func defaultFallbackSourcePathsFor(goos, home, appData, cwd string) []string {
 	if home == "" {
 		return nil
 	}
 
-	switch runtime.GOOS {
+	switch goos {
 	case "darwin":
 		return []string{
 			filepath.Join(home, ".cursor", "mcp.json"),
@@ -645,6 +856,8 @@ func defaultFallbackSourcePathsForCWD(cwd string) []string {
 			nearestUpwardPath(".mcp.json", cwd),
 			filepath.Join(home, ".kiro", "settings", "mcp.json"),
 			nearestUpwardPath(filepath.Join(".kiro", "settings", "mcp.json"), cwd),
+			nearestUpwardPath(filepath.Join(".vscode", "mcp.json"), cwd),
+			filepath.Join(home, ".config", "zed", "settings.json"),
 		}
 	case "linux":
 		return []string{
@@ -656,6 +869,24 @@ func defaultFallbackSourcePathsForCWD(cwd string) []string {
 			nearestUpwardPath(".mcp.json", cwd),
 			filepath.Join(home, ".kiro", "settings", "mcp.json"),
 			nearestUpwardPath(filepath.Join(".kiro", "settings", "mcp.json"), cwd),
+			nearestUpwardPath(filepath.Join(".vscode", "mcp.json"), cwd),
+			filepath.Join(home, ".config", "zed", "settings.json"),
+		}
+	case "windows":
+		if strings.TrimSpace(appData) == "" {
+			appData = filepath.Join(home, "AppData", "Roaming")
+		}
+		return []string{
+			filepath.Join(home, ".cursor", "mcp.json"),
+			filepath.Join(appData, "Claude", "claude_desktop_config.json"),
+			filepath.Join(appData, "Code", "User", "globalStorage", "saoudrizwan.claude-dev", "settings", "cline_mcp_settings.json"),
+			filepath.Join(home, ".claude.json"),
+			filepath.Join(home, ".codex", "config.toml"),
+			nearestUpwardPath(".mcp.json", cwd),
+			filepath.Join(home, ".kiro", "settings", "mcp.json"),
+			nearestUpwardPath(filepath.Join(".kiro", "settings", "mcp.json"), cwd),
+			nearestUpwardPath(filepath.Join(".vscode", "mcp.json"), cwd),
+			filepath.Join(appData, "Zed", "settings.json"),
 		}
 	default:
 		return nil
@@ -324,6 +324,134 @@ func TestDefaultFallbackSourcePathsIncludeClaudeCodeAndKiro(t *testing.T) {
 	}
 }
 
+func TestLoadVSCodeMCPFileReadsNearestProjectServers(t *testing.T) {
+	t.Setenv("GITHUB_TOKEN", "tok-input")
+	t.Setenv("VSCODE_TEST_HOST", "example.com")
+
+	root := t.TempDir()
+	projectRoot := filepath.Join(root, "project")
+	projectSubdir := filepath.Join(projectRoot, "pkg", "subdir")
+	if err := os.MkdirAll(projectSubdir, 0700); err != nil {
+		t.Fatalf("mkdir project subdir: %v", err)
+	}
+	if err := os.MkdirAll(filepath.Join(projectRoot, ".vscode"), 0700); err != nil {
+		t.Fatalf("mkdir .vscode: %v", err)
+	}
+	docPath := filepath.Join(projectRoot, ".vscode", "mcp.json")
+	raw := []byte(`{
+  // Prompted when VS Code starts the server.
+  "inputs": [{"type": "promptString", "id": "github-token", "password": true}],
+  "servers": {
+    "github": {
+      "type": "http",
+      "url": "https://${env:VSCODE_TEST_HOST}/mcp",
+      "headers": {"Authorization": "Bearer ${input:github-token}"},
+    },
+    "local": {"type": "stdio", "command": "node", "args": ["${workspaceFolder}/server.js"]},
+  },
+}`)
+	if err := os.WriteFile(docPath, raw, 0600); err != nil {
+		t.Fatalf("write vscode mcp.json: %v", err)
+	}
+
+	home := t.TempDir()
+	paths := defaultFallbackSourcePathsFor("linux", home, "", projectSubdir)
+	found := false
+	for _, p := range paths {
+		if p == docPath {
+			found = true
+		}
+	}
+	if !found {
+		t.Fatalf("fallback paths = %#v, want %q", paths, docPath)
+	}
+	if got := classifyFallbackOrigin(docPath).Kind; got != ServerOriginKindVSCode {
+		t.Fatalf("classifyFallbackOrigin(%q).Kind = %q, want %q", docPath, got, ServerOriginKindVSCode)
+	}
+
+	servers, err := loadFallbackSourceForCWD(docPath, projectSubdir)
+	if err != nil {
+		t.Fatalf("loadFallbackSourceForCWD(vscode) error = %v", err)
+	}
+	github := servers["github"]
+	if github.URL != "https://example.com/mcp" {
+		t.Fatalf("github url = %q, want env variable expanded", github.URL)
+	}
+	if got := github.Headers["Authorization"]; got != "Bearer tok-input" {
+		t.Fatalf("github Authorization = %q, want input mapped to GITHUB_TOKEN", got)
+	}
+	local := servers["local"]
+	if local.Command != "node" || !reflect.DeepEqual(local.Args, []string{projectRoot + "/server.js"}) {
+		t.Fatalf("local = %+v, want workspaceFolder resolved to project root", local)
+	}
+}
+
+func TestLoadZedSettingsFileReadsContextServers(t *testing.T) {
+	t.Setenv("ZED_TEST_TOKEN", "tok-zed")
+
+	path := filepath.Join(t.TempDir(), "zed", "settings.json")
+	raw := []byte(`// Zed settings
+{
+  "theme": "One Dark", /* editor settings mcpx ignores */
+  "context_servers": {
+    "postgres": {
+      "command": {
+        "path": "npx",
+        "args": ["-y", "@modelcontextprotocol/server-postgres", "postgres://localhost/db"],
+        "env": {"PGPASSWORD": "${ZED_TEST_TOKEN}"},
+      },
+      "settings": {},
+    },
+    "github": {
+      "source": "custom",
+      "command": "github-mcp",
+      "args": ["stdio"],
+    },
+    "remote": {"url": "https://example.com/mcp", "headers": {"X-Note": "a // b"}},
+    "off": {"command": "off-mcp", "enabled": false},
+  },
+}
+`)
+	if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		t.Fatalf("mkdir zed dir: %v", err)
+	}
+	if err := os.WriteFile(path, raw, 0600); err != nil {
+		t.Fatalf("write zed settings: %v", err)
+	}
+
+	servers, err := loadFallbackSourceForCWD(path, "")
+	if err != nil {
+		t.Fatalf("loadFallbackSourceForCWD(zed) error = %v", err)
+	}
+
+	postgres, ok := servers["postgres"]
+	if !ok {
+		t.Fatalf("servers = %#v, want postgres", servers)
+	}
+	if postgres.Command != "npx" || len(postgres.Args) != 3 || postgres.Args[2] != "postgres://localhost/db" {
+		t.Fatalf("postgres = %+v, want npx with three args", postgres)
+	}
+	if got := postgres.Env["PGPASSWORD"]; got != "tok-zed" {
+		t.Fatalf("postgres env PGPASSWORD = %q, want tok-zed", got)
+	}
+
+	github := servers["github"]
+	if github.Command != "github-mcp" || !reflect.DeepEqual(github.Args, []string{"stdio"}) {
+		t.Fatalf("github = %+v, want string command with args", github)
+	}
+	remote := servers["remote"]
+	if remote.URL != "https://example.com/mcp" || remote.Headers["X-Note"] != "a // b" {
+		t.Fatalf("remote = %+v, want URL and header kept intact", remote)
+	}
+	if _, ok := servers["off"]; ok {
+		t.Fatalf("servers = %#v, want disabled entry skipped", servers)
+	}
+
+	if got := classifyFallbackOrigin(path).Kind; got != ServerOriginKindZed {
+		t.Fatalf("classifyFallbackOrigin(%q).Kind = %q, want zed", path, got)
+	}
+}
+
 func TestLoadCodexConfigFileReadsMCPServers(t *testing.T) {
 	t.Setenv("REMOTE_TOKEN", "tok-123")
 	t.Setenv("TRACE_ID", "trace-abc")
@@ -834,6 +962,45 @@ func TestFallbackSourcePathsPreserveDeclaredDefaultOrder(t *testing.T) {
 	}
 }
 
+func TestDefaultFallbackSourcePathsForWindowsUseAppData(t *testing.T) {
+	home := filepath.Join("C:", "Users", "ada")
+	appData := filepath.Join(home, "AppData", "Roaming")
+	want := []string{
+		filepath.Join(home, ".cursor", "mcp.json"),
+		filepath.Join(appData, "Claude", "claude_desktop_config.json"),
+		filepath.Join(appData, "Code", "User", "globalStorage", "saoudrizwan.claude-dev", "settings", "cline_mcp_settings.json"),
+		filepath.Join(home, ".claude.json"),
+		filepath.Join(home, ".codex", "config.toml"),
+		filepath.Join(home, ".kiro", "settings", "mcp.json"),
+		filepath.Join(appData, "Zed", "settings.json"),
+	}
+
+	got := compactPaths(defaultFallbackSourcePathsFor("windows", home, appData, t.TempDir()))
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("windows fallback paths = %#v, want %#v", got, want)
+	}
+	if got := compactPaths(defaultFallbackSourcePathsFor("windows", home, "", t.TempDir())); !reflect.DeepEqual(got, want) {
+		t.Fatalf("windows fallback paths without APPDATA = %#v, want %#v", got, want)
+	}
+}
+
+func TestClassifyFallbackOriginHandlesBackslashPaths(t *testing.T) {
+	tests := map[string]ServerOriginKind{
+		`C:\Users\ada\.cursor\mcp.json`:                                  ServerOriginKindCursor,
+		`C:\Users\ada\AppData\Roaming\Claude\claude_desktop_config.json`: ServerOriginKindClaude,
+		`C:\Users\ada\.codex\config.toml`:                                ServerOriginKindCodex,
+		`C:\work\repo\.mcp.json`:                                         ServerOriginKindClaude,
+		`C:\Users\ada\.kiro\settings\mcp.json`:                           ServerOriginKindKiro,
+		`C:\work\repo\.vscode\mcp.json`:                                  ServerOriginKindVSCode,
+		`C:\work\servers.json`:                                           ServerOriginKindFallbackCustom,
+	}
+	for path, want := range tests {
+		if got := classifyFallbackOrigin(path).Kind; got != want {
+			t.Fatalf("classifyFallbackOrigin(%q).Kind = %q, want %q", path, got, want)
+		}
+	}
+}
+
 func TestCodexAuthFilePathResolutionOrder(t *testing.T) {
 	t.Setenv("CODEX_HOME", "/tmp/codex-home")
 	fromCodexHome := codexAuthFilePath("/tmp/ignored/config.toml")
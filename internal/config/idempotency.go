@@ -0,0 +1,50 @@
+package config
+
+import (
+	"fmt"
+	"strings"
+)
+
+// DefaultIdempotencyHeader carries --idempotency-key to HTTP servers when a
+// tool has no idempotency_key mapping. Stdio servers have no default: the key
+// would become a tool argument the schema may not declare, so their tools
+// need an explicit "arg:<param>" mapping.
+const DefaultIdempotencyHeader = "Idempotency-Key"
+
+// IdempotencyTarget says where a call's idempotency key is sent. Exactly one
+// of Header and Arg is set.
+type IdempotencyTarget struct {
+	Header string
+	Arg    string
+}
+
+// IdempotencyTarget resolves tools.<tool>.idempotency_key ("header:<Name>"
+// or "arg:<param>"), falling back to DefaultIdempotencyHeader on HTTP
+// servers. Unmapped tools on other servers have no target and return an
+// error.
+func (s ServerConfig) IdempotencyTarget(tool string) (IdempotencyTarget, error) {
+	raw := strings.TrimSpace(s.Tools[tool].IdempotencyKey)
+	if raw == "" {
+		if strings.TrimSpace(s.URL) != "" {
+			return IdempotencyTarget{Header: DefaultIdempotencyHeader}, nil
+		}
+		return IdempotencyTarget{}, fmt.Errorf("--idempotency-key has no target for tool %q: stdio servers need tools.%s.idempotency_key = \"arg:<param>\" naming the argument that takes the key", tool, tool)
+	}
+	return parseIdempotencyTarget(raw)
+}
+
+func parseIdempotencyTarget(raw string) (IdempotencyTarget, error) {
+	kind, name, ok := strings.Cut(strings.TrimSpace(raw), ":")
+	name = strings.TrimSpace(name)
+	if !ok || name == "" {
+		return IdempotencyTarget{}, fmt.Errorf("invalid idempotency_key %q: expected header:<Name> or arg:<param>", raw)
+	}
+	switch strings.ToLower(strings.TrimSpace(kind)) {
+	case "header":
+		return IdempotencyTarget{Header: name}, nil
+	case "arg":
+		return IdempotencyTarget{Arg: name}, nil
+	default:
+		return IdempotencyTarget{}, fmt.Errorf("invalid idempotency_key %q: expected header:<Name> or arg:<param>", raw)
+	}
+}
@@ -0,0 +1,77 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// importClientKinds maps the client names accepted by ImportClientServers to
+// the fallback origin kind whose source files they read.
+var importClientKinds = map[string]ServerOriginKind{
+	"cursor": ServerOriginKindCursor,
+	"claude": ServerOriginKindClaude,
+	"codex":  ServerOriginKindCodex,
+	"kiro":   ServerOriginKindKiro,
+}
+
+// ImportClients lists the client names accepted by ImportClientServers.
+func ImportClients() []string {
+	names := make([]string, 0, len(importClientKinds))
+	for name := range importClientKinds {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// ImportClientServers reads server entries from one client's config files,
+// using the same source paths as fallback discovery. ${VAR} placeholders are
+// kept as written so secrets are not copied into mcpx config. It returns the
+// servers (earlier sources win on name clashes) and the files that were read.
+func ImportClientServers(cfg *Config, client, cwd string) (map[string]ServerConfig, []string, error) {
+	kind, ok := importClientKinds[strings.ToLower(strings.TrimSpace(client))]
+	if !ok {
+		return nil, nil, fmt.Errorf("unknown client %q (expected one of: %s)", client, strings.Join(ImportClients(), ", "))
+	}
+
+	servers := make(map[string]ServerConfig)
+	var read []string
+	var errs []error
+	for _, path := range fallbackSourcePathsForCWD(cfg, cwd) {
+		if classifyFallbackOrigin(path).Kind != kind {
+			continue
+		}
+
+		var found map[string]ServerConfig
+		var err error
+		if strings.EqualFold(filepath.Ext(path), ".toml") {
+			found, err = readCodexConfigFile(path, false)
+		} else {
+			found, err = readMCPServersFileForCWD(path, cwd, false)
+		}
+		if err != nil {
+			if os.IsNotExist(err) {
+				continue
+			}
+			errs = append(errs, &FallbackSourceError{Path: path, Err: err})
+			continue
+		}
+
+		read = append(read, path)
+		for name, srv := range found {
+			if _, exists := servers[name]; exists {
+				continue
+			}
+			servers[name] = srv
+		}
+	}
+
+	if len(errs) > 0 {
+		return servers, read, errors.Join(errs...)
+	}
+	return servers, read, nil
+}
@@ -0,0 +1,59 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestImportClientServersKeepsPlaceholdersAndFiltersByClient(t *testing.T) {
+	tmp := t.TempDir()
+	cursorPath := filepath.Join(tmp, ".cursor", "mcp.json")
+	codexPath := filepath.Join(tmp, ".codex", "config.toml")
+	for path, body := range map[string]string{
+		cursorPath: `{"mcpServers":{"github":{"command":"npx","env":{"GITHUB_TOKEN":"${GITHUB_TOKEN}"}}}}`,
+		codexPath: `[mcp_servers.linear]
+command = "linear-mcp"
+env_vars = ["LINEAR_API_KEY"]
+`,
+	} {
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatalf("MkdirAll(%s): %v", path, err)
+		}
+		if err := os.WriteFile(path, []byte(body), 0o600); err != nil {
+			t.Fatalf("WriteFile(%s): %v", path, err)
+		}
+	}
+	t.Setenv("GITHUB_TOKEN", "secret")
+	t.Setenv("LINEAR_API_KEY", "secret")
+
+	cfg := &Config{FallbackSources: []string{cursorPath, codexPath}}
+
+	servers, sources, err := ImportClientServers(cfg, "cursor", tmp)
+	if err != nil {
+		t.Fatalf("ImportClientServers(cursor) error = %v", err)
+	}
+	if len(sources) != 1 || sources[0] != cursorPath {
+		t.Fatalf("sources = %v, want [%s]", sources, cursorPath)
+	}
+	if len(servers) != 1 || servers["github"].Env["GITHUB_TOKEN"] != "${GITHUB_TOKEN}" {
+		t.Fatalf("servers = %#v, want github with unexpanded token", servers)
+	}
+
+	servers, _, err = ImportClientServers(cfg, "codex", tmp)
+	if err != nil {
+		t.Fatalf("ImportClientServers(codex) error = %v", err)
+	}
+	if got := servers["linear"].Env["LINEAR_API_KEY"]; got != "${LINEAR_API_KEY}" {
+		t.Fatalf("linear env = %q, want placeholder", got)
+	}
+	if _, ok := servers[codexAppsServerName]; ok {
+		t.Fatal("codex_apps imported, want skipped")
+	}
+}
+
+func TestImportClientServersRejectsUnknownClient(t *testing.T) {
+	if _, _, err := ImportClientServers(&Config{}, "vim", ""); err == nil {
+		t.Fatal("ImportClientServers(vim) error = nil, want error")
+	}
+}
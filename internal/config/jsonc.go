@@ -0,0 +1,65 @@
+package config
+
+// stripJSONC turns JSON with comments (as editors like Zed write their
+// settings) into plain JSON: // and /* */ comments outside strings are
+// removed, and commas directly before a closing } or ] are dropped.
+// Newlines inside comments are kept so parse errors report useful offsets.
+func stripJSONC(data []byte) []byte {
+	out := make([]byte, 0, len(data))
+	inString, escaped := false, false
+	for i := 0; i < len(data); i++ {
+		c := data[i]
+		if inString {
+			out = append(out, c)
+			switch {
+			case escaped:
+				escaped = false
+			case c == '\\':
+				escaped = true
+			case c == '"':
+				inString = false
+			}
+			continue
+		}
+
+		switch {
+		case c == '"':
+			inString = true
+			out = append(out, c)
+		case c == '/' && i+1 < len(data) && data[i+1] == '/':
+			for i < len(data) && data[i] != '\n' {
+				i++
+			}
+			if i < len(data) {
+				out = append(out, '\n')
+			}
+		case c == '/' && i+1 < len(data) && data[i+1] == '*':
+			i += 2
+			for i < len(data) && !(data[i] == '*' && i+1 < len(data) && data[i+1] == '/') {
+				if data[i] == '\n' {
+					out = append(out, '\n')
+				}
+				i++
+			}
+			i++
+		case c == '}' || c == ']':
+			out = dropTrailingComma(out)
+			out = append(out, c)
+		default:
+			out = append(out, c)
+		}
+	}
+	return out
+}
+
+// dropTrailingComma removes a comma that, ignoring whitespace, ends out.
+func dropTrailingComma(out []byte) []byte {
+	j := len(out) - 1
+	for j >= 0 && (out[j] == ' ' || out[j] == '\t' || out[j] == '\n' || out[j] == '\r') {
+		j--
+	}
+	if j >= 0 && out[j] == ',' {
+		return append(out[:j], out[j+1:]...)
+	}
+	return out
+}
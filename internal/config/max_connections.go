@@ -0,0 +1,34 @@
+package config
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// MaxConnectionsEnvVar overrides max_connections from the environment.
+const MaxConnectionsEnvVar = "MCPX_MAX_CONNECTIONS"
+
+// applyMaxConnectionsEnv sets cfg.MaxConnections from MCPX_MAX_CONNECTIONS
+// in environ (KEY=VALUE form), which wins over the config file.
+func applyMaxConnectionsEnv(cfg *Config, environ []string) error {
+	if cfg == nil {
+		return nil
+	}
+	prefix := MaxConnectionsEnvVar + "="
+	for _, entry := range environ {
+		if !strings.HasPrefix(entry, prefix) {
+			continue
+		}
+		raw := strings.TrimSpace(strings.TrimPrefix(entry, prefix))
+		if raw == "" {
+			continue
+		}
+		n, err := strconv.Atoi(raw)
+		if err != nil || n < 0 {
+			return fmt.Errorf("%s: must be a non-negative integer, got %q", MaxConnectionsEnvVar, raw)
+		}
+		cfg.MaxConnections = n
+	}
+	return nil
+}
@@ -0,0 +1,56 @@
+package config
+
+import (
+	"fmt"
+	"strings"
+)
+
+// SetRecipe saves a tool call under name, replacing any recipe already
+// saved there.
+func SetRecipe(cfg *Config, name string, recipe Recipe) error {
+	if cfg == nil {
+		return fmt.Errorf("config is nil")
+	}
+	name = strings.TrimSpace(name)
+	if errs := validateRecipe(name, recipe); len(errs) > 0 {
+		return errs[0]
+	}
+	if cfg.Recipes == nil {
+		cfg.Recipes = make(map[string]Recipe)
+	}
+	cfg.Recipes[name] = recipe
+	return nil
+}
+
+func validateRecipe(name string, recipe Recipe) []error {
+	var errs []error
+	if strings.TrimSpace(name) == "" || strings.ContainsAny(name, " \t\r\n") {
+		errs = append(errs, fmt.Errorf("recipes: invalid recipe name %q: must be non-empty with no whitespace", name))
+		return errs
+	}
+	if strings.TrimSpace(recipe.Server) == "" {
+		errs = append(errs, fmt.Errorf("recipes.%s.server: must not be empty", name))
+	}
+	if strings.TrimSpace(recipe.Tool) == "" {
+		errs = append(errs, fmt.Errorf("recipes.%s.tool: must not be empty", name))
+	}
+	return errs
+}
+
+func cloneRecipes(recipes map[string]Recipe) map[string]Recipe {
+	if recipes == nil {
+		return nil
+	}
+	cloned := make(map[string]Recipe, len(recipes))
+	for name, recipe := range recipes {
+		if recipe.Args != nil {
+			args := make(map[string]any, len(recipe.Args))
+			for key, value := range recipe.Args {
+				args[key] = value
+			}
+			recipe.Args = args
+		}
+		cloned[name] = recipe
+	}
+	return cloned
+}
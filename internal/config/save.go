@@ -15,7 +15,8 @@ func Save(cfg *Config) error {
 	return SaveTo(paths.ConfigFile(), cfg)
 }
 
-// SaveTo writes cfg to path atomically.
+// SaveTo writes cfg to path atomically. A file that lists its servers as
+// [[servers]] keeps that form.
 func SaveTo(path string, cfg *Config) error {
 	if cfg == nil {
 		cfg = &Config{}
@@ -24,8 +25,16 @@ func SaveTo(path string, cfg *Config) error {
 		cfg.Servers = make(map[string]ServerConfig)
 	}
 
+	list, err := fileUsesServerList(path)
+	if err != nil {
+		return err
+	}
+	var doc any = cfg
+	if list {
+		doc = encodeServerList(cfg)
+	}
 	var payload bytes.Buffer
-	if err := toml.NewEncoder(&payload).Encode(cfg); err != nil {
+	if err := toml.NewEncoder(&payload).Encode(doc); err != nil {
 		return fmt.Errorf("encoding config: %w", err)
 	}
 
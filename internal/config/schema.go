@@ -0,0 +1,124 @@
+package config
+
+import (
+	"encoding/json"
+	"reflect"
+	"strings"
+)
+
+// SchemaID is the $id of the published config JSON Schema.
+const SchemaID = "https://github.com/lydakis/mcpx/schema/config.json"
+
+// schemaDescriptions documents config fields, keyed by "<Type>.<toml key>".
+// Every toml-tagged field must have an entry; see TestJSONSchemaDescribesEveryField.
+var schemaDescriptions = map[string]string{
+	"Config.servers":                   "MCP servers keyed by the name used on the command line.",
+	"Config.fallback_sources":          "Client config files read for servers not defined here. Replaces the built-in list when set.",
+	"Config.trusted_install_hosts":     "Host globs (example.com, *.example.com) or scheme entries (cursor:) that mcpx add accepts URL and install-link sources from. Empty trusts every source.",
+	"Config.cache_scope":               "Default cache key scope: global shares cached responses across directories; cwd keys them by request directory and server definition.",
+	"Config.default_output":            "Output mode for listings and tool help when neither --json nor --text is given: text (default) or json. MCPX_OUTPUT overrides it.",
+	"Config.default_request_timeout":   "Longest a single request to a server may take, as a Go duration, for servers without request_timeout. Empty means no limit.",
+	"Config.connection_check_interval": "How often the daemon pings open server connections and closes the ones that no longer answer, as a Go duration. Empty disables the checks.",
+	"Config.connection_max_idle":       "Close server connections unused for longer than this Go duration. Empty keeps them open until the daemon exits.",
+	"Config.recipes":                   "Saved tool calls keyed by name, created with --save-as and replayed with mcpx run <name>.",
+	"Config.max_connections":           "Most stdio server processes the daemon keeps open at once; the least recently used idle one is closed to make room. 0 means no limit. MCPX_MAX_CONNECTIONS overrides it.",
+
+	"ServerConfig.command":              "Executable for the stdio transport.",
+	"ServerConfig.args":                 "Arguments passed to command.",
+	"ServerConfig.env":                  "Environment variables for the stdio process. Values support ${VAR} placeholders.",
+	"ServerConfig.url":                  "Endpoint for the streamable HTTP transport.",
+	"ServerConfig.headers":              "HTTP headers sent with every request. Values support ${VAR} placeholders.",
+	"ServerConfig.http":                 "Connection pool tuning for the HTTP transport.",
+	"ServerConfig.default_cache_ttl":    "Cache TTL applied to every tool call, as a Go duration (for example 30s).",
+	"ServerConfig.no_cache_tools":       "Glob patterns of tools that are never cached.",
+	"ServerConfig.cache_errors":         "Also cache tool error results, for error_cache_ttl. Off by default: errors are never cached.",
+	"ServerConfig.error_cache_ttl":      "How long cached error results live, as a Go duration (default 10s, capped at the success TTL).",
+	"ServerConfig.cache_mode":           "How expired cache entries are served: strict (default) treats them as misses; stale_while_revalidate returns them within stale_ttl and refreshes in the background.",
+	"ServerConfig.stale_ttl":            "How long past expiry stale_while_revalidate may serve an entry, as a Go duration (default: the entry's TTL).",
+	"ServerConfig.tools":                "Per-tool overrides keyed by tool name.",
+	"ServerConfig.allow_tools":          "Glob patterns of tools to expose. Empty exposes all tools.",
+	"ServerConfig.deny_tools":           "Glob patterns of tools to hide. Wins over allow_tools.",
+	"ServerConfig.allow_env":            "Glob patterns of daemon environment variables a stdio server inherits. Empty inherits all.",
+	"ServerConfig.health_check":         "Tool called with no arguments after connecting; the server is used only once it succeeds.",
+	"ServerConfig.health_check_timeout": "How long to retry health_check before giving up, as a Go duration (default 30s).",
+	"ServerConfig.request_timeout":      "Longest a single tools/list or tools/call request to this server may take, as a Go duration. Overrides default_request_timeout.",
+	"ServerConfig.retry_max":            "Times to retry a tool call that fails with a transient connection or server error (default 0: no retries).",
+	"ServerConfig.retry_backoff":        "Wait before the first retry_max retry, doubling for each one after, as a Go duration (default 200ms).",
+	"ServerConfig.pool_size":            "Stdio processes to run for parallel tool calls (default 1). Each counts toward max_connections.",
+
+	"HTTPConfig.max_idle_conns":          "Maximum idle connections across hosts (default 100).",
+	"HTTPConfig.max_idle_conns_per_host": "Maximum idle connections per host (default 16).",
+	"HTTPConfig.idle_conn_timeout":       "How long an idle connection is kept, as a Go duration (default 90s).",
+
+	"ToolConfig.cache": "Enable or disable caching for this tool.",
+	"Recipe.server":    "Server the recipe calls.",
+	"Recipe.tool":      "Tool the recipe calls.",
+	"Recipe.args":      "Saved arguments. Flags given to mcpx run override them key by key.",
+
+	"ToolConfig.defaults":        "Parameter values merged into calls that do not set them.",
+	"ToolConfig.idempotency_key": "Where --idempotency-key is sent: header:<Name> or arg:<param>. Defaults to the Idempotency-Key header on HTTP servers; stdio servers need an explicit arg:<param>.",
+}
+
+// JSONSchema returns a JSON Schema (draft 2020-12) for config.toml, derived
+// from the Config structs so it stays in sync with what mcpx reads.
+func JSONSchema() ([]byte, error) {
+	root := schemaForStruct(reflect.TypeOf(Config{}))
+	root["$schema"] = "https://json-schema.org/draft/2020-12/schema"
+	root["$id"] = SchemaID
+	root["title"] = "mcpx config"
+
+	servers := root["properties"].(map[string]any)["servers"].(map[string]any)
+	server := servers["additionalProperties"].(map[string]any)
+	server["anyOf"] = []any{
+		map[string]any{"required": []string{"command"}},
+		map[string]any{"required": []string{"url"}},
+	}
+	server["not"] = map[string]any{"required": []string{"command", "url"}}
+
+	return json.MarshalIndent(root, "", "  ")
+}
+
+func schemaForStruct(t reflect.Type) map[string]any {
+	props := make(map[string]any)
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		key, _, _ := strings.Cut(field.Tag.Get("toml"), ",")
+		if key == "" || key == "-" || !field.IsExported() {
+			continue
+		}
+		prop := schemaForType(field.Type)
+		if desc := schemaDescriptions[t.Name()+"."+key]; desc != "" {
+			prop["description"] = desc
+		}
+		props[key] = prop
+	}
+	return map[string]any{
+		"type":                 "object",
+		"properties":           props,
+		"additionalProperties": false,
+	}
+}
+
+func schemaForType(t reflect.Type) map[string]any {
+	if t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	switch t.Kind() {
+	case reflect.String:
+		return map[string]any{"type": "string"}
+	case reflect.Bool:
+		return map[string]any{"type": "boolean"}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return map[string]any{"type": "integer"}
+	case reflect.Float32, reflect.Float64:
+		return map[string]any{"type": "number"}
+	case reflect.Slice:
+		return map[string]any{"type": "array", "items": schemaForType(t.Elem())}
+	case reflect.Map:
+		return map[string]any{"type": "object", "additionalProperties": schemaForType(t.Elem())}
+	case reflect.Struct:
+		return schemaForStruct(t)
+	default:
+		return map[string]any{}
+	}
+}
@@ -0,0 +1,62 @@
+package config
+
+import (
+	"encoding/json"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestJSONSchemaCoversKnownFields(t *testing.T) {
+	raw, err := JSONSchema()
+	if err != nil {
+		t.Fatalf("JSONSchema() error = %v", err)
+	}
+
+	var schema map[string]any
+	if err := json.Unmarshal(raw, &schema); err != nil {
+		t.Fatalf("schema is not valid JSON: %v", err)
+	}
+	if schema["$id"] != SchemaID {
+		t.Fatalf("$id = %v, want %q", schema["$id"], SchemaID)
+	}
+
+	props := schema["properties"].(map[string]any)
+	if _, ok := props["fallback_sources"]; !ok {
+		t.Fatal("schema missing fallback_sources")
+	}
+	server := props["servers"].(map[string]any)["additionalProperties"].(map[string]any)
+	serverProps := server["properties"].(map[string]any)
+	for _, key := range []string{"command", "args", "env", "url", "headers", "http", "default_cache_ttl", "no_cache_tools", "tools", "allow_tools", "deny_tools"} {
+		if _, ok := serverProps[key]; !ok {
+			t.Fatalf("server schema missing %q", key)
+		}
+	}
+	tool := serverProps["tools"].(map[string]any)["additionalProperties"].(map[string]any)
+	cache := tool["properties"].(map[string]any)["cache"].(map[string]any)
+	if cache["type"] != "boolean" {
+		t.Fatalf("tools.*.cache type = %v, want boolean", cache["type"])
+	}
+	if _, ok := server["anyOf"]; !ok {
+		t.Fatal("server schema missing transport anyOf")
+	}
+}
+
+func TestJSONSchemaDescribesEveryField(t *testing.T) {
+	for _, typ := range []reflect.Type{
+		reflect.TypeOf(Config{}),
+		reflect.TypeOf(ServerConfig{}),
+		reflect.TypeOf(HTTPConfig{}),
+		reflect.TypeOf(ToolConfig{}),
+	} {
+		for i := 0; i < typ.NumField(); i++ {
+			key, _, _ := strings.Cut(typ.Field(i).Tag.Get("toml"), ",")
+			if key == "" || key == "-" {
+				continue
+			}
+			if schemaDescriptions[typ.Name()+"."+key] == "" {
+				t.Errorf("schemaDescriptions missing %s.%s", typ.Name(), key)
+			}
+		}
+	}
+}
@@ -0,0 +1,97 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/BurntSushi/toml"
+)
+
+// namedServerConfig is one [[servers]] entry: a server definition that
+// carries its own name instead of being keyed by it.
+type namedServerConfig struct {
+	Name string `toml:"name"`
+	ServerConfig
+}
+
+// serverListDoc decodes a config file whose servers use the [[servers]]
+// array-of-tables form. Servers shadows the map-typed Config.Servers.
+type serverListDoc struct {
+	Config
+	Servers []namedServerConfig `toml:"servers"`
+}
+
+// usesServerList reports whether servers is written as [[servers]].
+func usesServerList(data []byte) (bool, error) {
+	var probe struct {
+		Servers any `toml:"servers"`
+	}
+	if err := toml.Unmarshal(data, &probe); err != nil {
+		return false, err
+	}
+	switch probe.Servers.(type) {
+	case []map[string]any, []any:
+		return true, nil
+	default:
+		return false, nil
+	}
+}
+
+// decodeServerList decodes the [[servers]] form into a Config with the
+// usual name-keyed Servers map.
+func decodeServerList(data []byte) (Config, error) {
+	var doc serverListDoc
+	if err := toml.Unmarshal(data, &doc); err != nil {
+		return Config{}, err
+	}
+
+	cfg := doc.Config
+	cfg.Servers = make(map[string]ServerConfig, len(doc.Servers))
+	for i, entry := range doc.Servers {
+		name := strings.TrimSpace(entry.Name)
+		if name == "" {
+			return Config{}, fmt.Errorf("servers[%d]: name is required", i)
+		}
+		if _, exists := cfg.Servers[name]; exists {
+			return Config{}, fmt.Errorf("servers[%d]: duplicate server name %q", i, name)
+		}
+		cfg.Servers[name] = entry.ServerConfig
+	}
+	return cfg, nil
+}
+
+// fileUsesServerList reports whether the config file at path exists and
+// writes its servers as [[servers]], so saves can keep that form.
+func fileUsesServerList(path string) (bool, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return false, nil
+		}
+		return false, fmt.Errorf("reading config: %w", err)
+	}
+	list, err := usesServerList(data)
+	if err != nil {
+		return false, fmt.Errorf("parsing config %s: %w", path, err)
+	}
+	return list, nil
+}
+
+// encodeServerList returns cfg in the [[servers]] form, one entry per
+// server in name order.
+func encodeServerList(cfg *Config) serverListDoc {
+	doc := serverListDoc{Config: *cfg}
+	doc.Config.Servers = nil
+	names := make([]string, 0, len(cfg.Servers))
+	for name := range cfg.Servers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	doc.Servers = make([]namedServerConfig, 0, len(names))
+	for _, name := range names {
+		doc.Servers = append(doc.Servers, namedServerConfig{Name: name, ServerConfig: cfg.Servers[name]})
+	}
+	return doc
+}
@@ -0,0 +1,34 @@
+package config
+
+import (
+	"fmt"
+	"strings"
+)
+
+// SetToolDefault saves value as the default for param on server's tool.
+// The server must already be defined in cfg.
+func SetToolDefault(cfg *Config, server, tool, param string, value any) error {
+	if cfg == nil {
+		return fmt.Errorf("config is nil")
+	}
+	param = strings.TrimSpace(param)
+	if param == "" {
+		return fmt.Errorf("parameter name must not be empty")
+	}
+	srv, ok := cfg.Servers[server]
+	if !ok {
+		return fmt.Errorf("server %q is not defined in config", server)
+	}
+
+	if srv.Tools == nil {
+		srv.Tools = make(map[string]ToolConfig)
+	}
+	tc := srv.Tools[tool]
+	if tc.Defaults == nil {
+		tc.Defaults = make(map[string]any)
+	}
+	tc.Defaults[param] = value
+	srv.Tools[tool] = tc
+	cfg.Servers[server] = srv
+	return nil
+}
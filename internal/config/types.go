@@ -1,9 +1,35 @@
 package config
 
+import "path"
+
 // Config is the top-level mcpx configuration.
 type Config struct {
 	Servers         map[string]ServerConfig `toml:"servers"`
 	FallbackSources []string                `toml:"fallback_sources"`
+	// TrustedInstallHosts limits `mcpx add` URL and install-link sources to
+	// these host globs or "scheme:" entries. Empty trusts every source.
+	TrustedInstallHosts []string `toml:"trusted_install_hosts,omitempty"`
+	// MaxConnections caps the stdio server processes the daemon keeps open
+	// at once. Zero means no limit.
+	MaxConnections int `toml:"max_connections,omitempty"`
+	// CacheScope is the default for --cache-scope: "global" (default) or
+	// "cwd".
+	CacheScope string `toml:"cache_scope,omitempty"`
+	// DefaultOutput is the output mode listings and tool help use when no
+	// --json or --text flag is given: "text" (default) or "json".
+	DefaultOutput string `toml:"default_output,omitempty"`
+	// DefaultRequestTimeout bounds each request the daemon sends to a server
+	// whose request_timeout is unset. Empty means no limit.
+	DefaultRequestTimeout string `toml:"default_request_timeout,omitempty"`
+	// ConnectionCheckInterval is how often the daemon pings its open server
+	// connections and drops the ones that no longer answer. Empty disables
+	// the checks.
+	ConnectionCheckInterval string `toml:"connection_check_interval,omitempty"`
+	// ConnectionMaxIdle closes connections unused for longer than this.
+	// Empty keeps them until the daemon exits.
+	ConnectionMaxIdle string `toml:"connection_max_idle,omitempty"`
+	// Recipes are saved tool calls replayed with `mcpx run <name>`.
+	Recipes map[string]Recipe `toml:"recipes,omitempty"`
 	// ServerOrigins records where each server entry came from at runtime.
 	// It is runtime metadata only and is not persisted to config.toml.
 	ServerOrigins map[string]ServerOrigin `toml:"-" json:"-"`
@@ -18,8 +44,11 @@ const (
 	ServerOriginKindCodex            ServerOriginKind = "codex"
 	ServerOriginKindClaude           ServerOriginKind = "claude"
 	ServerOriginKindKiro             ServerOriginKind = "kiro"
+	ServerOriginKindZed              ServerOriginKind = "zed"
+	ServerOriginKindVSCode           ServerOriginKind = "vscode"
 	ServerOriginKindFallbackCustom   ServerOriginKind = "fallback_custom"
 	ServerOriginKindRuntimeEphemeral ServerOriginKind = "runtime_ephemeral"
+	ServerOriginKindEnv              ServerOriginKind = "env"
 )
 
 // ServerOrigin describes the source of a resolved server entry.
@@ -60,16 +89,117 @@ type ServerConfig struct {
 	// HTTP transport
 	URL     string            `toml:"url"`
 	Headers map[string]string `toml:"headers"`
+	HTTP    *HTTPConfig       `toml:"http,omitempty"`
 
 	// Caching
 	DefaultCacheTTL string                `toml:"default_cache_ttl"`
 	NoCacheTools    []string              `toml:"no_cache_tools"`
 	Tools           map[string]ToolConfig `toml:"tools"`
+	// CacheErrors also caches tool error results, for ErrorCacheTTL
+	// (default 10s, never longer than the success TTL). Off by default.
+	CacheErrors   bool   `toml:"cache_errors,omitempty"`
+	ErrorCacheTTL string `toml:"error_cache_ttl,omitempty"`
+	// CacheMode is "strict" (default) or "stale_while_revalidate", which
+	// serves entries up to StaleTTL past expiry while a background call
+	// refreshes them. StaleTTL defaults to the entry's TTL.
+	CacheMode string `toml:"cache_mode,omitempty"`
+	StaleTTL  string `toml:"stale_ttl,omitempty"`
+
+	// Tool visibility. Glob patterns (path.Match) matched against tool names;
+	// deny_tools wins over allow_tools, and an empty allow_tools allows all.
+	AllowTools []string `toml:"allow_tools"`
+	DenyTools  []string `toml:"deny_tools"`
+
+	// AllowEnv restricts which daemon environment variables a stdio server
+	// inherits to names matching these globs (path.Match); Env is always
+	// passed. Empty inherits the full environment.
+	AllowEnv []string `toml:"allow_env,omitempty"`
+
+	// Readiness. HealthCheck names a tool called with no arguments after
+	// connecting; the connection is used only once it succeeds, retrying for
+	// up to HealthCheckTimeout (default 30s).
+	HealthCheck        string `toml:"health_check,omitempty"`
+	HealthCheckTimeout string `toml:"health_check_timeout,omitempty"`
+
+	// RequestTimeout bounds each tools/list and tools/call request to this
+	// server, overriding the top-level default_request_timeout.
+	RequestTimeout string `toml:"request_timeout,omitempty"`
+
+	// RetryMax retries a tools/call that fails with a transient transport or
+	// server error up to this many times, on a fresh connection, waiting
+	// RetryBackoff (default 200ms) before the first retry and doubling after.
+	RetryMax     int    `toml:"retry_max,omitempty"`
+	RetryBackoff string `toml:"retry_backoff,omitempty"`
+
+	// PoolSize is how many processes a stdio server may run so tool calls
+	// proceed in parallel; 0 and 1 keep a single, serialized connection.
+	PoolSize int `toml:"pool_size,omitempty"`
+}
+
+// EffectivePoolSize returns PoolSize with its default of 1 applied.
+func (s ServerConfig) EffectivePoolSize() int {
+	if s.PoolSize < 1 {
+		return 1
+	}
+	return s.PoolSize
+}
+
+// HTTPConfig tunes connection pooling for HTTP transports. Zero values fall
+// back to mcpx defaults.
+type HTTPConfig struct {
+	MaxIdleConns        int    `toml:"max_idle_conns"`
+	MaxIdleConnsPerHost int    `toml:"max_idle_conns_per_host"`
+	IdleConnTimeout     string `toml:"idle_conn_timeout"`
+}
+
+// Recipe is a saved tool call: the server, tool, and arguments given to
+// --save-as.
+type Recipe struct {
+	Server string         `toml:"server"`
+	Tool   string         `toml:"tool"`
+	Args   map[string]any `toml:"args,omitempty"`
 }
 
 // ToolConfig holds per-tool overrides.
 type ToolConfig struct {
 	Cache *bool `toml:"cache"`
+	// Defaults are saved parameter values merged into calls that do not set
+	// the parameter explicitly.
+	Defaults map[string]any `toml:"defaults,omitempty"`
+	// IdempotencyKey maps --idempotency-key to "header:<Name>" or
+	// "arg:<param>". Empty uses the Idempotency-Key header on HTTP servers
+	// and is an error on stdio servers (see IdempotencyTarget).
+	IdempotencyKey string `toml:"idempotency_key,omitempty"`
+}
+
+// ToolAllowed reports whether the allow_tools/deny_tools lists expose tool.
+func (s ServerConfig) ToolAllowed(tool string) bool {
+	if matchesAnyToolGlob(s.DenyTools, tool) {
+		return false
+	}
+	if len(s.AllowTools) == 0 {
+		return true
+	}
+	return matchesAnyToolGlob(s.AllowTools, tool)
+}
+
+// EnvAllowed reports whether allow_env lets a stdio server inherit the
+// environment variable name.
+func (s ServerConfig) EnvAllowed(name string) bool {
+	if len(s.AllowEnv) == 0 {
+		return true
+	}
+	return matchesAnyToolGlob(s.AllowEnv, name)
+}
+
+func matchesAnyToolGlob(patterns []string, tool string) bool {
+	for _, pattern := range patterns {
+		matched, err := path.Match(pattern, tool)
+		if err == nil && matched {
+			return true
+		}
+	}
+	return false
 }
 
 // IsStdio returns true if the server uses stdio transport.
@@ -23,10 +23,54 @@ func Validate(cfg *Config) error {
 	sort.Strings(names)
 
 	var errs []error
+	if cfg.MaxConnections < 0 {
+		errs = append(errs, fmt.Errorf("max_connections: must be 0 (no limit) or positive, got %d", cfg.MaxConnections))
+	}
+	if _, err := ParseCacheScope(cfg.CacheScope); err != nil {
+		errs = append(errs, fmt.Errorf("cache_scope: %w", err))
+	}
+	if _, err := ParseDefaultOutput(cfg.DefaultOutput); err != nil {
+		errs = append(errs, fmt.Errorf("default_output: %w", err))
+	}
+	if cfg.DefaultRequestTimeout != "" {
+		timeout, err := time.ParseDuration(cfg.DefaultRequestTimeout)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("default_request_timeout: invalid duration %q: %w", cfg.DefaultRequestTimeout, err))
+		} else if timeout <= 0 {
+			errs = append(errs, fmt.Errorf("default_request_timeout: must be > 0, got %q", cfg.DefaultRequestTimeout))
+		}
+	}
+	for _, field := range []struct{ key, value string }{
+		{"connection_check_interval", cfg.ConnectionCheckInterval},
+		{"connection_max_idle", cfg.ConnectionMaxIdle},
+	} {
+		if field.value == "" {
+			continue
+		}
+		d, err := time.ParseDuration(field.value)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("%s: invalid duration %q: %w", field.key, field.value, err))
+		} else if d <= 0 {
+			errs = append(errs, fmt.Errorf("%s: must be > 0, got %q", field.key, field.value))
+		}
+	}
+	for i, entry := range cfg.TrustedInstallHosts {
+		if _, err := path.Match(strings.ToLower(strings.TrimSpace(entry)), "probe"); err != nil {
+			errs = append(errs, fmt.Errorf("trusted_install_hosts[%d]: invalid glob %q: %w", i, entry, err))
+		}
+	}
 	for _, name := range names {
 		srv := cfg.Servers[name]
 		errs = append(errs, validateServer(name, srv)...)
 	}
+	recipeNames := make([]string, 0, len(cfg.Recipes))
+	for name := range cfg.Recipes {
+		recipeNames = append(recipeNames, name)
+	}
+	sort.Strings(recipeNames)
+	for _, name := range recipeNames {
+		errs = append(errs, validateRecipe(name, cfg.Recipes[name])...)
+	}
 
 	return errors.Join(errs...)
 }
@@ -58,9 +102,15 @@ func cloneConfig(cfg *Config) *Config {
 	}
 
 	cloned := &Config{
-		FallbackSources: append([]string(nil), cfg.FallbackSources...),
-		Servers:         make(map[string]ServerConfig, len(cfg.Servers)),
-		ServerOrigins:   make(map[string]ServerOrigin, len(cfg.ServerOrigins)),
+		FallbackSources:       append([]string(nil), cfg.FallbackSources...),
+		TrustedInstallHosts:   append([]string(nil), cfg.TrustedInstallHosts...),
+		MaxConnections:        cfg.MaxConnections,
+		CacheScope:            cfg.CacheScope,
+		DefaultOutput:         cfg.DefaultOutput,
+		DefaultRequestTimeout: cfg.DefaultRequestTimeout,
+		Recipes:               cloneRecipes(cfg.Recipes),
+		Servers:               make(map[string]ServerConfig, len(cfg.Servers)),
+		ServerOrigins:         make(map[string]ServerOrigin, len(cfg.ServerOrigins)),
 	}
 
 	for name, srv := range cfg.Servers {
@@ -77,9 +127,16 @@ func cloneServerConfig(srv ServerConfig) ServerConfig {
 	cloned := srv
 	cloned.Args = append([]string(nil), srv.Args...)
 	cloned.NoCacheTools = append([]string(nil), srv.NoCacheTools...)
+	cloned.AllowTools = append([]string(nil), srv.AllowTools...)
+	cloned.DenyTools = append([]string(nil), srv.DenyTools...)
+	cloned.AllowEnv = append([]string(nil), srv.AllowEnv...)
 	cloned.Env = cloneStringMap(srv.Env)
 	cloned.Headers = cloneStringMap(srv.Headers)
 	cloned.Tools = cloneToolMap(srv.Tools)
+	if srv.HTTP != nil {
+		httpCfg := *srv.HTTP
+		cloned.HTTP = &httpCfg
+	}
 	return cloned
 }
 
@@ -106,6 +163,12 @@ func cloneToolMap(in map[string]ToolConfig) map[string]ToolConfig {
 			val := *cfg.Cache
 			cloned.Cache = &val
 		}
+		if cfg.Defaults != nil {
+			cloned.Defaults = make(map[string]any, len(cfg.Defaults))
+			for key, value := range cfg.Defaults {
+				cloned.Defaults[key] = value
+			}
+		}
 		out[name] = cloned
 	}
 	return out
@@ -139,11 +202,119 @@ func validateServer(name string, srv ServerConfig) []error {
 		}
 	}
 
+	if srv.ErrorCacheTTL != "" {
+		ttl, err := time.ParseDuration(srv.ErrorCacheTTL)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("servers.%s.error_cache_ttl: invalid duration %q: %w", name, srv.ErrorCacheTTL, err))
+		} else if ttl <= 0 {
+			errs = append(errs, fmt.Errorf("servers.%s.error_cache_ttl: must be > 0, got %q", name, srv.ErrorCacheTTL))
+		}
+	}
+
+	if _, err := ParseCacheMode(srv.CacheMode); err != nil {
+		errs = append(errs, fmt.Errorf("servers.%s.cache_mode: %w", name, err))
+	}
+
+	if srv.StaleTTL != "" {
+		ttl, err := time.ParseDuration(srv.StaleTTL)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("servers.%s.stale_ttl: invalid duration %q: %w", name, srv.StaleTTL, err))
+		} else if ttl <= 0 {
+			errs = append(errs, fmt.Errorf("servers.%s.stale_ttl: must be > 0, got %q", name, srv.StaleTTL))
+		}
+	}
+
+	if srv.HealthCheckTimeout != "" {
+		timeout, err := time.ParseDuration(srv.HealthCheckTimeout)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("servers.%s.health_check_timeout: invalid duration %q: %w", name, srv.HealthCheckTimeout, err))
+		} else if timeout <= 0 {
+			errs = append(errs, fmt.Errorf("servers.%s.health_check_timeout: must be > 0, got %q", name, srv.HealthCheckTimeout))
+		}
+		if strings.TrimSpace(srv.HealthCheck) == "" {
+			errs = append(errs, fmt.Errorf("servers.%s.health_check_timeout: requires health_check", name))
+		}
+	}
+
+	if srv.RequestTimeout != "" {
+		timeout, err := time.ParseDuration(srv.RequestTimeout)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("servers.%s.request_timeout: invalid duration %q: %w", name, srv.RequestTimeout, err))
+		} else if timeout <= 0 {
+			errs = append(errs, fmt.Errorf("servers.%s.request_timeout: must be > 0, got %q", name, srv.RequestTimeout))
+		}
+	}
+
+	if srv.RetryMax < 0 {
+		errs = append(errs, fmt.Errorf("servers.%s.retry_max: must be >= 0, got %d", name, srv.RetryMax))
+	}
+	if srv.RetryBackoff != "" {
+		backoff, err := time.ParseDuration(srv.RetryBackoff)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("servers.%s.retry_backoff: invalid duration %q: %w", name, srv.RetryBackoff, err))
+		} else if backoff <= 0 {
+			errs = append(errs, fmt.Errorf("servers.%s.retry_backoff: must be > 0, got %q", name, srv.RetryBackoff))
+		}
+	}
+
+	if srv.PoolSize < 0 {
+		errs = append(errs, fmt.Errorf("servers.%s.pool_size: must be >= 0, got %d", name, srv.PoolSize))
+	} else if srv.PoolSize > 1 && hasURL {
+		errs = append(errs, fmt.Errorf("servers.%s.pool_size: only valid for command (stdio) servers", name))
+	}
+
+	if srv.HTTP != nil {
+		if hasCommand {
+			errs = append(errs, fmt.Errorf("servers.%s.http: only valid for url (http) servers", name))
+		}
+		if srv.HTTP.MaxIdleConns < 0 {
+			errs = append(errs, fmt.Errorf("servers.%s.http.max_idle_conns: must be >= 0, got %d", name, srv.HTTP.MaxIdleConns))
+		}
+		if srv.HTTP.MaxIdleConnsPerHost < 0 {
+			errs = append(errs, fmt.Errorf("servers.%s.http.max_idle_conns_per_host: must be >= 0, got %d", name, srv.HTTP.MaxIdleConnsPerHost))
+		}
+		if srv.HTTP.IdleConnTimeout != "" {
+			timeout, err := time.ParseDuration(srv.HTTP.IdleConnTimeout)
+			if err != nil {
+				errs = append(errs, fmt.Errorf("servers.%s.http.idle_conn_timeout: invalid duration %q: %w", name, srv.HTTP.IdleConnTimeout, err))
+			} else if timeout <= 0 {
+				errs = append(errs, fmt.Errorf("servers.%s.http.idle_conn_timeout: must be > 0, got %q", name, srv.HTTP.IdleConnTimeout))
+			}
+		}
+	}
+
 	for i, pattern := range srv.NoCacheTools {
 		if _, err := path.Match(pattern, "probe"); err != nil {
 			errs = append(errs, fmt.Errorf("servers.%s.no_cache_tools[%d]: invalid glob %q: %w", name, i, pattern, err))
 		}
 	}
+	for i, pattern := range srv.AllowTools {
+		if _, err := path.Match(pattern, "probe"); err != nil {
+			errs = append(errs, fmt.Errorf("servers.%s.allow_tools[%d]: invalid glob %q: %w", name, i, pattern, err))
+		}
+	}
+	for i, pattern := range srv.DenyTools {
+		if _, err := path.Match(pattern, "probe"); err != nil {
+			errs = append(errs, fmt.Errorf("servers.%s.deny_tools[%d]: invalid glob %q: %w", name, i, pattern, err))
+		}
+	}
+	for i, pattern := range srv.AllowEnv {
+		if _, err := path.Match(pattern, "probe"); err != nil {
+			errs = append(errs, fmt.Errorf("servers.%s.allow_env[%d]: invalid glob %q: %w", name, i, pattern, err))
+		}
+	}
+	for tool, tc := range srv.Tools {
+		for param := range tc.Defaults {
+			if strings.TrimSpace(param) == "" {
+				errs = append(errs, fmt.Errorf("servers.%s.tools.%s.defaults: parameter name must not be empty", name, tool))
+			}
+		}
+		if strings.TrimSpace(tc.IdempotencyKey) != "" {
+			if _, err := parseIdempotencyTarget(tc.IdempotencyKey); err != nil {
+				errs = append(errs, fmt.Errorf("servers.%s.tools.%s: %w", name, tool, err))
+			}
+		}
+	}
 
 	return errs
 }
@@ -57,6 +57,9 @@ func TestValidateRejectsInvalidURLTTLAndGlob(t *testing.T) {
 				URL:             "://bad-url",
 				DefaultCacheTTL: "abc",
 				NoCacheTools:    []string{"["},
+				AllowTools:      []string{"["},
+				DenyTools:       []string{"get_*", "["},
+				AllowEnv:        []string{"["},
 			},
 			"bad_ttl_zero": {
 				Command:         "npx",
@@ -80,6 +83,15 @@ func TestValidateRejectsInvalidURLTTLAndGlob(t *testing.T) {
 	if !strings.Contains(msg, "servers.bad.no_cache_tools[0]: invalid glob") {
 		t.Fatalf("Validate() error = %q, want invalid glob message", msg)
 	}
+	if !strings.Contains(msg, "servers.bad.allow_tools[0]: invalid glob") {
+		t.Fatalf("Validate() error = %q, want invalid allow_tools glob message", msg)
+	}
+	if !strings.Contains(msg, "servers.bad.deny_tools[1]: invalid glob") {
+		t.Fatalf("Validate() error = %q, want invalid deny_tools glob message", msg)
+	}
+	if !strings.Contains(msg, "servers.bad.allow_env[0]: invalid glob") {
+		t.Fatalf("Validate() error = %q, want invalid allow_env glob message", msg)
+	}
 	if !strings.Contains(msg, "servers.bad_ttl_zero.default_cache_ttl: must be > 0") {
 		t.Fatalf("Validate() error = %q, want non-positive TTL message", msg)
 	}
@@ -168,6 +180,61 @@ func TestServerConfigTransportPredicates(t *testing.T) {
 	}
 }
 
+func TestValidateServerConfigChecksHTTPTuning(t *testing.T) {
+	valid := ServerConfig{
+		URL:  "https://example.com/mcp",
+		HTTP: &HTTPConfig{MaxIdleConns: 50, MaxIdleConnsPerHost: 10, IdleConnTimeout: "30s"},
+	}
+	if err := ValidateServerConfig("remote", valid); err != nil {
+		t.Fatalf("ValidateServerConfig(valid http tuning) error = %v", err)
+	}
+
+	invalid := ServerConfig{
+		Command: "npx",
+		HTTP:    &HTTPConfig{MaxIdleConns: -1, IdleConnTimeout: "soon"},
+	}
+	err := ValidateServerConfig("local", invalid)
+	if err == nil {
+		t.Fatal("ValidateServerConfig(invalid http tuning) error = nil, want non-nil")
+	}
+	msg := err.Error()
+	for _, want := range []string{
+		"servers.local.http: only valid for url (http) servers",
+		"servers.local.http.max_idle_conns: must be >= 0",
+		"servers.local.http.idle_conn_timeout: invalid duration",
+	} {
+		if !strings.Contains(msg, want) {
+			t.Fatalf("ValidateServerConfig() error = %q, want %q", msg, want)
+		}
+	}
+}
+
+func TestServerConfigToolAllowed(t *testing.T) {
+	cases := []struct {
+		name string
+		cfg  ServerConfig
+		tool string
+		want bool
+	}{
+		{name: "no lists", cfg: ServerConfig{}, tool: "delete_repo", want: true},
+		{name: "allow-only match", cfg: ServerConfig{AllowTools: []string{"get_*", "list_*"}}, tool: "list_issues", want: true},
+		{name: "allow-only miss", cfg: ServerConfig{AllowTools: []string{"get_*", "list_*"}}, tool: "delete_repo", want: false},
+		{name: "deny-only match", cfg: ServerConfig{DenyTools: []string{"delete_*"}}, tool: "delete_repo", want: false},
+		{name: "deny-only miss", cfg: ServerConfig{DenyTools: []string{"delete_*"}}, tool: "get_repo", want: true},
+		{name: "deny wins over allow", cfg: ServerConfig{AllowTools: []string{"*"}, DenyTools: []string{"delete_*"}}, tool: "delete_repo", want: false},
+		{name: "allow with unrelated deny", cfg: ServerConfig{AllowTools: []string{"*"}, DenyTools: []string{"delete_*"}}, tool: "get_repo", want: true},
+	}
+
+	for _, tc := range cases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			if got := tc.cfg.ToolAllowed(tc.tool); got != tc.want {
+				t.Fatalf("ToolAllowed(%q) = %v, want %v", tc.tool, got, tc.want)
+			}
+		})
+	}
+}
+
 func TestCloneToolMapDeepCopiesCachePointers(t *testing.T) {
 	if got := cloneToolMap(nil); got != nil {
 		t.Fatalf("cloneToolMap(nil) = %#v, want nil", got)
@@ -198,3 +265,47 @@ func TestCloneToolMapDeepCopiesCachePointers(t *testing.T) {
 		t.Fatalf("cloneToolMap(in) cache pointer tracks source mutation: got %v, want true", *out["search"].Cache)
 	}
 }
+
+func TestValidateRejectsInvalidHealthCheckTimeout(t *testing.T) {
+	cfg := &Config{Servers: map[string]ServerConfig{
+		"github": {Command: "npx", HealthCheck: "ping", HealthCheckTimeout: "soon"},
+	}}
+	if err := Validate(cfg); err == nil || !strings.Contains(err.Error(), "health_check_timeout") {
+		t.Fatalf("Validate() error = %v, want health_check_timeout error", err)
+	}
+}
+
+func TestValidateRejectsInvalidRequestTimeouts(t *testing.T) {
+	cfg := &Config{
+		DefaultRequestTimeout: "-1s",
+		Servers: map[string]ServerConfig{
+			"github": {Command: "npx", RequestTimeout: "later"},
+		},
+	}
+	err := Validate(cfg)
+	if err == nil || !strings.Contains(err.Error(), "default_request_timeout") || !strings.Contains(err.Error(), "servers.github.request_timeout") {
+		t.Fatalf("Validate() error = %v, want default_request_timeout and request_timeout errors", err)
+	}
+
+	cfg.DefaultRequestTimeout = "30s"
+	cfg.Servers["github"] = ServerConfig{Command: "npx", RequestTimeout: "2m"}
+	if err := Validate(cfg); err != nil {
+		t.Fatalf("Validate(valid timeouts) error = %v", err)
+	}
+}
+
+func TestValidateRejectsInvalidIdempotencyKeyMapping(t *testing.T) {
+	cfg := &Config{Servers: map[string]ServerConfig{
+		"payments": {Command: "npx", Tools: map[string]ToolConfig{
+			"charge": {IdempotencyKey: "query:key"},
+		}},
+	}}
+	if err := Validate(cfg); err == nil || !strings.Contains(err.Error(), "servers.payments.tools.charge") {
+		t.Fatalf("Validate() error = %v, want idempotency_key error", err)
+	}
+
+	cfg.Servers["payments"].Tools["charge"] = ToolConfig{IdempotencyKey: "header:X-Request-Id"}
+	if err := Validate(cfg); err != nil {
+		t.Fatalf("Validate(header mapping) error = %v", err)
+	}
+}
@@ -0,0 +1,40 @@
+package daemon
+
+import (
+	"fmt"
+
+	"github.com/lydakis/mcpx/internal/cache"
+	"github.com/lydakis/mcpx/internal/ipc"
+)
+
+// clearCache removes every cached response when server is empty, else the
+// server's (or one of its tools') responses.
+func clearCache(server, tool string) (int, error) {
+	if server == "" {
+		return cache.Clear()
+	}
+	return cache.Delete(server, tool)
+}
+
+func cacheClearWithDeps(server, tool string, deps runtimeDeps) *ipc.Response {
+	if server == "" && tool != "" {
+		return &ipc.Response{ExitCode: ipc.ExitUsageErr, Stderr: "cache_clear: tool requires a server"}
+	}
+	removed, err := deps.cacheClear(server, tool)
+	if err != nil {
+		return &ipc.Response{ExitCode: ipc.ExitInternal, Stderr: fmt.Sprintf("clearing cache: %v", err)}
+	}
+
+	noun := "entries"
+	if removed == 1 {
+		noun = "entry"
+	}
+	scope := ""
+	switch {
+	case tool != "":
+		scope = fmt.Sprintf(" for %s %s", server, tool)
+	case server != "":
+		scope = fmt.Sprintf(" for %s", server)
+	}
+	return &ipc.Response{Content: []byte(fmt.Sprintf("removed %d cached %s%s\n", removed, noun, scope))}
+}
@@ -0,0 +1,51 @@
+package daemon
+
+import (
+	"context"
+	"testing"
+
+	"github.com/lydakis/mcpx/internal/config"
+	"github.com/lydakis/mcpx/internal/ipc"
+)
+
+func TestDispatchCacheClearScopesAndReportsCount(t *testing.T) {
+	ka := NewKeepalive(nil)
+	defer ka.Stop()
+
+	type clearCall struct{ server, tool string }
+	var calls []clearCall
+	deps := runtimeDefaultDeps()
+	deps.cacheClear = func(server, tool string) (int, error) {
+		calls = append(calls, clearCall{server, tool})
+		return len(server) % 3, nil
+	}
+
+	tests := []struct {
+		server, tool string
+		want         string
+	}{
+		{want: "removed 0 cached entries\n"},
+		{server: "gh", want: "removed 2 cached entries for gh\n"},
+		{server: "github", tool: "search", want: "removed 0 cached entries for github search\n"},
+		{server: "docs", tool: "get", want: "removed 1 cached entry for docs get\n"},
+	}
+	for _, tt := range tests {
+		calls = nil
+		req := &ipc.Request{Type: "cache_clear", Server: tt.server, Tool: tt.tool}
+		resp := dispatchWithDeps(context.Background(), &config.Config{}, nil, ka, req, deps)
+		if resp.ExitCode != ipc.ExitOK {
+			t.Fatalf("cache_clear(%q, %q) exit = %d, want %d (stderr=%q)", tt.server, tt.tool, resp.ExitCode, ipc.ExitOK, resp.Stderr)
+		}
+		if string(resp.Content) != tt.want {
+			t.Fatalf("cache_clear(%q, %q) content = %q, want %q", tt.server, tt.tool, resp.Content, tt.want)
+		}
+		if len(calls) != 1 || calls[0] != (clearCall{tt.server, tt.tool}) {
+			t.Fatalf("cacheClear calls = %#v, want one for %q %q", calls, tt.server, tt.tool)
+		}
+	}
+
+	resp := dispatchWithDeps(context.Background(), &config.Config{}, nil, ka, &ipc.Request{Type: "cache_clear", Tool: "search"}, deps)
+	if resp.ExitCode != ipc.ExitUsageErr {
+		t.Fatalf("cache_clear(tool only) exit = %d, want %d", resp.ExitCode, ipc.ExitUsageErr)
+	}
+}
@@ -0,0 +1,42 @@
+package daemon
+
+import (
+	"context"
+	"time"
+
+	"github.com/lydakis/mcpx/internal/config"
+)
+
+// defaultErrorCacheTTL is how long a cached tool error lives when the
+// server sets cache_errors without error_cache_ttl.
+const defaultErrorCacheTTL = 10 * time.Second
+
+type cacheErrorsCtx struct{}
+
+// withCacheErrors carries a call's --cache-errors (true) or
+// --no-cache-for-errors (false), which overrides the server's cache_errors.
+func withCacheErrors(ctx context.Context, enabled *bool) context.Context {
+	if enabled == nil {
+		return ctx
+	}
+	return context.WithValue(ctx, cacheErrorsCtx{}, *enabled)
+}
+
+// errorCacheTTL reports whether a tool error result may be cached, and for
+// how long. Errors are never cached unless the call or server opts in; the
+// TTL is error_cache_ttl (default 10s), capped at the success TTL.
+func errorCacheTTL(ctx context.Context, scfg config.ServerConfig, successTTL time.Duration) (time.Duration, bool) {
+	enabled := scfg.CacheErrors
+	if override, ok := ctx.Value(cacheErrorsCtx{}).(bool); ok {
+		enabled = override
+	}
+	if !enabled {
+		return 0, false
+	}
+
+	ttl := defaultErrorCacheTTL
+	if parsed, err := time.ParseDuration(scfg.ErrorCacheTTL); err == nil && parsed > 0 {
+		ttl = parsed
+	}
+	return min(ttl, successTTL), true
+}
@@ -0,0 +1,63 @@
+package daemon
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/lydakis/mcpx/internal/cache"
+	"github.com/lydakis/mcpx/internal/config"
+	"github.com/lydakis/mcpx/internal/ipc"
+	"github.com/lydakis/mcpx/internal/mcppool"
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+func TestCallToolCachesErrorResultsOnlyWhenOptedIn(t *testing.T) {
+	enabled, disabled := true, false
+	tests := []struct {
+		name     string
+		server   config.ServerConfig
+		override *bool
+		wantTTL  time.Duration // 0 means not stored
+	}{
+		{name: "default never caches errors", server: config.ServerConfig{DefaultCacheTTL: "1m"}},
+		{name: "cache_errors uses short default ttl", server: config.ServerConfig{DefaultCacheTTL: "1m", CacheErrors: true}, wantTTL: 10 * time.Second},
+		{name: "error ttl capped at success ttl", server: config.ServerConfig{DefaultCacheTTL: "5s", CacheErrors: true, ErrorCacheTTL: "30s"}, wantTTL: 5 * time.Second},
+		{name: "--cache-errors opts in per call", server: config.ServerConfig{DefaultCacheTTL: "1m", ErrorCacheTTL: "3s"}, override: &enabled, wantTTL: 3 * time.Second},
+		{name: "--no-cache-for-errors wins over config", server: config.ServerConfig{DefaultCacheTTL: "1m", CacheErrors: true}, override: &disabled},
+		{name: "no caching without a cache ttl", server: config.ServerConfig{CacheErrors: true}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := &config.Config{Servers: map[string]config.ServerConfig{"github": tt.server}}
+			ka := NewKeepalive(nil)
+			defer ka.Stop()
+
+			var storedTTL time.Duration
+			var storedExit int
+			deps := runtimeDefaultDeps()
+			deps.cacheGet = func(string, string, json.RawMessage) (cache.Response, bool) { return cache.Response{}, false }
+			deps.cachePut = func(_ string, _ string, _ json.RawMessage, resp cache.Response, ttl time.Duration) error {
+				storedTTL, storedExit = ttl, resp.ExitCode
+				return nil
+			}
+			deps.poolCallToolWithInfo = func(context.Context, *mcppool.Pool, string, *mcppool.ToolInfo, json.RawMessage) (*mcp.CallToolResult, error) {
+				return &mcp.CallToolResult{IsError: true, Content: []mcp.Content{mcp.TextContent{Type: "text", Text: "rate limited"}}}, nil
+			}
+
+			ctx := withCacheErrors(context.Background(), tt.override)
+			resp := callToolWithDeps(ctx, cfg, nil, ka, "github", "search", json.RawMessage(`{}`), nil, false, deps)
+			if resp.ExitCode != ipc.ExitToolErr {
+				t.Fatalf("callTool() exit = %d, want %d", resp.ExitCode, ipc.ExitToolErr)
+			}
+			if storedTTL != tt.wantTTL {
+				t.Fatalf("stored ttl = %s, want %s", storedTTL, tt.wantTTL)
+			}
+			if tt.wantTTL > 0 && storedExit != ipc.ExitToolErr {
+				t.Fatalf("stored exit code = %d, want %d", storedExit, ipc.ExitToolErr)
+			}
+		})
+	}
+}
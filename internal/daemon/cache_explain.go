@@ -0,0 +1,16 @@
+package daemon
+
+import "context"
+
+type explainCacheCtx struct{}
+
+// withExplainCache marks a call whose cache decision is reported in the
+// response stderr (--explain-cache).
+func withExplainCache(ctx context.Context) context.Context {
+	return context.WithValue(ctx, explainCacheCtx{}, true)
+}
+
+func explainCacheRequested(ctx context.Context) bool {
+	explain, _ := ctx.Value(explainCacheCtx{}).(bool)
+	return explain
+}
@@ -0,0 +1,33 @@
+package daemon
+
+import (
+	"context"
+	"time"
+)
+
+type cacheReadCtx struct{}
+
+// cacheRead overrides how one call reads the cache. fresh skips the lookup
+// but still stores the result (--fresh); maxAge serves any entry no older
+// than it, expired or not (--stale-ok).
+type cacheRead struct {
+	fresh  bool
+	maxAge time.Duration
+}
+
+// withCacheRead carries a call's --fresh or --stale-ok.
+func withCacheRead(ctx context.Context, fresh bool, staleOK *time.Duration) context.Context {
+	read := cacheRead{fresh: fresh}
+	if staleOK != nil {
+		read.maxAge = *staleOK
+	}
+	if read == (cacheRead{}) {
+		return ctx
+	}
+	return context.WithValue(ctx, cacheReadCtx{}, read)
+}
+
+func cacheReadFor(ctx context.Context) cacheRead {
+	read, _ := ctx.Value(cacheReadCtx{}).(cacheRead)
+	return read
+}
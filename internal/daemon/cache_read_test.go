@@ -0,0 +1,91 @@
+package daemon
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/lydakis/mcpx/internal/cache"
+	"github.com/lydakis/mcpx/internal/config"
+	"github.com/lydakis/mcpx/internal/ipc"
+	"github.com/lydakis/mcpx/internal/mcppool"
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+func TestCallToolCacheReadOverrides(t *testing.T) {
+	staleOK := func(d time.Duration) *time.Duration { return &d }
+	tests := []struct {
+		name      string
+		fresh     bool
+		staleOK   *time.Duration
+		wantOut   string
+		wantStore bool
+	}{
+		{name: "--fresh skips the hit and stores", fresh: true, wantOut: "fresh\n", wantStore: true},
+		{name: "--stale-ok serves an entry within the age", staleOK: staleOK(10 * time.Minute), wantOut: "cached"},
+		{name: "--stale-ok rejects an older entry", staleOK: staleOK(time.Minute), wantOut: "fresh\n", wantStore: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := &config.Config{Servers: map[string]config.ServerConfig{"github": {DefaultCacheTTL: "1m"}}}
+			ka := NewKeepalive(nil)
+			defer ka.Stop()
+
+			stored := false
+			deps := runtimeDefaultDeps()
+			deps.cacheGet = func(string, string, json.RawMessage) (cache.Response, bool) {
+				return cache.Response{Content: []byte("cached"), ExitCode: ipc.ExitOK}, true
+			}
+			// The entry is five minutes old and four minutes past its TTL.
+			deps.cacheGetStale = func(string, string, json.RawMessage, time.Duration) (cache.Hit, bool) {
+				return cache.Hit{Response: cache.Response{Content: []byte("cached")}, Age: 5 * time.Minute, TTL: time.Minute, Stale: 4 * time.Minute}, true
+			}
+			deps.cachePut = func(string, string, json.RawMessage, cache.Response, time.Duration) error {
+				stored = true
+				return nil
+			}
+			deps.poolCallToolWithInfo = func(context.Context, *mcppool.Pool, string, *mcppool.ToolInfo, json.RawMessage) (*mcp.CallToolResult, error) {
+				return &mcp.CallToolResult{Content: []mcp.Content{mcp.TextContent{Type: "text", Text: "fresh"}}}, nil
+			}
+
+			ctx := withCacheRead(context.Background(), tt.fresh, tt.staleOK)
+			resp := callToolWithDeps(ctx, cfg, nil, ka, "github", "search", json.RawMessage(`{}`), nil, false, deps)
+			if resp.ExitCode != ipc.ExitOK || string(resp.Content) != tt.wantOut {
+				t.Fatalf("callTool() = (%d, %q), want (0, %q)", resp.ExitCode, resp.Content, tt.wantOut)
+			}
+			if stored != tt.wantStore {
+				t.Fatalf("stored = %v, want %v", stored, tt.wantStore)
+			}
+		})
+	}
+}
+
+func TestCallToolStaleOKSkipsCacheForCombineContent(t *testing.T) {
+	cfg := &config.Config{Servers: map[string]config.ServerConfig{"github": {DefaultCacheTTL: "1m"}}}
+	ka := NewKeepalive(nil)
+	defer ka.Stop()
+
+	deps := runtimeDefaultDeps()
+	deps.cacheGetStale = func(string, string, json.RawMessage, time.Duration) (cache.Hit, bool) {
+		return cache.Hit{Response: cache.Response{Content: []byte("cached")}, Age: time.Second, TTL: time.Minute}, true
+	}
+	deps.cachePut = func(string, string, json.RawMessage, cache.Response, time.Duration) error {
+		t.Fatal("cachePut called, want no store for --combine-content")
+		return nil
+	}
+	deps.poolCallToolWithInfo = func(context.Context, *mcppool.Pool, string, *mcppool.ToolInfo, json.RawMessage) (*mcp.CallToolResult, error) {
+		return &mcp.CallToolResult{Content: []mcp.Content{mcp.TextContent{Type: "text", Text: "fresh"}}}, nil
+	}
+
+	maxAge := time.Hour
+	ctx := withCombineContent(withCacheRead(context.Background(), false, &maxAge), "json")
+	resp := callToolWithDeps(ctx, cfg, nil, ka, "github", "search", json.RawMessage(`{}`), nil, false, deps)
+	if resp.ExitCode != ipc.ExitOK || string(resp.Content) == "cached" {
+		t.Fatalf("callTool() = (%d, %q), want a fresh combined result", resp.ExitCode, resp.Content)
+	}
+	if resp.ContentType != "application/json" {
+		t.Fatalf("ContentType = %q, want application/json", resp.ContentType)
+	}
+}
@@ -0,0 +1,78 @@
+package daemon
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/lydakis/mcpx/internal/config"
+	"github.com/lydakis/mcpx/internal/mcppool"
+)
+
+type cacheScopeCtx struct{}
+
+type cacheScopeOption struct {
+	scope string
+	cwd   string
+}
+
+// withCacheScope records the request's --cache-scope override and working
+// directory for cache key derivation.
+func withCacheScope(ctx context.Context, scope, cwd string) context.Context {
+	return context.WithValue(ctx, cacheScopeCtx{}, cacheScopeOption{scope: scope, cwd: cwd})
+}
+
+// cacheKeyServer returns the server component of a tool call's cache key.
+// In the global scope it is the server name. In the cwd scope it also
+// carries a digest of the request directory and the resolved server
+// definition, so fallback servers that share a name across projects do not
+// serve each other's cached responses. Per-call headers (--header,
+// --headers-from-file, an Idempotency-Key header) add a digest of their
+// own, so calls made as different identities never share an entry.
+func cacheKeyServer(ctx context.Context, cfg *config.Config, server string, scfg config.ServerConfig) (string, error) {
+	opt, _ := ctx.Value(cacheScopeCtx{}).(cacheScopeOption)
+	raw := opt.scope
+	if raw == "" && cfg != nil {
+		raw = cfg.CacheScope
+	}
+	scope, err := config.ParseCacheScope(raw)
+	if err != nil {
+		return "", err
+	}
+	key := server
+	if scope != config.CacheScopeGlobal {
+		def, err := json.Marshal(scfg)
+		if err != nil {
+			return "", fmt.Errorf("fingerprinting server %s: %w", server, err)
+		}
+		h := sha256.New()
+		fmt.Fprintf(h, "%s\x00%s", opt.cwd, def)
+		key += "@" + hex.EncodeToString(h.Sum(nil))[:16]
+	}
+	if headers := mcppool.RequestHeaders(ctx); len(headers) > 0 {
+		key += "@h" + headersDigest(headers)
+	}
+	return key, nil
+}
+
+// headersDigest fingerprints per-call headers independent of map order and
+// header name case.
+func headersDigest(headers map[string]string) string {
+	names := make([]string, 0, len(headers))
+	values := make(map[string]string, len(headers))
+	for name, value := range headers {
+		lower := strings.ToLower(name)
+		names = append(names, lower)
+		values[lower] = value
+	}
+	sort.Strings(names)
+	h := sha256.New()
+	for _, name := range names {
+		fmt.Fprintf(h, "%s\x00%s\x00", name, values[name])
+	}
+	return hex.EncodeToString(h.Sum(nil))[:16]
+}
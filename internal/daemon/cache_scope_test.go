@@ -0,0 +1,136 @@
+package daemon
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/lydakis/mcpx/internal/cache"
+	"github.com/lydakis/mcpx/internal/config"
+	"github.com/lydakis/mcpx/internal/ipc"
+	"github.com/lydakis/mcpx/internal/mcppool"
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+func cacheScopeKeysForCalls(t *testing.T, cfg *config.Config, scope string, cwds ...string) []string {
+	t.Helper()
+	ka := NewKeepalive(nil)
+	defer ka.Stop()
+
+	var keys []string
+	deps := runtimeDefaultDeps()
+	deps.poolCallToolWithInfo = func(context.Context, *mcppool.Pool, string, *mcppool.ToolInfo, json.RawMessage) (*mcp.CallToolResult, error) {
+		return mcp.NewToolResultText("ok"), nil
+	}
+	deps.cacheGet = func(server, _ string, _ json.RawMessage) (cache.Response, bool) {
+		keys = append(keys, server)
+		return cache.Response{}, false
+	}
+	deps.cachePut = func(string, string, json.RawMessage, cache.Response, time.Duration) error { return nil }
+
+	reqCache := 30 * time.Second
+	for _, cwd := range cwds {
+		ctx := withCacheScope(context.Background(), scope, cwd)
+		resp := callToolWithDeps(ctx, cfg, nil, ka, "docs", "search", json.RawMessage(`{"q":"x"}`), &reqCache, false, deps)
+		if resp.ExitCode != ipc.ExitOK {
+			t.Fatalf("callTool() exit = %d, want %d (stderr=%q)", resp.ExitCode, ipc.ExitOK, resp.Stderr)
+		}
+	}
+	return keys
+}
+
+func TestCallToolGlobalCacheScopeSharesKeyAcrossDirectories(t *testing.T) {
+	cfg := &config.Config{Servers: map[string]config.ServerConfig{"docs": {Command: "docs-mcp"}}}
+
+	keys := cacheScopeKeysForCalls(t, cfg, "", "/work/a", "/work/b")
+	if len(keys) != 2 || keys[0] != "docs" || keys[1] != "docs" {
+		t.Fatalf("cache keys = %q, want the bare server name for both directories", keys)
+	}
+}
+
+func TestCallToolCWDCacheScopeSeparatesDirectories(t *testing.T) {
+	cfg := &config.Config{Servers: map[string]config.ServerConfig{"docs": {Command: "docs-mcp"}}}
+
+	keys := cacheScopeKeysForCalls(t, cfg, config.CacheScopeCWD, "/work/a", "/work/a", "/work/b")
+	if len(keys) != 3 {
+		t.Fatalf("cache keys = %q, want 3 lookups", keys)
+	}
+	if keys[0] != keys[1] {
+		t.Fatalf("same directory keys = %q and %q, want equal", keys[0], keys[1])
+	}
+	if keys[0] == keys[2] {
+		t.Fatalf("different directory keys = %q, want distinct", keys[0])
+	}
+	if !strings.HasPrefix(keys[0], "docs@") {
+		t.Fatalf("cwd key = %q, want docs@<digest>", keys[0])
+	}
+
+	// A different server definition under the same name and directory must
+	// not reuse the entry either.
+	other := &config.Config{Servers: map[string]config.ServerConfig{"docs": {Command: "other-docs-mcp"}}}
+	if got := cacheScopeKeysForCalls(t, other, config.CacheScopeCWD, "/work/a"); got[0] == keys[0] {
+		t.Fatalf("key for a different server definition = %q, want distinct from %q", got[0], keys[0])
+	}
+}
+
+func TestCallToolCacheScopeDefaultsFromConfig(t *testing.T) {
+	cfg := &config.Config{
+		CacheScope: config.CacheScopeCWD,
+		Servers:    map[string]config.ServerConfig{"docs": {Command: "docs-mcp"}},
+	}
+
+	if keys := cacheScopeKeysForCalls(t, cfg, "", "/work/a"); !strings.HasPrefix(keys[0], "docs@") {
+		t.Fatalf("cache key = %q, want cwd-scoped key from config default", keys[0])
+	}
+	if keys := cacheScopeKeysForCalls(t, cfg, config.CacheScopeGlobal, "/work/a"); keys[0] != "docs" {
+		t.Fatalf("cache key = %q, want --cache-scope global to override config", keys[0])
+	}
+}
+
+func TestCallToolPerCallHeadersSeparateCacheKeys(t *testing.T) {
+	cfg := &config.Config{Servers: map[string]config.ServerConfig{"docs": {URL: "https://docs.example.com/mcp"}}}
+	ka := NewKeepalive(nil)
+	defer ka.Stop()
+
+	var keys []string
+	deps := runtimeDefaultDeps()
+	deps.poolCallToolWithInfo = func(context.Context, *mcppool.Pool, string, *mcppool.ToolInfo, json.RawMessage) (*mcp.CallToolResult, error) {
+		return mcp.NewToolResultText("ok"), nil
+	}
+	deps.cacheGet = func(server, _ string, _ json.RawMessage) (cache.Response, bool) {
+		keys = append(keys, server)
+		return cache.Response{}, false
+	}
+	deps.cachePut = func(string, string, json.RawMessage, cache.Response, time.Duration) error { return nil }
+
+	reqCache := 30 * time.Second
+	for _, headers := range []map[string]string{
+		{"Authorization": "Bearer alice"},
+		{"authorization": "Bearer alice"},
+		{"Authorization": "Bearer bob"},
+		nil,
+	} {
+		ctx := context.Background()
+		if headers != nil {
+			ctx = mcppool.WithRequestHeaders(ctx, headers)
+		}
+		resp := callToolWithDeps(ctx, cfg, nil, ka, "docs", "search", json.RawMessage(`{"q":"x"}`), &reqCache, false, deps)
+		if resp.ExitCode != ipc.ExitOK {
+			t.Fatalf("callTool() exit = %d, want %d (stderr=%q)", resp.ExitCode, ipc.ExitOK, resp.Stderr)
+		}
+	}
+	if len(keys) != 4 {
+		t.Fatalf("cache keys = %q, want 4 lookups", keys)
+	}
+	if keys[0] != keys[1] {
+		t.Fatalf("keys for the same header = %q and %q, want equal", keys[0], keys[1])
+	}
+	if keys[0] == keys[2] {
+		t.Fatalf("keys for different Authorization headers = %q, want distinct", keys[0])
+	}
+	if keys[3] != "docs" || keys[0] == keys[3] {
+		t.Fatalf("keys = %q, want the bare server name only without headers", keys)
+	}
+}
@@ -0,0 +1,22 @@
+package daemon
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/lydakis/mcpx/internal/ipc"
+)
+
+// cacheStatsWithDeps reports the cache size on disk and the daemon's
+// hit, miss, and store counters as JSON.
+func cacheStatsWithDeps(deps runtimeDeps) *ipc.Response {
+	stats, err := deps.cacheStats()
+	if err != nil {
+		return &ipc.Response{ExitCode: ipc.ExitInternal, Stderr: fmt.Sprintf("reading cache stats: %v", err)}
+	}
+	data, err := json.Marshal(stats)
+	if err != nil {
+		return &ipc.Response{ExitCode: ipc.ExitInternal, Stderr: fmt.Sprintf("encoding cache stats: %v", err)}
+	}
+	return &ipc.Response{Content: data}
+}
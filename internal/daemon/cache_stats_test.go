@@ -0,0 +1,29 @@
+package daemon
+
+import (
+	"context"
+	"testing"
+
+	"github.com/lydakis/mcpx/internal/cache"
+	"github.com/lydakis/mcpx/internal/config"
+	"github.com/lydakis/mcpx/internal/ipc"
+)
+
+func TestDispatchCacheStatsReturnsJSON(t *testing.T) {
+	ka := NewKeepalive(nil)
+	defer ka.Stop()
+
+	deps := runtimeDefaultDeps()
+	deps.cacheStats = func() (cache.StatsSnapshot, error) {
+		return cache.StatsSnapshot{Entries: 2, Bytes: 512, Hits: 3, Misses: 1, Stores: 2, HitRate: 0.75}, nil
+	}
+
+	resp := dispatchWithDeps(context.Background(), &config.Config{}, nil, ka, &ipc.Request{Type: "cache_stats"}, deps)
+	if resp.ExitCode != ipc.ExitOK {
+		t.Fatalf("cache_stats exit = %d, want %d (stderr=%q)", resp.ExitCode, ipc.ExitOK, resp.Stderr)
+	}
+	want := `{"entries":2,"bytes":512,"hits":3,"misses":1,"stores":2,"hit_rate":0.75}`
+	if string(resp.Content) != want {
+		t.Fatalf("cache_stats content = %s, want %s", resp.Content, want)
+	}
+}
@@ -0,0 +1,88 @@
+package daemon
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/lydakis/mcpx/internal/ipc"
+	"github.com/lydakis/mcpx/internal/mcppool"
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+// callTimeouts bounds one call_tool request. total caps the whole dispatch
+// (connect, tool lookup, and every attempt); attempt caps a single tools/call.
+type callTimeouts struct {
+	total   time.Duration
+	attempt time.Duration
+}
+
+func callTimeoutsFromRequest(req *ipc.Request) callTimeouts {
+	var t callTimeouts
+	if req == nil {
+		return t
+	}
+	if req.Timeout != nil && *req.Timeout > 0 {
+		t.total = *req.Timeout
+	}
+	if req.AttemptTimeout != nil && *req.AttemptTimeout > 0 {
+		t.attempt = *req.AttemptTimeout
+	}
+	return t
+}
+
+// withCallTimeouts applies the total budget to ctx and wraps the pool call so
+// each attempt gets its own deadline. An attempt that times out is retried
+// while the total budget (or the caller's context) still has time left.
+func withCallTimeouts(ctx context.Context, t callTimeouts, deps runtimeDeps) (context.Context, context.CancelFunc, runtimeDeps) {
+	cancel := context.CancelFunc(func() {})
+	if t.total > 0 {
+		ctx, cancel = context.WithTimeout(ctx, t.total)
+	}
+	if t.total <= 0 && t.attempt <= 0 {
+		return ctx, cancel, deps
+	}
+
+	if t.total > 0 {
+		toolInfo := deps.poolToolInfoByName
+		deps.poolToolInfoByName = func(ctx context.Context, pool *mcppool.Pool, server, tool string) (*mcppool.ToolInfo, error) {
+			info, err := toolInfo(ctx, pool, server, tool)
+			if err != nil && errors.Is(ctx.Err(), context.DeadlineExceeded) {
+				return nil, fmt.Errorf("total timeout %s exceeded: %w", t.total, err)
+			}
+			return info, err
+		}
+	}
+
+	call := deps.poolCallToolWithInfo
+	deps.poolCallToolWithInfo = func(ctx context.Context, pool *mcppool.Pool, server string, info *mcppool.ToolInfo, args json.RawMessage) (*mcp.CallToolResult, error) {
+		for attempt := 1; ; attempt++ {
+			attemptCtx, attemptCancel := ctx, context.CancelFunc(func() {})
+			if t.attempt > 0 {
+				attemptCtx, attemptCancel = context.WithTimeout(ctx, t.attempt)
+			}
+			result, err := call(attemptCtx, pool, server, info, args)
+			attemptExpired := errors.Is(attemptCtx.Err(), context.DeadlineExceeded)
+			attemptCancel()
+
+			if err == nil {
+				return result, nil
+			}
+			if ctx.Err() != nil {
+				if t.total > 0 && errors.Is(ctx.Err(), context.DeadlineExceeded) {
+					return nil, fmt.Errorf("total timeout %s exceeded after %d attempt(s): %w", t.total, attempt, err)
+				}
+				return nil, err
+			}
+			if !attemptExpired {
+				return nil, err
+			}
+			if t.total <= 0 {
+				return nil, fmt.Errorf("attempt timeout %s exceeded: %w", t.attempt, err)
+			}
+		}
+	}
+	return ctx, cancel, deps
+}
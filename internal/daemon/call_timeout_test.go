@@ -0,0 +1,168 @@
+package daemon
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/lydakis/mcpx/internal/config"
+	"github.com/lydakis/mcpx/internal/ipc"
+	"github.com/lydakis/mcpx/internal/mcppool"
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+func TestDispatchCallToolRetriesTimedOutAttemptWithinTotalBudget(t *testing.T) {
+	cfg := &config.Config{Servers: map[string]config.ServerConfig{"github": {}}}
+	ka := NewKeepalive(nil)
+	defer ka.Stop()
+
+	attempts := 0
+	deps := runtimeDefaultDeps()
+	deps.poolCallToolWithInfo = func(ctx context.Context, _ *mcppool.Pool, _ string, _ *mcppool.ToolInfo, _ json.RawMessage) (*mcp.CallToolResult, error) {
+		attempts++
+		if attempts == 1 {
+			<-ctx.Done()
+			return nil, ctx.Err()
+		}
+		return &mcp.CallToolResult{Content: []mcp.Content{mcp.TextContent{Type: "text", Text: "ok"}}}, nil
+	}
+
+	total := 5 * time.Second
+	attempt := 20 * time.Millisecond
+	resp := dispatchWithDeps(context.Background(), cfg, nil, ka, &ipc.Request{
+		Type:           "call_tool",
+		Server:         "github",
+		Tool:           "search",
+		Timeout:        &total,
+		AttemptTimeout: &attempt,
+	}, deps)
+	if resp.ExitCode != ipc.ExitOK {
+		t.Fatalf("dispatch exit = %d, want %d (stderr=%q)", resp.ExitCode, ipc.ExitOK, resp.Stderr)
+	}
+	if attempts != 2 {
+		t.Fatalf("attempts = %d, want 2", attempts)
+	}
+	if string(resp.Content) != "ok\n" {
+		t.Fatalf("content = %q, want %q", resp.Content, "ok\n")
+	}
+}
+
+func TestDispatchCallToolStopsRetryingWhenTotalBudgetIsSpent(t *testing.T) {
+	cfg := &config.Config{Servers: map[string]config.ServerConfig{"github": {}}}
+	ka := NewKeepalive(nil)
+	defer ka.Stop()
+
+	attempts := 0
+	deps := runtimeDefaultDeps()
+	deps.poolCallToolWithInfo = func(ctx context.Context, _ *mcppool.Pool, _ string, _ *mcppool.ToolInfo, _ json.RawMessage) (*mcp.CallToolResult, error) {
+		attempts++
+		<-ctx.Done()
+		return nil, ctx.Err()
+	}
+
+	total := 100 * time.Millisecond
+	attempt := 30 * time.Millisecond
+	resp := dispatchWithDeps(context.Background(), cfg, nil, ka, &ipc.Request{
+		Type:           "call_tool",
+		Server:         "github",
+		Tool:           "search",
+		Timeout:        &total,
+		AttemptTimeout: &attempt,
+	}, deps)
+	if resp.ExitCode != ipc.ExitInternal {
+		t.Fatalf("dispatch exit = %d, want %d", resp.ExitCode, ipc.ExitInternal)
+	}
+	if !strings.Contains(resp.Stderr, "total timeout 100ms exceeded") {
+		t.Fatalf("stderr = %q, want total timeout message", resp.Stderr)
+	}
+	if attempts < 2 {
+		t.Fatalf("attempts = %d, want retries before the budget ran out", attempts)
+	}
+}
+
+func TestDispatchCallToolAttemptTimeoutWithoutTotalDoesNotRetry(t *testing.T) {
+	cfg := &config.Config{Servers: map[string]config.ServerConfig{"github": {}}}
+	ka := NewKeepalive(nil)
+	defer ka.Stop()
+
+	attempts := 0
+	deps := runtimeDefaultDeps()
+	deps.poolCallToolWithInfo = func(ctx context.Context, _ *mcppool.Pool, _ string, _ *mcppool.ToolInfo, _ json.RawMessage) (*mcp.CallToolResult, error) {
+		attempts++
+		<-ctx.Done()
+		return nil, ctx.Err()
+	}
+
+	attempt := 10 * time.Millisecond
+	resp := dispatchWithDeps(context.Background(), cfg, nil, ka, &ipc.Request{
+		Type:           "call_tool",
+		Server:         "github",
+		Tool:           "search",
+		AttemptTimeout: &attempt,
+	}, deps)
+	if resp.ExitCode != ipc.ExitInternal {
+		t.Fatalf("dispatch exit = %d, want %d", resp.ExitCode, ipc.ExitInternal)
+	}
+	if attempts != 1 {
+		t.Fatalf("attempts = %d, want 1", attempts)
+	}
+	if !strings.Contains(resp.Stderr, "attempt timeout 10ms exceeded") {
+		t.Fatalf("stderr = %q, want attempt timeout message", resp.Stderr)
+	}
+}
+
+func TestDispatchCallToolTimeoutReplacesServerRequestTimeout(t *testing.T) {
+	cfg := &config.Config{Servers: map[string]config.ServerConfig{"github": {RequestTimeout: "20ms"}}}
+	ka := NewKeepalive(nil)
+	defer ka.Stop()
+
+	deps := runtimeDefaultDeps()
+	deps.poolCallToolWithInfo = func(ctx context.Context, _ *mcppool.Pool, _ string, _ *mcppool.ToolInfo, _ json.RawMessage) (*mcp.CallToolResult, error) {
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-time.After(60 * time.Millisecond):
+			return &mcp.CallToolResult{Content: []mcp.Content{mcp.TextContent{Type: "text", Text: "ok"}}}, nil
+		}
+	}
+
+	for _, timeout := range []time.Duration{0, 5 * time.Second} {
+		resp := dispatchWithDeps(context.Background(), cfg, nil, ka, &ipc.Request{
+			Type:    "call_tool",
+			Server:  "github",
+			Tool:    "search",
+			Timeout: &timeout,
+		}, deps)
+		if resp.ExitCode != ipc.ExitOK {
+			t.Fatalf("--timeout=%s exit = %d, want %d (stderr=%q)", timeout, resp.ExitCode, ipc.ExitOK, resp.Stderr)
+		}
+	}
+}
+
+func TestDispatchCallToolTimeoutNamesBudgetWhenLookupExpires(t *testing.T) {
+	cfg := &config.Config{Servers: map[string]config.ServerConfig{"github": {}}}
+	ka := NewKeepalive(nil)
+	defer ka.Stop()
+
+	deps := runtimeDefaultDeps()
+	deps.poolToolInfoByName = func(ctx context.Context, _ *mcppool.Pool, _, _ string) (*mcppool.ToolInfo, error) {
+		<-ctx.Done()
+		return nil, ctx.Err()
+	}
+
+	total := 20 * time.Millisecond
+	resp := dispatchWithDeps(context.Background(), cfg, &mcppool.Pool{}, ka, &ipc.Request{
+		Type:    "call_tool",
+		Server:  "github",
+		Tool:    "search",
+		Timeout: &total,
+	}, deps)
+	if resp.ExitCode != ipc.ExitInternal {
+		t.Fatalf("dispatch exit = %d, want %d", resp.ExitCode, ipc.ExitInternal)
+	}
+	if !strings.Contains(resp.Stderr, "total timeout 20ms exceeded") {
+		t.Fatalf("stderr = %q, want total timeout message", resp.Stderr)
+	}
+}
@@ -0,0 +1,37 @@
+package daemon
+
+import (
+	"strings"
+	"sync"
+
+	"github.com/lydakis/mcpx/internal/ipc"
+)
+
+// stderrCapture collects a stdio server's stderr lines for one call.
+type stderrCapture struct {
+	server string
+	mu     sync.Mutex
+	lines  []string
+}
+
+func (c *stderrCapture) add(line string) {
+	c.mu.Lock()
+	c.lines = append(c.lines, "["+c.server+"] "+line)
+	c.mu.Unlock()
+}
+
+// attach puts the captured lines ahead of any stderr the response carries.
+func (c *stderrCapture) attach(resp *ipc.Response) *ipc.Response {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if resp == nil || len(c.lines) == 0 {
+		return resp
+	}
+	captured := strings.Join(c.lines, "\n")
+	if resp.Stderr == "" {
+		resp.Stderr = captured
+	} else {
+		resp.Stderr = captured + "\n" + resp.Stderr
+	}
+	return resp
+}
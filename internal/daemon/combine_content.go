@@ -0,0 +1,19 @@
+package daemon
+
+import "context"
+
+type combineContentCtx struct{}
+
+// withCombineContent carries a call's --combine-content mode, which chooses
+// how a result with several content blocks is joined.
+func withCombineContent(ctx context.Context, mode string) context.Context {
+	if mode == "" {
+		return ctx
+	}
+	return context.WithValue(ctx, combineContentCtx{}, mode)
+}
+
+func combineContentMode(ctx context.Context) string {
+	mode, _ := ctx.Value(combineContentCtx{}).(string)
+	return mode
+}
@@ -0,0 +1,52 @@
+package daemon
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/lydakis/mcpx/internal/cache"
+	"github.com/lydakis/mcpx/internal/config"
+	"github.com/lydakis/mcpx/internal/ipc"
+	"github.com/lydakis/mcpx/internal/mcppool"
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+func TestDispatchCallToolCombinesContentBlocksAndSkipsCache(t *testing.T) {
+	cfg := &config.Config{Servers: map[string]config.ServerConfig{"github": {DefaultCacheTTL: "1m"}}}
+	ka := NewKeepalive(nil)
+	defer ka.Stop()
+
+	deps := runtimeDefaultDeps()
+	deps.cacheGet = func(string, string, json.RawMessage) (cache.Response, bool) {
+		t.Fatal("cacheGet called with --combine-content")
+		return cache.Response{}, false
+	}
+	deps.cachePut = func(string, string, json.RawMessage, cache.Response, time.Duration) error {
+		t.Fatal("cachePut called with --combine-content")
+		return nil
+	}
+	deps.poolCallToolWithInfo = func(context.Context, *mcppool.Pool, string, *mcppool.ToolInfo, json.RawMessage) (*mcp.CallToolResult, error) {
+		return &mcp.CallToolResult{Content: []mcp.Content{
+			mcp.TextContent{Type: "text", Text: "alpha"},
+			mcp.TextContent{Type: "text", Text: "beta"},
+		}}, nil
+	}
+
+	resp := dispatchWithDeps(context.Background(), cfg, nil, ka, &ipc.Request{
+		Type:           "call_tool",
+		Server:         "github",
+		Tool:           "search",
+		CombineContent: "json",
+	}, deps)
+	if resp.ExitCode != ipc.ExitOK {
+		t.Fatalf("dispatch exit = %d, want %d (stderr=%q)", resp.ExitCode, ipc.ExitOK, resp.Stderr)
+	}
+	if string(resp.Content) != "[\"alpha\",\"beta\"]\n" {
+		t.Fatalf("content = %q, want JSON array of blocks", resp.Content)
+	}
+	if resp.ContentType != "application/json" {
+		t.Fatalf("content type = %q, want application/json", resp.ContentType)
+	}
+}
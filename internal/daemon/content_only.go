@@ -0,0 +1,40 @@
+package daemon
+
+import (
+	"context"
+
+	"github.com/lydakis/mcpx/internal/ipc"
+	"github.com/lydakis/mcpx/internal/response"
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+type contentOnlyCtx struct{}
+
+// withContentOnly carries a call's --structured-only or --text-only choice
+// as a response.Only* kind.
+func withContentOnly(ctx context.Context, kind string) context.Context {
+	if kind == "" {
+		return ctx
+	}
+	return context.WithValue(ctx, contentOnlyCtx{}, kind)
+}
+
+func contentOnlyKind(ctx context.Context) string {
+	kind, _ := ctx.Value(contentOnlyCtx{}).(string)
+	return kind
+}
+
+// unwrapContentOnly is response.UnwrapOnly shaped as a call_tool response.
+func unwrapContentOnly(result *mcp.CallToolResult, kind, mode string, logs []string) *ipc.Response {
+	out, exitCode, hint, err := response.UnwrapOnly(result, kind, mode)
+	if err != nil {
+		return &ipc.Response{ExitCode: exitCode, Stderr: joinLogs(append(logs, err.Error()))}
+	}
+	return &ipc.Response{
+		Content:     out,
+		ExitCode:    exitCode,
+		Stderr:      joinLogs(logs),
+		ContentType: hint.ContentType,
+		Encoding:    hint.Encoding,
+	}
+}
@@ -6,11 +6,14 @@ import (
 	"encoding/hex"
 	"encoding/json"
 	"fmt"
+	"log/slog"
+	"maps"
 	"os"
 	"os/signal"
 	"path"
 	"path/filepath"
 	"sort"
+	"strconv"
 	"strings"
 	"sync"
 	"syscall"
@@ -42,9 +45,18 @@ type runtimeDeps struct {
 	poolListTools             func(ctx context.Context, pool *mcppool.Pool, server string) ([]mcppool.ToolInfo, error)
 	poolToolInfoByName        func(ctx context.Context, pool *mcppool.Pool, server, tool string) (*mcppool.ToolInfo, error)
 	poolCallToolWithInfo      func(ctx context.Context, pool *mcppool.Pool, server string, info *mcppool.ToolInfo, args json.RawMessage) (*mcp.CallToolResult, error)
-	cacheGet                  func(server, tool string, args json.RawMessage) ([]byte, int, bool)
+	poolListResources         func(ctx context.Context, pool *mcppool.Pool, server string) ([]mcppool.ResourceInfo, error)
+	poolListPrompts           func(ctx context.Context, pool *mcppool.Pool, server string) ([]mcppool.PromptInfo, error)
+	poolGetPrompt             func(ctx context.Context, pool *mcppool.Pool, server, name string, args json.RawMessage) (*mcp.GetPromptResult, error)
+	poolServerState           func(pool *mcppool.Pool, server string) mcppool.ServerState
+	poolConnectionCount       func(pool *mcppool.Pool, server string) int
+	poolLastError             func(pool *mcppool.Pool, server string) (mcppool.LastError, bool)
+	cacheGet                  func(server, tool string, args json.RawMessage) (cache.Response, bool)
 	cacheGetMetadata          func(server, tool string, args json.RawMessage) (time.Duration, time.Duration, bool)
-	cachePut                  func(server, tool string, args json.RawMessage, content []byte, exitCode int, ttl time.Duration) error
+	cacheGetStale             func(server, tool string, args json.RawMessage, window time.Duration) (cache.Hit, bool)
+	cachePut                  func(server, tool string, args json.RawMessage, resp cache.Response, ttl time.Duration) error
+	cacheClear                func(server, tool string) (int, error)
+	cacheStats                func() (cache.StatsSnapshot, error)
 	poolReset                 func(pool *mcppool.Pool, cfg *config.Config)
 	poolSetConfig             func(pool *mcppool.Pool, cfg *config.Config)
 	poolClose                 func(pool *mcppool.Pool, server string)
@@ -55,6 +67,15 @@ type runtimeDeps struct {
 	currentRuntimeConfigStamp func(cfg *config.Config, cwd string) runtimeConfigStamp
 	now                       func() time.Time
 	signalShutdownProcess     func()
+	// recordToolUse and toolUseCounts track call_tool invocations for
+	// usage-sorted tool listings.
+	recordToolUse func(server, tool string)
+	toolUseCounts func(server string) map[string]int
+	// serverLastUse and usageTrackedSince expose per-server call timestamps
+	// for `mcpx --unused`; usageTrackedSince reports false when calls are
+	// not tracked.
+	serverLastUse     func(server string) (time.Time, bool)
+	usageTrackedSince func() (time.Time, bool)
 }
 
 func runtimeDefaultDeps() runtimeDeps {
@@ -66,11 +87,32 @@ func runtimeDefaultDeps() runtimeDeps {
 			return pool.ToolInfoByName(ctx, server, tool)
 		},
 		poolCallToolWithInfo: func(ctx context.Context, pool *mcppool.Pool, server string, info *mcppool.ToolInfo, args json.RawMessage) (*mcp.CallToolResult, error) {
-			return pool.CallToolWithInfo(ctx, server, info, args)
+			return pool.CallToolWithInfo(ctx, server, info, args, progressSink(ctx))
+		},
+		poolListResources: func(ctx context.Context, pool *mcppool.Pool, server string) ([]mcppool.ResourceInfo, error) {
+			return pool.ListResources(ctx, server)
+		},
+		poolListPrompts: func(ctx context.Context, pool *mcppool.Pool, server string) ([]mcppool.PromptInfo, error) {
+			return pool.ListPrompts(ctx, server)
+		},
+		poolGetPrompt: func(ctx context.Context, pool *mcppool.Pool, server, name string, args json.RawMessage) (*mcp.GetPromptResult, error) {
+			return pool.GetPrompt(ctx, server, name, args)
+		},
+		poolServerState: func(pool *mcppool.Pool, server string) mcppool.ServerState {
+			return pool.State(server)
+		},
+		poolConnectionCount: func(pool *mcppool.Pool, server string) int {
+			return pool.ConnectionCount(server)
+		},
+		poolLastError: func(pool *mcppool.Pool, server string) (mcppool.LastError, bool) {
+			return pool.LastError(server)
 		},
 		cacheGet:         cache.Get,
 		cacheGetMetadata: cache.GetMetadata,
+		cacheGetStale:    cache.GetStale,
 		cachePut:         cache.Put,
+		cacheClear:       clearCache,
+		cacheStats:       cache.Stats,
 		poolReset: func(pool *mcppool.Pool, cfg *config.Config) {
 			if pool != nil {
 				pool.Reset(cfg)
@@ -91,7 +133,7 @@ func runtimeDefaultDeps() runtimeDeps {
 				ka.Stop()
 			}
 		},
-		loadConfig:                config.Load,
+		loadConfig:                config.LoadWithoutEnvServers,
 		mergeFallbackForCWD:       config.MergeFallbackServersForCWD,
 		validateConfig:            config.Validate,
 		currentRuntimeConfigStamp: currentRuntimeConfigStamp,
@@ -100,6 +142,10 @@ func runtimeDefaultDeps() runtimeDeps {
 			p, _ := os.FindProcess(os.Getpid())
 			_ = p.Signal(syscall.SIGTERM)
 		},
+		recordToolUse:     func(string, string) {},
+		toolUseCounts:     func(string) map[string]int { return nil },
+		serverLastUse:     func(string) (time.Time, bool) { return time.Time{}, false },
+		usageTrackedSince: func() (time.Time, bool) { return time.Time{}, false },
 	}
 }
 
@@ -114,15 +160,42 @@ func (d runtimeDeps) withDefaults() runtimeDeps {
 	if d.poolCallToolWithInfo == nil {
 		d.poolCallToolWithInfo = def.poolCallToolWithInfo
 	}
+	if d.poolListResources == nil {
+		d.poolListResources = def.poolListResources
+	}
+	if d.poolListPrompts == nil {
+		d.poolListPrompts = def.poolListPrompts
+	}
+	if d.poolGetPrompt == nil {
+		d.poolGetPrompt = def.poolGetPrompt
+	}
+	if d.poolServerState == nil {
+		d.poolServerState = def.poolServerState
+	}
+	if d.poolConnectionCount == nil {
+		d.poolConnectionCount = def.poolConnectionCount
+	}
+	if d.poolLastError == nil {
+		d.poolLastError = def.poolLastError
+	}
 	if d.cacheGet == nil {
 		d.cacheGet = def.cacheGet
 	}
 	if d.cacheGetMetadata == nil {
 		d.cacheGetMetadata = def.cacheGetMetadata
 	}
+	if d.cacheGetStale == nil {
+		d.cacheGetStale = def.cacheGetStale
+	}
 	if d.cachePut == nil {
 		d.cachePut = def.cachePut
 	}
+	if d.cacheClear == nil {
+		d.cacheClear = def.cacheClear
+	}
+	if d.cacheStats == nil {
+		d.cacheStats = def.cacheStats
+	}
 	if d.poolReset == nil {
 		d.poolReset = def.poolReset
 	}
@@ -153,16 +226,57 @@ func (d runtimeDeps) withDefaults() runtimeDeps {
 	if d.signalShutdownProcess == nil {
 		d.signalShutdownProcess = def.signalShutdownProcess
 	}
+	if d.recordToolUse == nil {
+		d.recordToolUse = def.recordToolUse
+	}
+	if d.toolUseCounts == nil {
+		d.toolUseCounts = def.toolUseCounts
+	}
+	if d.serverLastUse == nil {
+		d.serverLastUse = def.serverLastUse
+	}
+	if d.usageTrackedSince == nil {
+		d.usageTrackedSince = def.usageTrackedSince
+	}
 	return d
 }
 
+// RunOptions configures a daemon started with RunWithOptions.
+type RunOptions struct {
+	// Foreground runs under an external supervisor: the daemon refuses to
+	// start next to a live daemon and does not exit when all servers go idle.
+	Foreground bool
+	// LogFormat is LogFormatText (default) or LogFormatJSON.
+	LogFormat string
+	// LogLevel drops messages below this level.
+	LogLevel slog.Level
+}
+
 // Run starts the daemon process. Called when argv[1] == "__daemon".
 func Run() error {
+	return RunWithOptions(RunOptions{})
+}
+
+// RunWithOptions starts the daemon and blocks until SIGINT or SIGTERM.
+func RunWithOptions(opts RunOptions) error {
 	deps := runtimeDefaultDeps()
+	usage := openToolUsageCounter(paths.UsagePath(), time.Now)
+	deps.recordToolUse, deps.toolUseCounts = usage.record, usage.snapshot
+	deps.serverLastUse, deps.usageTrackedSince = usage.lastUse, usage.trackedSince
+	daemonLog = newDaemonLogger(os.Stderr, opts.LogFormat, opts.LogLevel)
 
-	if err := paths.EnsureDir(paths.RuntimeDir()); err != nil {
+	runtimeDir, fallback := paths.ResolveRuntimeDir()
+	if err := paths.EnsureDir(runtimeDir); err != nil {
 		return fmt.Errorf("creating runtime dir: %w", err)
 	}
+	if fallback {
+		daemonLog.Warn("preferred runtime dir is not writable; using " + runtimeDir)
+	} else {
+		daemonLog.Info("runtime dir " + runtimeDir)
+	}
+	if opts.Foreground && isListeningFn() {
+		return fmt.Errorf("a daemon is already listening on %s", paths.SocketPath())
+	}
 
 	cfg, _, err := loadValidatedConfigForCWDWithDeps("", deps, nil)
 	if err != nil {
@@ -176,29 +290,35 @@ func Run() error {
 
 	pool := mcppool.New(cfg)
 	defer pool.CloseAll()
+	pool.StartMaintenance()
 
 	ka := NewKeepalive(pool)
-	ka.SetOnAllIdle(deps.signalShutdownProcess)
+	if !opts.Foreground {
+		ka.SetOnAllIdle(deps.signalShutdownProcess)
+	}
 	ka.TouchDaemon()
 	defer ka.Stop()
 
 	handler := newRuntimeRequestHandlerWithDeps(cfg, pool, ka, deps)
 
+	// Register for signals before listening so a supervisor that stops the
+	// daemon as soon as the socket appears gets a clean shutdown.
+	sigCh := make(chan os.Signal, 1)
+	signalNotifyFn(sigCh, syscall.SIGINT, syscall.SIGTERM)
+	defer signalStopFn(sigCh)
+
 	srv := ipc.NewServer(paths.SocketPath(), nonce, handler.handle)
 	if err := srv.Start(); err != nil {
 		return err
 	}
 	defer srv.Stop()
 
-	fmt.Fprintf(os.Stderr, "mcpx daemon: listening on %s\n", paths.SocketPath())
+	daemonLog.Info("listening on " + paths.SocketPath())
 
 	// Wait for signal
-	sigCh := make(chan os.Signal, 1)
-	signalNotifyFn(sigCh, syscall.SIGINT, syscall.SIGTERM)
-	defer signalStopFn(sigCh)
 	<-sigCh
 
-	fmt.Fprintln(os.Stderr, "mcpx daemon: shutting down")
+	daemonLog.Info("shutting down")
 	return nil
 }
 
@@ -216,6 +336,8 @@ type runtimeRequestHandler struct {
 	deps                  runtimeDeps
 	ephemeralServers      map[string]config.ServerConfig
 	ephemeralServerOrder  []string
+	envServers            envServerOverlay
+	startedAt             time.Time
 }
 
 type runtimeConfigStamp struct {
@@ -249,6 +371,7 @@ func newRuntimeRequestHandlerWithDeps(cfg *config.Config, pool *mcppool.Pool, ka
 		deps:                  deps,
 		ephemeralServers:      ephemeralServers,
 		ephemeralServerOrder:  runtimeEphemeralServerOrder(ephemeralServers),
+		startedAt:             deps.now(),
 	}
 }
 
@@ -256,9 +379,16 @@ func (h *runtimeRequestHandler) handle(ctx context.Context, req *ipc.Request) *i
 	if req == nil {
 		return &ipc.Response{ExitCode: ipc.ExitUsageErr, Stderr: "nil request"}
 	}
-	if h.ka != nil && req.Type != "shutdown" {
+	// A status check should not keep an otherwise idle daemon alive.
+	if h.ka != nil && req.Type != "shutdown" && req.Type != "status" {
 		h.ka.TouchDaemon()
 	}
+	if req.Type == "reload" {
+		return h.reload(req.CWD)
+	}
+	if req.Type == "status" {
+		return h.status()
+	}
 
 	if !requestNeedsRuntimeConfig(req) {
 		h.mu.RLock()
@@ -279,6 +409,7 @@ func (h *runtimeRequestHandler) handle(ctx context.Context, req *ipc.Request) *i
 		}
 		if sameLiveCWD &&
 			req.Ephemeral == nil &&
+			h.envServers.matches(h.cfg, req.EnvServers) &&
 			currentStamp == h.lastPolledConfigStamp &&
 			!h.configPollDueLocked(h.deps.now()) {
 			// Safe to dispatch concurrently for same-CWD requests.
@@ -305,13 +436,17 @@ func (h *runtimeRequestHandler) handle(ctx context.Context, req *ipc.Request) *i
 			return &ipc.Response{ExitCode: ipc.ExitUsageErr, Stderr: err.Error()}
 		}
 		if restoredEphemeral {
-			nextHash, hashErr := configFingerprint(h.cfg)
+			nextHash, hashErr := configFingerprint(h.envServers.withoutOverlay(h.cfg))
 			if hashErr != nil {
 				h.mu.Unlock()
 				return &ipc.Response{ExitCode: ipc.ExitInternal, Stderr: fmt.Sprintf("fingerprinting runtime config: %v", hashErr)}
 			}
 			h.cfgHash = nextHash
 		}
+		if err := h.envServers.apply(h.cfg, req.EnvServers, func(name string) { h.deps.poolClose(h.pool, name) }); err != nil {
+			h.mu.Unlock()
+			return &ipc.Response{ExitCode: ipc.ExitUsageErr, Stderr: err.Error()}
+		}
 
 		h.stateVersion++
 		dispatchVersion := h.stateVersion
@@ -438,7 +573,7 @@ func (h *runtimeRequestHandler) finalizeRequestEphemeralInstall(_ uint64, name s
 	}
 
 	if changed {
-		nextHash, hashErr := configFingerprint(h.cfg)
+		nextHash, hashErr := configFingerprint(h.envServers.withoutOverlay(h.cfg))
 		if hashErr != nil {
 			return &ipc.Response{
 				ExitCode: ipc.ExitInternal,
@@ -671,7 +806,7 @@ func requestNeedsRuntimeConfig(req *ipc.Request) bool {
 		return false
 	}
 	switch req.Type {
-	case "list_servers", "list_tools", "tool_schema", "call_tool":
+	case "list_servers", "list_tools", "tool_schema", "call_tool", "resolve_call", "list_resources", "list_prompts", "get_prompt":
 		return true
 	default:
 		return false
@@ -839,7 +974,7 @@ func loadValidatedConfigForCWDWithDeps(cwd string, deps runtimeDeps, preserveFal
 		if preserveFallbackFrom != nil {
 			preserveFallbackBackedServers(cfg, preserveFallbackFrom, config.FailedFallbackSourcePaths(ferr))
 		}
-		fmt.Fprintf(os.Stderr, "mcpx daemon: warning: failed to load fallback MCP server config: %v\n", ferr)
+		daemonLog.Warn(fmt.Sprintf("failed to load fallback MCP server config: %v", ferr))
 	}
 	if verr := deps.validateConfig(cfg); verr != nil {
 		return nil, false, fmt.Errorf("invalid config: %w", verr)
@@ -911,7 +1046,18 @@ func cloneRuntimeServerConfig(server config.ServerConfig) config.ServerConfig {
 		DefaultCacheTTL: server.DefaultCacheTTL,
 		NoCacheTools:    append([]string(nil), server.NoCacheTools...),
 		Tools:           cloneRuntimeToolConfigMap(server.Tools),
+		AllowTools:      append([]string(nil), server.AllowTools...),
+		DenyTools:       append([]string(nil), server.DenyTools...),
+		HTTP:            cloneRuntimeHTTPConfig(server.HTTP),
+	}
+}
+
+func cloneRuntimeHTTPConfig(src *config.HTTPConfig) *config.HTTPConfig {
+	if src == nil {
+		return nil
 	}
+	dst := *src
+	return &dst
 }
 
 func cloneRuntimeStringMap(src map[string]string) map[string]string {
@@ -931,6 +1077,7 @@ func cloneRuntimeToolConfigMap(src map[string]config.ToolConfig) map[string]conf
 	}
 	dst := make(map[string]config.ToolConfig, len(src))
 	for key, value := range src {
+		value.Defaults = maps.Clone(value.Defaults)
 		dst[key] = value
 	}
 	return dst
@@ -942,17 +1089,65 @@ func dispatch(ctx context.Context, cfg *config.Config, pool *mcppool.Pool, ka *K
 
 func dispatchWithDeps(ctx context.Context, cfg *config.Config, pool *mcppool.Pool, ka *Keepalive, req *ipc.Request, deps runtimeDeps) *ipc.Response {
 	deps = deps.withDefaults()
+	untimed := deps
+	deps = withRequestTimeouts(cfg, deps)
 	switch req.Type {
 	case "ping":
 		return &ipc.Response{ExitCode: ipc.ExitOK}
 	case "list_servers":
+		if req.Unused != nil {
+			return listUnusedServersWithDeps(ctx, cfg, pool, ka, req.IncludeHidden, *req.Unused, deps)
+		}
 		return listServersWithDeps(ctx, cfg, pool, ka, req.IncludeHidden, deps)
 	case "list_tools":
 		return listToolsWithDeps(ctx, cfg, pool, ka, req.Server, req.Verbose, deps)
 	case "tool_schema":
 		return toolSchemaWithDeps(ctx, cfg, pool, ka, req.Server, req.Tool, deps)
 	case "call_tool":
-		return callToolWithDeps(ctx, cfg, pool, ka, req.Server, req.Tool, req.Args, req.Cache, req.Verbose, deps)
+		if req.Timeout != nil {
+			// --timeout takes precedence over the server's request_timeout.
+			deps = untimed
+		}
+		ctx, cancel, callDeps := withCallTimeouts(ctx, callTimeoutsFromRequest(req), deps)
+		defer cancel()
+		ctx = withRetryRequest(ctx, req.RetryAfterRetries, req.RetryBudget)
+		if len(req.Headers) > 0 {
+			ctx = mcppool.WithRequestHeaders(ctx, req.Headers)
+		}
+		ctx = withIdempotencyKey(ctx, req.IdempotencyKey)
+		ctx = withCacheScope(ctx, req.CacheScope, req.CWD)
+		ctx = withCacheErrors(ctx, req.CacheErrors)
+		ctx = withCacheRead(ctx, req.Fresh, req.StaleOK)
+		if req.ExplainCache {
+			ctx = withExplainCache(ctx)
+		}
+		if req.RawBytes {
+			ctx = withRawOutput(ctx)
+		}
+		if req.Raw {
+			ctx = withRawResult(ctx)
+		}
+		ctx = withCombineContent(ctx, req.CombineContent)
+		ctx = withContentOnly(ctx, req.ContentOnly)
+		if req.CaptureStderr {
+			capture := &stderrCapture{server: req.Server}
+			ctx = mcppool.WithStderrCapture(ctx, capture.add)
+			return capture.attach(callToolWithDeps(ctx, cfg, pool, ka, req.Server, req.Tool, req.Args, req.Cache, req.Verbose, callDeps))
+		}
+		return callToolWithDeps(ctx, cfg, pool, ka, req.Server, req.Tool, req.Args, req.Cache, req.Verbose, callDeps)
+	case "resolve_call":
+		ctx = withIdempotencyKey(ctx, req.IdempotencyKey)
+		return resolveCallWithDeps(ctx, cfg, pool, ka, req.Server, req.Tool, req.Args, req.Cache, deps)
+	case "list_resources":
+		return listResourcesWithDeps(ctx, cfg, pool, ka, req.Server, req.Verbose, deps)
+	case "list_prompts":
+		return listPromptsWithDeps(ctx, cfg, pool, ka, req.Server, req.Verbose, deps)
+	case "get_prompt":
+		return getPromptWithDeps(ctx, cfg, pool, ka, req.Server, req.Prompt, req.Args, deps)
+	case "cache_clear":
+		return cacheClearWithDeps(req.Server, req.Tool, deps)
+	case "cache_stats":
+		return cacheStatsWithDeps(deps)
 	case "shutdown":
 		go deps.signalShutdownProcess()
 		return &ipc.Response{Content: []byte("shutting down\n")}
@@ -966,6 +1161,19 @@ func listServers(ctx context.Context, cfg *config.Config, pool *mcppool.Pool, ka
 }
 
 func listServersWithDeps(ctx context.Context, cfg *config.Config, pool *mcppool.Pool, ka *Keepalive, includeHidden bool, deps runtimeDeps) *ipc.Response {
+	entries, warn := serverListEntriesWithDeps(ctx, cfg, pool, ka, includeHidden, deps)
+	raw, marshalErr := json.Marshal(entries)
+	if marshalErr != nil {
+		return &ipc.Response{
+			ExitCode: ipc.ExitInternal,
+			Stderr:   fmt.Sprintf("encoding server list: %v", marshalErr),
+		}
+	}
+	return &ipc.Response{Content: raw, Stderr: warn}
+}
+
+func serverListEntriesWithDeps(ctx context.Context, cfg *config.Config, pool *mcppool.Pool, ka *Keepalive, includeHidden bool, deps runtimeDeps) ([]serverListEntry, string) {
+	deps = deps.withDefaults()
 	catalog := newServerCatalogWithDeps(cfg, pool, ka, deps)
 	names, err := catalog.ServerNames(ctx)
 	var warn string
@@ -979,20 +1187,23 @@ func listServersWithDeps(ctx context.Context, cfg *config.Config, pool *mcppool.
 
 	entries := make([]serverListEntry, 0, len(names))
 	for _, name := range names {
-		entries = append(entries, serverListEntry{
-			Name:   name,
-			Origin: resolveServerOrigin(cfg, name),
-		})
-	}
-
-	raw, marshalErr := json.Marshal(entries)
-	if marshalErr != nil {
-		return &ipc.Response{
-			ExitCode: ipc.ExitInternal,
-			Stderr:   fmt.Sprintf("encoding server list: %v", marshalErr),
+		backend := serverStateBackend(cfg, name)
+		entry := serverListEntry{
+			Name:      name,
+			Origin:    resolveServerOrigin(cfg, name),
+			Transport: serverTransport(cfg, backend),
+			State:     string(deps.poolServerState(pool, backend)),
+		}
+		if last, ok := deps.poolLastError(pool, backend); ok {
+			entry.LastError = last.Message
+			entry.LastErrorAt = last.At.UTC().Format(time.RFC3339)
 		}
+		if at, ok := deps.serverLastUse(name); ok {
+			entry.LastCallAt = at.UTC().Format(time.RFC3339)
+		}
+		entries = append(entries, entry)
 	}
-	return &ipc.Response{Content: raw, Stderr: warn}
+	return entries, warn
 }
 
 func visibleServerNames(cfg *config.Config, names []string) []string {
@@ -1014,6 +1225,46 @@ func visibleServerNames(cfg *config.Config, names []string) []string {
 type serverListEntry struct {
 	Name   string              `json:"name"`
 	Origin config.ServerOrigin `json:"origin"`
+	// Transport is "stdio" or "http", from the backing server's config.
+	Transport string `json:"transport,omitempty"`
+	State     string `json:"state,omitempty"`
+	// LastError is the redacted message of the most recent connect or
+	// request failure; LastErrorAt is its RFC 3339 timestamp.
+	LastError   string `json:"last_error,omitempty"`
+	LastErrorAt string `json:"last_error_at,omitempty"`
+	// LastCallAt is the RFC 3339 time of the server's most recent call_tool
+	// since the daemon started.
+	LastCallAt string `json:"last_call_at,omitempty"`
+}
+
+// serverStateBackend maps a listed server name to the pooled connection that
+// backs it; codex apps virtual servers share the codex_apps connection.
+func serverStateBackend(cfg *config.Config, name string) string {
+	if cfg != nil {
+		if _, ok := cfg.Servers[name]; ok {
+			return name
+		}
+	}
+	return codexAppsServerName
+}
+
+// serverTransport names the transport of a configured server, or "" when
+// it is not in config.
+func serverTransport(cfg *config.Config, name string) string {
+	if cfg == nil {
+		return ""
+	}
+	scfg, ok := cfg.Servers[name]
+	switch {
+	case !ok:
+		return ""
+	case scfg.IsHTTP():
+		return "http"
+	case scfg.IsStdio():
+		return "stdio"
+	default:
+		return ""
+	}
 }
 
 func resolveServerOrigin(cfg *config.Config, name string) config.ServerOrigin {
@@ -1056,6 +1307,13 @@ func configuredServerNames(cfg *config.Config, includeHidden bool) []string {
 	return names
 }
 
+func toolNotAllowedResponse(server, tool string) *ipc.Response {
+	return &ipc.Response{
+		ExitCode: ipc.ExitUsageErr,
+		Stderr:   fmt.Sprintf("tool %s is not allowed on server %s (allow_tools/deny_tools)", tool, server),
+	}
+}
+
 func unknownServerResponse(server string) *ipc.Response {
 	return &ipc.Response{
 		ExitCode:  ipc.ExitUsageErr,
@@ -1090,6 +1348,8 @@ func listServerToolsWithDeps(ctx context.Context, pool *mcppool.Pool, ka *Keepal
 type toolListEntry struct {
 	Name        string `json:"name"`
 	Description string `json:"description,omitempty"`
+	// Uses is how many times this daemon has called the tool.
+	Uses int `json:"uses,omitempty"`
 }
 
 func listTools(ctx context.Context, cfg *config.Config, pool *mcppool.Pool, ka *Keepalive, server string, verbose bool) *ipc.Response {
@@ -1112,9 +1372,8 @@ func listToolsWithDeps(ctx context.Context, cfg *config.Config, pool *mcppool.Po
 		if err != nil {
 			return &ipc.Response{ExitCode: ipc.ExitInternal, Stderr: fmt.Sprintf("listing tools: %v", err)}
 		}
-	} else {
-		tools = catalog.FilterTools(route, routeTools)
 	}
+	tools = catalog.FilterTools(route, tools)
 
 	displayNames := make(map[string]string, len(tools))
 	for _, t := range tools {
@@ -1138,11 +1397,13 @@ func listToolsWithDeps(ctx context.Context, cfg *config.Config, pool *mcppool.Po
 	}
 	sort.Strings(names)
 
+	uses := deps.toolUseCounts(server)
 	entries := make([]toolListEntry, 0, len(names))
 	for _, name := range names {
 		entries = append(entries, toolListEntry{
 			Name:        name,
 			Description: strings.TrimSpace(displayNames[name]),
+			Uses:        uses[name],
 		})
 	}
 	data, err := json.Marshal(entries)
@@ -1155,7 +1416,31 @@ func listToolsWithDeps(ctx context.Context, cfg *config.Config, pool *mcppool.Po
 
 const shortToolDescriptionMaxLen = 120
 
+// descSummaryLenEnvVar overrides shortToolDescriptionMaxLen so wide
+// terminals can show more of each description in the default tool list.
+const descSummaryLenEnvVar = "MCPX_DESC_SUMMARY_LEN"
+
+// minToolDescriptionSummaryLen leaves room for at least one character
+// before the ellipsis.
+const minToolDescriptionSummaryLen = 4
+
+func toolDescriptionSummaryLen() int {
+	raw := strings.TrimSpace(os.Getenv(descSummaryLenEnvVar))
+	if raw == "" {
+		return shortToolDescriptionMaxLen
+	}
+	n, err := strconv.Atoi(raw)
+	if err != nil || n < minToolDescriptionSummaryLen {
+		return shortToolDescriptionMaxLen
+	}
+	return n
+}
+
 func summarizeToolDescription(desc string) string {
+	return summarizeToolDescriptionTo(desc, toolDescriptionSummaryLen())
+}
+
+func summarizeToolDescriptionTo(desc string, maxLen int) string {
 	if desc == "" {
 		return ""
 	}
@@ -1172,10 +1457,10 @@ func summarizeToolDescription(desc string) string {
 	if desc == "" {
 		return ""
 	}
-	if len(desc) <= shortToolDescriptionMaxLen {
+	if len(desc) <= maxLen {
 		return desc
 	}
-	return strings.TrimSpace(desc[:shortToolDescriptionMaxLen-3]) + "..."
+	return strings.TrimSpace(desc[:maxLen-3]) + "..."
 }
 
 func toolSchema(ctx context.Context, cfg *config.Config, pool *mcppool.Pool, ka *Keepalive, server, tool string) *ipc.Response {
@@ -1204,6 +1489,10 @@ func toolSchemaWithDeps(ctx context.Context, cfg *config.Config, pool *mcppool.P
 		}
 		info = toolInfo
 	} else {
+		if !catalog.ToolAllowed(route, tool) {
+			return toolNotAllowedResponse(server, tool)
+		}
+
 		ka.Begin(route.Backend)
 		defer ka.End(route.Backend)
 
@@ -1214,6 +1503,9 @@ func toolSchemaWithDeps(ctx context.Context, cfg *config.Config, pool *mcppool.P
 				Stderr:   fmt.Sprintf("getting schema: %v", err),
 			}
 		}
+		if info != nil && !catalog.ToolAllowed(route, info.Name) {
+			return toolNotAllowedResponse(server, info.Name)
+		}
 	}
 
 	payload := map[string]any{
@@ -1234,11 +1526,31 @@ func toolSchemaWithDeps(ctx context.Context, cfg *config.Config, pool *mcppool.P
 		}
 	}
 
+	if annotations := toolAnnotationsPayload(info.Annotations); len(annotations) > 0 {
+		payload["annotations"] = annotations
+	}
+
 	data, _ := json.MarshalIndent(payload, "", "  ")
 	data = append(data, '\n')
 	return &ipc.Response{Content: data}
 }
 
+// toolAnnotationsPayload renders declared tool hints with their MCP names.
+func toolAnnotationsPayload(a mcppool.ToolAnnotations) map[string]bool {
+	out := make(map[string]bool, 4)
+	for key, hint := range map[string]*bool{
+		"readOnlyHint":    a.ReadOnly,
+		"destructiveHint": a.Destructive,
+		"idempotentHint":  a.Idempotent,
+		"openWorldHint":   a.OpenWorld,
+	} {
+		if hint != nil {
+			out[key] = *hint
+		}
+	}
+	return out
+}
+
 func callTool(ctx context.Context, cfg *config.Config, pool *mcppool.Pool, ka *Keepalive, server, tool string, args json.RawMessage, reqCache *time.Duration, verbose bool) *ipc.Response {
 	return callToolWithDeps(ctx, cfg, pool, ka, server, tool, args, reqCache, verbose, runtimeDefaultDeps())
 }
@@ -1257,30 +1569,87 @@ func callToolWithDeps(ctx context.Context, cfg *config.Config, pool *mcppool.Poo
 	if !ok {
 		return unknownServerResponse(server)
 	}
+	if !scfg.ToolAllowed(tool) {
+		return toolNotAllowedResponse(server, tool)
+	}
+	args, err = applyToolDefaults(args, scfg.Tools[tool].Defaults)
+	if err != nil {
+		return &ipc.Response{ExitCode: ipc.ExitUsageErr, Stderr: fmt.Sprintf("applying saved defaults: %v", err)}
+	}
+	ctx, args, err = applyIdempotencyKey(ctx, args, scfg, tool)
+	if err != nil {
+		return &ipc.Response{ExitCode: ipc.ExitUsageErr, Stderr: fmt.Sprintf("applying idempotency key: %v", err)}
+	}
 
 	ka.Begin(route.Backend)
 	defer ka.End(route.Backend)
 
-	cacheTTL, shouldCache, err := effectiveCacheTTL(scfg, tool, reqCache)
+	cacheTTL, shouldCache, cacheReason, err := cacheDecision(scfg, tool, reqCache)
 	if err != nil {
 		return &ipc.Response{
 			ExitCode: ipc.ExitInternal,
 			Stderr:   fmt.Sprintf("cache configuration error: %v", err),
 		}
 	}
+	// Cached entries hold the default rendering, so any other output mode
+	// neither reads nor writes them, --stale-ok included.
+	if mode := cacheBypassingOutputMode(ctx); mode != "" {
+		shouldCache = false
+		cacheReason = "disabled (" + mode + ")"
+	}
+	cacheServer, err := cacheKeyServer(ctx, cfg, server, scfg)
+	if err != nil {
+		return &ipc.Response{ExitCode: ipc.ExitUsageErr, Stderr: fmt.Sprintf("cache configuration error: %v", err)}
+	}
+	explain := explainCacheRequested(ctx)
 	var logs []string
-	if shouldCache {
-		if out, exitCode, ok := deps.cacheGet(server, tool, args); ok {
-			if verbose {
-				if age, ttl, ok := deps.cacheGetMetadata(server, tool, args); ok {
+	if explain {
+		logs = append(logs, "mcpx: cache "+cacheReason)
+	}
+	read := cacheReadFor(ctx)
+	switch {
+	case read.fresh:
+		if shouldCache && (verbose || explain) {
+			logs = append(logs, "mcpx: cache read skipped (--fresh)")
+		}
+	case read.maxAge > 0 && cacheBypassingOutputMode(ctx) == "":
+		if hit, ok := deps.cacheGetStale(cacheServer, tool, args, read.maxAge); ok && hit.Age <= read.maxAge {
+			if verbose || explain {
+				logs = append(logs, fmt.Sprintf("mcpx: cache hit (age=%s ttl=%s, --stale-ok %s)", hit.Age, hit.TTL, read.maxAge))
+			}
+			deps.recordToolUse(server, tool)
+			return cachedCallResponse(hit.Response, logs)
+		}
+		if verbose || explain {
+			logs = append(logs, "mcpx: cache miss")
+		}
+	case shouldCache && scfg.CacheMode == config.CacheModeStaleWhileRevalidate:
+		if hit, ok := deps.cacheGetStale(cacheServer, tool, args, staleWindow(scfg, cacheTTL)); ok {
+			if verbose || explain {
+				logs = append(logs, staleCacheHitLog(hit))
+			}
+			if hit.Stale > 0 {
+				refreshStaleEntry(ctx, pool, ka, route.Backend, cacheServer, tool, args, cacheTTL, deps)
+			}
+			deps.recordToolUse(server, tool)
+			return cachedCallResponse(hit.Response, logs)
+		}
+		if verbose || explain {
+			logs = append(logs, "mcpx: cache miss")
+		}
+	case shouldCache:
+		if hit, ok := deps.cacheGet(cacheServer, tool, args); ok {
+			if verbose || explain {
+				if age, ttl, ok := deps.cacheGetMetadata(cacheServer, tool, args); ok {
 					logs = append(logs, fmt.Sprintf("mcpx: cache hit (age=%s ttl=%s)", age, ttl))
 				} else {
 					logs = append(logs, "mcpx: cache hit")
 				}
 			}
-			return &ipc.Response{Content: out, ExitCode: exitCode, Stderr: joinLogs(logs)}
+			deps.recordToolUse(server, tool)
+			return cachedCallResponse(hit, logs)
 		}
-		if verbose {
+		if verbose || explain {
 			logs = append(logs, "mcpx: cache miss")
 		}
 	}
@@ -1300,6 +1669,9 @@ func callToolWithDeps(ctx context.Context, cfg *config.Config, pool *mcppool.Poo
 				Stderr:   fmt.Sprintf("resolving tool: %v", err),
 			}
 		}
+		if resolvedInfo != nil && !scfg.ToolAllowed(resolvedInfo.Name) {
+			return toolNotAllowedResponse(server, resolvedInfo.Name)
+		}
 		if resolvedInfo != nil && !catalog.ToolBelongsToRoute(route, resolvedInfo.Name) {
 			return &ipc.Response{
 				ExitCode: ipc.ExitUsageErr,
@@ -1315,54 +1687,128 @@ func callToolWithDeps(ctx context.Context, cfg *config.Config, pool *mcppool.Poo
 		cacheTool = info.Name
 	}
 
-	result, err := deps.poolCallToolWithInfo(ctx, pool, route.Backend, info, args)
+	var retryLogs []string
+	retryDeps := withRetries(retryPolicyFor(ctx, scfg, func(line string) {
+		if verbose {
+			retryLogs = append(retryLogs, line)
+		}
+	}), deps)
+	result, err := retryDeps.poolCallToolWithInfo(ctx, pool, route.Backend, info, args)
+	logs = append(logs, retryLogs...)
 	if err != nil {
 		return &ipc.Response{
 			ExitCode: classifyCallToolError(err),
-			Stderr:   fmt.Sprintf("calling tool: %v", err),
+			Stderr:   joinLogs(append(retryLogs, fmt.Sprintf("calling tool: %v", err))),
 		}
 	}
+	deps.recordToolUse(server, cacheTool)
 
-	out, exitCode := response.Unwrap(result)
+	if rawOutputRequested(ctx) {
+		return unwrapRawResult(result)
+	}
+	if rawResultRequested(ctx) {
+		return marshalRawResult(result, logs)
+	}
+	if kind := contentOnlyKind(ctx); kind != "" {
+		return unwrapContentOnly(result, kind, combineContentMode(ctx), logs)
+	}
+	out, exitCode, hint := response.UnwrapHinted(result, combineContentMode(ctx))
+	stored := cache.Response{Content: out, ExitCode: exitCode, ContentType: hint.ContentType, Encoding: hint.Encoding}
+	errorTTL, cacheErrors := errorCacheTTL(ctx, scfg, cacheTTL)
 	if shouldCache && exitCode == ipc.ExitOK {
-		_ = deps.cachePut(server, cacheTool, args, out, exitCode, cacheTTL)
-		if verbose {
+		_ = deps.cachePut(cacheServer, cacheTool, args, stored, cacheTTL)
+		if verbose || explain {
 			logs = append(logs, fmt.Sprintf("mcpx: cache store (ttl=%s)", cacheTTL))
 		}
+	} else if shouldCache && cacheErrors {
+		_ = deps.cachePut(cacheServer, cacheTool, args, stored, errorTTL)
+		if verbose || explain {
+			logs = append(logs, fmt.Sprintf("mcpx: cache store error result (exit code %d, ttl=%s)", exitCode, errorTTL))
+		}
+	} else if shouldCache && explain {
+		logs = append(logs, fmt.Sprintf("mcpx: cache not stored (exit code %d)", exitCode))
 	}
-	return &ipc.Response{Content: out, ExitCode: exitCode, Stderr: joinLogs(logs)}
+	return cachedCallResponse(stored, logs)
+}
+
+// cachedCallResponse shapes an unwrapped call result, fresh or from the
+// cache, as a call_tool response.
+func cachedCallResponse(resp cache.Response, logs []string) *ipc.Response {
+	return &ipc.Response{
+		Content:     resp.Content,
+		ExitCode:    resp.ExitCode,
+		Stderr:      joinLogs(logs),
+		ContentType: resp.ContentType,
+		Encoding:    resp.Encoding,
+	}
+}
+
+// cacheBypassingOutputMode names the flag selecting an output other than the
+// default unwrapped rendering that cache entries hold, or "" when none is.
+func cacheBypassingOutputMode(ctx context.Context) string {
+	switch {
+	case rawOutputRequested(ctx):
+		return "--output-raw-bytes"
+	case rawResultRequested(ctx):
+		return "--raw"
+	case contentOnlyKind(ctx) != "":
+		return "--" + contentOnlyKind(ctx) + "-only"
+	case combineContentMode(ctx) != "":
+		return "--combine-content"
+	}
+	return ""
 }
 
 func effectiveCacheTTL(scfg config.ServerConfig, tool string, reqCache *time.Duration) (time.Duration, bool, error) {
+	ttl, enabled, _, err := cacheDecision(scfg, tool, reqCache)
+	return ttl, enabled, err
+}
+
+// cacheDecision is effectiveCacheTTL plus a short reason naming the setting
+// that decided it, for --explain-cache.
+func cacheDecision(scfg config.ServerConfig, tool string, reqCache *time.Duration) (time.Duration, bool, string, error) {
 	if reqCache != nil {
 		if *reqCache <= 0 {
-			return 0, false, nil
+			return 0, false, "disabled (--no-cache)", nil
 		}
-		return *reqCache, true, nil
+		return *reqCache, true, fmt.Sprintf("enabled (ttl=%s from --cache)", *reqCache), nil
 	}
 
 	ttl, hasDefault, err := parseDefaultCacheTTL(scfg)
 	if err != nil {
-		return 0, false, err
+		return 0, false, "", err
 	}
 	enabled := hasDefault
+	reason := "disabled (server has no default_cache_ttl)"
+	if hasDefault {
+		reason = fmt.Sprintf("enabled (ttl=%s from default_cache_ttl)", ttl)
+	}
 
-	if hasDefault && matchesNoCachePattern(scfg, tool) {
-		enabled = false
+	if hasDefault {
+		if pattern, ok := matchingNoCachePattern(scfg, tool); ok {
+			enabled = false
+			reason = fmt.Sprintf("disabled (tool matches no_cache_tools pattern %q)", pattern)
+		}
 	}
 
 	if override, ok := lookupToolCacheOverride(scfg, tool); ok {
-		if override {
-			enabled = hasDefault
-		} else {
+		switch {
+		case !override:
+			enabled = false
+			reason = fmt.Sprintf("disabled (tools.%s.cache = false)", tool)
+		case hasDefault:
+			enabled = true
+			reason = fmt.Sprintf("enabled (ttl=%s from default_cache_ttl; tools.%s.cache = true)", ttl, tool)
+		default:
 			enabled = false
+			reason = fmt.Sprintf("disabled (tools.%s.cache = true but server has no default_cache_ttl)", tool)
 		}
 	}
 
 	if !enabled {
-		return 0, false, nil
+		return 0, false, reason, nil
 	}
-	return ttl, true, nil
+	return ttl, true, reason, nil
 }
 
 func parseDefaultCacheTTL(scfg config.ServerConfig) (time.Duration, bool, error) {
@@ -1387,13 +1833,18 @@ func lookupToolCacheOverride(scfg config.ServerConfig, tool string) (bool, bool)
 }
 
 func matchesNoCachePattern(scfg config.ServerConfig, tool string) bool {
+	_, ok := matchingNoCachePattern(scfg, tool)
+	return ok
+}
+
+func matchingNoCachePattern(scfg config.ServerConfig, tool string) (string, bool) {
 	for _, pattern := range scfg.NoCacheTools {
 		matched, err := path.Match(pattern, tool)
 		if err == nil && matched {
-			return true
+			return pattern, true
 		}
 	}
-	return false
+	return "", false
 }
 
 func joinLogs(lines []string) string {
@@ -2,12 +2,14 @@ package daemon
 
 import (
 	"context"
+	"encoding/base64"
 	"encoding/json"
 	"errors"
 	"strings"
 	"testing"
 	"time"
 
+	"github.com/lydakis/mcpx/internal/cache"
 	"github.com/lydakis/mcpx/internal/config"
 	"github.com/lydakis/mcpx/internal/ipc"
 	"github.com/lydakis/mcpx/internal/mcppool"
@@ -32,10 +34,10 @@ func TestCallToolUsesCachedResponseWhenPresent(t *testing.T) {
 		poolCalls++
 		return nil, errors.New("pool should not be called on cache hit")
 	}
-	deps.cacheGet = func(_, _ string, _ json.RawMessage) ([]byte, int, bool) {
-		return []byte("cached\n"), ipc.ExitOK, true
+	deps.cacheGet = func(_, _ string, _ json.RawMessage) (cache.Response, bool) {
+		return cache.Response{Content: []byte("cached\n"), ExitCode: ipc.ExitOK}, true
 	}
-	deps.cachePut = func(_ string, _ string, _ json.RawMessage, _ []byte, _ int, _ time.Duration) error {
+	deps.cachePut = func(_ string, _ string, _ json.RawMessage, _ cache.Response, _ time.Duration) error {
 		cacheWrites++
 		return nil
 	}
@@ -55,6 +57,38 @@ func TestCallToolUsesCachedResponseWhenPresent(t *testing.T) {
 	}
 }
 
+func TestCallToolCacheHitKeepsStoredContentType(t *testing.T) {
+	cfg := &config.Config{
+		Servers: map[string]config.ServerConfig{
+			"github": {},
+		},
+	}
+	ka := NewKeepalive(nil)
+	defer ka.Stop()
+
+	reqCache := 30 * time.Second
+	var stored cache.Response
+	deps := runtimeDefaultDeps()
+	deps.poolCallToolWithInfo = func(context.Context, *mcppool.Pool, string, *mcppool.ToolInfo, json.RawMessage) (*mcp.CallToolResult, error) {
+		return mcp.NewToolResultText("not json"), nil
+	}
+	deps.cacheGet = func(string, string, json.RawMessage) (cache.Response, bool) {
+		return stored, stored.Content != nil
+	}
+	deps.cachePut = func(_ string, _ string, _ json.RawMessage, resp cache.Response, _ time.Duration) error {
+		stored = resp
+		return nil
+	}
+
+	first := callToolWithDeps(context.Background(), cfg, nil, ka, "github", "search", json.RawMessage(`{}`), &reqCache, false, deps)
+	second := callToolWithDeps(context.Background(), cfg, nil, ka, "github", "search", json.RawMessage(`{}`), &reqCache, false, deps)
+	for name, resp := range map[string]*ipc.Response{"miss": first, "hit": second} {
+		if resp.ContentType != "text/plain" || resp.Encoding != ipc.EncodingText {
+			t.Fatalf("%s content type = %q, %q; want text/plain, text", name, resp.ContentType, resp.Encoding)
+		}
+	}
+}
+
 func TestCallToolCachesSuccessfulResponseWithDefaultTTL(t *testing.T) {
 	cfg := &config.Config{
 		Servers: map[string]config.ServerConfig{
@@ -75,14 +109,14 @@ func TestCallToolCachesSuccessfulResponseWithDefaultTTL(t *testing.T) {
 		poolCalls++
 		return &mcp.CallToolResult{StructuredContent: map[string]any{"ok": true}}, nil
 	}
-	deps.cacheGet = func(_ string, _ string, _ json.RawMessage) ([]byte, int, bool) {
-		return nil, 0, false
+	deps.cacheGet = func(_ string, _ string, _ json.RawMessage) (cache.Response, bool) {
+		return cache.Response{}, false
 	}
-	deps.cachePut = func(_ string, _ string, _ json.RawMessage, content []byte, exitCode int, ttl time.Duration) error {
+	deps.cachePut = func(_ string, _ string, _ json.RawMessage, resp cache.Response, ttl time.Duration) error {
 		cacheWrites++
 		wroteTTL = ttl
-		wroteExit = exitCode
-		wroteContent = string(content)
+		wroteExit = resp.ExitCode
+		wroteContent = string(resp.Content)
 		return nil
 	}
 
@@ -190,10 +224,10 @@ func TestCallToolVerboseIncludesCacheHitLog(t *testing.T) {
 	deps.poolCallToolWithInfo = func(_ context.Context, _ *mcppool.Pool, _ string, _ *mcppool.ToolInfo, _ json.RawMessage) (*mcp.CallToolResult, error) {
 		return nil, errors.New("pool should not be called on cache hit")
 	}
-	deps.cacheGet = func(_ string, _ string, _ json.RawMessage) ([]byte, int, bool) {
-		return []byte("cached\n"), ipc.ExitOK, true
+	deps.cacheGet = func(_ string, _ string, _ json.RawMessage) (cache.Response, bool) {
+		return cache.Response{Content: []byte("cached\n"), ExitCode: ipc.ExitOK}, true
 	}
-	deps.cachePut = func(_ string, _ string, _ json.RawMessage, _ []byte, _ int, _ time.Duration) error {
+	deps.cachePut = func(_ string, _ string, _ json.RawMessage, _ cache.Response, _ time.Duration) error {
 		return nil
 	}
 
@@ -216,13 +250,13 @@ func TestCallToolVerboseIncludesCacheAgeAndTTLWhenAvailable(t *testing.T) {
 	deps.poolCallToolWithInfo = func(_ context.Context, _ *mcppool.Pool, _ string, _ *mcppool.ToolInfo, _ json.RawMessage) (*mcp.CallToolResult, error) {
 		return nil, errors.New("pool should not be called on cache hit")
 	}
-	deps.cacheGet = func(_ string, _ string, _ json.RawMessage) ([]byte, int, bool) {
-		return []byte("cached\n"), ipc.ExitOK, true
+	deps.cacheGet = func(_ string, _ string, _ json.RawMessage) (cache.Response, bool) {
+		return cache.Response{Content: []byte("cached\n"), ExitCode: ipc.ExitOK}, true
 	}
 	deps.cacheGetMetadata = func(_ string, _ string, _ json.RawMessage) (time.Duration, time.Duration, bool) {
 		return 23 * time.Second, 60 * time.Second, true
 	}
-	deps.cachePut = func(_ string, _ string, _ json.RawMessage, _ []byte, _ int, _ time.Duration) error {
+	deps.cachePut = func(_ string, _ string, _ json.RawMessage, _ cache.Response, _ time.Duration) error {
 		return nil
 	}
 
@@ -241,8 +275,8 @@ func TestCallToolUsageErrorIncludesStderrByDefault(t *testing.T) {
 	deps.poolCallToolWithInfo = func(_ context.Context, _ *mcppool.Pool, _ string, _ *mcppool.ToolInfo, _ json.RawMessage) (*mcp.CallToolResult, error) {
 		return nil, mcp.ErrInvalidParams
 	}
-	deps.cacheGet = func(_ string, _ string, _ json.RawMessage) ([]byte, int, bool) {
-		return nil, 0, false
+	deps.cacheGet = func(_ string, _ string, _ json.RawMessage) (cache.Response, bool) {
+		return cache.Response{}, false
 	}
 
 	resp := callToolWithDeps(context.Background(), cfg, nil, ka, "github", "search", json.RawMessage(`{}`), nil, false, deps)
@@ -278,15 +312,15 @@ func TestCallToolCacheKeyUsesRequestedToolName(t *testing.T) {
 		poolCalls++
 		return &mcp.CallToolResult{StructuredContent: map[string]any{"count": poolCalls}}, nil
 	}
-	deps.cacheGet = func(_ string, tool string, _ json.RawMessage) ([]byte, int, bool) {
+	deps.cacheGet = func(_ string, tool string, _ json.RawMessage) (cache.Response, bool) {
 		content, ok := cacheStore[tool]
 		if !ok {
-			return nil, 0, false
+			return cache.Response{}, false
 		}
-		return content, ipc.ExitOK, true
+		return cache.Response{Content: content, ExitCode: ipc.ExitOK}, true
 	}
-	deps.cachePut = func(_ string, tool string, _ json.RawMessage, content []byte, _ int, _ time.Duration) error {
-		cacheStore[tool] = content
+	deps.cachePut = func(_ string, tool string, _ json.RawMessage, resp cache.Response, _ time.Duration) error {
+		cacheStore[tool] = resp.Content
 		return nil
 	}
 
@@ -315,10 +349,10 @@ func TestCallToolVirtualServerRoutesThroughCodexApps(t *testing.T) {
 	defer ka.Stop()
 
 	deps := runtimeDefaultDeps()
-	deps.cacheGet = func(_ string, _ string, _ json.RawMessage) ([]byte, int, bool) {
-		return nil, 0, false
+	deps.cacheGet = func(_ string, _ string, _ json.RawMessage) (cache.Response, bool) {
+		return cache.Response{}, false
 	}
-	deps.cachePut = func(_ string, _ string, _ json.RawMessage, _ []byte, _ int, _ time.Duration) error {
+	deps.cachePut = func(_ string, _ string, _ json.RawMessage, _ cache.Response, _ time.Duration) error {
 		return nil
 	}
 	deps.poolListTools = func(_ context.Context, _ *mcppool.Pool, _ string) ([]mcppool.ToolInfo, error) {
@@ -347,3 +381,198 @@ func TestCallToolVirtualServerRoutesThroughCodexApps(t *testing.T) {
 		t.Fatalf("callTool() content = %q, want %q", string(resp.Content), "{\"ok\":true}\n")
 	}
 }
+
+func TestCallToolRawOutputBypassesCacheAndReturnsBytes(t *testing.T) {
+	cfg := &config.Config{
+		Servers: map[string]config.ServerConfig{
+			"shots": {DefaultCacheTTL: "45s"},
+		},
+	}
+	ka := NewKeepalive(nil)
+	defer ka.Stop()
+
+	png := []byte{0x89, 'P', 'N', 'G', 0x00}
+	deps := runtimeDefaultDeps()
+	deps.poolCallToolWithInfo = func(_ context.Context, _ *mcppool.Pool, _ string, _ *mcppool.ToolInfo, _ json.RawMessage) (*mcp.CallToolResult, error) {
+		return &mcp.CallToolResult{Content: []mcp.Content{
+			mcp.ImageContent{Type: "image", Data: base64.StdEncoding.EncodeToString(png), MIMEType: "image/png"},
+		}}, nil
+	}
+	deps.cacheGet = func(_ string, _ string, _ json.RawMessage) (cache.Response, bool) {
+		t.Fatal("cacheGet called for raw output")
+		return cache.Response{}, false
+	}
+	deps.cachePut = func(_ string, _ string, _ json.RawMessage, _ cache.Response, _ time.Duration) error {
+		t.Fatal("cachePut called for raw output")
+		return nil
+	}
+
+	resp := callToolWithDeps(withRawOutput(context.Background()), cfg, nil, ka, "shots", "capture", json.RawMessage(`{}`), nil, false, deps)
+	if resp.ExitCode != ipc.ExitOK {
+		t.Fatalf("callTool() exit = %d, want %d (stderr=%q)", resp.ExitCode, ipc.ExitOK, resp.Stderr)
+	}
+	if string(resp.Content) != string(png) {
+		t.Fatalf("callTool() content = %v, want raw png bytes %v", resp.Content, png)
+	}
+}
+
+func TestCallToolRawResultBypassesCacheAndReturnsFullResult(t *testing.T) {
+	cfg := &config.Config{
+		Servers: map[string]config.ServerConfig{
+			"github": {DefaultCacheTTL: "45s"},
+		},
+	}
+	ka := NewKeepalive(nil)
+	defer ka.Stop()
+
+	deps := runtimeDefaultDeps()
+	deps.poolCallToolWithInfo = func(_ context.Context, _ *mcppool.Pool, _ string, _ *mcppool.ToolInfo, _ json.RawMessage) (*mcp.CallToolResult, error) {
+		return &mcp.CallToolResult{
+			Result:  mcp.Result{Meta: mcp.NewMetaFromMap(map[string]any{"trace": "abc"})},
+			Content: []mcp.Content{mcp.NewTextContent("hello")},
+		}, nil
+	}
+	deps.cacheGet = func(_ string, _ string, _ json.RawMessage) (cache.Response, bool) {
+		t.Fatal("cacheGet called for raw result")
+		return cache.Response{}, false
+	}
+	deps.cachePut = func(_ string, _ string, _ json.RawMessage, _ cache.Response, _ time.Duration) error {
+		t.Fatal("cachePut called for raw result")
+		return nil
+	}
+
+	resp := callToolWithDeps(withRawResult(context.Background()), cfg, nil, ka, "github", "search", json.RawMessage(`{}`), nil, false, deps)
+	if resp.ExitCode != ipc.ExitOK {
+		t.Fatalf("callTool() exit = %d, want %d (stderr=%q)", resp.ExitCode, ipc.ExitOK, resp.Stderr)
+	}
+	var got struct {
+		Meta    map[string]any   `json:"_meta"`
+		Content []map[string]any `json:"content"`
+	}
+	if err := json.Unmarshal(resp.Content, &got); err != nil {
+		t.Fatalf("unmarshal raw result: %v; content=%q", err, resp.Content)
+	}
+	if got.Meta["trace"] != "abc" || len(got.Content) != 1 || got.Content[0]["type"] != "text" || got.Content[0]["text"] != "hello" {
+		t.Fatalf("raw result = %s, want full CallToolResult with _meta and typed content", resp.Content)
+	}
+	if resp.ContentType != "application/json" {
+		t.Fatalf("content type = %q, want application/json", resp.ContentType)
+	}
+}
+
+func TestCallToolContentOnlyKeepsOneKindAndBypassesCache(t *testing.T) {
+	cfg := &config.Config{
+		Servers: map[string]config.ServerConfig{
+			"github": {DefaultCacheTTL: "45s"},
+		},
+	}
+	ka := NewKeepalive(nil)
+	defer ka.Stop()
+
+	result := &mcp.CallToolResult{
+		Content:           []mcp.Content{mcp.NewTextContent("Found 2 repositories")},
+		StructuredContent: map[string]any{"total": 2},
+	}
+	deps := runtimeDefaultDeps()
+	deps.poolCallToolWithInfo = func(_ context.Context, _ *mcppool.Pool, _ string, _ *mcppool.ToolInfo, _ json.RawMessage) (*mcp.CallToolResult, error) {
+		return result, nil
+	}
+	deps.cacheGet = func(_ string, _ string, _ json.RawMessage) (cache.Response, bool) {
+		t.Fatal("cacheGet called for --structured-only/--text-only")
+		return cache.Response{}, false
+	}
+	deps.cachePut = func(_ string, _ string, _ json.RawMessage, _ cache.Response, _ time.Duration) error {
+		t.Fatal("cachePut called for --structured-only/--text-only")
+		return nil
+	}
+
+	tests := []struct {
+		kind        string
+		want        string
+		contentType string
+	}{
+		{kind: "structured", want: "{\"total\":2}\n", contentType: "application/json"},
+		{kind: "text", want: "Found 2 repositories\n", contentType: "text/plain"},
+	}
+	for _, tt := range tests {
+		ctx := withContentOnly(context.Background(), tt.kind)
+		resp := callToolWithDeps(ctx, cfg, nil, ka, "github", "search", json.RawMessage(`{}`), nil, false, deps)
+		if resp.ExitCode != ipc.ExitOK || string(resp.Content) != tt.want || resp.ContentType != tt.contentType {
+			t.Fatalf("%s-only = (%d, %q, %q), want (0, %q, %q)", tt.kind, resp.ExitCode, resp.Content, resp.ContentType, tt.want, tt.contentType)
+		}
+	}
+
+	result.Content = nil
+	resp := callToolWithDeps(withContentOnly(context.Background(), "text"), cfg, nil, ka, "github", "search", json.RawMessage(`{}`), nil, false, deps)
+	if resp.ExitCode != ipc.ExitToolErr || resp.Stderr != "result has no text content" {
+		t.Fatalf("text-only without text = (%d, %q), want exit 1 with missing text error", resp.ExitCode, resp.Stderr)
+	}
+}
+
+func TestCallToolExplainCacheReportsDecisionAndLookup(t *testing.T) {
+	cacheOff := false
+	cfg := &config.Config{
+		Servers: map[string]config.ServerConfig{
+			"github": {
+				DefaultCacheTTL: "45s",
+				NoCacheTools:    []string{"create_*"},
+				Tools:           map[string]config.ToolConfig{"delete_repo": {Cache: &cacheOff}},
+			},
+		},
+	}
+	ka := NewKeepalive(nil)
+	defer ka.Stop()
+
+	cached := map[string]bool{}
+	deps := runtimeDefaultDeps()
+	deps.poolCallToolWithInfo = func(context.Context, *mcppool.Pool, string, *mcppool.ToolInfo, json.RawMessage) (*mcp.CallToolResult, error) {
+		return mcp.NewToolResultText("ok"), nil
+	}
+	deps.cacheGet = func(_, tool string, _ json.RawMessage) (cache.Response, bool) {
+		if cached[tool] {
+			return cache.Response{Content: []byte("cached\n"), ExitCode: ipc.ExitOK}, true
+		}
+		return cache.Response{}, false
+	}
+	deps.cacheGetMetadata = func(string, string, json.RawMessage) (time.Duration, time.Duration, bool) {
+		return 10 * time.Second, 45 * time.Second, true
+	}
+	deps.cachePut = func(_, tool string, _ json.RawMessage, _ cache.Response, _ time.Duration) error {
+		cached[tool] = true
+		return nil
+	}
+
+	explain := func(tool string, reqCache *time.Duration) string {
+		t.Helper()
+		ctx := withExplainCache(context.Background())
+		resp := callToolWithDeps(ctx, cfg, nil, ka, "github", tool, json.RawMessage(`{}`), reqCache, false, deps)
+		if resp.ExitCode != ipc.ExitOK {
+			t.Fatalf("callTool(%s) exit = %d, want %d (stderr=%q)", tool, resp.ExitCode, ipc.ExitOK, resp.Stderr)
+		}
+		return resp.Stderr
+	}
+
+	cases := []struct {
+		tool     string
+		reqCache *time.Duration
+		want     []string
+	}{
+		{tool: "search", want: []string{"mcpx: cache enabled (ttl=45s from default_cache_ttl)", "mcpx: cache miss", "mcpx: cache store (ttl=45s)"}},
+		{tool: "search", want: []string{"mcpx: cache enabled (ttl=45s from default_cache_ttl)", "mcpx: cache hit (age=10s ttl=45s)"}},
+		{tool: "create_issue", want: []string{`mcpx: cache disabled (tool matches no_cache_tools pattern "create_*")`}},
+		{tool: "delete_repo", want: []string{"mcpx: cache disabled (tools.delete_repo.cache = false)"}},
+		{tool: "list", reqCache: new(time.Duration), want: []string{"mcpx: cache disabled (--no-cache)"}},
+	}
+	for _, tc := range cases {
+		got := explain(tc.tool, tc.reqCache)
+		if want := strings.Join(tc.want, "\n"); got != want {
+			t.Errorf("explain %s stderr =\n%s\nwant\n%s", tc.tool, got, want)
+		}
+	}
+
+	// Without --explain-cache the decision is not reported.
+	resp := callToolWithDeps(context.Background(), cfg, nil, ka, "github", "create_issue", json.RawMessage(`{}`), nil, false, deps)
+	if resp.Stderr != "" {
+		t.Fatalf("stderr without --explain-cache = %q, want empty", resp.Stderr)
+	}
+}
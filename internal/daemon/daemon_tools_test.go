@@ -7,10 +7,13 @@ import (
 	"reflect"
 	"strings"
 	"testing"
+	"time"
 
+	"github.com/lydakis/mcpx/internal/cache"
 	"github.com/lydakis/mcpx/internal/config"
 	"github.com/lydakis/mcpx/internal/ipc"
 	"github.com/lydakis/mcpx/internal/mcppool"
+	"github.com/mark3labs/mcp-go/mcp"
 )
 
 func TestListToolsOutputsNativeNamesAndShortDescriptionsByDefault(t *testing.T) {
@@ -138,6 +141,29 @@ func TestSummarizeToolDescriptionTruncatesLongFirstLine(t *testing.T) {
 	}
 }
 
+func TestSummarizeToolDescriptionHonorsConfiguredLength(t *testing.T) {
+	input := strings.Repeat("word ", 60)
+
+	t.Setenv(descSummaryLenEnvVar, "200")
+	long := summarizeToolDescription(input)
+	if len(long) <= shortToolDescriptionMaxLen || len(long) > 200 {
+		t.Fatalf("summary length = %d, want in (%d, 200] (%q)", len(long), shortToolDescriptionMaxLen, long)
+	}
+
+	t.Setenv(descSummaryLenEnvVar, "40")
+	short := summarizeToolDescription(input)
+	if len(short) > 40 || !strings.HasSuffix(short, "...") {
+		t.Fatalf("summary = %q (len %d), want <= 40 with ellipsis", short, len(short))
+	}
+
+	for _, invalid := range []string{"abc", "2", "-5"} {
+		t.Setenv(descSummaryLenEnvVar, invalid)
+		if got := toolDescriptionSummaryLen(); got != shortToolDescriptionMaxLen {
+			t.Fatalf("toolDescriptionSummaryLen() with %q = %d, want default %d", invalid, got, shortToolDescriptionMaxLen)
+		}
+	}
+}
+
 func TestToolSchemaPayloadUsesNativeToolName(t *testing.T) {
 	cfg := &config.Config{
 		Servers: map[string]config.ServerConfig{
@@ -171,6 +197,42 @@ func TestToolSchemaPayloadUsesNativeToolName(t *testing.T) {
 	}
 }
 
+func TestToolSchemaPayloadIncludesDeclaredAnnotations(t *testing.T) {
+	cfg := &config.Config{
+		Servers: map[string]config.ServerConfig{
+			"github": {},
+		},
+	}
+	ka := NewKeepalive(nil)
+	defer ka.Stop()
+
+	destructive, readOnly := true, false
+	deps := runtimeDefaultDeps()
+	deps.poolToolInfoByName = func(_ context.Context, _ *mcppool.Pool, _, _ string) (*mcppool.ToolInfo, error) {
+		return &mcppool.ToolInfo{
+			Name:        "delete_repo",
+			InputSchema: json.RawMessage(`{"type":"object"}`),
+			Annotations: mcppool.ToolAnnotations{Destructive: &destructive, ReadOnly: &readOnly},
+		}, nil
+	}
+
+	resp := toolSchemaWithDeps(context.Background(), cfg, nil, ka, "github", "delete_repo", deps)
+	if resp.ExitCode != 0 {
+		t.Fatalf("toolSchema() exit = %d, want 0", resp.ExitCode)
+	}
+
+	var payload struct {
+		Annotations map[string]bool `json:"annotations"`
+	}
+	if err := json.Unmarshal(resp.Content, &payload); err != nil {
+		t.Fatalf("unmarshal payload: %v", err)
+	}
+	want := map[string]bool{"destructiveHint": true, "readOnlyHint": false}
+	if !reflect.DeepEqual(payload.Annotations, want) {
+		t.Fatalf("annotations = %#v, want %#v", payload.Annotations, want)
+	}
+}
+
 func TestListServersHidesCodexAppsAndShowsVirtualServers(t *testing.T) {
 	cfg := &config.Config{
 		Servers: map[string]config.ServerConfig{
@@ -451,3 +513,154 @@ func decodeServerLines(payload []byte) []string {
 	}
 	return out
 }
+
+func TestListServersReportsPoolStateWithoutConnecting(t *testing.T) {
+	cfg := &config.Config{
+		Servers: map[string]config.ServerConfig{
+			"github":            {},
+			"linear":            {},
+			codexAppsServerName: {},
+		},
+	}
+	ka := NewKeepalive(nil)
+	defer ka.Stop()
+
+	deps := runtimeDefaultDeps()
+	deps.poolListTools = func(_ context.Context, _ *mcppool.Pool, _ string) ([]mcppool.ToolInfo, error) {
+		return []mcppool.ToolInfo{{Name: "zillow_get_zestimate"}}, nil
+	}
+	states := map[string]mcppool.ServerState{
+		"github":            mcppool.ServerStateConnected,
+		"linear":            mcppool.ServerStateFailed,
+		codexAppsServerName: mcppool.ServerStateIdle,
+	}
+	deps.poolServerState = func(_ *mcppool.Pool, server string) mcppool.ServerState {
+		return states[server]
+	}
+	deps.poolLastError = func(_ *mcppool.Pool, server string) (mcppool.LastError, bool) {
+		if server != "linear" {
+			return mcppool.LastError{}, false
+		}
+		return mcppool.LastError{
+			At:      time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC),
+			Message: "connecting to linear: connection refused",
+		}, true
+	}
+
+	resp := listServersWithDeps(context.Background(), cfg, nil, ka, false, deps)
+	if resp.ExitCode != ipc.ExitOK {
+		t.Fatalf("listServers() exit = %d, want %d", resp.ExitCode, ipc.ExitOK)
+	}
+
+	got := map[string]string{}
+	for _, entry := range decodeServerEntries(resp.Content) {
+		got[entry.Name] = entry.State
+	}
+	want := map[string]string{
+		"github": "connected",
+		"linear": "failed",
+		"zillow": "idle",
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("server states = %#v, want %#v", got, want)
+	}
+
+	for _, entry := range decodeServerEntries(resp.Content) {
+		wantErr, wantAt := "", ""
+		if entry.Name == "linear" {
+			wantErr, wantAt = "connecting to linear: connection refused", "2026-01-02T03:04:05Z"
+		}
+		if entry.LastError != wantErr || entry.LastErrorAt != wantAt {
+			t.Fatalf("%s last error = (%q, %q), want (%q, %q)", entry.Name, entry.LastError, entry.LastErrorAt, wantErr, wantAt)
+		}
+	}
+}
+
+func TestListServersReportsTransport(t *testing.T) {
+	cfg := &config.Config{
+		Servers: map[string]config.ServerConfig{
+			"github": {URL: "https://example.com/mcp"},
+			"local":  {Command: "local-mcp"},
+		},
+	}
+	ka := NewKeepalive(nil)
+	defer ka.Stop()
+
+	resp := listServersWithDeps(context.Background(), cfg, nil, ka, false, runtimeDefaultDeps())
+	got := map[string]string{}
+	for _, entry := range decodeServerEntries(resp.Content) {
+		got[entry.Name] = entry.Transport
+	}
+	want := map[string]string{"github": "http", "local": "stdio"}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("server transports = %#v, want %#v", got, want)
+	}
+}
+
+func TestListToolsHidesToolsOutsideAllowAndDenyLists(t *testing.T) {
+	cfg := &config.Config{
+		Servers: map[string]config.ServerConfig{
+			"github": {
+				AllowTools: []string{"list_*", "delete_*"},
+				DenyTools:  []string{"delete_*"},
+			},
+		},
+	}
+	ka := NewKeepalive(nil)
+	defer ka.Stop()
+
+	deps := runtimeDefaultDeps()
+	deps.poolListTools = func(_ context.Context, _ *mcppool.Pool, _ string) ([]mcppool.ToolInfo, error) {
+		return []mcppool.ToolInfo{
+			{Name: "list_issues", Description: "List issues"},
+			{Name: "delete_repository", Description: "Delete a repository"},
+			{Name: "search_repositories", Description: "Search"},
+		}, nil
+	}
+
+	resp := listToolsWithDeps(context.Background(), cfg, nil, ka, "github", false, deps)
+	if resp.ExitCode != ipc.ExitOK {
+		t.Fatalf("listTools() exit = %d, want %d (stderr=%q)", resp.ExitCode, ipc.ExitOK, resp.Stderr)
+	}
+	var got []toolListEntry
+	if err := json.Unmarshal(resp.Content, &got); err != nil {
+		t.Fatalf("unmarshal json tool list: %v; payload=%q", err, string(resp.Content))
+	}
+	want := []toolListEntry{{Name: "list_issues", Description: "List issues"}}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("json tool list = %#v, want %#v", got, want)
+	}
+}
+
+func TestCallToolRejectsDeniedToolWithoutCallingServer(t *testing.T) {
+	cfg := &config.Config{
+		Servers: map[string]config.ServerConfig{
+			"github": {DenyTools: []string{"delete_*"}},
+		},
+	}
+	ka := NewKeepalive(nil)
+	defer ka.Stop()
+
+	deps := runtimeDefaultDeps()
+	deps.poolCallToolWithInfo = func(_ context.Context, _ *mcppool.Pool, _ string, _ *mcppool.ToolInfo, _ json.RawMessage) (*mcp.CallToolResult, error) {
+		t.Fatal("poolCallToolWithInfo called for denied tool")
+		return nil, nil
+	}
+	deps.cacheGet = func(_, _ string, _ json.RawMessage) (cache.Response, bool) {
+		t.Fatal("cacheGet called for denied tool")
+		return cache.Response{}, false
+	}
+
+	resp := callToolWithDeps(context.Background(), cfg, nil, ka, "github", "delete_repository", json.RawMessage(`{}`), nil, false, deps)
+	if resp.ExitCode != ipc.ExitUsageErr {
+		t.Fatalf("callTool() exit = %d, want %d", resp.ExitCode, ipc.ExitUsageErr)
+	}
+	if !strings.Contains(resp.Stderr, "not allowed") {
+		t.Fatalf("callTool() stderr = %q, want not allowed message", resp.Stderr)
+	}
+
+	schemaResp := toolSchemaWithDeps(context.Background(), cfg, nil, ka, "github", "delete_repository", deps)
+	if schemaResp.ExitCode != ipc.ExitUsageErr {
+		t.Fatalf("toolSchema() exit = %d, want %d", schemaResp.ExitCode, ipc.ExitUsageErr)
+	}
+}
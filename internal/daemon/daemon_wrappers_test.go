@@ -18,6 +18,7 @@ import (
 
 func TestRunReturnsRuntimeDirErrorBeforeStartingDaemon(t *testing.T) {
 	t.Setenv("XDG_RUNTIME_DIR", "/dev/null")
+	t.Setenv("TMPDIR", "/dev/null") // also rules out the temp-dir fallbacks
 
 	err := Run()
 	if err == nil {
@@ -135,7 +136,7 @@ func TestListServersWrapperReturnsConfiguredServerNames(t *testing.T) {
 		t.Fatalf("json.Unmarshal(server list) error = %v", err)
 	}
 	want := []serverListEntry{
-		{Name: "github", Origin: config.NewServerOrigin(config.ServerOriginKindMCPXConfig, "/tmp/config.toml")},
+		{Name: "github", Origin: config.NewServerOrigin(config.ServerOriginKindMCPXConfig, "/tmp/config.toml"), State: string(mcppool.ServerStateNever)},
 	}
 	if !reflect.DeepEqual(entries, want) {
 		t.Fatalf("listServers() entries = %#v, want %#v", entries, want)
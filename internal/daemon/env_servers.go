@@ -0,0 +1,134 @@
+package daemon
+
+import (
+	"fmt"
+	"reflect"
+
+	"github.com/lydakis/mcpx/internal/config"
+)
+
+// envServerOverlay layers the env-defined servers sent with CLI requests
+// over the loaded config. The daemon's own environment is whatever the
+// first CLI that spawned it had, so it serves the caller's
+// MCPX_SERVER_<NAME>_* servers instead of its own.
+type envServerOverlay struct {
+	// cfg is the config servers were applied to; a reload replaces it.
+	cfg *config.Config
+	// servers are the env-defined servers applied last, which pooled
+	// connections for those names were started from.
+	servers map[string]config.ServerConfig
+	// base holds the cfg entries the servers replaced.
+	base map[string]envServerBase
+}
+
+type envServerBase struct {
+	server    config.ServerConfig
+	origin    config.ServerOrigin
+	exists    bool
+	hasOrigin bool
+}
+
+// matches reports whether cfg already serves exactly servers; nil servers
+// leave the overlay alone and always match.
+func (o *envServerOverlay) matches(cfg *config.Config, servers map[string]config.ServerConfig) bool {
+	if servers == nil {
+		return true
+	}
+	return o.cfg == cfg && o.servers != nil && reflect.DeepEqual(o.servers, servers)
+}
+
+// apply makes servers the env-defined servers of cfg: names no longer
+// defined get their config file entry back (or disappear), and closeServer
+// drops the pooled connections of every name whose definition changed.
+func (o *envServerOverlay) apply(cfg *config.Config, servers map[string]config.ServerConfig, closeServer func(string)) error {
+	if cfg == nil || o.matches(cfg, servers) {
+		return nil
+	}
+	for name, server := range servers {
+		if err := config.ValidateServerConfig(name, server); err != nil {
+			return fmt.Errorf("env-defined server %s: %w", name, err)
+		}
+	}
+
+	running := o.servers
+	if o.cfg != cfg {
+		// A reload replaced cfg, so nothing is overlaid on it yet.
+		o.cfg = cfg
+		o.base = nil
+	}
+	if o.base == nil {
+		o.base = make(map[string]envServerBase)
+	}
+	if cfg.Servers == nil {
+		cfg.Servers = make(map[string]config.ServerConfig, len(servers))
+	}
+	if cfg.ServerOrigins == nil {
+		cfg.ServerOrigins = make(map[string]config.ServerOrigin, len(servers))
+	}
+
+	for name := range running {
+		if _, ok := servers[name]; ok {
+			continue
+		}
+		if base, ok := o.base[name]; ok {
+			restoreEnvServerBase(cfg, name, base)
+			delete(o.base, name)
+		}
+		closeServer(name)
+	}
+	for name, server := range servers {
+		if _, ok := o.base[name]; !ok {
+			base := envServerBase{}
+			base.server, base.exists = cfg.Servers[name]
+			base.origin, base.hasOrigin = cfg.ServerOrigins[name]
+			o.base[name] = base
+		}
+		if prev, ok := running[name]; !ok || !reflect.DeepEqual(prev, server) {
+			closeServer(name)
+		}
+		cfg.Servers[name] = server
+		cfg.ServerOrigins[name] = config.EnvServerOrigin(name)
+	}
+
+	o.servers = make(map[string]config.ServerConfig, len(servers))
+	for name, server := range servers {
+		o.servers[name] = server
+	}
+	return nil
+}
+
+// withoutOverlay returns cfg as loaded, without the env-defined servers
+// applied to it, for fingerprinting: they arrive with each request rather
+// than from config files, so they must not make a reload look like a config
+// change.
+func (o *envServerOverlay) withoutOverlay(cfg *config.Config) *config.Config {
+	if cfg == nil || o.cfg != cfg || len(o.base) == 0 {
+		return cfg
+	}
+	out := *cfg
+	out.Servers = make(map[string]config.ServerConfig, len(cfg.Servers))
+	for name, server := range cfg.Servers {
+		out.Servers[name] = server
+	}
+	out.ServerOrigins = make(map[string]config.ServerOrigin, len(cfg.ServerOrigins))
+	for name, origin := range cfg.ServerOrigins {
+		out.ServerOrigins[name] = origin
+	}
+	for name, base := range o.base {
+		restoreEnvServerBase(&out, name, base)
+	}
+	return &out
+}
+
+func restoreEnvServerBase(cfg *config.Config, name string, base envServerBase) {
+	if base.exists {
+		cfg.Servers[name] = base.server
+	} else {
+		delete(cfg.Servers, name)
+	}
+	if base.hasOrigin {
+		cfg.ServerOrigins[name] = base.origin
+	} else {
+		delete(cfg.ServerOrigins, name)
+	}
+}
@@ -0,0 +1,89 @@
+package daemon
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	"github.com/lydakis/mcpx/internal/config"
+	"github.com/lydakis/mcpx/internal/ipc"
+	"github.com/lydakis/mcpx/internal/mcppool"
+)
+
+func TestRuntimeRequestHandlerServesEnvServersFromRequest(t *testing.T) {
+	// The daemon was spawned from an environment without env-defined
+	// servers; callers with different environments send their own.
+	fileOrigin := config.NewServerOrigin(config.ServerOriginKindMCPXConfig, "/home/u/.config/mcpx/config.toml")
+	cfg := &config.Config{
+		Servers:       map[string]config.ServerConfig{"docs": {Command: "docs-mcp"}},
+		ServerOrigins: map[string]config.ServerOrigin{"docs": fileOrigin},
+	}
+	ka := NewKeepalive(nil)
+	defer ka.Stop()
+
+	var closed []string
+	deps := runtimeDefaultDeps()
+	deps.poolListTools = func(context.Context, *mcppool.Pool, string) ([]mcppool.ToolInfo, error) {
+		return []mcppool.ToolInfo{{Name: "ping"}}, nil
+	}
+	deps.poolClose = func(_ *mcppool.Pool, server string) {
+		closed = append(closed, server)
+	}
+
+	handler := newRuntimeRequestHandlerWithDeps(cfg, nil, ka, deps)
+	handler.activeCWD = "/tmp/project"
+	initialHash := handler.cfgHash
+	send := func(server string, envServers map[string]config.ServerConfig) {
+		t.Helper()
+		closed = nil
+		resp := handler.handle(context.Background(), &ipc.Request{
+			Type:       "list_tools",
+			Server:     server,
+			CWD:        "/tmp/project",
+			EnvServers: envServers,
+		})
+		if resp.ExitCode != ipc.ExitOK {
+			t.Fatalf("list_tools %s exit = %d, want %d (stderr=%q)", server, resp.ExitCode, ipc.ExitOK, resp.Stderr)
+		}
+	}
+
+	send("api", map[string]config.ServerConfig{"api": {Command: "api-v1"}})
+	if got := handler.cfg.Servers["api"].Command; got != "api-v1" {
+		t.Fatalf("api command = %q, want api-v1", got)
+	}
+	if got := handler.cfg.ServerOrigins["api"].Kind; got != config.ServerOriginKindEnv {
+		t.Fatalf("api origin = %q, want %q", got, config.ServerOriginKindEnv)
+	}
+
+	send("api", map[string]config.ServerConfig{"api": {Command: "api-v1"}})
+	if len(closed) != 0 {
+		t.Fatalf("closed = %q for an unchanged definition, want none", closed)
+	}
+
+	send("api", map[string]config.ServerConfig{"api": {Command: "api-v2"}})
+	if got := handler.cfg.Servers["api"].Command; got != "api-v2" {
+		t.Fatalf("api command = %q, want api-v2", got)
+	}
+	if !reflect.DeepEqual(closed, []string{"api"}) {
+		t.Fatalf("closed = %q, want the changed server", closed)
+	}
+
+	send("docs", map[string]config.ServerConfig{"docs": {Command: "env-docs"}})
+	if got := handler.cfg.Servers["docs"].Command; got != "env-docs" {
+		t.Fatalf("docs command = %q, want the env definition", got)
+	}
+	if _, ok := handler.cfg.Servers["api"]; ok {
+		t.Fatal("api still defined after the caller dropped it")
+	}
+
+	send("docs", map[string]config.ServerConfig{})
+	if got := handler.cfg.Servers["docs"].Command; got != "docs-mcp" {
+		t.Fatalf("docs command = %q, want the config file entry back", got)
+	}
+	if got := handler.cfg.ServerOrigins["docs"]; got != fileOrigin {
+		t.Fatalf("docs origin = %#v, want %#v", got, fileOrigin)
+	}
+	if handler.cfgHash != initialHash {
+		t.Fatal("cfgHash changed, want env-defined servers left out of the config fingerprint")
+	}
+}
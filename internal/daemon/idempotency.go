@@ -0,0 +1,54 @@
+package daemon
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/lydakis/mcpx/internal/config"
+	"github.com/lydakis/mcpx/internal/mcppool"
+)
+
+type idempotencyKeyCtx struct{}
+
+// withIdempotencyKey carries a call's --idempotency-key to callToolWithDeps,
+// which knows the resolved server config it has to be mapped through.
+func withIdempotencyKey(ctx context.Context, key string) context.Context {
+	if key == "" {
+		return ctx
+	}
+	return context.WithValue(ctx, idempotencyKeyCtx{}, key)
+}
+
+// applyIdempotencyKey places the call's idempotency key, if any, where the
+// tool's idempotency_key mapping says: as a request header or as an argument
+// that overrides any value the caller passed.
+func applyIdempotencyKey(ctx context.Context, args json.RawMessage, scfg config.ServerConfig, tool string) (context.Context, json.RawMessage, error) {
+	key, _ := ctx.Value(idempotencyKeyCtx{}).(string)
+	if key == "" {
+		return ctx, args, nil
+	}
+	target, err := scfg.IdempotencyTarget(tool)
+	if err != nil {
+		return ctx, nil, err
+	}
+	if target.Header != "" {
+		return mcppool.WithRequestHeaders(ctx, map[string]string{target.Header: key}), args, nil
+	}
+
+	merged := map[string]any{}
+	if len(args) > 0 {
+		if err := json.Unmarshal(args, &merged); err != nil {
+			return ctx, nil, fmt.Errorf("invalid args: %w", err)
+		}
+		if merged == nil {
+			merged = map[string]any{}
+		}
+	}
+	merged[target.Arg] = key
+	out, err := json.Marshal(merged)
+	if err != nil {
+		return ctx, nil, fmt.Errorf("encoding args: %w", err)
+	}
+	return ctx, out, nil
+}
@@ -0,0 +1,83 @@
+package daemon
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/lydakis/mcpx/internal/config"
+)
+
+func TestApplyIdempotencyKeyFollowsToolMapping(t *testing.T) {
+	tests := []struct {
+		name     string
+		scfg     config.ServerConfig
+		args     string
+		wantArgs string
+	}{
+		{
+			name:     "http default header",
+			scfg:     config.ServerConfig{URL: "https://example.com/mcp"},
+			args:     `{"amount":1}`,
+			wantArgs: `{"amount":1}`,
+		},
+		{
+			name: "stdio configured arg",
+			scfg: config.ServerConfig{Command: "payments-mcp", Tools: map[string]config.ToolConfig{
+				"charge": {IdempotencyKey: "arg:idempotency_key"},
+			}},
+			args:     `{"amount":1}`,
+			wantArgs: `{"amount":1,"idempotency_key":"order-1"}`,
+		},
+		{
+			name: "configured arg overrides caller value",
+			scfg: config.ServerConfig{URL: "https://example.com/mcp", Tools: map[string]config.ToolConfig{
+				"charge": {IdempotencyKey: "arg:request_id"},
+			}},
+			args:     `{"amount":1,"request_id":"stale"}`,
+			wantArgs: `{"amount":1,"request_id":"order-1"}`,
+		},
+		{
+			name: "configured header",
+			scfg: config.ServerConfig{Command: "payments-mcp", Tools: map[string]config.ToolConfig{
+				"charge": {IdempotencyKey: "header:X-Request-Id"},
+			}},
+			args:     `{"amount":1}`,
+			wantArgs: `{"amount":1}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := withIdempotencyKey(context.Background(), "order-1")
+			_, got, err := applyIdempotencyKey(ctx, json.RawMessage(tt.args), tt.scfg, "charge")
+			if err != nil {
+				t.Fatalf("applyIdempotencyKey() error = %v", err)
+			}
+			if string(got) != tt.wantArgs {
+				t.Fatalf("args = %s, want %s", got, tt.wantArgs)
+			}
+		})
+	}
+}
+
+func TestApplyIdempotencyKeyIsNoOpWithoutKey(t *testing.T) {
+	args := json.RawMessage(`{"amount":1}`)
+	ctx := context.Background()
+	gotCtx, got, err := applyIdempotencyKey(ctx, args, config.ServerConfig{Command: "payments-mcp"}, "charge")
+	if err != nil {
+		t.Fatalf("applyIdempotencyKey() error = %v", err)
+	}
+	if gotCtx != ctx || string(got) != string(args) {
+		t.Fatalf("applyIdempotencyKey() changed ctx or args: %s", got)
+	}
+}
+
+func TestApplyIdempotencyKeyRequiresArgMappingOnStdio(t *testing.T) {
+	ctx := withIdempotencyKey(context.Background(), "order-1")
+	_, _, err := applyIdempotencyKey(ctx, json.RawMessage(`{"amount":1}`), config.ServerConfig{Command: "payments-mcp"}, "charge")
+	if err == nil || !strings.Contains(err.Error(), `tools.charge.idempotency_key = "arg:<param>"`) {
+		t.Fatalf("applyIdempotencyKey() error = %v, want missing arg mapping error", err)
+	}
+}
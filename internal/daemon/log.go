@@ -0,0 +1,63 @@
+package daemon
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"strings"
+)
+
+// daemonLog receives daemon lifecycle messages. The default keeps the plain
+// "mcpx daemon: ..." lines; RunWithOptions can switch to JSON or raise the
+// minimum level for supervised deployments.
+var daemonLog = newDaemonLogger(os.Stderr, LogFormatText, slog.LevelInfo)
+
+// Daemon log formats accepted by RunOptions.LogFormat.
+const (
+	LogFormatText = "text"
+	LogFormatJSON = "json"
+)
+
+// ParseLogLevel maps debug|info|warn|error to a slog level.
+func ParseLogLevel(raw string) (slog.Level, error) {
+	var level slog.Level
+	if err := level.UnmarshalText([]byte(strings.TrimSpace(raw))); err != nil {
+		return 0, fmt.Errorf("invalid log level %q (want debug, info, warn, or error)", raw)
+	}
+	return level, nil
+}
+
+func newDaemonLogger(w io.Writer, format string, level slog.Level) *slog.Logger {
+	if format == LogFormatJSON {
+		return slog.New(slog.NewJSONHandler(w, &slog.HandlerOptions{Level: level}))
+	}
+	return slog.New(&plainLogHandler{w: w, level: level})
+}
+
+// plainLogHandler renders one "mcpx daemon: [warning: ]msg" line per record.
+type plainLogHandler struct {
+	w     io.Writer
+	level slog.Level
+}
+
+func (h *plainLogHandler) Enabled(_ context.Context, level slog.Level) bool {
+	return level >= h.level
+}
+
+func (h *plainLogHandler) Handle(_ context.Context, r slog.Record) error {
+	prefix := "mcpx daemon: "
+	switch {
+	case r.Level >= slog.LevelError:
+		prefix += "error: "
+	case r.Level >= slog.LevelWarn:
+		prefix += "warning: "
+	}
+	_, err := fmt.Fprintln(h.w, prefix+r.Message)
+	return err
+}
+
+func (h *plainLogHandler) WithAttrs([]slog.Attr) slog.Handler { return h }
+
+func (h *plainLogHandler) WithGroup(string) slog.Handler { return h }
@@ -0,0 +1,21 @@
+package daemon
+
+import (
+	"context"
+
+	"github.com/lydakis/mcpx/internal/ipc"
+	"github.com/lydakis/mcpx/internal/mcppool"
+)
+
+// progressSink returns the callback that relays the server's progress
+// notifications for this call to the IPC client as they arrive, or nil when
+// the client did not ask for them.
+func progressSink(ctx context.Context) func(mcppool.Progress) {
+	sink := ipc.ProgressSink(ctx)
+	if sink == nil {
+		return nil
+	}
+	return func(p mcppool.Progress) {
+		sink(ipc.Progress{Progress: p.Progress, Total: p.Total, Message: p.Message})
+	}
+}
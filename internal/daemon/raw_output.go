@@ -0,0 +1,31 @@
+package daemon
+
+import (
+	"context"
+
+	"github.com/lydakis/mcpx/internal/ipc"
+	"github.com/lydakis/mcpx/internal/response"
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+type rawOutputCtx struct{}
+
+// withRawOutput marks a call whose result is sent as the raw bytes of its
+// single content block (--output-raw-bytes).
+func withRawOutput(ctx context.Context) context.Context {
+	return context.WithValue(ctx, rawOutputCtx{}, true)
+}
+
+func rawOutputRequested(ctx context.Context) bool {
+	raw, _ := ctx.Value(rawOutputCtx{}).(bool)
+	return raw
+}
+
+// unwrapRawResult is response.UnwrapRaw shaped as a call_tool response.
+func unwrapRawResult(result *mcp.CallToolResult) *ipc.Response {
+	out, exitCode, hint, err := response.UnwrapRaw(result)
+	if err != nil {
+		return &ipc.Response{ExitCode: exitCode, Stderr: err.Error()}
+	}
+	return &ipc.Response{Content: out, ExitCode: exitCode, ContentType: hint.ContentType, Encoding: hint.Encoding}
+}
@@ -0,0 +1,46 @@
+package daemon
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/lydakis/mcpx/internal/ipc"
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+type rawResultCtx struct{}
+
+// withRawResult marks a call whose result is sent as the whole
+// CallToolResult JSON instead of unwrapped content (--raw).
+func withRawResult(ctx context.Context) context.Context {
+	return context.WithValue(ctx, rawResultCtx{}, true)
+}
+
+func rawResultRequested(ctx context.Context) bool {
+	raw, _ := ctx.Value(rawResultCtx{}).(bool)
+	return raw
+}
+
+// marshalRawResult encodes result as a call_tool response. Error results
+// keep their full JSON but exit with ExitToolErr, as unwrapped ones do.
+func marshalRawResult(result *mcp.CallToolResult, logs []string) *ipc.Response {
+	if result == nil {
+		return &ipc.Response{ExitCode: ipc.ExitInternal, Stderr: "empty tool result"}
+	}
+	out, err := json.Marshal(result)
+	if err != nil {
+		return &ipc.Response{ExitCode: ipc.ExitInternal, Stderr: fmt.Sprintf("encoding tool result: %v", err)}
+	}
+	exitCode := ipc.ExitOK
+	if result.IsError {
+		exitCode = ipc.ExitToolErr
+	}
+	return &ipc.Response{
+		Content:     append(out, '\n'),
+		ExitCode:    exitCode,
+		Stderr:      joinLogs(logs),
+		ContentType: "application/json",
+		Encoding:    ipc.EncodingText,
+	}
+}
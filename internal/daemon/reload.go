@@ -0,0 +1,54 @@
+package daemon
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/lydakis/mcpx/internal/config"
+	"github.com/lydakis/mcpx/internal/ipc"
+)
+
+// reload forces a config reload for cwd regardless of the polling deadline or
+// config stamp. The pool is reset only when the config fingerprint changed.
+// On a load or validation error the daemon keeps serving the last good config
+// and reports the error to the caller.
+func (h *runtimeRequestHandler) reload(cwd string) *ipc.Response {
+	normalizedCWD := strings.TrimSpace(cwd)
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	var preserveFallbackFrom *config.Config
+	if normalizedCWD == strings.TrimSpace(h.activeCWD) {
+		preserveFallbackFrom = h.cfg
+	}
+
+	nextState, err := loadRuntimeConfigStateForRequestWithDeps(normalizedCWD, runtimeConfigState{
+		activeCWD: h.activeCWD,
+		cfgHash:   h.cfgHash,
+		cfg:       h.cfg,
+	}, h.deps, preserveFallbackFrom)
+	if err != nil {
+		return &ipc.Response{ExitCode: ipc.ExitUsageErr, Stderr: fmt.Sprintf("reloading config: %v", err)}
+	}
+
+	changed := nextState.cfgHash != strings.TrimSpace(h.cfgHash)
+	if err := applyRuntimeConfigStateWithDeps(&h.activeCWD, &h.cfgHash, &h.cfg, h.pool, h.ka, h.deps, nextState); err != nil {
+		return &ipc.Response{ExitCode: ipc.ExitInternal, Stderr: fmt.Sprintf("applying config: %v", err)}
+	}
+	if _, err := installRuntimeEphemeralServers(h.cfg, h.ephemeralServers); err != nil {
+		return &ipc.Response{ExitCode: ipc.ExitInternal, Stderr: fmt.Sprintf("restoring ephemeral servers: %v", err)}
+	}
+
+	stamp := h.deps.currentRuntimeConfigStamp(h.cfg, normalizedCWD)
+	h.runtimeConfigStamp = stamp
+	h.lastPolledConfigStamp = stamp
+	h.nextConfigPollAt = h.deps.now().Add(runtimeConfigPollInterval)
+	h.stateVersion++
+
+	msg := "config reloaded (unchanged)\n"
+	if changed {
+		msg = "config reloaded (server connections reset)\n"
+	}
+	return &ipc.Response{Content: []byte(msg)}
+}
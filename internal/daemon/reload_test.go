@@ -0,0 +1,102 @@
+package daemon
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/lydakis/mcpx/internal/config"
+	"github.com/lydakis/mcpx/internal/ipc"
+	"github.com/lydakis/mcpx/internal/mcppool"
+)
+
+func TestRuntimeRequestHandlerReloadAppliesChangedConfig(t *testing.T) {
+	cfg := &config.Config{Servers: map[string]config.ServerConfig{"github": {Command: "echo"}}}
+
+	now := time.Unix(1_700_000_000, 0)
+	current := &config.Config{Servers: map[string]config.ServerConfig{"github": {Command: "echo"}}}
+	var resetCalls int
+
+	deps := runtimeDefaultDeps()
+	deps.now = func() time.Time { return now }
+	deps.currentRuntimeConfigStamp = func(*config.Config, string) runtimeConfigStamp {
+		return runtimeConfigStamp{Digest: "stable"}
+	}
+	deps.loadConfig = func() (*config.Config, error) { return current, nil }
+	deps.mergeFallbackForCWD = func(*config.Config, string) error { return nil }
+	deps.validateConfig = func(*config.Config) error { return nil }
+	deps.poolReset = func(*mcppool.Pool, *config.Config) { resetCalls++ }
+	deps.poolSetConfig = func(*mcppool.Pool, *config.Config) {}
+	deps.keepaliveStop = func(*Keepalive) {}
+
+	handler := newRuntimeRequestHandlerWithDeps(cfg, nil, nil, deps)
+	handler.activeCWD = "/tmp/project"
+	handler.nextConfigPollAt = now.Add(time.Minute)
+
+	resp := handler.handle(context.Background(), &ipc.Request{Type: "reload", CWD: "/tmp/project"})
+	if resp.ExitCode != ipc.ExitOK {
+		t.Fatalf("reload exit = %d, want %d (stderr=%q)", resp.ExitCode, ipc.ExitOK, resp.Stderr)
+	}
+	if resetCalls != 0 {
+		t.Fatalf("poolReset calls = %d, want 0 for unchanged config", resetCalls)
+	}
+	if !strings.Contains(string(resp.Content), "unchanged") {
+		t.Fatalf("reload content = %q, want unchanged note", resp.Content)
+	}
+
+	// The stamp does not change, so only an explicit reload picks this up
+	// before the next poll deadline.
+	current = &config.Config{Servers: map[string]config.ServerConfig{"linear": {Command: "echo"}}}
+
+	resp = handler.handle(context.Background(), &ipc.Request{Type: "reload", CWD: "/tmp/project"})
+	if resp.ExitCode != ipc.ExitOK {
+		t.Fatalf("reload exit = %d, want %d (stderr=%q)", resp.ExitCode, ipc.ExitOK, resp.Stderr)
+	}
+	if resetCalls != 1 {
+		t.Fatalf("poolReset calls = %d, want 1 after config change", resetCalls)
+	}
+	if !strings.Contains(string(resp.Content), "reset") {
+		t.Fatalf("reload content = %q, want reset note", resp.Content)
+	}
+	if _, ok := handler.cfg.Servers["linear"]; !ok {
+		t.Fatalf("cfg.Servers = %#v, want reloaded linear entry", handler.cfg.Servers)
+	}
+	if !handler.nextConfigPollAt.Equal(now.Add(runtimeConfigPollInterval)) {
+		t.Fatalf("nextConfigPollAt = %s, want %s", handler.nextConfigPollAt, now.Add(runtimeConfigPollInterval))
+	}
+}
+
+func TestRuntimeRequestHandlerReloadReportsValidationErrorAndKeepsConfig(t *testing.T) {
+	cfg := &config.Config{Servers: map[string]config.ServerConfig{"github": {Command: "echo"}}}
+
+	deps := runtimeDefaultDeps()
+	deps.currentRuntimeConfigStamp = func(*config.Config, string) runtimeConfigStamp {
+		return runtimeConfigStamp{Digest: "stable"}
+	}
+	deps.loadConfig = func() (*config.Config, error) {
+		return &config.Config{Servers: map[string]config.ServerConfig{"broken": {}}}, nil
+	}
+	deps.mergeFallbackForCWD = func(*config.Config, string) error { return nil }
+	deps.validateConfig = func(*config.Config) error {
+		return errors.New("servers.broken: missing transport")
+	}
+	deps.poolReset = func(*mcppool.Pool, *config.Config) {
+		t.Fatal("poolReset called after failed reload")
+	}
+
+	handler := newRuntimeRequestHandlerWithDeps(cfg, nil, nil, deps)
+	handler.activeCWD = "/tmp/project"
+
+	resp := handler.handle(context.Background(), &ipc.Request{Type: "reload", CWD: "/tmp/project"})
+	if resp.ExitCode != ipc.ExitUsageErr {
+		t.Fatalf("reload exit = %d, want %d", resp.ExitCode, ipc.ExitUsageErr)
+	}
+	if !strings.Contains(resp.Stderr, "servers.broken: missing transport") {
+		t.Fatalf("reload stderr = %q, want validation error", resp.Stderr)
+	}
+	if handler.cfg != cfg {
+		t.Fatal("handler config replaced after failed reload")
+	}
+}
@@ -0,0 +1,83 @@
+package daemon
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/lydakis/mcpx/internal/config"
+	"github.com/lydakis/mcpx/internal/mcppool"
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+// serverRequestTimeout returns the per-request budget for server: its
+// request_timeout, else the top-level default_request_timeout, else zero
+// (no limit). Both are validated when config loads.
+func serverRequestTimeout(cfg *config.Config, server string) time.Duration {
+	if cfg == nil {
+		return 0
+	}
+	raw := cfg.DefaultRequestTimeout
+	if scfg, ok := cfg.Servers[server]; ok && scfg.RequestTimeout != "" {
+		raw = scfg.RequestTimeout
+	}
+	if raw == "" {
+		return 0
+	}
+	timeout, err := time.ParseDuration(raw)
+	if err != nil || timeout <= 0 {
+		return 0
+	}
+	return timeout
+}
+
+// withRequestTimeouts bounds each tools/list, tool lookup, and tools/call
+// sent to a server by that server's request timeout. It wraps the pool
+// directly, so call-level timeouts and retries treat an expired request as
+// one failed attempt.
+func withRequestTimeouts(cfg *config.Config, deps runtimeDeps) runtimeDeps {
+	listTools := deps.poolListTools
+	deps.poolListTools = func(ctx context.Context, pool *mcppool.Pool, server string) ([]mcppool.ToolInfo, error) {
+		reqCtx, cancel, timeout := requestTimeoutContext(ctx, cfg, server)
+		defer cancel()
+		tools, err := listTools(reqCtx, pool, server)
+		return tools, requestTimeoutError(ctx, reqCtx, server, timeout, err)
+	}
+
+	toolInfo := deps.poolToolInfoByName
+	deps.poolToolInfoByName = func(ctx context.Context, pool *mcppool.Pool, server, tool string) (*mcppool.ToolInfo, error) {
+		reqCtx, cancel, timeout := requestTimeoutContext(ctx, cfg, server)
+		defer cancel()
+		info, err := toolInfo(reqCtx, pool, server, tool)
+		return info, requestTimeoutError(ctx, reqCtx, server, timeout, err)
+	}
+
+	call := deps.poolCallToolWithInfo
+	deps.poolCallToolWithInfo = func(ctx context.Context, pool *mcppool.Pool, server string, info *mcppool.ToolInfo, args json.RawMessage) (*mcp.CallToolResult, error) {
+		reqCtx, cancel, timeout := requestTimeoutContext(ctx, cfg, server)
+		defer cancel()
+		result, err := call(reqCtx, pool, server, info, args)
+		return result, requestTimeoutError(ctx, reqCtx, server, timeout, err)
+	}
+	return deps
+}
+
+func requestTimeoutContext(ctx context.Context, cfg *config.Config, server string) (context.Context, context.CancelFunc, time.Duration) {
+	timeout := serverRequestTimeout(cfg, server)
+	if timeout <= 0 {
+		return ctx, func() {}, 0
+	}
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	return ctx, cancel, timeout
+}
+
+// requestTimeoutError names the server's request timeout when it, rather
+// than the caller's context, ended the request.
+func requestTimeoutError(ctx, reqCtx context.Context, server string, timeout time.Duration, err error) error {
+	if err == nil || timeout <= 0 || ctx.Err() != nil || !errors.Is(reqCtx.Err(), context.DeadlineExceeded) {
+		return err
+	}
+	return fmt.Errorf("request to server %q timed out after %s: %w", server, timeout, err)
+}
@@ -0,0 +1,63 @@
+package daemon
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/lydakis/mcpx/internal/config"
+	"github.com/lydakis/mcpx/internal/ipc"
+	"github.com/lydakis/mcpx/internal/mcppool"
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+func TestServerRequestTimeoutPrefersServerValue(t *testing.T) {
+	cfg := &config.Config{
+		DefaultRequestTimeout: "30s",
+		Servers: map[string]config.ServerConfig{
+			"slow":  {RequestTimeout: "2m"},
+			"plain": {},
+		},
+	}
+	if got := serverRequestTimeout(cfg, "slow"); got != 2*time.Minute {
+		t.Fatalf("serverRequestTimeout(slow) = %s, want 2m", got)
+	}
+	if got := serverRequestTimeout(cfg, "plain"); got != 30*time.Second {
+		t.Fatalf("serverRequestTimeout(plain) = %s, want 30s", got)
+	}
+	if got := serverRequestTimeout(&config.Config{}, "plain"); got != 0 {
+		t.Fatalf("serverRequestTimeout(unset) = %s, want 0", got)
+	}
+}
+
+func TestDispatchAppliesServerRequestTimeout(t *testing.T) {
+	cfg := &config.Config{Servers: map[string]config.ServerConfig{"github": {RequestTimeout: "20ms"}}}
+	ka := NewKeepalive(nil)
+	defer ka.Stop()
+
+	deps := runtimeDefaultDeps()
+	deps.poolCallToolWithInfo = func(ctx context.Context, _ *mcppool.Pool, _ string, _ *mcppool.ToolInfo, _ json.RawMessage) (*mcp.CallToolResult, error) {
+		<-ctx.Done()
+		return nil, ctx.Err()
+	}
+	deps.poolListTools = func(ctx context.Context, _ *mcppool.Pool, _ string) ([]mcppool.ToolInfo, error) {
+		<-ctx.Done()
+		return nil, ctx.Err()
+	}
+
+	for _, reqType := range []string{"call_tool", "list_tools"} {
+		resp := dispatchWithDeps(context.Background(), cfg, nil, ka, &ipc.Request{
+			Type:   reqType,
+			Server: "github",
+			Tool:   "search",
+		}, deps)
+		if resp.ExitCode != ipc.ExitInternal {
+			t.Fatalf("%s exit = %d, want %d (stderr=%q)", reqType, resp.ExitCode, ipc.ExitInternal, resp.Stderr)
+		}
+		if !strings.Contains(resp.Stderr, `request to server "github" timed out after 20ms`) {
+			t.Fatalf("%s stderr = %q, want request timeout message", reqType, resp.Stderr)
+		}
+	}
+}
@@ -0,0 +1,96 @@
+package daemon
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/lydakis/mcpx/internal/config"
+	"github.com/lydakis/mcpx/internal/ipc"
+	"github.com/lydakis/mcpx/internal/mcppool"
+)
+
+// resolvedCall is the resolve_call payload: where call_tool would send a
+// call and with which arguments, worked out without calling the tool.
+type resolvedCall struct {
+	Server   string          `json:"server"`
+	Backend  string          `json:"backend"`
+	Tool     string          `json:"tool"`
+	Args     json.RawMessage `json:"args"`
+	CacheTTL string          `json:"cache_ttl,omitempty"`
+}
+
+// resolveCallWithDeps routes a call the way callToolWithDeps does, through
+// virtual servers, allow/deny lists, saved defaults, and the idempotency key
+// mapping, and reports the result instead of executing it.
+func resolveCallWithDeps(ctx context.Context, cfg *config.Config, pool *mcppool.Pool, ka *Keepalive, server, tool string, args json.RawMessage, reqCache *time.Duration, deps runtimeDeps) *ipc.Response {
+	deps = deps.withDefaults()
+	catalog := newServerCatalogWithDeps(cfg, pool, ka, deps)
+	route, found, err := catalog.ResolveForTool(ctx, server, tool)
+	if err != nil {
+		return &ipc.Response{ExitCode: ipc.ExitInternal, Stderr: fmt.Sprintf("resolving server: %v", err)}
+	}
+	if !found {
+		return unknownServerResponse(server)
+	}
+	scfg, ok := cfg.Servers[route.ConfigServer]
+	if !ok {
+		return unknownServerResponse(server)
+	}
+	if !scfg.ToolAllowed(tool) {
+		return toolNotAllowedResponse(server, tool)
+	}
+	args, err = applyToolDefaults(args, scfg.Tools[tool].Defaults)
+	if err != nil {
+		return &ipc.Response{ExitCode: ipc.ExitUsageErr, Stderr: fmt.Sprintf("applying saved defaults: %v", err)}
+	}
+	_, args, err = applyIdempotencyKey(ctx, args, scfg, tool)
+	if err != nil {
+		return &ipc.Response{ExitCode: ipc.ExitUsageErr, Stderr: fmt.Sprintf("applying idempotency key: %v", err)}
+	}
+	if !catalog.ToolBelongsToRoute(route, tool) {
+		return &ipc.Response{
+			ExitCode: ipc.ExitUsageErr,
+			Stderr:   fmt.Sprintf("resolving tool: tool %s not found on server %s", tool, server),
+		}
+	}
+
+	resolved := tool
+	if pool != nil {
+		ka.Begin(route.Backend)
+		defer ka.End(route.Backend)
+
+		info, err := deps.poolToolInfoByName(ctx, pool, route.Backend, tool)
+		if err != nil {
+			return &ipc.Response{
+				ExitCode: classifyToolLookupError(err),
+				Stderr:   fmt.Sprintf("resolving tool: %v", err),
+			}
+		}
+		if info != nil && !scfg.ToolAllowed(info.Name) {
+			return toolNotAllowedResponse(server, info.Name)
+		}
+		if info != nil && info.Name != "" {
+			resolved = info.Name
+		}
+	}
+
+	cacheTTL, shouldCache, _, err := cacheDecision(scfg, tool, reqCache)
+	if err != nil {
+		return &ipc.Response{ExitCode: ipc.ExitInternal, Stderr: fmt.Sprintf("cache configuration error: %v", err)}
+	}
+
+	payload := resolvedCall{Server: server, Backend: route.Backend, Tool: resolved, Args: args}
+	if len(payload.Args) == 0 {
+		payload.Args = json.RawMessage(`{}`)
+	}
+	if shouldCache {
+		payload.CacheTTL = cacheTTL.String()
+	}
+	data, err := json.MarshalIndent(payload, "", "  ")
+	if err != nil {
+		return &ipc.Response{ExitCode: ipc.ExitInternal, Stderr: fmt.Sprintf("encoding resolved call: %v", err)}
+	}
+	return &ipc.Response{Content: append(data, '\n')}
+}
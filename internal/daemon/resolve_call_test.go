@@ -0,0 +1,91 @@
+package daemon
+
+import (
+	"context"
+	"encoding/json"
+	"reflect"
+	"testing"
+
+	"github.com/lydakis/mcpx/internal/config"
+	"github.com/lydakis/mcpx/internal/ipc"
+	"github.com/lydakis/mcpx/internal/mcppool"
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+func TestDispatchResolveCallReportsRouteWithoutCalling(t *testing.T) {
+	cfg := &config.Config{Servers: map[string]config.ServerConfig{
+		"github": {
+			DefaultCacheTTL: "45s",
+			Tools: map[string]config.ToolConfig{
+				"search": {Defaults: map[string]any{"per_page": float64(10)}},
+			},
+		},
+		codexAppsServerName: {},
+	}}
+	ka := NewKeepalive(nil)
+	defer ka.Stop()
+	pool := mcppool.New(cfg)
+	defer pool.CloseAll()
+
+	deps := runtimeDefaultDeps()
+	deps.poolToolInfoByName = func(_ context.Context, _ *mcppool.Pool, server, tool string) (*mcppool.ToolInfo, error) {
+		if server == "github" && tool == "search" {
+			return &mcppool.ToolInfo{Name: "search_repositories"}, nil
+		}
+		return &mcppool.ToolInfo{Name: tool}, nil
+	}
+	deps.poolCallToolWithInfo = func(context.Context, *mcppool.Pool, string, *mcppool.ToolInfo, json.RawMessage) (*mcp.CallToolResult, error) {
+		t.Fatal("resolve_call executed the tool")
+		return nil, nil
+	}
+
+	tests := []struct {
+		name string
+		req  *ipc.Request
+		want resolvedCall
+	}{
+		{
+			name: "configured server",
+			req:  &ipc.Request{Type: "resolve_call", Server: "github", Tool: "search", Args: json.RawMessage(`{"query":"mcp"}`)},
+			want: resolvedCall{
+				Server:   "github",
+				Backend:  "github",
+				Tool:     "search_repositories",
+				Args:     json.RawMessage(`{"per_page":10,"query":"mcp"}`),
+				CacheTTL: "45s",
+			},
+		},
+		{
+			name: "virtual server",
+			req:  &ipc.Request{Type: "resolve_call", Server: "linear", Tool: "linear_get_profile"},
+			want: resolvedCall{
+				Server:  "linear",
+				Backend: codexAppsServerName,
+				Tool:    "linear_get_profile",
+				Args:    json.RawMessage(`{}`),
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resp := dispatchWithDeps(context.Background(), cfg, pool, ka, tt.req, deps)
+			if resp.ExitCode != ipc.ExitOK {
+				t.Fatalf("resolve_call exit = %d, want %d (stderr=%q)", resp.ExitCode, ipc.ExitOK, resp.Stderr)
+			}
+			var got resolvedCall
+			if err := json.Unmarshal(resp.Content, &got); err != nil {
+				t.Fatalf("unmarshal resolve_call payload: %v; payload=%q", err, resp.Content)
+			}
+			var gotArgs, wantArgs any
+			_ = json.Unmarshal(got.Args, &gotArgs)
+			_ = json.Unmarshal(tt.want.Args, &wantArgs)
+			if !reflect.DeepEqual(gotArgs, wantArgs) {
+				t.Fatalf("args = %s, want %s", got.Args, tt.want.Args)
+			}
+			got.Args, tt.want.Args = nil, nil
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Fatalf("resolved call = %#v, want %#v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,208 @@
+package daemon
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/lydakis/mcpx/internal/config"
+	"github.com/lydakis/mcpx/internal/ipc"
+	"github.com/lydakis/mcpx/internal/mcppool"
+	"github.com/lydakis/mcpx/internal/servercatalog"
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+type resourceListEntry struct {
+	URI         string `json:"uri"`
+	Name        string `json:"name,omitempty"`
+	Description string `json:"description,omitempty"`
+	MIMEType    string `json:"mime_type,omitempty"`
+}
+
+type promptListEntry struct {
+	Name        string                `json:"name"`
+	Description string                `json:"description,omitempty"`
+	Arguments   []promptArgumentEntry `json:"arguments,omitempty"`
+}
+
+type promptArgumentEntry struct {
+	Name        string `json:"name"`
+	Description string `json:"description,omitempty"`
+	Required    bool   `json:"required,omitempty"`
+}
+
+func listResourcesWithDeps(ctx context.Context, cfg *config.Config, pool *mcppool.Pool, ka *Keepalive, server string, verbose bool, deps runtimeDeps) *ipc.Response {
+	deps = deps.withDefaults()
+	route, resp := resolveConcreteServerRoute(ctx, cfg, pool, ka, server, "resources", deps)
+	if resp != nil {
+		return resp
+	}
+
+	if ka != nil {
+		ka.Begin(route.Backend)
+		defer ka.End(route.Backend)
+	}
+	resources, err := deps.poolListResources(ctx, pool, route.Backend)
+	if err != nil {
+		return &ipc.Response{ExitCode: ipc.ExitInternal, Stderr: fmt.Sprintf("listing resources: %v", err)}
+	}
+
+	seen := make(map[string]struct{}, len(resources))
+	entries := make([]resourceListEntry, 0, len(resources))
+	for _, r := range resources {
+		uri := strings.TrimSpace(r.URI)
+		if uri == "" {
+			continue
+		}
+		if _, exists := seen[uri]; exists {
+			continue
+		}
+		seen[uri] = struct{}{}
+		desc := strings.TrimSpace(r.Description)
+		if !verbose {
+			desc = summarizeToolDescription(desc)
+		}
+		entries = append(entries, resourceListEntry{
+			URI:         uri,
+			Name:        strings.TrimSpace(r.Name),
+			Description: desc,
+			MIMEType:    strings.TrimSpace(r.MIMEType),
+		})
+	}
+	sort.Slice(entries, func(i, j int) bool {
+		return entries[i].URI < entries[j].URI
+	})
+
+	data, err := json.Marshal(entries)
+	if err != nil {
+		return &ipc.Response{ExitCode: ipc.ExitInternal, Stderr: fmt.Sprintf("encoding resource list: %v", err)}
+	}
+	data = append(data, '\n')
+	return &ipc.Response{Content: data}
+}
+
+func listPromptsWithDeps(ctx context.Context, cfg *config.Config, pool *mcppool.Pool, ka *Keepalive, server string, verbose bool, deps runtimeDeps) *ipc.Response {
+	deps = deps.withDefaults()
+	route, resp := resolveConcreteServerRoute(ctx, cfg, pool, ka, server, "prompts", deps)
+	if resp != nil {
+		return resp
+	}
+
+	if ka != nil {
+		ka.Begin(route.Backend)
+		defer ka.End(route.Backend)
+	}
+	prompts, err := deps.poolListPrompts(ctx, pool, route.Backend)
+	if err != nil {
+		return &ipc.Response{ExitCode: ipc.ExitInternal, Stderr: fmt.Sprintf("listing prompts: %v", err)}
+	}
+
+	seen := make(map[string]struct{}, len(prompts))
+	entries := make([]promptListEntry, 0, len(prompts))
+	for _, p := range prompts {
+		name := strings.TrimSpace(p.Name)
+		if name == "" {
+			continue
+		}
+		if _, exists := seen[name]; exists {
+			continue
+		}
+		seen[name] = struct{}{}
+		desc := strings.TrimSpace(p.Description)
+		if !verbose {
+			desc = summarizeToolDescription(desc)
+		}
+		var args []promptArgumentEntry
+		for _, arg := range p.Arguments {
+			argName := strings.TrimSpace(arg.Name)
+			if argName == "" {
+				continue
+			}
+			args = append(args, promptArgumentEntry{
+				Name:        argName,
+				Description: strings.TrimSpace(arg.Description),
+				Required:    arg.Required,
+			})
+		}
+		entries = append(entries, promptListEntry{
+			Name:        name,
+			Description: desc,
+			Arguments:   args,
+		})
+	}
+	sort.Slice(entries, func(i, j int) bool {
+		return entries[i].Name < entries[j].Name
+	})
+
+	data, err := json.Marshal(entries)
+	if err != nil {
+		return &ipc.Response{ExitCode: ipc.ExitInternal, Stderr: fmt.Sprintf("encoding prompt list: %v", err)}
+	}
+	data = append(data, '\n')
+	return &ipc.Response{Content: data}
+}
+
+type promptResultPayload struct {
+	Description string              `json:"description,omitempty"`
+	Messages    []mcp.PromptMessage `json:"messages"`
+}
+
+func getPromptWithDeps(ctx context.Context, cfg *config.Config, pool *mcppool.Pool, ka *Keepalive, server, prompt string, args json.RawMessage, deps runtimeDeps) *ipc.Response {
+	deps = deps.withDefaults()
+	prompt = strings.TrimSpace(prompt)
+	if prompt == "" {
+		return &ipc.Response{ExitCode: ipc.ExitUsageErr, Stderr: "prompt name is required"}
+	}
+	route, resp := resolveConcreteServerRoute(ctx, cfg, pool, ka, server, "prompts", deps)
+	if resp != nil {
+		return resp
+	}
+
+	if ka != nil {
+		ka.Begin(route.Backend)
+		defer ka.End(route.Backend)
+	}
+	result, err := deps.poolGetPrompt(ctx, pool, route.Backend, prompt, args)
+	if err != nil {
+		return &ipc.Response{
+			ExitCode: classifyCallToolError(err),
+			Stderr:   fmt.Sprintf("getting prompt: %v", err),
+		}
+	}
+
+	payload := promptResultPayload{Messages: []mcp.PromptMessage{}}
+	if result != nil {
+		payload.Description = result.Description
+		if result.Messages != nil {
+			payload.Messages = result.Messages
+		}
+	}
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return &ipc.Response{ExitCode: ipc.ExitInternal, Stderr: fmt.Sprintf("encoding prompt: %v", err)}
+	}
+	data = append(data, '\n')
+	return &ipc.Response{Content: data}
+}
+
+// resolveConcreteServerRoute resolves a server for capabilities that are not
+// partitioned across codex apps virtual servers (resources and prompts).
+func resolveConcreteServerRoute(ctx context.Context, cfg *config.Config, pool *mcppool.Pool, ka *Keepalive, server, capability string, deps runtimeDeps) (servercatalog.Route, *ipc.Response) {
+	catalog := newServerCatalogWithDeps(cfg, pool, ka, deps)
+	route, _, found, err := catalog.Resolve(ctx, server)
+	if err != nil {
+		return servercatalog.Route{}, &ipc.Response{ExitCode: ipc.ExitInternal, Stderr: fmt.Sprintf("resolving server: %v", err)}
+	}
+	if !found {
+		return servercatalog.Route{}, unknownServerResponse(server)
+	}
+	if route.IsVirtual() {
+		return servercatalog.Route{}, &ipc.Response{
+			ExitCode: ipc.ExitUsageErr,
+			Stderr:   fmt.Sprintf("server %s does not support %s listing; use %s", server, capability, route.Backend),
+		}
+	}
+	return route, nil
+}
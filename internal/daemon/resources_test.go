@@ -0,0 +1,206 @@
+package daemon
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/lydakis/mcpx/internal/config"
+	"github.com/lydakis/mcpx/internal/ipc"
+	"github.com/lydakis/mcpx/internal/mcppool"
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+func TestListResourcesOutputsSortedEntriesWithShortDescriptions(t *testing.T) {
+	cfg := &config.Config{
+		Servers: map[string]config.ServerConfig{
+			"docs": {},
+		},
+	}
+	ka := NewKeepalive(nil)
+	defer ka.Stop()
+
+	deps := runtimeDefaultDeps()
+	deps.poolListResources = func(_ context.Context, _ *mcppool.Pool, server string) ([]mcppool.ResourceInfo, error) {
+		if server != "docs" {
+			t.Fatalf("poolListResources server = %q, want %q", server, "docs")
+		}
+		return []mcppool.ResourceInfo{
+			{URI: "file:///b.md", Name: "b", Description: "Second\nmore details", MIMEType: "text/markdown"},
+			{URI: "file:///a.md", Name: "a"},
+			{URI: "file:///a.md", Name: "duplicate"},
+			{URI: " ", Name: "blank"},
+		}, nil
+	}
+
+	resp := dispatchWithDeps(context.Background(), cfg, nil, ka, &ipc.Request{Type: "list_resources", Server: "docs"}, deps)
+	if resp.ExitCode != ipc.ExitOK {
+		t.Fatalf("list_resources exit = %d, want 0 (stderr=%q)", resp.ExitCode, resp.Stderr)
+	}
+
+	var got []resourceListEntry
+	if err := json.Unmarshal(resp.Content, &got); err != nil {
+		t.Fatalf("unmarshal resource list: %v; payload=%q", err, string(resp.Content))
+	}
+	want := []resourceListEntry{
+		{URI: "file:///a.md", Name: "a"},
+		{URI: "file:///b.md", Name: "b", Description: "Second", MIMEType: "text/markdown"},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("resource list = %#v, want %#v", got, want)
+	}
+}
+
+func TestListPromptsIncludesArguments(t *testing.T) {
+	cfg := &config.Config{
+		Servers: map[string]config.ServerConfig{
+			"docs": {},
+		},
+	}
+	ka := NewKeepalive(nil)
+	defer ka.Stop()
+
+	deps := runtimeDefaultDeps()
+	deps.poolListPrompts = func(_ context.Context, _ *mcppool.Pool, _ string) ([]mcppool.PromptInfo, error) {
+		return []mcppool.PromptInfo{
+			{
+				Name:        "summarize",
+				Description: "Summarize text\nwith details",
+				Arguments: []mcppool.PromptArgumentInfo{
+					{Name: "text", Required: true},
+				},
+			},
+			{Name: "greet"},
+		}, nil
+	}
+
+	resp := dispatchWithDeps(context.Background(), cfg, nil, ka, &ipc.Request{Type: "list_prompts", Server: "docs", Verbose: true}, deps)
+	if resp.ExitCode != ipc.ExitOK {
+		t.Fatalf("list_prompts exit = %d, want 0 (stderr=%q)", resp.ExitCode, resp.Stderr)
+	}
+
+	var got []promptListEntry
+	if err := json.Unmarshal(resp.Content, &got); err != nil {
+		t.Fatalf("unmarshal prompt list: %v; payload=%q", err, string(resp.Content))
+	}
+	want := []promptListEntry{
+		{Name: "greet"},
+		{
+			Name:        "summarize",
+			Description: "Summarize text\nwith details",
+			Arguments:   []promptArgumentEntry{{Name: "text", Required: true}},
+		},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("prompt list = %#v, want %#v", got, want)
+	}
+}
+
+func TestListPromptsUnknownServerReturnsStructuredError(t *testing.T) {
+	cfg := &config.Config{Servers: map[string]config.ServerConfig{}}
+
+	resp := dispatchWithDeps(context.Background(), cfg, nil, nil, &ipc.Request{Type: "list_prompts", Server: "missing"}, runtimeDefaultDeps())
+	if resp.ExitCode != ipc.ExitUsageErr {
+		t.Fatalf("list_prompts exit = %d, want %d", resp.ExitCode, ipc.ExitUsageErr)
+	}
+	if resp.ErrorCode != ipc.ErrorCodeUnknownServer {
+		t.Fatalf("list_prompts error code = %q, want %q", resp.ErrorCode, ipc.ErrorCodeUnknownServer)
+	}
+}
+
+func TestListResourcesPoolErrorReturnsInternal(t *testing.T) {
+	cfg := &config.Config{
+		Servers: map[string]config.ServerConfig{
+			"docs": {},
+		},
+	}
+
+	deps := runtimeDefaultDeps()
+	deps.poolListResources = func(context.Context, *mcppool.Pool, string) ([]mcppool.ResourceInfo, error) {
+		return nil, errors.New("method not found")
+	}
+
+	resp := dispatchWithDeps(context.Background(), cfg, nil, nil, &ipc.Request{Type: "list_resources", Server: "docs"}, deps)
+	if resp.ExitCode != ipc.ExitInternal {
+		t.Fatalf("list_resources exit = %d, want %d", resp.ExitCode, ipc.ExitInternal)
+	}
+	if resp.Stderr != "listing resources: method not found" {
+		t.Fatalf("list_resources stderr = %q", resp.Stderr)
+	}
+}
+
+func TestGetPromptReturnsMessagesPayload(t *testing.T) {
+	cfg := &config.Config{
+		Servers: map[string]config.ServerConfig{
+			"docs": {},
+		},
+	}
+	ka := NewKeepalive(nil)
+	defer ka.Stop()
+
+	deps := runtimeDefaultDeps()
+	deps.poolGetPrompt = func(_ context.Context, _ *mcppool.Pool, server, name string, args json.RawMessage) (*mcp.GetPromptResult, error) {
+		if server != "docs" || name != "summarize" {
+			t.Fatalf("poolGetPrompt(%q, %q), want docs/summarize", server, name)
+		}
+		if string(args) != `{"text":"hi"}` {
+			t.Fatalf("poolGetPrompt args = %s, want text arg", args)
+		}
+		return &mcp.GetPromptResult{
+			Description: "Summary prompt",
+			Messages: []mcp.PromptMessage{
+				{Role: mcp.RoleUser, Content: mcp.NewTextContent("Summarize: hi")},
+			},
+		}, nil
+	}
+
+	resp := dispatchWithDeps(context.Background(), cfg, nil, ka, &ipc.Request{
+		Type:   "get_prompt",
+		Server: "docs",
+		Prompt: "summarize",
+		Args:   json.RawMessage(`{"text":"hi"}`),
+	}, deps)
+	if resp.ExitCode != ipc.ExitOK {
+		t.Fatalf("get_prompt exit = %d, want 0 (stderr=%q)", resp.ExitCode, resp.Stderr)
+	}
+
+	var got struct {
+		Description string `json:"description"`
+		Messages    []struct {
+			Role    string `json:"role"`
+			Content struct {
+				Type string `json:"type"`
+				Text string `json:"text"`
+			} `json:"content"`
+		} `json:"messages"`
+	}
+	if err := json.Unmarshal(resp.Content, &got); err != nil {
+		t.Fatalf("unmarshal prompt payload: %v; payload=%q", err, string(resp.Content))
+	}
+	if got.Description != "Summary prompt" || len(got.Messages) != 1 {
+		t.Fatalf("prompt payload = %+v, want description and one message", got)
+	}
+	if got.Messages[0].Role != "user" || got.Messages[0].Content.Type != "text" || got.Messages[0].Content.Text != "Summarize: hi" {
+		t.Fatalf("prompt message = %+v, want user text message", got.Messages[0])
+	}
+}
+
+func TestGetPromptInvalidParamsReturnsUsageError(t *testing.T) {
+	cfg := &config.Config{
+		Servers: map[string]config.ServerConfig{
+			"docs": {},
+		},
+	}
+
+	deps := runtimeDefaultDeps()
+	deps.poolGetPrompt = func(context.Context, *mcppool.Pool, string, string, json.RawMessage) (*mcp.GetPromptResult, error) {
+		return nil, errors.New("request failed: -32602 invalid params: missing argument text")
+	}
+
+	resp := dispatchWithDeps(context.Background(), cfg, nil, nil, &ipc.Request{Type: "get_prompt", Server: "docs", Prompt: "summarize"}, deps)
+	if resp.ExitCode != ipc.ExitUsageErr {
+		t.Fatalf("get_prompt exit = %d, want %d", resp.ExitCode, ipc.ExitUsageErr)
+	}
+}
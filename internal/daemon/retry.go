@@ -0,0 +1,165 @@
+package daemon
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/lydakis/mcpx/internal/config"
+	"github.com/lydakis/mcpx/internal/ipc"
+	"github.com/lydakis/mcpx/internal/mcppool"
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+const (
+	retryBudgetInitialBackoff = 200 * time.Millisecond
+	retryBudgetMaxBackoff     = 5 * time.Second
+)
+
+// retryBudgetNow is the clock the retry budget is measured on; tests
+// replace it.
+var retryBudgetNow = time.Now
+
+// isTransientCallError reports whether a failed tools/call is worth
+// retrying: transport and server failures are, while usage errors and a
+// canceled or expired caller context are not.
+func isTransientCallError(ctx context.Context, err error) bool {
+	if ctx.Err() != nil || errors.Is(err, context.Canceled) {
+		return false
+	}
+	if _, limited := mcppool.RetryAfter(err); limited {
+		return true
+	}
+	return classifyCallToolError(err) == ipc.ExitInternal
+}
+
+// retryAfterSleep waits d or until ctx is done; tests replace it.
+var retryAfterSleep = func(ctx context.Context, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+type retryRequestCtx struct{}
+
+// retryPolicy is every retry setting that applies to one tools/call:
+// the request's --max-retries-respect-retry-after and --retry-budget and
+// the server's retry_max and retry_backoff. withRetries runs them as one
+// loop so they share a single attempt counter and deadline.
+type retryPolicy struct {
+	// rateLimited retries a 429 with Retry-After up to this many times.
+	rateLimited int
+	// budget retries transient failures until this long after the first
+	// attempt, and caps every other kind of retry too.
+	budget time.Duration
+	// max retries transient failures this many times; with a budget,
+	// whichever runs out first ends the retries.
+	max     int
+	backoff time.Duration
+	// logf receives one line per retry.
+	logf func(string)
+}
+
+// withRetryRequest carries a call_tool request's retry flags to
+// callToolWithDeps, which combines them with the server's retry_max.
+func withRetryRequest(ctx context.Context, rateLimited int, budget *time.Duration) context.Context {
+	policy := retryPolicy{rateLimited: rateLimited}
+	if budget != nil {
+		policy.budget = *budget
+	}
+	return context.WithValue(ctx, retryRequestCtx{}, policy)
+}
+
+// retryPolicyFor is the request's retry flags plus scfg's retry settings.
+func retryPolicyFor(ctx context.Context, scfg config.ServerConfig, logf func(string)) retryPolicy {
+	policy, _ := ctx.Value(retryRequestCtx{}).(retryPolicy)
+	policy.max = scfg.RetryMax
+	policy.backoff = serverRetryBackoff(scfg)
+	policy.logf = logf
+	return policy
+}
+
+// serverRetryBackoff is the server's retry_backoff, else the retry budget's
+// initial backoff. It is validated when config loads.
+func serverRetryBackoff(scfg config.ServerConfig) time.Duration {
+	if backoff, err := time.ParseDuration(scfg.RetryBackoff); err == nil && backoff > 0 {
+		return backoff
+	}
+	return retryBudgetInitialBackoff
+}
+
+// withRetries wraps the pool call so failures are retried as policy allows.
+// A transient failure is retried while fewer than max retries have run, or,
+// without max, until the budget is spent; a 429 with Retry-After is also
+// retried while fewer than rateLimited retries have run. Waits double from
+// backoff up to the retry budget's cap (or backoff itself, when larger), or
+// follow a 429's Retry-After, and no retry starts whose wait would overrun
+// the budget. The pool evicts a connection whose call failed, so each retry
+// runs on a fresh one. It wraps outside withCallTimeouts, so waits count
+// against the total timeout but not against any single attempt.
+func withRetries(policy retryPolicy, deps runtimeDeps) runtimeDeps {
+	if policy.rateLimited <= 0 && policy.budget <= 0 && policy.max <= 0 {
+		return deps
+	}
+	if policy.backoff <= 0 {
+		policy.backoff = retryBudgetInitialBackoff
+	}
+
+	// A retry_backoff above the default cap is kept as its own cap, so
+	// doubling never shortens the configured wait.
+	maxBackoff := max(policy.backoff, retryBudgetMaxBackoff)
+
+	call := deps.poolCallToolWithInfo
+	deps.poolCallToolWithInfo = func(ctx context.Context, pool *mcppool.Pool, server string, info *mcppool.ToolInfo, args json.RawMessage) (*mcp.CallToolResult, error) {
+		var deadline time.Time
+		if policy.budget > 0 {
+			deadline = retryBudgetNow().Add(policy.budget)
+		}
+		backoff := policy.backoff
+		for retry := 0; ; retry++ {
+			result, err := call(ctx, pool, server, info, args)
+			if err == nil {
+				return result, nil
+			}
+			if !isTransientCallError(ctx, err) {
+				return nil, err
+			}
+
+			wait, limited := mcppool.RetryAfter(err)
+			allowed := (limited && retry < policy.rateLimited) ||
+				(policy.max > 0 && retry < policy.max) ||
+				(policy.max <= 0 && policy.budget > 0)
+			if !allowed {
+				return nil, err
+			}
+			if !limited {
+				wait = backoff
+				backoff = min(backoff*2, maxBackoff)
+			}
+			if policy.budget > 0 && retryBudgetNow().Add(wait).After(deadline) {
+				return nil, fmt.Errorf("retry budget %s spent after %d attempt(s): %w", policy.budget, retry+1, err)
+			}
+			if ctxDeadline, ok := ctx.Deadline(); ok && limited && time.Until(ctxDeadline) < wait {
+				return nil, fmt.Errorf("server asked to retry after %s, beyond the remaining timeout: %w", wait, err)
+			}
+			if policy.logf != nil {
+				if policy.max > 0 {
+					policy.logf(fmt.Sprintf("mcpx: retrying %s (retry %d/%d) after %s: %v", info.Name, retry+1, policy.max, wait, err))
+				} else {
+					policy.logf(fmt.Sprintf("mcpx: retrying %s (retry %d) after %s: %v", info.Name, retry+1, wait, err))
+				}
+			}
+			if sleepErr := retryAfterSleep(ctx, wait); sleepErr != nil {
+				return nil, err
+			}
+		}
+	}
+	return deps
+}
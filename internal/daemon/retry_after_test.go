@@ -0,0 +1,84 @@
+package daemon
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/lydakis/mcpx/internal/config"
+	"github.com/lydakis/mcpx/internal/ipc"
+	"github.com/lydakis/mcpx/internal/mcppool"
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+func TestDispatchCallToolWaitsForRetryAfterBeforeRetrying(t *testing.T) {
+	cfg := &config.Config{Servers: map[string]config.ServerConfig{"github": {}}}
+	ka := NewKeepalive(nil)
+	defer ka.Stop()
+
+	var waits []time.Duration
+	oldSleep := retryAfterSleep
+	retryAfterSleep = func(_ context.Context, d time.Duration) error {
+		waits = append(waits, d)
+		return nil
+	}
+	defer func() { retryAfterSleep = oldSleep }()
+
+	attempts := 0
+	deps := runtimeDefaultDeps()
+	deps.poolCallToolWithInfo = func(context.Context, *mcppool.Pool, string, *mcppool.ToolInfo, json.RawMessage) (*mcp.CallToolResult, error) {
+		attempts++
+		if attempts == 1 {
+			return nil, &mcppool.RetryAfterError{Wait: 7 * time.Second, Err: errors.New("request failed with status 429")}
+		}
+		return &mcp.CallToolResult{Content: []mcp.Content{mcp.TextContent{Type: "text", Text: "ok"}}}, nil
+	}
+
+	resp := dispatchWithDeps(context.Background(), cfg, nil, ka, &ipc.Request{
+		Type:              "call_tool",
+		Server:            "github",
+		Tool:              "search",
+		RetryAfterRetries: 2,
+	}, deps)
+	if resp.ExitCode != ipc.ExitOK {
+		t.Fatalf("dispatch exit = %d, want %d (stderr=%q)", resp.ExitCode, ipc.ExitOK, resp.Stderr)
+	}
+	if attempts != 2 {
+		t.Fatalf("attempts = %d, want 2", attempts)
+	}
+	if len(waits) != 1 || waits[0] != 7*time.Second {
+		t.Fatalf("waits = %v, want [7s]", waits)
+	}
+}
+
+func TestDispatchCallToolStopsAfterRetryAfterRetriesAreSpent(t *testing.T) {
+	cfg := &config.Config{Servers: map[string]config.ServerConfig{"github": {}}}
+	ka := NewKeepalive(nil)
+	defer ka.Stop()
+
+	oldSleep := retryAfterSleep
+	retryAfterSleep = func(context.Context, time.Duration) error { return nil }
+	defer func() { retryAfterSleep = oldSleep }()
+
+	attempts := 0
+	deps := runtimeDefaultDeps()
+	deps.poolCallToolWithInfo = func(context.Context, *mcppool.Pool, string, *mcppool.ToolInfo, json.RawMessage) (*mcp.CallToolResult, error) {
+		attempts++
+		return nil, &mcppool.RetryAfterError{Wait: time.Second, Err: errors.New("request failed with status 429")}
+	}
+
+	resp := dispatchWithDeps(context.Background(), cfg, nil, ka, &ipc.Request{
+		Type:              "call_tool",
+		Server:            "github",
+		Tool:              "search",
+		RetryAfterRetries: 2,
+	}, deps)
+	if resp.ExitCode == ipc.ExitOK {
+		t.Fatal("dispatch exit = 0, want failure")
+	}
+	if attempts != 3 {
+		t.Fatalf("attempts = %d, want 3", attempts)
+	}
+}
@@ -0,0 +1,131 @@
+package daemon
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/lydakis/mcpx/internal/config"
+	"github.com/lydakis/mcpx/internal/ipc"
+	"github.com/lydakis/mcpx/internal/mcppool"
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+// stubRetryBudgetClock replaces the sleep and clock used by retries with a
+// fake clock that advances by each wait, and returns the recorded waits.
+func stubRetryBudgetClock(t *testing.T) *[]time.Duration {
+	t.Helper()
+	now := time.Unix(1_700_000_000, 0)
+	var waits []time.Duration
+	oldSleep, oldNow := retryAfterSleep, retryBudgetNow
+	retryAfterSleep = func(_ context.Context, d time.Duration) error {
+		waits = append(waits, d)
+		now = now.Add(d)
+		return nil
+	}
+	retryBudgetNow = func() time.Time { return now }
+	t.Cleanup(func() { retryAfterSleep, retryBudgetNow = oldSleep, oldNow })
+	return &waits
+}
+
+func dispatchWithRetryBudget(t *testing.T, budget time.Duration, call func(int) (*mcp.CallToolResult, error)) (*ipc.Response, int) {
+	t.Helper()
+	cfg := &config.Config{Servers: map[string]config.ServerConfig{"github": {}}}
+	ka := NewKeepalive(nil)
+	defer ka.Stop()
+
+	attempts := 0
+	deps := runtimeDefaultDeps()
+	deps.poolCallToolWithInfo = func(context.Context, *mcppool.Pool, string, *mcppool.ToolInfo, json.RawMessage) (*mcp.CallToolResult, error) {
+		attempts++
+		return call(attempts)
+	}
+	resp := dispatchWithDeps(context.Background(), cfg, nil, ka, &ipc.Request{
+		Type:        "call_tool",
+		Server:      "github",
+		Tool:        "search",
+		RetryBudget: &budget,
+	}, deps)
+	return resp, attempts
+}
+
+func TestDispatchCallToolRetryBudgetStopsOnFirstSuccess(t *testing.T) {
+	waits := stubRetryBudgetClock(t)
+
+	resp, attempts := dispatchWithRetryBudget(t, 30*time.Second, func(attempt int) (*mcp.CallToolResult, error) {
+		if attempt < 3 {
+			return nil, errors.New("transport closed")
+		}
+		return &mcp.CallToolResult{Content: []mcp.Content{mcp.TextContent{Type: "text", Text: "ok"}}}, nil
+	})
+	if resp.ExitCode != ipc.ExitOK {
+		t.Fatalf("dispatch exit = %d, want %d (stderr=%q)", resp.ExitCode, ipc.ExitOK, resp.Stderr)
+	}
+	if attempts != 3 {
+		t.Fatalf("attempts = %d, want 3", attempts)
+	}
+	if want := []time.Duration{200 * time.Millisecond, 400 * time.Millisecond}; len(*waits) != 2 || (*waits)[0] != want[0] || (*waits)[1] != want[1] {
+		t.Fatalf("waits = %v, want %v", *waits, want)
+	}
+}
+
+func TestDispatchCallToolRetryBudgetFailsOnceBudgetIsSpent(t *testing.T) {
+	waits := stubRetryBudgetClock(t)
+
+	resp, attempts := dispatchWithRetryBudget(t, 20*time.Second, func(int) (*mcp.CallToolResult, error) {
+		return nil, errors.New("transport closed")
+	})
+	if resp.ExitCode != ipc.ExitInternal {
+		t.Fatalf("dispatch exit = %d, want %d", resp.ExitCode, ipc.ExitInternal)
+	}
+	if !strings.Contains(resp.Stderr, "retry budget 20s spent") {
+		t.Fatalf("stderr = %q, want retry budget message", resp.Stderr)
+	}
+	var total time.Duration
+	for _, wait := range *waits {
+		total += wait
+	}
+	if total > 20*time.Second {
+		t.Fatalf("waited %s in total, want at most the 20s budget", total)
+	}
+	if attempts != len(*waits)+1 {
+		t.Fatalf("attempts = %d, want one more than the %d waits", attempts, len(*waits))
+	}
+	if (*waits)[len(*waits)-1] != 5*time.Second {
+		t.Fatalf("waits = %v, want backoff capped at 5s", *waits)
+	}
+}
+
+func TestDispatchCallToolRetryBudgetDoesNotRetryUsageErrors(t *testing.T) {
+	waits := stubRetryBudgetClock(t)
+
+	resp, attempts := dispatchWithRetryBudget(t, 30*time.Second, func(int) (*mcp.CallToolResult, error) {
+		return nil, mcp.ErrInvalidParams
+	})
+	if resp.ExitCode != ipc.ExitUsageErr {
+		t.Fatalf("dispatch exit = %d, want %d", resp.ExitCode, ipc.ExitUsageErr)
+	}
+	if attempts != 1 || len(*waits) != 0 {
+		t.Fatalf("attempts = %d, waits = %v; want a single attempt", attempts, *waits)
+	}
+}
+
+func TestDispatchCallToolRetryBudgetUsesRetryAfterWait(t *testing.T) {
+	waits := stubRetryBudgetClock(t)
+
+	resp, _ := dispatchWithRetryBudget(t, 30*time.Second, func(attempt int) (*mcp.CallToolResult, error) {
+		if attempt == 1 {
+			return nil, &mcppool.RetryAfterError{Wait: 7 * time.Second, Err: errors.New("request failed with status 429")}
+		}
+		return &mcp.CallToolResult{Content: []mcp.Content{mcp.TextContent{Type: "text", Text: "ok"}}}, nil
+	})
+	if resp.ExitCode != ipc.ExitOK {
+		t.Fatalf("dispatch exit = %d, want %d (stderr=%q)", resp.ExitCode, ipc.ExitOK, resp.Stderr)
+	}
+	if len(*waits) != 1 || (*waits)[0] != 7*time.Second {
+		t.Fatalf("waits = %v, want [7s]", *waits)
+	}
+}
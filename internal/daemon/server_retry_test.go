@@ -0,0 +1,134 @@
+package daemon
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/lydakis/mcpx/internal/config"
+	"github.com/lydakis/mcpx/internal/ipc"
+	"github.com/lydakis/mcpx/internal/mcppool"
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+func dispatchWithServerRetries(t *testing.T, scfg config.ServerConfig, call func(int) (*mcp.CallToolResult, error)) (*ipc.Response, int) {
+	t.Helper()
+	cfg := &config.Config{Servers: map[string]config.ServerConfig{"github": scfg}}
+	ka := NewKeepalive(nil)
+	defer ka.Stop()
+
+	attempts := 0
+	deps := runtimeDefaultDeps()
+	deps.poolCallToolWithInfo = func(context.Context, *mcppool.Pool, string, *mcppool.ToolInfo, json.RawMessage) (*mcp.CallToolResult, error) {
+		attempts++
+		return call(attempts)
+	}
+	resp := dispatchWithDeps(context.Background(), cfg, nil, ka, &ipc.Request{
+		Type:    "call_tool",
+		Server:  "github",
+		Tool:    "search",
+		Verbose: true,
+	}, deps)
+	return resp, attempts
+}
+
+func TestDispatchCallToolRetriesTransientErrorsWithBackoff(t *testing.T) {
+	waits := stubRetryBudgetClock(t)
+
+	resp, attempts := dispatchWithServerRetries(t, config.ServerConfig{RetryMax: 3, RetryBackoff: "100ms"}, func(attempt int) (*mcp.CallToolResult, error) {
+		if attempt < 3 {
+			return nil, errors.New("transport closed")
+		}
+		return &mcp.CallToolResult{Content: []mcp.Content{mcp.TextContent{Type: "text", Text: "ok"}}}, nil
+	})
+	if resp.ExitCode != ipc.ExitOK || string(resp.Content) != "ok\n" {
+		t.Fatalf("dispatch = (%d, %q), want ok (stderr=%q)", resp.ExitCode, resp.Content, resp.Stderr)
+	}
+	if attempts != 3 {
+		t.Fatalf("attempts = %d, want 3", attempts)
+	}
+	if want := []time.Duration{100 * time.Millisecond, 200 * time.Millisecond}; !reflect.DeepEqual(*waits, want) {
+		t.Fatalf("waits = %v, want %v", *waits, want)
+	}
+	if !strings.Contains(resp.Stderr, "mcpx: retrying search (retry 2/3) after 200ms: transport closed") {
+		t.Fatalf("stderr = %q, want verbose retry log", resp.Stderr)
+	}
+}
+
+func TestDispatchCallToolKeepsRetryBackoffAboveDefaultCap(t *testing.T) {
+	waits := stubRetryBudgetClock(t)
+
+	_, attempts := dispatchWithServerRetries(t, config.ServerConfig{RetryMax: 3, RetryBackoff: "10s"}, func(int) (*mcp.CallToolResult, error) {
+		return nil, errors.New("transport closed")
+	})
+	if attempts != 4 {
+		t.Fatalf("attempts = %d, want 4", attempts)
+	}
+	if want := []time.Duration{10 * time.Second, 10 * time.Second, 10 * time.Second}; !reflect.DeepEqual(*waits, want) {
+		t.Fatalf("waits = %v, want %v (never below retry_backoff)", *waits, want)
+	}
+}
+
+func TestDispatchCallToolStopsAfterRetryMax(t *testing.T) {
+	stubRetryBudgetClock(t)
+
+	resp, attempts := dispatchWithServerRetries(t, config.ServerConfig{RetryMax: 2}, func(int) (*mcp.CallToolResult, error) {
+		return nil, errors.New("transport closed")
+	})
+	if resp.ExitCode != ipc.ExitInternal {
+		t.Fatalf("dispatch exit = %d, want %d", resp.ExitCode, ipc.ExitInternal)
+	}
+	if attempts != 3 {
+		t.Fatalf("attempts = %d, want 3 (one call and two retries)", attempts)
+	}
+	if !strings.HasSuffix(resp.Stderr, "calling tool: transport closed") {
+		t.Fatalf("stderr = %q, want final call error", resp.Stderr)
+	}
+}
+
+func TestDispatchCallToolDoesNotRetryInvalidParams(t *testing.T) {
+	waits := stubRetryBudgetClock(t)
+
+	resp, attempts := dispatchWithServerRetries(t, config.ServerConfig{RetryMax: 3}, func(int) (*mcp.CallToolResult, error) {
+		return nil, fmt.Errorf("%w: missing query", mcp.ErrInvalidParams)
+	})
+	if resp.ExitCode != ipc.ExitUsageErr {
+		t.Fatalf("dispatch exit = %d, want %d", resp.ExitCode, ipc.ExitUsageErr)
+	}
+	if attempts != 1 || len(*waits) != 0 {
+		t.Fatalf("attempts = %d, waits = %v; want a single attempt", attempts, *waits)
+	}
+}
+
+func TestDispatchCallToolRetryMaxAndBudgetShareOneAttemptCounter(t *testing.T) {
+	stubRetryBudgetClock(t)
+	cfg := &config.Config{Servers: map[string]config.ServerConfig{"github": {RetryMax: 3}}}
+	ka := NewKeepalive(nil)
+	defer ka.Stop()
+
+	attempts := 0
+	deps := runtimeDefaultDeps()
+	deps.poolCallToolWithInfo = func(context.Context, *mcppool.Pool, string, *mcppool.ToolInfo, json.RawMessage) (*mcp.CallToolResult, error) {
+		attempts++
+		return nil, &mcppool.RetryAfterError{Wait: time.Second, Err: errors.New("request failed with status 429")}
+	}
+	budget := 30 * time.Second
+	resp := dispatchWithDeps(context.Background(), cfg, nil, ka, &ipc.Request{
+		Type:              "call_tool",
+		Server:            "github",
+		Tool:              "search",
+		RetryBudget:       &budget,
+		RetryAfterRetries: 2,
+	}, deps)
+	if resp.ExitCode != ipc.ExitInternal {
+		t.Fatalf("dispatch exit = %d, want %d", resp.ExitCode, ipc.ExitInternal)
+	}
+	if attempts != 4 {
+		t.Fatalf("attempts = %d, want 4 (one call and retry_max retries)", attempts)
+	}
+}
@@ -3,6 +3,7 @@ package daemon
 import (
 	"crypto/rand"
 	"encoding/hex"
+	"errors"
 	"fmt"
 	"net"
 	"os"
@@ -65,6 +66,26 @@ func SpawnOrConnect() (string, error) {
 	return waitForDaemonFn()
 }
 
+// ErrNotRunning is returned by Connect when no daemon is listening.
+var ErrNotRunning = errors.New("daemon not running")
+
+// Connect returns the nonce of an already running daemon without spawning
+// one, for commands that only inspect the daemon.
+func Connect() (string, error) {
+	nonce, err := readNonceFn()
+	if err != nil || !isListeningFn() {
+		return "", ErrNotRunning
+	}
+	valid, err := validateDaemonNonceFn(nonce)
+	if err != nil {
+		return "", err
+	}
+	if !valid {
+		return "", fmt.Errorf("daemon on %s rejected the saved nonce", paths.SocketPath())
+	}
+	return nonce, nil
+}
+
 func validateDaemonNonce(nonce string) (bool, error) {
 	client := ipc.NewClient(paths.SocketPath(), nonce)
 	resp, err := client.Send(&ipc.Request{Type: "ping"})
@@ -103,10 +124,15 @@ func acquireSpawnLock(path string) (func() error, error) {
 	}, nil
 }
 
+// daemonBinEnvVar pins the binary spawned as the daemon, for wrappers and
+// shims that invoke mcpx through a different path than the one that should
+// serve requests.
+const daemonBinEnvVar = "MCPX_DAEMON_BIN"
+
 func spawnDaemon() error {
-	exe, err := os.Executable()
+	exe, err := daemonExecutable()
 	if err != nil {
-		return fmt.Errorf("finding executable: %w", err)
+		return err
 	}
 
 	cmd, cleanup, err := newDaemonCommand(exe)
@@ -124,6 +150,27 @@ func spawnDaemon() error {
 	return nil
 }
 
+// daemonExecutable returns the binary to re-exec as the daemon: the
+// MCPX_DAEMON_BIN override when set, otherwise the current executable.
+func daemonExecutable() (string, error) {
+	if override := strings.TrimSpace(os.Getenv(daemonBinEnvVar)); override != "" {
+		info, err := os.Stat(override)
+		if err != nil {
+			return "", fmt.Errorf("invalid %s: %w", daemonBinEnvVar, err)
+		}
+		if !info.Mode().IsRegular() || info.Mode().Perm()&0o111 == 0 {
+			return "", fmt.Errorf("invalid %s: %s is not an executable file", daemonBinEnvVar, override)
+		}
+		return override, nil
+	}
+
+	exe, err := os.Executable()
+	if err != nil {
+		return "", fmt.Errorf("finding executable: %w", err)
+	}
+	return exe, nil
+}
+
 func newDaemonCommand(exe string) (*exec.Cmd, func(), error) {
 	cmd := execCommandFn(exe, "__daemon")
 	devNull, err := os.OpenFile(os.DevNull, os.O_RDWR, 0)
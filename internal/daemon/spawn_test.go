@@ -7,6 +7,8 @@ import (
 	"net"
 	"os"
 	"os/exec"
+	"path/filepath"
+	"strings"
 	"sync"
 	"sync/atomic"
 	"testing"
@@ -476,6 +478,68 @@ func TestSpawnDaemonPropagatesCommandStartErrors(t *testing.T) {
 	}
 }
 
+func TestSpawnDaemonUsesDaemonBinOverride(t *testing.T) {
+	restore := saveSpawnHooks()
+	defer restore()
+
+	bin := filepath.Join(t.TempDir(), "mcpx-pinned")
+	if err := os.WriteFile(bin, []byte("#!/bin/sh\nexit 0\n"), 0o755); err != nil {
+		t.Fatalf("WriteFile(bin): %v", err)
+	}
+	t.Setenv(daemonBinEnvVar, bin)
+
+	var gotName string
+	var gotArgs []string
+	execCommandFn = func(name string, args ...string) *exec.Cmd {
+		gotName = name
+		gotArgs = args
+		return exec.Command(bin)
+	}
+
+	if err := spawnDaemon(); err != nil {
+		t.Fatalf("spawnDaemon() error = %v", err)
+	}
+	if gotName != bin {
+		t.Fatalf("spawned binary = %q, want %q", gotName, bin)
+	}
+	if len(gotArgs) != 1 || gotArgs[0] != "__daemon" {
+		t.Fatalf("spawned args = %#v, want [__daemon]", gotArgs)
+	}
+}
+
+func TestDaemonExecutableRejectsNonExecutableOverride(t *testing.T) {
+	bin := filepath.Join(t.TempDir(), "mcpx-plain")
+	if err := os.WriteFile(bin, []byte("not a binary"), 0o644); err != nil {
+		t.Fatalf("WriteFile(bin): %v", err)
+	}
+	t.Setenv(daemonBinEnvVar, bin)
+
+	if _, err := daemonExecutable(); err == nil || !strings.Contains(err.Error(), daemonBinEnvVar) {
+		t.Fatalf("daemonExecutable() error = %v, want %s validation error", err, daemonBinEnvVar)
+	}
+
+	t.Setenv(daemonBinEnvVar, filepath.Join(t.TempDir(), "missing"))
+	if _, err := daemonExecutable(); err == nil {
+		t.Fatal("daemonExecutable() error = nil, want error for missing override")
+	}
+}
+
+func TestDaemonExecutableFallsBackToCurrentExecutable(t *testing.T) {
+	t.Setenv(daemonBinEnvVar, "")
+
+	got, err := daemonExecutable()
+	if err != nil {
+		t.Fatalf("daemonExecutable() error = %v", err)
+	}
+	want, err := os.Executable()
+	if err != nil {
+		t.Fatalf("os.Executable() error = %v", err)
+	}
+	if got != want {
+		t.Fatalf("daemonExecutable() = %q, want %q", got, want)
+	}
+}
+
 func TestAcquireSpawnLockReturnsErrorForInvalidPath(t *testing.T) {
 	t.Setenv("XDG_RUNTIME_DIR", t.TempDir())
 	if err := paths.EnsureDir(paths.RuntimeDir()); err != nil {
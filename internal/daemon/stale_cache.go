@@ -0,0 +1,107 @@
+package daemon
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/lydakis/mcpx/internal/cache"
+	"github.com/lydakis/mcpx/internal/config"
+	"github.com/lydakis/mcpx/internal/ipc"
+	"github.com/lydakis/mcpx/internal/mcppool"
+	"github.com/lydakis/mcpx/internal/response"
+)
+
+// refreshGroup runs at most one background refresh per cache key.
+type refreshGroup struct {
+	mu       sync.Mutex
+	inFlight map[string]struct{}
+	wg       sync.WaitGroup
+}
+
+// staleRefreshes tracks stale_while_revalidate refreshes across calls.
+var staleRefreshes = &refreshGroup{inFlight: make(map[string]struct{})}
+
+// Go runs fn in a goroutine unless a refresh for key is already running,
+// and reports whether it started one.
+func (g *refreshGroup) Go(key string, fn func()) bool {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	if _, ok := g.inFlight[key]; ok {
+		return false
+	}
+	g.inFlight[key] = struct{}{}
+	g.wg.Add(1)
+	go func() {
+		defer g.wg.Done()
+		defer func() {
+			g.mu.Lock()
+			delete(g.inFlight, key)
+			g.mu.Unlock()
+		}()
+		fn()
+	}()
+	return true
+}
+
+// Wait blocks until every running refresh has finished.
+func (g *refreshGroup) Wait() {
+	g.wg.Wait()
+}
+
+// staleWindow is how long past expiry a stale_while_revalidate server may
+// serve an entry: its stale_ttl, else the cache TTL.
+func staleWindow(scfg config.ServerConfig, ttl time.Duration) time.Duration {
+	if window, err := time.ParseDuration(scfg.StaleTTL); err == nil && window > 0 {
+		return window
+	}
+	return ttl
+}
+
+// staleCacheHitLog formats the verbose log line for a stale_while_revalidate
+// lookup, naming the staleness of an expired entry.
+func staleCacheHitLog(hit cache.Hit) string {
+	if hit.Stale > 0 {
+		return fmt.Sprintf("mcpx: cache hit (age=%s ttl=%s stale=%s, refreshing)", hit.Age, hit.TTL, hit.Stale)
+	}
+	return fmt.Sprintf("mcpx: cache hit (age=%s ttl=%s)", hit.Age, hit.TTL)
+}
+
+// refreshStaleEntry calls the tool again in the background and stores a
+// successful result for ttl. Failures keep the stale entry in place. The
+// refresh outlives the request, so it drops ctx's cancellation but keeps
+// its values.
+func refreshStaleEntry(ctx context.Context, pool *mcppool.Pool, ka *Keepalive, backend, cacheServer, tool string, args json.RawMessage, ttl time.Duration, deps runtimeDeps) {
+	ctx = context.WithoutCancel(ctx)
+	key := cacheServer + "\x00" + tool + "\x00" + string(args)
+	staleRefreshes.Go(key, func() {
+		ka.Begin(backend)
+		defer ka.End(backend)
+
+		info := &mcppool.ToolInfo{Name: tool}
+		if pool != nil {
+			resolved, err := deps.poolToolInfoByName(ctx, pool, backend, tool)
+			if err != nil {
+				return
+			}
+			if resolved != nil {
+				info = resolved
+			}
+		}
+		result, err := deps.poolCallToolWithInfo(ctx, pool, backend, info, args)
+		if err != nil {
+			return
+		}
+		out, exitCode, hint := response.UnwrapHinted(result, "")
+		if exitCode != ipc.ExitOK {
+			return
+		}
+		cacheTool := tool
+		if info.Name != "" {
+			cacheTool = info.Name
+		}
+		_ = deps.cachePut(cacheServer, cacheTool, args, cache.Response{Content: out, ExitCode: exitCode, ContentType: hint.ContentType, Encoding: hint.Encoding}, ttl)
+	})
+}
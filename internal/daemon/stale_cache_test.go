@@ -0,0 +1,102 @@
+package daemon
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/lydakis/mcpx/internal/cache"
+	"github.com/lydakis/mcpx/internal/config"
+	"github.com/lydakis/mcpx/internal/ipc"
+	"github.com/lydakis/mcpx/internal/mcppool"
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+func TestCallToolServesStaleEntryAndRefreshesOnce(t *testing.T) {
+	cfg := &config.Config{Servers: map[string]config.ServerConfig{
+		"github": {DefaultCacheTTL: "1m", CacheMode: config.CacheModeStaleWhileRevalidate, StaleTTL: "10m"},
+	}}
+	ka := NewKeepalive(nil)
+	defer ka.Stop()
+
+	release := make(chan struct{})
+	var calls atomic.Int32
+	var mu sync.Mutex
+	var window, storedTTL time.Duration
+	var stored string
+	deps := runtimeDefaultDeps()
+	deps.cacheGetStale = func(_ string, _ string, _ json.RawMessage, w time.Duration) (cache.Hit, bool) {
+		mu.Lock()
+		window = w
+		mu.Unlock()
+		return cache.Hit{Response: cache.Response{Content: []byte("stale")}, Age: 2 * time.Minute, TTL: time.Minute, Stale: time.Minute}, true
+	}
+	deps.cachePut = func(_ string, _ string, _ json.RawMessage, resp cache.Response, ttl time.Duration) error {
+		mu.Lock()
+		stored, storedTTL = string(resp.Content), ttl
+		mu.Unlock()
+		return nil
+	}
+	deps.poolCallToolWithInfo = func(context.Context, *mcppool.Pool, string, *mcppool.ToolInfo, json.RawMessage) (*mcp.CallToolResult, error) {
+		calls.Add(1)
+		<-release
+		return &mcp.CallToolResult{Content: []mcp.Content{mcp.TextContent{Type: "text", Text: "fresh"}}}, nil
+	}
+
+	for i := 0; i < 2; i++ {
+		resp := callToolWithDeps(context.Background(), cfg, nil, ka, "github", "search", json.RawMessage(`{}`), nil, true, deps)
+		if resp.ExitCode != ipc.ExitOK || string(resp.Content) != "stale" {
+			t.Fatalf("call %d = (%d, %q), want stale content immediately", i, resp.ExitCode, resp.Content)
+		}
+		if !strings.Contains(resp.Stderr, "mcpx: cache hit (age=2m0s ttl=1m0s stale=1m0s") {
+			t.Fatalf("call %d stderr = %q, want stale cache hit log", i, resp.Stderr)
+		}
+	}
+	close(release)
+	staleRefreshes.Wait()
+
+	if got := calls.Load(); got != 1 {
+		t.Fatalf("refresh calls = %d, want 1", got)
+	}
+	if window != 10*time.Minute {
+		t.Fatalf("stale window = %s, want 10m", window)
+	}
+	if stored != "fresh\n" || storedTTL != time.Minute {
+		t.Fatalf("stored = (%q, %s), want (fresh, 1m)", stored, storedTTL)
+	}
+}
+
+func TestCallToolStrictModeIgnoresStaleEntries(t *testing.T) {
+	cfg := &config.Config{Servers: map[string]config.ServerConfig{"github": {DefaultCacheTTL: "1m"}}}
+	ka := NewKeepalive(nil)
+	defer ka.Stop()
+
+	deps := runtimeDefaultDeps()
+	deps.cacheGetStale = func(string, string, json.RawMessage, time.Duration) (cache.Hit, bool) {
+		t.Fatal("cacheGetStale called in strict mode")
+		return cache.Hit{}, false
+	}
+	deps.cacheGet = func(string, string, json.RawMessage) (cache.Response, bool) { return cache.Response{}, false }
+	deps.cachePut = func(string, string, json.RawMessage, cache.Response, time.Duration) error { return nil }
+	deps.poolCallToolWithInfo = func(context.Context, *mcppool.Pool, string, *mcppool.ToolInfo, json.RawMessage) (*mcp.CallToolResult, error) {
+		return &mcp.CallToolResult{Content: []mcp.Content{mcp.TextContent{Type: "text", Text: "fresh"}}}, nil
+	}
+
+	resp := callToolWithDeps(context.Background(), cfg, nil, ka, "github", "search", json.RawMessage(`{}`), nil, false, deps)
+	if resp.ExitCode != ipc.ExitOK || string(resp.Content) != "fresh\n" {
+		t.Fatalf("callTool() = (%d, %q), want fresh result", resp.ExitCode, resp.Content)
+	}
+}
+
+func TestStaleWindowDefaultsToCacheTTL(t *testing.T) {
+	if got := staleWindow(config.ServerConfig{}, time.Minute); got != time.Minute {
+		t.Fatalf("staleWindow() = %s, want 1m", got)
+	}
+	if got := staleWindow(config.ServerConfig{StaleTTL: "5m"}, time.Minute); got != 5*time.Minute {
+		t.Fatalf("staleWindow(stale_ttl=5m) = %s, want 5m", got)
+	}
+}
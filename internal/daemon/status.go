@@ -0,0 +1,73 @@
+package daemon
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+	"time"
+
+	"github.com/lydakis/mcpx/internal/ipc"
+	"github.com/lydakis/mcpx/internal/paths"
+)
+
+// daemonStatus is the status response payload, printed by
+// `mcpx daemon status`.
+type daemonStatus struct {
+	PID               int    `json:"pid"`
+	Socket            string `json:"socket"`
+	StartedAt         string `json:"started_at"`
+	UptimeSeconds     int64  `json:"uptime_seconds"`
+	ConfigFingerprint string `json:"config_fingerprint"`
+	ActiveCWD         string `json:"active_cwd,omitempty"`
+	// Connections is the total open server connections, pool_size
+	// replicas included.
+	Connections int            `json:"connections"`
+	Servers     []serverStatus `json:"servers"`
+}
+
+type serverStatus struct {
+	Name        string `json:"name"`
+	State       string `json:"state"`
+	Connections int    `json:"connections"`
+}
+
+// status reports the daemon's process, config, and per-server connection
+// state without loading config for the caller's directory.
+func (h *runtimeRequestHandler) status() *ipc.Response {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+
+	now := h.deps.now()
+	out := daemonStatus{
+		PID:               os.Getpid(),
+		Socket:            paths.SocketPath(),
+		StartedAt:         h.startedAt.UTC().Format(time.RFC3339),
+		UptimeSeconds:     int64(now.Sub(h.startedAt) / time.Second),
+		ConfigFingerprint: h.cfgHash,
+		ActiveCWD:         h.activeCWD,
+		Servers:           []serverStatus{},
+	}
+	if h.cfg != nil {
+		names := make([]string, 0, len(h.cfg.Servers))
+		for name := range h.cfg.Servers {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		for _, name := range names {
+			n := h.deps.poolConnectionCount(h.pool, name)
+			out.Connections += n
+			out.Servers = append(out.Servers, serverStatus{
+				Name:        name,
+				State:       string(h.deps.poolServerState(h.pool, name)),
+				Connections: n,
+			})
+		}
+	}
+
+	raw, err := json.Marshal(out)
+	if err != nil {
+		return &ipc.Response{ExitCode: ipc.ExitInternal, Stderr: fmt.Sprintf("encoding daemon status: %v", err)}
+	}
+	return &ipc.Response{Content: raw}
+}
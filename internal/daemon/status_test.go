@@ -0,0 +1,96 @@
+package daemon
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/lydakis/mcpx/internal/config"
+	"github.com/lydakis/mcpx/internal/ipc"
+	"github.com/lydakis/mcpx/internal/mcppool"
+)
+
+func TestRuntimeRequestHandlerStatusReportsUptimeConfigAndConnections(t *testing.T) {
+	cfg := &config.Config{Servers: map[string]config.ServerConfig{
+		"github": {Command: "echo"},
+		"linear": {Command: "echo"},
+	}}
+	started := time.Unix(1_700_000_000, 0)
+	now := started
+
+	deps := runtimeDefaultDeps()
+	deps.now = func() time.Time { return now }
+	deps.poolServerState = func(_ *mcppool.Pool, server string) mcppool.ServerState {
+		if server == "github" {
+			return mcppool.ServerStateConnected
+		}
+		return mcppool.ServerStateNever
+	}
+	deps.poolConnectionCount = func(_ *mcppool.Pool, server string) int {
+		if server == "github" {
+			return 2
+		}
+		return 0
+	}
+
+	handler := newRuntimeRequestHandlerWithDeps(cfg, nil, nil, deps)
+	handler.activeCWD = "/tmp/project"
+	now = started.Add(90 * time.Second)
+
+	resp := handler.handle(context.Background(), &ipc.Request{Type: "status"})
+	if resp.ExitCode != ipc.ExitOK {
+		t.Fatalf("status exit = %d, want %d (stderr=%q)", resp.ExitCode, ipc.ExitOK, resp.Stderr)
+	}
+	var got daemonStatus
+	if err := json.Unmarshal(resp.Content, &got); err != nil {
+		t.Fatalf("unmarshal status: %v; payload=%q", err, resp.Content)
+	}
+
+	wantHash, _ := configFingerprint(cfg)
+	if got.PID != os.Getpid() || got.UptimeSeconds != 90 || got.ConfigFingerprint != wantHash || got.ActiveCWD != "/tmp/project" {
+		t.Fatalf("status = %+v, want pid %d, uptime 90s, fingerprint %s, cwd /tmp/project", got, os.Getpid(), wantHash)
+	}
+	if got.StartedAt != "2023-11-14T22:13:20Z" || got.Connections != 2 {
+		t.Fatalf("started_at = %q connections = %d, want 2023-11-14T22:13:20Z and 2", got.StartedAt, got.Connections)
+	}
+	want := []serverStatus{
+		{Name: "github", State: "connected", Connections: 2},
+		{Name: "linear", State: "never"},
+	}
+	if !reflect.DeepEqual(got.Servers, want) {
+		t.Fatalf("servers = %#v, want %#v", got.Servers, want)
+	}
+}
+
+func TestRuntimeRequestHandlerStatusDoesNotTouchKeepalive(t *testing.T) {
+	ka := NewKeepalive(nil)
+	defer ka.Stop()
+	handler := newRuntimeRequestHandlerWithDeps(&config.Config{}, nil, ka, runtimeDefaultDeps())
+
+	handler.handle(context.Background(), &ipc.Request{Type: "status"})
+	ka.mu.Lock()
+	_, touched := ka.timers[daemonIdleSentinel]
+	ka.mu.Unlock()
+	if touched {
+		t.Fatal("status started the daemon idle timer, want it left alone")
+	}
+}
+
+func TestConnectReportsNotRunningWithoutSpawning(t *testing.T) {
+	oldRead, oldListening, oldSpawn := readNonceFn, isListeningFn, spawnDaemonFn
+	defer func() { readNonceFn, isListeningFn, spawnDaemonFn = oldRead, oldListening, oldSpawn }()
+
+	readNonceFn = func() (string, error) { return "nonce", nil }
+	isListeningFn = func() bool { return false }
+	spawnDaemonFn = func() error {
+		t.Fatal("Connect spawned a daemon")
+		return nil
+	}
+
+	if _, err := Connect(); err != ErrNotRunning {
+		t.Fatalf("Connect() error = %v, want ErrNotRunning", err)
+	}
+}
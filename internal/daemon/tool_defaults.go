@@ -0,0 +1,46 @@
+package daemon
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+// applyToolDefaults merges saved per-tool defaults into call args. Keys the
+// caller already set win, including boolean negations (--no-<name>), so an
+// explicit flag always overrides a saved default.
+func applyToolDefaults(args json.RawMessage, defaults map[string]any) (json.RawMessage, error) {
+	if len(defaults) == 0 {
+		return args, nil
+	}
+
+	merged := map[string]any{}
+	if len(args) > 0 {
+		if err := json.Unmarshal(args, &merged); err != nil {
+			return nil, fmt.Errorf("invalid args: %w", err)
+		}
+		if merged == nil {
+			merged = map[string]any{}
+		}
+	}
+
+	applied := false
+	for name, value := range defaults {
+		if _, ok := merged[name]; ok {
+			continue
+		}
+		if _, ok := merged["no-"+name]; ok {
+			continue
+		}
+		merged[name] = value
+		applied = true
+	}
+	if !applied {
+		return args, nil
+	}
+
+	out, err := json.Marshal(merged)
+	if err != nil {
+		return nil, fmt.Errorf("encoding args: %w", err)
+	}
+	return out, nil
+}
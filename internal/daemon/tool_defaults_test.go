@@ -0,0 +1,84 @@
+package daemon
+
+import (
+	"context"
+	"encoding/json"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/lydakis/mcpx/internal/cache"
+	"github.com/lydakis/mcpx/internal/config"
+	"github.com/lydakis/mcpx/internal/ipc"
+	"github.com/lydakis/mcpx/internal/mcppool"
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+func TestApplyToolDefaultsKeepsExplicitArgs(t *testing.T) {
+	defaults := map[string]any{"limit": "10", "sort": "stars", "archived": "true"}
+
+	got, err := applyToolDefaults(json.RawMessage(`{"limit":"5","no-archived":true}`), defaults)
+	if err != nil {
+		t.Fatalf("applyToolDefaults() error = %v", err)
+	}
+	var args map[string]any
+	if err := json.Unmarshal(got, &args); err != nil {
+		t.Fatalf("json.Unmarshal() error = %v", err)
+	}
+	want := map[string]any{"limit": "5", "sort": "stars", "no-archived": true}
+	if !reflect.DeepEqual(args, want) {
+		t.Fatalf("args = %#v, want %#v", args, want)
+	}
+
+	same := json.RawMessage(`{"limit":"1","sort":"x","archived":false}`)
+	got, err = applyToolDefaults(same, defaults)
+	if err != nil {
+		t.Fatalf("applyToolDefaults(all explicit) error = %v", err)
+	}
+	if string(got) != string(same) {
+		t.Fatalf("applyToolDefaults(all explicit) = %s, want unchanged %s", got, same)
+	}
+
+	if _, err := applyToolDefaults(json.RawMessage(`[1]`), defaults); err == nil {
+		t.Fatal("applyToolDefaults(array) error = nil, want non-nil")
+	}
+}
+
+func TestCallToolAppliesSavedDefaultsBeforeCacheLookup(t *testing.T) {
+	cfg := &config.Config{
+		Servers: map[string]config.ServerConfig{
+			"github": {
+				DefaultCacheTTL: "30s",
+				Tools: map[string]config.ToolConfig{
+					"search": {Defaults: map[string]any{"limit": "10"}},
+				},
+			},
+		},
+	}
+	ka := NewKeepalive(nil)
+	defer ka.Stop()
+
+	var cachedArgs, calledArgs string
+	deps := runtimeDefaultDeps()
+	deps.cacheGet = func(_, _ string, args json.RawMessage) (cache.Response, bool) {
+		cachedArgs = string(args)
+		return cache.Response{}, false
+	}
+	deps.cachePut = func(_, _ string, _ json.RawMessage, _ cache.Response, _ time.Duration) error { return nil }
+	deps.poolCallToolWithInfo = func(_ context.Context, _ *mcppool.Pool, _ string, _ *mcppool.ToolInfo, args json.RawMessage) (*mcp.CallToolResult, error) {
+		calledArgs = string(args)
+		return &mcp.CallToolResult{Content: []mcp.Content{mcp.TextContent{Type: "text", Text: "ok"}}}, nil
+	}
+
+	resp := callToolWithDeps(context.Background(), cfg, nil, ka, "github", "search", json.RawMessage(`{"query":"mcp"}`), nil, false, deps)
+	if resp.ExitCode != ipc.ExitOK {
+		t.Fatalf("callTool() exit = %d, want %d (stderr=%q)", resp.ExitCode, ipc.ExitOK, resp.Stderr)
+	}
+	want := `{"limit":"10","query":"mcp"}`
+	if calledArgs != want {
+		t.Fatalf("called args = %s, want %s", calledArgs, want)
+	}
+	if cachedArgs != want {
+		t.Fatalf("cache lookup args = %s, want %s", cachedArgs, want)
+	}
+}
@@ -0,0 +1,128 @@
+package daemon
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+)
+
+// toolUsageCounter counts call_tool invocations per server and tool, so tool
+// listings can be sorted by use, and records each server's last call for
+// --unused. A counter opened with a path saves its history there after every
+// call and loads it on open, so it outlives the daemon, which exits once
+// idle. RunWithOptions wires one into runtimeDeps;
+// the defaults elsewhere do not count.
+type toolUsageCounter struct {
+	mu     sync.Mutex
+	path   string
+	counts map[string]map[string]int
+	// lastCall holds each server's most recent invocation time, and started
+	// is when counting began (in the saved history, if any), so callers can
+	// tell how far back "never called" reaches.
+	lastCall map[string]time.Time
+	started  time.Time
+	now      func() time.Time
+}
+
+func newToolUsageCounter(now func() time.Time) *toolUsageCounter {
+	return &toolUsageCounter{started: now(), now: now}
+}
+
+// toolUsageFile is the on-disk form of a toolUsageCounter.
+type toolUsageFile struct {
+	Started  time.Time                 `json:"started"`
+	Counts   map[string]map[string]int `json:"counts"`
+	LastCall map[string]time.Time      `json:"last_call,omitempty"`
+}
+
+// openToolUsageCounter returns a counter saved at path, starting from the
+// history already there. A missing or unreadable file starts a fresh history,
+// saved right away so tracking counts from now even before the first call.
+func openToolUsageCounter(path string, now func() time.Time) *toolUsageCounter {
+	c := newToolUsageCounter(now)
+	c.path = path
+	var saved toolUsageFile
+	data, err := os.ReadFile(path)
+	if err == nil {
+		err = json.Unmarshal(data, &saved)
+	}
+	if err != nil || saved.Started.IsZero() {
+		c.mu.Lock()
+		c.saveLocked()
+		c.mu.Unlock()
+		return c
+	}
+	c.started, c.counts, c.lastCall = saved.Started, saved.Counts, saved.LastCall
+	return c
+}
+
+// saveLocked writes the counter to its path, if any. Saving is best effort:
+// a failed write only costs history, never the call. Callers must hold c.mu.
+func (c *toolUsageCounter) saveLocked() {
+	if c.path == "" {
+		return
+	}
+	data, err := json.Marshal(toolUsageFile{Started: c.started, Counts: c.counts, LastCall: c.lastCall})
+	if err != nil {
+		return
+	}
+	dir := filepath.Dir(c.path)
+	if err := os.MkdirAll(dir, 0o700); err != nil {
+		return
+	}
+	tmp, err := os.CreateTemp(dir, ".usage.json.tmp-*")
+	if err != nil {
+		return
+	}
+	_, werr := tmp.Write(data)
+	cerr := tmp.Close()
+	if werr != nil || cerr != nil || os.Rename(tmp.Name(), c.path) != nil {
+		_ = os.Remove(tmp.Name())
+	}
+}
+
+func (c *toolUsageCounter) record(server, tool string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.counts == nil {
+		c.counts = make(map[string]map[string]int)
+	}
+	if c.counts[server] == nil {
+		c.counts[server] = make(map[string]int)
+	}
+	c.counts[server][tool]++
+	if c.now != nil {
+		if c.lastCall == nil {
+			c.lastCall = make(map[string]time.Time)
+		}
+		c.lastCall[server] = c.now()
+	}
+	c.saveLocked()
+}
+
+// lastUse reports when server was last called.
+func (c *toolUsageCounter) lastUse(server string) (time.Time, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	at, ok := c.lastCall[server]
+	return at, ok
+}
+
+// trackedSince reports when call tracking began; false when the counter was
+// not built with a clock.
+func (c *toolUsageCounter) trackedSince() (time.Time, bool) {
+	return c.started, c.now != nil
+}
+
+// snapshot returns a copy of server's per-tool counts.
+func (c *toolUsageCounter) snapshot(server string) map[string]int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	out := make(map[string]int, len(c.counts[server]))
+	for tool, n := range c.counts[server] {
+		out[tool] = n
+	}
+	return out
+}
@@ -0,0 +1,77 @@
+package daemon
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/lydakis/mcpx/internal/config"
+	"github.com/lydakis/mcpx/internal/mcppool"
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+func TestListToolsReportsCallCountsRecordedByCallTool(t *testing.T) {
+	cfg := &config.Config{Servers: map[string]config.ServerConfig{"github": {}}}
+	ka := NewKeepalive(nil)
+	defer ka.Stop()
+
+	usage := &toolUsageCounter{}
+	deps := runtimeDefaultDeps()
+	deps.recordToolUse, deps.toolUseCounts = usage.record, usage.snapshot
+	deps.poolCallToolWithInfo = func(context.Context, *mcppool.Pool, string, *mcppool.ToolInfo, json.RawMessage) (*mcp.CallToolResult, error) {
+		return &mcp.CallToolResult{Content: []mcp.Content{mcp.TextContent{Type: "text", Text: "ok"}}}, nil
+	}
+	deps.poolListTools = func(context.Context, *mcppool.Pool, string) ([]mcppool.ToolInfo, error) {
+		return []mcppool.ToolInfo{{Name: "list_issues"}, {Name: "search"}, {Name: "get_me"}}, nil
+	}
+
+	for _, tool := range []string{"search", "get_me", "search"} {
+		if resp := callToolWithDeps(context.Background(), cfg, nil, ka, "github", tool, nil, nil, false, deps); resp.ExitCode != 0 {
+			t.Fatalf("callTool(%s) exit = %d (stderr=%q)", tool, resp.ExitCode, resp.Stderr)
+		}
+	}
+
+	resp := listToolsWithDeps(context.Background(), cfg, nil, ka, "github", false, deps)
+	var got []toolListEntry
+	if err := json.Unmarshal(resp.Content, &got); err != nil {
+		t.Fatalf("unmarshal json tool list: %v; payload=%q", err, string(resp.Content))
+	}
+	want := []toolListEntry{
+		{Name: "get_me", Uses: 1},
+		{Name: "list_issues"},
+		{Name: "search", Uses: 2},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("tool list = %#v, want %#v", got, want)
+	}
+}
+
+func TestToolUsageCounterKeepsCountsAcrossDaemonRestarts(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "state", "usage.json")
+	now := func() time.Time { return time.Unix(1_700_000_000, 0) }
+
+	first := openToolUsageCounter(path, now)
+	first.record("github", "search")
+	first.record("github", "search")
+	first.record("github", "get_me")
+
+	second := openToolUsageCounter(path, now)
+	if got, want := second.snapshot("github"), map[string]int{"search": 2, "get_me": 1}; !reflect.DeepEqual(got, want) {
+		t.Fatalf("snapshot after reopen = %v, want %v", got, want)
+	}
+	second.record("github", "get_me")
+	if got := openToolUsageCounter(path, now).snapshot("github")["get_me"]; got != 2 {
+		t.Fatalf("get_me count after second daemon = %d, want 2", got)
+	}
+
+	if err := os.WriteFile(path, []byte("not json"), 0o600); err != nil {
+		t.Fatalf("WriteFile() error = %v", err)
+	}
+	if got := openToolUsageCounter(path, now).snapshot("github"); len(got) != 0 {
+		t.Fatalf("snapshot from corrupt file = %v, want a fresh count", got)
+	}
+}
@@ -0,0 +1,56 @@
+package daemon
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/lydakis/mcpx/internal/config"
+	"github.com/lydakis/mcpx/internal/ipc"
+	"github.com/lydakis/mcpx/internal/mcppool"
+)
+
+// listUnusedServersWithDeps lists servers with no call_tool invocation in the
+// last window. Call times are only known since call history was first saved,
+// so a window reaching further back than that is refused rather than
+// answered with servers that may well have been used.
+func listUnusedServersWithDeps(ctx context.Context, cfg *config.Config, pool *mcppool.Pool, ka *Keepalive, includeHidden bool, window time.Duration, deps runtimeDeps) *ipc.Response {
+	deps = deps.withDefaults()
+	since, ok := deps.usageTrackedSince()
+	if !ok {
+		return &ipc.Response{
+			ExitCode: ipc.ExitUsageErr,
+			Stderr:   "mcpx: --unused needs call tracking, which this daemon does not have enabled",
+		}
+	}
+	now := deps.now()
+	if tracked := now.Sub(since); tracked < window {
+		return &ipc.Response{
+			ExitCode: ipc.ExitUsageErr,
+			Stderr: fmt.Sprintf(
+				"mcpx: --unused %s needs call history for the whole window, but calls have only been tracked for %s (since %s); use a shorter window",
+				window, tracked.Round(time.Second), since.Format(time.RFC3339),
+			),
+		}
+	}
+
+	entries, warn := serverListEntriesWithDeps(ctx, cfg, pool, ka, includeHidden, deps)
+	cutoff := now.Add(-window)
+	unused := make([]serverListEntry, 0, len(entries))
+	for _, entry := range entries {
+		if at, ok := deps.serverLastUse(entry.Name); ok && at.After(cutoff) {
+			continue
+		}
+		unused = append(unused, entry)
+	}
+
+	raw, err := json.Marshal(unused)
+	if err != nil {
+		return &ipc.Response{
+			ExitCode: ipc.ExitInternal,
+			Stderr:   fmt.Sprintf("encoding server list: %v", err),
+		}
+	}
+	return &ipc.Response{Content: raw, Stderr: warn}
+}
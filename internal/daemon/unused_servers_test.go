@@ -0,0 +1,125 @@
+package daemon
+
+import (
+	"context"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/lydakis/mcpx/internal/config"
+	"github.com/lydakis/mcpx/internal/ipc"
+)
+
+func TestListUnusedServersFiltersBySeededCallTimes(t *testing.T) {
+	cfg := &config.Config{Servers: map[string]config.ServerConfig{
+		"github": {}, "linear": {}, "notion": {},
+	}}
+	ka := NewKeepalive(nil)
+	defer ka.Stop()
+
+	now := time.Date(2026, 5, 10, 12, 0, 0, 0, time.UTC)
+	usage := newToolUsageCounter(func() time.Time { return now.Add(-72 * time.Hour) })
+	usage.lastCall = map[string]time.Time{
+		"github": now.Add(-time.Hour),
+		"linear": now.Add(-48 * time.Hour),
+	}
+	deps := runtimeDefaultDeps()
+	deps.now = func() time.Time { return now }
+	deps.serverLastUse, deps.usageTrackedSince = usage.lastUse, usage.trackedSince
+
+	unused := 24 * time.Hour
+	resp := dispatchWithDeps(context.Background(), cfg, nil, ka, &ipc.Request{Type: "list_servers", Unused: &unused}, deps)
+	if resp.ExitCode != ipc.ExitOK {
+		t.Fatalf("exit = %d, want %d (stderr=%q)", resp.ExitCode, ipc.ExitOK, resp.Stderr)
+	}
+	got := map[string]string{}
+	for _, entry := range decodeServerEntries(resp.Content) {
+		got[entry.Name] = entry.LastCallAt
+	}
+	want := map[string]string{"linear": "2026-05-08T12:00:00Z", "notion": ""}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("unused servers = %#v, want %#v", got, want)
+	}
+}
+
+func TestListUnusedServersRejectsWindowLongerThanTracking(t *testing.T) {
+	cfg := &config.Config{Servers: map[string]config.ServerConfig{"github": {}}}
+	ka := NewKeepalive(nil)
+	defer ka.Stop()
+
+	now := time.Date(2026, 5, 10, 12, 0, 0, 0, time.UTC)
+	usage := newToolUsageCounter(func() time.Time { return now.Add(-5 * time.Minute) })
+	deps := runtimeDefaultDeps()
+	deps.now = func() time.Time { return now }
+	deps.serverLastUse, deps.usageTrackedSince = usage.lastUse, usage.trackedSince
+
+	unused := time.Hour
+	resp := dispatchWithDeps(context.Background(), cfg, nil, ka, &ipc.Request{Type: "list_servers", Unused: &unused}, deps)
+	if resp.ExitCode != ipc.ExitUsageErr {
+		t.Fatalf("exit = %d, want %d", resp.ExitCode, ipc.ExitUsageErr)
+	}
+	if !strings.Contains(resp.Stderr, "only been tracked for 5m0s (since 2026-05-10T11:55:00Z)") {
+		t.Fatalf("stderr = %q, want tracked-window error", resp.Stderr)
+	}
+}
+
+func TestListUnusedServersRequiresCallTracking(t *testing.T) {
+	cfg := &config.Config{Servers: map[string]config.ServerConfig{"github": {}}}
+	ka := NewKeepalive(nil)
+	defer ka.Stop()
+
+	unused := time.Hour
+	resp := dispatchWithDeps(context.Background(), cfg, nil, ka, &ipc.Request{Type: "list_servers", Unused: &unused}, runtimeDefaultDeps())
+	if resp.ExitCode != ipc.ExitUsageErr || !strings.Contains(resp.Stderr, "needs call tracking") {
+		t.Fatalf("response = (%d, %q), want call-tracking usage error", resp.ExitCode, resp.Stderr)
+	}
+}
+
+func TestToolUsageCounterRecordsLastCallTime(t *testing.T) {
+	at := time.Date(2026, 5, 10, 12, 0, 0, 0, time.UTC)
+	usage := newToolUsageCounter(func() time.Time { return at })
+	usage.record("github", "search")
+	if got, ok := usage.lastUse("github"); !ok || !got.Equal(at) {
+		t.Fatalf("lastUse(github) = (%v, %v), want (%v, true)", got, ok, at)
+	}
+	if _, ok := usage.lastUse("linear"); ok {
+		t.Fatal("lastUse(linear) reported a call that never happened")
+	}
+}
+
+func TestToolUsageCounterKeepsCallHistoryAcrossDaemonRestarts(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "usage.json")
+	started := time.Date(2026, 5, 8, 12, 0, 0, 0, time.UTC)
+	now := started
+
+	openToolUsageCounter(path, func() time.Time { return now })
+	now = started.Add(time.Hour)
+	openToolUsageCounter(path, func() time.Time { return now }).record("github", "search")
+
+	now = started.Add(48 * time.Hour)
+	usage := openToolUsageCounter(path, func() time.Time { return now })
+	if since, ok := usage.trackedSince(); !ok || !since.Equal(started) {
+		t.Fatalf("trackedSince() = (%v, %v), want first daemon start %v", since, ok, started)
+	}
+	if at, ok := usage.lastUse("github"); !ok || !at.Equal(started.Add(time.Hour)) {
+		t.Fatalf("lastUse(github) = (%v, %v), want the call saved by the earlier daemon", at, ok)
+	}
+
+	cfg := &config.Config{Servers: map[string]config.ServerConfig{"github": {}, "linear": {}}}
+	ka := NewKeepalive(nil)
+	defer ka.Stop()
+	deps := runtimeDefaultDeps()
+	deps.now = func() time.Time { return now }
+	deps.serverLastUse, deps.usageTrackedSince = usage.lastUse, usage.trackedSince
+
+	unused := 24 * time.Hour
+	resp := dispatchWithDeps(context.Background(), cfg, nil, ka, &ipc.Request{Type: "list_servers", Unused: &unused}, deps)
+	if resp.ExitCode != ipc.ExitOK {
+		t.Fatalf("exit = %d, want %d (stderr=%q)", resp.ExitCode, ipc.ExitOK, resp.Stderr)
+	}
+	if got := len(decodeServerEntries(resp.Content)); got != 2 {
+		t.Fatalf("unused servers = %d, want github and linear", got)
+	}
+}
@@ -39,10 +39,17 @@ func (c *Client) Send(req *Request) (*Response, error) {
 		return nil, fmt.Errorf("sending request: %w", err)
 	}
 
-	var resp Response
 	dec := json.NewDecoder(conn)
-	if err := dec.Decode(&resp); err != nil {
-		return nil, fmt.Errorf("reading response: %w", err)
+	for {
+		var resp Response
+		if err := dec.Decode(&resp); err != nil {
+			return nil, fmt.Errorf("reading response: %w", err)
+		}
+		if resp.Progress == nil {
+			return &resp, nil
+		}
+		if req.OnProgress != nil {
+			req.OnProgress(*resp.Progress)
+		}
 	}
-	return &resp, nil
 }
@@ -88,6 +88,51 @@ func TestClientSendRoundTrip(t *testing.T) {
 	}
 }
 
+func TestClientSendStreamsProgressFramesBeforeResponse(t *testing.T) {
+	socketPath := shortSocketPath(t)
+
+	srv := NewServer(socketPath, "secret", func(ctx context.Context, req *Request) *Response {
+		sink := ProgressSink(ctx)
+		if !req.Progress {
+			if sink != nil {
+				return &Response{ExitCode: ExitInternal, Stderr: "unexpected progress sink"}
+			}
+			return &Response{Content: []byte("quiet\n"), ExitCode: ExitOK}
+		}
+		sink(Progress{Progress: 1, Total: 3, Message: "one"})
+		sink(Progress{Progress: 3, Total: 3})
+		return &Response{Content: []byte("done\n"), ExitCode: ExitOK}
+	})
+	if err := srv.Start(); err != nil {
+		t.Fatalf("Start() error = %v", err)
+	}
+	defer srv.Stop()
+
+	client := NewClient(socketPath, "secret")
+	var got []Progress
+	resp, err := client.Send(&Request{Type: "call_tool", Progress: true, OnProgress: func(p Progress) {
+		got = append(got, p)
+	}})
+	if err != nil {
+		t.Fatalf("Send(progress) error = %v", err)
+	}
+	if string(resp.Content) != "done\n" || resp.Progress != nil {
+		t.Fatalf("final response = %#v, want done without progress", resp)
+	}
+	want := []Progress{{Progress: 1, Total: 3, Message: "one"}, {Progress: 3, Total: 3}}
+	if len(got) != len(want) || got[0] != want[0] || got[1] != want[1] {
+		t.Fatalf("progress frames = %#v, want %#v", got, want)
+	}
+
+	resp, err = client.Send(&Request{Type: "call_tool"})
+	if err != nil {
+		t.Fatalf("Send(no progress) error = %v", err)
+	}
+	if string(resp.Content) != "quiet\n" {
+		t.Fatalf("response = %#v, want quiet", resp)
+	}
+}
+
 func TestClientSendReturnsDialError(t *testing.T) {
 	client := NewClient(shortSocketPath(t)+"-missing", "secret")
 	_, err := client.Send(&Request{Type: "ping"})
@@ -10,17 +10,76 @@ import (
 // Request is sent from the CLI to the daemon over the Unix socket.
 type Request struct {
 	Nonce   string          `json:"nonce"`            // daemon nonce for auth
-	Type    string          `json:"type"`             // "ping", "list_servers", "list_tools", "call_tool", "tool_schema", "shutdown"
+	Type    string          `json:"type"`             // "ping", "list_servers", "list_tools", "call_tool", "resolve_call", "tool_schema", "list_resources", "list_prompts", "get_prompt", "cache_clear", "cache_stats", "reload", "status", "shutdown"
 	CWD     string          `json:"cwd,omitempty"`    // caller working directory
 	Server  string          `json:"server,omitempty"` // target server name
 	Tool    string          `json:"tool,omitempty"`   // target tool name
-	Args    json.RawMessage `json:"args,omitempty"`   // tool arguments
+	Prompt  string          `json:"prompt,omitempty"` // target prompt name (get_prompt)
+	Args    json.RawMessage `json:"args,omitempty"`   // tool or prompt arguments
 	Cache   *time.Duration  `json:"cache,omitempty"`  // cache TTL override
 	Verbose bool            `json:"verbose,omitempty"`
+	// CacheScope overrides the config cache_scope for call_tool.
+	CacheScope string `json:"cache_scope,omitempty"`
+	// ExplainCache adds the call_tool cache decision (why caching is on or
+	// off, and hit, miss, or store) to Response.Stderr.
+	ExplainCache bool `json:"explain_cache,omitempty"`
+	// CacheErrors overrides the server's cache_errors for this call_tool:
+	// true caches a tool error result briefly, false never does.
+	CacheErrors *bool `json:"cache_errors,omitempty"`
+	// Fresh skips the call_tool cache lookup but still stores the result.
+	// StaleOK serves a cached entry no older than it, even past its TTL.
+	Fresh   bool           `json:"fresh,omitempty"`
+	StaleOK *time.Duration `json:"stale_ok,omitempty"`
+	// Timeout caps the whole call_tool request and replaces the server's
+	// request_timeout for it; zero means no limit. AttemptTimeout caps each
+	// tools/call try. An attempt that hits its own deadline is retried while
+	// the total budget has time left.
+	Timeout        *time.Duration `json:"timeout,omitempty"`
+	AttemptTimeout *time.Duration `json:"attempt_timeout,omitempty"`
+	// CombineContent joins a call_tool result's content blocks as "text"
+	// (concatenated) or "json" (an array of strings); empty keeps the
+	// newline join.
+	CombineContent string `json:"combine_content,omitempty"`
+	// ContentOnly keeps only the "structured" content or only the "text"
+	// blocks of a call_tool result, failing when there is none of it.
+	ContentOnly string `json:"content_only,omitempty"`
+	// RetryAfterRetries is how many times a call rejected with 429 and a
+	// Retry-After header is retried after waiting the indicated duration.
+	RetryAfterRetries int `json:"retry_after_retries,omitempty"`
+	// RetryBudget retries transient call_tool failures with backoff until
+	// this much time has passed since the first attempt.
+	RetryBudget *time.Duration `json:"retry_budget,omitempty"`
+	// CaptureStderr forwards what a stdio server writes to stderr during
+	// call_tool into Response.Stderr, one prefixed line each.
+	CaptureStderr bool `json:"capture_stderr,omitempty"`
+	// Headers are added to the HTTP requests of this call_tool, over the
+	// server's configured headers. Stdio servers ignore them.
+	Headers map[string]string `json:"headers,omitempty"`
+	// IdempotencyKey is forwarded with call_tool as a header or argument,
+	// per the tool's idempotency_key mapping.
+	IdempotencyKey string `json:"idempotency_key,omitempty"`
+	// RawBytes returns the single content block of a call_tool result as
+	// raw bytes instead of rendered text, bypassing the cache.
+	RawBytes bool `json:"raw_bytes,omitempty"`
+	// Raw returns a call_tool result as the full MCP CallToolResult JSON,
+	// content block types and _meta included, bypassing the cache.
+	Raw bool `json:"raw,omitempty"`
+	// Progress asks the daemon to stream MCP progress notifications for
+	// call_tool as interim response frames, which Client.Send passes to
+	// OnProgress before returning the final response.
+	Progress   bool           `json:"progress,omitempty"`
+	OnProgress func(Progress) `json:"-"`
 	// IncludeHidden asks daemon responses (currently list_servers) to include
 	// otherwise hidden runtime-only servers.
 	IncludeHidden bool             `json:"include_hidden,omitempty"`
 	Ephemeral     *EphemeralServer `json:"ephemeral,omitempty"`
+	// EnvServers are the caller's MCPX_SERVER_<NAME>_* servers, expanded.
+	// The daemon serves exactly these as env-defined servers for the
+	// request; nil (unlike an empty map) leaves its current set in place.
+	EnvServers map[string]config.ServerConfig `json:"env_servers"`
+	// Unused asks list_servers for only the servers with no call_tool
+	// invocations in this window.
+	Unused *time.Duration `json:"unused,omitempty"`
 }
 
 // EphemeralServer carries a transient server definition to be registered by
@@ -35,6 +94,33 @@ type Response struct {
 	ExitCode  int    `json:"exit_code"`            // 0=ok, 1=tool error, 2=usage error, 3=internal error
 	Stderr    string `json:"stderr,omitempty"`     // error message for stderr
 	ErrorCode string `json:"error_code,omitempty"` // stable machine-readable error classification
+	// Progress marks an interim frame sent ahead of the final response to a
+	// request with Progress set; its other fields are empty.
+	Progress *Progress `json:"progress,omitempty"`
+	// ContentType and Encoding describe Content for final call_tool
+	// responses: ContentType is a MIME type and Encoding is one of the
+	// Encoding* constants. Both are empty when unknown, such as for cache hits
+	// or older daemons, and clients fall back to treating Content as text.
+	ContentType string `json:"content_type,omitempty"`
+	Encoding    string `json:"encoding,omitempty"`
+}
+
+// Response content encodings.
+const (
+	// EncodingText is inline UTF-8 text.
+	EncodingText = "text"
+	// EncodingPath is newline-separated paths of temp files holding image or
+	// resource data.
+	EncodingPath = "path"
+	// EncodingBinary is raw decoded bytes (--output-raw-bytes).
+	EncodingBinary = "binary"
+)
+
+// Progress is one progress update reported by a server during a call.
+type Progress struct {
+	Progress float64 `json:"progress"`
+	Total    float64 `json:"total,omitempty"`
+	Message  string  `json:"message,omitempty"`
 }
 
 const (
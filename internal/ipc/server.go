@@ -107,6 +107,18 @@ func (s *Server) handleConn(conn net.Conn) {
 	ctx, cancel := context.WithCancel(context.Background())
 	defer cancel()
 
+	var writeMu sync.Mutex
+	finished := false
+	if req.Progress {
+		ctx = context.WithValue(ctx, progressSinkKey{}, func(p Progress) {
+			writeMu.Lock()
+			defer writeMu.Unlock()
+			if !finished {
+				writeResponse(conn, &Response{Progress: &p})
+			}
+		})
+	}
+
 	done := make(chan struct{})
 	go func() {
 		defer close(done)
@@ -122,7 +134,19 @@ func (s *Server) handleConn(conn net.Conn) {
 	_ = conn.SetReadDeadline(time.Now())
 	<-done
 	_ = conn.SetReadDeadline(time.Time{})
+	writeMu.Lock()
+	finished = true
 	writeResponse(conn, resp)
+	writeMu.Unlock()
+}
+
+type progressSinkKey struct{}
+
+// ProgressSink returns the func that streams progress frames back to the
+// client of the request being handled, or nil when it did not ask for them.
+func ProgressSink(ctx context.Context) func(Progress) {
+	sink, _ := ctx.Value(progressSinkKey{}).(func(Progress))
+	return sink
 }
 
 func writeResponse(conn net.Conn, resp *Response) {
@@ -62,6 +62,11 @@ func coerceObject(raw map[string]any, schema map[string]any, path string) (map[s
 	if err != nil {
 		return nil, err
 	}
+	if path == "" {
+		// Only top-level arguments can come from --flag=null; nested
+		// values are JSON, where a "null" string is just a string.
+		raw = dropNullBooleanFlags(raw, props)
+	}
 
 	for key := range required {
 		if _, ok := raw[key]; !ok {
@@ -140,6 +145,11 @@ func rewriteNoPrefixedBooleanAliases(raw map[string]any, props map[string]any, p
 		if !ok || schemaType(baseSchema) != "boolean" {
 			continue
 		}
+		if isNullFlagValue(value) {
+			// --no-flag=null omits the argument just like --flag=null.
+			delete(rewritten, key)
+			continue
+		}
 		if _, exists := rewritten[baseKey]; exists {
 			return nil, invalidParamsError("conflicting arguments %q and %q", dottedPath(path, baseKey), dottedPath(path, key))
 		}
@@ -155,11 +165,90 @@ func rewriteNoPrefixedBooleanAliases(raw map[string]any, props map[string]any, p
 	return rewritten, nil
 }
 
+// dropNullBooleanFlags removes boolean arguments given the literal string
+// "null" (--flag=null) so optional booleans can be explicitly left unset.
+// Booleans whose schema also allows null receive a JSON null instead.
+func dropNullBooleanFlags(raw map[string]any, props map[string]any) map[string]any {
+	if len(raw) == 0 || len(props) == 0 {
+		return raw
+	}
+
+	var out map[string]any
+	for key, value := range raw {
+		if !isNullFlagValue(value) {
+			continue
+		}
+		propSchema, ok := props[key].(map[string]any)
+		if !ok {
+			continue
+		}
+		typ, nullable := nullableSchemaType(propSchema)
+		if typ != "boolean" {
+			continue
+		}
+		if out == nil {
+			out = make(map[string]any, len(raw))
+			for k, v := range raw {
+				out[k] = v
+			}
+		}
+		if nullable {
+			out[key] = nil
+		} else {
+			delete(out, key)
+		}
+	}
+	if out == nil {
+		return raw
+	}
+	return out
+}
+
+func isNullFlagValue(value any) bool {
+	s, ok := value.(string)
+	return ok && strings.EqualFold(strings.TrimSpace(s), "null")
+}
+
+// nullableSchemaType reports the single non-null type of a schema declared as
+// {"type": ["<type>", "null"]}. Plain string types are returned as-is.
+func nullableSchemaType(schema map[string]any) (string, bool) {
+	types, ok := schema["type"].([]any)
+	if !ok {
+		return schemaType(schema), false
+	}
+
+	typ := ""
+	nullable := false
+	for _, item := range types {
+		name, ok := item.(string)
+		if !ok {
+			return "", false
+		}
+		name = strings.TrimSpace(strings.ToLower(name))
+		if name == "null" {
+			nullable = true
+			continue
+		}
+		if typ != "" {
+			return "", false
+		}
+		typ = name
+	}
+	return typ, nullable
+}
+
 func coerceValue(value any, schema map[string]any, path string) (any, error) {
 	if schema == nil || value == nil {
 		return value, nil
 	}
 
+	if typ, nullable := nullableSchemaType(schema); nullable {
+		if typ == "" {
+			return value, nil
+		}
+		schema = withSchemaType(schema, typ)
+	}
+
 	switch schemaType(schema) {
 	case "string":
 		s, ok := value.(string)
@@ -254,7 +343,7 @@ func coerceBoolean(value any, path string) (bool, error) {
 	case string:
 		b, err := strconv.ParseBool(strings.TrimSpace(v))
 		if err != nil {
-			return false, invalidParamsError("argument %q must be boolean: %v", path, err)
+			return false, invalidParamsError("argument %q must be boolean (true, false, or null to omit): %v", path, err)
 		}
 		return b, nil
 	default:
@@ -333,6 +422,15 @@ func coerceObjectValue(value any, schema map[string]any, path string) (map[strin
 	}
 }
 
+func withSchemaType(schema map[string]any, typ string) map[string]any {
+	out := make(map[string]any, len(schema))
+	for k, v := range schema {
+		out[k] = v
+	}
+	out["type"] = typ
+	return out
+}
+
 func requiredSet(schema map[string]any) map[string]struct{} {
 	out := map[string]struct{}{}
 
@@ -4,6 +4,7 @@ import (
 	"context"
 	"encoding/json"
 	"errors"
+	"reflect"
 	"strings"
 	"testing"
 
@@ -435,6 +436,99 @@ func TestCallToolPreservesLiteralNoPrefixedBooleanParams(t *testing.T) {
 	}
 }
 
+func TestCompileToolArgsSupportsExplicitBooleanValues(t *testing.T) {
+	t.Parallel()
+
+	schema := map[string]any{
+		"type": "object",
+		"properties": map[string]any{
+			"draft":    map[string]any{"type": "boolean"},
+			"archived": map[string]any{"type": []any{"boolean", "null"}},
+		},
+	}
+
+	tests := []struct {
+		name string
+		raw  map[string]any
+		want map[string]any
+	}{
+		{name: "explicit true", raw: map[string]any{"draft": "true"}, want: map[string]any{"draft": true}},
+		{name: "explicit false", raw: map[string]any{"draft": "false"}, want: map[string]any{"draft": false}},
+		{name: "bare flag", raw: map[string]any{"draft": true}, want: map[string]any{"draft": true}},
+		{name: "negated flag", raw: map[string]any{"no-draft": true}, want: map[string]any{"draft": false}},
+		{name: "negated explicit false", raw: map[string]any{"no-draft": "false"}, want: map[string]any{"draft": true}},
+		{name: "null omits", raw: map[string]any{"draft": "null"}, want: map[string]any{}},
+		{name: "negated null omits", raw: map[string]any{"no-draft": "null"}, want: map[string]any{}},
+		{name: "nullable true", raw: map[string]any{"archived": "true"}, want: map[string]any{"archived": true}},
+		{name: "nullable null", raw: map[string]any{"archived": "NULL"}, want: map[string]any{"archived": nil}},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := compileToolArgsAgainstSchema(tc.raw, schema)
+			if err != nil {
+				t.Fatalf("compileToolArgsAgainstSchema(%v) error = %v", tc.raw, err)
+			}
+			if !reflect.DeepEqual(got, tc.want) {
+				t.Fatalf("compileToolArgsAgainstSchema(%v) = %#v, want %#v", tc.raw, got, tc.want)
+			}
+		})
+	}
+}
+
+func TestCompileToolArgsRejectsNullForRequiredBoolean(t *testing.T) {
+	t.Parallel()
+
+	schema := map[string]any{
+		"type":       "object",
+		"properties": map[string]any{"draft": map[string]any{"type": "boolean"}},
+		"required":   []any{"draft"},
+	}
+
+	_, err := compileToolArgsAgainstSchema(map[string]any{"draft": "null"}, schema)
+	if !errors.Is(err, mcp.ErrInvalidParams) {
+		t.Fatalf("compileToolArgsAgainstSchema() error = %v, want ErrInvalidParams", err)
+	}
+	if !strings.Contains(err.Error(), `missing required argument "draft"`) {
+		t.Fatalf("error = %q, want missing required argument", err.Error())
+	}
+}
+
+func TestCompileToolArgsKeepsNullStringForNonBooleanNullables(t *testing.T) {
+	t.Parallel()
+
+	schema := map[string]any{
+		"type": "object",
+		"properties": map[string]any{
+			"title": map[string]any{"type": []any{"string", "null"}},
+			"meta": map[string]any{
+				"type": "object",
+				"properties": map[string]any{
+					"archived": map[string]any{"type": []any{"boolean", "null"}},
+				},
+			},
+		},
+	}
+
+	raw := map[string]any{
+		"title": "null",
+		"meta":  map[string]any{"archived": "null"},
+	}
+	got, err := compileToolArgsAgainstSchema(raw, schema)
+	if err == nil {
+		t.Fatalf("compileToolArgsAgainstSchema(%v) = %#v, want nested \"null\" string rejected as a boolean", raw, got)
+	}
+
+	raw = map[string]any{"title": "null"}
+	got, err = compileToolArgsAgainstSchema(raw, schema)
+	if err != nil {
+		t.Fatalf("compileToolArgsAgainstSchema(%v) error = %v", raw, err)
+	}
+	if want := map[string]any{"title": "null"}; !reflect.DeepEqual(got, want) {
+		t.Fatalf("compileToolArgsAgainstSchema(%v) = %#v, want %#v", raw, got, want)
+	}
+}
+
 func TestCoerceIntegerCoversSupportedInputTypesAndFailures(t *testing.T) {
 	t.Parallel()
 
@@ -0,0 +1,66 @@
+package mcppool
+
+import (
+	"context"
+
+	mcpclient "github.com/mark3labs/mcp-go/client"
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+// newClientConnection adapts an initialized MCP client to the pool's
+// transport-agnostic connection hooks.
+func newClientConnection(c *mcpclient.Client) *connection {
+	progress := &progressTap{}
+	c.OnNotification(progress.handle)
+	return &connection{
+		listTools: func(ctx context.Context) ([]mcp.Tool, error) {
+			result, err := c.ListTools(ctx, mcp.ListToolsRequest{})
+			if err != nil {
+				return nil, err
+			}
+			return result.Tools, nil
+		},
+		callTool: func(ctx context.Context, name string, args map[string]any) (*mcp.CallToolResult, error) {
+			req := mcp.CallToolRequest{
+				Params: mcp.CallToolParams{
+					Name:      name,
+					Arguments: args,
+				},
+			}
+			if sink := progressFrom(ctx); sink != nil {
+				token, detach := progress.attach(sink)
+				defer detach()
+				req.Params.Meta = &mcp.Meta{ProgressToken: token}
+			}
+			return c.CallTool(ctx, req)
+		},
+		listResources: func(ctx context.Context) ([]mcp.Resource, error) {
+			result, err := c.ListResources(ctx, mcp.ListResourcesRequest{})
+			if err != nil {
+				return nil, err
+			}
+			return result.Resources, nil
+		},
+		listPrompts: func(ctx context.Context) ([]mcp.Prompt, error) {
+			result, err := c.ListPrompts(ctx, mcp.ListPromptsRequest{})
+			if err != nil {
+				return nil, err
+			}
+			return result.Prompts, nil
+		},
+		getPrompt: func(ctx context.Context, name string, args map[string]string) (*mcp.GetPromptResult, error) {
+			return c.GetPrompt(ctx, mcp.GetPromptRequest{
+				Params: mcp.GetPromptParams{
+					Name:      name,
+					Arguments: args,
+				},
+			})
+		},
+		ping: func(ctx context.Context) error {
+			return c.Ping(ctx)
+		},
+		close: func() error {
+			return c.Close()
+		},
+	}
+}
@@ -0,0 +1,29 @@
+package mcppool
+
+import (
+	"context"
+
+	"github.com/lydakis/mcpx/internal/httpheaders"
+)
+
+type requestHeadersKey struct{}
+
+// WithRequestHeaders adds headers to the HTTP requests of calls made with
+// ctx, on top of the server's configured headers and any added to ctx
+// before (names match case-insensitively; the newest value wins). Stdio
+// servers ignore it.
+func WithRequestHeaders(ctx context.Context, headers map[string]string) context.Context {
+	merged := httpheaders.Merge(httpheaders.Merge(nil, requestHeadersFrom(ctx), true), headers, true)
+	return context.WithValue(ctx, requestHeadersKey{}, merged)
+}
+
+// RequestHeaders returns the per-call headers added to ctx with
+// WithRequestHeaders, or nil.
+func RequestHeaders(ctx context.Context) map[string]string {
+	return requestHeadersFrom(ctx)
+}
+
+func requestHeadersFrom(ctx context.Context) map[string]string {
+	headers, _ := ctx.Value(requestHeadersKey{}).(map[string]string)
+	return headers
+}
@@ -0,0 +1,59 @@
+package mcppool
+
+import (
+	"context"
+	"encoding/json"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/lydakis/mcpx/internal/config"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+func TestCallToolSendsRequestHeadersFromContext(t *testing.T) {
+	var mu sync.Mutex
+	var seen []string
+	mcpServer := server.NewMCPServer("mcpx-headers-helper", "1.0.0")
+	mcpServer.AddTool(mcp.Tool{Name: "charge", InputSchema: mcp.ToolInputSchema{Type: "object"}}, func(_ context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		mu.Lock()
+		defer mu.Unlock()
+		seen = append(seen, req.Header.Get("Idempotency-Key"))
+		return mcp.NewToolResultText("ok"), nil
+	})
+	httpServer := server.NewTestStreamableHTTPServer(mcpServer)
+	defer httpServer.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	pool := New(&config.Config{Servers: map[string]config.ServerConfig{
+		"http": {URL: httpServer.URL},
+	}})
+	defer pool.CloseAll()
+
+	keyed := WithRequestHeaders(ctx, map[string]string{"Idempotency-Key": "order-1"})
+	if _, err := pool.CallTool(keyed, "http", "charge", json.RawMessage(`{}`)); err != nil {
+		t.Fatalf("CallTool(keyed) error = %v", err)
+	}
+	if _, err := pool.CallTool(ctx, "http", "charge", json.RawMessage(`{}`)); err != nil {
+		t.Fatalf("CallTool(plain) error = %v", err)
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	if len(seen) != 2 || seen[0] != "order-1" || seen[1] != "" {
+		t.Fatalf("Idempotency-Key headers = %q, want [order-1, \"\"]", seen)
+	}
+}
+
+func TestWithRequestHeadersLayersOnEarlierHeaders(t *testing.T) {
+	ctx := WithRequestHeaders(context.Background(), map[string]string{"X-Team": "core", "Authorization": "Bearer a"})
+	ctx = WithRequestHeaders(ctx, map[string]string{"authorization": "Bearer b"})
+
+	got := requestHeadersFrom(ctx)
+	if len(got) != 2 || got["X-Team"] != "core" || got["authorization"] != "Bearer b" {
+		t.Fatalf("request headers = %#v, want X-Team kept and authorization replaced", got)
+	}
+}
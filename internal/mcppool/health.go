@@ -0,0 +1,67 @@
+package mcppool
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/lydakis/mcpx/internal/config"
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+const defaultHealthCheckTimeout = 30 * time.Second
+
+// healthCheckRetryInterval is the pause between failed probes.
+var healthCheckRetryInterval = 250 * time.Millisecond
+
+// probeHealth calls the server's health_check tool until it succeeds or the
+// health check timeout elapses. Servers without health_check pass at once.
+func probeHealth(ctx context.Context, conn *connection, scfg config.ServerConfig) error {
+	tool := strings.TrimSpace(scfg.HealthCheck)
+	if tool == "" {
+		return nil
+	}
+	timeout := defaultHealthCheckTimeout
+	if d, err := time.ParseDuration(scfg.HealthCheckTimeout); err == nil && d > 0 {
+		timeout = d
+	}
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	var lastErr error
+	for {
+		result, err := runCallTool(conn, ctx, tool, map[string]any{})
+		if err == nil && !result.IsError {
+			return nil
+		}
+		if err == nil {
+			err = errors.New(healthResultText(result))
+		}
+		// Keep the probe's own failure rather than the deadline that cut
+		// the final attempt short.
+		if lastErr == nil || ctx.Err() == nil {
+			lastErr = err
+		}
+
+		select {
+		case <-ctx.Done():
+			return fmt.Errorf("health check %q did not pass within %s: %w", tool, timeout, lastErr)
+		case <-time.After(healthCheckRetryInterval):
+		}
+	}
+}
+
+func healthResultText(result *mcp.CallToolResult) string {
+	var parts []string
+	for _, content := range result.Content {
+		if text, ok := content.(mcp.TextContent); ok && strings.TrimSpace(text.Text) != "" {
+			parts = append(parts, strings.TrimSpace(text.Text))
+		}
+	}
+	if len(parts) == 0 {
+		return "tool reported an error"
+	}
+	return strings.Join(parts, "; ")
+}
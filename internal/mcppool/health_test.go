@@ -0,0 +1,150 @@
+package mcppool
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/lydakis/mcpx/internal/config"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+func newHealthCheckServer(t *testing.T, failures int) (url string, probes func() int, served func() int) {
+	t.Helper()
+	var mu sync.Mutex
+	probeCount, servedCount := 0, 0
+
+	mcpServer := server.NewMCPServer("mcpx-health-helper", "1.0.0")
+	mcpServer.AddTool(mcp.Tool{Name: "ping", InputSchema: mcp.ToolInputSchema{Type: "object"}}, func(context.Context, mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		mu.Lock()
+		defer mu.Unlock()
+		probeCount++
+		if failures < 0 || probeCount <= failures {
+			return mcp.NewToolResultError("warming up"), nil
+		}
+		return mcp.NewToolResultText("ok"), nil
+	})
+	mcpServer.AddTool(mcp.Tool{Name: "work", InputSchema: mcp.ToolInputSchema{Type: "object"}}, func(context.Context, mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		mu.Lock()
+		defer mu.Unlock()
+		servedCount++
+		return mcp.NewToolResultText("done"), nil
+	})
+
+	httpServer := server.NewTestStreamableHTTPServer(mcpServer)
+	t.Cleanup(httpServer.Close)
+
+	return httpServer.URL,
+		func() int { mu.Lock(); defer mu.Unlock(); return probeCount },
+		func() int { mu.Lock(); defer mu.Unlock(); return servedCount }
+}
+
+func TestPoolWaitsForHealthCheckBeforeServingCalls(t *testing.T) {
+	oldInterval := healthCheckRetryInterval
+	healthCheckRetryInterval = time.Millisecond
+	defer func() { healthCheckRetryInterval = oldInterval }()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	url, probes, served := newHealthCheckServer(t, 2)
+	pool := New(&config.Config{Servers: map[string]config.ServerConfig{
+		"http": {URL: url, HealthCheck: "ping"},
+	}})
+	defer pool.CloseAll()
+
+	if _, err := pool.CallTool(ctx, "http", "work", json.RawMessage(`{}`)); err != nil {
+		t.Fatalf("CallTool() error = %v", err)
+	}
+	if got := probes(); got != 3 {
+		t.Fatalf("health probes = %d, want 3 (two failures then success)", got)
+	}
+	if got := served(); got != 1 {
+		t.Fatalf("served calls = %d, want 1", got)
+	}
+
+	if _, err := pool.CallTool(ctx, "http", "work", json.RawMessage(`{}`)); err != nil {
+		t.Fatalf("second CallTool() error = %v", err)
+	}
+	if got := probes(); got != 3 {
+		t.Fatalf("health probes = %d after reuse, want 3 (probe runs once per connection)", got)
+	}
+}
+
+func TestPoolEvictsConnectionWhenHealthCheckNeverPasses(t *testing.T) {
+	oldInterval := healthCheckRetryInterval
+	healthCheckRetryInterval = time.Millisecond
+	defer func() { healthCheckRetryInterval = oldInterval }()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	url, _, served := newHealthCheckServer(t, -1)
+	pool := New(&config.Config{Servers: map[string]config.ServerConfig{
+		"http": {URL: url, HealthCheck: "ping", HealthCheckTimeout: "50ms"},
+	}})
+	defer pool.CloseAll()
+
+	_, err := pool.CallTool(ctx, "http", "work", json.RawMessage(`{}`))
+	if err == nil || !strings.Contains(err.Error(), `health check "ping"`) || !strings.Contains(err.Error(), "warming up") {
+		t.Fatalf("CallTool() error = %v, want health check failure", err)
+	}
+	if got := served(); got != 0 {
+		t.Fatalf("served calls = %d, want 0 while unhealthy", got)
+	}
+	pool.mu.Lock()
+	_, cached := pool.conns["http"]
+	pool.mu.Unlock()
+	if cached {
+		t.Fatal("unhealthy connection kept in pool, want evicted")
+	}
+}
+
+func TestPoolServesOtherServersWhileHealthCheckIsPending(t *testing.T) {
+	oldInterval := healthCheckRetryInterval
+	healthCheckRetryInterval = time.Millisecond
+	defer func() { healthCheckRetryInterval = oldInterval }()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	slowURL, slowProbes, _ := newHealthCheckServer(t, -1)
+	fastURL, _, fastServed := newHealthCheckServer(t, 0)
+	pool := New(&config.Config{Servers: map[string]config.ServerConfig{
+		"slow": {URL: slowURL, HealthCheck: "ping", HealthCheckTimeout: "1m"},
+		"fast": {URL: fastURL, HealthCheck: "ping"},
+	}})
+	defer pool.CloseAll()
+
+	slowCtx, cancelSlow := context.WithCancel(ctx)
+	slowDone := make(chan error, 1)
+	go func() {
+		_, err := pool.CallTool(slowCtx, "slow", "work", json.RawMessage(`{}`))
+		slowDone <- err
+	}()
+	for slowProbes() == 0 {
+		time.Sleep(time.Millisecond)
+	}
+
+	if _, err := pool.CallTool(ctx, "fast", "work", json.RawMessage(`{}`)); err != nil {
+		t.Fatalf("CallTool(fast) error = %v", err)
+	}
+	if got := fastServed(); got != 1 {
+		t.Fatalf("fast served calls = %d, want 1 while slow is probing", got)
+	}
+	pool.mu.Lock()
+	_, published := pool.conns["slow"]
+	pool.mu.Unlock()
+	if published {
+		t.Fatal("slow connection published before its health check passed")
+	}
+
+	cancelSlow()
+	if err := <-slowDone; err == nil {
+		t.Fatal("CallTool(slow) error = nil, want health check failure")
+	}
+}
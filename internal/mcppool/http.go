@@ -3,6 +3,8 @@ package mcppool
 import (
 	"context"
 	"fmt"
+	"net/http"
+	"time"
 
 	"github.com/lydakis/mcpx/internal/config"
 	mcpclient "github.com/mark3labs/mcp-go/client"
@@ -10,11 +12,51 @@ import (
 	"github.com/mark3labs/mcp-go/mcp"
 )
 
+// Connection pooling defaults for HTTP servers. Go's default of two idle
+// connections per host forces re-dialing under concurrent tool calls.
+const (
+	defaultHTTPMaxIdleConns        = 100
+	defaultHTTPMaxIdleConnsPerHost = 16
+	defaultHTTPIdleConnTimeout     = 90 * time.Second
+)
+
+// newHTTPTransport builds a keep-alive transport per server connection,
+// applying any [servers.<name>.http] overrides on top of mcpx defaults.
+func newHTTPTransport(tuning *config.HTTPConfig) *http.Transport {
+	base, ok := http.DefaultTransport.(*http.Transport)
+	var tr *http.Transport
+	if ok {
+		tr = base.Clone()
+	} else {
+		tr = &http.Transport{Proxy: http.ProxyFromEnvironment}
+	}
+	tr.MaxIdleConns = defaultHTTPMaxIdleConns
+	tr.MaxIdleConnsPerHost = defaultHTTPMaxIdleConnsPerHost
+	tr.IdleConnTimeout = defaultHTTPIdleConnTimeout
+
+	if tuning == nil {
+		return tr
+	}
+	if tuning.MaxIdleConns > 0 {
+		tr.MaxIdleConns = tuning.MaxIdleConns
+	}
+	if tuning.MaxIdleConnsPerHost > 0 {
+		tr.MaxIdleConnsPerHost = tuning.MaxIdleConnsPerHost
+	}
+	if timeout, err := time.ParseDuration(tuning.IdleConnTimeout); err == nil && timeout > 0 {
+		tr.IdleConnTimeout = timeout
+	}
+	return tr
+}
+
 func connectHTTP(ctx context.Context, scfg config.ServerConfig) (*connection, error) {
 	var opts []transport.StreamableHTTPCOption
 	if len(scfg.Headers) > 0 {
 		opts = append(opts, transport.WithHTTPHeaders(scfg.Headers))
 	}
+	opts = append(opts, transport.WithHTTPHeaderFunc(requestHeadersFrom))
+	httpTransport := newHTTPTransport(scfg.HTTP)
+	opts = append(opts, transport.WithHTTPBasicClient(&http.Client{Transport: &retryAfterTransport{base: httpTransport}}))
 
 	c, err := mcpclient.NewStreamableHttpClient(scfg.URL, opts...)
 	if err != nil {
@@ -40,24 +82,12 @@ func connectHTTP(ctx context.Context, scfg config.ServerConfig) (*connection, er
 		return nil, fmt.Errorf("initializing: %w", err)
 	}
 
-	return &connection{
-		listTools: func(ctx context.Context) ([]mcp.Tool, error) {
-			result, err := c.ListTools(ctx, mcp.ListToolsRequest{})
-			if err != nil {
-				return nil, err
-			}
-			return result.Tools, nil
-		},
-		callTool: func(ctx context.Context, name string, args map[string]any) (*mcp.CallToolResult, error) {
-			return c.CallTool(ctx, mcp.CallToolRequest{
-				Params: mcp.CallToolParams{
-					Name:      name,
-					Arguments: args,
-				},
-			})
-		},
-		close: func() error {
-			return c.Close()
-		},
-	}, nil
+	conn := newClientConnection(c)
+	closeClient := conn.close
+	conn.close = func() error {
+		err := closeClient()
+		httpTransport.CloseIdleConnections()
+		return err
+	}
+	return conn, nil
 }
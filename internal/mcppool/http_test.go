@@ -0,0 +1,57 @@
+package mcppool
+
+import (
+	"testing"
+	"time"
+
+	"github.com/lydakis/mcpx/internal/config"
+)
+
+func TestNewHTTPTransportUsesPoolingDefaults(t *testing.T) {
+	tr := newHTTPTransport(nil)
+
+	if tr.MaxIdleConns != defaultHTTPMaxIdleConns {
+		t.Fatalf("MaxIdleConns = %d, want %d", tr.MaxIdleConns, defaultHTTPMaxIdleConns)
+	}
+	if tr.MaxIdleConnsPerHost != defaultHTTPMaxIdleConnsPerHost {
+		t.Fatalf("MaxIdleConnsPerHost = %d, want %d", tr.MaxIdleConnsPerHost, defaultHTTPMaxIdleConnsPerHost)
+	}
+	if tr.IdleConnTimeout != defaultHTTPIdleConnTimeout {
+		t.Fatalf("IdleConnTimeout = %s, want %s", tr.IdleConnTimeout, defaultHTTPIdleConnTimeout)
+	}
+	if tr.DisableKeepAlives {
+		t.Fatal("DisableKeepAlives = true, want keep-alive enabled")
+	}
+}
+
+func TestNewHTTPTransportAppliesConfiguredTuning(t *testing.T) {
+	tr := newHTTPTransport(&config.HTTPConfig{
+		MaxIdleConns:        256,
+		MaxIdleConnsPerHost: 64,
+		IdleConnTimeout:     "2m",
+	})
+
+	if tr.MaxIdleConns != 256 {
+		t.Fatalf("MaxIdleConns = %d, want 256", tr.MaxIdleConns)
+	}
+	if tr.MaxIdleConnsPerHost != 64 {
+		t.Fatalf("MaxIdleConnsPerHost = %d, want 64", tr.MaxIdleConnsPerHost)
+	}
+	if tr.IdleConnTimeout != 2*time.Minute {
+		t.Fatalf("IdleConnTimeout = %s, want 2m", tr.IdleConnTimeout)
+	}
+}
+
+func TestNewHTTPTransportKeepsDefaultsForZeroValues(t *testing.T) {
+	tr := newHTTPTransport(&config.HTTPConfig{MaxIdleConnsPerHost: 4})
+
+	if tr.MaxIdleConns != defaultHTTPMaxIdleConns {
+		t.Fatalf("MaxIdleConns = %d, want default %d", tr.MaxIdleConns, defaultHTTPMaxIdleConns)
+	}
+	if tr.MaxIdleConnsPerHost != 4 {
+		t.Fatalf("MaxIdleConnsPerHost = %d, want 4", tr.MaxIdleConnsPerHost)
+	}
+	if tr.IdleConnTimeout != defaultHTTPIdleConnTimeout {
+		t.Fatalf("IdleConnTimeout = %s, want default %s", tr.IdleConnTimeout, defaultHTTPIdleConnTimeout)
+	}
+}
@@ -5,6 +5,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"os"
+	"path/filepath"
 	"strings"
 	"sync"
 	"testing"
@@ -15,7 +16,16 @@ import (
 	"github.com/mark3labs/mcp-go/server"
 )
 
-const stdioHelperEnv = "GO_WANT_MCPX_STDIO_HELPER"
+const (
+	stdioHelperEnv      = "GO_WANT_MCPX_STDIO_HELPER"
+	stdioHelperNoisyEnv = "GO_WANT_MCPX_STDIO_HELPER_NOISY"
+	// stdioHelperBarrierEnv names a directory; when set, the helper adds
+	// barrier_tool, which returns only once two calls are in it at once.
+	stdioHelperBarrierEnv = "GO_WANT_MCPX_STDIO_HELPER_BARRIER_DIR"
+	// stdioHelperEnvToolEnv adds env_tool, which returns the helper's
+	// environment variable names, one per line.
+	stdioHelperEnvToolEnv = "GO_WANT_MCPX_STDIO_HELPER_ENV_TOOL"
+)
 
 func TestPoolStdioIntegrationListToolsAndCallTool(t *testing.T) {
 	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
@@ -66,6 +76,55 @@ func TestPoolStdioIntegrationListToolsAndCallTool(t *testing.T) {
 	}
 }
 
+func TestPoolStdioIntegrationAllowEnvFiltersInheritedEnvironment(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	t.Setenv("MCPX_TEST_ALLOWED_VAR", "visible")
+	t.Setenv("MCPX_TEST_SECRET_VAR", "hidden")
+
+	cfg := &config.Config{
+		Servers: map[string]config.ServerConfig{
+			"stdio": {
+				Command: os.Args[0],
+				Args:    []string{"-test.run=TestMCPXStdioHelperProcess", "--", "stdio-helper"},
+				Env: map[string]string{
+					stdioHelperEnv:        "1",
+					stdioHelperEnvToolEnv: "1",
+				},
+				AllowEnv: []string{"MCPX_TEST_ALLOWED_*"},
+			},
+		},
+	}
+
+	pool := New(cfg)
+	defer pool.CloseAll()
+
+	result, err := pool.CallTool(ctx, "stdio", "env_tool", json.RawMessage(`{}`))
+	if err != nil {
+		t.Fatalf("CallTool() error = %v", err)
+	}
+	text, ok := mcp.AsTextContent(result.Content[0])
+	if !ok {
+		t.Fatalf("Content[0] type = %T, want text", result.Content[0])
+	}
+	got := map[string]bool{}
+	for _, name := range strings.Split(text.Text, "\n") {
+		got[name] = true
+	}
+
+	for _, name := range []string{"MCPX_TEST_ALLOWED_VAR", stdioHelperEnv, stdioHelperEnvToolEnv} {
+		if !got[name] {
+			t.Fatalf("child env missing %s, got %q", name, text.Text)
+		}
+	}
+	for _, name := range []string{"MCPX_TEST_SECRET_VAR", "PATH"} {
+		if got[name] {
+			t.Fatalf("child env has %s, want it filtered by allow_env", name)
+		}
+	}
+}
+
 func TestPoolHTTPIntegrationListToolsCallToolAndHeaders(t *testing.T) {
 	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
 	defer cancel()
@@ -223,6 +282,52 @@ func TestMCPXStdioHelperProcess(t *testing.T) {
 		return mcp.NewToolResultStructuredOnly(map[string]any{"echo": query}), nil
 	})
 
+	if os.Getenv(stdioHelperNoisyEnv) == "1" {
+		s.AddTool(mcp.Tool{
+			Name:        "noisy_tool",
+			Description: "Logs to stderr",
+			InputSchema: mcp.ToolInputSchema{Type: "object"},
+		}, func(context.Context, mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			fmt.Fprintln(os.Stderr, "noisy: warming cache")
+			fmt.Fprintln(os.Stderr, "noisy: done")
+			return mcp.NewToolResultText("ok"), nil
+		})
+	}
+
+	if os.Getenv(stdioHelperEnvToolEnv) == "1" {
+		s.AddTool(mcp.Tool{
+			Name:        "env_tool",
+			Description: "Lists environment variable names",
+			InputSchema: mcp.ToolInputSchema{Type: "object"},
+		}, func(context.Context, mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			names := make([]string, 0, len(os.Environ()))
+			for _, kv := range os.Environ() {
+				name, _, _ := strings.Cut(kv, "=")
+				names = append(names, name)
+			}
+			return mcp.NewToolResultText(strings.Join(names, "\n")), nil
+		})
+	}
+
+	if dir := os.Getenv(stdioHelperBarrierEnv); dir != "" {
+		s.AddTool(mcp.Tool{
+			Name:        "barrier_tool",
+			Description: "Waits until two calls are in flight",
+			InputSchema: mcp.ToolInputSchema{Type: "object"},
+		}, func(context.Context, mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			marker := filepath.Join(dir, fmt.Sprintf("%d-%d", os.Getpid(), time.Now().UnixNano()))
+			if err := os.WriteFile(marker, nil, 0o600); err != nil {
+				return nil, err
+			}
+			for deadline := time.Now().Add(5 * time.Second); time.Now().Before(deadline); time.Sleep(10 * time.Millisecond) {
+				if entries, _ := os.ReadDir(dir); len(entries) >= 2 {
+					return mcp.NewToolResultText(fmt.Sprint(os.Getpid())), nil
+				}
+			}
+			return mcp.NewToolResultError("barrier timed out: calls did not overlap"), nil
+		})
+	}
+
 	if err := server.ServeStdio(s); err != nil {
 		fmt.Fprintf(os.Stderr, "serve stdio helper: %v\n", err)
 		os.Exit(1)
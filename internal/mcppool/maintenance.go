@@ -0,0 +1,156 @@
+package mcppool
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// connectionPingTimeout bounds each maintenance ping.
+var connectionPingTimeout = 10 * time.Second
+
+// maintenanceIdlePoll is how often the maintenance loop rereads the config
+// while neither connection_check_interval nor connection_max_idle is set.
+var maintenanceIdlePoll = 30 * time.Second
+
+// StartMaintenance runs connection health checks and idle eviction in the
+// background until CloseAll. Intervals come from the pool's current config
+// on every pass, so a reload takes effect without a restart. Calling it
+// again while it runs does nothing.
+func (p *Pool) StartMaintenance() {
+	p.mu.Lock()
+	if p.maintStop != nil {
+		p.mu.Unlock()
+		return
+	}
+	stop, done := make(chan struct{}), make(chan struct{})
+	p.maintStop, p.maintDone = stop, done
+	p.mu.Unlock()
+
+	go func() {
+		defer close(done)
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+		go func() {
+			<-stop
+			cancel()
+		}()
+
+		for {
+			every, maxIdle, ping := p.maintenanceSettings()
+			wait := every
+			if wait <= 0 {
+				wait = maintenanceIdlePoll
+			}
+			select {
+			case <-stop:
+				return
+			case <-time.After(wait):
+			}
+			if every > 0 {
+				p.sweepConnections(ctx, time.Now(), maxIdle, ping)
+			}
+		}
+	}()
+}
+
+func (p *Pool) stopMaintenance() {
+	p.mu.Lock()
+	stop, done := p.maintStop, p.maintDone
+	p.maintStop, p.maintDone = nil, nil
+	p.mu.Unlock()
+	if stop == nil {
+		return
+	}
+	close(stop)
+	<-done
+}
+
+// maintenanceSettings returns how often to sweep, the idle limit, and
+// whether sweeps ping. Without connection_check_interval, idle eviction
+// alone sweeps at half the idle limit.
+func (p *Pool) maintenanceSettings() (every, maxIdle time.Duration, ping bool) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.cfg == nil {
+		return 0, 0, false
+	}
+	if d, err := time.ParseDuration(p.cfg.ConnectionMaxIdle); err == nil && d > 0 {
+		maxIdle = d
+	}
+	if d, err := time.ParseDuration(p.cfg.ConnectionCheckInterval); err == nil && d > 0 {
+		return d, maxIdle, true
+	}
+	return maxIdle / 2, maxIdle, false
+}
+
+type pooledConn struct {
+	server string
+	conn   *connection
+}
+
+// sweepConnections closes connections idle for longer than maxIdle and, when
+// ping is set, pings the rest, dropping any that fail as if a request had.
+// Connections with a request in flight are left alone.
+func (p *Pool) sweepConnections(ctx context.Context, now time.Time, maxIdle time.Duration, ping bool) {
+	var idle, live []pooledConn
+	p.mu.Lock()
+	visit := func(server string, conn *connection) {
+		if conn.inflight > 0 || !conn.reqMu.TryLock() {
+			return
+		}
+		conn.reqMu.Unlock()
+		if maxIdle > 0 && now.Sub(conn.lastUsed) > maxIdle {
+			idle = append(idle, pooledConn{server, conn})
+		} else if ping {
+			live = append(live, pooledConn{server, conn})
+		}
+	}
+	for server, conn := range p.conns {
+		visit(server, conn)
+	}
+	for server, replicas := range p.replicas {
+		for _, conn := range replicas {
+			visit(server, conn)
+		}
+	}
+	for _, c := range idle {
+		if p.conns[c.server] == c.conn {
+			delete(p.conns, c.server)
+			p.markClosedLocked(c.server)
+		} else {
+			p.removeReplicaLocked(c.server, c.conn)
+		}
+	}
+	p.mu.Unlock()
+
+	for _, c := range idle {
+		closeConnection(c.conn)
+	}
+	for _, c := range live {
+		if err := pingConnection(ctx, c.conn); err != nil && ctx.Err() == nil {
+			p.invalidate(c.server, c.conn, fmt.Errorf("health check: %w", err))
+		}
+	}
+}
+
+// pingConnection sends an MCP ping, or lists tools on connections that
+// cannot ping. A connection busy with a request is taken to be alive.
+func pingConnection(ctx context.Context, conn *connection) error {
+	if !conn.reqMu.TryLock() {
+		return nil
+	}
+	defer conn.reqMu.Unlock()
+
+	ctx, cancel := context.WithTimeout(ctx, connectionPingTimeout)
+	defer cancel()
+	switch {
+	case conn.ping != nil:
+		return conn.ping(ctx)
+	case conn.listTools != nil:
+		_, err := conn.listTools(ctx)
+		return err
+	default:
+		return nil
+	}
+}
@@ -0,0 +1,116 @@
+package mcppool
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/lydakis/mcpx/internal/config"
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+func TestSweepConnectionsEvictsDeadConnections(t *testing.T) {
+	var deadClosed, liveClosed atomic.Bool
+	dead := &connection{
+		ping:  func(context.Context) error { return errors.New("broken pipe") },
+		close: func() error { deadClosed.Store(true); return nil },
+	}
+	live := &connection{
+		ping:  func(context.Context) error { return nil },
+		close: func() error { liveClosed.Store(true); return nil },
+	}
+	now := time.Now()
+	dead.lastUsed, live.lastUsed = now, now
+
+	pool := New(&config.Config{})
+	pool.conns["dead"], pool.conns["live"] = dead, live
+
+	pool.sweepConnections(context.Background(), now, 0, true)
+
+	pool.mu.Lock()
+	_, hasDead := pool.conns["dead"]
+	_, hasLive := pool.conns["live"]
+	pool.mu.Unlock()
+	if hasDead || !hasLive {
+		t.Fatalf("open = dead:%v live:%v, want only live", hasDead, hasLive)
+	}
+	if !deadClosed.Load() || liveClosed.Load() {
+		t.Fatalf("closed = dead:%v live:%v, want only dead closed", deadClosed.Load(), liveClosed.Load())
+	}
+	if got := pool.State("dead"); got != ServerStateFailed {
+		t.Fatalf("State(dead) = %q, want %q", got, ServerStateFailed)
+	}
+	if last, ok := pool.LastError("dead"); !ok || last.Message != "health check: broken pipe" {
+		t.Fatalf("LastError(dead) = (%+v, %v), want health check failure", last, ok)
+	}
+}
+
+func TestSweepConnectionsEvictsIdleConnectionsAndSparesBusyOnes(t *testing.T) {
+	now := time.Now()
+	idle := &connection{lastUsed: now.Add(-time.Hour), close: func() error { return nil }}
+	recent := &connection{lastUsed: now.Add(-time.Minute), close: func() error { return nil }}
+	busy := &connection{lastUsed: now.Add(-time.Hour), inflight: 1, close: func() error { return nil }}
+	replica := &connection{lastUsed: now.Add(-time.Hour), stdio: true, close: func() error { return nil }}
+
+	pool := New(&config.Config{})
+	pool.conns["idle"], pool.conns["recent"], pool.conns["busy"] = idle, recent, busy
+	pool.conns["pooled"] = recent
+	pool.replicas["pooled"] = []*connection{replica}
+
+	pool.sweepConnections(context.Background(), now, 10*time.Minute, false)
+
+	pool.mu.Lock()
+	_, hasIdle := pool.conns["idle"]
+	_, hasRecent := pool.conns["recent"]
+	_, hasBusy := pool.conns["busy"]
+	replicas := len(pool.replicas["pooled"])
+	pool.mu.Unlock()
+	if hasIdle || !hasRecent || !hasBusy || replicas != 0 {
+		t.Fatalf("open = idle:%v recent:%v busy:%v replicas:%d, want recent and busy only", hasIdle, hasRecent, hasBusy, replicas)
+	}
+	if got := pool.State("idle"); got != ServerStateIdle {
+		t.Fatalf("State(idle) = %q, want %q", got, ServerStateIdle)
+	}
+}
+
+func TestSweepConnectionsFallsBackToListTools(t *testing.T) {
+	conn := &connection{lastUsed: time.Now(), close: func() error { return nil }}
+	conn.listTools = func(context.Context) ([]mcp.Tool, error) { return nil, errors.New("EOF") }
+
+	pool := New(&config.Config{})
+	pool.conns["github"] = conn
+	pool.sweepConnections(context.Background(), time.Now(), 0, true)
+
+	if got := pool.State("github"); got != ServerStateFailed {
+		t.Fatalf("State(github) = %q, want %q", got, ServerStateFailed)
+	}
+}
+
+func TestStartMaintenancePingsOnIntervalAndStopsOnCloseAll(t *testing.T) {
+	var pings atomic.Int32
+	conn := &connection{
+		lastUsed: time.Now(),
+		ping:     func(context.Context) error { pings.Add(1); return nil },
+		close:    func() error { return nil },
+	}
+	pool := New(&config.Config{ConnectionCheckInterval: "5ms"})
+	pool.conns["github"] = conn
+
+	pool.StartMaintenance()
+	deadline := time.Now().Add(5 * time.Second)
+	for pings.Load() < 2 {
+		if time.Now().After(deadline) {
+			t.Fatalf("pings = %d, want at least 2", pings.Load())
+		}
+		time.Sleep(time.Millisecond)
+	}
+
+	pool.CloseAll()
+	after := pings.Load()
+	time.Sleep(20 * time.Millisecond)
+	if got := pings.Load(); got != after {
+		t.Fatalf("pings after CloseAll = %d, want %d", got, after)
+	}
+}
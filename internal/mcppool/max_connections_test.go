@@ -0,0 +1,166 @@
+package mcppool
+
+import (
+	"context"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/lydakis/mcpx/internal/config"
+)
+
+func stdioHelperServer() config.ServerConfig {
+	return config.ServerConfig{
+		Command: os.Args[0],
+		Args:    []string{"-test.run=TestMCPXStdioHelperProcess", "--", "stdio-helper"},
+		Env:     map[string]string{stdioHelperEnv: "1"},
+	}
+}
+
+func TestPoolMaxConnectionsEvictsLeastRecentlyUsedIdleConnection(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	cfg := &config.Config{
+		MaxConnections: 2,
+		Servers: map[string]config.ServerConfig{
+			"a": stdioHelperServer(),
+			"b": stdioHelperServer(),
+			"c": stdioHelperServer(),
+		},
+	}
+	pool := New(cfg)
+	defer pool.CloseAll()
+
+	for _, server := range []string{"a", "b", "a", "c"} {
+		if _, err := pool.ListTools(ctx, server); err != nil {
+			t.Fatalf("ListTools(%s) error = %v", server, err)
+		}
+	}
+
+	pool.mu.Lock()
+	open := len(pool.conns)
+	_, hasA := pool.conns["a"]
+	_, hasB := pool.conns["b"]
+	pool.mu.Unlock()
+	if open != 2 {
+		t.Fatalf("open connections = %d, want 2", open)
+	}
+	if !hasA || hasB {
+		t.Fatalf("open = a:%v b:%v, want b (least recently used) evicted", hasA, hasB)
+	}
+	if got := pool.State("b"); got != ServerStateIdle {
+		t.Fatalf("State(b) = %q, want %q", got, ServerStateIdle)
+	}
+}
+
+func TestPoolMaxConnectionsRefusesWhenEveryConnectionIsBusy(t *testing.T) {
+	busy := &connection{stdio: true, close: func() error { return nil }}
+	busy.reqMu.Lock()
+	defer busy.reqMu.Unlock()
+
+	pool := New(&config.Config{
+		MaxConnections: 1,
+		Servers:        map[string]config.ServerConfig{"b": stdioHelperServer()},
+	})
+	pool.conns["a"] = busy
+
+	_, err := pool.ListTools(context.Background(), "b")
+	if err == nil || !strings.Contains(err.Error(), "max_connections") {
+		t.Fatalf("ListTools(b) error = %v, want max_connections error", err)
+	}
+	if pool.conns["a"] != busy {
+		t.Fatal("busy connection was evicted")
+	}
+}
+
+func TestPoolMaxConnectionsKeepsConnectionClaimedByGetOrCreate(t *testing.T) {
+	idle := &connection{stdio: true, close: func() error { return nil }}
+	pool := New(&config.Config{
+		MaxConnections: 1,
+		Servers:        map[string]config.ServerConfig{"a": {Command: "unused"}},
+	})
+	pool.conns["a"] = idle
+
+	conn, release, err := pool.getOrCreate(context.Background(), "a")
+	if err != nil {
+		t.Fatalf("getOrCreate(a) error = %v", err)
+	}
+	if conn != idle {
+		t.Fatal("getOrCreate(a) returned a new connection, want the open one")
+	}
+
+	pool.mu.Lock()
+	_, err = pool.makeRoomLocked()
+	pool.mu.Unlock()
+	if err == nil || pool.conns["a"] != idle {
+		t.Fatalf("makeRoomLocked() error = %v, want the claimed connection kept", err)
+	}
+
+	release()
+	pool.mu.Lock()
+	evicted, err := pool.makeRoomLocked()
+	pool.mu.Unlock()
+	if err != nil || pool.conns["a"] != nil || len(evicted) != 1 || evicted[0] != idle {
+		t.Fatalf("makeRoomLocked() after release error = %v, want the idle connection evicted", err)
+	}
+}
+
+func TestPoolMaxConnectionsClosesEvictedConnectionOutsidePoolLock(t *testing.T) {
+	closing := make(chan struct{})
+	finishClose := make(chan struct{})
+	idle := &connection{stdio: true, close: func() error {
+		close(closing)
+		<-finishClose
+		return nil
+	}}
+	other := &connection{close: func() error { return nil }}
+	connectServer = func(context.Context, config.ServerConfig) (*connection, error) {
+		return &connection{close: func() error { return nil }}, nil
+	}
+	defer func() { connectServer = defaultConnectServer }()
+
+	pool := New(&config.Config{
+		MaxConnections: 1,
+		Servers: map[string]config.ServerConfig{
+			"a":    {Command: "unused"},
+			"b":    {Command: "unused"},
+			"http": {URL: "https://example.com/mcp"},
+		},
+	})
+	pool.conns["a"] = idle
+	pool.conns["http"] = other
+
+	evictDone := make(chan error, 1)
+	go func() {
+		_, release, err := pool.getOrCreate(context.Background(), "b")
+		if err == nil {
+			release()
+		}
+		evictDone <- err
+	}()
+	<-closing
+
+	acquired := make(chan error, 1)
+	go func() {
+		_, release, err := pool.getOrCreate(context.Background(), "http")
+		if err == nil {
+			release()
+		}
+		acquired <- err
+	}()
+	select {
+	case err := <-acquired:
+		if err != nil {
+			t.Fatalf("getOrCreate(http) error = %v", err)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("getOrCreate(http) blocked while the evicted connection was closing")
+	}
+
+	close(finishClose)
+	if err := <-evictDone; err != nil {
+		t.Fatalf("getOrCreate(b) error = %v", err)
+	}
+}
@@ -5,6 +5,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"sync"
+	"time"
 
 	"github.com/lydakis/mcpx/internal/config"
 	"github.com/mark3labs/mcp-go/mcp"
@@ -16,74 +17,300 @@ type ToolInfo struct {
 	Description  string
 	InputSchema  json.RawMessage
 	OutputSchema json.RawMessage
+	Annotations  ToolAnnotations
 	parsedInput  map[string]any
 }
 
+// ToolAnnotations holds the behavior hints a server declares for a tool.
+// A nil hint was not declared; callers should not assume the MCP default.
+type ToolAnnotations struct {
+	ReadOnly    *bool
+	Destructive *bool
+	Idempotent  *bool
+	OpenWorld   *bool
+}
+
+// ResourceInfo is a simplified resource descriptor returned by ListResources.
+type ResourceInfo struct {
+	URI         string
+	Name        string
+	Description string
+	MIMEType    string
+}
+
+// PromptInfo is a simplified prompt descriptor returned by ListPrompts.
+type PromptInfo struct {
+	Name        string
+	Description string
+	Arguments   []PromptArgumentInfo
+}
+
+// PromptArgumentInfo describes one templating argument accepted by a prompt.
+type PromptArgumentInfo struct {
+	Name        string
+	Description string
+	Required    bool
+}
+
 // connection wraps an MCP client with its transport.
 type connection struct {
-	listTools func(ctx context.Context) ([]mcp.Tool, error)
-	callTool  func(ctx context.Context, name string, args map[string]any) (*mcp.CallToolResult, error)
-	close     func() error
-	reqMu     sync.Mutex
-	toolMu    sync.RWMutex
-	toolIndex map[string]ToolInfo
-	toolList  []ToolInfo
-	indexed   bool
-	indexMu   sync.Mutex
+	listTools     func(ctx context.Context) ([]mcp.Tool, error)
+	callTool      func(ctx context.Context, name string, args map[string]any) (*mcp.CallToolResult, error)
+	listResources func(ctx context.Context) ([]mcp.Resource, error)
+	listPrompts   func(ctx context.Context) ([]mcp.Prompt, error)
+	getPrompt     func(ctx context.Context, name string, args map[string]string) (*mcp.GetPromptResult, error)
+	ping          func(ctx context.Context) error
+	close         func() error
+	stderr        *stderrTap // stdio only
+	stdio         bool
+	lastUsed      time.Time // guarded by Pool.mu
+	inflight      int       // requests using this connection; guarded by Pool.mu
+	reqMu         sync.Mutex
+	toolMu        sync.RWMutex
+	toolIndex     map[string]ToolInfo
+	toolList      []ToolInfo
+	indexed       bool
+	indexMu       sync.Mutex
 }
 
 // Pool manages MCP server connections, creating them on demand.
 type Pool struct {
-	cfg   *config.Config
-	mu    sync.Mutex
-	conns map[string]*connection
+	cfg        *config.Config
+	mu         sync.Mutex
+	conns      map[string]*connection
+	states     map[string]ServerState
+	lastErrors map[string]LastError
+	// replicas holds the extra stdio connections of servers with
+	// pool_size > 1. They serve only tool calls; conns stays the primary
+	// connection for tool listing and everything else.
+	replicas map[string][]*connection
+	// replicaSpawns counts replicas being spawned outside p.mu, so
+	// max_connections and pool_size account for them before they land.
+	replicaSpawns map[string]int
+	// connecting holds a placeholder per server whose primary connection
+	// is being dialed outside p.mu.
+	connecting map[string]*pendingConn
+	// maintStop and maintDone control the StartMaintenance goroutine.
+	maintStop chan struct{}
+	maintDone chan struct{}
 }
 
 // New creates a new connection pool.
 func New(cfg *config.Config) *Pool {
 	return &Pool{
-		cfg:   cfg,
-		conns: make(map[string]*connection),
+		cfg:        cfg,
+		conns:      make(map[string]*connection),
+		states:     make(map[string]ServerState),
+		lastErrors: make(map[string]LastError),
+		replicas:   make(map[string][]*connection),
 	}
 }
 
-func (p *Pool) getOrCreate(ctx context.Context, server string) (*connection, error) {
-	p.mu.Lock()
-	defer p.mu.Unlock()
+// getOrCreate returns server's primary connection, connecting first if
+// needed, with a request already claimed on it. The claim is taken under the
+// same lock that found or published the connection so max_connections
+// eviction can never close it before the caller's request starts. Callers
+// must call release when the request ends.
+//
+// Connecting and the health check run with p.mu released behind a
+// per-server placeholder in p.connecting, so a slow or failing server does
+// not stall calls to other servers; concurrent callers for the same server
+// wait for the placeholder and then look again. The connection is published
+// only once its health check passes.
+func (p *Pool) getOrCreate(ctx context.Context, server string) (*connection, func(), error) {
+	for {
+		p.mu.Lock()
+		if conn, ok := p.conns[server]; ok {
+			release := p.claimLocked(conn)
+			p.mu.Unlock()
+			return conn, release, nil
+		}
+		if pending, ok := p.connecting[server]; ok {
+			p.mu.Unlock()
+			select {
+			case <-pending.done:
+				continue
+			case <-ctx.Done():
+				return nil, nil, fmt.Errorf("connecting to %s: %w", server, ctx.Err())
+			}
+		}
 
-	if conn, ok := p.conns[server]; ok {
-		return conn, nil
-	}
+		scfg, ok := p.cfg.Servers[server]
+		if !ok {
+			p.mu.Unlock()
+			return nil, nil, fmt.Errorf("unknown server: %s", server)
+		}
+		var evicted []*connection
+		if scfg.IsStdio() {
+			var err error
+			evicted, err = p.makeRoomLocked()
+			if err != nil {
+				p.mu.Unlock()
+				closeConnections(evicted)
+				return nil, nil, fmt.Errorf("connecting to %s: %w", server, err)
+			}
+		} else if !scfg.IsHTTP() {
+			p.mu.Unlock()
+			return nil, nil, fmt.Errorf("server %s: no command or url configured", server)
+		}
 
-	scfg, ok := p.cfg.Servers[server]
-	if !ok {
-		return nil, fmt.Errorf("unknown server: %s", server)
+		pending := &pendingConn{done: make(chan struct{}), stdio: scfg.IsStdio()}
+		if p.connecting == nil {
+			p.connecting = make(map[string]*pendingConn)
+		}
+		p.connecting[server] = pending
+		p.mu.Unlock()
+		closeConnections(evicted)
+
+		conn, err := dial(ctx, scfg)
+
+		p.mu.Lock()
+		current := p.connecting[server] == pending
+		if current {
+			delete(p.connecting, server)
+		}
+		close(pending.done)
+		if err != nil {
+			err = fmt.Errorf("connecting to %s: %w", server, err)
+			if current {
+				p.recordFailureLocked(server, err)
+			}
+			p.mu.Unlock()
+			return nil, nil, err
+		}
+		if !current {
+			// The server was closed or the pool reset while dialing.
+			p.mu.Unlock()
+			closeConnection(conn)
+			return nil, nil, fmt.Errorf("connecting to %s: closed while connecting", server)
+		}
+
+		conn.stdio = scfg.IsStdio()
+		p.conns[server] = conn
+		p.recordStateLocked(server, ServerStateConnected)
+		delete(p.lastErrors, server)
+		release := p.claimLocked(conn)
+		p.mu.Unlock()
+		return conn, release, nil
 	}
+}
+
+// pendingConn stands in for a primary connection being dialed outside p.mu.
+type pendingConn struct {
+	done  chan struct{} // closed once the dial finishes, either way
+	stdio bool
+}
 
-	var conn *connection
-	var err error
+// connectServer opens a transport connection to a configured server. Tests
+// replace it to stand in for slow or failing servers.
+var connectServer = defaultConnectServer
 
+func defaultConnectServer(ctx context.Context, scfg config.ServerConfig) (*connection, error) {
 	if scfg.IsStdio() {
-		conn, err = connectStdio(ctx, scfg)
-	} else if scfg.IsHTTP() {
-		conn, err = connectHTTP(ctx, scfg)
-	} else {
-		return nil, fmt.Errorf("server %s: no command or url configured", server)
+		return connectStdio(ctx, scfg)
 	}
+	return connectHTTP(ctx, scfg)
+}
 
+// dial connects to a configured server and waits for its health check to
+// pass. It does not touch the pool, so callers run it without p.mu.
+func dial(ctx context.Context, scfg config.ServerConfig) (*connection, error) {
+	conn, err := connectServer(ctx, scfg)
 	if err != nil {
-		return nil, fmt.Errorf("connecting to %s: %w", server, err)
+		return nil, err
+	}
+	if err := probeHealth(ctx, conn, scfg); err != nil {
+		closeConnection(conn)
+		return nil, err
 	}
-
-	p.conns[server] = conn
 	return conn, nil
 }
 
-func (p *Pool) invalidate(server string, conn *connection) {
+// claimLocked marks one more request in flight on conn and returns the func
+// that ends it. Callers must hold p.mu.
+func (p *Pool) claimLocked(conn *connection) func() {
+	conn.inflight++
+	conn.lastUsed = time.Now()
+	var once sync.Once
+	return func() {
+		once.Do(func() {
+			p.mu.Lock()
+			conn.inflight--
+			p.mu.Unlock()
+		})
+	}
+}
+
+// makeRoomLocked enforces max_connections before a new stdio process is
+// spawned, closing the least recently used idle stdio connection when the
+// pool is full. Connections with a request in flight are never evicted.
+// Callers must hold p.mu and close the returned connections after releasing
+// it, since closing a stdio server can wait on the process to exit.
+func (p *Pool) makeRoomLocked() ([]*connection, error) {
+	limit := p.cfg.MaxConnections
+	if limit <= 0 {
+		return nil, nil
+	}
+
+	var evicted []*connection
+	for {
+		open := 0
+		victimServer := ""
+		var victim *connection
+		consider := func(name string, conn *connection) {
+			if !conn.stdio {
+				return
+			}
+			open++
+			if conn.inflight > 0 || !conn.reqMu.TryLock() {
+				return
+			}
+			conn.reqMu.Unlock()
+			if victim == nil || conn.lastUsed.Before(victim.lastUsed) {
+				victimServer, victim = name, conn
+			}
+		}
+		for name, conn := range p.conns {
+			consider(name, conn)
+		}
+		for name, replicas := range p.replicas {
+			for _, conn := range replicas {
+				consider(name, conn)
+			}
+		}
+		for _, n := range p.replicaSpawns {
+			open += n
+		}
+		for _, pending := range p.connecting {
+			if pending.stdio {
+				open++
+			}
+		}
+		if open < limit {
+			return evicted, nil
+		}
+		if victim == nil {
+			return evicted, fmt.Errorf("max_connections (%d) reached and every connection is busy", limit)
+		}
+
+		if p.conns[victimServer] == victim {
+			delete(p.conns, victimServer)
+			p.markClosedLocked(victimServer)
+		} else {
+			p.removeReplicaLocked(victimServer, victim)
+		}
+		evicted = append(evicted, victim)
+	}
+}
+
+func (p *Pool) invalidate(server string, conn *connection, cause error) {
 	shouldClose := false
 	p.mu.Lock()
 	if current, ok := p.conns[server]; ok && current == conn {
 		delete(p.conns, server)
+		p.recordFailureLocked(server, cause)
+		shouldClose = true
+	} else if p.removeReplicaLocked(server, conn) {
 		shouldClose = true
 	}
 	p.mu.Unlock()
@@ -95,10 +322,11 @@ func (p *Pool) invalidate(server string, conn *connection) {
 
 // ListTools returns the tools available on a server.
 func (p *Pool) ListTools(ctx context.Context, server string) ([]ToolInfo, error) {
-	conn, err := p.getOrCreate(ctx, server)
+	conn, release, err := p.getOrCreate(ctx, server)
 	if err != nil {
 		return nil, err
 	}
+	defer release()
 
 	if infos, found := cachedToolInfos(conn); found {
 		return infos, nil
@@ -113,7 +341,7 @@ func (p *Pool) ListTools(ctx context.Context, server string) ([]ToolInfo, error)
 
 	tools, err := runListTools(conn, ctx)
 	if err != nil {
-		p.invalidate(server, conn)
+		p.invalidate(server, conn, err)
 		return nil, err
 	}
 
@@ -125,6 +353,123 @@ func (p *Pool) ListTools(ctx context.Context, server string) ([]ToolInfo, error)
 	return infos, nil
 }
 
+// ListResources returns the resources available on a server.
+func (p *Pool) ListResources(ctx context.Context, server string) ([]ResourceInfo, error) {
+	conn, release, err := p.getOrCreate(ctx, server)
+	if err != nil {
+		return nil, err
+	}
+	defer release()
+
+	resources, err := runListResources(conn, ctx)
+	if err != nil {
+		p.invalidate(server, conn, err)
+		return nil, err
+	}
+
+	infos := make([]ResourceInfo, 0, len(resources))
+	for _, r := range resources {
+		infos = append(infos, ResourceInfo{
+			URI:         r.URI,
+			Name:        r.Name,
+			Description: r.Description,
+			MIMEType:    r.MIMEType,
+		})
+	}
+	return infos, nil
+}
+
+// ListPrompts returns the prompts available on a server.
+func (p *Pool) ListPrompts(ctx context.Context, server string) ([]PromptInfo, error) {
+	conn, release, err := p.getOrCreate(ctx, server)
+	if err != nil {
+		return nil, err
+	}
+	defer release()
+
+	prompts, err := runListPrompts(conn, ctx)
+	if err != nil {
+		p.invalidate(server, conn, err)
+		return nil, err
+	}
+
+	infos := make([]PromptInfo, 0, len(prompts))
+	for _, pr := range prompts {
+		var args []PromptArgumentInfo
+		for _, arg := range pr.Arguments {
+			args = append(args, PromptArgumentInfo{
+				Name:        arg.Name,
+				Description: arg.Description,
+				Required:    arg.Required,
+			})
+		}
+		infos = append(infos, PromptInfo{
+			Name:        pr.Name,
+			Description: pr.Description,
+			Arguments:   args,
+		})
+	}
+	return infos, nil
+}
+
+// GetPrompt renders a prompt template on a server with the given arguments.
+// MCP prompt arguments are strings, so non-string JSON values are passed in
+// their JSON-encoded form.
+func (p *Pool) GetPrompt(ctx context.Context, server, name string, argsJSON json.RawMessage) (*mcp.GetPromptResult, error) {
+	if name == "" {
+		return nil, fmt.Errorf("prompt name is required")
+	}
+
+	args, err := compilePromptArgs(argsJSON)
+	if err != nil {
+		return nil, err
+	}
+
+	conn, release, err := p.getOrCreate(ctx, server)
+	if err != nil {
+		return nil, err
+	}
+	defer release()
+
+	result, err := runGetPrompt(conn, ctx, name, args)
+	if err != nil {
+		p.invalidate(server, conn, err)
+		return nil, err
+	}
+	return result, nil
+}
+
+func compilePromptArgs(argsJSON json.RawMessage) (map[string]string, error) {
+	if len(argsJSON) == 0 {
+		return nil, nil
+	}
+
+	var raw map[string]any
+	if err := json.Unmarshal(argsJSON, &raw); err != nil {
+		return nil, fmt.Errorf("invalid args: %w", err)
+	}
+	if len(raw) == 0 {
+		return nil, nil
+	}
+
+	args := make(map[string]string, len(raw))
+	for key, value := range raw {
+		switch v := value.(type) {
+		case string:
+			args[key] = v
+		case nil:
+			continue
+		default:
+			encoded, err := json.Marshal(v)
+			if err != nil {
+				return nil, fmt.Errorf("invalid args: %s: %w", key, err)
+			}
+			args[key] = string(encoded)
+		}
+	}
+	return args, nil
+}
+
 // ToolSchema returns the input schema for a specific tool.
 func (p *Pool) ToolSchema(ctx context.Context, server, tool string) (json.RawMessage, error) {
 	info, err := p.ToolInfoByName(ctx, server, tool)
@@ -136,10 +481,11 @@ func (p *Pool) ToolSchema(ctx context.Context, server, tool string) (json.RawMes
 
 // ToolInfoByName returns metadata and schemas for a specific tool.
 func (p *Pool) ToolInfoByName(ctx context.Context, server, tool string) (*ToolInfo, error) {
-	conn, err := p.getOrCreate(ctx, server)
+	conn, release, err := p.getOrCreate(ctx, server)
 	if err != nil {
 		return nil, err
 	}
+	defer release()
 
 	if info, found, _ := cachedToolInfo(conn, tool); found {
 		return info, nil
@@ -154,7 +500,7 @@ func (p *Pool) ToolInfoByName(ctx context.Context, server, tool string) (*ToolIn
 
 	tools, err := runListTools(conn, ctx)
 	if err != nil {
-		p.invalidate(server, conn)
+		p.invalidate(server, conn, err)
 		return nil, err
 	}
 	cacheToolInfos(conn, buildToolInfos(tools))
@@ -175,7 +521,13 @@ func buildToolInfos(tools []mcp.Tool) []ToolInfo {
 			Description:  t.Description,
 			InputSchema:  inputSchema,
 			OutputSchema: outputSchema,
-			parsedInput:  parseInputSchema(inputSchema),
+			Annotations: ToolAnnotations{
+				ReadOnly:    t.Annotations.ReadOnlyHint,
+				Destructive: t.Annotations.DestructiveHint,
+				Idempotent:  t.Annotations.IdempotentHint,
+				OpenWorld:   t.Annotations.OpenWorldHint,
+			},
+			parsedInput: parseInputSchema(inputSchema),
 		}
 	}
 	return infos
@@ -271,25 +623,36 @@ func cacheToolInfos(conn *connection, infos []ToolInfo) {
 	conn.toolMu.Unlock()
 }
 
-// CallToolWithInfo invokes a resolved tool on a server.
-func (p *Pool) CallToolWithInfo(ctx context.Context, server string, info *ToolInfo, argsJSON json.RawMessage) (*mcp.CallToolResult, error) {
+// CallToolWithInfo invokes a resolved tool on a server. A non-nil progress
+// asks the server for progress notifications and receives each one as it
+// arrives; servers that send none just return the result.
+func (p *Pool) CallToolWithInfo(ctx context.Context, server string, info *ToolInfo, argsJSON json.RawMessage, progress func(Progress)) (*mcp.CallToolResult, error) {
 	if info == nil || info.Name == "" {
 		return nil, fmt.Errorf("tool info is required")
 	}
+	if progress != nil {
+		ctx = withProgress(ctx, progress)
+	}
 
-	conn, err := p.getOrCreate(ctx, server)
+	conn, release, err := p.acquireCallConn(ctx, server)
 	if err != nil {
 		return nil, err
 	}
+	defer release()
 
 	args, err := compileJSONArgs(argsJSON, info.InputSchema, info.parsedInput)
 	if err != nil {
 		return nil, err
 	}
 
+	ctx, retryAfter := withRetryAfterHint(ctx)
 	result, err := runCallTool(conn, ctx, info.Name, args)
 	if err != nil {
-		p.invalidate(server, conn)
+		err = retryAfter.wrap(err)
+		// A rate-limited call says nothing about the connection's health.
+		if _, limited := RetryAfter(err); !limited {
+			p.invalidate(server, conn, err)
+		}
 		return nil, err
 	}
 	return result, nil
@@ -301,7 +664,7 @@ func (p *Pool) CallTool(ctx context.Context, server, tool string, argsJSON json.
 	if err != nil {
 		return nil, err
 	}
-	return p.CallToolWithInfo(ctx, server, info, argsJSON)
+	return p.CallToolWithInfo(ctx, server, info, argsJSON, nil)
 }
 
 func compileJSONArgs(argsJSON json.RawMessage, toolSchema json.RawMessage, parsedSchema map[string]any) (map[string]any, error) {
@@ -329,9 +692,36 @@ func runListTools(conn *connection, ctx context.Context) ([]mcp.Tool, error) {
 func runCallTool(conn *connection, ctx context.Context, name string, args map[string]any) (*mcp.CallToolResult, error) {
 	conn.reqMu.Lock()
 	defer conn.reqMu.Unlock()
+	if sink := stderrCaptureFrom(ctx); sink != nil {
+		defer conn.stderr.attach(sink)()
+	}
 	return conn.callTool(ctx, name, args)
 }
 
+func runListResources(conn *connection, ctx context.Context) ([]mcp.Resource, error) {
+	conn.reqMu.Lock()
+	defer conn.reqMu.Unlock()
+	return conn.listResources(ctx)
+}
+
+func runListPrompts(conn *connection, ctx context.Context) ([]mcp.Prompt, error) {
+	conn.reqMu.Lock()
+	defer conn.reqMu.Unlock()
+	return conn.listPrompts(ctx)
+}
+
+func runGetPrompt(conn *connection, ctx context.Context, name string, args map[string]string) (*mcp.GetPromptResult, error) {
+	conn.reqMu.Lock()
+	defer conn.reqMu.Unlock()
+	return conn.getPrompt(ctx, name, args)
+}
+
+func closeConnections(conns []*connection) {
+	for _, conn := range conns {
+		closeConnection(conn)
+	}
+}
+
 func closeConnection(conn *connection) {
 	if conn == nil || conn.close == nil {
 		return
@@ -387,24 +777,39 @@ func (p *Pool) Close(server string) {
 	conn, ok := p.conns[server]
 	if ok {
 		delete(p.conns, server)
+		p.markClosedLocked(server)
 	}
+	replicas := p.replicas[server]
+	delete(p.replicas, server)
+	delete(p.connecting, server)
 	p.mu.Unlock()
 
 	if ok {
 		closeConnection(conn)
 	}
+	for _, replica := range replicas {
+		closeConnection(replica)
+	}
 }
 
-// CloseAll disconnects all servers.
+// CloseAll stops connection maintenance and disconnects all servers.
 func (p *Pool) CloseAll() {
+	p.stopMaintenance()
 	p.mu.Lock()
 	conns := p.conns
 	p.conns = make(map[string]*connection)
+	for server := range conns {
+		p.markClosedLocked(server)
+	}
+	replicas := p.replicas
+	p.replicas = make(map[string][]*connection)
+	p.connecting = nil
 	p.mu.Unlock()
 
 	for _, conn := range conns {
 		closeConnection(conn)
 	}
+	closeReplicas(replicas)
 }
 
 // Reset swaps the underlying config and drops all active connections.
@@ -416,10 +821,17 @@ func (p *Pool) Reset(cfg *config.Config) {
 	p.mu.Lock()
 	conns := p.conns
 	p.conns = make(map[string]*connection)
+	for server := range conns {
+		p.markClosedLocked(server)
+	}
+	replicas := p.replicas
+	p.replicas = make(map[string][]*connection)
+	p.connecting = nil
 	p.cfg = cfg
 	p.mu.Unlock()
 
 	for _, conn := range conns {
 		closeConnection(conn)
 	}
+	closeReplicas(replicas)
 }
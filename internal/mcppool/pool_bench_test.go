@@ -133,7 +133,7 @@ func BenchmarkCallToolWithInfo(b *testing.B) {
 	b.ReportAllocs()
 	b.ResetTimer()
 	for i := 0; i < b.N; i++ {
-		if _, err := p.CallToolWithInfo(ctx, "bench", info, args); err != nil {
+		if _, err := p.CallToolWithInfo(ctx, "bench", info, args, nil); err != nil {
 			b.Fatalf("CallToolWithInfo() error = %v", err)
 		}
 	}
@@ -0,0 +1,140 @@
+package mcppool
+
+import (
+	"context"
+
+	"github.com/lydakis/mcpx/internal/config"
+)
+
+// acquireCallConn returns the connection a tool call should run on and a
+// release func the caller must call when the call ends. A stdio server with
+// pool_size > 1 routes the call to its least busy process, spawning another
+// (up to pool_size, within max_connections) when every process has a call in
+// flight. If spawning fails the call waits on the least busy process instead.
+func (p *Pool) acquireCallConn(ctx context.Context, server string) (*connection, func(), error) {
+	primary, releasePrimary, err := p.getOrCreate(ctx, server)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	p.mu.Lock()
+	scfg := p.cfg.Servers[server]
+	size := scfg.EffectivePoolSize()
+	if size <= 1 || !primary.stdio {
+		p.mu.Unlock()
+		return primary, releasePrimary, nil
+	}
+
+	// Connections to close once p.mu is released.
+	var closing []*connection
+	defer func() { closeConnections(closing) }()
+
+	conn := p.leastBusyLocked(server, primary)
+	if callLoad(conn, primary) > 0 && 1+len(p.replicas[server])+p.replicaSpawns[server] < size {
+		evicted, err := p.makeRoomLocked()
+		if err != nil {
+			closing = evicted
+		} else if replica, orphan := p.spawnReplicaLocked(ctx, server, primary, scfg, evicted); replica != nil {
+			conn = replica
+		} else {
+			closing = append(closing, orphan)
+			conn = p.leastBusyLocked(server, primary)
+		}
+	}
+
+	if conn == primary {
+		p.mu.Unlock()
+		return primary, releasePrimary, nil
+	}
+	// Move the claim getOrCreate took on the primary over to conn.
+	primary.inflight--
+	release := p.claimLocked(conn)
+	p.mu.Unlock()
+	return conn, release, nil
+}
+
+// leastBusyLocked returns whichever of server's primary and replicas has the
+// fewest calls in flight, preferring the primary on ties. Callers must hold
+// p.mu and a claim on primary.
+func (p *Pool) leastBusyLocked(server string, primary *connection) *connection {
+	conn := primary
+	for _, replica := range p.replicas[server] {
+		if callLoad(replica, primary) < callLoad(conn, primary) {
+			conn = replica
+		}
+	}
+	return conn
+}
+
+// callLoad reports the calls in flight on conn other than the caller's own
+// claim on primary.
+func callLoad(conn, primary *connection) int {
+	if conn == primary {
+		return conn.inflight - 1
+	}
+	return conn.inflight
+}
+
+// spawnReplicaLocked starts one more stdio process for server and adds it to
+// the replicas. The spawn and health check run with p.mu released, counted in
+// replicaSpawns so concurrent callers and max_connections see it; the replica
+// is dropped if primary stopped being server's connection meanwhile (closed,
+// reset or invalidated). evicted are the connections makeRoomLocked freed
+// for it, closed once p.mu is released. It returns the added replica, or nil
+// and the dropped connection (if any), which the caller must close after
+// releasing p.mu. Callers must hold p.mu, which is held again on return.
+func (p *Pool) spawnReplicaLocked(ctx context.Context, server string, primary *connection, scfg config.ServerConfig, evicted []*connection) (replica, orphan *connection) {
+	if p.replicaSpawns == nil {
+		p.replicaSpawns = make(map[string]int)
+	}
+	p.replicaSpawns[server]++
+	p.mu.Unlock()
+	closeConnections(evicted)
+
+	conn, err := dial(ctx, scfg)
+
+	p.mu.Lock()
+	if p.replicaSpawns[server]--; p.replicaSpawns[server] <= 0 {
+		delete(p.replicaSpawns, server)
+	}
+	if err != nil {
+		return nil, nil
+	}
+	if p.conns[server] != primary {
+		return nil, conn
+	}
+
+	conn.stdio = true
+	if p.replicas == nil {
+		p.replicas = make(map[string][]*connection)
+	}
+	p.replicas[server] = append(p.replicas[server], conn)
+	return conn, nil
+}
+
+// removeReplicaLocked drops conn from server's replicas and reports whether
+// it was one. Callers must hold p.mu.
+func (p *Pool) removeReplicaLocked(server string, conn *connection) bool {
+	replicas := p.replicas[server]
+	for i, replica := range replicas {
+		if replica != conn {
+			continue
+		}
+		replicas = append(replicas[:i:i], replicas[i+1:]...)
+		if len(replicas) == 0 {
+			delete(p.replicas, server)
+		} else {
+			p.replicas[server] = replicas
+		}
+		return true
+	}
+	return false
+}
+
+func closeReplicas(replicas map[string][]*connection) {
+	for _, conns := range replicas {
+		for _, conn := range conns {
+			closeConnection(conn)
+		}
+	}
+}
@@ -0,0 +1,261 @@
+package mcppool
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/lydakis/mcpx/internal/config"
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+func TestPoolSizeRunsConcurrentCallsOnSeparateProcesses(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 15*time.Second)
+	defer cancel()
+
+	scfg := stdioHelperServer()
+	scfg.Env[stdioHelperBarrierEnv] = t.TempDir()
+	scfg.PoolSize = 2
+	pool := New(&config.Config{Servers: map[string]config.ServerConfig{"slow": scfg}})
+	defer pool.CloseAll()
+
+	info, err := pool.ToolInfoByName(ctx, "slow", "barrier_tool")
+	if err != nil {
+		t.Fatalf("ToolInfoByName() error = %v", err)
+	}
+
+	var wg sync.WaitGroup
+	results := make([]*mcp.CallToolResult, 2)
+	errs := make([]error, 2)
+	for i := range results {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			results[i], errs[i] = pool.CallToolWithInfo(ctx, "slow", info, nil, nil)
+		}(i)
+	}
+	wg.Wait()
+
+	pids := map[string]bool{}
+	for i, result := range results {
+		if errs[i] != nil {
+			t.Fatalf("call %d error = %v", i, errs[i])
+		}
+		text, _ := result.Content[0].(mcp.TextContent)
+		if result.IsError {
+			t.Fatalf("call %d result = %q, want both calls in flight together", i, text.Text)
+		}
+		pids[text.Text] = true
+	}
+	if len(pids) != 2 {
+		t.Fatalf("calls ran on processes %v, want two distinct processes", pids)
+	}
+
+	pool.mu.Lock()
+	replicas := len(pool.replicas["slow"])
+	pool.mu.Unlock()
+	if replicas != 1 {
+		t.Fatalf("replicas = %d, want 1 beside the primary connection", replicas)
+	}
+
+	pool.Close("slow")
+	pool.mu.Lock()
+	replicas = len(pool.replicas["slow"])
+	pool.mu.Unlock()
+	if replicas != 0 {
+		t.Fatalf("replicas after Close = %d, want 0", replicas)
+	}
+}
+
+func TestPoolSizeReusesIdleConnectionBeforeSpawning(t *testing.T) {
+	calls := 0
+	primary := &connection{
+		stdio: true,
+		close: func() error { return nil },
+		callTool: func(context.Context, string, map[string]any) (*mcp.CallToolResult, error) {
+			calls++
+			return mcp.NewToolResultText("ok"), nil
+		},
+	}
+	pool := New(&config.Config{Servers: map[string]config.ServerConfig{
+		"svc": {Command: "unused", PoolSize: 4},
+	}})
+	pool.conns["svc"] = primary
+
+	for range 3 {
+		if _, err := pool.CallToolWithInfo(context.Background(), "svc", &ToolInfo{Name: "t"}, nil, nil); err != nil {
+			t.Fatalf("CallToolWithInfo() error = %v", err)
+		}
+	}
+	if calls != 3 {
+		t.Fatalf("primary calls = %d, want 3", calls)
+	}
+	if len(pool.replicas["svc"]) != 0 || primary.inflight != 0 {
+		t.Fatalf("replicas = %d, inflight = %d; want sequential calls to stay on the primary", len(pool.replicas["svc"]), primary.inflight)
+	}
+}
+
+func TestPoolSizeSpawnsReplicaWithoutHoldingPoolLock(t *testing.T) {
+	primaryBusy := make(chan struct{})
+	primary := &connection{
+		stdio: true,
+		close: func() error { return nil },
+		callTool: func(context.Context, string, map[string]any) (*mcp.CallToolResult, error) {
+			<-primaryBusy
+			return mcp.NewToolResultText("primary"), nil
+		},
+	}
+	spawning := make(chan struct{})
+	finishSpawn := make(chan struct{})
+	connectServer = func(context.Context, config.ServerConfig) (*connection, error) {
+		close(spawning)
+		<-finishSpawn
+		return &connection{
+			close: func() error { return nil },
+			callTool: func(context.Context, string, map[string]any) (*mcp.CallToolResult, error) {
+				return mcp.NewToolResultText("replica"), nil
+			},
+		}, nil
+	}
+	defer func() { connectServer = defaultConnectServer }()
+
+	pool := New(&config.Config{Servers: map[string]config.ServerConfig{
+		"svc": {Command: "unused", PoolSize: 2},
+	}})
+	pool.conns["svc"] = primary
+	info := &ToolInfo{Name: "t"}
+
+	firstDone := make(chan error, 1)
+	go func() {
+		_, err := pool.CallToolWithInfo(context.Background(), "svc", info, nil, nil)
+		firstDone <- err
+	}()
+	for {
+		pool.mu.Lock()
+		inflight := primary.inflight
+		pool.mu.Unlock()
+		if inflight == 1 {
+			break
+		}
+		time.Sleep(time.Millisecond)
+	}
+
+	secondDone := make(chan *mcp.CallToolResult, 1)
+	go func() {
+		result, err := pool.CallToolWithInfo(context.Background(), "svc", info, nil, nil)
+		if err != nil {
+			t.Errorf("second CallToolWithInfo() error = %v", err)
+		}
+		secondDone <- result
+	}()
+	<-spawning
+
+	stateDone := make(chan struct{})
+	go func() {
+		pool.State("other")
+		close(stateDone)
+	}()
+	select {
+	case <-stateDone:
+	case <-time.After(2 * time.Second):
+		t.Fatal("State() blocked while a replica was spawning")
+	}
+
+	close(finishSpawn)
+	result := <-secondDone
+	if text, _ := result.Content[0].(mcp.TextContent); text.Text != "replica" {
+		t.Fatalf("second call ran on %q, want the new replica", text.Text)
+	}
+	close(primaryBusy)
+	if err := <-firstDone; err != nil {
+		t.Fatalf("first CallToolWithInfo() error = %v", err)
+	}
+	if got := pool.ConnectionCount("svc"); got != 2 {
+		t.Fatalf("ConnectionCount() = %d, want primary and one replica", got)
+	}
+}
+
+func TestPoolSizeClosesOrphanedReplicaOutsidePoolLock(t *testing.T) {
+	primaryBusy := make(chan struct{})
+	primary := &connection{
+		stdio: true,
+		close: func() error { return nil },
+		callTool: func(context.Context, string, map[string]any) (*mcp.CallToolResult, error) {
+			<-primaryBusy
+			return mcp.NewToolResultText("primary"), nil
+		},
+	}
+	spawning := make(chan struct{})
+	finishSpawn := make(chan struct{})
+	closing := make(chan struct{})
+	finishClose := make(chan struct{})
+	connectServer = func(context.Context, config.ServerConfig) (*connection, error) {
+		close(spawning)
+		<-finishSpawn
+		return &connection{close: func() error {
+			close(closing)
+			<-finishClose
+			return nil
+		}}, nil
+	}
+	defer func() { connectServer = defaultConnectServer }()
+
+	pool := New(&config.Config{Servers: map[string]config.ServerConfig{
+		"svc": {Command: "unused", PoolSize: 2},
+	}})
+	pool.conns["svc"] = primary
+	info := &ToolInfo{Name: "t"}
+
+	firstDone := make(chan error, 1)
+	go func() {
+		_, err := pool.CallToolWithInfo(context.Background(), "svc", info, nil, nil)
+		firstDone <- err
+	}()
+	for {
+		pool.mu.Lock()
+		inflight := primary.inflight
+		pool.mu.Unlock()
+		if inflight == 1 {
+			break
+		}
+		time.Sleep(time.Millisecond)
+	}
+
+	secondDone := make(chan error, 1)
+	go func() {
+		_, err := pool.CallToolWithInfo(context.Background(), "svc", info, nil, nil)
+		secondDone <- err
+	}()
+	<-spawning
+
+	// The primary stops being svc's connection while the replica spawns.
+	pool.mu.Lock()
+	delete(pool.conns, "svc")
+	pool.mu.Unlock()
+	close(finishSpawn)
+	<-closing
+
+	stateDone := make(chan struct{})
+	go func() {
+		pool.State("other")
+		close(stateDone)
+	}()
+	select {
+	case <-stateDone:
+	case <-time.After(2 * time.Second):
+		t.Fatal("State() blocked while the orphaned replica was closing")
+	}
+
+	close(finishClose)
+	close(primaryBusy)
+	if err := <-firstDone; err != nil {
+		t.Fatalf("first CallToolWithInfo() error = %v", err)
+	}
+	if err := <-secondDone; err != nil {
+		t.Fatalf("second CallToolWithInfo() error = %v", err)
+	}
+	if got := len(pool.replicas["svc"]); got != 0 {
+		t.Fatalf("replicas = %d, want the orphaned replica dropped", got)
+	}
+}
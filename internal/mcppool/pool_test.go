@@ -424,7 +424,7 @@ func TestCallToolWithInfoSkipsToolListing(t *testing.T) {
 		InputSchema: json.RawMessage(`{"type":"object","properties":{"query":{"type":"string"}},"required":["query"]}`),
 	}
 
-	if _, err := p.CallToolWithInfo(context.Background(), "github", info, []byte(`{"query":"mcp"}`)); err != nil {
+	if _, err := p.CallToolWithInfo(context.Background(), "github", info, []byte(`{"query":"mcp"}`), nil); err != nil {
 		t.Fatalf("CallToolWithInfo() error = %v", err)
 	}
 
@@ -509,7 +509,7 @@ func TestCallToolWithInfoSerializesRequestsPerConnection(t *testing.T) {
 		go func() {
 			defer wg.Done()
 			<-start
-			_, err := p.CallToolWithInfo(context.Background(), "github", &ToolInfo{Name: "search"}, nil)
+			_, err := p.CallToolWithInfo(context.Background(), "github", &ToolInfo{Name: "search"}, nil, nil)
 			errs <- err
 		}()
 	}
@@ -644,6 +644,41 @@ func TestListToolsIncludesOutputSchema(t *testing.T) {
 	}
 }
 
+func TestListToolsCapturesAnnotations(t *testing.T) {
+	destructive := true
+	conn := &connection{
+		listTools: func(context.Context) ([]mcp.Tool, error) {
+			return []mcp.Tool{
+				{
+					Name:        "delete",
+					InputSchema: mcp.ToolInputSchema{Type: "object"},
+					Annotations: mcp.ToolAnnotation{DestructiveHint: &destructive},
+				},
+			}, nil
+		},
+	}
+
+	p := &Pool{
+		cfg:   &config.Config{Servers: map[string]config.ServerConfig{"github": {}}},
+		conns: map[string]*connection{"github": conn},
+	}
+
+	tools, err := p.ListTools(context.Background(), "github")
+	if err != nil {
+		t.Fatalf("ListTools() error = %v", err)
+	}
+	if len(tools) != 1 {
+		t.Fatalf("len(tools) = %d, want 1", len(tools))
+	}
+	got := tools[0].Annotations
+	if got.Destructive == nil || !*got.Destructive {
+		t.Fatalf("Annotations.Destructive = %v, want true", got.Destructive)
+	}
+	if got.ReadOnly != nil {
+		t.Fatalf("Annotations.ReadOnly = %v, want undeclared", *got.ReadOnly)
+	}
+}
+
 func TestToolSchemaReturnsInputSchemaForNamedTool(t *testing.T) {
 	t.Parallel()
 
@@ -739,3 +774,153 @@ func TestCloseIsNoopForUnknownServer(t *testing.T) {
 
 	p.Close("missing")
 }
+
+func TestListResourcesReturnsResourceInfos(t *testing.T) {
+	t.Parallel()
+
+	conn := &connection{
+		listResources: func(context.Context) ([]mcp.Resource, error) {
+			return []mcp.Resource{
+				{URI: "file:///readme.md", Name: "readme", Description: "Project readme", MIMEType: "text/markdown"},
+			}, nil
+		},
+	}
+
+	p := &Pool{
+		cfg:   &config.Config{Servers: map[string]config.ServerConfig{"github": {}}},
+		conns: map[string]*connection{"github": conn},
+	}
+
+	got, err := p.ListResources(context.Background(), "github")
+	if err != nil {
+		t.Fatalf("ListResources() error = %v", err)
+	}
+	want := []ResourceInfo{
+		{URI: "file:///readme.md", Name: "readme", Description: "Project readme", MIMEType: "text/markdown"},
+	}
+	if len(got) != 1 || got[0] != want[0] {
+		t.Fatalf("ListResources() = %#v, want %#v", got, want)
+	}
+}
+
+func TestListPromptsReturnsPromptInfos(t *testing.T) {
+	t.Parallel()
+
+	conn := &connection{
+		listPrompts: func(context.Context) ([]mcp.Prompt, error) {
+			return []mcp.Prompt{
+				{
+					Name:        "summarize",
+					Description: "Summarize text",
+					Arguments: []mcp.PromptArgument{
+						{Name: "text", Description: "Input text", Required: true},
+					},
+				},
+			}, nil
+		},
+	}
+
+	p := &Pool{
+		cfg:   &config.Config{Servers: map[string]config.ServerConfig{"github": {}}},
+		conns: map[string]*connection{"github": conn},
+	}
+
+	got, err := p.ListPrompts(context.Background(), "github")
+	if err != nil {
+		t.Fatalf("ListPrompts() error = %v", err)
+	}
+	if len(got) != 1 || got[0].Name != "summarize" || got[0].Description != "Summarize text" {
+		t.Fatalf("ListPrompts() = %#v, want summarize prompt", got)
+	}
+	if len(got[0].Arguments) != 1 || got[0].Arguments[0] != (PromptArgumentInfo{Name: "text", Description: "Input text", Required: true}) {
+		t.Fatalf("ListPrompts() arguments = %#v, want required text argument", got[0].Arguments)
+	}
+}
+
+func TestListPromptsErrorInvalidatesConnection(t *testing.T) {
+	var closed bool
+	conn := &connection{
+		listPrompts: func(context.Context) ([]mcp.Prompt, error) {
+			return nil, errors.New("boom")
+		},
+		close: func() error {
+			closed = true
+			return nil
+		},
+	}
+
+	p := &Pool{
+		cfg:   &config.Config{Servers: map[string]config.ServerConfig{}},
+		conns: map[string]*connection{"github": conn},
+	}
+
+	if _, err := p.ListPrompts(context.Background(), "github"); err == nil {
+		t.Fatal("ListPrompts() error = nil, want non-nil")
+	}
+
+	p.mu.Lock()
+	_, ok := p.conns["github"]
+	p.mu.Unlock()
+	if ok {
+		t.Fatal("connection was not evicted after list prompts error")
+	}
+	if !closed {
+		t.Fatal("connection close was not called after list prompts error")
+	}
+}
+
+func TestGetPromptStringifiesNonStringArguments(t *testing.T) {
+	t.Parallel()
+
+	var gotName string
+	var gotArgs map[string]string
+	conn := &connection{
+		getPrompt: func(_ context.Context, name string, args map[string]string) (*mcp.GetPromptResult, error) {
+			gotName = name
+			gotArgs = args
+			return &mcp.GetPromptResult{
+				Messages: []mcp.PromptMessage{
+					{Role: mcp.RoleUser, Content: mcp.NewTextContent("hello")},
+				},
+			}, nil
+		},
+	}
+
+	p := &Pool{
+		cfg:   &config.Config{Servers: map[string]config.ServerConfig{"docs": {}}},
+		conns: map[string]*connection{"docs": conn},
+	}
+
+	result, err := p.GetPrompt(context.Background(), "docs", "summarize", []byte(`{"text":"hi","limit":3,"strict":true,"skip":null}`))
+	if err != nil {
+		t.Fatalf("GetPrompt() error = %v", err)
+	}
+	if len(result.Messages) != 1 {
+		t.Fatalf("len(messages) = %d, want 1", len(result.Messages))
+	}
+	if gotName != "summarize" {
+		t.Fatalf("prompt name = %q, want %q", gotName, "summarize")
+	}
+	want := map[string]string{"text": "hi", "limit": "3", "strict": "true"}
+	if len(gotArgs) != len(want) {
+		t.Fatalf("prompt args = %#v, want %#v", gotArgs, want)
+	}
+	for key, value := range want {
+		if gotArgs[key] != value {
+			t.Fatalf("prompt args[%q] = %q, want %q", key, gotArgs[key], value)
+		}
+	}
+}
+
+func TestGetPromptRejectsInvalidArgsWithoutConnecting(t *testing.T) {
+	t.Parallel()
+
+	p := &Pool{
+		cfg:   &config.Config{Servers: map[string]config.ServerConfig{}},
+		conns: map[string]*connection{},
+	}
+
+	if _, err := p.GetPrompt(context.Background(), "docs", "summarize", []byte(`[1]`)); err == nil {
+		t.Fatal("GetPrompt() error = nil, want invalid args error")
+	}
+}
@@ -0,0 +1,76 @@
+package mcppool
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+// Progress is one notifications/progress update for an in-flight call.
+type Progress struct {
+	Progress float64
+	Total    float64 // 0 when the server did not say
+	Message  string
+}
+
+type progressKey struct{}
+
+// withProgress asks the tool call made with ctx to request progress
+// notifications from the server and pass each one to sink as it arrives.
+func withProgress(ctx context.Context, sink func(Progress)) context.Context {
+	return context.WithValue(ctx, progressKey{}, sink)
+}
+
+func progressFrom(ctx context.Context) func(Progress) {
+	sink, _ := ctx.Value(progressKey{}).(func(Progress))
+	return sink
+}
+
+// progressTap routes a client's notifications/progress messages to the sink
+// registered for their progress token.
+type progressTap struct {
+	mu    sync.Mutex
+	seq   int
+	sinks map[string]func(Progress)
+}
+
+// attach registers sink under a fresh token, returning the token to send
+// with the request and a func that unregisters it.
+func (t *progressTap) attach(sink func(Progress)) (string, func()) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.seq++
+	token := fmt.Sprintf("mcpx-%d", t.seq)
+	if t.sinks == nil {
+		t.sinks = make(map[string]func(Progress))
+	}
+	t.sinks[token] = sink
+	return token, func() {
+		t.mu.Lock()
+		delete(t.sinks, token)
+		t.mu.Unlock()
+	}
+}
+
+func (t *progressTap) handle(n mcp.JSONRPCNotification) {
+	if n.Method != "notifications/progress" {
+		return
+	}
+	fields := n.Params.AdditionalFields
+	token := fmt.Sprint(fields["progressToken"])
+
+	t.mu.Lock()
+	sink := t.sinks[token]
+	t.mu.Unlock()
+	if sink == nil {
+		return
+	}
+
+	p := Progress{}
+	p.Progress, _ = fields["progress"].(float64)
+	p.Total, _ = fields["total"].(float64)
+	p.Message, _ = fields["message"].(string)
+	sink(p)
+}
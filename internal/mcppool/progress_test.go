@@ -0,0 +1,99 @@
+package mcppool
+
+import (
+	"context"
+	"encoding/json"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/lydakis/mcpx/internal/config"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+func TestCallToolWithInfoForwardsProgressToCallback(t *testing.T) {
+	mcpServer := server.NewMCPServer("mcpx-progress-helper", "1.0.0")
+	mcpServer.AddTool(mcp.Tool{Name: "build", InputSchema: mcp.ToolInputSchema{Type: "object"}}, func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		if req.Params.Meta == nil || req.Params.Meta.ProgressToken == nil {
+			return mcp.NewToolResultText("no token"), nil
+		}
+		srv := server.ServerFromContext(ctx)
+		for i, msg := range []string{"compiling", "linking"} {
+			if err := srv.SendNotificationToClient(ctx, "notifications/progress", map[string]any{
+				"progressToken": req.Params.Meta.ProgressToken,
+				"progress":      i + 1,
+				"total":         2,
+				"message":       msg,
+			}); err != nil {
+				return nil, err
+			}
+		}
+		return mcp.NewToolResultText("built"), nil
+	})
+	httpServer := server.NewTestStreamableHTTPServer(mcpServer)
+	defer httpServer.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	pool := New(&config.Config{Servers: map[string]config.ServerConfig{
+		"http": {URL: httpServer.URL},
+	}})
+	defer pool.CloseAll()
+
+	var mu sync.Mutex
+	var got []Progress
+	info, err := pool.ToolInfoByName(ctx, "http", "build")
+	if err != nil {
+		t.Fatalf("ToolInfoByName() error = %v", err)
+	}
+	result, err := pool.CallToolWithInfo(ctx, "http", info, json.RawMessage(`{}`), func(p Progress) {
+		mu.Lock()
+		got = append(got, p)
+		mu.Unlock()
+	})
+	if err != nil {
+		t.Fatalf("CallToolWithInfo() error = %v", err)
+	}
+	if text := result.Content[0].(mcp.TextContent).Text; text != "built" {
+		t.Fatalf("result = %q, want built", text)
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	want := []Progress{{Progress: 1, Total: 2, Message: "compiling"}, {Progress: 2, Total: 2, Message: "linking"}}
+	if len(got) != len(want) {
+		t.Fatalf("progress = %#v, want %#v", got, want)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Fatalf("progress[%d] = %#v, want %#v", i, got[i], want[i])
+		}
+	}
+}
+
+func TestCallToolWithoutProgressSendsNoToken(t *testing.T) {
+	mcpServer := server.NewMCPServer("mcpx-progress-helper", "1.0.0")
+	mcpServer.AddTool(mcp.Tool{Name: "build", InputSchema: mcp.ToolInputSchema{Type: "object"}}, func(_ context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		if req.Params.Meta != nil && req.Params.Meta.ProgressToken != nil {
+			return mcp.NewToolResultText("token"), nil
+		}
+		return mcp.NewToolResultText("no token"), nil
+	})
+	httpServer := server.NewTestStreamableHTTPServer(mcpServer)
+	defer httpServer.Close()
+
+	pool := New(&config.Config{Servers: map[string]config.ServerConfig{
+		"http": {URL: httpServer.URL},
+	}})
+	defer pool.CloseAll()
+
+	result, err := pool.CallTool(context.Background(), "http", "build", json.RawMessage(`{}`))
+	if err != nil {
+		t.Fatalf("CallTool() error = %v", err)
+	}
+	if text := result.Content[0].(mcp.TextContent).Text; text != "no token" {
+		t.Fatalf("result = %q, want no token", text)
+	}
+}
@@ -0,0 +1,111 @@
+package mcppool
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// RetryAfterError marks a tool call that an HTTP server rejected with
+// 429 Too Many Requests and a Retry-After header.
+type RetryAfterError struct {
+	Wait time.Duration
+	Err  error
+}
+
+func (e *RetryAfterError) Error() string {
+	return e.Err.Error()
+}
+
+func (e *RetryAfterError) Unwrap() error {
+	return e.Err
+}
+
+// RetryAfter reports the server-requested wait carried by err, if any.
+func RetryAfter(err error) (time.Duration, bool) {
+	var target *RetryAfterError
+	if errors.As(err, &target) {
+		return target.Wait, true
+	}
+	return 0, false
+}
+
+type retryAfterKey struct{}
+
+// retryAfterHint collects the Retry-After seen while serving one request.
+// mcp-go only surfaces the status code and body, so the header is captured
+// by retryAfterTransport through the request context.
+type retryAfterHint struct {
+	mu   sync.Mutex
+	wait time.Duration
+	set  bool
+}
+
+func withRetryAfterHint(ctx context.Context) (context.Context, *retryAfterHint) {
+	hint := &retryAfterHint{}
+	return context.WithValue(ctx, retryAfterKey{}, hint), hint
+}
+
+func (h *retryAfterHint) record(wait time.Duration) {
+	h.mu.Lock()
+	h.wait = wait
+	h.set = true
+	h.mu.Unlock()
+}
+
+// wrap attaches the captured Retry-After to err.
+func (h *retryAfterHint) wrap(err error) error {
+	if err == nil {
+		return nil
+	}
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if !h.set {
+		return err
+	}
+	return &RetryAfterError{Wait: h.wait, Err: err}
+}
+
+type retryAfterTransport struct {
+	base http.RoundTripper
+}
+
+func (t *retryAfterTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	resp, err := t.base.RoundTrip(req)
+	if err != nil || resp.StatusCode != http.StatusTooManyRequests {
+		return resp, err
+	}
+	if hint, ok := req.Context().Value(retryAfterKey{}).(*retryAfterHint); ok {
+		if wait, ok := parseRetryAfter(resp.Header.Get("Retry-After"), time.Now()); ok {
+			hint.record(wait)
+		}
+	}
+	return resp, err
+}
+
+// parseRetryAfter accepts delay-seconds or an HTTP date (RFC 9110 10.2.3).
+func parseRetryAfter(value string, now time.Time) (time.Duration, bool) {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return 0, false
+	}
+	if secs, err := strconv.Atoi(value); err == nil {
+		if secs < 0 {
+			return 0, false
+		}
+		return time.Duration(secs) * time.Second, true
+	}
+	when, err := http.ParseTime(value)
+	if err != nil {
+		return 0, false
+	}
+	wait := when.Sub(now)
+	if wait < 0 {
+		wait = 0
+	}
+	return wait, true
+}
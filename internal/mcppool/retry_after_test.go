@@ -0,0 +1,46 @@
+package mcppool
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestRetryAfterTransportCapturesRateLimitWait(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Set("Retry-After", "3")
+		w.WriteHeader(http.StatusTooManyRequests)
+	}))
+	defer srv.Close()
+
+	ctx, hint := withRetryAfterHint(context.Background())
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, srv.URL, nil)
+	if err != nil {
+		t.Fatalf("NewRequest: %v", err)
+	}
+	client := &http.Client{Transport: &retryAfterTransport{base: http.DefaultTransport}}
+	resp, err := client.Do(req)
+	if err != nil {
+		t.Fatalf("Do: %v", err)
+	}
+	resp.Body.Close()
+
+	wait, ok := RetryAfter(hint.wrap(errors.New("request failed with status 429")))
+	if !ok || wait != 3*time.Second {
+		t.Fatalf("RetryAfter = %v, %v; want 3s, true", wait, ok)
+	}
+}
+
+func TestParseRetryAfterAcceptsHTTPDate(t *testing.T) {
+	now := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+	wait, ok := parseRetryAfter(now.Add(90*time.Second).Format(http.TimeFormat), now)
+	if !ok || wait != 90*time.Second {
+		t.Fatalf("parseRetryAfter(date) = %v, %v; want 90s, true", wait, ok)
+	}
+	if _, ok := parseRetryAfter("soon", now); ok {
+		t.Fatal("parseRetryAfter(soon) ok = true, want false")
+	}
+}
@@ -0,0 +1,143 @@
+package mcppool
+
+import (
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/lydakis/mcpx/internal/config"
+)
+
+// ServerState is the last-known connection state of a pooled server.
+type ServerState string
+
+const (
+	// ServerStateNever means the pool has not connected to the server yet.
+	ServerStateNever ServerState = "never"
+	// ServerStateConnected means the pool holds a live connection.
+	ServerStateConnected ServerState = "connected"
+	// ServerStateIdle means a previous connection was closed cleanly.
+	ServerStateIdle ServerState = "idle"
+	// ServerStateFailed means the last connect or request attempt failed.
+	ServerStateFailed ServerState = "failed"
+)
+
+// State reports the last-known connection state for server without
+// connecting to it.
+func (p *Pool) State(server string) ServerState {
+	if p == nil {
+		return ServerStateNever
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if _, ok := p.conns[server]; ok {
+		return ServerStateConnected
+	}
+	if state, ok := p.states[server]; ok {
+		return state
+	}
+	return ServerStateNever
+}
+
+// ConnectionCount reports how many connections the pool holds open for
+// server, counting pool_size replicas.
+func (p *Pool) ConnectionCount(server string) int {
+	if p == nil {
+		return 0
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	n := len(p.replicas[server])
+	if _, ok := p.conns[server]; ok {
+		n++
+	}
+	return n
+}
+
+// recordStateLocked stores the last-known state for server. Callers must hold p.mu.
+func (p *Pool) recordStateLocked(server string, state ServerState) {
+	if p.states == nil {
+		p.states = make(map[string]ServerState)
+	}
+	p.states[server] = state
+}
+
+// markClosedLocked records a clean close unless the server already failed.
+// Callers must hold p.mu.
+func (p *Pool) markClosedLocked(server string) {
+	if p.states[server] == ServerStateFailed {
+		return
+	}
+	p.recordStateLocked(server, ServerStateIdle)
+}
+
+// LastError is the most recent connect or request failure recorded for a
+// pooled server. Message has configured secrets redacted.
+type LastError struct {
+	At      time.Time
+	Message string
+}
+
+// LastError reports the most recent failure for server, if any. A successful
+// reconnect clears it.
+func (p *Pool) LastError(server string) (LastError, bool) {
+	if p == nil {
+		return LastError{}, false
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	last, ok := p.lastErrors[server]
+	return last, ok
+}
+
+// recordFailureLocked marks server failed and stores err as its last error.
+// Callers must hold p.mu.
+func (p *Pool) recordFailureLocked(server string, err error) {
+	p.recordStateLocked(server, ServerStateFailed)
+	if err == nil {
+		return
+	}
+	if p.lastErrors == nil {
+		p.lastErrors = make(map[string]LastError)
+	}
+	var scfg config.ServerConfig
+	if p.cfg != nil {
+		scfg = p.cfg.Servers[server]
+	}
+	p.lastErrors[server] = LastError{
+		At:      time.Now(),
+		Message: redactErrorMessage(err.Error(), scfg),
+	}
+}
+
+var secretAssignmentPattern = regexp.MustCompile(`(?i)\b(bearer|basic|token|api[_-]?key|password|secret)([=: ]+)[^\s&"']+`)
+
+// minRedactedValueLen skips short config values (ports, flags) that would
+// otherwise mangle unrelated parts of an error message.
+const minRedactedValueLen = 6
+
+// redactErrorMessage strips configured header/env values, URL credentials,
+// and token-looking assignments from msg.
+func redactErrorMessage(msg string, scfg config.ServerConfig) string {
+	for _, values := range []map[string]string{scfg.Headers, scfg.Env} {
+		for _, value := range values {
+			if len(value) >= minRedactedValueLen {
+				msg = strings.ReplaceAll(msg, value, "[REDACTED]")
+			}
+		}
+	}
+	msg = redactURLUserinfo(msg)
+	return secretAssignmentPattern.ReplaceAllString(msg, "${1}${2}[REDACTED]")
+}
+
+var urlUserinfoPattern = regexp.MustCompile(`(://)[^/\s@:]+:[^/\s@]+@`)
+
+func redactURLUserinfo(msg string) string {
+	return urlUserinfoPattern.ReplaceAllString(msg, "${1}[REDACTED]@")
+}
@@ -0,0 +1,67 @@
+package mcppool
+
+import (
+	"bufio"
+	"context"
+	"io"
+	"sync"
+	"time"
+)
+
+// stderrDrainWindow is how long a capturing call keeps listening after the
+// response arrives, since stderr lines race the JSON-RPC reply on stdout.
+var stderrDrainWindow = 25 * time.Millisecond
+
+type stderrCaptureKey struct{}
+
+// WithStderrCapture asks stdio calls made with ctx to pass each line the
+// server writes to stderr during the call to sink. HTTP servers ignore it.
+func WithStderrCapture(ctx context.Context, sink func(line string)) context.Context {
+	return context.WithValue(ctx, stderrCaptureKey{}, sink)
+}
+
+func stderrCaptureFrom(ctx context.Context) func(string) {
+	sink, _ := ctx.Value(stderrCaptureKey{}).(func(string))
+	return sink
+}
+
+// stderrTap drains a stdio server's stderr for the life of the process,
+// so a chatty server never blocks on a full pipe, and forwards lines to the
+// sink of the call in flight, if any.
+type stderrTap struct {
+	mu   sync.Mutex
+	sink func(string)
+}
+
+func newStderrTap(r io.Reader) *stderrTap {
+	tap := &stderrTap{}
+	go func() {
+		scanner := bufio.NewScanner(r)
+		scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+		for scanner.Scan() {
+			tap.mu.Lock()
+			sink := tap.sink
+			tap.mu.Unlock()
+			if sink != nil {
+				sink(scanner.Text())
+			}
+		}
+	}()
+	return tap
+}
+
+// attach routes lines to sink until the returned detach is called.
+func (t *stderrTap) attach(sink func(string)) (detach func()) {
+	if t == nil || sink == nil {
+		return func() {}
+	}
+	t.mu.Lock()
+	t.sink = sink
+	t.mu.Unlock()
+	return func() {
+		time.Sleep(stderrDrainWindow)
+		t.mu.Lock()
+		t.sink = nil
+		t.mu.Unlock()
+	}
+}
@@ -0,0 +1,56 @@
+package mcppool
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/lydakis/mcpx/internal/config"
+)
+
+func TestPoolStdioCapturesServerStderrDuringCall(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	oldWindow := stderrDrainWindow
+	stderrDrainWindow = 200 * time.Millisecond
+	defer func() { stderrDrainWindow = oldWindow }()
+
+	pool := New(&config.Config{Servers: map[string]config.ServerConfig{
+		"stdio": {
+			Command: os.Args[0],
+			Args:    []string{"-test.run=TestMCPXStdioHelperProcess", "--", "stdio-helper"},
+			Env: map[string]string{
+				stdioHelperEnv:      "1",
+				stdioHelperNoisyEnv: "1",
+			},
+		},
+	}})
+	defer pool.CloseAll()
+
+	if _, err := pool.CallTool(ctx, "stdio", "noisy_tool", json.RawMessage(`{}`)); err != nil {
+		t.Fatalf("CallTool(uncaptured) error = %v", err)
+	}
+	// Let the uncaptured call's lines drain; they must not reach the sink.
+	time.Sleep(200 * time.Millisecond)
+
+	var mu sync.Mutex
+	var lines []string
+	captureCtx := WithStderrCapture(ctx, func(line string) {
+		mu.Lock()
+		lines = append(lines, line)
+		mu.Unlock()
+	})
+	if _, err := pool.CallTool(captureCtx, "stdio", "noisy_tool", json.RawMessage(`{}`)); err != nil {
+		t.Fatalf("CallTool(captured) error = %v", err)
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	if len(lines) != 2 || lines[0] != "noisy: warming cache" || lines[1] != "noisy: done" {
+		t.Fatalf("captured lines = %q, want only the second call's two lines", lines)
+	}
+}
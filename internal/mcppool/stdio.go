@@ -3,9 +3,13 @@ package mcppool
 import (
 	"context"
 	"fmt"
+	"os"
+	"os/exec"
+	"strings"
 
 	"github.com/lydakis/mcpx/internal/config"
 	mcpclient "github.com/mark3labs/mcp-go/client"
+	"github.com/mark3labs/mcp-go/client/transport"
 	"github.com/mark3labs/mcp-go/mcp"
 )
 
@@ -15,7 +19,17 @@ func connectStdio(ctx context.Context, scfg config.ServerConfig) (*connection, e
 		env = append(env, k+"="+v)
 	}
 
-	c, err := mcpclient.NewStdioMCPClient(scfg.Command, env, scfg.Args...)
+	var opts []transport.StdioOption
+	if len(scfg.AllowEnv) > 0 {
+		inherited := inheritedEnv(scfg, os.Environ())
+		opts = append(opts, transport.WithCommandFunc(func(ctx context.Context, command string, env []string, args []string) (*exec.Cmd, error) {
+			cmd := exec.CommandContext(ctx, command, args...)
+			cmd.Env = append(append([]string(nil), inherited...), env...)
+			return cmd, nil
+		}))
+	}
+
+	c, err := mcpclient.NewStdioMCPClientWithOptions(scfg.Command, env, scfg.Args, opts...)
 	if err != nil {
 		return nil, fmt.Errorf("creating stdio client: %w", err)
 	}
@@ -34,24 +48,22 @@ func connectStdio(ctx context.Context, scfg config.ServerConfig) (*connection, e
 		return nil, fmt.Errorf("initializing: %w", err)
 	}
 
-	return &connection{
-		listTools: func(ctx context.Context) ([]mcp.Tool, error) {
-			result, err := c.ListTools(ctx, mcp.ListToolsRequest{})
-			if err != nil {
-				return nil, err
-			}
-			return result.Tools, nil
-		},
-		callTool: func(ctx context.Context, name string, args map[string]any) (*mcp.CallToolResult, error) {
-			return c.CallTool(ctx, mcp.CallToolRequest{
-				Params: mcp.CallToolParams{
-					Name:      name,
-					Arguments: args,
-				},
-			})
-		},
-		close: func() error {
-			return c.Close()
-		},
-	}, nil
+	conn := newClientConnection(c)
+	if stderr, ok := mcpclient.GetStderr(c); ok && stderr != nil {
+		conn.stderr = newStderrTap(stderr)
+	}
+	return conn, nil
+}
+
+// inheritedEnv filters environ, in KEY=VALUE form, to the variables the
+// server's allow_env lets it inherit.
+func inheritedEnv(scfg config.ServerConfig, environ []string) []string {
+	filtered := make([]string, 0, len(environ))
+	for _, kv := range environ {
+		name, _, _ := strings.Cut(kv, "=")
+		if scfg.EnvAllowed(name) {
+			filtered = append(filtered, kv)
+		}
+	}
+	return filtered
 }
@@ -0,0 +1,47 @@
+package paths
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// CompletionFileCandidates lists where shell loads mcpx completions from,
+// user locations first. It returns nil for unsupported shells.
+func CompletionFileCandidates(shell string) []string {
+	dataHome := xdgBaseDir("XDG_DATA_HOME", ".local", "share")
+	configHome := xdgBaseDir("XDG_CONFIG_HOME", ".config")
+
+	switch shell {
+	case "bash":
+		return []string{
+			filepath.Join(dataHome, "bash-completion", "completions", "mcpx"),
+			"/usr/local/share/bash-completion/completions/mcpx",
+			"/usr/share/bash-completion/completions/mcpx",
+			"/etc/bash_completion.d/mcpx",
+		}
+	case "zsh":
+		// fpath is a shell array and rarely exported; use FPATH when it is.
+		var out []string
+		for _, dir := range strings.Split(os.Getenv("FPATH"), ":") {
+			if dir = strings.TrimSpace(dir); dir != "" {
+				out = append(out, filepath.Join(dir, "_mcpx"))
+			}
+		}
+		return append(out,
+			filepath.Join(homeDir(), ".zfunc", "_mcpx"),
+			"/usr/local/share/zsh/site-functions/_mcpx",
+			"/opt/homebrew/share/zsh/site-functions/_mcpx",
+			"/usr/share/zsh/site-functions/_mcpx",
+		)
+	case "fish":
+		return []string{
+			filepath.Join(configHome, "fish", "completions", "mcpx.fish"),
+			filepath.Join(dataHome, "fish", "vendor_completions.d", "mcpx.fish"),
+			"/usr/local/share/fish/vendor_completions.d/mcpx.fish",
+			"/usr/share/fish/vendor_completions.d/mcpx.fish",
+		}
+	default:
+		return nil
+	}
+}
@@ -0,0 +1,87 @@
+package paths
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+)
+
+// runtimeDirCandidates lists where the runtime dir may live, most preferred
+// first: $XDG_RUNTIME_DIR/mcpx (or StateDir when unset), then a user-scoped
+// mcpx-<uid> under $TMPDIR and under os.TempDir().
+func runtimeDirCandidates() []string {
+	preferred := StateDir()
+	if v := os.Getenv("XDG_RUNTIME_DIR"); v != "" {
+		preferred = filepath.Join(v, "mcpx")
+	}
+	candidates := []string{preferred}
+	userDir := fmt.Sprintf("mcpx-%d", os.Getuid())
+	for _, base := range []string{os.Getenv("TMPDIR"), os.TempDir()} {
+		if base == "" {
+			continue
+		}
+		dir := filepath.Join(base, userDir)
+		if dir != candidates[len(candidates)-1] {
+			candidates = append(candidates, dir)
+		}
+	}
+	return candidates
+}
+
+// runtimeDirCache holds the resolved runtime dir so the CLI's many path
+// lookups pay for the filesystem checks once per process. It is keyed by the
+// candidate list, so a changed environment (as in tests) resolves again.
+var runtimeDirCache struct {
+	mu       sync.Mutex
+	key      string
+	dir      string
+	fallback bool
+}
+
+// ResolveRuntimeDir picks the first runtime dir candidate that can be
+// created (see EnsureDir) and then checks out as a directory this user can
+// write; shared temp dirs must also be owned by this user and closed to
+// everyone else (mode 0700). fallback reports whether that is not the
+// preferred dir. When no candidate is usable the preferred dir is returned,
+// uncached, so EnsureDir reports why it cannot be used.
+func ResolveRuntimeDir() (dir string, fallback bool) {
+	candidates := runtimeDirCandidates()
+	key := strings.Join(candidates, "\x00")
+
+	runtimeDirCache.mu.Lock()
+	defer runtimeDirCache.mu.Unlock()
+	if runtimeDirCache.key == key {
+		return runtimeDirCache.dir, runtimeDirCache.fallback
+	}
+	for i, candidate := range candidates {
+		if prepareRuntimeDir(candidate, i > 0) {
+			runtimeDirCache.key, runtimeDirCache.dir, runtimeDirCache.fallback = key, candidate, i > 0
+			return candidate, i > 0
+		}
+	}
+	return candidates[0], false
+}
+
+// prepareRuntimeDir creates dir if needed and reports whether it is usable.
+// The checks run after creation, so they cover a dir this call just made as
+// well as one that was already there. A shared dir is checked without
+// following symlinks, since another user could have planted one.
+func prepareRuntimeDir(dir string, shared bool) bool {
+	if err := EnsureDir(dir); err != nil {
+		return false
+	}
+	stat := os.Stat
+	if shared {
+		stat = os.Lstat
+	}
+	info, err := stat(dir)
+	if err != nil || !info.IsDir() {
+		return false
+	}
+	if shared && !privateToCurrentUser(info) {
+		return false
+	}
+	return dirWritable(dir)
+}
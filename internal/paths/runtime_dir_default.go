@@ -0,0 +1,13 @@
+//go:build !linux && !darwin
+
+package paths
+
+import "os"
+
+func dirWritable(string) bool {
+	return true
+}
+
+func privateToCurrentUser(os.FileInfo) bool {
+	return true
+}
@@ -43,13 +43,13 @@ func StateDir() string {
 	return xdgDir("XDG_STATE_HOME", filepath.Join(".local", "state"))
 }
 
-// RuntimeDir returns the mcpx runtime directory for sockets and state.
-// Falls back to $XDG_STATE_HOME/mcpx if XDG_RUNTIME_DIR is unset.
+// RuntimeDir returns the mcpx runtime directory for sockets and state,
+// creating it on first use. Falls back to $XDG_STATE_HOME/mcpx if
+// XDG_RUNTIME_DIR is unset, and to a user-scoped temp dir when that is not
+// writable (see ResolveRuntimeDir).
 func RuntimeDir() string {
-	if v := os.Getenv("XDG_RUNTIME_DIR"); v != "" {
-		return filepath.Join(v, "mcpx")
-	}
-	return StateDir()
+	dir, _ := ResolveRuntimeDir()
+	return dir
 }
 
 // ManDir returns the man page target directory ($XDG_DATA_HOME/man/man1).
@@ -73,6 +73,11 @@ func StatePath() string {
 	return filepath.Join(RuntimeDir(), "daemon.state")
 }
 
+// UsagePath returns the path to the daemon's saved tool call history.
+func UsagePath() string {
+	return filepath.Join(StateDir(), "usage.json")
+}
+
 // LockPath returns the path to the daemon file lock.
 func LockPath() string {
 	return filepath.Join(RuntimeDir(), "daemon.lock")
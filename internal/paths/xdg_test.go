@@ -1,19 +1,21 @@
 package paths
 
 import (
+	"fmt"
 	"os"
 	"path/filepath"
 	"testing"
 )
 
 func TestRuntimeDirUsesXDGStateHomeFallback(t *testing.T) {
+	tmp := t.TempDir()
 	t.Setenv("XDG_RUNTIME_DIR", "")
-	t.Setenv("XDG_STATE_HOME", "/tmp/state-home")
-	t.Setenv("XDG_CACHE_HOME", "/tmp/cache-home")
-	t.Setenv("HOME", "/tmp/home")
+	t.Setenv("XDG_STATE_HOME", filepath.Join(tmp, "state-home"))
+	t.Setenv("XDG_CACHE_HOME", filepath.Join(tmp, "cache-home"))
+	t.Setenv("HOME", filepath.Join(tmp, "home"))
 
 	got := RuntimeDir()
-	want := filepath.Join("/tmp/state-home", "mcpx")
+	want := filepath.Join(tmp, "state-home", "mcpx")
 	if got != want {
 		t.Fatalf("RuntimeDir() = %q, want %q", got, want)
 	}
@@ -21,22 +23,24 @@ func TestRuntimeDirUsesXDGStateHomeFallback(t *testing.T) {
 
 func TestRuntimeDirFallsBackToHomeLocalState(t *testing.T) {
 	t.Setenv("XDG_RUNTIME_DIR", "")
+	tmp := t.TempDir()
 	t.Setenv("XDG_STATE_HOME", "")
-	t.Setenv("HOME", "/tmp/home")
+	t.Setenv("HOME", filepath.Join(tmp, "home"))
 
 	got := RuntimeDir()
-	want := filepath.Join("/tmp/home", ".local", "state", "mcpx")
+	want := filepath.Join(tmp, "home", ".local", "state", "mcpx")
 	if got != want {
 		t.Fatalf("RuntimeDir() = %q, want %q", got, want)
 	}
 }
 
 func TestRuntimeDirPrefersXDGRuntimeDir(t *testing.T) {
-	t.Setenv("XDG_RUNTIME_DIR", "/tmp/xdg-runtime")
-	t.Setenv("XDG_STATE_HOME", "/tmp/state-home")
+	tmp := t.TempDir()
+	t.Setenv("XDG_RUNTIME_DIR", filepath.Join(tmp, "xdg-runtime"))
+	t.Setenv("XDG_STATE_HOME", filepath.Join(tmp, "state-home"))
 
 	got := RuntimeDir()
-	want := filepath.Join("/tmp/xdg-runtime", "mcpx")
+	want := filepath.Join(tmp, "xdg-runtime", "mcpx")
 	if got != want {
 		t.Fatalf("RuntimeDir() = %q, want %q", got, want)
 	}
@@ -87,19 +91,20 @@ func TestConfigDirFallsBackToHomeDotConfig(t *testing.T) {
 }
 
 func TestConfigAndRuntimeDerivedPaths(t *testing.T) {
+	runtimeHome := t.TempDir()
 	t.Setenv("XDG_CONFIG_HOME", "/tmp/config-home")
-	t.Setenv("XDG_RUNTIME_DIR", "/tmp/runtime-home")
+	t.Setenv("XDG_RUNTIME_DIR", runtimeHome)
 
 	if got, want := ConfigFile(), filepath.Join("/tmp/config-home", "mcpx", "config.toml"); got != want {
 		t.Fatalf("ConfigFile() = %q, want %q", got, want)
 	}
-	if got, want := SocketPath(), filepath.Join("/tmp/runtime-home", "mcpx", "daemon.sock"); got != want {
+	if got, want := SocketPath(), filepath.Join(runtimeHome, "mcpx", "daemon.sock"); got != want {
 		t.Fatalf("SocketPath() = %q, want %q", got, want)
 	}
-	if got, want := StatePath(), filepath.Join("/tmp/runtime-home", "mcpx", "daemon.state"); got != want {
+	if got, want := StatePath(), filepath.Join(runtimeHome, "mcpx", "daemon.state"); got != want {
 		t.Fatalf("StatePath() = %q, want %q", got, want)
 	}
-	if got, want := LockPath(), filepath.Join("/tmp/runtime-home", "mcpx", "daemon.lock"); got != want {
+	if got, want := LockPath(), filepath.Join(runtimeHome, "mcpx", "daemon.lock"); got != want {
 		t.Fatalf("LockPath() = %q, want %q", got, want)
 	}
 }
@@ -130,3 +135,120 @@ func TestEnsureDirReturnsErrorWhenParentIsFile(t *testing.T) {
 		t.Fatal("EnsureDir() error = nil, want non-nil when parent is a file")
 	}
 }
+
+func TestResolveRuntimeDirFallsBackWhenPrimaryIsUnwritable(t *testing.T) {
+	tmp := t.TempDir()
+	t.Setenv("XDG_RUNTIME_DIR", "/dev/null")
+	t.Setenv("TMPDIR", tmp)
+
+	dir, fallback := ResolveRuntimeDir()
+	want := filepath.Join(tmp, fmt.Sprintf("mcpx-%d", os.Getuid()))
+	if dir != want || !fallback {
+		t.Fatalf("ResolveRuntimeDir() = %q, %v; want %q, true", dir, fallback, want)
+	}
+	if got := SocketPath(); got != filepath.Join(want, "daemon.sock") {
+		t.Fatalf("SocketPath() = %q, want it under the fallback dir", got)
+	}
+	if err := EnsureDir(dir); err != nil {
+		t.Fatalf("EnsureDir(fallback) error = %v", err)
+	}
+}
+
+func TestResolveRuntimeDirKeepsWritablePrimary(t *testing.T) {
+	runtimeRoot := t.TempDir()
+	t.Setenv("XDG_RUNTIME_DIR", runtimeRoot)
+	t.Setenv("TMPDIR", t.TempDir())
+
+	dir, fallback := ResolveRuntimeDir()
+	if dir != filepath.Join(runtimeRoot, "mcpx") || fallback {
+		t.Fatalf("ResolveRuntimeDir() = %q, %v; want primary, false", dir, fallback)
+	}
+}
+
+func TestResolveRuntimeDirReturnsPrimaryWhenNothingIsUsable(t *testing.T) {
+	t.Setenv("XDG_RUNTIME_DIR", "/dev/null")
+	t.Setenv("TMPDIR", "/dev/null")
+
+	dir, fallback := ResolveRuntimeDir()
+	if dir != filepath.Join("/dev/null", "mcpx") || fallback {
+		t.Fatalf("ResolveRuntimeDir() = %q, %v; want primary so EnsureDir reports the error", dir, fallback)
+	}
+	if err := EnsureDir(dir); err == nil {
+		t.Fatal("EnsureDir(/dev/null/mcpx) error = nil, want non-nil")
+	}
+}
+
+func TestResolveRuntimeDirCreatesPrivateFallbackDir(t *testing.T) {
+	tmp := t.TempDir()
+	t.Setenv("XDG_RUNTIME_DIR", "/dev/null")
+	t.Setenv("TMPDIR", tmp)
+
+	dir, fallback := ResolveRuntimeDir()
+	if !fallback {
+		t.Fatalf("ResolveRuntimeDir() = %q, false; want the temp-dir fallback", dir)
+	}
+	info, err := os.Lstat(dir)
+	if err != nil {
+		t.Fatalf("Lstat(%q): %v", dir, err)
+	}
+	if !info.IsDir() || info.Mode().Perm() != 0o700 {
+		t.Fatalf("fallback dir mode = %v, want a 0700 directory", info.Mode())
+	}
+}
+
+func TestResolveRuntimeDirSkipsSharedFallbackOpenToOthers(t *testing.T) {
+	tmp := t.TempDir()
+	t.Setenv("XDG_RUNTIME_DIR", "/dev/null")
+	t.Setenv("TMPDIR", tmp)
+	shared := filepath.Join(tmp, fmt.Sprintf("mcpx-%d", os.Getuid()))
+	if err := os.Mkdir(shared, 0o700); err != nil {
+		t.Fatalf("Mkdir(%q): %v", shared, err)
+	}
+	if err := os.Chmod(shared, 0o755); err != nil {
+		t.Fatalf("Chmod(%q): %v", shared, err)
+	}
+
+	dir, _ := ResolveRuntimeDir()
+	if dir == shared {
+		t.Fatalf("ResolveRuntimeDir() = %q, want a group/world-accessible shared dir refused", dir)
+	}
+}
+
+func TestResolveRuntimeDirSkipsSymlinkedSharedFallback(t *testing.T) {
+	tmp := t.TempDir()
+	t.Setenv("XDG_RUNTIME_DIR", "/dev/null")
+	t.Setenv("TMPDIR", tmp)
+	shared := filepath.Join(tmp, fmt.Sprintf("mcpx-%d", os.Getuid()))
+	target := filepath.Join(tmp, "elsewhere")
+	if err := os.Mkdir(target, 0o700); err != nil {
+		t.Fatalf("Mkdir(%q): %v", target, err)
+	}
+	if err := os.Symlink(target, shared); err != nil {
+		t.Fatalf("Symlink(%q): %v", shared, err)
+	}
+
+	dir, _ := ResolveRuntimeDir()
+	if dir == shared {
+		t.Fatalf("ResolveRuntimeDir() = %q, want a symlinked shared dir refused", dir)
+	}
+}
+
+func TestResolveRuntimeDirReusesResolvedDir(t *testing.T) {
+	runtimeRoot := t.TempDir()
+	t.Setenv("XDG_RUNTIME_DIR", runtimeRoot)
+	t.Setenv("TMPDIR", t.TempDir())
+
+	first, _ := ResolveRuntimeDir()
+	// Removing the dir would make a fresh resolution recreate it; a cached
+	// result must not touch the filesystem again.
+	if err := os.Remove(first); err != nil {
+		t.Fatalf("Remove(%q): %v", first, err)
+	}
+	second, fallback := ResolveRuntimeDir()
+	if second != first || fallback {
+		t.Fatalf("second ResolveRuntimeDir() = %q, %v; want cached %q, false", second, fallback, first)
+	}
+	if _, err := os.Stat(first); !os.IsNotExist(err) {
+		t.Fatalf("Stat(%q) error = %v, want the cached lookup to leave the filesystem alone", first, err)
+	}
+}
@@ -0,0 +1,137 @@
+package response
+
+import (
+	"encoding/json"
+
+	"github.com/lydakis/mcpx/internal/ipc"
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+// Hint describes unwrapped output as a MIME type and an ipc.Encoding* value.
+// Text that is a single valid JSON document is reported as application/json.
+// Output made only of image or resource blocks is temp file paths and
+// reports the blocks' shared MIME type, if any. Both values are empty for
+// results with no output.
+type Hint struct {
+	ContentType string
+	Encoding    string
+}
+
+var (
+	jsonHint = Hint{ContentType: "application/json", Encoding: ipc.EncodingText}
+	textHint = Hint{ContentType: "text/plain", Encoding: ipc.EncodingText}
+)
+
+// contentBlock is the subset of an MCP content block needed to describe it.
+type contentBlock struct {
+	Type     string `json:"type"`
+	Text     string `json:"text"`
+	MIMEType string `json:"mimeType"`
+	Resource struct {
+		MIMEType string `json:"mimeType"`
+		Blob     string `json:"blob"`
+	} `json:"resource"`
+}
+
+func decodeContentBlock(content mcp.Content) (contentBlock, bool) {
+	var block contentBlock
+	raw, err := json.Marshal(content)
+	if err != nil || json.Unmarshal(raw, &block) != nil {
+		return contentBlock{}, false
+	}
+	return block, true
+}
+
+func (b contentBlock) mimeType() string {
+	if b.Type == "resource" {
+		return b.Resource.MIMEType
+	}
+	return b.MIMEType
+}
+
+// hintBuilder accumulates the Hint of output UnwrapHinted joins from
+// rendered content blocks.
+type hintBuilder struct {
+	parts             int
+	allText, allFiles bool
+	onlyJSON          bool
+	files             int
+	fileMIME          string
+	text              string
+}
+
+func newHintBuilder() *hintBuilder {
+	return &hintBuilder{allText: true, allFiles: true, onlyJSON: true}
+}
+
+// addRendered records a block renderContent turned into rendered: text, or
+// the temp file path of an image or resource.
+func (b *hintBuilder) addRendered(content mcp.Content, rendered string) {
+	b.parts++
+	b.onlyJSON = false
+	block, ok := decodeContentBlock(content)
+	switch {
+	case ok && block.Type == "text":
+		b.allFiles = false
+		b.text = rendered
+	case ok && (block.Type == "image" || block.Type == "resource"):
+		b.allText = false
+		if b.files == 0 {
+			b.fileMIME = block.mimeType()
+		} else if block.mimeType() != b.fileMIME {
+			b.fileMIME = ""
+		}
+		b.files++
+	default:
+		b.allText, b.allFiles = false, false
+	}
+}
+
+// addJSON records a block that could not be rendered and is output as its
+// JSON encoding.
+func (b *hintBuilder) addJSON() {
+	b.parts++
+	b.allText, b.allFiles = false, false
+}
+
+func (b *hintBuilder) hint() Hint {
+	switch {
+	case b.parts == 0:
+		return Hint{}
+	case b.onlyJSON && b.parts == 1:
+		return jsonHint
+	case b.allText:
+		if b.parts == 1 && json.Valid([]byte(b.text)) {
+			return jsonHint
+		}
+		return textHint
+	case b.allFiles:
+		return Hint{ContentType: b.fileMIME, Encoding: ipc.EncodingPath}
+	default:
+		return textHint
+	}
+}
+
+// rawHint describes the bytes UnwrapRaw returns for a block of typ: text and
+// text resources stay text, image, audio, and blob blocks are binary with
+// the block's MIME type.
+func rawHint(typ, mimeType, text string, binary bool) Hint {
+	switch {
+	case typ == "text":
+		if json.Valid([]byte(text)) {
+			return jsonHint
+		}
+		return textHint
+	case binary:
+		return Hint{ContentType: orDefault(mimeType, "application/octet-stream"), Encoding: ipc.EncodingBinary}
+	default:
+		return Hint{ContentType: orDefault(mimeType, "text/plain"), Encoding: ipc.EncodingText}
+	}
+}
+
+func orDefault(value, fallback string) string {
+	if value == "" {
+		return fallback
+	}
+	return value
+}
@@ -0,0 +1,122 @@
+package response
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/lydakis/mcpx/internal/ipc"
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+func TestHintMapsBlockTypes(t *testing.T) {
+	tests := []struct {
+		name        string
+		result      *mcp.CallToolResult
+		contentType string
+		encoding    string
+		rawType     string
+		rawEncoding string
+	}{
+		{
+			name:        "structured",
+			result:      &mcp.CallToolResult{StructuredContent: map[string]any{"n": 1}},
+			contentType: "application/json", encoding: ipc.EncodingText,
+		},
+		{
+			name:        "plain text",
+			result:      &mcp.CallToolResult{Content: []mcp.Content{mcp.TextContent{Type: "text", Text: "hello"}}},
+			contentType: "text/plain", encoding: ipc.EncodingText,
+			rawType: "text/plain", rawEncoding: ipc.EncodingText,
+		},
+		{
+			name:        "json text",
+			result:      &mcp.CallToolResult{Content: []mcp.Content{mcp.TextContent{Type: "text", Text: `{"ok":true}`}}},
+			contentType: "application/json", encoding: ipc.EncodingText,
+			rawType: "application/json", rawEncoding: ipc.EncodingText,
+		},
+		{
+			name: "several text blocks",
+			result: &mcp.CallToolResult{Content: []mcp.Content{
+				mcp.TextContent{Type: "text", Text: "{}"},
+				mcp.TextContent{Type: "text", Text: "{}"},
+			}},
+			contentType: "text/plain", encoding: ipc.EncodingText,
+		},
+		{
+			name:        "image",
+			result:      &mcp.CallToolResult{Content: []mcp.Content{mcp.ImageContent{Type: "image", Data: "AA==", MIMEType: "image/png"}}},
+			contentType: "image/png", encoding: ipc.EncodingPath,
+			rawType: "image/png", rawEncoding: ipc.EncodingBinary,
+		},
+		{
+			name: "blob resource",
+			result: &mcp.CallToolResult{Content: []mcp.Content{mcp.EmbeddedResource{Type: "resource", Resource: mcp.BlobResourceContents{
+				URI: "file:///a.pdf", MIMEType: "application/pdf", Blob: "AA==",
+			}}}},
+			contentType: "application/pdf", encoding: ipc.EncodingPath,
+			rawType: "application/pdf", rawEncoding: ipc.EncodingBinary,
+		},
+		{
+			name: "text resource",
+			result: &mcp.CallToolResult{Content: []mcp.Content{mcp.EmbeddedResource{Type: "resource", Resource: mcp.TextResourceContents{
+				URI: "file:///a.md", MIMEType: "text/markdown", Text: "# a",
+			}}}},
+			contentType: "text/markdown", encoding: ipc.EncodingPath,
+			rawType: "text/markdown", rawEncoding: ipc.EncodingText,
+		},
+		{
+			name:        "audio",
+			result:      &mcp.CallToolResult{Content: []mcp.Content{&mcp.AudioContent{Type: "audio", Data: "AA==", MIMEType: "audio/wav"}}},
+			contentType: "application/json", encoding: ipc.EncodingText,
+			rawType: "audio/wav", rawEncoding: ipc.EncodingBinary,
+		},
+		{
+			name: "mixed",
+			result: &mcp.CallToolResult{Content: []mcp.Content{
+				mcp.TextContent{Type: "text", Text: "caption"},
+				mcp.ImageContent{Type: "image", Data: "AA==", MIMEType: "image/png"},
+			}},
+			contentType: "text/plain", encoding: ipc.EncodingText,
+		},
+		{
+			name:   "empty",
+			result: &mcp.CallToolResult{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, _, hint := UnwrapHinted(tt.result, "")
+			if hint.ContentType != tt.contentType || hint.Encoding != tt.encoding {
+				t.Fatalf("UnwrapHinted() hint = %q, %q; want %q, %q", hint.ContentType, hint.Encoding, tt.contentType, tt.encoding)
+			}
+			_, _, rawHint, _ := UnwrapRaw(tt.result)
+			if rawHint.ContentType != tt.rawType || rawHint.Encoding != tt.rawEncoding {
+				t.Fatalf("UnwrapRaw() hint = %q, %q; want %q, %q", rawHint.ContentType, rawHint.Encoding, tt.rawType, tt.rawEncoding)
+			}
+		})
+	}
+}
+
+func TestUnwrapRawHintDescribesErrorsLikeUnwrap(t *testing.T) {
+	result := &mcp.CallToolResult{IsError: true, Content: []mcp.Content{mcp.TextContent{Type: "text", Text: "boom"}}}
+	_, _, hint, _ := UnwrapRaw(result)
+	if hint != textHint {
+		t.Fatalf("UnwrapRaw(error) hint = %+v, want text/plain, text", hint)
+	}
+}
+
+func TestUnwrapHintedDescribesUnrenderableBlocksAsJSON(t *testing.T) {
+	result := &mcp.CallToolResult{Content: []mcp.Content{mcp.ImageContent{Type: "image", Data: "not base64", MIMEType: "image/png"}}}
+	out, _, hint := UnwrapHinted(result, "")
+	if hint != jsonHint || !json.Valid(out) {
+		t.Fatalf("UnwrapHinted(bad image) = %q, %+v; want JSON-encoded block described as JSON", out, hint)
+	}
+}
+
+func TestUnwrapHintedCombineJSONIsJSON(t *testing.T) {
+	_, _, hint := UnwrapHinted(mcp.NewToolResultText("plain"), CombineJSON)
+	if hint != jsonHint {
+		t.Fatalf("UnwrapHinted(CombineJSON) hint = %+v, want application/json", hint)
+	}
+}
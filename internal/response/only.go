@@ -0,0 +1,53 @@
+package response
+
+import (
+	"fmt"
+
+	"github.com/lydakis/mcpx/internal/ipc"
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+// Kinds of content UnwrapOnly keeps.
+const (
+	// OnlyStructured keeps the result's structured content.
+	OnlyStructured = "structured"
+	// OnlyText keeps the result's text blocks.
+	OnlyText = "text"
+)
+
+// UnwrapOnly is UnwrapCombined restricted to one kind of content: the
+// structured content, or only the text blocks, joined per mode. It fails
+// when the result has none of that kind. Error results fall back to
+// UnwrapHinted so their message still reaches stderr.
+func UnwrapOnly(result *mcp.CallToolResult, kind, mode string) ([]byte, int, Hint, error) {
+	if result == nil {
+		return nil, ipc.ExitInternal, Hint{}, fmt.Errorf("empty tool result")
+	}
+	if result.IsError {
+		out, exitCode, hint := UnwrapHinted(result, "")
+		return out, exitCode, hint, nil
+	}
+
+	switch kind {
+	case OnlyStructured:
+		if result.StructuredContent == nil {
+			return nil, ipc.ExitToolErr, Hint{}, fmt.Errorf("result has no structured content")
+		}
+		out, exitCode, hint := UnwrapHinted(&mcp.CallToolResult{StructuredContent: result.StructuredContent}, mode)
+		return out, exitCode, hint, nil
+	case OnlyText:
+		var texts []mcp.Content
+		for _, content := range result.Content {
+			if block, ok := decodeContentBlock(content); ok && block.Type == "text" {
+				texts = append(texts, content)
+			}
+		}
+		if len(texts) == 0 {
+			return nil, ipc.ExitToolErr, Hint{}, fmt.Errorf("result has no text content")
+		}
+		out, exitCode, hint := UnwrapHinted(&mcp.CallToolResult{Content: texts}, mode)
+		return out, exitCode, hint, nil
+	default:
+		return nil, ipc.ExitInternal, Hint{}, fmt.Errorf("unknown content kind %q", kind)
+	}
+}
@@ -0,0 +1,63 @@
+package response
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/lydakis/mcpx/internal/ipc"
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+func mixedResult() *mcp.CallToolResult {
+	return &mcp.CallToolResult{
+		Content: []mcp.Content{
+			mcp.NewTextContent("Found 2 repositories"),
+			mcp.NewImageContent("aGk=", "image/png"),
+			mcp.NewTextContent("page 1 of 1"),
+		},
+		StructuredContent: map[string]any{"total": 2},
+	}
+}
+
+func TestUnwrapOnlyStructuredDropsTextBlocks(t *testing.T) {
+	out, code, _, err := UnwrapOnly(mixedResult(), OnlyStructured, "")
+	if err != nil || code != ipc.ExitOK {
+		t.Fatalf("UnwrapOnly(structured) = (%q, %d, %v), want success", out, code, err)
+	}
+	if string(out) != "{\"total\":2}\n" {
+		t.Fatalf("output = %q, want structured content only", out)
+	}
+
+	_, code, _, err = UnwrapOnly(mcp.NewToolResultText("plain"), OnlyStructured, "")
+	if err == nil || code != ipc.ExitToolErr || !strings.Contains(err.Error(), "no structured content") {
+		t.Fatalf("UnwrapOnly(structured, text result) = (%d, %v), want missing structured error", code, err)
+	}
+}
+
+func TestUnwrapOnlyTextDropsStructuredAndNonTextBlocks(t *testing.T) {
+	out, code, _, err := UnwrapOnly(mixedResult(), OnlyText, "")
+	if err != nil || code != ipc.ExitOK {
+		t.Fatalf("UnwrapOnly(text) = (%q, %d, %v), want success", out, code, err)
+	}
+	if string(out) != "Found 2 repositories\npage 1 of 1\n" {
+		t.Fatalf("output = %q, want text blocks only", out)
+	}
+
+	out, _, _, err = UnwrapOnly(mixedResult(), OnlyText, CombineJSON)
+	if err != nil || string(out) != "[\"Found 2 repositories\",\"page 1 of 1\"]\n" {
+		t.Fatalf("UnwrapOnly(text, json) = (%q, %v), want JSON array of text blocks", out, err)
+	}
+
+	structured := &mcp.CallToolResult{StructuredContent: map[string]any{"ok": true}}
+	_, code, _, err = UnwrapOnly(structured, OnlyText, "")
+	if err == nil || code != ipc.ExitToolErr || !strings.Contains(err.Error(), "no text content") {
+		t.Fatalf("UnwrapOnly(text, structured result) = (%d, %v), want missing text error", code, err)
+	}
+}
+
+func TestUnwrapOnlyKeepsErrorMessages(t *testing.T) {
+	out, code, _, err := UnwrapOnly(mcp.NewToolResultError("boom"), OnlyStructured, "")
+	if err != nil || code != ipc.ExitToolErr || string(out) != "boom\n" {
+		t.Fatalf("UnwrapOnly(error result) = (%q, %d, %v), want boom with exit 1", out, code, err)
+	}
+}
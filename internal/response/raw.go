@@ -0,0 +1,68 @@
+package response
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+
+	"github.com/lydakis/mcpx/internal/ipc"
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+// UnwrapRaw returns the bytes of a result's single content block as is:
+// decoded image, audio, or blob data, or text without an added newline,
+// along with a Hint describing them. Error results fall back to
+// UnwrapHinted so their message still reaches stderr.
+func UnwrapRaw(result *mcp.CallToolResult) ([]byte, int, Hint, error) {
+	if result == nil {
+		return nil, ipc.ExitInternal, Hint{}, fmt.Errorf("empty tool result")
+	}
+	if result.IsError {
+		out, exitCode, hint := UnwrapHinted(result, "")
+		return out, exitCode, hint, nil
+	}
+	if len(result.Content) != 1 {
+		return nil, ipc.ExitToolErr, Hint{}, fmt.Errorf("raw output needs exactly one content block, got %d", len(result.Content))
+	}
+
+	var block struct {
+		Type     string `json:"type"`
+		Text     string `json:"text"`
+		Data     string `json:"data"`
+		MIMEType string `json:"mimeType"`
+		Resource struct {
+			Text     string `json:"text"`
+			Blob     string `json:"blob"`
+			MIMEType string `json:"mimeType"`
+		} `json:"resource"`
+	}
+	raw, err := json.Marshal(result.Content[0])
+	if err != nil {
+		return nil, ipc.ExitInternal, Hint{}, fmt.Errorf("encoding content block: %w", err)
+	}
+	if err := json.Unmarshal(raw, &block); err != nil {
+		return nil, ipc.ExitInternal, Hint{}, fmt.Errorf("decoding content block: %w", err)
+	}
+
+	encoded := ""
+	mimeType := block.MIMEType
+	switch block.Type {
+	case "text":
+		return []byte(block.Text), ipc.ExitOK, rawHint(block.Type, "", block.Text, false), nil
+	case "image", "audio":
+		encoded = block.Data
+	case "resource":
+		mimeType = block.Resource.MIMEType
+		if block.Resource.Blob == "" {
+			return []byte(block.Resource.Text), ipc.ExitOK, rawHint(block.Type, mimeType, "", false), nil
+		}
+		encoded = block.Resource.Blob
+	default:
+		return nil, ipc.ExitToolErr, Hint{}, fmt.Errorf("raw output does not support %q content blocks", block.Type)
+	}
+	data, err := base64.StdEncoding.DecodeString(encoded)
+	if err != nil {
+		return nil, ipc.ExitToolErr, Hint{}, fmt.Errorf("decoding %s data: %w", block.Type, err)
+	}
+	return data, ipc.ExitOK, rawHint(block.Type, mimeType, "", true), nil
+}
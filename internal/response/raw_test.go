@@ -0,0 +1,57 @@
+package response
+
+import (
+	"bytes"
+	"encoding/base64"
+	"testing"
+
+	"github.com/lydakis/mcpx/internal/ipc"
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+func TestUnwrapRawReturnsDecodedBinaryBlock(t *testing.T) {
+	png := []byte{0x89, 'P', 'N', 'G', '\r', '\n', 0x1a, '\n', 0x00, 0xff}
+	encoded := base64.StdEncoding.EncodeToString(png)
+
+	for name, content := range map[string]mcp.Content{
+		"image": mcp.ImageContent{Type: "image", Data: encoded, MIMEType: "image/png"},
+		"audio": &mcp.AudioContent{Type: "audio", Data: encoded, MIMEType: "audio/wav"},
+		"blob": mcp.EmbeddedResource{Type: "resource", Resource: mcp.BlobResourceContents{
+			URI: "file:///shot.png", MIMEType: "image/png", Blob: encoded,
+		}},
+	} {
+		out, code, _, err := UnwrapRaw(&mcp.CallToolResult{Content: []mcp.Content{content}})
+		if err != nil || code != ipc.ExitOK {
+			t.Fatalf("UnwrapRaw(%s) = code %d, err %v", name, code, err)
+		}
+		if !bytes.Equal(out, png) {
+			t.Fatalf("UnwrapRaw(%s) = %v, want %v", name, out, png)
+		}
+	}
+}
+
+func TestUnwrapRawKeepsTextExact(t *testing.T) {
+	out, code, _, err := UnwrapRaw(&mcp.CallToolResult{Content: []mcp.Content{
+		mcp.TextContent{Type: "text", Text: "no newline"},
+	}})
+	if err != nil || code != ipc.ExitOK || string(out) != "no newline" {
+		t.Fatalf("UnwrapRaw(text) = %q, %d, %v; want exact text", out, code, err)
+	}
+}
+
+func TestUnwrapRawRequiresSingleBlock(t *testing.T) {
+	_, code, _, err := UnwrapRaw(&mcp.CallToolResult{Content: []mcp.Content{
+		mcp.TextContent{Type: "text", Text: "a"},
+		mcp.TextContent{Type: "text", Text: "b"},
+	}})
+	if err == nil || code != ipc.ExitToolErr {
+		t.Fatalf("UnwrapRaw(two blocks) = %d, %v; want tool error", code, err)
+	}
+
+	out, code, _, err := UnwrapRaw(&mcp.CallToolResult{IsError: true, Content: []mcp.Content{
+		mcp.TextContent{Type: "text", Text: "boom"},
+	}})
+	if err != nil || code != ipc.ExitToolErr || string(out) != "boom\n" {
+		t.Fatalf("UnwrapRaw(error result) = %q, %d, %v; want rendered error", out, code, err)
+	}
+}
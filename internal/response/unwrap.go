@@ -31,11 +31,33 @@ var (
 	lastTempArtifactCleanup time.Time
 )
 
+// Ways UnwrapCombined joins a result with several content blocks.
+const (
+	// CombineText concatenates the rendered blocks with no separator.
+	CombineText = "text"
+	// CombineJSON prints a JSON array with one string per rendered block.
+	CombineJSON = "json"
+)
+
 // Unwrap extracts raw output from an MCP CallToolResult.
 // Returns the output bytes and an exit code.
 func Unwrap(result *mcp.CallToolResult) ([]byte, int) {
+	return UnwrapCombined(result, "")
+}
+
+// UnwrapCombined is Unwrap with a choice of how content blocks are joined:
+// CombineText or CombineJSON, or newlines (Unwrap's behavior) for any
+// other mode. Structured content is returned as is in every mode.
+func UnwrapCombined(result *mcp.CallToolResult, mode string) ([]byte, int) {
+	out, exitCode, _ := UnwrapHinted(result, mode)
+	return out, exitCode
+}
+
+// UnwrapHinted is UnwrapCombined that also describes the output it
+// produced; see Hint.
+func UnwrapHinted(result *mcp.CallToolResult, mode string) ([]byte, int, Hint) {
 	if result == nil {
-		return nil, ipc.ExitInternal
+		return nil, ipc.ExitInternal, Hint{}
 	}
 
 	exitCode := ipc.ExitOK
@@ -45,29 +67,49 @@ func Unwrap(result *mcp.CallToolResult) ([]byte, int) {
 
 	if result.StructuredContent != nil {
 		if data, err := json.Marshal(result.StructuredContent); err == nil {
-			return ensureTrailingNewline(data), exitCode
+			return ensureTrailingNewline(data), exitCode, jsonHint
 		}
 	}
 
 	var parts []string
+	hints := newHintBuilder()
 	for _, content := range result.Content {
 		if rendered, ok := renderContent(content); ok {
 			parts = append(parts, rendered)
+			hints.addRendered(content, rendered)
 			continue
 		}
 
 		raw, err := json.Marshal(content)
 		if err == nil {
 			parts = append(parts, string(raw))
+			hints.addJSON()
+		}
+	}
+
+	switch mode {
+	case CombineJSON:
+		if parts == nil {
+			parts = []string{}
+		}
+		data, err := json.Marshal(parts)
+		if err != nil {
+			return nil, ipc.ExitInternal, Hint{}
+		}
+		return ensureTrailingNewline(data), exitCode, jsonHint
+	case CombineText:
+		if len(parts) == 0 {
+			return nil, exitCode, Hint{}
 		}
+		return ensureTrailingNewline([]byte(strings.Join(parts, ""))), exitCode, hints.hint()
 	}
 
 	if len(parts) == 0 {
-		return nil, exitCode
+		return nil, exitCode, Hint{}
 	}
 
 	out := strings.Join(parts, "\n")
-	return ensureTrailingNewline([]byte(out)), exitCode
+	return ensureTrailingNewline([]byte(out)), exitCode, hints.hint()
 }
 
 func renderContent(content mcp.Content) (string, bool) {
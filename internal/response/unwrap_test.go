@@ -44,6 +44,37 @@ func TestUnwrapMultipleTextBlocksAreNewlineSeparated(t *testing.T) {
 	}
 }
 
+func TestUnwrapCombinedJoinsMultipleTextBlocksPerMode(t *testing.T) {
+	result := &mcp.CallToolResult{
+		Content: []mcp.Content{
+			mcp.TextContent{Type: "text", Text: "alpha"},
+			mcp.TextContent{Type: "text", Text: "beta \"quoted\""},
+		},
+	}
+
+	tests := []struct {
+		mode string
+		want string
+	}{
+		{mode: "", want: "alpha\nbeta \"quoted\"\n"},
+		{mode: CombineText, want: "alphabeta \"quoted\"\n"},
+		{mode: CombineJSON, want: `["alpha","beta \"quoted\""]` + "\n"},
+	}
+	for _, tt := range tests {
+		out, exitCode := UnwrapCombined(result, tt.mode)
+		if exitCode != ipc.ExitOK {
+			t.Fatalf("UnwrapCombined(%q) exit = %d, want %d", tt.mode, exitCode, ipc.ExitOK)
+		}
+		if string(out) != tt.want {
+			t.Fatalf("UnwrapCombined(%q) = %q, want %q", tt.mode, out, tt.want)
+		}
+	}
+
+	if out, _ := UnwrapCombined(&mcp.CallToolResult{}, CombineJSON); string(out) != "[]\n" {
+		t.Fatalf("UnwrapCombined(empty, json) = %q, want %q", out, "[]\n")
+	}
+}
+
 func TestUnwrapImageContentWritesTempFileAndPrintsPath(t *testing.T) {
 	payload := []byte("image-bytes")
 	result := &mcp.CallToolResult{
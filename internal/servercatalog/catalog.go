@@ -150,14 +150,9 @@ func (c *Catalog) ResolveForTool(ctx context.Context, requested, tool string) (R
 }
 
 func (c *Catalog) FilterTools(route Route, tools []mcppool.ToolInfo) []mcppool.ToolInfo {
-	if !route.IsVirtual() {
-		out := make([]mcppool.ToolInfo, len(tools))
-		copy(out, tools)
-		return out
-	}
 	filtered := make([]mcppool.ToolInfo, 0, len(tools))
 	for _, tool := range tools {
-		if toolMatchesPrefix(tool.Name, route.VirtualPrefix) {
+		if c.ToolBelongsToRoute(route, tool.Name) {
 			filtered = append(filtered, tool)
 		}
 	}
@@ -170,7 +165,7 @@ func (c *Catalog) ToolInfo(route Route, tools []mcppool.ToolInfo, requested stri
 		if name != requested {
 			continue
 		}
-		if route.IsVirtual() && !toolMatchesPrefix(name, route.VirtualPrefix) {
+		if !c.ToolBelongsToRoute(route, name) {
 			return nil, false
 		}
 		toolCopy := tools[i]
@@ -179,11 +174,26 @@ func (c *Catalog) ToolInfo(route Route, tools []mcppool.ToolInfo, requested stri
 	return nil, false
 }
 
+// ToolBelongsToRoute reports whether tool is exposed through route: it must
+// match the virtual server prefix (if any) and pass the backing server's
+// allow_tools/deny_tools lists.
 func (c *Catalog) ToolBelongsToRoute(route Route, tool string) bool {
-	if !route.IsVirtual() {
+	if route.IsVirtual() && !toolMatchesPrefix(tool, route.VirtualPrefix) {
+		return false
+	}
+	return c.ToolAllowed(route, tool)
+}
+
+// ToolAllowed applies the route's server allow_tools/deny_tools lists.
+func (c *Catalog) ToolAllowed(route Route, tool string) bool {
+	if c == nil || c.cfg == nil {
+		return true
+	}
+	scfg, ok := c.cfg.Servers[route.ConfigServer]
+	if !ok {
 		return true
 	}
-	return toolMatchesPrefix(tool, route.VirtualPrefix)
+	return scfg.ToolAllowed(tool)
 }
 
 func (c *Catalog) hasCodexApps() bool {
@@ -213,6 +213,35 @@ func TestFilterToolsVirtualRouteKeepsOnlyPrefixMatches(t *testing.T) {
 	}
 }
 
+func TestFilterToolsAppliesAllowAndDenyLists(t *testing.T) {
+	catalog := New(&config.Config{
+		Servers: map[string]config.ServerConfig{
+			"github": {
+				AllowTools: []string{"get_*", "delete_*"},
+				DenyTools:  []string{"delete_*"},
+			},
+		},
+	}, nil)
+	route := Route{Backend: "github", ConfigServer: "github"}
+	tools := []mcppool.ToolInfo{
+		{Name: "get_repo"},
+		{Name: "delete_repo"},
+		{Name: "list_issues"},
+	}
+
+	got := catalog.FilterTools(route, tools)
+	want := []mcppool.ToolInfo{{Name: "get_repo"}}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("FilterTools(allow/deny) = %#v, want %#v", got, want)
+	}
+	if catalog.ToolBelongsToRoute(route, "delete_repo") {
+		t.Fatal("ToolBelongsToRoute(delete_repo) = true, want false for denied tool")
+	}
+	if _, ok := catalog.ToolInfo(route, tools, "list_issues"); ok {
+		t.Fatal("ToolInfo(list_issues) ok = true, want false for tool outside allow list")
+	}
+}
+
 func TestFilterToolsNonVirtualReturnsCopyOfAllTools(t *testing.T) {
 	catalog := New(&config.Config{}, nil)
 	route := Route{Backend: "github", ConfigServer: "github"}
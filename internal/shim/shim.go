@@ -15,24 +15,36 @@ import (
 
 const markerPrefix = "# mcpx-shim:server="
 
+// targetMarkerPrefix records what a recipe or tool shim runs; server shims,
+// which forward to `mcpx <name>`, have no target line.
+const targetMarkerPrefix = "# mcpx-shim:target="
+
 var (
 	ErrInvalidServerName = errors.New("invalid shim server name")
 	ErrPathOccupied      = errors.New("shim path already exists")
 	ErrCommandCollision  = errors.New("command already exists in PATH")
 	ErrNotInstalled      = errors.New("shim is not installed")
 	ErrNotManagedShim    = errors.New("shim path is not managed by mcpx")
+	ErrInvalidTarget     = errors.New("invalid shim target")
 )
 
 var serverNameRe = regexp.MustCompile(`^[A-Za-z0-9][A-Za-z0-9._-]*$`)
 
 type InstallOptions struct {
 	Dir string
+	// Recipe makes the shim run `mcpx run <recipe>`. Server and Tool make it
+	// call one tool, `mcpx <server> <tool>`. With neither, the shim forwards
+	// to `mcpx <name>`.
+	Recipe string
+	Server string
+	Tool   string
 }
 
 type InstallResult struct {
 	Dir              string
 	Path             string
 	Server           string
+	Target           string
 	AlreadyInstalled bool
 	DirInPath        bool
 }
@@ -55,6 +67,9 @@ type Entry struct {
 	Dir    string
 	Path   string
 	Server string
+	// Target is what the shim runs after `mcpx`: the server for server
+	// shims, "run <recipe>", or "<server> <tool>".
+	Target string
 }
 
 // DefaultDir returns the default install location for shims.
@@ -71,6 +86,11 @@ func Install(server string, opts InstallOptions) (*InstallResult, error) {
 		return nil, fmt.Errorf("%w: %q", ErrInvalidServerName, server)
 	}
 
+	args, target, err := shimTarget(server, opts)
+	if err != nil {
+		return nil, err
+	}
+
 	dir, err := normalizeDir(opts.Dir)
 	if err != nil {
 		return nil, err
@@ -89,10 +109,18 @@ func Install(server string, opts InstallOptions) (*InstallResult, error) {
 			return nil, fmt.Errorf("reading existing shim: %w", readErr)
 		}
 		if managed && managedServer == server {
+			existing, err := readShimTarget(path, server)
+			if err != nil {
+				return nil, fmt.Errorf("reading existing shim: %w", err)
+			}
+			if existing != target {
+				return nil, fmt.Errorf("%w: %s runs mcpx %s; remove it first", ErrPathOccupied, path, existing)
+			}
 			return &InstallResult{
 				Dir:              dir,
 				Path:             path,
 				Server:           server,
+				Target:           target,
 				AlreadyInstalled: true,
 				DirInPath:        dirInPath(dir),
 			}, nil
@@ -106,7 +134,7 @@ func Install(server string, opts InstallOptions) (*InstallResult, error) {
 		return nil, fmt.Errorf("creating shim directory: %w", err)
 	}
 
-	script := renderShimScript(server)
+	script := renderShimScript(server, args, target)
 	tmp, err := os.CreateTemp(dir, ".mcpx-shim-*")
 	if err != nil {
 		return nil, fmt.Errorf("creating shim temp file: %w", err)
@@ -139,6 +167,7 @@ func Install(server string, opts InstallOptions) (*InstallResult, error) {
 		Dir:              dir,
 		Path:             path,
 		Server:           server,
+		Target:           target,
 		AlreadyInstalled: false,
 		DirInPath:        dirInPath(dir),
 	}, nil
@@ -207,7 +236,11 @@ func List(opts ListOptions) ([]Entry, error) {
 		if !managed {
 			continue
 		}
-		entries = append(entries, Entry{Dir: dir, Path: path, Server: server})
+		target, err := readShimTarget(path, server)
+		if err != nil {
+			return nil, fmt.Errorf("reading shim entry: %w", err)
+		}
+		entries = append(entries, Entry{Dir: dir, Path: path, Server: server, Target: target})
 	}
 
 	sort.Slice(entries, func(i, j int) bool {
@@ -258,8 +291,61 @@ func readManagedServer(path string, info os.FileInfo) (string, bool, error) {
 	return "", false, nil
 }
 
-func renderShimScript(server string) string {
-	return fmt.Sprintf("#!/bin/sh\n%s%s\nexec mcpx '%s' \"$@\"\n", markerPrefix, server, shellSingleQuote(server))
+// shimTarget returns the mcpx arguments a shim named name runs, and the
+// target recorded for shim list.
+func shimTarget(name string, opts InstallOptions) ([]string, string, error) {
+	recipe := strings.TrimSpace(opts.Recipe)
+	server := strings.TrimSpace(opts.Server)
+	tool := strings.TrimSpace(opts.Tool)
+	switch {
+	case recipe != "" && (server != "" || tool != ""):
+		return nil, "", fmt.Errorf("%w: a shim runs either a recipe or a tool", ErrInvalidTarget)
+	case recipe != "":
+		return []string{"run", recipe}, "run " + recipe, nil
+	case server != "" && tool != "":
+		// "--" keeps tools named like mcpx subcommands or flags in tool mode.
+		return []string{server, "--", tool}, server + " " + tool, nil
+	case server != "" || tool != "":
+		return nil, "", fmt.Errorf("%w: a tool shim needs both a server and a tool", ErrInvalidTarget)
+	default:
+		return []string{name}, name, nil
+	}
+}
+
+// readShimTarget returns the target line of a managed shim, or name for
+// server shims, which have none.
+func readShimTarget(path, name string) (string, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return "", err
+	}
+	defer file.Close()
+
+	scanner := bufio.NewScanner(file)
+	for i := 0; i < 4 && scanner.Scan(); i++ {
+		if target, ok := strings.CutPrefix(scanner.Text(), targetMarkerPrefix); ok {
+			return strings.TrimSpace(target), nil
+		}
+	}
+	if err := scanner.Err(); err != nil && !errors.Is(err, bufio.ErrTooLong) {
+		return "", err
+	}
+	return name, nil
+}
+
+func renderShimScript(name string, args []string, target string) string {
+	var b strings.Builder
+	b.WriteString("#!/bin/sh\n")
+	b.WriteString(markerPrefix + name + "\n")
+	if target != name {
+		b.WriteString(targetMarkerPrefix + target + "\n")
+	}
+	b.WriteString("exec mcpx")
+	for _, arg := range args {
+		b.WriteString(" '" + shellSingleQuote(arg) + "'")
+	}
+	b.WriteString(" \"$@\"\n")
+	return b.String()
 }
 
 func shellSingleQuote(value string) string {
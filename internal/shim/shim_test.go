@@ -368,3 +368,55 @@ func TestHomeDirFallsBackToUserHomeWhenHOMEUnset(t *testing.T) {
 		t.Fatalf("homeDir() = %q, want %q", got, want)
 	}
 }
+
+func TestInstallRecipeAndToolShimsRecordTarget(t *testing.T) {
+	tmp := t.TempDir()
+	t.Setenv("PATH", tmp)
+
+	result, err := Install("ghsearch", InstallOptions{Dir: tmp, Recipe: "mcp-repos"})
+	if err != nil {
+		t.Fatalf("Install(recipe) error = %v", err)
+	}
+	if result.Target != "run mcp-repos" {
+		t.Fatalf("Target = %q, want %q", result.Target, "run mcp-repos")
+	}
+	content, err := os.ReadFile(result.Path)
+	if err != nil {
+		t.Fatalf("ReadFile(%q) error = %v", result.Path, err)
+	}
+	want := "#!/bin/sh\n# mcpx-shim:server=ghsearch\n# mcpx-shim:target=run mcp-repos\nexec mcpx 'run' 'mcp-repos' \"$@\"\n"
+	if string(content) != want {
+		t.Fatalf("shim content = %q, want %q", string(content), want)
+	}
+
+	if _, err := Install("ghrepo", InstallOptions{Dir: tmp, Server: "github", Tool: "get_repo"}); err != nil {
+		t.Fatalf("Install(tool) error = %v", err)
+	}
+	if _, err := Install("github", InstallOptions{Dir: tmp}); err != nil {
+		t.Fatalf("Install(server) error = %v", err)
+	}
+
+	again, err := Install("ghsearch", InstallOptions{Dir: tmp, Recipe: "mcp-repos"})
+	if err != nil || !again.AlreadyInstalled {
+		t.Fatalf("Install(same recipe) = %+v, %v; want already installed", again, err)
+	}
+	if _, err := Install("ghsearch", InstallOptions{Dir: tmp, Recipe: "other"}); !errors.Is(err, ErrPathOccupied) {
+		t.Fatalf("Install(different recipe) error = %v, want ErrPathOccupied", err)
+	}
+	if _, err := Install("bad", InstallOptions{Dir: tmp, Server: "github"}); !errors.Is(err, ErrInvalidTarget) {
+		t.Fatalf("Install(server without tool) error = %v, want ErrInvalidTarget", err)
+	}
+
+	entries, err := List(ListOptions{Dir: tmp})
+	if err != nil {
+		t.Fatalf("List() error = %v", err)
+	}
+	got := map[string]string{}
+	for _, entry := range entries {
+		got[entry.Server] = entry.Target
+	}
+	wantTargets := map[string]string{"ghrepo": "github get_repo", "ghsearch": "run mcp-repos", "github": "github"}
+	if !reflect.DeepEqual(got, wantTargets) {
+		t.Fatalf("list targets = %#v, want %#v", got, wantTargets)
+	}
+}